@@ -7,6 +7,15 @@ CREATE TABLE IF NOT EXISTS users (
     last_name VARCHAR(255),
     notion_token VARCHAR(255),
     notion_database_id VARCHAR(255),
+    notion_hub_page_id VARCHAR(255),
+    result_retention_hours INTEGER NOT NULL DEFAULT 0,
+    transcription_options JSONB NOT NULL DEFAULT '{}'::jsonb,
+    summary_language VARCHAR(16),
+    language_code VARCHAR(16),
+    chat_type VARCHAR(16) NOT NULL DEFAULT 'private',
+    is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+    banned_at TIMESTAMP WITH TIME ZONE,
+    ban_reason TEXT,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 );
@@ -37,14 +46,40 @@ CREATE TYPE job_type AS ENUM (
 CREATE TABLE IF NOT EXISTS jobs (
     id SERIAL PRIMARY KEY,
     user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(64) NOT NULL DEFAULT 'transcription',
     audio_file_path VARCHAR(255) NOT NULL,
     file_name VARCHAR(255) NOT NULL,
     duration INTEGER,
     status job_status NOT NULL DEFAULT 'pending',
     transcription TEXT,
+    transcription_gz BYTEA,
     summary TEXT,
+    summary_gz BYTEA,
     notion_page_id VARCHAR(255),
     notion_database_id VARCHAR(255),
+    source_message_id INTEGER,
+    instructions TEXT,
+    notion_unlinked_at TIMESTAMP WITH TIME ZONE,
+    audio_profile VARCHAR(16) NOT NULL DEFAULT 'auto',
+    summary_model VARCHAR(64),
+    summary_cost NUMERIC(12, 6),
+    summary_tokens INTEGER NOT NULL DEFAULT 0,
+    keepalive_message_id INTEGER,
+    keepalive_at TIMESTAMP WITH TIME ZONE,
+    cleanup_retried_at TIMESTAMP WITH TIME ZONE,
+    file_unique_id VARCHAR(255),
+    failed_stage VARCHAR(32),
+    notion_upload_page_id VARCHAR(255),
+    notion_upload_cursor INTEGER NOT NULL DEFAULT 0,
+    progress_message_id INTEGER,
+    summary_style VARCHAR(16),
+    processed_audio_path VARCHAR(255),
+    processed_audio_profile VARCHAR(16),
+    processed_audio_at TIMESTAMP WITH TIME ZONE,
+    transcript_minhash TEXT,
+    duplicate_of_job_id INTEGER REFERENCES jobs(id) ON DELETE SET NULL,
+    duplicate_similarity REAL,
+    speaker_segments JSONB,
     error_message TEXT,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
@@ -57,6 +92,15 @@ CREATE INDEX IF NOT EXISTS idx_jobs_user_id ON jobs(user_id);
 -- Создание индекса для быстрого поиска по статусу
 CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status);
 
+-- Создание индекса для поиска повторно присланных файлов
+CREATE INDEX IF NOT EXISTS idx_jobs_user_file_unique_id ON jobs(user_id, file_unique_id) WHERE file_unique_id IS NOT NULL;
+
+-- Создание индекса для поиска задачи по сообщению с записью, на которое ответил пользователь
+CREATE INDEX IF NOT EXISTS idx_jobs_user_source_message_id ON jobs(user_id, source_message_id) WHERE source_message_id IS NOT NULL;
+
+-- Создание индекса для поиска подготовленных WAV с истекшим сроком хранения
+CREATE INDEX IF NOT EXISTS idx_jobs_processed_audio_at ON jobs(processed_audio_at) WHERE processed_audio_at IS NOT NULL;
+
 -- Функция для обновления updated_at
 CREATE OR REPLACE FUNCTION update_updated_at_column()
 RETURNS TRIGGER AS $$
@@ -76,4 +120,305 @@ EXECUTE FUNCTION update_updated_at_column();
 CREATE TRIGGER update_jobs_updated_at
 BEFORE UPDATE ON jobs
 FOR EACH ROW
-EXECUTE FUNCTION update_updated_at_column();
\ No newline at end of file
+EXECUTE FUNCTION update_updated_at_column();
+
+-- Создание таблицы публичных ссылок на результаты задач
+CREATE TABLE IF NOT EXISTS share_links (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Создание индекса для быстрого поиска ссылок задачи
+CREATE INDEX IF NOT EXISTS idx_share_links_job_id ON share_links(job_id);
+
+-- Создание таблицы этапов обработки задач
+CREATE TABLE IF NOT EXISTS job_stages (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    stage VARCHAR(64) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT,
+    external_calls JSONB NOT NULL DEFAULT '[]'::jsonb,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (job_id, stage)
+);
+
+-- Подключение расширения pgvector
+CREATE EXTENSION IF NOT EXISTS vector;
+
+-- Создание таблицы эмбеддингов заметок для семантического поиска
+CREATE TABLE IF NOT EXISTS note_embeddings (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL,
+    chunk_index INTEGER NOT NULL DEFAULT 0,
+    content TEXT NOT NULL,
+    embedding vector(1536) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Создание индексов для поиска по пользователю и по близости векторов
+CREATE INDEX IF NOT EXISTS idx_note_embeddings_user_id ON note_embeddings(user_id);
+CREATE INDEX IF NOT EXISTS idx_note_embeddings_job_id ON note_embeddings(job_id);
+CREATE INDEX IF NOT EXISTS idx_note_embeddings_embedding ON note_embeddings USING hnsw (embedding vector_cosine_ops);
+
+-- Создание таблицы подписок на RSS-ленты подкастов
+CREATE TABLE IF NOT EXISTS podcast_feeds (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    notion_database_id VARCHAR(255),
+    last_checked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, url)
+);
+
+-- Создание таблицы учтенных выпусков подкастов (дедупликация по пользователю)
+CREATE TABLE IF NOT EXISTS podcast_episodes (
+    id SERIAL PRIMARY KEY,
+    feed_id INTEGER REFERENCES podcast_feeds(id) ON DELETE SET NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    guid TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    audio_url TEXT NOT NULL,
+    published_at TIMESTAMP WITH TIME ZONE,
+    job_id INTEGER REFERENCES jobs(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, guid)
+);
+
+-- Создание таблицы фрагментов транскрипций для поиска внутри задачи (команда /find).
+-- Фрагмент - сегмент Whisper с меткой времени или предложение транскрипции без меток
+CREATE TABLE IF NOT EXISTS transcript_segments (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    start_seconds INTEGER,
+    text TEXT NOT NULL,
+    UNIQUE (job_id, position)
+);
+
+-- Создание таблицы результатов выгрузки задач в места синхронизации (Notion, хранилище Obsidian, вебхук)
+CREATE TABLE IF NOT EXISTS job_sync_results (
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    target VARCHAR(32) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    external_id TEXT,
+    error_message TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (job_id, target)
+);
+
+-- Создание таблицы команд с каналом для ежедневного дайджеста
+CREATE TABLE IF NOT EXISTS teams (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invite_code VARCHAR(32) UNIQUE NOT NULL,
+    channel_id BIGINT,
+    digest_hour SMALLINT NOT NULL DEFAULT 9,
+    last_digest_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Создание таблицы участников команд; пользователь состоит не более чем в одной команде
+CREATE TABLE IF NOT EXISTS team_members (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    joined_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_team_id ON team_members(team_id);
+
+-- Создание таблицы уроков чтения вслух с эталонным текстом
+CREATE TABLE IF NOT EXISTS reading_lessons (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    reference_text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Создание таблицы фотографий, приложенных к задачам, с распознанным текстом
+CREATE TABLE IF NOT EXISTS job_attachments (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    image BYTEA NOT NULL,
+    ocr_text TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_attachments_job_id ON job_attachments(job_id);
+
+-- Создание таблицы вебхуков пользователей с фильтром событий и дополнительными заголовками
+CREATE TABLE IF NOT EXISTS user_webhooks (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    events TEXT[] NOT NULL DEFAULT '{}',
+    headers JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Создание таблицы дневных сводок ошибок по этапу, внешнему сервису и категории
+CREATE TABLE IF NOT EXISTS failure_rollups (
+    day DATE NOT NULL,
+    stage VARCHAR(64) NOT NULL,
+    provider VARCHAR(64) NOT NULL,
+    category VARCHAR(64) NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (day, stage, provider, category)
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_stages_failed_finished_at ON job_stages(finished_at) WHERE status = 'failed';
+
+-- Создание журнала действий администраторов с данными пользователей
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    id SERIAL PRIMARY KEY,
+    admin_telegram_id BIGINT NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    target_telegram_id BIGINT,
+    details TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_created_at ON admin_audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_target ON admin_audit_log(target_telegram_id);
+
+-- Создание таблицы глав видео, присланных ссылкой. Транскрипция делится по главам,
+-- и краткое содержание составляется отдельно для каждой главы
+CREATE TABLE IF NOT EXISTS video_chapters (
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    start_seconds INTEGER NOT NULL,
+    end_seconds INTEGER NOT NULL,
+    PRIMARY KEY (job_id, position)
+);
+
+-- Создание таблицы разборов настроения встреч: общее настроение, тон обсуждения
+-- и напряженные моменты с цитатами из транскрипции
+CREATE TABLE IF NOT EXISTS job_sentiments (
+    job_id INTEGER PRIMARY KEY REFERENCES jobs(id) ON DELETE CASCADE,
+    mood VARCHAR(16) NOT NULL,
+    tone TEXT NOT NULL DEFAULT '',
+    tension_points JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Создание таблицы повесток встреч. Повестка без задачи ожидает следующей записи пользователя,
+-- после чего привязывается к ее задаче, и краткое содержание составляется по пунктам повестки
+CREATE TABLE IF NOT EXISTS meeting_agendas (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job_id INTEGER UNIQUE REFERENCES jobs(id) ON DELETE CASCADE,
+    agenda TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_meeting_agendas_pending ON meeting_agendas(user_id) WHERE job_id IS NULL;
+
+-- Создание таблицы настроек обработки записей, которые пользователь выбирает командой /settings
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    transcription_language VARCHAR(8) NOT NULL DEFAULT '',
+    summary_style VARCHAR(16) NOT NULL DEFAULT 'standard',
+    notion_auto_sync BOOLEAN NOT NULL DEFAULT TRUE,
+    failed_jobs_cleanup BOOLEAN NOT NULL DEFAULT FALSE,
+    failed_jobs_cleanup_at TIMESTAMP WITH TIME ZONE,
+    usage_analytics_opt_out BOOLEAN NOT NULL DEFAULT FALSE,
+    interface_language VARCHAR(16),
+    sentiment_analysis BOOLEAN NOT NULL DEFAULT FALSE,
+    summary_style_remembered BOOLEAN NOT NULL DEFAULT FALSE,
+    notion_transcript BOOLEAN NOT NULL DEFAULT TRUE,
+    notion_summary_only BOOLEAN NOT NULL DEFAULT FALSE,
+    notion_audio_stats BOOLEAN NOT NULL DEFAULT FALSE,
+    notion_usage_cost BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Создание таблицы обезличенных событий использования бота для отчета /admin usage
+CREATE TABLE IF NOT EXISTS usage_events (
+    id SERIAL PRIMARY KEY,
+    user_key VARCHAR(64) NOT NULL,
+    event VARCHAR(64) NOT NULL,
+    detail VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_events_created_at ON usage_events(created_at);
+CREATE INDEX IF NOT EXISTS idx_usage_events_user_key ON usage_events(user_key);
+
+-- Создание таблицы сравнений моделей суммаризации командой /compare: пользователь видит краткие содержания
+-- двух моделей без названий и голосует за лучшее, а итоги голосований помогают выбрать модель по умолчанию
+CREATE TABLE IF NOT EXISTS model_comparisons (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    model_a VARCHAR(255) NOT NULL,
+    model_b VARCHAR(255) NOT NULL,
+    vote VARCHAR(8),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    voted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS call_recordings (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(32) NOT NULL,
+    call_id VARCHAR(255) NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job_id INTEGER REFERENCES jobs(id) ON DELETE SET NULL,
+    direction VARCHAR(16),
+    caller VARCHAR(64),
+    callee VARCHAR(64),
+    started_at TIMESTAMP WITH TIME ZONE,
+    duration INTEGER NOT NULL DEFAULT 0,
+    recording_received_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (provider, call_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_call_recordings_job_id ON call_recordings(job_id);
+
+CREATE TABLE IF NOT EXISTS notion_routes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category VARCHAR(64) NOT NULL,
+    database_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, category)
+);
+
+CREATE TABLE IF NOT EXISTS job_logs (
+    id BIGSERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    level VARCHAR(8) NOT NULL,
+    message TEXT NOT NULL,
+    attrs JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_logs_job_id ON job_logs(job_id, id);
+CREATE INDEX IF NOT EXISTS idx_job_logs_created_at ON job_logs(created_at);
+
+CREATE TABLE IF NOT EXISTS live_sessions (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    message_id INTEGER NOT NULL DEFAULT 0,
+    transcript TEXT NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    segments INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
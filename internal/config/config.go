@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -9,15 +12,40 @@ import (
 
 // Config представляет собой структуру конфигурации приложения
 type Config struct {
-	App      AppConfig
-	Log      LogConfig
-	Postgres PostgresConfig
-	Redis    RedisConfig
-	Telegram TelegramConfig
-	OpenAI   OpenAIConfig
-	DeepSeek DeepSeekConfig
-	Notion   NotionConfig
-	FFmpeg   FFmpegConfig
+	App          AppConfig
+	Log          LogConfig
+	Database     DatabaseConfig
+	Postgres     PostgresConfig
+	Redis        RedisConfig
+	Queue        QueueConfig
+	Telegram     TelegramConfig
+	OpenAI       OpenAIConfig
+	WhisperLocal WhisperLocalConfig
+	Deepgram     DeepgramConfig
+	Diarization  DiarizationConfig
+	DeepSeek     DeepSeekConfig
+	Notion       NotionConfig
+	FFmpeg       FFmpegConfig
+	Temp         TempConfig
+	HTTP         HTTPConfig
+	Share        ShareConfig
+	Text         TextConfig
+	Duplicate    DuplicateConfig
+	Podcast      PodcastConfig
+	Sync         SyncConfig
+	Quota        QuotaConfig
+	Team         TeamConfig
+	Analytics    AnalyticsConfig
+	Progress     ProgressConfig
+	Stage        StageConfig
+	Video        VideoConfig
+	Ingestion    IngestionConfig
+	PDF          PDFConfig
+	VoIP         VoIPConfig
+	Secrets      SecretsConfig
+	// SecretStore выдает текущие значения секретов с учетом ротации; поля APIKey и Token
+	// содержат значения, прочитанные при запуске
+	SecretStore *SecretStore
 }
 
 // AppConfig содержит общие настройки приложения
@@ -25,11 +53,30 @@ type AppConfig struct {
 	Name    string
 	Version string
 	Env     string
+
+	// RestartBackoff - пауза перед перезапуском упавшей подсистемы (бота, HTTP сервера, воркера очереди,
+	// фоновых расписаний); при сбоях подряд она удваивается, но не превышает RestartMaxBackoff
+	RestartBackoff    time.Duration
+	RestartMaxBackoff time.Duration
 }
 
 // LogConfig содержит настройки логирования
 type LogConfig struct {
 	Level string
+	// JobRetention - сколько хранятся записи лога задач для /logs и /admin_logs (0 - индекс логов отключен)
+	JobRetention time.Duration
+}
+
+// Драйверы хранилища данных
+const (
+	DatabaseDriverPostgres = "postgres" // PostgreSQL для данных и Redis для очередей, кэша и блокировок
+	DatabaseDriverSQLite   = "sqlite"   // Файл SQLite, очередь и блокировки в памяти процесса (без PostgreSQL и Redis)
+)
+
+// DatabaseConfig содержит выбор хранилища данных
+type DatabaseConfig struct {
+	Driver     string // postgres или sqlite
+	SQLitePath string // Путь к файлу базы данных SQLite
 }
 
 // PostgresConfig содержит настройки подключения к PostgreSQL
@@ -41,6 +88,9 @@ type PostgresConfig struct {
 	DBName   string
 	SSLMode  string
 	PoolMax  int
+	// CompressMinBytes - размер в байтах, начиная с которого транскрипции и резюме сохраняются сжатыми.
+	// 0 отключает сжатие новых текстов, уже сжатые тексты по-прежнему читаются
+	CompressMinBytes int
 }
 
 // DSN возвращает строку подключения к PostgreSQL
@@ -54,38 +104,300 @@ type RedisConfig struct {
 	Addr     string
 	Password string
 	DB       int
+	// UserCacheTTL - время хранения профилей пользователей в кэше Redis (0 - без кэша)
+	UserCacheTTL time.Duration
 }
 
+// QueueConfig содержит настройки обработки очереди задач
+type QueueConfig struct {
+	MaxPanicAttempts int // После скольких аварийных завершений обработчика задача переносится в очередь poison
+	// FailedCleanupInterval - период разбора задач с ошибками у пользователей, включивших его в /settings (0 - отключен)
+	FailedCleanupInterval time.Duration
+	// InlineMaxDuration - голосовые сообщения не длиннее этого обрабатываются сразу, минуя очередь (0 - отключено)
+	InlineMaxDuration time.Duration
+	// InlineConcurrency - сколько коротких голосовых сообщений может одновременно обрабатываться минуя очередь
+	InlineConcurrency int
+}
+
+// Режимы получения обновлений Telegram
+const (
+	TelegramModePolling = "polling" // Опрос Telegram (long polling), подходит для одного экземпляра
+	TelegramModeWebhook = "webhook" // Вебхук: Telegram сам присылает обновления, экземпляры можно поставить за балансировщик
+)
+
 // TelegramConfig содержит настройки для Telegram бота
 type TelegramConfig struct {
-	Token string
+	Token    string
+	AdminIDs []int64 // Telegram ID администраторов, которым доступны служебные команды
+
+	// AllowedIDs и AllowedChannel включают приватный режим: бот отвечает только пользователям из списка,
+	// подписчикам канала (числовой ID или @username) и администраторам
+	AllowedIDs     []int64
+	AllowedChannel string
+
+	Mode                  string // polling или webhook
+	WebhookURL            string // Публичный HTTPS-адрес вебхука, на который Telegram отправляет обновления
+	WebhookListenAddr     string // Адрес слушателя вебхука
+	WebhookSecret         string // Секрет для проверки, что запрос пришел от Telegram
+	WebhookMaxConnections int    // Ограничение одновременных запросов Telegram к вебхуку (0 - по умолчанию Telegram)
+
+	// APIURL - адрес собственного сервера Bot API (например, http://telegram-bot-api:8081); пустое значение -
+	// api.telegram.org. Собственный сервер позволяет загружать файлы больше 20 МБ
+	APIURL string
+
+	// DownloadConcurrency - сколько файлов загружается из Telegram одновременно; остальные загрузки ждут очереди
+	DownloadConcurrency int
+	// DownloadRateMB - общее ограничение скорости загрузок файлов в МБ/с (0 - без ограничения)
+	DownloadRateMB int64
+}
+
+// PrivateMode сообщает, что бот отвечает только пользователям из списка доступа
+func (c TelegramConfig) PrivateMode() bool {
+	return len(c.AllowedIDs) > 0 || c.AllowedChannel != ""
 }
 
 // OpenAIConfig содержит настройки для OpenAI API
 type OpenAIConfig struct {
-	APIKey      string
-	WhisperModel string
-	Timeout     time.Duration
+	APIKey             string
+	CurrentAPIKey      func() string // Текущее значение ключа с учетом ротации
+	WhisperModel       string
+	WhisperTemperature float64 // Температура декодирования Whisper по умолчанию
+	WhisperPrompt      string  // Подсказка для Whisper по умолчанию
+	WhisperGranularity string  // Детализация ответа Whisper: text или segment
+	// WhisperMaxFileMB - наибольший размер записи, которую Whisper API принимает одним файлом; записи больше
+	// нарезаются на фрагменты, которые распознаются параллельно (0 - не нарезать)
+	WhisperMaxFileMB        int
+	WhisperChunkOverlap     time.Duration // На сколько соседние фрагменты перекрываются, чтобы не терять слова на стыках
+	WhisperChunkConcurrency int           // Сколько фрагментов одной записи распознается одновременно
+	EmbeddingModel          string
+	OCRModel                string // Модель с поддержкой изображений для распознавания текста на фотографиях
+	TTSModel                string // Модель синтеза речи для озвучивания кратких содержаний
+	TTSVoice                string // Голос синтеза речи
+	Timeout                 time.Duration
+}
+
+// API локальных серверов распознавания
+const (
+	WhisperLocalAPIWhisperCpp = "whispercpp" // Сервер whisper.cpp: POST /inference
+	WhisperLocalAPIOpenAI     = "openai"     // OpenAI-совместимый сервер faster-whisper: POST /v1/audio/transcriptions
+)
+
+// WhisperLocalConfig содержит настройки локального сервера распознавания whisper.cpp или faster-whisper
+type WhisperLocalConfig struct {
+	URL         string // Адрес сервера (пусто - записи распознаются через OpenAI Whisper API)
+	API         string // API сервера: whispercpp или openai
+	Model       string // Модель распознавания (tiny, base, small, medium, large-v3)
+	Concurrency int    // Сколько записей сервер распознает одновременно
+}
+
+// Enabled сообщает, что записи распознаются локальным сервером
+func (c WhisperLocalConfig) Enabled() bool {
+	return c.URL != ""
+}
+
+// DeepgramConfig содержит настройки распознавания через Deepgram API
+type DeepgramConfig struct {
+	APIKey        string        // Ключ API (пусто - записи распознаются через OpenAI Whisper API)
+	CurrentAPIKey func() string // Текущее значение ключа с учетом ротации
+	Model         string        // Модель распознавания (nova-2, nova-3, enhanced, base)
+	Diarize       bool          // Разделять ли речь по говорящим
+	SmartFormat   bool          // Расставлять ли пунктуацию и абзацы, форматировать ли числа и даты
+}
+
+// Enabled сообщает, что записи распознаются через Deepgram
+func (c DeepgramConfig) Enabled() bool {
+	return c.APIKey != ""
+}
+
+// DiarizationConfig содержит настройки разделения транскрипций по говорящим
+type DiarizationConfig struct {
+	Enabled bool   // Подписывать фразы транскрипций номером говорящего
+	URL     string // Адрес сервиса разделения на pyannote.audio для OpenAI Whisper и локального Whisper, которые не различают говорящих
 }
 
 // DeepSeekConfig содержит настройки для DeepSeek API
 type DeepSeekConfig struct {
-	APIKey  string
-	Model   string
-	Timeout time.Duration
+	APIKey           string
+	CurrentAPIKey    func() string // Текущее значение ключа с учетом ротации
+	Model            string
+	SystemPrompt     string
+	Timeout          time.Duration
+	ChunkSize        int
+	ChunkConcurrency int
+	ChunkRetries     int
+	// MaxConcurrency - наибольшее число одновременных запросов к DeepSeek со всех задач; ниже него
+	// параллелизм подстраивается под лимиты DeepSeek по заголовкам ответов
+	MaxConcurrency int
+	// ModelLadder - лестница моделей суммаризации "model:context:input_price:output_price:max_job_cost,..."
+	// (цены в USD за миллион токенов, потолок в USD на задачу); пустое значение - только Model
+	ModelLadder string
+	// CompareModels - две модели, краткие содержания которых сравнивает команда /compare;
+	// пустой список отключает команду
+	CompareModels []string
 }
 
 // NotionConfig содержит настройки для Notion API
 type NotionConfig struct {
-	APIKey string
+	APIKey            string
+	CurrentAPIKey     func() string // Текущее значение ключа с учетом ротации
+	ReconcileInterval time.Duration // Период сверки задач со страницами Notion (0 - только по команде)
+	UserLockTTL       time.Duration // Срок жизни блокировки записи в Notion пользователя; продлевается, пока запись идет
+	UserLockWait      time.Duration // Сколько задача ждет завершения записи другой задачи того же пользователя
+	RelatedNotes      int           // Сколько похожих прошлых заметок ссылается со страницы задачи (0 - не ссылаться)
+
+	// OAuthClientID и OAuthClientSecret - данные публичной интеграции Notion для подключения через OAuth.
+	// Без них пользователи подключают Notion, отправляя токен своей интеграции командой /notion <токен>
+	OAuthClientID     string
+	OAuthClientSecret string
+	// CurrentOAuthClientSecret возвращает текущее значение OAuthClientSecret с учетом ротации
+	CurrentOAuthClientSecret func() string
+}
+
+// OAuthEnabled сообщает, настроено ли подключение Notion через OAuth
+func (c NotionConfig) OAuthEnabled() bool {
+	return c.OAuthClientID != "" && c.OAuthClientSecret != ""
 }
 
 // FFmpegConfig содержит настройки для FFmpeg
 type FFmpegConfig struct {
-	BinaryPath string
+	BinaryPath     string
+	MaxConcurrency int // Сколько команд FFmpeg выполняется одновременно (0 - по числу процессоров)
+	// ArtifactRetention - сколько хранится подготовленный к распознаванию WAV задачи для повторной
+	// транскрибации после последнего использования (0 - WAV удаляется сразу после распознавания)
+	ArtifactRetention time.Duration
 }
 
-// NewConfig создает и загружает конфигурацию из файла и переменных окружения
+// TempConfig содержит настройки каталога временных файлов (загрузки Telegram и промежуточные файлы FFmpeg)
+type TempConfig struct {
+	Dir       string
+	MaxSizeMB int64 // Ограничение суммарного объема одновременно обрабатываемых файлов (0 - без ограничения)
+}
+
+// HTTPConfig содержит настройки HTTP сервера
+type HTTPConfig struct {
+	Addr      string
+	PublicURL string
+}
+
+// ShareConfig содержит настройки публичных ссылок на результаты задач
+type ShareConfig struct {
+	LinkTTL time.Duration
+}
+
+// PodcastConfig содержит настройки подписок на подкасты
+type PodcastConfig struct {
+	PollInterval time.Duration
+	MaxEpisodeMB int64 // Максимальный размер аудио выпуска
+}
+
+// SyncConfig содержит настройки дополнительных мест выгрузки результатов задач.
+// Пустое значение отключает соответствующее место; Notion настраивается каждым пользователем командой /notion
+type SyncConfig struct {
+	VaultDir   string // Каталог хранилища Obsidian для Markdown-заметок
+	WebhookURL string // URL, на который POST-запросом отправляются результаты задач
+}
+
+// QuotaConfig содержит ограничения на использование бота
+type QuotaConfig struct {
+	DailyJobs int // Сколько задач пользователь может создать за сутки (0 - без ограничения)
+
+	// Сколько аудио сообщений пользователь может прислать за AudioWindow, по ролям (0 - без ограничения).
+	// Администраторы - TELEGRAM_ADMIN_IDS, премиум-пользователи - PremiumIDs, остальные - бесплатный тариф
+	AudioWindow  time.Duration
+	AudioFree    int
+	AudioPremium int
+	AudioAdmin   int
+	PremiumIDs   []int64 // Telegram ID пользователей премиум-тарифа
+}
+
+// AudioLimit возвращает, сколько аудио сообщений за AudioWindow может прислать пользователь
+// с указанным Telegram ID (0 - без ограничения)
+func (c QuotaConfig) AudioLimit(telegramID int64, adminIDs []int64) int {
+	switch {
+	case slices.Contains(adminIDs, telegramID):
+		return c.AudioAdmin
+	case slices.Contains(c.PremiumIDs, telegramID):
+		return c.AudioPremium
+	default:
+		return c.AudioFree
+	}
+}
+
+// TeamConfig содержит настройки командных дайджестов
+type TeamConfig struct {
+	DigestTimezone string // Часовой пояс, в котором задается час публикации дайджеста
+}
+
+// AnalyticsConfig содержит настройки сводок для операторов
+type AnalyticsConfig struct {
+	FailureRollupInterval time.Duration // Период пересчета дневных сводок ошибок (0 - только по команде /admin failures)
+	UsageEvents           bool          // Записывать ли обезличенные события использования бота для /admin usage
+	UsageSalt             string        // Соль хэша Telegram ID в событиях использования
+}
+
+// ProgressConfig содержит настройки сообщений о ходе обработки задач
+type ProgressConfig struct {
+	KeepaliveAfter    time.Duration // Через сколько после создания задачи сообщать, что она все еще в работе (0 - не сообщать)
+	KeepaliveInterval time.Duration // Период обновления сообщения о ходе задачи
+}
+
+// StageConfig содержит ограничения времени этапов обработки, зависящие от размера входных данных.
+// Нулевые база и множитель этапа отключают его ограничение
+type StageConfig struct {
+	TranscriptionTimeoutBase       time.Duration // Постоянная часть ограничения времени распознавания
+	TranscriptionTimeoutFactor     float64       // Сколько длительностей записи добавляется к ограничению распознавания
+	SummarizationTimeoutBase       time.Duration // Постоянная часть ограничения времени суммаризации
+	SummarizationTimeoutPer1KChars time.Duration // Сколько добавляется к ограничению суммаризации на тысячу символов транскрипции
+}
+
+// VideoConfig содержит настройки обработки видео, присланных ссылкой
+type VideoConfig struct {
+	YTDLPPath   string        // Путь к yt-dlp; пустое значение отключает обработку ссылок на видео и подкасты
+	MaxDuration time.Duration // Максимальная длительность видео (0 - без ограничения)
+}
+
+// IngestionConfig содержит настройки загрузки записей по прямым ссылкам на аудиофайлы, присланным текстом
+type IngestionConfig struct {
+	MaxSizeMB int64 // Максимальный размер загружаемого файла; 0 отключает обработку ссылок на аудио
+}
+
+// PDFConfig содержит настройки выгрузки результатов задач в PDF
+type PDFConfig struct {
+	RendererPath string // Путь к wkhtmltopdf; пустое значение отключает команду /pdf
+}
+
+// VoIPConfig содержит настройки приема записей звонков из вебхуков VoIP провайдеров
+type VoIPConfig struct {
+	// PhoneNumbers - Telegram ID пользователей по номерам телефонов (только цифры номера); ключ "*" -
+	// пользователь для звонков с остальных номеров. Пустой список отключает прием записей звонков
+	PhoneNumbers map[string]int64
+
+	TwilioAuthToken  string // Auth Token аккаунта Twilio для проверки подписи вебхуков; пустое значение отключает Twilio
+	ZadarmaAPIKey    string // Ключ и секрет API Zadarma; пустые значения отключают Zadarma
+	ZadarmaAPISecret string
+}
+
+// Enabled сообщает, настроен ли прием записей звонков
+func (c VoIPConfig) Enabled() bool {
+	return len(c.PhoneNumbers) > 0 && (c.TwilioAuthToken != "" || c.ZadarmaAPISecret != "")
+}
+
+// TextConfig содержит настройки обработки присланного текста
+type TextConfig struct {
+	MinLength int
+}
+
+// DuplicateConfig содержит настройки поиска повторно присланных записей: одна и та же запись, присланная
+// голосовым сообщением и файлом, дает почти одинаковые транскрипции
+type DuplicateConfig struct {
+	// Threshold - доля совпадения транскрипций (от 0 до 1), начиная с которой задача считается повтором; 0 отключает поиск
+	Threshold float64
+	// Window - за какой срок среди задач пользователя ищется совпадающая; 0 - среди всех задач
+	Window time.Duration
+}
+
+// NewConfig создает и загружает конфигурацию из файла и переменных окружения.
+// Ошибки конфигурации собираются в один отчет *ValidationError, чтобы исправить их за один запуск
 func NewConfig() (*Config, error) {
 	// Установка значений по умолчанию
 	setDefaults()
@@ -105,6 +417,10 @@ func NewConfig() (*Config, error) {
 	// Чтение переменных окружения
 	viper.AutomaticEnv()
 
+	// Проверка формата значений до их чтения: viper молча заменяет нераспознанные значения нулями
+	validator := &configValidator{}
+	validator.checkFormats()
+
 	// Создание и заполнение структуры конфигурации
 	var cfg Config
 
@@ -112,10 +428,19 @@ func NewConfig() (*Config, error) {
 		Name:    viper.GetString("APP_NAME"),
 		Version: viper.GetString("APP_VERSION"),
 		Env:     viper.GetString("APP_ENV"),
+
+		RestartBackoff:    viper.GetDuration("APP_RESTART_BACKOFF"),
+		RestartMaxBackoff: viper.GetDuration("APP_RESTART_MAX_BACKOFF"),
 	}
 
 	cfg.Log = LogConfig{
-		Level: viper.GetString("LOG_LEVEL"),
+		Level:        viper.GetString("LOG_LEVEL"),
+		JobRetention: viper.GetDuration("LOG_JOB_RETENTION"),
+	}
+
+	cfg.Database = DatabaseConfig{
+		Driver:     viper.GetString("DB_DRIVER"),
+		SQLitePath: viper.GetString("SQLITE_PATH"),
 	}
 
 	cfg.Postgres = PostgresConfig{
@@ -126,36 +451,248 @@ func NewConfig() (*Config, error) {
 		DBName:   viper.GetString("POSTGRES_DB"),
 		SSLMode:  viper.GetString("POSTGRES_SSLMODE"),
 		PoolMax:  viper.GetInt("POSTGRES_POOL_MAX"),
+
+		CompressMinBytes: viper.GetInt("POSTGRES_COMPRESS_MIN_BYTES"),
 	}
 
 	cfg.Redis = RedisConfig{
 		Addr:     viper.GetString("REDIS_ADDR"),
 		Password: viper.GetString("REDIS_PASSWORD"),
 		DB:       viper.GetInt("REDIS_DB"),
+
+		UserCacheTTL: viper.GetDuration("REDIS_USER_CACHE_TTL"),
+	}
+
+	cfg.Queue = QueueConfig{
+		MaxPanicAttempts:      viper.GetInt("QUEUE_MAX_PANIC_ATTEMPTS"),
+		FailedCleanupInterval: viper.GetDuration("QUEUE_FAILED_CLEANUP_INTERVAL"),
+		InlineMaxDuration:     viper.GetDuration("QUEUE_INLINE_MAX_DURATION"),
+		InlineConcurrency:     viper.GetInt("QUEUE_INLINE_CONCURRENCY"),
+	}
+
+	// Ключи API читаются из выбранного источника секретов; без него - из переменных окружения
+	cfg.Secrets = SecretsConfig{
+		Source:          viper.GetString("SECRETS_SOURCE"),
+		RefreshInterval: viper.GetDuration("SECRETS_REFRESH_INTERVAL"),
+		Dir:             viper.GetString("SECRETS_DIR"),
+		VaultAddr:       viper.GetString("SECRETS_VAULT_ADDR"),
+		VaultToken:      viper.GetString("SECRETS_VAULT_TOKEN"),
+		VaultPath:       viper.GetString("SECRETS_VAULT_PATH"),
+		AWSRegion:       viper.GetString("SECRETS_AWS_REGION"),
+		AWSSecretID:     viper.GetString("SECRETS_AWS_SECRET_ID"),
+		AWSAccessKeyID:  viper.GetString("AWS_ACCESS_KEY_ID"),
+		AWSSecretKey:    viper.GetString("AWS_SECRET_ACCESS_KEY"),
+		AWSSessionToken: viper.GetString("AWS_SESSION_TOKEN"),
 	}
 
+	fallback := make(map[string]string, len(secretNames))
+	for _, name := range secretNames {
+		fallback[name] = viper.GetString(name)
+	}
+	secretStore, err := newSecretStore(cfg.Secrets, fallback)
+	if err != nil {
+		// Без источника секретов обязательные ключи проверить нельзя: сообщаем о том, что уже найдено
+		validator.addf("%v", err)
+		return nil, validator.err()
+	}
+	cfg.SecretStore = secretStore
+
 	cfg.Telegram = TelegramConfig{
-		Token: viper.GetString("TELEGRAM_TOKEN"),
+		Token:                 secretStore.Get(SecretTelegramToken),
+		Mode:                  viper.GetString("TELEGRAM_MODE"),
+		WebhookURL:            viper.GetString("TELEGRAM_WEBHOOK_URL"),
+		WebhookListenAddr:     viper.GetString("TELEGRAM_WEBHOOK_LISTEN_ADDR"),
+		WebhookSecret:         viper.GetString("TELEGRAM_WEBHOOK_SECRET"),
+		WebhookMaxConnections: viper.GetInt("TELEGRAM_WEBHOOK_MAX_CONNECTIONS"),
+
+		AllowedChannel: strings.TrimSpace(viper.GetString("TELEGRAM_ALLOWED_CHANNEL")),
+
+		APIURL: strings.TrimRight(viper.GetString("TELEGRAM_API_URL"), "/"),
+
+		DownloadConcurrency: viper.GetInt("TELEGRAM_DOWNLOAD_CONCURRENCY"),
+		DownloadRateMB:      viper.GetInt64("TELEGRAM_DOWNLOAD_RATE_MB"),
+	}
+
+	adminIDs, err := parseIDList(viper.GetString("TELEGRAM_ADMIN_IDS"))
+	if err != nil {
+		validator.addf("invalid TELEGRAM_ADMIN_IDS: %v", err)
+	}
+	cfg.Telegram.AdminIDs = adminIDs
+
+	allowedIDs, err := parseIDList(viper.GetString("TELEGRAM_ALLOWED_IDS"))
+	if err != nil {
+		validator.addf("invalid TELEGRAM_ALLOWED_IDS: %v", err)
 	}
+	cfg.Telegram.AllowedIDs = allowedIDs
 
 	cfg.OpenAI = OpenAIConfig{
-		APIKey:      viper.GetString("OPENAI_API_KEY"),
-		WhisperModel: viper.GetString("OPENAI_WHISPER_MODEL"),
-		Timeout:     viper.GetDuration("OPENAI_TIMEOUT"),
+		APIKey:                  secretStore.Get(SecretOpenAIAPIKey),
+		CurrentAPIKey:           secretStore.Getter(SecretOpenAIAPIKey),
+		WhisperModel:            viper.GetString("OPENAI_WHISPER_MODEL"),
+		WhisperTemperature:      viper.GetFloat64("OPENAI_WHISPER_TEMPERATURE"),
+		WhisperPrompt:           viper.GetString("OPENAI_WHISPER_PROMPT"),
+		WhisperGranularity:      viper.GetString("OPENAI_WHISPER_GRANULARITY"),
+		WhisperMaxFileMB:        viper.GetInt("OPENAI_WHISPER_MAX_FILE_MB"),
+		WhisperChunkOverlap:     viper.GetDuration("OPENAI_WHISPER_CHUNK_OVERLAP"),
+		WhisperChunkConcurrency: viper.GetInt("OPENAI_WHISPER_CHUNK_CONCURRENCY"),
+		EmbeddingModel:          viper.GetString("OPENAI_EMBEDDING_MODEL"),
+		OCRModel:                viper.GetString("OPENAI_OCR_MODEL"),
+		TTSModel:                viper.GetString("OPENAI_TTS_MODEL"),
+		TTSVoice:                viper.GetString("OPENAI_TTS_VOICE"),
+		Timeout:                 viper.GetDuration("OPENAI_TIMEOUT"),
+	}
+
+	cfg.WhisperLocal = WhisperLocalConfig{
+		URL:         viper.GetString("WHISPER_LOCAL_URL"),
+		API:         viper.GetString("WHISPER_LOCAL_API"),
+		Model:       viper.GetString("WHISPER_LOCAL_MODEL"),
+		Concurrency: viper.GetInt("WHISPER_LOCAL_CONCURRENCY"),
+	}
+
+	cfg.Deepgram = DeepgramConfig{
+		APIKey:        secretStore.Get(SecretDeepgramAPIKey),
+		CurrentAPIKey: secretStore.Getter(SecretDeepgramAPIKey),
+		Model:         viper.GetString("DEEPGRAM_MODEL"),
+		Diarize:       viper.GetBool("DEEPGRAM_DIARIZE"),
+		SmartFormat:   viper.GetBool("DEEPGRAM_SMART_FORMAT"),
+	}
+
+	cfg.Diarization = DiarizationConfig{
+		Enabled: viper.GetBool("DIARIZATION_ENABLED"),
+		URL:     viper.GetString("DIARIZATION_URL"),
 	}
 
 	cfg.DeepSeek = DeepSeekConfig{
-		APIKey:  viper.GetString("DEEPSEEK_API_KEY"),
-		Model:   viper.GetString("DEEPSEEK_MODEL"),
-		Timeout: viper.GetDuration("DEEPSEEK_TIMEOUT"),
+		APIKey:           secretStore.Get(SecretDeepSeekAPIKey),
+		CurrentAPIKey:    secretStore.Getter(SecretDeepSeekAPIKey),
+		Model:            viper.GetString("DEEPSEEK_MODEL"),
+		SystemPrompt:     viper.GetString("DEEPSEEK_SYSTEM_PROMPT"),
+		Timeout:          viper.GetDuration("DEEPSEEK_TIMEOUT"),
+		ChunkSize:        viper.GetInt("DEEPSEEK_CHUNK_SIZE"),
+		ChunkConcurrency: viper.GetInt("DEEPSEEK_CHUNK_CONCURRENCY"),
+		ChunkRetries:     viper.GetInt("DEEPSEEK_CHUNK_RETRIES"),
+		MaxConcurrency:   viper.GetInt("DEEPSEEK_MAX_CONCURRENCY"),
+		ModelLadder:      viper.GetString("DEEPSEEK_MODEL_LADDER"),
+		CompareModels:    parseNameList(viper.GetString("DEEPSEEK_COMPARE_MODELS")),
 	}
 
 	cfg.Notion = NotionConfig{
-		APIKey: viper.GetString("NOTION_API_KEY"),
+		APIKey:            secretStore.Get(SecretNotionAPIKey),
+		CurrentAPIKey:     secretStore.Getter(SecretNotionAPIKey),
+		ReconcileInterval: viper.GetDuration("NOTION_RECONCILE_INTERVAL"),
+		UserLockTTL:       viper.GetDuration("NOTION_USER_LOCK_TTL"),
+		UserLockWait:      viper.GetDuration("NOTION_USER_LOCK_WAIT"),
+		RelatedNotes:      viper.GetInt("NOTION_RELATED_NOTES"),
+
+		OAuthClientID:            viper.GetString("NOTION_OAUTH_CLIENT_ID"),
+		OAuthClientSecret:        secretStore.Get(SecretNotionOAuth),
+		CurrentOAuthClientSecret: secretStore.Getter(SecretNotionOAuth),
 	}
 
 	cfg.FFmpeg = FFmpegConfig{
-		BinaryPath: viper.GetString("FFMPEG_BINARY_PATH"),
+		BinaryPath:        viper.GetString("FFMPEG_BINARY_PATH"),
+		MaxConcurrency:    viper.GetInt("FFMPEG_MAX_CONCURRENCY"),
+		ArtifactRetention: viper.GetDuration("FFMPEG_ARTIFACT_RETENTION"),
+	}
+
+	cfg.Temp = TempConfig{
+		Dir:       viper.GetString("TMP_DIR"),
+		MaxSizeMB: viper.GetInt64("TMP_MAX_SIZE_MB"),
+	}
+
+	cfg.HTTP = HTTPConfig{
+		Addr:      viper.GetString("HTTP_ADDR"),
+		PublicURL: viper.GetString("HTTP_PUBLIC_URL"),
+	}
+
+	cfg.Share = ShareConfig{
+		LinkTTL: viper.GetDuration("SHARE_LINK_TTL"),
+	}
+
+	cfg.Text = TextConfig{
+		MinLength: viper.GetInt("TEXT_MIN_LENGTH"),
+	}
+
+	cfg.Duplicate = DuplicateConfig{
+		Threshold: viper.GetFloat64("DUPLICATE_SIMILARITY_THRESHOLD"),
+		Window:    viper.GetDuration("DUPLICATE_WINDOW"),
+	}
+
+	cfg.Podcast = PodcastConfig{
+		PollInterval: viper.GetDuration("PODCAST_POLL_INTERVAL"),
+		MaxEpisodeMB: viper.GetInt64("PODCAST_MAX_EPISODE_MB"),
+	}
+
+	cfg.Quota = QuotaConfig{
+		DailyJobs: viper.GetInt("QUOTA_DAILY_JOBS"),
+
+		AudioWindow:  viper.GetDuration("QUOTA_AUDIO_WINDOW"),
+		AudioFree:    viper.GetInt("QUOTA_AUDIO_FREE"),
+		AudioPremium: viper.GetInt("QUOTA_AUDIO_PREMIUM"),
+		AudioAdmin:   viper.GetInt("QUOTA_AUDIO_ADMIN"),
+	}
+
+	premiumIDs, err := parseIDList(viper.GetString("QUOTA_PREMIUM_IDS"))
+	if err != nil {
+		validator.addf("invalid QUOTA_PREMIUM_IDS: %v", err)
+	}
+	cfg.Quota.PremiumIDs = premiumIDs
+
+	cfg.Sync = SyncConfig{
+		VaultDir:   viper.GetString("SYNC_VAULT_DIR"),
+		WebhookURL: viper.GetString("SYNC_WEBHOOK_URL"),
+	}
+
+	cfg.Team = TeamConfig{
+		DigestTimezone: viper.GetString("TEAM_DIGEST_TIMEZONE"),
+	}
+
+	cfg.Analytics = AnalyticsConfig{
+		FailureRollupInterval: viper.GetDuration("ANALYTICS_FAILURE_ROLLUP_INTERVAL"),
+		UsageEvents:           viper.GetBool("ANALYTICS_USAGE_EVENTS"),
+		UsageSalt:             viper.GetString("ANALYTICS_USAGE_SALT"),
+	}
+
+	cfg.Progress = ProgressConfig{
+		KeepaliveAfter:    viper.GetDuration("PROGRESS_KEEPALIVE_AFTER"),
+		KeepaliveInterval: viper.GetDuration("PROGRESS_KEEPALIVE_INTERVAL"),
+	}
+
+	cfg.Stage = StageConfig{
+		TranscriptionTimeoutBase:       viper.GetDuration("STAGE_TRANSCRIPTION_TIMEOUT_BASE"),
+		TranscriptionTimeoutFactor:     viper.GetFloat64("STAGE_TRANSCRIPTION_TIMEOUT_FACTOR"),
+		SummarizationTimeoutBase:       viper.GetDuration("STAGE_SUMMARIZATION_TIMEOUT_BASE"),
+		SummarizationTimeoutPer1KChars: viper.GetDuration("STAGE_SUMMARIZATION_TIMEOUT_PER_1K_CHARS"),
+	}
+
+	cfg.Video = VideoConfig{
+		YTDLPPath:   viper.GetString("VIDEO_YTDLP_PATH"),
+		MaxDuration: viper.GetDuration("VIDEO_MAX_DURATION"),
+	}
+
+	cfg.Ingestion = IngestionConfig{
+		MaxSizeMB: viper.GetInt64("URL_INGESTION_MAX_SIZE_MB"),
+	}
+
+	cfg.PDF = PDFConfig{
+		RendererPath: viper.GetString("PDF_RENDERER_PATH"),
+	}
+
+	phoneNumbers, err := parsePhoneNumbers(viper.GetString("VOIP_PHONE_NUMBERS"))
+	if err != nil {
+		validator.addf("invalid VOIP_PHONE_NUMBERS: %v", err)
+	}
+	cfg.VoIP = VoIPConfig{
+		PhoneNumbers:     phoneNumbers,
+		TwilioAuthToken:  viper.GetString("VOIP_TWILIO_AUTH_TOKEN"),
+		ZadarmaAPIKey:    viper.GetString("VOIP_ZADARMA_API_KEY"),
+		ZadarmaAPISecret: viper.GetString("VOIP_ZADARMA_API_SECRET"),
+	}
+
+	// Проверка обязательных параметров и их согласованности
+	validator.checkConfig(&cfg)
+	if err := validator.err(); err != nil {
+		return nil, err
 	}
 
 	return &cfg, nil
@@ -167,9 +704,16 @@ func setDefaults() {
 	viper.SetDefault("APP_NAME", "project_obsidian")
 	viper.SetDefault("APP_VERSION", "0.1.0")
 	viper.SetDefault("APP_ENV", "development")
+	viper.SetDefault("APP_RESTART_BACKOFF", time.Second)
+	viper.SetDefault("APP_RESTART_MAX_BACKOFF", time.Minute*5)
 
 	// Log
 	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("LOG_JOB_RETENTION", 7*24*time.Hour)
+
+	// Database
+	viper.SetDefault("DB_DRIVER", DatabaseDriverPostgres)
+	viper.SetDefault("SQLITE_PATH", "data/obsidian.db")
 
 	// PostgreSQL
 	viper.SetDefault("POSTGRES_HOST", "localhost")
@@ -179,20 +723,207 @@ func setDefaults() {
 	viper.SetDefault("POSTGRES_DB", "obsidian")
 	viper.SetDefault("POSTGRES_SSLMODE", "disable")
 	viper.SetDefault("POSTGRES_POOL_MAX", 10)
+	viper.SetDefault("POSTGRES_COMPRESS_MIN_BYTES", 2048)
 
 	// Redis
 	viper.SetDefault("REDIS_ADDR", "localhost:6379")
 	viper.SetDefault("REDIS_PASSWORD", "")
 	viper.SetDefault("REDIS_DB", 0)
+	viper.SetDefault("REDIS_USER_CACHE_TTL", time.Minute*10)
+
+	// Queue
+	viper.SetDefault("QUEUE_MAX_PANIC_ATTEMPTS", 3)
+	viper.SetDefault("QUEUE_FAILED_CLEANUP_INTERVAL", time.Hour*24)
+	viper.SetDefault("QUEUE_INLINE_MAX_DURATION", time.Second*15)
+	viper.SetDefault("QUEUE_INLINE_CONCURRENCY", 4)
+
+	// Telegram
+	viper.SetDefault("TELEGRAM_MODE", TelegramModePolling)
+	viper.SetDefault("TELEGRAM_WEBHOOK_LISTEN_ADDR", ":8443")
+	viper.SetDefault("TELEGRAM_DOWNLOAD_CONCURRENCY", 4)
+	viper.SetDefault("TELEGRAM_DOWNLOAD_RATE_MB", 0)
 
 	// OpenAI
 	viper.SetDefault("OPENAI_WHISPER_MODEL", "whisper-1")
+	viper.SetDefault("OPENAI_WHISPER_TEMPERATURE", 0)
+	viper.SetDefault("OPENAI_WHISPER_GRANULARITY", "text")
+	viper.SetDefault("OPENAI_WHISPER_MAX_FILE_MB", 24)
+	viper.SetDefault("OPENAI_WHISPER_CHUNK_OVERLAP", time.Second*10)
+	viper.SetDefault("OPENAI_WHISPER_CHUNK_CONCURRENCY", 3)
+	viper.SetDefault("OPENAI_EMBEDDING_MODEL", "text-embedding-3-small")
+	viper.SetDefault("OPENAI_OCR_MODEL", "gpt-4o-mini")
+	viper.SetDefault("OPENAI_TTS_MODEL", "tts-1")
+	viper.SetDefault("OPENAI_TTS_VOICE", "alloy")
 	viper.SetDefault("OPENAI_TIMEOUT", time.Second*30)
 
+	// Локальный сервер распознавания
+	viper.SetDefault("WHISPER_LOCAL_API", WhisperLocalAPIWhisperCpp)
+	viper.SetDefault("WHISPER_LOCAL_MODEL", "base")
+	viper.SetDefault("WHISPER_LOCAL_CONCURRENCY", 1)
+
+	// Deepgram
+	viper.SetDefault("DEEPGRAM_MODEL", "nova-2")
+	viper.SetDefault("DEEPGRAM_DIARIZE", false)
+	viper.SetDefault("DEEPGRAM_SMART_FORMAT", true)
+
+	// Diarization
+	viper.SetDefault("DIARIZATION_ENABLED", false)
+	viper.SetDefault("DIARIZATION_URL", "")
+
 	// DeepSeek
 	viper.SetDefault("DEEPSEEK_MODEL", "deepseek-chat")
 	viper.SetDefault("DEEPSEEK_TIMEOUT", time.Second*30)
+	viper.SetDefault("DEEPSEEK_CHUNK_SIZE", 12000)
+	viper.SetDefault("DEEPSEEK_CHUNK_CONCURRENCY", 4)
+	viper.SetDefault("DEEPSEEK_CHUNK_RETRIES", 3)
+	viper.SetDefault("DEEPSEEK_MAX_CONCURRENCY", 8)
+	viper.SetDefault("DEEPSEEK_COMPARE_MODELS", "")
+
+	// Notion
+	viper.SetDefault("NOTION_RECONCILE_INTERVAL", time.Hour*24)
+	viper.SetDefault("NOTION_USER_LOCK_TTL", time.Second*30)
+	viper.SetDefault("NOTION_USER_LOCK_WAIT", time.Minute*5)
+	viper.SetDefault("NOTION_RELATED_NOTES", 3)
 
 	// FFmpeg
 	viper.SetDefault("FFMPEG_BINARY_PATH", "ffmpeg")
-}
\ No newline at end of file
+	viper.SetDefault("FFMPEG_MAX_CONCURRENCY", 0)
+	viper.SetDefault("FFMPEG_ARTIFACT_RETENTION", 72*time.Hour)
+
+	// Temp
+	viper.SetDefault("TMP_DIR", "./data/tmp")
+	viper.SetDefault("TMP_MAX_SIZE_MB", 1024)
+
+	// HTTP
+	viper.SetDefault("HTTP_ADDR", ":8080")
+	viper.SetDefault("HTTP_PUBLIC_URL", "http://localhost:8080")
+
+	// Share
+	viper.SetDefault("SHARE_LINK_TTL", time.Hour*72)
+
+	// Text
+	viper.SetDefault("TEXT_MIN_LENGTH", 300)
+
+	// Duplicate
+	viper.SetDefault("DUPLICATE_SIMILARITY_THRESHOLD", 0.6)
+	viper.SetDefault("DUPLICATE_WINDOW", time.Hour*24*7)
+
+	// Podcast
+	viper.SetDefault("PODCAST_POLL_INTERVAL", time.Minute*30)
+	viper.SetDefault("PODCAST_MAX_EPISODE_MB", 500)
+
+	// Quota
+	viper.SetDefault("QUOTA_DAILY_JOBS", 0)
+	viper.SetDefault("QUOTA_AUDIO_WINDOW", time.Hour)
+	viper.SetDefault("QUOTA_AUDIO_FREE", 0)
+	viper.SetDefault("QUOTA_AUDIO_PREMIUM", 0)
+	viper.SetDefault("QUOTA_AUDIO_ADMIN", 0)
+
+	// Team
+	viper.SetDefault("TEAM_DIGEST_TIMEZONE", "Europe/Moscow")
+
+	// Analytics
+	viper.SetDefault("ANALYTICS_FAILURE_ROLLUP_INTERVAL", time.Hour)
+	viper.SetDefault("ANALYTICS_USAGE_EVENTS", true)
+
+	// Progress
+	viper.SetDefault("PROGRESS_KEEPALIVE_AFTER", time.Minute*3)
+	viper.SetDefault("PROGRESS_KEEPALIVE_INTERVAL", time.Minute)
+
+	// Stage
+	viper.SetDefault("STAGE_TRANSCRIPTION_TIMEOUT_BASE", time.Minute)
+	viper.SetDefault("STAGE_TRANSCRIPTION_TIMEOUT_FACTOR", 2)
+	viper.SetDefault("STAGE_SUMMARIZATION_TIMEOUT_BASE", time.Minute)
+	viper.SetDefault("STAGE_SUMMARIZATION_TIMEOUT_PER_1K_CHARS", time.Second*5)
+
+	// Video
+	viper.SetDefault("VIDEO_YTDLP_PATH", "yt-dlp")
+	viper.SetDefault("VIDEO_MAX_DURATION", time.Hour*3)
+
+	// URL ingestion
+	viper.SetDefault("URL_INGESTION_MAX_SIZE_MB", 200)
+
+	// PDF
+	viper.SetDefault("PDF_RENDERER_PATH", "")
+
+	// VoIP
+	viper.SetDefault("VOIP_PHONE_NUMBERS", "")
+	viper.SetDefault("VOIP_TWILIO_AUTH_TOKEN", "")
+	viper.SetDefault("VOIP_ZADARMA_API_KEY", "")
+	viper.SetDefault("VOIP_ZADARMA_API_SECRET", "")
+
+	// Secrets
+	viper.SetDefault("SECRETS_SOURCE", SecretsSourceEnv)
+	viper.SetDefault("SECRETS_REFRESH_INTERVAL", time.Minute*5)
+	viper.SetDefault("SECRETS_DIR", "/run/secrets")
+}
+
+// parseIDList разбирает список ID, разделенных запятыми
+func parseIDList(value string) ([]int64, error) {
+	ids := make([]int64, 0)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ID %q: %w", part, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseNameList разбирает список названий, разделенных запятыми, пропуская пустые
+func parseNameList(value string) []string {
+	names := make([]string, 0)
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			names = append(names, part)
+		}
+	}
+	return names
+}
+
+// PhoneNumberAny - ключ списка VOIP_PHONE_NUMBERS для звонков с номеров, которых нет в списке
+const PhoneNumberAny = "*"
+
+// parsePhoneNumbers разбирает список пар номер=Telegram ID, разделенных запятыми, например
+// "+7 495 123-45-67=123456789,*=123456789". В номере учитываются только цифры
+func parsePhoneNumbers(value string) (map[string]int64, error) {
+	numbers := make(map[string]int64)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		number, idValue, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected number=telegram_id, got %q", part)
+		}
+		id, err := strconv.ParseInt(strings.TrimSpace(idValue), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Telegram ID %q: %w", idValue, err)
+		}
+		number = strings.TrimSpace(number)
+		if number != PhoneNumberAny {
+			number = PhoneDigits(number)
+		}
+		if number == "" {
+			return nil, fmt.Errorf("phone number is empty in %q", part)
+		}
+		numbers[number] = id
+	}
+	return numbers, nil
+}
+
+// PhoneDigits оставляет в номере телефона только цифры, чтобы номера в разной записи совпадали
+func PhoneDigits(number string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, number)
+}
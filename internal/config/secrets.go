@@ -0,0 +1,386 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Имена секретов: совпадают с переменными окружения, из которых они читаются без внешнего источника,
+// с ключами в Vault и AWS Secrets Manager, а в нижнем регистре - с именами файлов Docker secrets
+const (
+	SecretTelegramToken  = "TELEGRAM_TOKEN"
+	SecretOpenAIAPIKey   = "OPENAI_API_KEY"
+	SecretDeepSeekAPIKey = "DEEPSEEK_API_KEY"
+	SecretDeepgramAPIKey = "DEEPGRAM_API_KEY"
+	SecretNotionAPIKey   = "NOTION_API_KEY"
+	SecretNotionOAuth    = "NOTION_OAUTH_CLIENT_SECRET"
+)
+
+// secretNames - секреты, которые читаются из внешнего источника
+var secretNames = []string{SecretTelegramToken, SecretOpenAIAPIKey, SecretDeepSeekAPIKey, SecretDeepgramAPIKey, SecretNotionAPIKey, SecretNotionOAuth}
+
+// Источники секретов
+const (
+	SecretsSourceEnv   = "env"   // Переменные окружения (по умолчанию)
+	SecretsSourceFile  = "file"  // Файлы Docker secrets: один файл на секрет в каталоге SECRETS_DIR
+	SecretsSourceVault = "vault" // KV-хранилище HashiCorp Vault
+	SecretsSourceAWS   = "aws"   // AWS Secrets Manager: секрет с JSON-объектом ключей
+)
+
+// secretsFetchTimeout - ограничение времени чтения секретов из внешнего источника
+const secretsFetchTimeout = 10 * time.Second
+
+// SecretsConfig содержит настройки источника секретов (ключей API и токена бота)
+type SecretsConfig struct {
+	Source          string        // env, file, vault или aws
+	RefreshInterval time.Duration // Период перечитывания секретов для подхвата ротации (0 - только при запуске)
+	Dir             string        // Каталог файлов Docker secrets
+	VaultAddr       string        // Адрес Vault, например https://vault.example.com:8200
+	VaultToken      string        // Токен доступа к Vault
+	VaultPath       string        // Путь секрета в Vault, для KV v2 - с "data/", например secret/data/obsidian
+	AWSRegion       string        // Регион AWS Secrets Manager
+	AWSSecretID     string        // Имя или ARN секрета в AWS Secrets Manager
+	AWSAccessKeyID  string
+	AWSSecretKey    string
+	AWSSessionToken string
+}
+
+// secretSource читает значения секретов из внешнего источника.
+// Секреты, которых нет в источнике, в результат не попадают
+type secretSource interface {
+	fetch(ctx context.Context, names []string) (map[string]string, error)
+}
+
+// SecretStore хранит секреты, прочитанные из источника, и перечитывает их по истечении периода обновления,
+// поэтому сервисы, получающие ключ при каждом запросе, подхватывают ротацию без перезапуска.
+// Если источник недоступен, используются последние прочитанные значения; секреты, которых нет в источнике,
+// берутся из переменных окружения
+type SecretStore struct {
+	source          secretSource
+	fallback        map[string]string
+	refreshInterval time.Duration
+
+	mu        sync.Mutex
+	values    map[string]string
+	fetchedAt time.Time
+	onError   func(error)
+}
+
+// newSecretStore создает хранилище секретов и читает их из источника.
+// Ошибка первого чтения возвращается, чтобы приложение не запустилось без ключей
+func newSecretStore(cfg SecretsConfig, fallback map[string]string) (*SecretStore, error) {
+	store := &SecretStore{
+		fallback:        fallback,
+		refreshInterval: cfg.RefreshInterval,
+		values:          make(map[string]string),
+	}
+
+	switch cfg.Source {
+	case SecretsSourceEnv:
+		return store, nil
+	case SecretsSourceFile:
+		store.source = &fileSecretSource{dir: cfg.Dir}
+	case SecretsSourceVault:
+		if cfg.VaultAddr == "" || cfg.VaultPath == "" {
+			return nil, fmt.Errorf("SECRETS_VAULT_ADDR and SECRETS_VAULT_PATH are required for vault secrets")
+		}
+		store.source = &vaultSecretSource{
+			addr:   strings.TrimRight(cfg.VaultAddr, "/"),
+			token:  cfg.VaultToken,
+			path:   strings.Trim(cfg.VaultPath, "/"),
+			client: &http.Client{},
+		}
+	case SecretsSourceAWS:
+		if cfg.AWSRegion == "" || cfg.AWSSecretID == "" {
+			return nil, fmt.Errorf("SECRETS_AWS_REGION and SECRETS_AWS_SECRET_ID are required for aws secrets")
+		}
+		if cfg.AWSAccessKeyID == "" || cfg.AWSSecretKey == "" {
+			return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required for aws secrets")
+		}
+		store.source = &awsSecretSource{
+			region:       cfg.AWSRegion,
+			secretID:     cfg.AWSSecretID,
+			accessKeyID:  cfg.AWSAccessKeyID,
+			secretKey:    cfg.AWSSecretKey,
+			sessionToken: cfg.AWSSessionToken,
+			client:       &http.Client{},
+		}
+	default:
+		return nil, fmt.Errorf("invalid SECRETS_SOURCE %q: expected %s, %s, %s or %s",
+			cfg.Source, SecretsSourceEnv, SecretsSourceFile, SecretsSourceVault, SecretsSourceAWS)
+	}
+
+	if err := store.refresh(); err != nil {
+		return nil, fmt.Errorf("failed to read secrets from %s: %w", cfg.Source, err)
+	}
+
+	return store, nil
+}
+
+// OnRefreshError задает обработчик ошибок перечитывания секретов (например, запись в журнал).
+// После ошибки хранилище продолжает выдавать прежние значения
+func (s *SecretStore) OnRefreshError(handler func(error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onError = handler
+}
+
+// Get возвращает текущее значение секрета, при необходимости перечитывая секреты из источника
+func (s *SecretStore) Get(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.source != nil && s.refreshInterval > 0 && time.Since(s.fetchedAt) >= s.refreshInterval {
+		if err := s.refreshLocked(); err != nil && s.onError != nil {
+			s.onError(fmt.Errorf("failed to refresh secrets: %w", err))
+		}
+	}
+
+	if value, ok := s.values[name]; ok {
+		return value
+	}
+	return s.fallback[name]
+}
+
+// Getter возвращает функцию чтения секрета для сервисов, которые запрашивают ключ перед каждым обращением к API
+func (s *SecretStore) Getter(name string) func() string {
+	return func() string {
+		return s.Get(name)
+	}
+}
+
+// refresh перечитывает секреты из источника
+func (s *SecretStore) refresh() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.refreshLocked()
+}
+
+// refreshLocked перечитывает секреты из источника; вызывается под блокировкой.
+// При ошибке момент чтения все равно обновляется, чтобы недоступный источник не опрашивался на каждый запрос
+func (s *SecretStore) refreshLocked() error {
+	s.fetchedAt = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretsFetchTimeout)
+	defer cancel()
+
+	values, err := s.source.fetch(ctx, secretNames)
+	if err != nil {
+		return err
+	}
+
+	s.values = values
+	return nil
+}
+
+// fileSecretSource читает секреты из файлов Docker secrets: значение секрета OPENAI_API_KEY
+// хранится в файле <dir>/openai_api_key
+type fileSecretSource struct {
+	dir string
+}
+
+// fetch читает файлы секретов; отсутствующие файлы пропускаются
+func (s *fileSecretSource) fetch(ctx context.Context, names []string) (map[string]string, error) {
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(s.dir, strings.ToLower(name)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret file: %w", err)
+		}
+		values[name] = strings.TrimSpace(string(data))
+	}
+	return values, nil
+}
+
+// vaultSecretSource читает секреты из KV-хранилища HashiCorp Vault (версий 1 и 2)
+type vaultSecretSource struct {
+	addr   string
+	token  string
+	path   string
+	client *http.Client
+}
+
+// fetch читает секрет Vault и возвращает его строковые ключи
+func (s *vaultSecretSource) fetch(ctx context.Context, names []string) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.addr+"/v1/"+s.path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	// В KV v2 ключи секрета вложены в data.data, в KV v1 лежат прямо в data
+	data := body.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	return pickSecrets(data, names), nil
+}
+
+// awsSecretSource читает секреты из AWS Secrets Manager. Значение секрета - JSON-объект
+// с ключами по именам секретов; запросы подписываются AWS Signature Version 4
+type awsSecretSource struct {
+	region       string
+	secretID     string
+	accessKeyID  string
+	secretKey    string
+	sessionToken string
+	client       *http.Client
+}
+
+// fetch запрашивает значение секрета GetSecretValue и возвращает его строковые ключи
+func (s *awsSecretSource) fetch(ctx context.Context, names []string) (map[string]string, error) {
+	payload, err := json.Marshal(map[string]string{"SecretId": s.secretID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal aws request: %w", err)
+	}
+
+	host := fmt.Sprintf("secretsmanager.%s.amazonaws.com", s.region)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+host+"/", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aws request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	s.sign(req, host, payload, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request aws secrets manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("aws secrets manager returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var body struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode aws response: %w", err)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(body.SecretString), &data); err != nil {
+		return nil, fmt.Errorf("aws secret is not a JSON object: %w", err)
+	}
+
+	return pickSecrets(data, names), nil
+}
+
+// sign подписывает запрос к AWS по алгоритму Signature Version 4
+func (s *awsSecretSource) sign(req *http.Request, host string, payload []byte, now time.Time) {
+	const service = "secretsmanager"
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	// Подписываемые заголовки перечисляются в алфавитном порядке
+	headers := map[string]string{
+		"content-type": req.Header.Get("Content-Type"),
+		"host":         host,
+		"x-amz-date":   amzDate,
+		"x-amz-target": req.Header.Get("X-Amz-Target"),
+	}
+	names := []string{"content-type", "host", "x-amz-date"}
+	if s.sessionToken != "" {
+		headers["x-amz-security-token"] = s.sessionToken
+		names = append(names, "x-amz-security-token")
+	}
+	names = append(names, "x-amz-target")
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodPost,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		hashHex(payload),
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature))
+}
+
+// hashHex возвращает SHA-256 данных в шестнадцатеричном виде
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 возвращает HMAC-SHA256 данных
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// pickSecrets выбирает из данных источника строковые значения известных секретов
+func pickSecrets(data map[string]interface{}, names []string) map[string]string {
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		if value, ok := data[name].(string); ok && value != "" {
+			values[name] = value
+		}
+	}
+	return values
+}
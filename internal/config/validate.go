@@ -0,0 +1,301 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// durationKeys - параметры-длительности. viper молча превращает нераспознанное значение в 0,
+// а число без единицы измерения - в наносекунды, поэтому значения проверяются до чтения
+var durationKeys = []string{
+	"LOG_JOB_RETENTION",
+	"REDIS_USER_CACHE_TTL",
+	"OPENAI_TIMEOUT",
+	"OPENAI_WHISPER_CHUNK_OVERLAP",
+	"DEEPSEEK_TIMEOUT",
+	"NOTION_RECONCILE_INTERVAL",
+	"NOTION_USER_LOCK_TTL",
+	"NOTION_USER_LOCK_WAIT",
+	"SHARE_LINK_TTL",
+	"PODCAST_POLL_INTERVAL",
+	"QUOTA_AUDIO_WINDOW",
+	"ANALYTICS_FAILURE_ROLLUP_INTERVAL",
+	"PROGRESS_KEEPALIVE_AFTER",
+	"PROGRESS_KEEPALIVE_INTERVAL",
+	"STAGE_TRANSCRIPTION_TIMEOUT_BASE",
+	"STAGE_SUMMARIZATION_TIMEOUT_BASE",
+	"STAGE_SUMMARIZATION_TIMEOUT_PER_1K_CHARS",
+	"VIDEO_MAX_DURATION",
+	"SECRETS_REFRESH_INTERVAL",
+	"QUEUE_FAILED_CLEANUP_INTERVAL",
+	"APP_RESTART_BACKOFF",
+	"APP_RESTART_MAX_BACKOFF",
+	"FFMPEG_ARTIFACT_RETENTION",
+	"DUPLICATE_WINDOW",
+}
+
+// integerKeys - целочисленные параметры и их минимальные значения
+var integerKeys = []struct {
+	key string
+	min int64
+}{
+	{"POSTGRES_POOL_MAX", 1},
+	{"REDIS_DB", 0},
+	{"QUEUE_MAX_PANIC_ATTEMPTS", 1},
+	{"TELEGRAM_WEBHOOK_MAX_CONNECTIONS", 0},
+	{"TELEGRAM_DOWNLOAD_CONCURRENCY", 1},
+	{"TELEGRAM_DOWNLOAD_RATE_MB", 0},
+	{"DEEPSEEK_CHUNK_SIZE", 1},
+	{"DEEPSEEK_CHUNK_CONCURRENCY", 1},
+	{"DEEPSEEK_CHUNK_RETRIES", 0},
+	{"DEEPSEEK_MAX_CONCURRENCY", 1},
+	{"TMP_MAX_SIZE_MB", 0},
+	{"TEXT_MIN_LENGTH", 0},
+	{"PODCAST_MAX_EPISODE_MB", 1},
+	{"QUOTA_DAILY_JOBS", 0},
+	{"QUOTA_AUDIO_FREE", 0},
+	{"QUOTA_AUDIO_PREMIUM", 0},
+	{"QUOTA_AUDIO_ADMIN", 0},
+	{"FFMPEG_MAX_CONCURRENCY", 0},
+	{"WHISPER_LOCAL_CONCURRENCY", 1},
+	{"OPENAI_WHISPER_MAX_FILE_MB", 0},
+	{"OPENAI_WHISPER_CHUNK_CONCURRENCY", 1},
+	{"NOTION_RELATED_NOTES", 0},
+}
+
+// floatKeys - дробные параметры и допустимые диапазоны значений
+var floatKeys = []struct {
+	key      string
+	min, max float64
+}{
+	{"OPENAI_WHISPER_TEMPERATURE", 0, 1},
+	{"STAGE_TRANSCRIPTION_TIMEOUT_FACTOR", 0, 100},
+	{"DUPLICATE_SIMILARITY_THRESHOLD", 0, 1},
+}
+
+// boolKeys - логические параметры. viper молча превращает нераспознанное значение в false
+var boolKeys = []string{
+	"ANALYTICS_USAGE_EVENTS",
+	"DEEPGRAM_DIARIZE",
+	"DEEPGRAM_SMART_FORMAT",
+	"DIARIZATION_ENABLED",
+}
+
+// logLevels - уровни логирования, которые понимает логгер
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+// ValidationError представляет собой отчет обо всех ошибках конфигурации, найденных при запуске
+type ValidationError struct {
+	Problems []string
+}
+
+// Error возвращает отчет об ошибках конфигурации, по одной в строке
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration, %d problem(s):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// configValidator собирает ошибки конфигурации, чтобы сообщить обо всех сразу, а не об одной за запуск
+type configValidator struct {
+	problems []string
+}
+
+// addf добавляет ошибку в отчет
+func (v *configValidator) addf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// err возвращает отчет об ошибках или nil, если ошибок нет
+func (v *configValidator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: v.problems}
+}
+
+// checkFormats проверяет формат значений параметров до их чтения в структуру конфигурации
+func (v *configValidator) checkFormats() {
+	for _, key := range durationKeys {
+		raw := strings.TrimSpace(viper.GetString(key))
+		if raw == "" || raw == "0" {
+			continue
+		}
+		duration, err := time.ParseDuration(raw)
+		switch {
+		case err != nil:
+			v.addf("%s=%q is not a duration: use a number with a unit, e.g. 30s, 5m or 1h30m", key, raw)
+		case duration < 0:
+			v.addf("%s=%q must not be negative", key, raw)
+		}
+	}
+
+	for _, param := range integerKeys {
+		raw := strings.TrimSpace(viper.GetString(param.key))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(raw, 10, 64)
+		switch {
+		case err != nil:
+			v.addf("%s=%q is not an integer", param.key, raw)
+		case value < param.min:
+			v.addf("%s=%d must be at least %d", param.key, value, param.min)
+		}
+	}
+
+	for _, param := range floatKeys {
+		raw := strings.TrimSpace(viper.GetString(param.key))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		switch {
+		case err != nil:
+			v.addf("%s=%q is not a number", param.key, raw)
+		case value < param.min || value > param.max:
+			v.addf("%s=%g must be between %g and %g", param.key, value, param.min, param.max)
+		}
+	}
+
+	for _, key := range boolKeys {
+		raw := strings.TrimSpace(viper.GetString(key))
+		if raw == "" {
+			continue
+		}
+		if _, err := strconv.ParseBool(raw); err != nil {
+			v.addf("%s=%q is not a boolean: use true or false", key, raw)
+		}
+	}
+
+	v.checkOneOf("LOG_LEVEL", strings.ToLower(viper.GetString("LOG_LEVEL")), logLevels...)
+	v.checkOneOf("DB_DRIVER", viper.GetString("DB_DRIVER"), DatabaseDriverPostgres, DatabaseDriverSQLite)
+	v.checkOneOf("TELEGRAM_MODE", viper.GetString("TELEGRAM_MODE"), TelegramModePolling, TelegramModeWebhook)
+	v.checkOneOf("OPENAI_WHISPER_GRANULARITY", viper.GetString("OPENAI_WHISPER_GRANULARITY"), "text", "segment")
+	v.checkOneOf("WHISPER_LOCAL_API", viper.GetString("WHISPER_LOCAL_API"), WhisperLocalAPIWhisperCpp, WhisperLocalAPIOpenAI)
+	v.checkOneOf("OPENAI_TTS_MODEL", viper.GetString("OPENAI_TTS_MODEL"), "tts-1", "tts-1-hd")
+	v.checkOneOf("OPENAI_TTS_VOICE", viper.GetString("OPENAI_TTS_VOICE"), "alloy", "echo", "fable", "onyx", "nova", "shimmer")
+
+	v.checkURL("HTTP_PUBLIC_URL", viper.GetString("HTTP_PUBLIC_URL"), false)
+	v.checkURL("TELEGRAM_WEBHOOK_URL", viper.GetString("TELEGRAM_WEBHOOK_URL"), true)
+	v.checkURL("TELEGRAM_API_URL", viper.GetString("TELEGRAM_API_URL"), false)
+	v.checkURL("SYNC_WEBHOOK_URL", viper.GetString("SYNC_WEBHOOK_URL"), false)
+	v.checkURL("SECRETS_VAULT_ADDR", viper.GetString("SECRETS_VAULT_ADDR"), false)
+	v.checkURL("WHISPER_LOCAL_URL", viper.GetString("WHISPER_LOCAL_URL"), false)
+	v.checkURL("DIARIZATION_URL", viper.GetString("DIARIZATION_URL"), false)
+
+	if timezone := viper.GetString("TEAM_DIGEST_TIMEZONE"); timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			v.addf("TEAM_DIGEST_TIMEZONE=%q is not a known time zone, e.g. Europe/Moscow", timezone)
+		}
+	}
+}
+
+// webhookSecretPattern - символы и длина, которые Telegram допускает в секрете вебхука
+var webhookSecretPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// checkConfig проверяет обязательные параметры и согласованность прочитанной конфигурации
+func (v *configValidator) checkConfig(cfg *Config) {
+	if cfg.Telegram.Token == "" {
+		v.addf("%s is required: create a bot with @BotFather", SecretTelegramToken)
+	}
+	if cfg.Telegram.Mode == TelegramModeWebhook && cfg.Telegram.WebhookURL == "" {
+		v.addf("TELEGRAM_WEBHOOK_URL is required for TELEGRAM_MODE=%s", TelegramModeWebhook)
+	}
+	// Без секрета любой может отправить на вебхук поддельное обновление от имени администратора
+	if cfg.Telegram.Mode == TelegramModeWebhook {
+		switch secret := cfg.Telegram.WebhookSecret; {
+		case secret == "":
+			v.addf("TELEGRAM_WEBHOOK_SECRET is required for TELEGRAM_MODE=%s", TelegramModeWebhook)
+		case !webhookSecretPattern.MatchString(secret):
+			v.addf("TELEGRAM_WEBHOOK_SECRET must be 1-256 characters of A-Z, a-z, 0-9, _ and -")
+		}
+	}
+	if channel := cfg.Telegram.AllowedChannel; channel != "" && !strings.HasPrefix(channel, "@") {
+		if _, err := strconv.ParseInt(channel, 10, 64); err != nil {
+			v.addf("TELEGRAM_ALLOWED_CHANNEL=%q must be a numeric chat ID or a @username", channel)
+		}
+	}
+
+	// Распознавание речи пока выполняет только Whisper, поэтому его ключ обязателен
+	if cfg.OpenAI.APIKey == "" {
+		v.addf("no transcription provider is configured: set %s for Whisper", SecretOpenAIAPIKey)
+	}
+	if cfg.WhisperLocal.Enabled() && cfg.Deepgram.Enabled() {
+		v.addf("WHISPER_LOCAL_URL and %s select different transcription providers: set only one of them", SecretDeepgramAPIKey)
+	}
+	if cfg.OpenAI.WhisperMaxFileMB > 25 {
+		v.addf("OPENAI_WHISPER_MAX_FILE_MB=%d exceeds the 25 MB file limit of the Whisper API", cfg.OpenAI.WhisperMaxFileMB)
+	}
+	// Мегабайт подготовленного WAV (16 кГц, моно, 16 бит) - около 32 секунд записи
+	if chunk := time.Duration(cfg.OpenAI.WhisperMaxFileMB) * 32 * time.Second; chunk > 0 && cfg.OpenAI.WhisperChunkOverlap*2 >= chunk {
+		v.addf("OPENAI_WHISPER_CHUNK_OVERLAP=%s must be shorter than half of a chunk (about %s for OPENAI_WHISPER_MAX_FILE_MB=%d)",
+			cfg.OpenAI.WhisperChunkOverlap, chunk/2, cfg.OpenAI.WhisperMaxFileMB)
+	}
+	if cfg.Diarization.Enabled && !cfg.Deepgram.Enabled() && cfg.Diarization.URL == "" {
+		v.addf("DIARIZATION_ENABLED requires %s or DIARIZATION_URL: Whisper cannot tell speakers apart", SecretDeepgramAPIKey)
+	}
+	if cfg.DeepSeek.APIKey == "" {
+		v.addf("%s is required for summarization", SecretDeepSeekAPIKey)
+	}
+	if models := cfg.DeepSeek.CompareModels; len(models) != 0 && (len(models) != 2 || models[0] == models[1]) {
+		v.addf("DEEPSEEK_COMPARE_MODELS must list two different models separated by a comma, e.g. deepseek-chat,deepseek-reasoner")
+	}
+
+	if cfg.Database.Driver == DatabaseDriverSQLite && cfg.Database.SQLitePath == "" {
+		v.addf("SQLITE_PATH is required for DB_DRIVER=%s", DatabaseDriverSQLite)
+	}
+	if (cfg.Notion.OAuthClientID == "") != (cfg.Notion.OAuthClientSecret == "") {
+		v.addf("NOTION_OAUTH_CLIENT_ID and %s must be set together", SecretNotionOAuth)
+	}
+	if (cfg.VoIP.ZadarmaAPIKey == "") != (cfg.VoIP.ZadarmaAPISecret == "") {
+		v.addf("VOIP_ZADARMA_API_KEY and VOIP_ZADARMA_API_SECRET must be set together")
+	}
+	if cfg.VoIP.TwilioAuthToken != "" && cfg.HTTP.PublicURL == "" {
+		v.addf("HTTP_PUBLIC_URL is required for VOIP_TWILIO_AUTH_TOKEN: Twilio signs the public webhook URL")
+	}
+	if len(cfg.VoIP.PhoneNumbers) > 0 && cfg.VoIP.TwilioAuthToken == "" && cfg.VoIP.ZadarmaAPISecret == "" {
+		v.addf("VOIP_PHONE_NUMBERS is set, but no VoIP provider is configured: set VOIP_TWILIO_AUTH_TOKEN or VOIP_ZADARMA_API_KEY")
+	}
+	if quota := cfg.Quota; (quota.AudioFree > 0 || quota.AudioPremium > 0 || quota.AudioAdmin > 0) && quota.AudioWindow <= 0 {
+		v.addf("QUOTA_AUDIO_WINDOW must be positive when an audio limit is set")
+	}
+	if cfg.Progress.KeepaliveAfter > 0 && cfg.Progress.KeepaliveInterval <= 0 {
+		v.addf("PROGRESS_KEEPALIVE_INTERVAL must be positive when PROGRESS_KEEPALIVE_AFTER is set")
+	}
+	if cfg.App.RestartBackoff <= 0 {
+		v.addf("APP_RESTART_BACKOFF must be positive")
+	} else if cfg.App.RestartMaxBackoff < cfg.App.RestartBackoff {
+		v.addf("APP_RESTART_MAX_BACKOFF must not be less than APP_RESTART_BACKOFF")
+	}
+}
+
+// checkOneOf проверяет, что значение параметра входит в список допустимых
+func (v *configValidator) checkOneOf(key, value string, allowed ...string) {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+	v.addf("%s=%q is invalid: expected %s", key, value, strings.Join(allowed, ", "))
+}
+
+// checkURL проверяет, что непустое значение параметра - абсолютный адрес http(s); для requireHTTPS - только https
+func (v *configValidator) checkURL(key, value string, requireHTTPS bool) {
+	if value == "" {
+		return
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		v.addf("%s=%q is not an absolute http(s) URL", key, value)
+		return
+	}
+	if requireHTTPS && parsed.Scheme != "https" {
+		v.addf("%s=%q must use https", key, value)
+	}
+}
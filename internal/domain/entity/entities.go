@@ -4,36 +4,198 @@ import (
 	"time"
 )
 
-// User представляет собой сущность пользователя
+// User представляет собой сущность пользователя. Пользователь соответствует чату с ботом (TelegramID - ID чата):
+// для личного чата это сам человек, для группы - общие настройки группы, в том числе общая база Notion,
+// а FirstName - название группы
 type User struct {
-	ID              int64     `json:"id" db:"id"`
-	TelegramID      int64     `json:"telegram_id" db:"telegram_id"`
-	Username        string    `json:"username" db:"username"`
-	FirstName       string    `json:"first_name" db:"first_name"`
-	LastName        string    `json:"last_name" db:"last_name"`
-	NotionToken     string    `json:"notion_token" db:"notion_token"`
-	NotionDatabaseID string    `json:"notion_database_id" db:"notion_database_id"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	ID               int64  `json:"id" db:"id"`
+	TelegramID       int64  `json:"telegram_id" db:"telegram_id"`
+	Username         string `json:"username" db:"username"`
+	FirstName        string `json:"first_name" db:"first_name"`
+	LastName         string `json:"last_name" db:"last_name"`
+	NotionToken      string `json:"notion_token" db:"notion_token"`
+	NotionDatabaseID string `json:"notion_database_id" db:"notion_database_id"`
+	// NotionHubPageID - страница со ссылками на представления базы (по тегам, по неделям)
+	NotionHubPageID string `json:"notion_hub_page_id" db:"notion_hub_page_id"`
+	// ResultRetentionHours - через сколько часов удалять из чата сообщения с результатами (0 - не удалять)
+	ResultRetentionHours int `json:"result_retention_hours" db:"result_retention_hours"`
+	// TranscriptionOptions - пользовательские параметры декодирования Whisper поверх значений из конфигурации
+	TranscriptionOptions TranscriptionOptions `json:"transcription_options" db:"transcription_options"`
+	// SummaryLanguage - код языка кратких содержаний (пустая строка - язык транскрипции)
+	SummaryLanguage string `json:"summary_language" db:"summary_language"`
+	// LanguageCode - код языка из профиля Telegram, полученный с последним сообщением пользователя
+	LanguageCode string `json:"language_code" db:"language_code"`
+	// ChatType - тип чата: личный чат или группа
+	ChatType ChatType `json:"chat_type" db:"chat_type"`
+	// IsAdmin - пользователь входит в список администраторов бота из конфигурации (TELEGRAM_ADMIN_IDS)
+	IsAdmin bool `json:"is_admin" db:"is_admin"`
+	// BannedAt - время блокировки пользователя администратором (nil - пользователь не заблокирован)
+	BannedAt *time.Time `json:"banned_at" db:"banned_at"`
+	// BanReason - причина блокировки, указанная администратором
+	BanReason string    `json:"ban_reason" db:"ban_reason"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// IsGroup сообщает, что пользователь - это групповой чат
+func (u *User) IsGroup() bool {
+	return u.ChatType == ChatTypeGroup || u.ChatType == ChatTypeSupergroup
+}
+
+// ChatType представляет тип чата Telegram
+type ChatType string
+
+// Константы для типов чата
+const (
+	ChatTypePrivate    ChatType = "private"    // Личный чат с пользователем
+	ChatTypeGroup      ChatType = "group"      // Группа
+	ChatTypeSupergroup ChatType = "supergroup" // Супергруппа
+)
+
+// TranscriptionGranularity представляет детализацию ответа Whisper
+type TranscriptionGranularity string
+
+// Константы для детализации ответа Whisper
+const (
+	TranscriptionGranularityText    TranscriptionGranularity = "text"    // Сплошной текст
+	TranscriptionGranularitySegment TranscriptionGranularity = "segment" // Текст по сегментам с временными метками
+)
+
+// TranscriptionOptions представляет собой параметры декодирования Whisper.
+// Незаданные поля не переопределяют значения по умолчанию
+type TranscriptionOptions struct {
+	Temperature *float32                 `json:"temperature,omitempty"` // Температура декодирования (0 - без случайности)
+	Prompt      string                   `json:"prompt,omitempty"`      // Подсказка: термины, имена и стиль текста
+	Granularity TranscriptionGranularity `json:"granularity,omitempty"` // Детализация ответа
+	Language    string                   `json:"language,omitempty"`    // Код языка записи ISO 639-1 (пусто - определяется автоматически)
+}
+
+// Merge возвращает параметры, в которых заданные поля override заменяют текущие
+func (o TranscriptionOptions) Merge(override TranscriptionOptions) TranscriptionOptions {
+	if override.Temperature != nil {
+		o.Temperature = override.Temperature
+	}
+	if override.Prompt != "" {
+		o.Prompt = override.Prompt
+	}
+	if override.Granularity != "" {
+		o.Granularity = override.Granularity
+	}
+	if override.Language != "" {
+		o.Language = override.Language
+	}
+	return o
+}
+
+// SummaryStyle представляет стиль краткого содержания
+type SummaryStyle string
+
+// Константы для стилей краткого содержания
+const (
+	SummaryStyleStandard SummaryStyle = "standard" // Обычное краткое содержание
+	SummaryStyleBrief    SummaryStyle = "brief"    // Несколько предложений о главном
+	SummaryStyleDetailed SummaryStyle = "detailed" // Подробный конспект по разделам
+	SummaryStyleBullets  SummaryStyle = "bullets"  // Только тезисы списком
+	SummaryStyleMinutes  SummaryStyle = "minutes"  // Протокол встречи: решения и задачи
+)
+
+// SummaryStyles - стили краткого содержания в порядке показа пользователю
+var SummaryStyles = []SummaryStyle{SummaryStyleStandard, SummaryStyleBrief, SummaryStyleDetailed, SummaryStyleBullets, SummaryStyleMinutes}
+
+// Pipeline представляет именованный набор настроек обработки записи, который выбирается одной кнопкой
+type Pipeline string
+
+// Константы для наборов настроек обработки
+const (
+	PipelineFast    Pipeline = "fast"    // Быстро: без шумоподавления, короткое краткое содержание
+	PipelineQuality Pipeline = "quality" // Качественно: полная предобработка, подробный конспект
+)
+
+// PipelineDefinition описывает, какие настройки задачи выставляет набор
+type PipelineDefinition struct {
+	AudioProfile AudioProfile
+	SummaryStyle SummaryStyle
+}
+
+// Pipelines - наборы настроек обработки в порядке показа пользователю
+var Pipelines = []Pipeline{PipelineFast, PipelineQuality}
+
+// PipelineDefinitions - настройки, которые выставляет каждый набор. Профиль «С музыкой» только
+// нормализует громкость, поэтому быстрый набор обходится без шумоподавления
+var PipelineDefinitions = map[Pipeline]PipelineDefinition{
+	PipelineFast:    {AudioProfile: AudioProfileMusic, SummaryStyle: SummaryStyleBrief},
+	PipelineQuality: {AudioProfile: AudioProfileAuto, SummaryStyle: SummaryStyleDetailed},
+}
+
+// UserSettings представляет собой настройки обработки записей, которые пользователь выбирает командой /settings
+type UserSettings struct {
+	UserID int64 `json:"user_id" db:"user_id"`
+	// TranscriptionLanguage - код языка записей для Whisper (пустая строка - язык определяется автоматически)
+	TranscriptionLanguage string       `json:"transcription_language" db:"transcription_language"`
+	SummaryStyle          SummaryStyle `json:"summary_style" db:"summary_style"`
+	// NotionAutoSync - выгружать ли результаты задач в Notion автоматически
+	NotionAutoSync bool `json:"notion_auto_sync" db:"notion_auto_sync"`
+	// FailedJobsCleanup - перезапускать ли задачи с ошибками раз в день и присылать сводку о неисправимых
+	FailedJobsCleanup bool `json:"failed_jobs_cleanup" db:"failed_jobs_cleanup"`
+	// FailedJobsCleanupAt - время последнего разбора задач с ошибками (nil - разбора еще не было)
+	FailedJobsCleanupAt *time.Time `json:"failed_jobs_cleanup_at" db:"failed_jobs_cleanup_at"`
+	// UsageAnalyticsOptOut - не записывать обезличенные события использования бота
+	UsageAnalyticsOptOut bool `json:"usage_analytics_opt_out" db:"usage_analytics_opt_out"`
+	// InterfaceLanguage - язык ответов бота, выбранный пользователем (пустая строка - язык профиля Telegram)
+	InterfaceLanguage string `json:"interface_language" db:"interface_language"`
+	// SentimentAnalysis - добавлять ли в краткое содержание встреч разбор настроения и напряженных моментов
+	SentimentAnalysis bool `json:"sentiment_analysis" db:"sentiment_analysis"`
+	// SummaryStyleRemembered - пользователь запомнил выбор стиля, и бот больше не спрашивает стиль для каждой записи
+	SummaryStyleRemembered bool `json:"summary_style_remembered" db:"summary_style_remembered"`
+	// NotionTranscript - добавлять ли на страницу задачи в Notion полную транскрипцию
+	NotionTranscript bool `json:"notion_transcript" db:"notion_transcript"`
+	// NotionSummaryOnly - выгружать в Notion только краткое содержание, без остальных разделов страницы
+	NotionSummaryOnly bool `json:"notion_summary_only" db:"notion_summary_only"`
+	// NotionAudioStats - добавлять ли на страницу задачи в Notion сведения о записи: длительность, профиль
+	// предобработки и темп речи
+	NotionAudioStats bool `json:"notion_audio_stats" db:"notion_audio_stats"`
+	// NotionUsageCost - добавлять ли на страницу задачи в Notion модель, токены и стоимость суммаризации
+	NotionUsageCost bool      `json:"notion_usage_cost" db:"notion_usage_cost"`
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // Job представляет собой сущность задачи обработки аудио
 type Job struct {
-	ID              int64     `json:"id" db:"id"`
-	UserID          int64     `json:"user_id" db:"user_id"`
-	Type            JobType   `json:"type" db:"type"`
-	Status          JobStatus `json:"status" db:"status"`
-	AudioFilePath   string    `json:"audio_file_path" db:"audio_file_path"`
-	FileName        string    `json:"file_name" db:"file_name"`
-	Duration        float64   `json:"duration" db:"duration"`
-	Transcription   string    `json:"transcription" db:"transcription"`
-	Summary         string    `json:"summary" db:"summary"`
-	NotionPageID    string    `json:"notion_page_id" db:"notion_page_id"`
-	NotionDatabaseID string   `json:"notion_database_id" db:"notion_database_id"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
-	CompletedAt     *time.Time `json:"completed_at" db:"completed_at"`
-	ErrorMessage    string    `json:"error_message" db:"error_message"`
+	ID                    int64            `json:"id" db:"id"`
+	UserID                int64            `json:"user_id" db:"user_id"`
+	Type                  JobType          `json:"type" db:"type"`
+	Status                JobStatus        `json:"status" db:"status"`
+	AudioFilePath         string           `json:"audio_file_path" db:"audio_file_path"`
+	FileName              string           `json:"file_name" db:"file_name"`
+	Duration              float64          `json:"duration" db:"duration"`
+	Transcription         string           `json:"transcription" db:"transcription"`
+	Summary               string           `json:"summary" db:"summary"`
+	NotionPageID          string           `json:"notion_page_id" db:"notion_page_id"`
+	NotionDatabaseID      string           `json:"notion_database_id" db:"notion_database_id"`
+	SourceMessageID       int              `json:"source_message_id" db:"source_message_id"`                 // ID исходного сообщения в Telegram
+	Instructions          string           `json:"instructions" db:"instructions"`                           // Дополнительные указания к конспекту из подписи к аудио
+	AudioProfile          AudioProfile     `json:"audio_profile" db:"audio_profile"`                         // Профиль предобработки аудио
+	SummaryModel          string           `json:"summary_model" db:"summary_model"`                         // Модель, которой выполнена суммаризация
+	SummaryCost           float64          `json:"summary_cost" db:"summary_cost"`                           // Стоимость суммаризации, USD
+	SummaryTokens         int              `json:"summary_tokens" db:"summary_tokens"`                       // Токены запросов и ответов всех попыток суммаризации
+	KeepaliveMessageID    int              `json:"keepalive_message_id" db:"keepalive_message_id"`           // ID сообщения «всё ещё обрабатываю» (0 - не отправлено)
+	CleanupRetriedAt      *time.Time       `json:"cleanup_retried_at" db:"cleanup_retried_at"`               // Когда задача перезапущена ежедневным разбором ошибок
+	FileUniqueID          string           `json:"file_unique_id" db:"file_unique_id"`                       // Постоянный идентификатор исходного файла в Telegram
+	FailedStage           JobStageName     `json:"failed_stage" db:"failed_stage"`                           // Этап, на котором задача завершилась ошибкой (пусто - ошибки нет)
+	NotionUploadPageID    string           `json:"notion_upload_page_id" db:"notion_upload_page_id"`         // Страница Notion, выгрузка содержимого на которую прервана
+	NotionUploadCursor    int              `json:"notion_upload_cursor" db:"notion_upload_cursor"`           // Сколько блоков содержимого уже добавлено на эту страницу
+	ProgressMessageID     int              `json:"progress_message_id" db:"progress_message_id"`             // ID сообщения о ходе обработки, которое обновляется на каждом этапе (0 - не отправлено)
+	SummaryStyle          SummaryStyle     `json:"summary_style" db:"summary_style"`                         // Стиль краткого содержания, выбранный для задачи (пусто - из настроек)
+	ProcessedAudioPath    string           `json:"processed_audio_path" db:"processed_audio_path"`           // Подготовленный к распознаванию WAV (пусто - нет или удален)
+	ProcessedAudioProfile AudioProfile     `json:"processed_audio_profile" db:"processed_audio_profile"`     // Профиль предобработки, с которым подготовлен WAV
+	TranscriptSignature   string           `json:"-" db:"transcript_minhash"`                                // Сигнатура MinHash транскрипции для поиска повторов
+	DuplicateOfJobID      int64            `json:"duplicate_of_job_id,omitempty" db:"duplicate_of_job_id"`   // Задача, повтором которой считается эта (0 - не повтор)
+	Segments              []SpeakerSegment `json:"segments,omitempty" db:"speaker_segments"`                 // Фразы транскрипции с говорящими, если она разделена по говорящим
+	DuplicateSimilarity   float64          `json:"duplicate_similarity,omitempty" db:"duplicate_similarity"` // Оценка доли совпадения транскрипций с DuplicateOfJobID
+	CreatedAt             time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time        `json:"updated_at" db:"updated_at"`
+	CompletedAt           *time.Time       `json:"completed_at" db:"completed_at"`
+	ErrorMessage          string           `json:"error_message" db:"error_message"`
 }
 
 // JobStatus представляет статус задачи
@@ -49,12 +211,117 @@ const (
 	JobStatusFailed      JobStatus = "failed"       // Задача завершена с ошибкой
 )
 
+// AudioProfile представляет профиль предобработки аудио перед транскрибацией
+type AudioProfile string
+
+// Константы для профилей предобработки аудио
+const (
+	AudioProfileAuto    AudioProfile = "auto"    // Профиль выбирается по характеристикам записи
+	AudioProfileSpeech  AudioProfile = "speech"  // Обычная речь: нормализация громкости и шумоподавление
+	AudioProfilePhone   AudioProfile = "phone"   // Телефонный разговор: полосовой фильтр 300–3400 Гц
+	AudioProfileLecture AudioProfile = "lecture" // Лекция в зале: приглушение реверберации
+	AudioProfileMusic   AudioProfile = "music"   // Речь на фоне музыки: только нормализация громкости
+)
+
+// AudioProfiles - профили, доступные для выбора пользователем, в порядке отображения
+var AudioProfiles = []AudioProfile{
+	AudioProfileAuto,
+	AudioProfileSpeech,
+	AudioProfilePhone,
+	AudioProfileLecture,
+	AudioProfileMusic,
+}
+
+// IsValid сообщает, является ли значение известным профилем
+func (p AudioProfile) IsValid() bool {
+	for _, profile := range AudioProfiles {
+		if p == profile {
+			return true
+		}
+	}
+	return false
+}
+
 // Дополнительные константы для статусов задач
 const (
 	JobStatusQueued  JobStatus = "queued"  // Задача добавлена в очередь
 	JobStatusPending JobStatus = "pending" // Задача ожидает обработки
 )
 
+// JobStatusFilter представляет отбор задач по статусу в списке задач пользователя
+type JobStatusFilter string
+
+// Константы для отборов задач по статусу
+const (
+	JobStatusFilterAll       JobStatusFilter = "all"    // Все задачи
+	JobStatusFilterActive    JobStatusFilter = "active" // Задачи, которые еще обрабатываются
+	JobStatusFilterCompleted JobStatusFilter = "done"   // Завершенные задачи
+	JobStatusFilterFailed    JobStatusFilter = "failed" // Задачи с ошибкой
+)
+
+// JobStatusFilters - отборы задач по статусу в порядке отображения
+var JobStatusFilters = []JobStatusFilter{
+	JobStatusFilterAll,
+	JobStatusFilterActive,
+	JobStatusFilterCompleted,
+	JobStatusFilterFailed,
+}
+
+// JobStage представляет собой запись об отдельном этапе обработки задачи
+type JobStage struct {
+	ID           int64          `json:"id" db:"id"`
+	JobID        int64          `json:"job_id" db:"job_id"`
+	Stage        JobStageName   `json:"stage" db:"stage"`
+	Status       JobStageStatus `json:"status" db:"status"`
+	Attempts     int            `json:"attempts" db:"attempts"`
+	ErrorMessage string         `json:"error_message" db:"error_message"`
+	// ExternalCalls - внешние вызовы последней попытки этапа (для разбора инцидентов)
+	ExternalCalls []ExternalCall `json:"external_calls" db:"external_calls"`
+	StartedAt     *time.Time     `json:"started_at" db:"started_at"`
+	FinishedAt    *time.Time     `json:"finished_at" db:"finished_at"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// Duration возвращает длительность последней попытки этапа
+func (s *JobStage) Duration() time.Duration {
+	if s.StartedAt == nil || s.FinishedAt == nil {
+		return 0
+	}
+	return s.FinishedAt.Sub(*s.StartedAt)
+}
+
+// ExternalCall представляет собой запись о вызове внешнего сервиса (Whisper, DeepSeek, Notion, Telegram)
+type ExternalCall struct {
+	Provider   string    `json:"provider"`
+	Operation  string    `json:"operation"`
+	Status     string    `json:"status"`
+	DurationMs int64     `json:"duration_ms"`
+	Retries    int       `json:"retries"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+}
+
+// JobStageName представляет собой название этапа обработки задачи
+type JobStageName string
+
+// Константы для этапов обработки задачи
+const (
+	JobStageTranscription JobStageName = "transcription" // Транскрибация аудио
+	JobStageSummarization JobStageName = "summarization" // Суммаризация текста
+	JobStageNotionSync    JobStageName = "notion_sync"   // Сохранение в Notion
+)
+
+// JobStageStatus представляет статус этапа обработки задачи
+type JobStageStatus string
+
+// Константы для статусов этапов
+const (
+	JobStageStatusRunning   JobStageStatus = "running"   // Этап выполняется
+	JobStageStatusSucceeded JobStageStatus = "succeeded" // Этап успешно завершен
+	JobStageStatusFailed    JobStageStatus = "failed"    // Этап завершен с ошибкой
+)
+
 // QueueJob представляет собой задачу для очереди Redis
 type QueueJob struct {
 	ID        int64     `json:"id"`        // ID задачи в базе данных
@@ -63,6 +330,7 @@ type QueueJob struct {
 	JobType   JobType   `json:"job_type"`   // Тип задачи
 	CreatedAt time.Time `json:"created_at"` // Время создания задачи
 	Payload   any       `json:"payload"`    // Дополнительные данные для задачи
+	Attempts  int       `json:"attempts"`   // Число аварийных завершений обработчика задачи
 }
 
 // JobType представляет собой тип задачи для очереди
@@ -77,4 +345,644 @@ const (
 	JobTypeNotionSync                  JobType = "notion_sync"                  // Синхронизация с Notion
 	JobTypeNotion                      JobType = "notion"                       // Интеграция с Notion
 	JobTypeNotification                JobType = "notification"                 // Уведомление о завершении задачи
-)
\ No newline at end of file
+	JobTypeText                        JobType = "text"                         // Суммаризация присланного текста
+	JobTypeEmbedding                   JobType = "embedding"                    // Индексация заметки для семантического поиска
+	JobTypePodcastEpisode              JobType = "podcast_episode"              // Обработка выпуска подкаста из подписки
+	JobTypeReadingLesson               JobType = "reading_lesson"               // Проверка чтения вслух по эталонному тексту
+	JobTypeVideo                       JobType = "video"                        // Обработка видео, присланного ссылкой
+	JobTypePodcastLink                 JobType = "podcast_link"                 // Обработка выпуска подкаста, присланного ссылкой
+	JobTypeFollowUp                    JobType = "follow_up"                    // Действие с результатом задачи по кнопке под уведомлением
+	JobTypeLive                        JobType = "live"                         // Суммаризация транскрипции завершенной live-сессии
+)
+
+// IsMediaLink сообщает, что задача обрабатывает видео или выпуск подкаста, присланный ссылкой: у такой задачи
+// есть название, а краткое содержание составляется по главам, если они есть в описании
+func (t JobType) IsMediaLink() bool {
+	return t == JobTypeVideo || t == JobTypePodcastLink
+}
+
+// UserStats представляет собой агрегированную статистику задач пользователя
+type UserStats struct {
+	TotalJobs          int64   `json:"total_jobs"`
+	JobsSince          int64   `json:"jobs_since"`
+	SecondsTranscribed float64 `json:"seconds_transcribed"`
+	PendingJobs        int64   `json:"pending_jobs"`
+}
+
+// UserUsage представляет собой итоги использования бота пользователем за все время
+type UserUsage struct {
+	TotalJobs          int64   `json:"total_jobs"`
+	CompletedJobs      int64   `json:"completed_jobs"`
+	FailedJobs         int64   `json:"failed_jobs"`
+	SecondsTranscribed float64 `json:"seconds_transcribed"` // Длительность записей, по которым получена транскрипция
+	SummaryTokens      int64   `json:"summary_tokens"`
+	SummaryCost        float64 `json:"summary_cost"` // Стоимость суммаризации, USD
+}
+
+// NotionReconcileResult представляет собой итоги сверки задач с Notion
+type NotionReconcileResult struct {
+	Checked   int `json:"checked"`   // Проверено задач
+	Recreated int `json:"recreated"` // Создано недостающих страниц
+	Cleared   int `json:"cleared"`   // Удалено ссылок на удаленные страницы
+	Failed    int `json:"failed"`    // Задач, которые не удалось сверить
+}
+
+// ShareLink представляет собой публичную ссылку только для чтения на результаты задачи
+type ShareLink struct {
+	ID        int64      `json:"id" db:"id"`
+	JobID     int64      `json:"job_id" db:"job_id"`
+	UserID    int64      `json:"user_id" db:"user_id"`
+	Token     string     `json:"token" db:"token"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsActive сообщает, можно ли использовать ссылку в указанный момент времени
+func (l *ShareLink) IsActive(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
+
+// TranscriptSegment представляет собой фрагмент транскрипции (сегмент Whisper или предложение) для поиска внутри задачи
+type TranscriptSegment struct {
+	ID           int64  `json:"id" db:"id"`
+	JobID        int64  `json:"job_id" db:"job_id"`
+	Position     int    `json:"position" db:"position"`
+	StartSeconds *int   `json:"start_seconds" db:"start_seconds"` // Начало сегмента, если транскрипция содержит метки времени
+	Text         string `json:"text" db:"text"`
+}
+
+// SpeakerSegment представляет собой фразу транскрипции с меткой времени и говорящим
+type SpeakerSegment struct {
+	Start   float64 `json:"start"`             // Начало фразы в секундах от начала записи
+	End     float64 `json:"end"`               // Конец фразы в секундах
+	Speaker int     `json:"speaker,omitempty"` // Номер говорящего начиная с 1; 0 - говорящий не определен
+	Text    string  `json:"text"`
+}
+
+// AudioChunk представляет собой фрагмент длинной записи, который распознается отдельно
+type AudioChunk struct {
+	Path   string  `json:"path"`
+	Offset float64 `json:"offset"` // Начало фрагмента в секундах от начала записи
+}
+
+// SpeakerTurn представляет собой промежуток записи, в котором звучит один говорящий
+type SpeakerTurn struct {
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Speaker int     `json:"speaker"` // Номер говорящего начиная с 1 в порядке первого появления в записи
+}
+
+// JobDocument представляет собой документ с результатом задачи для выгрузки в PDF
+type JobDocument struct {
+	Title      string               `json:"title"`
+	Language   string               `json:"language"` // Язык подписей документа
+	Metadata   []DocumentField      `json:"metadata"` // Сведения о задаче под заголовком в порядке показа
+	Summary    string               `json:"summary"`  // Краткое содержание в Markdown
+	Transcript []*TranscriptSegment `json:"transcript"`
+}
+
+// DocumentField представляет собой строку сведений о задаче в документе
+type DocumentField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// TranscriptExcerpt представляет собой найденный фрагмент транскрипции с соседними фрагментами
+type TranscriptExcerpt struct {
+	StartSeconds *int   `json:"start_seconds"`
+	Before       string `json:"before"`
+	Text         string `json:"text"`
+	After        string `json:"after"`
+}
+
+// NoteEmbedding представляет собой фрагмент заметки с его векторным представлением для семантического поиска
+type NoteEmbedding struct {
+	ID         int64     `json:"id" db:"id"`
+	JobID      int64     `json:"job_id" db:"job_id"`
+	UserID     int64     `json:"user_id" db:"user_id"`
+	Kind       string    `json:"kind" db:"kind"`
+	ChunkIndex int       `json:"chunk_index" db:"chunk_index"`
+	Content    string    `json:"content" db:"content"`
+	Embedding  []float32 `json:"-" db:"embedding"`
+	Distance   float64   `json:"distance" db:"-"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Виды фрагментов заметок
+const (
+	NoteEmbeddingKindSummary       = "summary"       // Краткое содержание
+	NoteEmbeddingKindTranscription = "transcription" // Фрагмент транскрипции
+)
+
+// ScheduledDeletion представляет собой запланированное удаление сообщения бота из чата
+type ScheduledDeletion struct {
+	ChatID    int64     `json:"chat_id"`
+	MessageID int       `json:"message_id"`
+	DeleteAt  time.Time `json:"delete_at"`
+}
+
+// PodcastFeed представляет собой подписку пользователя на RSS-ленту подкаста
+type PodcastFeed struct {
+	ID     int64  `json:"id" db:"id"`
+	UserID int64  `json:"user_id" db:"user_id"`
+	URL    string `json:"url" db:"url"`
+	Title  string `json:"title" db:"title"`
+	// NotionDatabaseID - отдельная база Notion для выпусков этого подкаста
+	NotionDatabaseID string     `json:"notion_database_id" db:"notion_database_id"`
+	LastCheckedAt    *time.Time `json:"last_checked_at" db:"last_checked_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+// PodcastEpisode представляет собой выпуск подкаста, уже учтенный для пользователя
+type PodcastEpisode struct {
+	ID          int64      `json:"id" db:"id"`
+	FeedID      int64      `json:"feed_id" db:"feed_id"`
+	UserID      int64      `json:"user_id" db:"user_id"`
+	GUID        string     `json:"guid" db:"guid"`
+	Title       string     `json:"title" db:"title"`
+	AudioURL    string     `json:"audio_url" db:"audio_url"`
+	AudioSize   int64      `json:"audio_size" db:"-"` // Размер аудио из enclosure ленты, если указан
+	PublishedAt *time.Time `json:"published_at" db:"published_at"`
+	JobID       *int64     `json:"job_id" db:"job_id"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// VideoInfo представляет собой видео или выпуск подкаста, присланный ссылкой: название, длительность и главы из описания
+type VideoInfo struct {
+	URL      string          `json:"url"`
+	Title    string          `json:"title"`
+	Duration float64         `json:"duration"` // Длительность в секундах, 0 - неизвестна
+	Chapters []*VideoChapter `json:"chapters"`
+}
+
+// VideoChapter представляет собой главу видео; краткое содержание задачи составляется по главам
+type VideoChapter struct {
+	JobID        int64  `json:"job_id" db:"job_id"`
+	Position     int    `json:"position" db:"position"`
+	Title        string `json:"title" db:"title"`
+	StartSeconds int    `json:"start_seconds" db:"start_seconds"`
+	EndSeconds   int    `json:"end_seconds" db:"end_seconds"`
+}
+
+// SyncResultStatus представляет результат выгрузки задачи в место синхронизации
+type SyncResultStatus string
+
+// Константы для результатов выгрузки
+const (
+	SyncResultStatusSucceeded SyncResultStatus = "succeeded" // Результаты выгружены
+	SyncResultStatusFailed    SyncResultStatus = "failed"    // Выгрузка завершилась ошибкой
+)
+
+// SyncResult представляет собой результат выгрузки задачи в одно из мест синхронизации (Notion, хранилище Obsidian, вебхук)
+type SyncResult struct {
+	JobID        int64            `json:"job_id" db:"job_id"`
+	Target       string           `json:"target" db:"target"`
+	Status       SyncResultStatus `json:"status" db:"status"`
+	ExternalID   string           `json:"external_id" db:"external_id"` // ID страницы, путь к файлу и т.п. в месте синхронизации
+	ErrorMessage string           `json:"error_message" db:"error_message"`
+	UpdatedAt    time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// CommandRequirement представляет предусловие команды или функции бота
+type CommandRequirement string
+
+// Константы для предусловий команд
+const (
+	CommandRequirementNotion CommandRequirement = "notion" // Настроена интеграция с Notion
+	CommandRequirementAdmin  CommandRequirement = "admin"  // Пользователь - администратор бота
+	CommandRequirementQuota  CommandRequirement = "quota"  // Не исчерпан дневной лимит задач
+	// Бот не на техническом обслуживании и принимает новые записи
+	CommandRequirementService CommandRequirement = "service"
+)
+
+// GuardPrompt представляет собой стандартный ответ на команду, предусловие которой не выполнено
+type GuardPrompt struct {
+	Text        string // Текст ответа в разметке Markdown
+	SetupLabel  string // Подпись кнопки настройки; пустая - ответ без кнопки
+	SetupAction string // Действие кнопки настройки (например, notion)
+}
+
+// Team представляет собой команду пользователей, краткие содержания которых ежедневно
+// публикуются одним дайджестом в канал или группу команды
+type Team struct {
+	ID           int64      `json:"id" db:"id"`
+	Name         string     `json:"name" db:"name"`
+	OwnerID      int64      `json:"owner_id" db:"owner_id"`
+	InviteCode   string     `json:"invite_code" db:"invite_code"`       // Код, по которому участники вступают в команду
+	ChannelID    int64      `json:"channel_id" db:"channel_id"`         // Чат канала или группы для дайджеста (0 - не задан)
+	DigestHour   int        `json:"digest_hour" db:"digest_hour"`       // Час публикации дайджеста (0-23)
+	LastDigestAt *time.Time `json:"last_digest_at" db:"last_digest_at"` // Время публикации последнего дайджеста
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// TeamMember представляет собой участника команды
+type TeamMember struct {
+	TeamID     int64     `json:"team_id" db:"team_id"`
+	UserID     int64     `json:"user_id" db:"user_id"`
+	TelegramID int64     `json:"telegram_id" db:"telegram_id"`
+	Username   string    `json:"username" db:"username"`
+	FirstName  string    `json:"first_name" db:"first_name"`
+	JoinedAt   time.Time `json:"joined_at" db:"joined_at"`
+}
+
+// TeamDigestEntry представляет собой завершенную задачу участника команды для дайджеста
+type TeamDigestEntry struct {
+	JobID        int64     `json:"job_id" db:"job_id"`
+	UserID       int64     `json:"user_id" db:"user_id"`
+	TelegramID   int64     `json:"telegram_id" db:"telegram_id"`
+	Username     string    `json:"username" db:"username"`
+	FirstName    string    `json:"first_name" db:"first_name"`
+	FileName     string    `json:"file_name" db:"file_name"`
+	Summary      string    `json:"summary" db:"summary"`
+	NotionPageID string    `json:"notion_page_id" db:"notion_page_id"`
+	CompletedAt  time.Time `json:"completed_at" db:"completed_at"`
+}
+
+// MessageButton представляет собой inline-кнопку под сообщением: нажатие кнопки с Data передается
+// обработчику нажатий, кнопка с URL открывает ссылку
+type MessageButton struct {
+	Text string
+	Data string
+	URL  string
+}
+
+// SummaryResult представляет собой результат суммаризации текста задачи
+type SummaryResult struct {
+	Summary          string  `json:"summary"`
+	Model            string  `json:"model"`             // Модель, которой получено краткое содержание
+	CostUSD          float64 `json:"cost_usd"`          // Стоимость всех попыток суммаризации, USD
+	PromptTokens     int     `json:"prompt_tokens"`     // Токены запросов всех попыток
+	CompletionTokens int     `json:"completion_tokens"` // Токены ответов всех попыток
+}
+
+// ReadingLesson представляет собой активный урок чтения вслух: голосовые сообщения пользователя
+// сравниваются с эталонным текстом
+type ReadingLesson struct {
+	UserID        int64     `json:"user_id" db:"user_id"`
+	ReferenceText string    `json:"reference_text" db:"reference_text"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// MeetingAgenda представляет собой повестку встречи. Повестка, присланная до записи, ожидает следующего
+// аудио пользователя (JobID == nil), а затем привязывается к его задаче: краткое содержание записи
+// составляется по пунктам повестки
+type MeetingAgenda struct {
+	ID        int64     `json:"id" db:"id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	JobID     *int64    `json:"job_id" db:"job_id"`
+	Text      string    `json:"text" db:"agenda"` // Пункты повестки, по одному в строке
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// SentimentMood представляет общее настроение встречи
+type SentimentMood string
+
+// Константы для настроения встречи
+const (
+	SentimentMoodPositive SentimentMood = "positive" // Доброжелательная, конструктивная встреча
+	SentimentMoodNeutral  SentimentMood = "neutral"  // Деловая встреча без выраженных эмоций
+	SentimentMoodNegative SentimentMood = "negative" // Напряженная встреча, преобладают недовольство и споры
+	SentimentMoodMixed    SentimentMood = "mixed"    // Настроение заметно менялось по ходу встречи
+)
+
+// JobSentiment представляет собой разбор настроения и тона встречи, составленный вместе с кратким содержанием
+type JobSentiment struct {
+	JobID         int64          `json:"job_id" db:"job_id"`
+	Mood          SentimentMood  `json:"mood" db:"mood"`
+	Tone          string         `json:"tone" db:"tone"`                     // Краткое описание тона обсуждения
+	TensionPoints []TensionPoint `json:"tension_points" db:"tension_points"` // Напряженные моменты встречи
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+}
+
+// ModelComparisonVote - итог голосования в сравнении моделей суммаризации
+type ModelComparisonVote string
+
+// Константы для итога голосования
+const (
+	ModelComparisonVoteA   ModelComparisonVote = "a"   // Лучше краткое содержание варианта A
+	ModelComparisonVoteB   ModelComparisonVote = "b"   // Лучше краткое содержание варианта B
+	ModelComparisonVoteTie ModelComparisonVote = "tie" // Варианты одинаково хороши
+)
+
+// ModelComparison представляет собой сравнение кратких содержаний задачи двумя моделями суммаризации.
+// Варианты показываются пользователю без названий моделей; Vote пуст, пока пользователь не проголосовал
+type ModelComparison struct {
+	ID        int64               `json:"id" db:"id"`
+	JobID     int64               `json:"job_id" db:"job_id"`
+	UserID    int64               `json:"user_id" db:"user_id"`
+	ModelA    string              `json:"model_a" db:"model_a"`
+	ModelB    string              `json:"model_b" db:"model_b"`
+	Vote      ModelComparisonVote `json:"vote" db:"vote"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+	VotedAt   *time.Time          `json:"voted_at" db:"voted_at"`
+}
+
+// ModelVoteStats представляет собой итоги голосований за модель суммаризации по всем сравнениям
+type ModelVoteStats struct {
+	Model       string `json:"model"`
+	Comparisons int    `json:"comparisons"` // Сравнения с голосом, в которых участвовала модель
+	Wins        int    `json:"wins"`
+	Ties        int    `json:"ties"`
+}
+
+// NotionRoute представляет собой правило выгрузки в Notion по теме записи: страницы задач, которые
+// классификатор отнес к теме Category, создаются в базе DatabaseID вместо основной базы пользователя
+type NotionRoute struct {
+	ID         int64     `json:"id" db:"id"`
+	UserID     int64     `json:"user_id" db:"user_id"`
+	Category   string    `json:"category" db:"category"`
+	DatabaseID string    `json:"database_id" db:"database_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// JobLogLine представляет собой запись лога приложения, относящуюся к задаче. Записи собираются
+// в индекс логов по атрибуту job_id, чтобы по обращению в поддержку не искать их в логах сервера
+type JobLogLine struct {
+	ID        int64             `json:"id" db:"id"`
+	JobID     int64             `json:"job_id" db:"job_id"`
+	Level     string            `json:"level" db:"level"`
+	Message   string            `json:"message" db:"message"`
+	Attrs     map[string]string `json:"attrs" db:"attrs"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
+
+// LiveSession представляет собой live-сессию пользователя (/live): короткие голосовые сообщения транскрибируются
+// по мере поступления, а транскрипция и текущее краткое содержание показываются в одном сообщении MessageID,
+// которое бот редактирует. После завершения сессии транскрипция обрабатывается как одна задача
+type LiveSession struct {
+	UserID     int64     `json:"user_id" db:"user_id"`
+	MessageID  int       `json:"message_id" db:"message_id"`
+	Transcript string    `json:"transcript" db:"transcript"`
+	Summary    string    `json:"summary" db:"summary"`
+	Segments   int       `json:"segments" db:"segments"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CallDirection - направление звонка, записанного VoIP провайдером
+type CallDirection string
+
+// Константы для направления звонка
+const (
+	CallDirectionIncoming CallDirection = "incoming"
+	CallDirectionOutgoing CallDirection = "outgoing"
+)
+
+// CallRecording представляет собой звонок, о котором сообщил вебхук VoIP провайдера, и задачу обработки его записи.
+// Провайдеры присылают данные о звонке и ссылку на запись разными уведомлениями, поэтому они собираются
+// в одной записи по ID звонка у провайдера; JobID равен 0, пока задача не создана
+type CallRecording struct {
+	ID        int64         `json:"id" db:"id"`
+	Provider  string        `json:"provider" db:"provider"`
+	CallID    string        `json:"call_id" db:"call_id"`
+	UserID    int64         `json:"user_id" db:"user_id"`
+	JobID     int64         `json:"job_id" db:"job_id"`
+	Direction CallDirection `json:"direction" db:"direction"`
+	Caller    string        `json:"caller" db:"caller"` // Номер звонящего
+	Callee    string        `json:"callee" db:"callee"` // Номер, на который звонили
+	StartedAt *time.Time    `json:"started_at" db:"started_at"`
+	Duration  int           `json:"duration" db:"duration"` // Длительность разговора в секундах
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
+
+// CallEvent представляет собой разобранное уведомление VoIP провайдера о звонке.
+// Если RecordingURL и RecordingID пусты, уведомление содержит только данные о звонке; RecordingID задан,
+// когда провайдер сообщает о готовой записи без ссылки и ссылку нужно запросить отдельно
+type CallEvent struct {
+	Call         CallRecording `json:"call"`
+	RecordingURL string        `json:"recording_url"`
+	RecordingID  string        `json:"recording_id"`
+}
+
+// HasRecording сообщает, что уведомление сообщает о готовой записи звонка
+func (e *CallEvent) HasRecording() bool {
+	return e.RecordingURL != "" || e.RecordingID != ""
+}
+
+// TensionPoint представляет собой напряженный момент встречи с подтверждающей цитатой из транскрипции
+type TensionPoint struct {
+	Topic string `json:"topic"`
+	Quote string `json:"quote"`
+}
+
+// JobAttachment представляет собой фотографию, приложенную к задаче, с распознанным на ней текстом.
+// Изображение доступно по токену, чтобы Notion мог показать его на странице задачи
+type JobAttachment struct {
+	ID          int64     `json:"id" db:"id"`
+	JobID       int64     `json:"job_id" db:"job_id"`
+	Token       string    `json:"token" db:"token"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Image       []byte    `json:"-" db:"image"`
+	OCRText     string    `json:"ocr_text" db:"ocr_text"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// WebhookEvent представляет собой событие задачи, о котором сообщает вебхук пользователя
+type WebhookEvent string
+
+const (
+	WebhookEventJobCompleted WebhookEvent = "job.completed" // Задача успешно завершена
+	WebhookEventJobFailed    WebhookEvent = "job.failed"    // Этап обработки задачи завершился ошибкой
+	WebhookEventTest         WebhookEvent = "test"          // Тестовая доставка по команде пользователя
+)
+
+// UserWebhook представляет собой вебхук пользователя (например, для сценариев n8n или Zapier)
+type UserWebhook struct {
+	UserID    int64             `json:"user_id" db:"user_id"`
+	URL       string            `json:"url" db:"url"`
+	Events    []WebhookEvent    `json:"events" db:"events"`   // Отправляемые события; пустой список - все события
+	Headers   map[string]string `json:"headers" db:"headers"` // Дополнительные заголовки запроса
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// Accepts сообщает, нужно ли отправлять событие на вебхук. Тестовая доставка отправляется всегда
+func (w *UserWebhook) Accepts(event WebhookEvent) bool {
+	if event == WebhookEventTest || len(w.Events) == 0 {
+		return true
+	}
+	for _, accepted := range w.Events {
+		if accepted == event {
+			return true
+		}
+	}
+	return false
+}
+
+// FailureCategory представляет собой категорию ошибки этапа обработки задачи
+type FailureCategory string
+
+const (
+	FailureCategoryTimeout      FailureCategory = "timeout"       // Истекло время ожидания
+	FailureCategoryRateLimit    FailureCategory = "rate_limit"    // Превышен лимит запросов внешнего сервиса
+	FailureCategoryAuth         FailureCategory = "auth"          // Ошибка авторизации или доступа
+	FailureCategoryNetwork      FailureCategory = "network"       // Сетевая ошибка
+	FailureCategoryInvalidInput FailureCategory = "invalid_input" // Некорректные входные данные
+	FailureCategoryBudget       FailureCategory = "budget"        // Превышен потолок стоимости
+	FailureCategoryOther        FailureCategory = "other"         // Прочие ошибки
+)
+
+// FailureRollup представляет собой число проваленных этапов за день по этапу, источнику ошибки и категории
+type FailureRollup struct {
+	Day      time.Time       `json:"day" db:"day"`
+	Stage    JobStageName    `json:"stage" db:"stage"`
+	Provider string          `json:"provider" db:"provider"` // Внешний сервис, ffmpeg или internal
+	Category FailureCategory `json:"category" db:"category"`
+	Failures int             `json:"failures" db:"failures"`
+}
+
+// UsageEventName представляет собой вид события использования бота
+type UsageEventName string
+
+const (
+	UsageEventStart        UsageEventName = "start"         // Команда /start
+	UsageEventCommand      UsageEventName = "command"       // Вызов команды; в Detail - имя команды
+	UsageEventJobCreated   UsageEventName = "job_created"   // Создание задачи; в Detail - источник (voice, audio, video, text, link)
+	UsageEventJobCompleted UsageEventName = "job_completed" // Завершение задачи; в Detail - тип задачи
+)
+
+// UsageEvent представляет собой обезличенное событие использования бота. Вместо идентификатора
+// пользователя хранится UserKey - хэш Telegram ID с секретной солью, по которому события одного
+// пользователя можно связать между собой, но нельзя найти самого пользователя
+type UsageEvent struct {
+	ID        int64          `json:"id" db:"id"`
+	UserKey   string         `json:"user_key" db:"user_key"`
+	Event     UsageEventName `json:"event" db:"event"`
+	Detail    string         `json:"detail" db:"detail"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// UsageEventCount представляет собой число событий и различных пользователей по виду события и уточнению
+type UsageEventCount struct {
+	Event  UsageEventName `json:"event"`
+	Detail string         `json:"detail"`
+	Events int            `json:"events"`
+	Users  int            `json:"users"`
+}
+
+// UsageFunnel представляет собой воронку от /start до первой завершенной задачи: сколько пользователей,
+// выполнивших /start за период, после этого создали задачу и дождались ее результата
+type UsageFunnel struct {
+	Started      int `json:"started"`
+	CreatedJob   int `json:"created_job"`
+	CompletedJob int `json:"completed_job"`
+}
+
+// AdminAuditAction представляет собой действие администратора, записываемое в журнал
+type AdminAuditAction string
+
+const (
+	AdminAuditActionUserView  AdminAuditAction = "user_view"  // Просмотр задач, ошибок и настроек пользователя
+	AdminAuditActionAuditView AdminAuditAction = "audit_view" // Просмотр журнала действий администраторов
+	AdminAuditActionBan       AdminAuditAction = "ban"        // Блокировка пользователя
+	AdminAuditActionUnban     AdminAuditAction = "unban"      // Снятие блокировки пользователя
+	AdminAuditActionBroadcast AdminAuditAction = "broadcast"  // Рассылка сообщения всем пользователям
+	// Включение и выключение режима технического обслуживания
+	AdminAuditActionMaintenanceOn  AdminAuditAction = "maintenance_on"
+	AdminAuditActionMaintenanceOff AdminAuditAction = "maintenance_off"
+)
+
+// Maintenance представляет собой режим технического обслуживания: бот не принимает новые записи,
+// а воркеры очереди не берут задачи, пока администратор не выключит режим
+type Maintenance struct {
+	Reason          string     `json:"reason"`
+	AdminTelegramID int64      `json:"admin_telegram_id"`
+	StartedAt       time.Time  `json:"started_at"`
+	ResumeAt        *time.Time `json:"resume_at"` // Ожидаемое время возобновления работы (nil - неизвестно)
+}
+
+// AdminAuditEntry представляет собой запись журнала действий администраторов с данными пользователей
+type AdminAuditEntry struct {
+	ID               int64            `json:"id" db:"id"`
+	AdminTelegramID  int64            `json:"admin_telegram_id" db:"admin_telegram_id"`
+	Action           AdminAuditAction `json:"action" db:"action"`
+	TargetTelegramID int64            `json:"target_telegram_id" db:"target_telegram_id"` // 0 - действие без конкретного пользователя
+	Details          string           `json:"details" db:"details"`
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`
+}
+
+// UserBaseStats представляет собой сводку по пользователям бота для команды /admin_stats
+type UserBaseStats struct {
+	Users    int64 `json:"users"`     // Пользователи в личных чатах
+	Groups   int64 `json:"groups"`    // Группы
+	NewSince int64 `json:"new_since"` // Пользователи, впервые написавшие боту за период
+	Banned   int64 `json:"banned"`
+	Admins   int64 `json:"admins"`
+}
+
+// JobTotals представляет собой сводку по задачам всех пользователей для команды /admin_stats
+type JobTotals struct {
+	TotalJobs          int64   `json:"total_jobs"`
+	JobsSince          int64   `json:"jobs_since"`
+	CompletedSince     int64   `json:"completed_since"`
+	FailedSince        int64   `json:"failed_since"`
+	PendingJobs        int64   `json:"pending_jobs"`
+	ActiveUsersSince   int64   `json:"active_users_since"`  // Пользователи и группы, создавшие задачи за период
+	SecondsTranscribed float64 `json:"seconds_transcribed"` // Длительность записей, обработанных за период
+	SummaryCostSince   float64 `json:"summary_cost_since"`  // Стоимость суммаризации за период, USD
+}
+
+// UserInspection представляет собой сведения о пользователе для разбора обращений в поддержку
+type UserInspection struct {
+	User          *User        `json:"user"`
+	Webhook       *UserWebhook `json:"webhook"`        // Вебхук пользователя или nil
+	RecentJobs    []*Job       `json:"recent_jobs"`    // Последние задачи, начиная с самых новых
+	LastFailure   *Job         `json:"last_failure"`   // Последняя задача с ошибкой среди RecentJobs или nil
+	FailureStages []*JobStage  `json:"failure_stages"` // Этапы последней задачи с ошибкой
+}
+
+// UserConfigVersion - версия формата файла настроек пользователя
+const UserConfigVersion = 1
+
+// UserConfig представляет собой файл настроек пользователя для переноса между экземплярами бота
+// и восстановления после сброса аккаунта. Токен Notion в файл не попадает: интеграция подключается заново
+type UserConfig struct {
+	Version              int                  `json:"version"`
+	ExportedAt           time.Time            `json:"exported_at"`
+	ResultRetentionHours int                  `json:"result_retention_hours"`
+	SummaryLanguage      string               `json:"summary_language,omitempty"` // Пустая строка - язык записи
+	Transcription        TranscriptionOptions `json:"transcription"`
+	Notion               *UserConfigNotion    `json:"notion,omitempty"`
+	Webhook              *UserConfigWebhook   `json:"webhook,omitempty"`
+	Podcasts             []string             `json:"podcasts,omitempty"` // Адреса RSS-лент подкастов
+	Settings             *UserConfigSettings  `json:"settings,omitempty"` // Настройки /settings; в старых файлах их нет
+}
+
+// UserConfigSettings представляет собой настройки обработки записей (/settings) в файле настроек пользователя.
+// Время последнего разбора задач с ошибками не переносится
+type UserConfigSettings struct {
+	TranscriptionLanguage  string       `json:"transcription_language,omitempty"`
+	SummaryStyle           SummaryStyle `json:"summary_style,omitempty"`
+	SummaryStyleRemembered bool         `json:"summary_style_remembered"`
+	NotionAutoSync         bool         `json:"notion_auto_sync"`
+	FailedJobsCleanup      bool         `json:"failed_jobs_cleanup"`
+	UsageAnalyticsOptOut   bool         `json:"usage_analytics_opt_out"`
+	InterfaceLanguage      string       `json:"interface_language,omitempty"`
+	SentimentAnalysis      bool         `json:"sentiment_analysis"`
+	NotionTranscript       bool         `json:"notion_transcript"`
+	NotionSummaryOnly      bool         `json:"notion_summary_only"`
+	NotionAudioStats       bool         `json:"notion_audio_stats"`
+	NotionUsageCost        bool         `json:"notion_usage_cost"`
+}
+
+// UserConfigNotion представляет собой настройки Notion в файле настроек пользователя
+type UserConfigNotion struct {
+	DatabaseID string `json:"database_id,omitempty"`
+	HubPageID  string `json:"hub_page_id,omitempty"`
+}
+
+// UserConfigWebhook представляет собой вебхук в файле настроек пользователя
+type UserConfigWebhook struct {
+	URL     string            `json:"url"`
+	Events  []WebhookEvent    `json:"events,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// UserConfigImportResult представляет собой итоги загрузки файла настроек пользователя
+type UserConfigImportResult struct {
+	PodcastsAdded   int      `json:"podcasts_added"`   // Новых подписок на подкасты
+	PodcastsSkipped int      `json:"podcasts_skipped"` // Подписок, которые уже были
+	PodcastsFailed  []string `json:"podcasts_failed"`  // Ленты, на которые не удалось подписаться
+	NotionConnected bool     `json:"notion_connected"` // Подключен ли у пользователя Notion
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/112Alex/project_obsidian/internal/domain/entity"
 )
@@ -12,28 +13,129 @@ type UserRepository interface {
 	Create(ctx context.Context, user *entity.User) error
 	// GetByTelegramID возвращает пользователя по его Telegram ID
 	GetByTelegramID(ctx context.Context, telegramID int64) (*entity.User, error)
+	// GetByID возвращает пользователя по его ID
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
 	// Update обновляет информацию о пользователе
 	Update(ctx context.Context, user *entity.User) error
+	// Delete удаляет пользователя вместе с его задачами, настройками и подписками
+	Delete(ctx context.Context, user *entity.User) error
+	// SetAdmins отмечает администраторами пользователей с указанными Telegram ID и снимает отметку с остальных
+	SetAdmins(ctx context.Context, telegramIDs []int64) error
+	// SetBan блокирует пользователя с причиной reason или снимает блокировку, если bannedAt - nil.
+	// Возвращает false, если пользователя нет
+	SetBan(ctx context.Context, telegramID int64, bannedAt *time.Time, reason string) (bool, error)
+	// IsBanned сообщает, что пользователь с указанным Telegram ID заблокирован
+	IsBanned(ctx context.Context, telegramID int64) (bool, error)
+	// GetBroadcastRecipients возвращает незаблокированных пользователей личных чатов с ID больше afterID
+	// по возрастанию ID; у пользователей заполнены только ID, Telegram ID и язык
+	GetBroadcastRecipients(ctx context.Context, afterID int64, limit int) ([]*entity.User, error)
+	// GetBaseStats возвращает сводку по пользователям; новыми считаются зарегистрированные начиная с since
+	GetBaseStats(ctx context.Context, since time.Time) (*entity.UserBaseStats, error)
 }
 
 // JobRepository определяет интерфейс для работы с задачами
 type JobRepository interface {
 	// Create создает новую задачу
 	Create(ctx context.Context, job *entity.Job) error
+	// CreateBatch создает задачи одним пакетом запросов в транзакции и заполняет их ID
+	CreateBatch(ctx context.Context, jobs []*entity.Job) error
+	// Import загружает задачи через COPY без получения их ID и возвращает число загруженных задач.
+	// В отличие от CreateBatch, статус и время создания задач сохраняются
+	Import(ctx context.Context, jobs []*entity.Job) (int64, error)
 	// GetByID возвращает задачу по её ID
 	GetByID(ctx context.Context, id int64) (*entity.Job, error)
 	// GetByUserID возвращает задачи пользователя
 	GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*entity.Job, error)
+	// GetByUserIDAndStatus возвращает задачи пользователя, подходящие под отбор по статусу, от новых к старым
+	GetByUserIDAndStatus(ctx context.Context, userID int64, filter entity.JobStatusFilter, limit, offset int) ([]*entity.Job, error)
 	// Update обновляет информацию о задаче
 	Update(ctx context.Context, job *entity.Job) error
 	// UpdateStatus обновляет статус задачи
 	UpdateStatus(ctx context.Context, id int64, status entity.JobStatus, errorMessage string) error
+	// MarkFailed переводит задачу в статус ошибки и запоминает этап, на котором она произошла
+	MarkFailed(ctx context.Context, id int64, stage entity.JobStageName, errorMessage string) error
 	// SetTranscription устанавливает транскрипцию для задачи
 	SetTranscription(ctx context.Context, id int64, transcription string) error
 	// SetSummary устанавливает суммаризацию для задачи
 	SetSummary(ctx context.Context, id int64, summary string) error
+	// SetSummaryUsage сохраняет модель, стоимость и число токенов суммаризации задачи
+	SetSummaryUsage(ctx context.Context, id int64, model string, cost float64, tokens int) error
 	// SetNotionIDs устанавливает ID страницы и базы данных Notion для задачи
 	SetNotionIDs(ctx context.Context, id int64, pageID, databaseID string) error
+	// SetNotionUpload сохраняет ход выгрузки содержимого на страницу Notion; пустой pageID сбрасывает его
+	SetNotionUpload(ctx context.Context, id int64, pageID string, cursor int) error
+	// SetAudioProfile устанавливает профиль предобработки аудио, если обработка задачи еще не началась
+	SetAudioProfile(ctx context.Context, id int64, profile entity.AudioProfile) (bool, error)
+	// SetSummaryStyle устанавливает стиль краткого содержания задачи, если ее транскрибация еще не завершена.
+	// Возвращает false, если суммаризация уже поставлена в очередь
+	SetSummaryStyle(ctx context.Context, id int64, style entity.SummaryStyle) (bool, error)
+	// SetProcessedAudio сохраняет подготовленный к распознаванию WAV задачи и профиль, с которым он получен,
+	// и отсчитывает срок его хранения заново
+	SetProcessedAudio(ctx context.Context, id int64, path string, profile entity.AudioProfile) error
+	// GetProcessedAudioBefore возвращает задачи, подготовленные WAV которых сохранены или использованы до before
+	GetProcessedAudioBefore(ctx context.Context, before time.Time, limit int) ([]*entity.Job, error)
+	// ClearProcessedAudio забывает подготовленный WAV задачи, если он не использовался после before.
+	// Возвращает false, если WAV использован повторно и удалять его рано
+	ClearProcessedAudio(ctx context.Context, id int64, before time.Time) (bool, error)
+	// SetTranscriptSignature сохраняет сигнатуру транскрипции задачи для поиска повторно присланных записей
+	SetTranscriptSignature(ctx context.Context, id int64, signature string) error
+	// GetTranscriptSignatures возвращает ID и сигнатуры транскрипций задач пользователя, созданных после since,
+	// кроме excludeID и задач, которые сами помечены как повтор, начиная с самых новых
+	GetTranscriptSignatures(ctx context.Context, userID, excludeID int64, since time.Time, limit int) ([]*entity.Job, error)
+	// SetSpeakerSegments сохраняет фразы транскрипции задачи с говорящими; пустой список забывает их
+	SetSpeakerSegments(ctx context.Context, id int64, segments []entity.SpeakerSegment) error
+	// SetDuplicateOf помечает задачу как повтор задачи originalID с долей совпадения similarity; originalID 0 снимает пометку
+	SetDuplicateOf(ctx context.Context, id, originalID int64, similarity float64) error
+	// GetForNotionReconcile возвращает завершенные задачи пользователей с Notion для сверки, постранично по ID
+	GetForNotionReconcile(ctx context.Context, afterID int64, limit int) ([]*entity.Job, error)
+	// ClearNotionPage удаляет ссылку на удаленную страницу Notion и помечает задачу отвязанной
+	ClearNotionPage(ctx context.Context, id int64) error
+	// GetUserStats возвращает агрегированную статистику задач пользователя начиная с указанного момента
+	GetUserStats(ctx context.Context, userID int64, since time.Time) (*entity.UserStats, error)
+	// GetUserUsage возвращает итоги использования бота пользователем за все время
+	GetUserUsage(ctx context.Context, userID int64) (*entity.UserUsage, error)
+	// GetTotals возвращает сводку по задачам всех пользователей; итоги за период считаются начиная с since
+	GetTotals(ctx context.Context, since time.Time) (*entity.JobTotals, error)
+	// GetForKeepalive возвращает незавершенные задачи, созданные в промежутке [createdAfter, createdBefore),
+	// о ходе которых пользователю не сообщали после keepaliveBefore
+	GetForKeepalive(ctx context.Context, createdAfter, createdBefore, keepaliveBefore time.Time, limit int) ([]*entity.Job, error)
+	// ClaimKeepalive отмечает время сообщения о ходе задачи, если о нем не сообщали после keepaliveBefore.
+	// Возвращает false, если сообщение уже отправил другой экземпляр приложения
+	ClaimKeepalive(ctx context.Context, id int64, keepaliveBefore, now time.Time) (bool, error)
+	// SetKeepaliveMessage сохраняет ID сообщения о ходе задачи
+	SetKeepaliveMessage(ctx context.Context, id int64, messageID int) error
+	// SetProgressMessage сохраняет ID сообщения о ходе обработки задачи, если сохранен ID previousID.
+	// Возвращает false, если сообщение уже заменил другой обработчик
+	SetProgressMessage(ctx context.Context, id int64, previousID, messageID int) (bool, error)
+	// GetFailedForCleanup возвращает задачи пользователя с ошибкой, обновленные после since, вместе со временем
+	// их перезапуска ежедневным разбором ошибок
+	GetFailedForCleanup(ctx context.Context, userID int64, since time.Time, limit int) ([]*entity.Job, error)
+	// MarkCleanupRetried отмечает перезапуск задачи разбором ошибок, если она еще не перезапускалась.
+	// Возвращает false, если задача уже перезапущена
+	MarkCleanupRetried(ctx context.Context, id int64, now time.Time) (bool, error)
+	// GetLatestByFileUniqueID возвращает последнюю задачу пользователя без ошибки по постоянному идентификатору
+	// файла в Telegram или nil, если такой задачи нет
+	GetLatestByFileUniqueID(ctx context.Context, userID int64, fileUniqueID string) (*entity.Job, error)
+	// GetLatestBySourceMessage возвращает последнюю задачу пользователя без ошибки, созданную по сообщению
+	// sourceMessageID, или nil, если такой задачи нет
+	GetLatestBySourceMessage(ctx context.Context, userID int64, sourceMessageID int) (*entity.Job, error)
+	// SetInstructionsBySourceMessage заменяет указания к конспекту задач пользователя, созданных по сообщению
+	// sourceMessageID, краткое содержание которых еще не составлено. Возвращает false, если таких задач нет
+	SetInstructionsBySourceMessage(ctx context.Context, userID int64, sourceMessageID int, instructions string) (bool, error)
+	// Delete удаляет задачу вместе с ее этапами, публичными ссылками и другими связанными записями
+	Delete(ctx context.Context, id int64) error
+}
+
+// JobStageRepository определяет интерфейс для работы с этапами обработки задач
+type JobStageRepository interface {
+	// Start отмечает начало очередной попытки этапа
+	Start(ctx context.Context, jobID int64, stage entity.JobStageName) error
+	// Finish отмечает завершение этапа с указанным статусом и сохраняет его внешние вызовы
+	Finish(ctx context.Context, jobID int64, stage entity.JobStageName, status entity.JobStageStatus, errorMessage string, calls []entity.ExternalCall) error
+	// GetByJobID возвращает этапы задачи в порядке их начала
+	GetByJobID(ctx context.Context, jobID int64) ([]*entity.JobStage, error)
+	// GetFailed возвращает проваленные этапы, завершенные в промежутке [since, until)
+	GetFailed(ctx context.Context, since, until time.Time) ([]*entity.JobStage, error)
 }
 
 // QueueRepository определяет интерфейс для работы с очередью задач
@@ -45,3 +147,287 @@ type QueueRepository interface {
 	// Size возвращает размер очереди
 	Size(ctx context.Context, queueName string) (int64, error)
 }
+
+// ShareLinkRepository определяет интерфейс для работы с публичными ссылками на задачи
+type ShareLinkRepository interface {
+	// Create создает новую ссылку
+	Create(ctx context.Context, link *entity.ShareLink) error
+	// GetByToken возвращает ссылку по её токену
+	GetByToken(ctx context.Context, token string) (*entity.ShareLink, error)
+	// RevokeByJobID отзывает все активные ссылки задачи и возвращает их количество
+	RevokeByJobID(ctx context.Context, jobID int64) (int64, error)
+}
+
+// EmbeddingRepository определяет интерфейс для работы с эмбеддингами заметок
+type EmbeddingRepository interface {
+	// ReplaceForJob заменяет все эмбеддинги задачи переданными
+	ReplaceForJob(ctx context.Context, jobID int64, embeddings []*entity.NoteEmbedding) error
+	// SearchSimilar возвращает ближайшие к вектору фрагменты заметок пользователя
+	SearchSimilar(ctx context.Context, userID int64, embedding []float32, limit int) ([]*entity.NoteEmbedding, error)
+}
+
+// TranscriptSegmentRepository определяет интерфейс для работы с фрагментами транскрипций
+type TranscriptSegmentRepository interface {
+	// ReplaceForJob заменяет все фрагменты транскрипции задачи переданными
+	ReplaceForJob(ctx context.Context, jobID int64, segments []*entity.TranscriptSegment) error
+	// CountByJobID возвращает число фрагментов транскрипции задачи
+	CountByJobID(ctx context.Context, jobID int64) (int, error)
+	// Search возвращает фрагменты транскрипции задачи, содержащие фразу, вместе с соседними фрагментами
+	Search(ctx context.Context, jobID int64, phrase string, limit int) ([]*entity.TranscriptExcerpt, error)
+	// GetRange возвращает фрагменты транскрипции задачи, начинающиеся в промежутке [fromSeconds, toSeconds)
+	GetRange(ctx context.Context, jobID int64, fromSeconds, toSeconds int) ([]*entity.TranscriptSegment, error)
+	// LastStartSeconds возвращает начало последнего фрагмента с меткой времени или nil, если меток времени нет
+	LastStartSeconds(ctx context.Context, jobID int64) (*int, error)
+}
+
+// VideoChapterRepository определяет интерфейс для работы с главами видео
+type VideoChapterRepository interface {
+	// ReplaceForJob заменяет все главы видео задачи переданными
+	ReplaceForJob(ctx context.Context, jobID int64, chapters []*entity.VideoChapter) error
+	// GetByJobID возвращает главы видео задачи по порядку; для задач без глав - пустой список
+	GetByJobID(ctx context.Context, jobID int64) ([]*entity.VideoChapter, error)
+}
+
+// JobSentimentRepository определяет интерфейс для работы с разборами настроения встреч
+type JobSentimentRepository interface {
+	// Save сохраняет разбор настроения задачи, заменяя прежний
+	Save(ctx context.Context, sentiment *entity.JobSentiment) error
+	// GetByJobID возвращает разбор настроения задачи или nil
+	GetByJobID(ctx context.Context, jobID int64) (*entity.JobSentiment, error)
+}
+
+// MessageDeletionRepository определяет интерфейс для работы с запланированными удалениями сообщений
+type MessageDeletionRepository interface {
+	// Schedule планирует удаление сообщения
+	Schedule(ctx context.Context, deletion entity.ScheduledDeletion) error
+	// PopDue извлекает удаления, время которых наступило к указанному моменту
+	PopDue(ctx context.Context, now time.Time) ([]entity.ScheduledDeletion, error)
+}
+
+// NotionOAuthStateRepository определяет интерфейс для хранения параметров state входа в Notion через OAuth
+type NotionOAuthStateRepository interface {
+	// Save сохраняет state, выданный пользователю, на время ttl
+	Save(ctx context.Context, state string, userID int64, ttl time.Duration) error
+	// Pop возвращает ID пользователя, которому выдан state, и удаляет state, чтобы его нельзя было использовать
+	// повторно. Для неизвестного или истекшего state возвращается 0
+	Pop(ctx context.Context, state string) (int64, error)
+}
+
+// MaintenanceRepository определяет интерфейс для хранения режима технического обслуживания,
+// общего для всех экземпляров приложения
+type MaintenanceRepository interface {
+	// Get возвращает включенный режим обслуживания или nil, если он выключен
+	Get(ctx context.Context) (*entity.Maintenance, error)
+	// Save включает режим обслуживания или обновляет его параметры
+	Save(ctx context.Context, maintenance *entity.Maintenance) error
+	// Clear выключает режим обслуживания
+	Clear(ctx context.Context) error
+}
+
+// RateLimitRepository определяет интерфейс для учета событий при ограничении их частоты в скользящем окне
+type RateLimitRepository interface {
+	// Take учитывает событие key и возвращает 0, если за последние window событий key было меньше limit.
+	// Иначе событие не учитывается, а возвращается время, через которое можно будет повторить попытку
+	Take(ctx context.Context, key string, limit int, window time.Duration) (time.Duration, error)
+}
+
+// ModelComparisonRepository определяет интерфейс для работы со сравнениями моделей суммаризации
+type ModelComparisonRepository interface {
+	// Create создает сравнение без голоса
+	Create(ctx context.Context, comparison *entity.ModelComparison) error
+	// GetByID возвращает сравнение или nil, если его нет
+	GetByID(ctx context.Context, id int64) (*entity.ModelComparison, error)
+	// Vote сохраняет голос в сравнении. Возвращает false, если голос в нем уже отдан
+	Vote(ctx context.Context, id int64, vote entity.ModelComparisonVote, now time.Time) (bool, error)
+	// GetModelStats возвращает итоги голосований по моделям, начиная с модели с наибольшим числом побед
+	GetModelStats(ctx context.Context) ([]*entity.ModelVoteStats, error)
+}
+
+// CallRecordingRepository определяет интерфейс для работы со звонками, записанными VoIP провайдерами
+type CallRecordingRepository interface {
+	// Create создает звонок
+	Create(ctx context.Context, call *entity.CallRecording) error
+	// GetByCallID возвращает звонок по ID у провайдера или nil, если его нет
+	GetByCallID(ctx context.Context, provider, callID string) (*entity.CallRecording, error)
+	// GetByJobID возвращает звонок, по записи которого создана задача, или nil, если задача не из звонка
+	GetByJobID(ctx context.Context, jobID int64) (*entity.CallRecording, error)
+	// UpdateMetadata дополняет данные о звонке непустыми полями call
+	UpdateMetadata(ctx context.Context, call *entity.CallRecording) error
+	// ClaimRecording отмечает получение записи звонка. Возвращает false, если запись уже получена:
+	// провайдеры повторяют уведомления, а задача по записи должна создаваться один раз
+	ClaimRecording(ctx context.Context, id int64, now time.Time) (bool, error)
+	// SetJob связывает звонок с задачей обработки записи
+	SetJob(ctx context.Context, id, jobID int64) error
+}
+
+// NotionRouteRepository определяет интерфейс для работы с правилами выгрузки в Notion по темам записей
+type NotionRouteRepository interface {
+	// Create создает правило
+	Create(ctx context.Context, route *entity.NotionRoute) error
+	// GetByUserID возвращает правила пользователя в порядке создания
+	GetByUserID(ctx context.Context, userID int64) ([]*entity.NotionRoute, error)
+	// Delete удаляет правило пользователя. Возвращает false, если такого правила нет
+	Delete(ctx context.Context, userID, id int64) (bool, error)
+}
+
+// JobLogRepository определяет интерфейс для работы с индексом логов задач
+type JobLogRepository interface {
+	// Create добавляет записи лога; записи удаленных задач пропускаются
+	Create(ctx context.Context, lines []*entity.JobLogLine) error
+	// GetByJobID возвращает последние limit записей задачи в порядке их появления
+	GetByJobID(ctx context.Context, jobID int64, limit int) ([]*entity.JobLogLine, error)
+	// DeleteBefore удаляет записи, сделанные раньше before, и возвращает их число
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// LiveSessionRepository определяет интерфейс для работы с live-сессиями
+type LiveSessionRepository interface {
+	// Set сохраняет live-сессию пользователя, заменяя предыдущую
+	Set(ctx context.Context, session *entity.LiveSession) error
+	// GetByUserID возвращает активную live-сессию пользователя или nil
+	GetByUserID(ctx context.Context, userID int64) (*entity.LiveSession, error)
+	// Delete завершает live-сессию пользователя и сообщает, была ли она активна
+	Delete(ctx context.Context, userID int64) (bool, error)
+}
+
+// PodcastRepository определяет интерфейс для работы с подписками на подкасты
+type PodcastRepository interface {
+	// CreateFeed создает подписку на ленту
+	CreateFeed(ctx context.Context, feed *entity.PodcastFeed) error
+	// GetFeedsByUserID возвращает подписки пользователя
+	GetFeedsByUserID(ctx context.Context, userID int64) ([]*entity.PodcastFeed, error)
+	// GetAllFeeds возвращает подписки всех пользователей
+	GetAllFeeds(ctx context.Context) ([]*entity.PodcastFeed, error)
+	// DeleteFeed удаляет подписку пользователя и сообщает, была ли она найдена
+	DeleteFeed(ctx context.Context, userID, feedID int64) (bool, error)
+	// SetFeedChecked отмечает время последней проверки ленты
+	SetFeedChecked(ctx context.Context, feedID int64, checkedAt time.Time) error
+	// AddEpisode учитывает выпуск для пользователя и возвращает false, если он уже был учтен
+	AddEpisode(ctx context.Context, episode *entity.PodcastEpisode) (bool, error)
+	// AddEpisodes учитывает выпуски одним пакетом запросов и возвращает число новых выпусков.
+	// ID заполняется только у новых выпусков
+	AddEpisodes(ctx context.Context, episodes []*entity.PodcastEpisode) (int, error)
+	// SetEpisodeJob связывает выпуск с задачей обработки
+	SetEpisodeJob(ctx context.Context, episodeID, jobID int64) error
+}
+
+// SyncResultRepository определяет интерфейс для работы с результатами выгрузки задач в места синхронизации
+type SyncResultRepository interface {
+	// Save сохраняет результат выгрузки задачи, заменяя предыдущий результат для того же места
+	Save(ctx context.Context, result *entity.SyncResult) error
+	// GetByJobID возвращает результаты выгрузки задачи
+	GetByJobID(ctx context.Context, jobID int64) ([]*entity.SyncResult, error)
+}
+
+// TeamRepository определяет интерфейс для работы с командами и их участниками
+type TeamRepository interface {
+	// Create создает команду и добавляет в нее владельца
+	Create(ctx context.Context, team *entity.Team) error
+	// GetByUserID возвращает команду, в которой состоит пользователь, или nil
+	GetByUserID(ctx context.Context, userID int64) (*entity.Team, error)
+	// GetByInviteCode возвращает команду по коду приглашения
+	GetByInviteCode(ctx context.Context, code string) (*entity.Team, error)
+	// GetWithChannel возвращает команды, для которых задан канал дайджеста
+	GetWithChannel(ctx context.Context) ([]*entity.Team, error)
+	// Delete удаляет команду вместе со списком участников
+	Delete(ctx context.Context, teamID int64) error
+	// AddMember добавляет пользователя в команду
+	AddMember(ctx context.Context, teamID, userID int64) error
+	// RemoveMember исключает пользователя из команды и сообщает, состоял ли он в ней
+	RemoveMember(ctx context.Context, teamID, userID int64) (bool, error)
+	// GetMembers возвращает участников команды в порядке вступления
+	GetMembers(ctx context.Context, teamID int64) ([]*entity.TeamMember, error)
+	// SetChannel задает канал для публикации дайджеста (0 - отключить публикацию)
+	SetChannel(ctx context.Context, teamID, channelID int64) error
+	// SetDigestHour задает час публикации дайджеста
+	SetDigestHour(ctx context.Context, teamID int64, hour int) error
+	// SetDigestSent отмечает время публикации дайджеста
+	SetDigestSent(ctx context.Context, teamID int64, sentAt time.Time) error
+	// GetDigestEntries возвращает задачи участников команды, завершенные в промежутке [since, until)
+	GetDigestEntries(ctx context.Context, teamID int64, since, until time.Time) ([]*entity.TeamDigestEntry, error)
+}
+
+// ReadingLessonRepository определяет интерфейс для работы с уроками чтения вслух
+type ReadingLessonRepository interface {
+	// Set начинает урок пользователя, заменяя предыдущий
+	Set(ctx context.Context, lesson *entity.ReadingLesson) error
+	// GetByUserID возвращает активный урок пользователя или nil
+	GetByUserID(ctx context.Context, userID int64) (*entity.ReadingLesson, error)
+	// Delete завершает урок пользователя и сообщает, был ли он активен
+	Delete(ctx context.Context, userID int64) (bool, error)
+}
+
+// UserSettingsRepository определяет интерфейс для работы с настройками пользователей
+type UserSettingsRepository interface {
+	// GetByUserID возвращает настройки пользователя или nil, если он их не менял
+	GetByUserID(ctx context.Context, userID int64) (*entity.UserSettings, error)
+	// Upsert сохраняет настройки пользователя
+	Upsert(ctx context.Context, settings *entity.UserSettings) error
+	// GetForFailedJobsCleanup возвращает настройки пользователей с включенным разбором задач с ошибками,
+	// у которых разбора не было после before
+	GetForFailedJobsCleanup(ctx context.Context, before time.Time, limit int) ([]*entity.UserSettings, error)
+	// ClaimFailedJobsCleanup отмечает время разбора задач с ошибками пользователя, если разбора не было после before.
+	// Возвращает false, если разбор уже выполнил другой экземпляр приложения
+	ClaimFailedJobsCleanup(ctx context.Context, userID int64, before, now time.Time) (bool, error)
+}
+
+// MeetingAgendaRepository определяет интерфейс для работы с повестками встреч
+type MeetingAgendaRepository interface {
+	// SetPending сохраняет повестку, ожидающую записи встречи, заменяя прежнюю ожидающую повестку пользователя
+	SetPending(ctx context.Context, agenda *entity.MeetingAgenda) error
+	// GetPending возвращает ожидающую повестку пользователя, сохраненную после createdAfter, или nil
+	GetPending(ctx context.Context, userID int64, createdAfter time.Time) (*entity.MeetingAgenda, error)
+	// DeletePending удаляет ожидающую повестку пользователя и сообщает, была ли она
+	DeletePending(ctx context.Context, userID int64) (bool, error)
+	// AttachPending привязывает к задаче ожидающую повестку пользователя, сохраненную после createdAfter,
+	// и возвращает ее или nil, если такой повестки нет
+	AttachPending(ctx context.Context, userID, jobID int64, createdAfter time.Time) (*entity.MeetingAgenda, error)
+	// GetByJobID возвращает повестку задачи или nil
+	GetByJobID(ctx context.Context, jobID int64) (*entity.MeetingAgenda, error)
+}
+
+// JobAttachmentRepository определяет интерфейс для работы с фотографиями, приложенными к задачам
+type JobAttachmentRepository interface {
+	// Create сохраняет фотографию задачи
+	Create(ctx context.Context, attachment *entity.JobAttachment) error
+	// GetByToken возвращает фотографию по ее токену
+	GetByToken(ctx context.Context, token string) (*entity.JobAttachment, error)
+}
+
+// UserWebhookRepository определяет интерфейс для работы с вебхуками пользователей
+type UserWebhookRepository interface {
+	// Save создает или обновляет вебхук пользователя
+	Save(ctx context.Context, webhook *entity.UserWebhook) error
+	// GetByUserID возвращает вебхук пользователя или nil
+	GetByUserID(ctx context.Context, userID int64) (*entity.UserWebhook, error)
+	// Delete удаляет вебхук пользователя и сообщает, был ли он настроен
+	Delete(ctx context.Context, userID int64) (bool, error)
+}
+
+// FailureRollupRepository определяет интерфейс для работы с дневными сводками ошибок
+type FailureRollupRepository interface {
+	// ReplaceDay заменяет сводки за день переданными
+	ReplaceDay(ctx context.Context, day time.Time, rollups []*entity.FailureRollup) error
+	// GetSince возвращает сводки начиная с дня since в порядке дней
+	GetSince(ctx context.Context, since time.Time) ([]*entity.FailureRollup, error)
+}
+
+// UsageEventRepository определяет интерфейс для работы с обезличенными событиями использования бота
+type UsageEventRepository interface {
+	// Create добавляет событие
+	Create(ctx context.Context, event *entity.UsageEvent) error
+	// CountSince возвращает число событий и пользователей по виду события и уточнению начиная с since
+	CountSince(ctx context.Context, since time.Time) ([]*entity.UsageEventCount, error)
+	// GetFunnelSince возвращает воронку пользователей, выполнивших /start начиная с since
+	GetFunnelSince(ctx context.Context, since time.Time) (*entity.UsageFunnel, error)
+	// DeleteByUserKey удаляет все события пользователя, например после отказа от аналитики
+	DeleteByUserKey(ctx context.Context, userKey string) error
+}
+
+// AdminAuditRepository определяет интерфейс для работы с журналом действий администраторов
+type AdminAuditRepository interface {
+	// Create добавляет запись в журнал
+	Create(ctx context.Context, entry *entity.AdminAuditEntry) error
+	// GetRecent возвращает последние записи журнала, начиная с самых новых
+	GetRecent(ctx context.Context, limit int) ([]*entity.AdminAuditEntry, error)
+}
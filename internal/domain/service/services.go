@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"io"
+	"time"
 
 	"github.com/112Alex/project_obsidian/internal/domain/entity"
 )
@@ -31,18 +33,42 @@ type JobService interface {
 type AudioService interface {
 	// SaveAudio сохраняет аудиофайл
 	SaveAudio(ctx context.Context, userID int64, audioData io.Reader, filename string) (string, error)
+	// DeleteUserFiles удаляет все сохраненные файлы пользователя с указанным Telegram ID
+	DeleteUserFiles(ctx context.Context, userID int64) error
 	// ConvertToWAV конвертирует аудиофайл в формат WAV
 	ConvertToWAV(ctx context.Context, inputPath string) (string, error)
+	// ExtractAudioFromVideo извлекает звуковую дорожку из видеофайла и возвращает путь к аудиофайлу
+	ExtractAudioFromVideo(ctx context.Context, videoPath string) (string, error)
+	// TrimAudio вырезает из аудиофайла промежуток с fromSeconds по toSeconds и возвращает путь к новому файлу
+	TrimAudio(ctx context.Context, audioPath string, fromSeconds int, toSeconds int) (string, error)
 	// GetAudioDuration возвращает длительность аудиофайла в секундах
 	GetAudioDuration(ctx context.Context, audioPath string) (float64, error)
 	// ProcessAudio обрабатывает аудиофайл для дальнейшего использования
 	ProcessAudio(ctx context.Context, audioPath string, fileName string) (string, error)
+	// ProcessAudioWithProfile обрабатывает аудиофайл цепочкой фильтров профиля и возвращает примененный профиль;
+	// для профиля auto он выбирается по характеристикам записи
+	ProcessAudioWithProfile(ctx context.Context, audioPath string, profile entity.AudioProfile) (string, entity.AudioProfile, error)
+	// SplitAudio нарезает запись на фрагменты WAV не больше maxBytes, соседние фрагменты перекрываются на overlap.
+	// Запись, которая помещается в один фрагмент, возвращается без нарезки
+	SplitAudio(ctx context.Context, audioPath string, maxBytes int64, overlap time.Duration) ([]entity.AudioChunk, error)
 }
 
 // TranscriptionService определяет интерфейс для транскрибации аудио
 type TranscriptionService interface {
 	// Transcribe выполняет транскрибацию аудиофайла
 	Transcribe(ctx context.Context, audioFilePath string) (string, error)
+	// TranscribeWithOptions выполняет транскрибацию с параметрами, переопределяющими значения по умолчанию
+	TranscribeWithOptions(ctx context.Context, audioFilePath string, opts entity.TranscriptionOptions) (string, error)
+	// TranscribeWithSpeakers выполняет транскрибацию с разделением по говорящим и возвращает фразы с метками времени.
+	// Провайдер, который не различает говорящих, возвращает фразы без них: их определяет DiarizationService
+	TranscribeWithSpeakers(ctx context.Context, audioFilePath string, opts entity.TranscriptionOptions) ([]entity.SpeakerSegment, error)
+}
+
+// DiarizationService определяет интерфейс разделения записи по говорящим для провайдеров распознавания,
+// которые сами говорящих не различают
+type DiarizationService interface {
+	// Diarize возвращает промежутки записи с номерами говорящих
+	Diarize(ctx context.Context, audioFilePath string) ([]entity.SpeakerTurn, error)
 }
 
 // SummarizationService определяет интерфейс для суммаризации текста
@@ -51,30 +77,195 @@ type SummarizationService interface {
 	Summarize(ctx context.Context, text string) (string, error)
 	// SummarizeText выполняет суммаризацию текста с форматированием
 	SummarizeText(ctx context.Context, text string) (string, error)
+	// SummarizeWithInstructions выполняет суммаризацию с учетом дополнительных указаний пользователя
+	SummarizeWithInstructions(ctx context.Context, text string, instructions string) (string, error)
+	// SummarizeJob выполняет суммаризацию текста задачи по лестнице моделей и возвращает
+	// итоговую модель и стоимость всех попыток
+	SummarizeJob(ctx context.Context, text string, instructions string) (*entity.SummaryResult, error)
+	// SummarizeWithModel выполняет суммаризацию текста задачи указанной моделью, минуя лестницу моделей
+	SummarizeWithModel(ctx context.Context, text string, instructions string, model string) (*entity.SummaryResult, error)
+	// SummarizeMeeting выполняет суммаризацию записи встречи по пунктам повестки, отмечая нерешенные пункты
+	SummarizeMeeting(ctx context.Context, text string, agenda string, instructions string) (*entity.SummaryResult, error)
+	// Translate переводит текст на язык, заданный названием в предложном падеже (например, «английском»)
+	Translate(ctx context.Context, text string, language string) (string, error)
+	// SuggestTags предлагает короткие теги по теме текста
+	SuggestTags(ctx context.Context, text string) ([]string, error)
+	// ClassifyTopic относит текст к одной из тем или возвращает пустую строку, если ни одна не подходит
+	ClassifyTopic(ctx context.Context, text string, topics []string) (string, error)
+	// AnalyzeSentiment оценивает настроение и тон встречи и находит напряженные моменты с цитатами
+	AnalyzeSentiment(ctx context.Context, text string) (*entity.JobSentiment, error)
+}
+
+// EmbeddingService определяет интерфейс для получения векторных представлений текста
+type EmbeddingService interface {
+	// Embed возвращает эмбеддинги для списка текстов в том же порядке
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// AnswerService определяет интерфейс для ответов на вопросы по заметкам
+type AnswerService interface {
+	// Answer отвечает на вопрос, опираясь на пронумерованные фрагменты заметок
+	Answer(ctx context.Context, question string, notes []string) (string, error)
 }
 
 // NotionService определяет интерфейс для работы с Notion
 type NotionService interface {
 	// CreateDatabase создает базу данных в Notion
 	CreateDatabase(ctx context.Context, userID int64, title string) (string, error)
-	// CreatePage создает страницу в Notion
-	CreatePage(ctx context.Context, databaseID, title, content string) (string, error)
+	// CreatePage создает пустую страницу в Notion
+	CreatePage(ctx context.Context, databaseID, title string) (string, error)
+	// AppendMarkdown добавляет на страницу следующую часть содержимого начиная с блока offset.
+	// Возвращает номер следующего блока и признак того, что содержимое добавлено полностью
+	AppendMarkdown(ctx context.Context, pageID, content string, offset int) (int, bool, error)
+	// CreateViewsPage создает рядом с базой данных страницу со ссылками на ее представления
+	CreateViewsPage(ctx context.Context, databaseID string) (string, error)
+	// AppendRelatedPages добавляет в конец страницы раздел со ссылками-упоминаниями связанных страниц
+	AppendRelatedPages(ctx context.Context, pageID string, relatedPageIDs []string) error
+	// AppendImage добавляет в конец страницы изображение с подписью и распознанный на нем текст
+	AppendImage(ctx context.Context, pageID, imageURL, caption, text string) error
+	// PageExists проверяет, что страница существует и не удалена пользователем
+	PageExists(ctx context.Context, pageID string) (bool, error)
+	// ArchivePage перемещает страницу в корзину Notion; уже удаленная страница не считается ошибкой
+	ArchivePage(ctx context.Context, pageID string) error
 	// ConvertMarkdownToBlocks конвертирует Markdown в блоки Notion
 	ConvertMarkdownToBlocks(ctx context.Context, markdown string) (interface{}, error)
 }
 
+// NotionOAuthService определяет интерфейс подключения Notion пользователя через OAuth
+type NotionOAuthService interface {
+	// AuthorizeURL возвращает адрес страницы Notion, на которой пользователь разрешает доступ к своему
+	// рабочему пространству; state возвращается в обратный вызов без изменений
+	AuthorizeURL(state string) string
+	// ExchangeCode обменивает код из обратного вызова на токен доступа и возвращает токен и название
+	// рабочего пространства
+	ExchangeCode(ctx context.Context, code string) (string, string, error)
+}
+
+// OCRService определяет интерфейс для распознавания текста на изображениях
+type OCRService interface {
+	// RecognizeText возвращает текст, распознанный на изображении (пустой, если текста нет)
+	RecognizeText(ctx context.Context, image []byte, contentType string) (string, error)
+}
+
+// MaxSpeechTextLength - наибольшая длина текста в символах, который SpeechService озвучивает за один вызов
+const MaxSpeechTextLength = 4096
+
+// SpeechService определяет интерфейс для сервиса синтеза речи
+type SpeechService interface {
+	// Synthesize озвучивает текст и возвращает аудио в формате OGG/Opus
+	Synthesize(ctx context.Context, text string) ([]byte, error)
+}
+
+// WebhookService определяет интерфейс доставки событий задач на вебхуки пользователей
+type WebhookService interface {
+	// Deliver отправляет событие задачи на вебхук; errorMessage заполняется для события об ошибке
+	Deliver(ctx context.Context, webhook *entity.UserWebhook, event entity.WebhookEvent, user *entity.User, job *entity.Job, errorMessage string) error
+}
+
+// LockService определяет интерфейс блокировок, общих для всех экземпляров приложения
+type LockService interface {
+	// Lock захватывает блокировку key, ожидая ее освобождения не дольше wait, и возвращает функцию освобождения
+	Lock(ctx context.Context, key string, wait time.Duration) (func(), error)
+}
+
+// SyncTarget определяет интерфейс места, в которое выгружаются результаты завершенной задачи
+// (Notion, хранилище Obsidian, вебхук). Активные места выгружаются одновременно
+type SyncTarget interface {
+	// Name возвращает имя места синхронизации, под которым сохраняется результат выгрузки
+	Name() string
+	// Enabled сообщает, настроено ли место синхронизации для пользователя
+	Enabled(user *entity.User) bool
+	// Sync выгружает транскрипцию и конспект задачи и возвращает ID результата в месте синхронизации
+	Sync(ctx context.Context, user *entity.User, job *entity.Job) (string, error)
+}
+
+// HealthCheck определяет интерфейс проверки зависимости приложения (базы данных, FFmpeg, API провайдера)
+// для самодиагностики командой /admin doctor
+type HealthCheck interface {
+	// Name возвращает название проверяемой зависимости для отчета или идентификатор его перевода
+	Name() string
+	// Check выполняет проверку и возвращает подробности для отчета (версию, свободное место и т.п.)
+	Check(ctx context.Context) (string, error)
+}
+
+// PodcastService определяет интерфейс для работы с RSS-лентами подкастов
+type PodcastService interface {
+	// FetchFeed загружает ленту и возвращает ее название и выпуски, новые первыми
+	FetchFeed(ctx context.Context, feedURL string) (string, []*entity.PodcastEpisode, error)
+	// DownloadEpisode загружает аудио выпуска во временный каталог и возвращает путь к файлу
+	DownloadEpisode(ctx context.Context, userID int64, episode *entity.PodcastEpisode) (string, error)
+}
+
+// VideoService определяет интерфейс для загрузки видео по ссылке
+type VideoService interface {
+	// FetchInfo возвращает название, длительность и главы видео, не загружая его
+	FetchInfo(ctx context.Context, videoURL string) (*entity.VideoInfo, error)
+	// DownloadAudio загружает звуковую дорожку видео во временный каталог и возвращает путь к файлу
+	DownloadAudio(ctx context.Context, userID int64, video *entity.VideoInfo) (string, error)
+}
+
+// DocumentService определяет интерфейс для выгрузки результатов задач в документы
+type DocumentService interface {
+	// RenderJob верстает документ с результатом задачи и возвращает содержимое PDF
+	RenderJob(ctx context.Context, document *entity.JobDocument) ([]byte, error)
+}
+
+// Ошибки проверки файла, загружаемого по ссылке, о которых бот сообщает пользователю
+var (
+	// ErrRemoteFileTooLarge - файл по ссылке больше допустимого размера
+	ErrRemoteFileTooLarge = errors.New("remote file is too large")
+	// ErrRemoteFileNotAudio - по ссылке находится не аудио- или видеофайл (например, веб-страница)
+	ErrRemoteFileNotAudio = errors.New("remote file is not audio")
+)
+
+// URLIngestionService определяет интерфейс загрузки записей по прямым ссылкам на аудиофайлы
+type URLIngestionService interface {
+	// MaxSize возвращает наибольший размер загружаемого файла в байтах
+	MaxSize() int64
+	// Download загружает файл по ссылке во временный каталог пользователя и возвращает путь к файлу и его имя.
+	// Если файл не прошел проверку, возвращает ошибку ErrRemoteFileTooLarge или ErrRemoteFileNotAudio
+	Download(ctx context.Context, userID int64, fileURL string) (string, string, error)
+}
+
+// CallRecordingService определяет интерфейс получения записей звонков у VoIP провайдеров
+type CallRecordingService interface {
+	// ResolveRecording заполняет ссылку на запись, если провайдер сообщил о готовой записи без ссылки
+	ResolveRecording(ctx context.Context, event *entity.CallEvent) error
+}
+
+// MessengerService определяет интерфейс для отправки сообщений пользователям
+type MessengerService interface {
+	// SendMessage отправляет текстовое сообщение и возвращает его ID
+	SendMessage(chatID int64, text string) (int, error)
+	// SendReply отправляет текстовое сообщение в ответ на указанное сообщение и возвращает его ID
+	SendReply(chatID int64, replyToMessageID int, text string) (int, error)
+	// SendReplyWithButtons отправляет сообщение с рядами inline-кнопок в ответ на указанное сообщение и возвращает его ID
+	SendReplyWithButtons(chatID int64, replyToMessageID int, text string, buttons [][]entity.MessageButton) (int, error)
+	// SendDocument отправляет файл в ответ на указанное сообщение и возвращает ID сообщения с ним
+	SendDocument(chatID int64, replyToMessageID int, fileName string, data []byte, caption string) (int, error)
+	// SendVoice отправляет голосовое сообщение в формате OGG/Opus в ответ на указанное сообщение и возвращает его ID
+	SendVoice(chatID int64, replyToMessageID int, fileName string, data []byte, caption string) (int, error)
+	// EditMessage заменяет текст ранее отправленного сообщения
+	EditMessage(chatID int64, messageID int, text string) error
+	// DeleteMessage удаляет ранее отправленное сообщение
+	DeleteMessage(chatID int64, messageID int) error
+}
+
 // QueueService определяет интерфейс для работы с очередью задач
 type QueueService interface {
 	// EnqueueTranscriptionJob добавляет задачу транскрибации в очередь
 	EnqueueTranscriptionJob(ctx context.Context, jobID, userID int64, audioFilePath string) error
-	// EnqueueSummarizationJob добавляет задачу суммаризации в очередь
-	EnqueueSummarizationJob(ctx context.Context, jobID, userID int64, transcription string) error
+	// EnqueueSummarizationJob добавляет задачу суммаризации сохраненной транскрипции в очередь
+	EnqueueSummarizationJob(ctx context.Context, jobID, userID int64) error
 	// EnqueueNotionSyncJob добавляет задачу синхронизации с Notion в очередь
 	EnqueueNotionSyncJob(ctx context.Context, jobID, userID int64, title, content string) error
 	// RegisterHandler регистрирует обработчик для определенного типа задач
 	RegisterHandler(jobType entity.JobType, handler func(ctx context.Context, job entity.QueueJob) error)
-	// StartWorker запускает обработчик задач из очереди
-	StartWorker(ctx context.Context) error
+	// RunWorker обрабатывает задачи из очереди до отмены контекста
+	RunWorker(ctx context.Context) error
 	// PushJob добавляет задачу в очередь
 	PushJob(ctx context.Context, job entity.QueueJob) error
+	// RunNow выполняет задачу сразу, минуя очередь, вместе с последующими этапами обработки.
+	// Возвращает false, если все места для таких задач заняты и задачу нужно поставить в очередь
+	RunNow(ctx context.Context, job entity.QueueJob) bool
 }
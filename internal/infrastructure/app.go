@@ -2,96 +2,234 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/112Alex/project_obsidian/internal/config"
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
 	"github.com/112Alex/project_obsidian/internal/infrastructure/database"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/deepgram"
 	"github.com/112Alex/project_obsidian/internal/infrastructure/deepseek"
 	"github.com/112Alex/project_obsidian/internal/infrastructure/ffmpeg"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/ingestion"
 	"github.com/112Alex/project_obsidian/internal/infrastructure/notion"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/obsidian"
 	"github.com/112Alex/project_obsidian/internal/infrastructure/openai"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/pdf"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/podcast"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/pyannote"
 	"github.com/112Alex/project_obsidian/internal/infrastructure/queue"
 	"github.com/112Alex/project_obsidian/internal/infrastructure/telegram"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/video"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/voip"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/web"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/webhook"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/whisperlocal"
 	"github.com/112Alex/project_obsidian/internal/usecase"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
 	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/tempfs"
 )
 
 // App представляет собой приложение
 type App struct {
-	Config      *config.Config
-	Logger      *logger.Logger
-	PostgresDB  *database.PostgresDB
-	RedisClient *database.RedisClient
-	Bot         *telegram.Bot
-	UseCase     *usecase.App
+	Config     *config.Config
+	Logger     *logger.Logger
+	storage    *storage
+	Bot        *telegram.Bot
+	HTTPServer *web.Server
+	UseCase    *usecase.App
+	supervisor *supervisor
+
+	callReceiver *voip.Receiver
 }
 
 // NewApp создает новое приложение
 func NewApp(config *config.Config, logger *logger.Logger) (*App, error) {
-	// Инициализация PostgreSQL
-	postgresDB, err := database.NewPostgresDB(context.Background(), config.Postgres)
+	// Инициализация хранилища данных и репозиториев
+	store, err := newStorage(context.Background(), config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Инициализация каталога временных файлов
+	tempSpace, err := tempfs.New(config.Temp.Dir, config.Temp.MaxSizeMB*1024*1024, logger)
 	if err != nil {
-		logger.Error("Failed to initialize PostgreSQL",
+		logger.Error("Failed to initialize temp directory",
 			"error", err,
 		)
 		return nil, err
 	}
 
-	// Инициализация Redis
-	redisClient, err := database.NewRedisClient(context.Background(), config.Redis)
+	// Ошибки перечитывания секретов не останавливают работу: сервисы используют прежние значения ключей
+	config.SecretStore.OnRefreshError(func(err error) {
+		logger.Error("Failed to refresh secrets",
+			"error", err,
+			"source", config.Secrets.Source,
+		)
+	})
+
+	// Инициализация сервисов
+	audioService := ffmpeg.NewAudioService(config.FFmpeg.BinaryPath, config.FFmpeg.MaxConcurrency, tempSpace, logger)
+	whisperTemperature := float32(config.OpenAI.WhisperTemperature)
+	whisperDefaults := entity.TranscriptionOptions{
+		Temperature: &whisperTemperature,
+		Prompt:      config.OpenAI.WhisperPrompt,
+		Granularity: entity.TranscriptionGranularity(config.OpenAI.WhisperGranularity),
+	}
+	openAITranscriptionService := openai.NewTranscriptionService(config.OpenAI.CurrentAPIKey, config.OpenAI.WhisperModel, whisperDefaults, logger)
+	// Записи распознаются локальным сервером или Deepgram, если они настроены: тогда они не отправляются в OpenAI
+	var transcriptionService service.TranscriptionService = openAITranscriptionService
+	var localTranscriptionService *whisperlocal.TranscriptionService
+	var deepgramTranscriptionService *deepgram.TranscriptionService
+	switch {
+	case config.WhisperLocal.Enabled():
+		localTranscriptionService = whisperlocal.NewTranscriptionService(config.WhisperLocal.URL, config.WhisperLocal.API,
+			config.WhisperLocal.Model, config.WhisperLocal.Concurrency, whisperDefaults, logger)
+		transcriptionService = localTranscriptionService
+	case config.Deepgram.Enabled():
+		deepgramTranscriptionService = deepgram.NewTranscriptionService(config.Deepgram.CurrentAPIKey, config.Deepgram.Model,
+			config.Deepgram.SmartFormat, whisperDefaults, logger)
+		transcriptionService = deepgramTranscriptionService
+	}
+	// Говорящих определяет сервис разделения, если провайдер распознавания их не различает
+	var diarizationService service.DiarizationService
+	var pyannoteDiarizationService *pyannote.DiarizationService
+	if config.Diarization.Enabled && config.Diarization.URL != "" && !config.Deepgram.Enabled() {
+		pyannoteDiarizationService = pyannote.NewDiarizationService(config.Diarization.URL, logger)
+		diarizationService = pyannoteDiarizationService
+	}
+	modelLadder, err := deepseek.ParseModelLadder(config.DeepSeek.ModelLadder)
 	if err != nil {
-		logger.Error("Failed to initialize Redis",
+		logger.Error("Failed to parse summarization model ladder",
 			"error", err,
 		)
 		return nil, err
 	}
+	summarizationService := deepseek.NewSummarizationService(config.DeepSeek.CurrentAPIKey, "", config.DeepSeek.Model, config.DeepSeek.SystemPrompt, deepseek.ChunkingOptions{
+		Size:        config.DeepSeek.ChunkSize,
+		Concurrency: config.DeepSeek.ChunkConcurrency,
+		Retries:     config.DeepSeek.ChunkRetries,
+	}, config.DeepSeek.MaxConcurrency, modelLadder, logger)
+	notionService := notion.NewNotionService(config.Notion.CurrentAPIKey, logger)
+	var notionOAuthService service.NotionOAuthService
+	if config.Notion.OAuthEnabled() {
+		redirectURL := strings.TrimRight(config.HTTP.PublicURL, "/") + web.NotionCallbackPath
+		notionOAuthService = notion.NewOAuthService(config.Notion.OAuthClientID, config.Notion.CurrentOAuthClientSecret, redirectURL, logger)
+	}
+	embeddingService := openai.NewEmbeddingService(config.OpenAI.CurrentAPIKey, config.OpenAI.EmbeddingModel, logger)
+	ocrService := openai.NewOCRService(config.OpenAI.CurrentAPIKey, config.OpenAI.OCRModel, logger)
+	speechService := openai.NewSpeechService(config.OpenAI.CurrentAPIKey, config.OpenAI.TTSModel, config.OpenAI.TTSVoice, logger)
+	queueService := queue.NewQueueService(store.queueRepo, store.jobRepo, config.Queue.MaxPanicAttempts, config.Queue.InlineConcurrency, logger)
+	podcastService := podcast.NewFeedService(config.Podcast.MaxEpisodeMB*1024*1024, tempSpace, logger)
+	videoService := video.NewYTDLPService(config.Video.YTDLPPath, tempSpace, logger)
+	urlIngestionService := ingestion.NewURLIngestionService(config.Ingestion.MaxSizeMB*1024*1024, tempSpace, logger)
+	callReceiver := voip.NewReceiver(config.HTTP.PublicURL, config.VoIP.TwilioAuthToken, config.VoIP.ZadarmaAPIKey, config.VoIP.ZadarmaAPISecret, logger)
+	var documentService service.DocumentService
+	if config.PDF.RendererPath != "" {
+		documentService = pdf.NewRenderer(config.PDF.RendererPath, tempSpace, logger)
+	}
+
+	// Дополнительные места выгрузки результатов, включенные в конфигурации
+	syncTargets := make([]service.SyncTarget, 0)
+	if config.Sync.VaultDir != "" {
+		syncTargets = append(syncTargets, obsidian.NewVaultTarget(config.Sync.VaultDir, logger))
+	}
+	if config.Sync.WebhookURL != "" {
+		syncTargets = append(syncTargets, webhook.NewTarget(config.Sync.WebhookURL, logger))
+	}
 
-	// Инициализация репозиториев
-	userRepo := database.NewUserRepository(postgresDB)
-	jobRepo := database.NewJobRepository(postgresDB)
-	queueRepo := database.NewQueueRepository(redisClient)
+	// Проверки самодиагностики для команды /admin doctor
+	healthChecks := newHealthChecks(config, store, audioService, openAITranscriptionService, localTranscriptionService, deepgramTranscriptionService, pyannoteDiarizationService, summarizationService, notionService, queueService, tempSpace)
 
-	// Инициализация сервисов
-	audioService := ffmpeg.NewAudioService(config.FFmpeg.BinaryPath, logger)
-	transcriptionService := openai.NewTranscriptionService(config.OpenAI.APIKey, config.OpenAI.WhisperModel, logger)
-	summarizationService := deepseek.NewSummarizationService(config.DeepSeek.APIKey, "", config.DeepSeek.Model, logger)
-	notionService := notion.NewNotionService(config.Notion.APIKey, logger)
-	queueService := queue.NewQueueService(queueRepo, jobRepo, logger)
+	// Инициализация Telegram бота
+	bot, err := telegram.NewBot(config.Telegram.Token, config.Telegram.APIURL, config.Telegram.DownloadConcurrency, config.Telegram.DownloadRateMB, tempSpace, logger)
+	if err != nil {
+		logger.Error("Failed to initialize Telegram bot",
+			"error", err,
+		)
+		return nil, err
+	}
 
 	// Инициализация слоя usecase
 	useCaseApp := usecase.NewApp(
 		config,
 		logger,
-		userRepo,
-		jobRepo,
-		queueRepo,
+		store.userRepo,
+		store.jobRepo,
+		store.jobStageRepo,
+		store.queueRepo,
+		store.shareLinkRepo,
+		store.embeddingRepo,
+		store.messageDeletionRepo,
+		store.podcastRepo,
+		store.transcriptSegmentRepo,
+		store.syncResultRepo,
+		store.teamRepo,
+		store.readingLessonRepo,
+		store.jobAttachmentRepo,
+		store.userWebhookRepo,
+		store.failureRollupRepo,
+		store.adminAuditRepo,
+		store.videoChapterRepo,
+		store.meetingAgendaRepo,
+		store.userSettingsRepo,
+		store.usageEventRepo,
+		store.jobSentimentRepo,
+		store.notionOAuthStateRepo,
+		store.modelComparisonRepo,
+		store.callRecordingRepo,
+		store.notionRouteRepo,
+		store.jobLogRepo,
+		store.rateLimitRepo,
+		store.liveSessionRepo,
+		store.maintenanceRepo,
 		audioService,
 		transcriptionService,
+		diarizationService,
 		summarizationService,
 		notionService,
+		notionOAuthService,
+		embeddingService,
+		summarizationService,
 		queueService,
+		podcastService,
+		ocrService,
+		speechService,
+		webhook.NewClient(logger),
+		videoService,
+		urlIngestionService,
+		callReceiver,
+		documentService,
+		store.lockService,
+		telegram.NewMessenger(bot),
+		syncTargets,
+		healthChecks,
 	)
 
-	// Инициализация Telegram бота
-	// Инициализация Telegram бота
-	bot, err := telegram.NewBot(config.Telegram.Token, logger)
-	if err != nil {
-		logger.Error("Failed to initialize Telegram bot",
-			"error", err,
-		)
-		return nil, err
+	// В режиме обслуживания воркер дорабатывает текущую задачу и больше задач не берет
+	queueService.RegisterPauseCheck(useCaseApp.MaintenanceUseCase.QueuePaused)
+
+	// Инициализация HTTP сервера
+	httpServer := web.NewServer(config.HTTP.Addr, logger)
+
+	app := &App{
+		Config:     config,
+		Logger:     logger,
+		storage:    store,
+		Bot:        bot,
+		HTTPServer: httpServer,
+		UseCase:    useCaseApp,
+
+		callReceiver: callReceiver,
 	}
+	app.supervisor = newSupervisor(config.App.RestartBackoff, config.App.RestartMaxBackoff, app.alertAdmins, logger)
 
-	return &App{
-		Config:      config,
-		Logger:      logger,
-		PostgresDB:  postgresDB,
-		RedisClient: redisClient,
-		Bot:         bot,
-		UseCase:     useCaseApp,
-	}, nil
+	return app, nil
 }
 
 // Start запускает приложение
@@ -108,6 +246,45 @@ func (a *App) Start(ctx context.Context) error {
 		return err
 	}
 
+	// Воркер очереди и расписания работают под наблюдением супервизора, который перезапускает упавшие
+	for _, subsystem := range a.UseCase.Subsystems() {
+		a.supervisor.start(ctx, subsystem.Name, subsystem.Run)
+	}
+
+	// Сжатие транскрипций и резюме, сохраненных до включения сжатия, идет в фоне на работающем приложении
+	if a.storage.postgresDB != nil && a.Config.Postgres.CompressMinBytes > 0 {
+		go func() {
+			if _, err := database.CompressJobTexts(ctx, a.storage.postgresDB, a.Config.Postgres.CompressMinBytes, a.Logger); err != nil {
+				a.Logger.Error("Failed to compress stored job texts",
+					"error", err,
+				)
+			}
+		}()
+	}
+
+	// Ответы бота на языке, выбранном в /settings, или на языке профиля Telegram
+	a.Bot.RegisterLanguageResolver(a.UseCase.InterfaceLanguageUseCase.Resolve)
+
+	// Группа, в которую добавили бота, получает свои настройки и приветствие с инструкцией
+	a.Bot.RegisterMembershipHandler(func(ctx context.Context, chat *tgbotapi.Chat, added bool) error {
+		if !added {
+			a.Logger.Info("Bot removed from group", "chat_id", chat.ID)
+			return nil
+		}
+		resp, err := a.UseCase.GroupChatUseCase.HandleAdded(ctx, chat.ID, entity.ChatType(chat.Type), chat.Title)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(chat.ID, resp)
+		return err
+	})
+	a.Bot.RegisterChatMigrationHandler(a.UseCase.GroupChatUseCase.Migrate)
+
+	// Учет вызовов команд в обезличенной статистике использования
+	a.Bot.RegisterCommandListener(func(ctx context.Context, m *tgbotapi.Message) {
+		a.UseCase.UsageAnalyticsUseCase.TrackCommand(ctx, m.Chat.ID, m.Command())
+	})
+
 	// Регистрация обработчиков команд Telegram
 	a.Bot.RegisterCommandHandler("start", func(ctx context.Context, m *tgbotapi.Message) error {
 		resp, err := a.UseCase.TelegramHandlersUseCase.HandleStart(ctx, m.Chat.ID, m.From.UserName)
@@ -129,6 +306,18 @@ func (a *App) Start(ctx context.Context) error {
 
 	a.Bot.RegisterCommandHandler("notion", func(ctx context.Context, m *tgbotapi.Message) error {
 		args := strings.TrimSpace(m.CommandArguments())
+		if !m.Chat.IsPrivate() {
+			return a.handleGroupNotion(ctx, m, args)
+		}
+		// С настроенным OAuth токен не принимается: пользователь входит в Notion по ссылке
+		if a.UseCase.NotionOAuthUseCase.Enabled() {
+			resp, buttons, err := a.UseCase.NotionOAuthUseCase.HandleNotion(ctx, m.Chat.ID)
+			if err != nil {
+				return err
+			}
+			_, err = a.Bot.SendMarkdownMessageWithKeyboard(m.Chat.ID, resp, telegram.NewButtonsKeyboard(buttons))
+			return err
+		}
 		resp, err := a.UseCase.TelegramHandlersUseCase.HandleNotion(ctx, m.Chat.ID, args)
 		if err != nil {
 			return err
@@ -138,7 +327,31 @@ func (a *App) Start(ctx context.Context) error {
 	})
 
 	a.Bot.RegisterCommandHandler("jobs", func(ctx context.Context, m *tgbotapi.Message) error {
-		resp, err := a.UseCase.TelegramHandlersUseCase.HandleJobs(ctx, m.Chat.ID)
+		resp, buttons, err := a.UseCase.TelegramHandlersUseCase.HandleJobs(ctx, m.Chat.ID)
+		if err != nil {
+			return err
+		}
+		if len(buttons) == 0 {
+			_, err = a.Bot.SendMarkdownMessage(m.Chat.ID, resp)
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessageWithKeyboard(m.Chat.ID, resp, telegram.NewButtonsKeyboard(buttons))
+		return err
+	})
+
+	a.Bot.RegisterCommandHandler("share", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleShare(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessage(m.Chat.ID, resp)
+		return err
+	})
+
+	a.Bot.RegisterCommandHandler("unshare", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleUnshare(ctx, m.Chat.ID, args)
 		if err != nil {
 			return err
 		}
@@ -146,61 +359,1141 @@ func (a *App) Start(ctx context.Context) error {
 		return err
 	})
 
-	// Регистрация обработчика аудио и голосовых сообщений
+	// В приватном режиме бот отвечает только пользователям из списка доступа и администраторам
+	if a.Config.Telegram.PrivateMode() {
+		allowedIDs := append(append([]int64{}, a.Config.Telegram.AllowedIDs...), a.Config.Telegram.AdminIDs...)
+		access := telegram.NewAccessList(a.Bot, allowedIDs, a.Config.Telegram.AllowedChannel, a.Logger)
+		a.Bot.RegisterAccessCheck(access.Allowed)
+	}
+
+	// Сообщения пользователей, заблокированных командой /admin_ban, отклоняются до любой обработки
+	a.Bot.RegisterBanCheck(a.UseCase.AdminModerationUseCase.IsBanned)
+
+	// Частота аудио сообщений ограничивается лимитом роли пользователя до загрузки файла
+	a.Bot.RegisterRateLimit(a.UseCase.AudioRateLimitUseCase.Take)
+
+	// Повторно присланный файл не загружается: пользователь сразу получает прошлый результат.
+	// Новые аудио не принимаются сверх дневного лимита задач
+	a.Bot.RegisterAudioGuard(func(ctx context.Context, m *tgbotapi.Message) (bool, error) {
+		if err := a.registerGroup(ctx, m.Chat); err != nil {
+			return false, err
+		}
+		if _, fileUniqueID := audioFileIDs(m); fileUniqueID != "" {
+			duplicate, err := a.UseCase.TelegramHandlersUseCase.HandleDuplicateFile(ctx, m.Chat.ID, m.MessageID, fileUniqueID)
+			if err != nil {
+				a.Logger.Warn("Failed to check duplicate file", "error", err)
+			} else if duplicate {
+				return false, nil
+			}
+		}
+		return a.checkRequirements(ctx, m.Chat.ID, entity.CommandRequirementService, entity.CommandRequirementQuota)
+	})
+
+	// Регистрация обработчика аудио, голосовых сообщений и видео
 	a.Bot.RegisterAudioHandler(func(ctx context.Context, m *tgbotapi.Message, filePath string, fileName string) error {
-		// Определяем тип сообщения и вызываем соответствующий usecase
+		// Определяем тип сообщения и вызываем соответствующий usecase.
+		// Подпись к аудио передается как дополнительные указания к конспекту
+		var resp string
+		var jobID int64
 		var err error
+		fileID, fileUniqueID := audioFileIDs(m)
 		if m.Voice != nil {
-			_, err = a.UseCase.TelegramHandlersUseCase.HandleVoiceMessage(ctx, m.Chat.ID, m.From.UserName, m.Voice.FileID, filePath, fileName)
+			resp, jobID, err = a.UseCase.TelegramHandlersUseCase.HandleVoiceMessage(ctx, m.Chat.ID, m.From.UserName, m.MessageID, fileID, fileUniqueID, filePath, fileName, m.Caption)
 		} else if m.Audio != nil {
-			_, err = a.UseCase.TelegramHandlersUseCase.HandleAudioFile(ctx, m.Chat.ID, m.From.UserName, m.Audio.FileID, filePath, fileName)
+			resp, jobID, err = a.UseCase.TelegramHandlersUseCase.HandleAudioFile(ctx, m.Chat.ID, m.From.UserName, m.MessageID, fileID, fileUniqueID, filePath, fileName, m.Caption)
+		} else if m.VideoNote != nil || m.Video != nil {
+			resp, jobID, err = a.UseCase.TelegramHandlersUseCase.HandleVideoMessage(ctx, m.Chat.ID, m.From.UserName, m.MessageID, fileID, fileUniqueID, filePath, fileName, m.Caption)
+		}
+		if err != nil || resp == "" {
+			return err
+		}
+		// Сообщение live-сессии не создает задачу, и выбирать профиль не для чего
+		if jobID == 0 {
+			_, err = a.Bot.SendReplyMessage(m.Chat.ID, m.MessageID, resp)
+			return err
+		}
+		// К ответу прикладываются кнопки выбора профиля предобработки аудио. Дальше ответ становится
+		// сообщением о ходе обработки: кнопки пропадают, когда обработка начинается и профиль уже не выбрать
+		sent, err := a.Bot.SendMarkdownReplyMessageWithKeyboard(m.Chat.ID, m.MessageID, resp, telegram.NewAudioProfileKeyboard(ctx, jobID))
+		if err != nil {
+			return err
+		}
+		a.UseCase.TelegramHandlersUseCase.AttachProgressMessage(ctx, jobID, sent.MessageID)
+		a.offerSummaryStyle(ctx, m, jobID)
+		return nil
+	})
+
+	// Регистрация обработчика отредактированных сообщений: исправленная подпись к записи заменяет указания
+	// к конспекту, пока краткое содержание не составлено
+	a.Bot.RegisterEditedMessageHandler(func(ctx context.Context, m *tgbotapi.Message) error {
+		if m.Voice == nil && m.Audio == nil && m.VideoNote == nil && m.Video == nil {
+			return nil
+		}
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleEditedCaption(ctx, m.Chat.ID, m.MessageID, m.Caption)
+		if err != nil || resp == "" {
+			return err
 		}
+		_, err = a.Bot.SendReplyMessage(m.Chat.ID, m.MessageID, resp)
 		return err
 	})
 
-	// Запуск Telegram бота
-	err = a.Bot.Start()
-	if err != nil {
-		a.Logger.Error("Failed to start Telegram bot",
-			"error", err,
-		)
+	// Регистрация обработчика фотографий: подпись «к задаче <id>» прикладывает фото к задаче
+	a.Bot.RegisterPhotoHandler(func(ctx context.Context, m *tgbotapi.Message, image []byte) error {
+		// В группе фото без такой подписи адресованы участникам, а не боту
+		if !m.Chat.IsPrivate() && !usecase.IsPhotoAttachmentCaption(m.Caption) {
+			return nil
+		}
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandlePhoto(ctx, m.Chat.ID, m.Caption, image, telegram.PhotoContentType)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendReplyMessage(m.Chat.ID, m.MessageID, resp)
 		return err
-	}
-	if err != nil {
-		a.Logger.Error("Failed to start Telegram bot",
-			"error", err,
-		)
+	})
+
+	// Регистрация обработчика документов: подпись «/config import» загружает файл настроек,
+	// подпись «/agenda» - повестку следующей записи встречи
+	a.Bot.RegisterDocumentHandler(func(ctx context.Context, m *tgbotapi.Message) error {
+		command, args := captionCommand(m.Caption)
+		switch {
+		case command == "config" && args == "import":
+			return a.groupAdminOnly(func(ctx context.Context, m *tgbotapi.Message) error {
+				return a.importUserConfig(ctx, m, m.Document)
+			})(ctx, m)
+		case command == "agenda" && args == "":
+			return a.setMeetingAgenda(ctx, m, m.Document)
+		}
+		return nil
+	})
+
+	// Регистрация обработчика кнопок выбора профиля предобработки аудио
+	a.Bot.RegisterCallbackHandler(telegram.AudioProfileCallbackPrefix, func(ctx context.Context, q *tgbotapi.CallbackQuery, data string) (string, error) {
+		if q.Message == nil {
+			return i18n.T(ctx, "callback.message_unavailable"), nil
+		}
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleAudioProfile(ctx, q.Message.Chat.ID, data)
+		if err != nil {
+			return "", err
+		}
+		// Профиль выбирается один раз, поэтому кнопки больше не нужны
+		if err := a.Bot.RemoveInlineKeyboard(q.Message.Chat.ID, q.Message.MessageID); err != nil {
+			a.Logger.Warn("Failed to remove audio profile keyboard", "error", err)
+		}
+		return resp, nil
+	})
+
+	// Регистрация обработчика кнопок выбора набора настроек обработки: набор заменяет выбор профиля,
+	// поэтому кнопки профилей убираются вместе с ним
+	a.Bot.RegisterCallbackHandler(telegram.PipelineCallbackPrefix, func(ctx context.Context, q *tgbotapi.CallbackQuery, data string) (string, error) {
+		if q.Message == nil {
+			return i18n.T(ctx, "callback.message_unavailable"), nil
+		}
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandlePipeline(ctx, q.Message.Chat.ID, data)
+		if err != nil {
+			return "", err
+		}
+		if err := a.Bot.RemoveInlineKeyboard(q.Message.Chat.ID, q.Message.MessageID); err != nil {
+			a.Logger.Warn("Failed to remove pipeline keyboard", "error", err)
+		}
+		return resp, nil
+	})
+
+	// Регистрация обработчика кнопок выбора стиля краткого содержания: сообщение с вопросом заменяется выбранным
+	// стилем и кнопкой «Запомнить выбор». Запомненный стиль меняет настройки, поэтому в группе его запоминают
+	// только администраторы
+	a.Bot.RegisterCallbackHandler(usecase.SummaryStyleCallbackPrefix, func(ctx context.Context, q *tgbotapi.CallbackQuery, data string) (string, error) {
+		if q.Message == nil {
+			return i18n.T(ctx, "callback.message_unavailable"), nil
+		}
+		if !q.Message.Chat.IsPrivate() && strings.HasSuffix(data, ":remember") {
+			admin, err := a.Bot.IsChatAdmin(ctx, q.Message.Chat.ID, q.From.ID)
+			if err != nil {
+				return "", err
+			}
+			if !admin {
+				return i18n.T(ctx, usecase.GroupAdminOnlyMessage), nil
+			}
+		}
+		text, buttons, answer, err := a.UseCase.TelegramHandlersUseCase.HandleSummaryStyle(ctx, q.Message.Chat.ID, data)
+		if err != nil || text == "" {
+			return answer, err
+		}
+		if len(buttons) > 0 {
+			err = a.Bot.EditMessageTextWithKeyboard(q.Message.Chat.ID, q.Message.MessageID, text, telegram.NewButtonsKeyboard(buttons))
+		} else {
+			err = a.Bot.EditMessageText(q.Message.Chat.ID, q.Message.MessageID, text)
+		}
+		if err != nil {
+			a.Logger.Warn("Failed to update summary style message", "error", err)
+		}
+		return answer, nil
+	})
+
+	// Регистрация обработчика кнопок голосования в сравнении моделей: после голоса показываются названия моделей
+	a.Bot.RegisterCallbackHandler(usecase.ModelComparisonCallbackPrefix, func(ctx context.Context, q *tgbotapi.CallbackQuery, data string) (string, error) {
+		if q.Message == nil {
+			return i18n.T(ctx, "callback.message_unavailable"), nil
+		}
+		text, answer, err := a.UseCase.ModelComparisonUseCase.HandleVote(ctx, q.Message.Chat.ID, data)
+		if err != nil || text == "" {
+			return answer, err
+		}
+		if err := a.Bot.EditMessageText(q.Message.Chat.ID, q.Message.MessageID, text); err != nil {
+			a.Logger.Warn("Failed to update model comparison message", "error", err)
+		}
+		return answer, nil
+	})
+
+	// Регистрация обработчика кнопок под уведомлениями о завершении задач. Задачи и настройки принадлежат
+	// чату, поэтому здесь и ниже они ищутся по ID чата сообщения с кнопкой: в группе ее нажимает любой участник
+	a.Bot.RegisterCallbackHandler(usecase.JobActionCallbackPrefix, func(ctx context.Context, q *tgbotapi.CallbackQuery, data string) (string, error) {
+		if q.Message == nil {
+			return i18n.T(ctx, "callback.message_unavailable"), nil
+		}
+		return a.UseCase.TelegramHandlersUseCase.HandleJobAction(ctx, q.Message.Chat.ID, q.Message.Chat.ID, q.Message.MessageID, data)
+	})
+
+	// Регистрация обработчика кнопки «Сохранить в Notion» под кратким содержанием /summarize: краткое содержание
+	// берется из сообщения с кнопкой, а исходный текст - из сообщения, на которое оно отвечает
+	a.Bot.RegisterCallbackHandler(usecase.QuickSummaryCallbackPrefix, func(ctx context.Context, q *tgbotapi.CallbackQuery, data string) (string, error) {
+		if data != usecase.QuickSummaryActionNotion || q.Message == nil {
+			return i18n.T(ctx, "callback.unknown_action"), nil
+		}
+		var sourceText string
+		if source := q.Message.ReplyToMessage; source != nil {
+			sourceText = source.Text
+			if sourceText == "" {
+				sourceText = source.Caption
+			}
+			// Текст, написанный после команды, сохраняется без самой команды
+			if source.IsCommand() && source.Command() == "summarize" {
+				sourceText = source.CommandArguments()
+			}
+		}
+		answer, saved, err := a.UseCase.QuickSummaryUseCase.SaveToNotion(ctx, q.Message.Chat.ID, q.Message.MessageID, q.Message.Text, sourceText)
+		if err != nil || !saved {
+			return answer, err
+		}
+		// Результат сохраняется один раз, поэтому кнопка больше не нужна
+		if err := a.Bot.RemoveInlineKeyboard(q.Message.Chat.ID, q.Message.MessageID); err != nil {
+			a.Logger.Warn("Failed to remove quick summary keyboard", "error", err)
+		}
+		return answer, nil
+	})
+
+	// Регистрация обработчика кнопок списка задач /jobs: страница списка обновляется на месте
+	a.Bot.RegisterCallbackHandler(usecase.JobsCallbackPrefix, func(ctx context.Context, q *tgbotapi.CallbackQuery, data string) (string, error) {
+		if q.Message == nil {
+			return i18n.T(ctx, "callback.message_unavailable"), nil
+		}
+		text, buttons, answer, err := a.UseCase.TelegramHandlersUseCase.HandleJobsPage(ctx, q.Message.Chat.ID, data)
+		if err != nil {
+			return "", err
+		}
+		// Повторное нажатие выбранного отбора не меняет сообщение, и Telegram отвечает на правку ошибкой
+		if err := a.Bot.EditMarkdownMessageTextWithKeyboard(q.Message.Chat.ID, q.Message.MessageID, text, telegram.NewButtonsKeyboard(buttons)); err != nil {
+			a.Logger.Warn("Failed to update jobs message", "error", err)
+		}
+		return answer, nil
+	})
+
+	// Регистрация обработчика кнопок команды /settings: сообщение с настройками обновляется на месте
+	a.Bot.RegisterCallbackHandler(usecase.SettingsCallbackPrefix, func(ctx context.Context, q *tgbotapi.CallbackQuery, data string) (string, error) {
+		if q.Message == nil {
+			return i18n.T(ctx, "callback.message_unavailable"), nil
+		}
+		if !q.Message.Chat.IsPrivate() {
+			admin, err := a.Bot.IsChatAdmin(ctx, q.Message.Chat.ID, q.From.ID)
+			if err != nil {
+				return "", err
+			}
+			if !admin {
+				return i18n.T(ctx, usecase.GroupAdminOnlyMessage), nil
+			}
+		}
+		text, buttons, answer, err := a.UseCase.TelegramHandlersUseCase.HandleSettingsAction(ctx, q.Message.Chat.ID, data)
+		if err != nil {
+			return "", err
+		}
+		// Повторное нажатие уже выбранного значения не меняет сообщение, и Telegram отвечает на правку ошибкой
+		if err := a.Bot.EditMessageTextWithKeyboard(q.Message.Chat.ID, q.Message.MessageID, text, telegram.NewButtonsKeyboard(buttons)); err != nil {
+			a.Logger.Warn("Failed to update settings message", "error", err)
+		}
+		return answer, nil
+	})
+
+	// Регистрация обработчика кнопок подтверждения /delete_account: предупреждение заменяется результатом,
+	// чтобы кнопку нельзя было нажать повторно
+	a.Bot.RegisterCallbackHandler(usecase.AccountCallbackPrefix, func(ctx context.Context, q *tgbotapi.CallbackQuery, data string) (string, error) {
+		if q.Message == nil || !q.Message.Chat.IsPrivate() {
+			return i18n.T(ctx, "callback.message_unavailable"), nil
+		}
+		text, err := a.UseCase.AccountDeletionUseCase.HandleAction(ctx, q.Message.Chat.ID, data)
+		if err != nil {
+			return "", err
+		}
+		if err := a.Bot.EditMessageText(q.Message.Chat.ID, q.Message.MessageID, text); err != nil {
+			a.Logger.Warn("Failed to update account deletion message", "error", err)
+		}
+		return "", nil
+	})
+
+	// Регистрация обработчика кнопок настройки из ответов на команды с невыполненными предусловиями
+	a.Bot.RegisterCallbackHandler(telegram.SetupCallbackPrefix, func(ctx context.Context, q *tgbotapi.CallbackQuery, data string) (string, error) {
+		if data != usecase.SetupActionNotion || q.Message == nil {
+			return i18n.T(ctx, "callback.unknown_setting"), nil
+		}
+		if !q.Message.Chat.IsPrivate() {
+			resp, err := a.UseCase.GroupChatUseCase.HandleNotion(ctx, q.Message.Chat.ID, q.From.ID, false, "")
+			if err != nil {
+				return "", err
+			}
+			_, err = a.Bot.SendMarkdownMessage(q.Message.Chat.ID, resp)
+			return "", err
+		}
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleNotion(ctx, q.Message.Chat.ID, "")
+		if err != nil {
+			return "", err
+		}
+		_, err = a.Bot.SendMarkdownMessage(q.Message.Chat.ID, resp)
+		return "", err
+	})
+
+	a.Bot.RegisterCommandHandler("get", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleGet(ctx, m.Chat.ID, args)
+		if err != nil || resp == "" {
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessage(m.Chat.ID, resp)
 		return err
-	}
+	})
 
-	// Логирование успешного запуска приложения
-	a.Logger.Info("Application started successfully")
+	a.Bot.RegisterCommandHandler("delete", a.groupAdminOnly(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleDelete(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessage(m.Chat.ID, resp)
+		return err
+	}))
 
-	return nil
-}
+	a.Bot.RegisterCommandHandler("stats", func(ctx context.Context, m *tgbotapi.Message) error {
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleStats(ctx, m.Chat.ID)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessage(m.Chat.ID, resp)
+		return err
+	})
 
-// Stop останавливает приложение
-func (a *App) Stop(ctx context.Context) error {
-	// Логирование начала остановки приложения
-	a.Logger.Info("Stopping application")
+	a.Bot.RegisterCommandHandler("transcript", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleTranscript(ctx, m.Chat.ID, m.MessageID, args)
+		if err != nil || resp == "" {
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessage(m.Chat.ID, resp)
+		return err
+	})
 
-	// Остановка Telegram бота
-	a.Bot.Stop()
+	a.Bot.RegisterCommandHandler("pdf", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.JobExportUseCase.HandlePDF(ctx, m.Chat.ID, m.MessageID, args)
+		if err != nil || resp == "" {
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessage(m.Chat.ID, resp)
+		return err
+	})
 
-	// Остановка слоя usecase
-	err := a.UseCase.Stop(ctx)
-	if err != nil {
-		a.Logger.Error("Failed to stop usecase layer",
-			"error", err,
-		)
+	a.Bot.RegisterCommandHandler("compare", a.guardCommand(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.ModelComparisonUseCase.HandleCompare(ctx, m.Chat.ID, m.MessageID, args)
+		if err != nil || resp == "" {
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessage(m.Chat.ID, resp)
 		return err
-	}
+	}, entity.CommandRequirementService))
+
+	a.Bot.RegisterCommandHandler("route", a.groupAdminOnly(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.NotionRoutingUseCase.HandleRoute(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessage(m.Chat.ID, resp)
+		return err
+	}))
 
-	// Закрытие соединения с Redis
-	a.RedisClient.Close()
+	a.Bot.RegisterCommandHandler("summary", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleSummary(ctx, m.Chat.ID, m.MessageID, args)
+		if err != nil || resp == "" {
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessage(m.Chat.ID, resp)
+		return err
+	})
+
+	a.Bot.RegisterCommandHandler("retry", a.guardCommand(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleRetry(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}, entity.CommandRequirementService))
+
+	a.Bot.RegisterCommandHandler("find", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleFind(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	})
+
+	// Команда /summarize суммаризирует текст сообщения, на которое она отвечает, или текст после команды
+	a.Bot.RegisterCommandHandler("summarize", a.guardCommand(func(ctx context.Context, m *tgbotapi.Message) error {
+		text, textMessageID := m.CommandArguments(), m.MessageID
+		if m.ReplyToMessage != nil {
+			text, textMessageID = m.ReplyToMessage.Text, m.ReplyToMessage.MessageID
+			if text == "" {
+				text = m.ReplyToMessage.Caption
+			}
+		}
+		resp, err := a.UseCase.QuickSummaryUseCase.Summarize(ctx, m.Chat.ID, textMessageID, text)
+		if err != nil || resp == "" {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}, entity.CommandRequirementService))
+
+	// Команда /trim отвечает на запись: вырезанный промежуток обрабатывается как новая задача,
+	// а ответ становится сообщением о ходе ее обработки
+	a.Bot.RegisterCommandHandler("trim", a.guardCommand(func(ctx context.Context, m *tgbotapi.Message) error {
+		fileUniqueID := ""
+		if m.ReplyToMessage != nil {
+			_, fileUniqueID = audioFileIDs(m.ReplyToMessage)
+		}
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, jobID, err := a.UseCase.TelegramHandlersUseCase.HandleTrim(ctx, m.Chat.ID, m.MessageID, fileUniqueID, args)
+		if err != nil {
+			return err
+		}
+		sent, err := a.Bot.SendMarkdownReplyMessage(m.Chat.ID, m.MessageID, resp)
+		if err != nil || jobID == 0 {
+			return err
+		}
+		a.UseCase.TelegramHandlersUseCase.AttachProgressMessage(ctx, jobID, sent.MessageID)
+		return nil
+	}, entity.CommandRequirementService, entity.CommandRequirementQuota))
+
+	a.Bot.RegisterCommandHandler("summarize_range", a.guardCommand(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleSummarizeRange(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}, entity.CommandRequirementService))
+
+	a.Bot.RegisterCommandHandler("status", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleStatus(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	})
+
+	a.Bot.RegisterCommandHandler("logs", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.JobLogUseCase.HandleLogs(ctx, m.Chat.ID, m.MessageID, args)
+		if err != nil || resp == "" {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	})
+
+	a.Bot.RegisterCommandHandler("autodelete", a.groupAdminOnly(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleAutoDelete(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessage(m.Chat.ID, resp)
+		return err
+	}))
+
+	a.Bot.RegisterCommandHandler("whisper", a.groupAdminOnly(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleWhisper(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}))
+
+	a.Bot.RegisterCommandHandler("language", a.groupAdminOnly(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleLanguage(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}))
+
+	a.Bot.RegisterCommandHandler("settings", func(ctx context.Context, m *tgbotapi.Message) error {
+		text, buttons, err := a.UseCase.TelegramHandlersUseCase.HandleSettings(ctx, m.Chat.ID)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessageWithKeyboard(m.Chat.ID, text, telegram.NewButtonsKeyboard(buttons))
+		return err
+	})
+
+	a.Bot.RegisterCommandHandler("config", a.groupAdminOnly(func(ctx context.Context, m *tgbotapi.Message) error {
+		switch strings.ToLower(strings.TrimSpace(m.CommandArguments())) {
+		case "export":
+			data, err := a.UseCase.TelegramHandlersUseCase.HandleConfigExport(ctx, m.Chat.ID)
+			if err != nil {
+				return err
+			}
+			_, err = a.Bot.SendDocument(m.Chat.ID, usecase.UserConfigFileName, data, i18n.T(ctx, "config.exported"))
+			return err
+		case "import":
+			// Файл берется из сообщения, на которое пользователь ответил командой
+			if m.ReplyToMessage != nil && m.ReplyToMessage.Document != nil {
+				return a.importUserConfig(ctx, m, m.ReplyToMessage.Document)
+			}
+		}
+		_, err := a.Bot.SendMessage(m.Chat.ID, i18n.T(ctx, "config.usage"))
+		return err
+	}))
+
+	// Аккаунт удаляется только из личного чата: в группе команда удалила бы данные всей группы
+	a.Bot.RegisterCommandHandler("delete_account", func(ctx context.Context, m *tgbotapi.Message) error {
+		if !m.Chat.IsPrivate() {
+			_, err := a.Bot.SendMessage(m.Chat.ID, i18n.T(ctx, "delete_account.private_only"))
+			return err
+		}
+		text, buttons, err := a.UseCase.AccountDeletionUseCase.HandleDeleteAccount(ctx, m.Chat.ID)
+		if err != nil {
+			return err
+		}
+		if len(buttons) == 0 {
+			_, err = a.Bot.SendMessage(m.Chat.ID, text)
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessageWithKeyboard(m.Chat.ID, text, telegram.NewButtonsKeyboard(buttons))
+		return err
+	})
+
+	a.Bot.RegisterCommandHandler("podcast", a.groupAdminOnly(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandlePodcast(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}))
+
+	a.Bot.RegisterCommandHandler("webhook", a.groupAdminOnly(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleWebhook(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}))
+
+	// Текст урока можно передать ответом на сообщение с ним
+	a.Bot.RegisterCommandHandler("live", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.LiveSessionUseCase.HandleLive(ctx, m.Chat.ID, m.From.UserName, args)
+		if err != nil || resp == "" {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	})
+
+	a.Bot.RegisterCommandHandler("lesson", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		replyText := ""
+		if m.ReplyToMessage != nil {
+			replyText = m.ReplyToMessage.Text
+		}
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleLesson(ctx, m.Chat.ID, args, replyText)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	})
+
+	// Повестку можно передать ответом на сообщение или текстовый файл с ней
+	a.Bot.RegisterCommandHandler("agenda", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		replyText := ""
+		if m.ReplyToMessage != nil {
+			if m.ReplyToMessage.Document != nil && args == "" {
+				return a.setMeetingAgenda(ctx, m, m.ReplyToMessage.Document)
+			}
+			replyText = m.ReplyToMessage.Text
+		}
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleAgenda(ctx, m.Chat.ID, args, replyText)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	})
+
+	// Команда может быть отправлена из группы, которую владелец назначает каналом дайджеста
+	a.Bot.RegisterCommandHandler("team", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleTeam(ctx, m.From.ID, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	})
+
+	a.Bot.RegisterCommandHandler("reconcile", a.guardCommand(func(ctx context.Context, m *tgbotapi.Message) error {
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleReconcile(ctx, m.Chat.ID)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMarkdownMessage(m.Chat.ID, resp)
+		return err
+	}, entity.CommandRequirementAdmin))
+
+	a.Bot.RegisterCommandHandler("admin", a.guardCommand(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleAdmin(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}, entity.CommandRequirementAdmin))
+
+	a.Bot.RegisterCommandHandler("admin_ban", a.guardCommand(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.AdminModerationUseCase.HandleBan(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}, entity.CommandRequirementAdmin))
+
+	a.Bot.RegisterCommandHandler("admin_broadcast", a.guardCommand(func(ctx context.Context, m *tgbotapi.Message) error {
+		resp, err := a.UseCase.AdminModerationUseCase.HandleBroadcast(ctx, m.Chat.ID, m.CommandArguments())
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}, entity.CommandRequirementAdmin))
+
+	a.Bot.RegisterCommandHandler("admin_maintenance", a.guardCommand(func(ctx context.Context, m *tgbotapi.Message) error {
+		resp, err := a.UseCase.MaintenanceUseCase.HandleMaintenance(ctx, m.Chat.ID, m.CommandArguments())
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}, entity.CommandRequirementAdmin))
+
+	a.Bot.RegisterCommandHandler("admin_stats", a.guardCommand(func(ctx context.Context, m *tgbotapi.Message) error {
+		resp, err := a.UseCase.AdminModerationUseCase.HandleStats(ctx, m.Chat.ID)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}, entity.CommandRequirementAdmin))
+
+	a.Bot.RegisterCommandHandler("admin_logs", a.guardCommand(func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.JobLogUseCase.HandleAdminLogs(ctx, m.Chat.ID, m.MessageID, args)
+		if err != nil || resp == "" {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	}, entity.CommandRequirementAdmin))
+
+	a.Bot.RegisterCommandHandler("ask", func(ctx context.Context, m *tgbotapi.Message) error {
+		args := strings.TrimSpace(m.CommandArguments())
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleAsk(ctx, m.Chat.ID, args)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMessage(m.Chat.ID, resp)
+		return err
+	})
+
+	// Регистрация обработчика текстовых сообщений
+	a.Bot.RegisterMessageHandler(func(ctx context.Context, m *tgbotapi.Message) error {
+		// Стикеры, фото и прочие сообщения без текста пропускаем
+		if m.Text == "" {
+			return nil
+		}
+
+		// Текст сообщения, на которое ответил пользователь
+		replyText := ""
+		if m.ReplyToMessage != nil {
+			replyText = m.ReplyToMessage.Text
+			if replyText == "" {
+				replyText = m.ReplyToMessage.Caption
+			}
+		}
+
+		// Просьба в ответ на запись, например «транскрипцию на английском», выполняется над задачей этой записи.
+		// В группе участники отвечают на записи друг друга, поэтому просьбы принимаются только в личном чате
+		if m.ReplyToMessage != nil && m.Chat.IsPrivate() {
+			if _, fileUniqueID := audioFileIDs(m.ReplyToMessage); fileUniqueID != "" {
+				return a.handleAudioReply(ctx, m, fileUniqueID)
+			}
+		}
+
+		// В группе бот не отвечает на переписку участников: текст суммаризируется только по ответу «суммаризируй»
+		if !m.Chat.IsPrivate() {
+			if replyText == "" || !usecase.IsSummarizeReply(m.Text) {
+				return nil
+			}
+			if err := a.registerGroup(ctx, m.Chat); err != nil {
+				return err
+			}
+		}
+
+		// Ссылка на видео обрабатывается как аудио: звуковая дорожка загружается и ставится в очередь
+		if videoURL, ok := usecase.ParseVideoLink(m.Text); ok && a.UseCase.VideoUseCase.Enabled() {
+			allowed, err := a.checkRequirements(ctx, m.Chat.ID, entity.CommandRequirementService, entity.CommandRequirementQuota)
+			if err != nil || !allowed {
+				return err
+			}
+
+			resp, err := a.UseCase.TelegramHandlersUseCase.HandleVideoLink(ctx, m.Chat.ID, m.From.UserName, m.MessageID, videoURL)
+			if err != nil {
+				return err
+			}
+			_, err = a.Bot.SendMarkdownReplyMessage(m.Chat.ID, m.MessageID, resp)
+			return err
+		}
+
+		// Ссылка на выпуск подкаста обрабатывается так же, как ссылка на видео
+		if episodeURL, ok := usecase.ParsePodcastLink(m.Text); ok && a.UseCase.VideoUseCase.Enabled() {
+			allowed, err := a.checkRequirements(ctx, m.Chat.ID, entity.CommandRequirementService, entity.CommandRequirementQuota)
+			if err != nil || !allowed {
+				return err
+			}
+
+			resp, err := a.UseCase.TelegramHandlersUseCase.HandlePodcastLink(ctx, m.Chat.ID, m.From.UserName, m.MessageID, episodeURL)
+			if err != nil {
+				return err
+			}
+			_, err = a.Bot.SendMarkdownReplyMessage(m.Chat.ID, m.MessageID, resp)
+			return err
+		}
+
+		// Прямая ссылка на аудиофайл загружается ботом и обрабатывается как присланный аудиофайл
+		if audioURL, caption, ok := usecase.ParseAudioLink(m.Text); ok && a.Config.Ingestion.MaxSizeMB > 0 {
+			allowed, err := a.checkRequirements(ctx, m.Chat.ID, entity.CommandRequirementService, entity.CommandRequirementQuota)
+			if err != nil || !allowed {
+				return err
+			}
+
+			resp, jobID, err := a.UseCase.TelegramHandlersUseCase.HandleAudioLink(ctx, m.Chat.ID, m.From.UserName, m.MessageID, audioURL, caption)
+			if err != nil {
+				return err
+			}
+			if jobID == 0 {
+				_, err = a.Bot.SendMarkdownReplyMessage(m.Chat.ID, m.MessageID, resp)
+				return err
+			}
+			sent, err := a.Bot.SendMarkdownReplyMessageWithKeyboard(m.Chat.ID, m.MessageID, resp, telegram.NewAudioProfileKeyboard(ctx, jobID))
+			if err != nil {
+				return err
+			}
+			a.UseCase.TelegramHandlersUseCase.AttachProgressMessage(ctx, jobID, sent.MessageID)
+			a.offerSummaryStyle(ctx, m, jobID)
+			return nil
+		}
+
+		// Вставленный текст становится задачей суммаризации, поэтому во время обслуживания
+		// и сверх дневной квоты не принимается
+		allowed, err := a.checkRequirements(ctx, m.Chat.ID, entity.CommandRequirementService, entity.CommandRequirementQuota)
+		if err != nil || !allowed {
+			return err
+		}
+
+		resp, err := a.UseCase.TelegramHandlersUseCase.HandleTextMessage(ctx, m.Chat.ID, m.From.UserName, m.MessageID, m.Text, replyText)
+		if err != nil {
+			return err
+		}
+		_, err = a.Bot.SendMarkdownReplyMessage(m.Chat.ID, m.MessageID, resp)
+		return err
+	})
+
+	// Меню команд в Telegram на языках интерфейса. Без него команды по-прежнему работают, поэтому ошибку только логируем
+	if err := a.Bot.SetCommands(menuCommands); err != nil {
+		a.Logger.Warn("Failed to set bot commands", "error", err)
+	}
+
+	// Регистрация обработчика публичных ссылок и запуск HTTP сервера
+	a.HTTPServer.RegisterShareResolver(a.UseCase.ShareLinkUseCase.GetSharedJob)
+	a.HTTPServer.RegisterAttachmentResolver(a.UseCase.PhotoAttachmentUseCase.GetAttachment)
+	if a.UseCase.NotionOAuthUseCase.Enabled() {
+		a.HTTPServer.RegisterNotionOAuthHandler(func(ctx context.Context, state, code string) error {
+			err := a.UseCase.NotionOAuthUseCase.Complete(ctx, state, code)
+			if errors.Is(err, usecase.ErrNotionOAuthState) {
+				return web.ErrNotionOAuthExpired
+			}
+			return err
+		})
+	}
+	if a.Config.VoIP.Enabled() {
+		a.HTTPServer.RegisterCallWebhookHandler(a.handleCallWebhook)
+	}
+	a.supervisor.start(ctx, "http_server", func(context.Context) error {
+		return a.HTTPServer.Start()
+	})
+
+	// Логирование успешного запуска приложения
+	a.Logger.Info("Application started successfully")
+
+	// Telegram бот работает до остановки приложения
+	a.supervisor.run(ctx, "telegram_bot", a.runBot)
+
+	return nil
+}
+
+// handleCallWebhook разбирает вебхук VoIP провайдера и отвечает ему сразу: загрузка записи и создание задачи
+// идут в фоне, чтобы провайдер не повторял уведомление из-за долгого ответа
+func (a *App) handleCallWebhook(ctx context.Context, provider string, r *http.Request) (string, error) {
+	event, body, err := a.callReceiver.Parse(provider, r)
+	switch {
+	case errors.Is(err, voip.ErrUnknownProvider):
+		return "", web.ErrCallWebhookProvider
+	case errors.Is(err, voip.ErrInvalidSignature):
+		return "", web.ErrCallWebhookSignature
+	case errors.Is(err, voip.ErrInvalidRequest):
+		return "", web.ErrCallWebhookRequest
+	case err != nil:
+		return "", err
+	}
+
+	if event != nil {
+		go func(ctx context.Context) {
+			if err := a.UseCase.CallRecordingUseCase.Ingest(ctx, event); err != nil {
+				a.Logger.Error("Failed to ingest VoIP call event",
+					"error", err,
+					"provider", provider,
+				)
+			}
+		}(context.WithoutCancel(ctx))
+	}
+
+	return body, nil
+}
+
+// offerSummaryStyle предлагает выбрать стиль краткого содержания новой задачи отдельным сообщением
+// с кнопками, если пользователь не запомнил выбор. Без вопроса стиль берется из настроек, поэтому ошибку
+// отправки только логируем
+func (a *App) offerSummaryStyle(ctx context.Context, m *tgbotapi.Message, jobID int64) {
+	text, buttons := a.UseCase.TelegramHandlersUseCase.SummaryStylePrompt(ctx, m.Chat.ID, jobID)
+	if text == "" {
+		return
+	}
+	if _, err := a.Bot.SendReplyMessageWithKeyboard(m.Chat.ID, m.MessageID, text, telegram.NewButtonsKeyboard(buttons)); err != nil {
+		a.Logger.Warn("Failed to send summary style prompt",
+			"error", err,
+			"job_id", jobID,
+		)
+	}
+}
+
+// handleAudioReply выполняет просьбу пользователя в ответ на запись. Если запись еще не обрабатывалась,
+// она обрабатывается как новая, а просьба становится подписью к ней - указаниями к конспекту
+func (a *App) handleAudioReply(ctx context.Context, m *tgbotapi.Message, fileUniqueID string) error {
+	resp, missing, err := a.UseCase.TelegramHandlersUseCase.HandleAudioReply(ctx, m.Chat.ID, m.MessageID, m.ReplyToMessage.MessageID, fileUniqueID, m.Text)
+	if err != nil {
+		return err
+	}
+	if !missing {
+		_, err = a.Bot.SendReplyMessage(m.Chat.ID, m.MessageID, resp)
+		return err
+	}
+
+	source := *m.ReplyToMessage
+	source.From = m.From
+	source.Caption = m.Text
+	a.Bot.HandleMediaMessage(ctx, &source)
+	return nil
+}
+
+// runBot получает обновления Telegram опросом или через вебхук до остановки бота
+func (a *App) runBot(context.Context) error {
+	if a.Config.Telegram.Mode == config.TelegramModeWebhook {
+		return a.Bot.StartWebhook(telegram.WebhookOptions{
+			URL:            a.Config.Telegram.WebhookURL,
+			ListenAddr:     a.Config.Telegram.WebhookListenAddr,
+			Secret:         a.Config.Telegram.WebhookSecret,
+			MaxConnections: a.Config.Telegram.WebhookMaxConnections,
+		})
+	}
+	return a.Bot.Start()
+}
+
+// alertAdmins отправляет всем администраторам сообщение id из каталога i18n на языке каждого из них;
+// ошибки отправки только логируются
+func (a *App) alertAdmins(id string, args ...interface{}) {
+	ctx := context.Background()
+	for _, adminID := range a.Config.Telegram.AdminIDs {
+		language := a.UseCase.InterfaceLanguageUseCase.Resolve(ctx, adminID, "")
+		if _, err := a.Bot.SendMessage(adminID, i18n.Translate(language, id, args...)); err != nil {
+			a.Logger.Warn("Failed to send admin alert",
+				"error", err,
+				"admin_id", adminID,
+			)
+		}
+	}
+}
+
+// menuCommands - команды меню Telegram в порядке показа. Служебные команды администраторов в меню не попадают
+var menuCommands = []string{
+	"start",
+	"help",
+	"notion",
+	"jobs",
+	"settings",
+	"get",
+	"transcript",
+	"summary",
+	"pdf",
+	"compare",
+	"summarize",
+	"summarize_range",
+	"trim",
+	"status",
+	"logs",
+	"stats",
+	"retry",
+	"find",
+	"ask",
+	"share",
+	"unshare",
+	"delete",
+	"autodelete",
+	"language",
+	"agenda",
+	"live",
+	"lesson",
+	"team",
+	"webhook",
+	"route",
+	"config",
+	"delete_account",
+}
+
+// guardCommand оборачивает обработчик команды проверкой предусловий: если они не выполнены,
+// пользователь получает стандартный ответ, а сам обработчик не вызывается
+func (a *App) guardCommand(handler telegram.CommandHandler, requirements ...entity.CommandRequirement) telegram.CommandHandler {
+	return func(ctx context.Context, m *tgbotapi.Message) error {
+		ok, err := a.checkRequirements(ctx, m.Chat.ID, requirements...)
+		if err != nil || !ok {
+			return err
+		}
+		return handler(ctx, m)
+	}
+}
+
+// groupAdminOnly оборачивает обработчик команды, которая меняет настройки: в группе ее выполняют
+// только администраторы группы
+func (a *App) groupAdminOnly(handler telegram.CommandHandler) telegram.CommandHandler {
+	return func(ctx context.Context, m *tgbotapi.Message) error {
+		if m.Chat.IsPrivate() {
+			return handler(ctx, m)
+		}
+		admin, err := a.isGroupAdmin(ctx, m)
+		if err != nil {
+			return err
+		}
+		if !admin {
+			_, err = a.Bot.SendReplyMessage(m.Chat.ID, m.MessageID, i18n.T(ctx, usecase.GroupAdminOnlyMessage))
+			return err
+		}
+		return handler(ctx, m)
+	}
+}
+
+// isGroupAdmin сообщает, что сообщение в группе отправил ее администратор. Анонимные администраторы
+// пишут от имени самой группы
+func (a *App) isGroupAdmin(ctx context.Context, m *tgbotapi.Message) (bool, error) {
+	if m.SenderChat != nil && m.SenderChat.ID == m.Chat.ID {
+		return true, nil
+	}
+	return a.Bot.IsChatAdmin(ctx, m.Chat.ID, m.From.ID)
+}
+
+// registerGroup запоминает группу, из которой пришло сообщение, чтобы у нее были свои настройки и задачи.
+// Группы, в которые бота добавили до появления поддержки групп, регистрируются так при первом сообщении
+func (a *App) registerGroup(ctx context.Context, chat *tgbotapi.Chat) error {
+	if chat.IsPrivate() {
+		return nil
+	}
+	_, err := a.UseCase.GroupChatUseCase.Register(ctx, chat.ID, entity.ChatType(chat.Type), chat.Title)
+	return err
+}
+
+// handleGroupNotion обрабатывает команду /notion в группе: администратор привязывает к группе
+// базу Notion, подключенную в личном чате с ботом
+func (a *App) handleGroupNotion(ctx context.Context, m *tgbotapi.Message, args string) error {
+	if err := a.registerGroup(ctx, m.Chat); err != nil {
+		return err
+	}
+	admin := false
+	if args != "" {
+		var err error
+		if admin, err = a.isGroupAdmin(ctx, m); err != nil {
+			return err
+		}
+	}
+	resp, err := a.UseCase.GroupChatUseCase.HandleNotion(ctx, m.Chat.ID, m.From.ID, admin, args)
+	if err != nil {
+		return err
+	}
+	_, err = a.Bot.SendMarkdownReplyMessage(m.Chat.ID, m.MessageID, resp)
+	return err
+}
+
+// checkRequirements проверяет предусловия и, если они не выполнены, отправляет ответ с кнопкой настройки
+func (a *App) checkRequirements(ctx context.Context, chatID int64, requirements ...entity.CommandRequirement) (bool, error) {
+	prompt, err := a.UseCase.TelegramHandlersUseCase.CheckRequirements(ctx, chatID, requirements...)
+	if err != nil {
+		return false, err
+	}
+	if prompt == nil {
+		return true, nil
+	}
+
+	if prompt.SetupAction != "" {
+		_, err = a.Bot.SendMarkdownMessageWithKeyboard(chatID, prompt.Text, telegram.NewSetupKeyboard(prompt.SetupLabel, prompt.SetupAction))
+	} else {
+		_, err = a.Bot.SendMarkdownMessage(chatID, prompt.Text)
+	}
+	return false, err
+}
+
+// importUserConfig загружает файл настроек из документа и отвечает пользователю итогами загрузки
+func (a *App) importUserConfig(ctx context.Context, m *tgbotapi.Message, document *tgbotapi.Document) error {
+	data, err := a.Bot.DownloadDocument(ctx, document, usecase.MaxUserConfigSize)
+	if err != nil {
+		a.Logger.Warn("Failed to download user config", "error", err)
+		_, err = a.Bot.SendReplyMessage(m.Chat.ID, m.MessageID, i18n.T(ctx, "config.too_large"))
+		return err
+	}
+
+	resp, err := a.UseCase.TelegramHandlersUseCase.HandleConfigImport(ctx, m.Chat.ID, data)
+	if err != nil {
+		return err
+	}
+	_, err = a.Bot.SendReplyMessage(m.Chat.ID, m.MessageID, resp)
+	return err
+}
+
+// setMeetingAgenda загружает текстовый файл с повесткой встречи и сохраняет ее для следующей записи
+func (a *App) setMeetingAgenda(ctx context.Context, m *tgbotapi.Message, document *tgbotapi.Document) error {
+	data, err := a.Bot.DownloadDocument(ctx, document, usecase.MaxMeetingAgendaSize)
+	if err != nil {
+		a.Logger.Warn("Failed to download meeting agenda", "error", err)
+		_, err = a.Bot.SendReplyMessage(m.Chat.ID, m.MessageID, i18n.T(ctx, "agenda.too_large"))
+		return err
+	}
+
+	resp, err := a.UseCase.TelegramHandlersUseCase.HandleAgenda(ctx, m.Chat.ID, "", string(data))
+	if err != nil {
+		return err
+	}
+	_, err = a.Bot.SendReplyMessage(m.Chat.ID, m.MessageID, resp)
+	return err
+}
+
+// audioFileIDs возвращает идентификатор файла для загрузки и постоянный идентификатор файла (file_unique_id)
+// голосового сообщения, аудио, кружка или видео; постоянный идентификатор одинаков у пересланных копий файла
+func audioFileIDs(m *tgbotapi.Message) (string, string) {
+	switch {
+	case m.Voice != nil:
+		return m.Voice.FileID, m.Voice.FileUniqueID
+	case m.Audio != nil:
+		return m.Audio.FileID, m.Audio.FileUniqueID
+	case m.VideoNote != nil:
+		return m.VideoNote.FileID, m.VideoNote.FileUniqueID
+	case m.Video != nil:
+		return m.Video.FileID, m.Video.FileUniqueID
+	}
+	return "", ""
+}
+
+// captionCommand разбирает подпись к документу вида «/команда аргументы» и возвращает команду без «/»
+// и упоминания бота и аргументы в нижнем регистре; для подписи без команды возвращается пустая команда
+func captionCommand(caption string) (string, string) {
+	fields := strings.Fields(strings.ToLower(caption))
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", ""
+	}
+	command, _, _ := strings.Cut(strings.TrimPrefix(fields[0], "/"), "@")
+	return command, strings.Join(fields[1:], " ")
+}
+
+// Stop останавливает приложение
+func (a *App) Stop(ctx context.Context) error {
+	// Логирование начала остановки приложения
+	a.Logger.Info("Stopping application")
+
+	// Остановленные ниже подсистемы не должны перезапускаться
+	a.supervisor.stop()
+
+	// Остановка Telegram бота
+	a.Bot.Stop()
+
+	// Остановка HTTP сервера
+	if err := a.HTTPServer.Stop(ctx); err != nil {
+		a.Logger.Error("Failed to stop HTTP server",
+			"error", err,
+		)
+	}
+
+	// Остановка слоя usecase
+	err := a.UseCase.Stop(ctx)
+	if err != nil {
+		a.Logger.Error("Failed to stop usecase layer",
+			"error", err,
+		)
+		return err
+	}
 
-	// Закрытие соединения с PostgreSQL
-	a.PostgresDB.Close()
+	// Закрытие соединений с хранилищем данных
+	a.storage.close()
 
 	// Логирование успешной остановки приложения
 	a.Logger.Info("Application stopped successfully")
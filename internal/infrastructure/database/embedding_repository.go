@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// EmbeddingRepositoryPG реализует интерфейс EmbeddingRepository для PostgreSQL с расширением pgvector
+type EmbeddingRepositoryPG struct {
+	db *PostgresDB
+}
+
+// NewEmbeddingRepository создает новый репозиторий для работы с эмбеддингами заметок
+func NewEmbeddingRepository(db *PostgresDB) repository.EmbeddingRepository {
+	return &EmbeddingRepositoryPG{db: db}
+}
+
+// ReplaceForJob заменяет все эмбеддинги задачи переданными
+func (r *EmbeddingRepositoryPG) ReplaceForJob(ctx context.Context, jobID int64, embeddings []*entity.NoteEmbedding) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Удаление старых эмбеддингов задачи
+	_, err = tx.Exec(ctx, `DELETE FROM note_embeddings WHERE job_id = $1`, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to delete embeddings: %w", err)
+	}
+
+	query := `
+		INSERT INTO note_embeddings (job_id, user_id, kind, chunk_index, content, embedding, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6::vector, $7)
+		RETURNING id
+	`
+
+	now := time.Now()
+	for _, e := range embeddings {
+		e.CreatedAt = now
+		err = tx.QueryRow(
+			ctx,
+			query,
+			jobID,
+			e.UserID,
+			e.Kind,
+			e.ChunkIndex,
+			e.Content,
+			vectorLiteral(e.Embedding),
+			e.CreatedAt,
+		).Scan(&e.ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert embedding: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SearchSimilar возвращает ближайшие к вектору фрагменты заметок пользователя по косинусному расстоянию
+func (r *EmbeddingRepositoryPG) SearchSimilar(ctx context.Context, userID int64, embedding []float32, limit int) ([]*entity.NoteEmbedding, error) {
+	query := `
+		SELECT id, job_id, user_id, kind, chunk_index, content, embedding <=> $2::vector AS distance, created_at
+		FROM note_embeddings
+		WHERE user_id = $1
+		ORDER BY distance
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, vectorLiteral(embedding), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*entity.NoteEmbedding
+	for rows.Next() {
+		e := &entity.NoteEmbedding{}
+		err := rows.Scan(
+			&e.ID,
+			&e.JobID,
+			&e.UserID,
+			&e.Kind,
+			&e.ChunkIndex,
+			&e.Content,
+			&e.Distance,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan embedding: %w", err)
+		}
+		results = append(results, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating embeddings: %w", err)
+	}
+
+	return results, nil
+}
+
+// vectorLiteral формирует текстовое представление вектора в формате pgvector: [1,2,3]
+func vectorLiteral(embedding []float32) string {
+	var builder strings.Builder
+	builder.WriteByte('[')
+	for i, v := range embedding {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	}
+	builder.WriteByte(']')
+	return builder.String()
+}
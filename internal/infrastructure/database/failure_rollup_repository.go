@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// FailureRollupRepositoryPG реализует интерфейс FailureRollupRepository для PostgreSQL
+type FailureRollupRepositoryPG struct {
+	db *PostgresDB
+}
+
+// NewFailureRollupRepository создает новый репозиторий для работы с дневными сводками ошибок
+func NewFailureRollupRepository(db *PostgresDB) repository.FailureRollupRepository {
+	return &FailureRollupRepositoryPG{db: db}
+}
+
+// ReplaceDay заменяет сводки за день переданными
+func (r *FailureRollupRepositoryPG) ReplaceDay(ctx context.Context, day time.Time, rollups []*entity.FailureRollup) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Удаление прежних сводок за день
+	_, err = tx.Exec(ctx, `DELETE FROM failure_rollups WHERE day = $1`, day)
+	if err != nil {
+		return fmt.Errorf("failed to delete failure rollups: %w", err)
+	}
+
+	query := `
+		INSERT INTO failure_rollups (day, stage, provider, category, failures, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	now := time.Now()
+	for _, rollup := range rollups {
+		_, err = tx.Exec(ctx, query, day, rollup.Stage, rollup.Provider, rollup.Category, rollup.Failures, now)
+		if err != nil {
+			return fmt.Errorf("failed to insert failure rollup: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetSince возвращает сводки начиная с дня since в порядке дней
+func (r *FailureRollupRepositoryPG) GetSince(ctx context.Context, since time.Time) ([]*entity.FailureRollup, error) {
+	query := `
+		SELECT day, stage, provider, category, failures
+		FROM failure_rollups
+		WHERE day >= $1
+		ORDER BY day, stage, provider, category
+	`
+
+	rows, err := r.db.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failure rollups: %w", err)
+	}
+	defer rows.Close()
+
+	rollups := make([]*entity.FailureRollup, 0)
+	for rows.Next() {
+		rollup := &entity.FailureRollup{}
+		err := rows.Scan(
+			&rollup.Day,
+			&rollup.Stage,
+			&rollup.Provider,
+			&rollup.Category,
+			&rollup.Failures,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan failure rollup: %w", err)
+		}
+		rollups = append(rollups, rollup)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate failure rollups: %w", err)
+	}
+
+	return rollups, nil
+}
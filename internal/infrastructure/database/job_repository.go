@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -13,65 +14,263 @@ import (
 
 // JobRepositoryPG реализует интерфейс JobRepository для PostgreSQL
 type JobRepositoryPG struct {
-	db *PostgresDB
+	db         *PostgresDB
+	compressor textCompressor
 }
 
-// NewJobRepository создает новый репозиторий для работы с задачами
-func NewJobRepository(db *PostgresDB) repository.JobRepository {
-	return &JobRepositoryPG{db: db}
+// NewJobRepository создает новый репозиторий для работы с задачами. Транскрипции и резюме длиннее
+// compressMinSize байт сохраняются сжатыми, 0 отключает сжатие новых текстов
+func NewJobRepository(db *PostgresDB, compressMinSize int) repository.JobRepository {
+	return &JobRepositoryPG{db: db, compressor: textCompressor{minSize: compressMinSize}}
 }
 
+// jobInsertQuery - запрос добавления задачи с аргументами из jobInsertArgs
+const jobInsertQuery = `
+	INSERT INTO jobs (
+		user_id, type, status, audio_file_path, file_name, transcription, summary,
+		notion_page_id, notion_database_id, source_message_id, instructions, audio_profile, created_at, updated_at,
+		completed_at, error_message, file_unique_id, transcription_gz, summary_gz
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12, $13, $14, $15, $16, NULLIF($17, ''), $18, $19)
+	RETURNING id
+`
+
+// jobCopyColumns - колонки, заполняемые при загрузке задач через COPY, в порядке jobCopyRow
+var jobCopyColumns = []string{
+	"user_id", "type", "status", "audio_file_path", "file_name", "transcription", "summary",
+	"notion_page_id", "notion_database_id", "source_message_id", "instructions", "audio_profile", "created_at", "updated_at",
+	"completed_at", "error_message", "file_unique_id", "transcription_gz", "summary_gz",
+}
+
+// jobImportStagingQueries создают временную таблицу jobs_import с колонками jobCopyColumns, в которую Import
+// загружает задачи через COPY. Статус в ней хранится текстом: COPY не приводит текст к перечислению job_status,
+// поэтому статус приводится при переносе задач в jobs
+var jobImportStagingQueries = []string{
+	`CREATE TEMP TABLE jobs_import ON COMMIT DROP AS
+		SELECT user_id, type, status, audio_file_path, file_name, transcription, summary,
+			notion_page_id, notion_database_id, source_message_id, instructions, audio_profile, created_at, updated_at,
+			completed_at, error_message, file_unique_id, transcription_gz, summary_gz
+		FROM jobs WITH NO DATA`,
+	`ALTER TABLE jobs_import ALTER COLUMN status TYPE TEXT`,
+}
+
+// jobImportQuery переносит загруженные задачи из jobs_import в jobs. Пустые указания и ID файла
+// заменяются на NULL, как в jobInsertQuery
+const jobImportQuery = `
+	INSERT INTO jobs (
+		user_id, type, status, audio_file_path, file_name, transcription, summary,
+		notion_page_id, notion_database_id, source_message_id, instructions, audio_profile, created_at, updated_at,
+		completed_at, error_message, file_unique_id, transcription_gz, summary_gz
+	)
+	SELECT user_id, type, status::job_status, audio_file_path, file_name, transcription, summary,
+		notion_page_id, notion_database_id, source_message_id, NULLIF(instructions, ''), audio_profile, created_at, updated_at,
+		completed_at, error_message, NULLIF(file_unique_id, ''), transcription_gz, summary_gz
+	FROM jobs_import
+`
+
 // Create создает новую задачу
 func (r *JobRepositoryPG) Create(ctx context.Context, job *entity.Job) error {
+	prepareNewJob(job, time.Now())
+
+	texts, err := r.compressor.packJobTexts(job)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRow(ctx, jobInsertQuery, jobInsertArgs(job, texts)...).Scan(&job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	return nil
+}
+
+// CreateBatch создает задачи одним пакетом запросов в транзакции и заполняет их ID.
+// Если не удалось создать хотя бы одну задачу, не создается ни одна
+func (r *JobRepositoryPG) CreateBatch(ctx context.Context, jobs []*entity.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	batch := &pgx.Batch{}
+	for _, job := range jobs {
+		prepareNewJob(job, now)
+		texts, err := r.compressor.packJobTexts(job)
+		if err != nil {
+			return err
+		}
+		batch.Queue(jobInsertQuery, jobInsertArgs(job, texts)...)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	results := tx.SendBatch(ctx, batch)
+	for _, job := range jobs {
+		if err := results.QueryRow().Scan(&job.ID); err != nil {
+			results.Close()
+			return fmt.Errorf("failed to create job: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("failed to create jobs: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Import загружает задачи через COPY и возвращает число загруженных задач. Статус, время создания и ID файла
+// задач сохраняются, поэтому импортированный архив сохраняет завершенные задачи и проверку дубликатов.
+// ID задачам не присваиваются, поэтому Import подходит для импорта архива, а не для постановки задач в очередь
+func (r *JobRepositoryPG) Import(ctx context.Context, jobs []*entity.Job) (int64, error) {
+	if len(jobs) == 0 {
+		return 0, nil
+	}
+
 	now := time.Now()
+	rows := make([][]interface{}, 0, len(jobs))
+	for _, job := range jobs {
+		prepareImportedJob(job, now)
+		texts, err := r.compressor.packJobTexts(job)
+		if err != nil {
+			return 0, err
+		}
+		rows = append(rows, jobCopyRow(job, texts))
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, query := range jobImportStagingQueries {
+		if _, err := tx.Exec(ctx, query); err != nil {
+			return 0, fmt.Errorf("failed to create import table: %w", err)
+		}
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"jobs_import"}, jobCopyColumns, pgx.CopyFromRows(rows)); err != nil {
+		return 0, fmt.Errorf("failed to import jobs: %w", err)
+	}
+	tag, err := tx.Exec(ctx, jobImportQuery)
+	if err != nil {
+		return 0, fmt.Errorf("failed to import jobs: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// prepareNewJob заполняет время создания, начальный статус и значения по умолчанию новой задачи
+func prepareNewJob(job *entity.Job, now time.Time) {
 	job.CreatedAt = now
 	job.UpdatedAt = now
 	job.Status = entity.JobStatusPending
 
-	query := `
-		INSERT INTO jobs (
-			user_id, status, audio_file_path, file_name, transcription, summary,
-			notion_page_id, notion_database_id, created_at, updated_at, completed_at, error_message
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		RETURNING id
-	`
+	if job.Type == "" {
+		job.Type = entity.JobTypeTranscription
+	}
+	if job.AudioProfile == "" {
+		job.AudioProfile = entity.AudioProfileAuto
+	}
+}
 
-	err := r.db.QueryRow(
-		ctx,
-		query,
+// prepareImportedJob заполняет значения по умолчанию импортируемой задачи. В отличие от prepareNewJob,
+// статус и время создания задачи сохраняются, если они заданы
+func prepareImportedJob(job *entity.Job, now time.Time) {
+	status, createdAt, updatedAt := job.Status, job.CreatedAt, job.UpdatedAt
+	prepareNewJob(job, now)
+
+	if status != "" {
+		job.Status = status
+	}
+	if !createdAt.IsZero() {
+		job.CreatedAt = createdAt
+	}
+	if !updatedAt.IsZero() {
+		job.UpdatedAt = updatedAt
+	}
+}
+
+// jobInsertArgs возвращает аргументы jobInsertQuery с транскрипцией и резюме в виде для хранения
+func jobInsertArgs(job *entity.Job, texts storedJobTexts) []interface{} {
+	return []interface{}{
 		job.UserID,
+		job.Type,
 		job.Status,
 		job.AudioFilePath,
 		job.FileName,
-		job.Transcription,
-		job.Summary,
+		texts.transcription,
+		texts.summary,
 		job.NotionPageID,
 		job.NotionDatabaseID,
+		job.SourceMessageID,
+		job.Instructions,
+		job.AudioProfile,
 		job.CreatedAt,
 		job.UpdatedAt,
 		job.CompletedAt,
 		job.ErrorMessage,
-	).Scan(&job.ID)
-
-	if err != nil {
-		return fmt.Errorf("failed to create job: %w", err)
+		job.FileUniqueID,
+		texts.transcriptionGz,
+		texts.summaryGz,
 	}
+}
 
-	return nil
+// jobCopyRow возвращает строку COPY для задачи в порядке jobCopyColumns
+func jobCopyRow(job *entity.Job, texts storedJobTexts) []interface{} {
+	return []interface{}{
+		job.UserID,
+		job.Type,
+		string(job.Status),
+		job.AudioFilePath,
+		job.FileName,
+		texts.transcription,
+		texts.summary,
+		job.NotionPageID,
+		job.NotionDatabaseID,
+		job.SourceMessageID,
+		job.Instructions,
+		job.AudioProfile,
+		job.CreatedAt,
+		job.UpdatedAt,
+		job.CompletedAt,
+		job.ErrorMessage,
+		job.FileUniqueID,
+		texts.transcriptionGz,
+		texts.summaryGz,
+	}
 }
 
 // GetByID возвращает задачу по её ID
 func (r *JobRepositoryPG) GetByID(ctx context.Context, id int64) (*entity.Job, error) {
 	query := `
 		SELECT 
-			id, user_id, status, audio_file_path, file_name, duration, transcription, summary,
-			notion_page_id, notion_database_id, created_at, updated_at, completed_at, error_message
+			id, user_id, type, status, audio_file_path, file_name, duration, transcription, transcription_gz, summary, summary_gz,
+			notion_page_id, notion_database_id, COALESCE(source_message_id, 0), COALESCE(instructions, ''),
+			audio_profile, COALESCE(summary_model, ''), COALESCE(summary_cost, 0), summary_tokens,
+			created_at, updated_at, completed_at, error_message, COALESCE(failed_stage, ''),
+			COALESCE(notion_upload_page_id, ''), notion_upload_cursor, COALESCE(progress_message_id, 0),
+			COALESCE(summary_style, ''), COALESCE(processed_audio_path, ''), COALESCE(processed_audio_profile, ''),
+			COALESCE(duplicate_of_job_id, 0), COALESCE(duplicate_similarity, 0), speaker_segments
 		FROM jobs
 		WHERE id = $1
 	`
 
 	job := &entity.Job{}
+	var packed packedJobTexts
+	var segmentsJSON []byte
 	err := r.db.QueryRow(
 		ctx,
 		query,
@@ -79,18 +278,37 @@ func (r *JobRepositoryPG) GetByID(ctx context.Context, id int64) (*entity.Job, e
 	).Scan(
 		&job.ID,
 		&job.UserID,
+		&job.Type,
 		&job.Status,
 		&job.AudioFilePath,
 		&job.FileName,
 		&job.Duration,
 		&job.Transcription,
+		&packed.transcription,
 		&job.Summary,
+		&packed.summary,
 		&job.NotionPageID,
 		&job.NotionDatabaseID,
+		&job.SourceMessageID,
+		&job.Instructions,
+		&job.AudioProfile,
+		&job.SummaryModel,
+		&job.SummaryCost,
+		&job.SummaryTokens,
 		&job.CreatedAt,
 		&job.UpdatedAt,
 		&job.CompletedAt,
 		&job.ErrorMessage,
+		&job.FailedStage,
+		&job.NotionUploadPageID,
+		&job.NotionUploadCursor,
+		&job.ProgressMessageID,
+		&job.SummaryStyle,
+		&job.ProcessedAudioPath,
+		&job.ProcessedAudioProfile,
+		&job.DuplicateOfJobID,
+		&job.DuplicateSimilarity,
+		&segmentsJSON,
 	)
 
 	if err != nil {
@@ -99,6 +317,14 @@ func (r *JobRepositoryPG) GetByID(ctx context.Context, id int64) (*entity.Job, e
 		}
 		return nil, fmt.Errorf("failed to get job: %w", err)
 	}
+	if err := packed.unpack(job); err != nil {
+		return nil, err
+	}
+	if len(segmentsJSON) > 0 {
+		if err := json.Unmarshal(segmentsJSON, &job.Segments); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal speaker segments: %w", err)
+		}
+	}
 
 	return job, nil
 }
@@ -107,8 +333,9 @@ func (r *JobRepositoryPG) GetByID(ctx context.Context, id int64) (*entity.Job, e
 func (r *JobRepositoryPG) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*entity.Job, error) {
 	query := `
 		SELECT 
-			id, user_id, status, audio_file_path, file_name, duration, transcription, summary,
-			notion_page_id, notion_database_id, created_at, updated_at, completed_at, error_message
+			id, user_id, type, status, audio_file_path, file_name, duration, transcription, transcription_gz, summary, summary_gz,
+			notion_page_id, notion_database_id, COALESCE(source_message_id, 0), COALESCE(instructions, ''),
+			audio_profile, created_at, updated_at, completed_at, error_message
 		FROM jobs
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -124,17 +351,98 @@ func (r *JobRepositoryPG) GetByUserID(ctx context.Context, userID int64, limit,
 	var jobs []*entity.Job
 	for rows.Next() {
 		job := &entity.Job{}
+		var packed packedJobTexts
+		err := rows.Scan(
+			&job.ID,
+			&job.UserID,
+			&job.Type,
+			&job.Status,
+			&job.AudioFilePath,
+			&job.FileName,
+			&job.Duration,
+			&job.Transcription,
+			&packed.transcription,
+			&job.Summary,
+			&packed.summary,
+			&job.NotionPageID,
+			&job.NotionDatabaseID,
+			&job.SourceMessageID,
+			&job.Instructions,
+			&job.AudioProfile,
+			&job.CreatedAt,
+			&job.UpdatedAt,
+			&job.CompletedAt,
+			&job.ErrorMessage,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		if err := packed.unpack(job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// jobStatusFilterConditions - условия на статус задачи для отборов списка задач
+var jobStatusFilterConditions = map[entity.JobStatusFilter]string{
+	entity.JobStatusFilterAll:       "TRUE",
+	entity.JobStatusFilterActive:    "status::text NOT IN ('completed', 'failed')",
+	entity.JobStatusFilterCompleted: "status::text = 'completed'",
+	entity.JobStatusFilterFailed:    "status::text = 'failed'",
+}
+
+// GetByUserIDAndStatus возвращает задачи пользователя, подходящие под отбор по статусу, от новых к старым
+func (r *JobRepositoryPG) GetByUserIDAndStatus(ctx context.Context, userID int64, filter entity.JobStatusFilter, limit, offset int) ([]*entity.Job, error) {
+	condition, ok := jobStatusFilterConditions[filter]
+	if !ok {
+		return nil, fmt.Errorf("unknown job status filter %q", filter)
+	}
+
+	query := `
+		SELECT
+			id, user_id, type, status, audio_file_path, file_name, duration, transcription, transcription_gz, summary, summary_gz,
+			notion_page_id, notion_database_id, COALESCE(source_message_id, 0), COALESCE(instructions, ''),
+			audio_profile, created_at, updated_at, completed_at, error_message
+		FROM jobs
+		WHERE user_id = $1 AND ` + condition + `
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*entity.Job
+	for rows.Next() {
+		job := &entity.Job{}
+		var packed packedJobTexts
 		err := rows.Scan(
 			&job.ID,
 			&job.UserID,
+			&job.Type,
 			&job.Status,
 			&job.AudioFilePath,
 			&job.FileName,
 			&job.Duration,
 			&job.Transcription,
+			&packed.transcription,
 			&job.Summary,
+			&packed.summary,
 			&job.NotionPageID,
 			&job.NotionDatabaseID,
+			&job.SourceMessageID,
+			&job.Instructions,
+			&job.AudioProfile,
 			&job.CreatedAt,
 			&job.UpdatedAt,
 			&job.CompletedAt,
@@ -143,6 +451,9 @@ func (r *JobRepositoryPG) GetByUserID(ctx context.Context, userID int64, limit,
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan job: %w", err)
 		}
+		if err := packed.unpack(job); err != nil {
+			return nil, err
+		}
 		jobs = append(jobs, job)
 	}
 
@@ -157,6 +468,11 @@ func (r *JobRepositoryPG) GetByUserID(ctx context.Context, userID int64, limit,
 func (r *JobRepositoryPG) Update(ctx context.Context, job *entity.Job) error {
 	job.UpdatedAt = time.Now()
 
+	texts, err := r.compressor.packJobTexts(job)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE jobs
 		SET 
@@ -165,24 +481,28 @@ func (r *JobRepositoryPG) Update(ctx context.Context, job *entity.Job) error {
 			file_name = $3,
 			duration = $4,
 			transcription = $5, 
-			summary = $6,
-			notion_page_id = $7, 
-			notion_database_id = $8, 
-			updated_at = $9, 
-			completed_at = $10, 
-			error_message = $11
-		WHERE id = $12
+			transcription_gz = $6,
+			summary = $7,
+			summary_gz = $8,
+			notion_page_id = $9, 
+			notion_database_id = $10, 
+			updated_at = $11, 
+			completed_at = $12, 
+			error_message = $13
+		WHERE id = $14
 	`
 
-	_, err := r.db.Exec(
+	_, err = r.db.Exec(
 		ctx,
 		query,
 		job.Status,
 		job.AudioFilePath,
 		job.FileName,
 		job.Duration,
-		job.Transcription,
-		job.Summary,
+		texts.transcription,
+		texts.transcriptionGz,
+		texts.summary,
+		texts.summaryGz,
 		job.NotionPageID,
 		job.NotionDatabaseID,
 		job.UpdatedAt,
@@ -207,13 +527,15 @@ func (r *JobRepositoryPG) UpdateStatus(ctx context.Context, id int64, status ent
 		completedAt = &now
 	}
 
+	// Этап ошибки остается только у задачи с ошибкой: перезапущенная задача его теряет
 	query := `
 		UPDATE jobs
-		SET status = $1, updated_at = $2, completed_at = $3, error_message = $4
+		SET status = $1, updated_at = $2, completed_at = $3, error_message = $4,
+			failed_stage = CASE WHEN $6 THEN failed_stage END
 		WHERE id = $5
 	`
 
-	_, err := r.db.Exec(ctx, query, status, now, completedAt, errorMessage, id)
+	_, err := r.db.Exec(ctx, query, status, now, completedAt, errorMessage, id, status == entity.JobStatusFailed)
 	if err != nil {
 		return fmt.Errorf("failed to update job status: %w", err)
 	}
@@ -221,15 +543,38 @@ func (r *JobRepositoryPG) UpdateStatus(ctx context.Context, id int64, status ent
 	return nil
 }
 
+// MarkFailed переводит задачу в статус ошибки и запоминает этап, на котором она произошла
+func (r *JobRepositoryPG) MarkFailed(ctx context.Context, id int64, stage entity.JobStageName, errorMessage string) error {
+	now := time.Now()
+
+	query := `
+		UPDATE jobs
+		SET status = $1, failed_stage = $2, error_message = $3, completed_at = $4, updated_at = $4
+		WHERE id = $5
+	`
+
+	_, err := r.db.Exec(ctx, query, entity.JobStatusFailed, stage, errorMessage, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark job failed: %w", err)
+	}
+
+	return nil
+}
+
 // SetTranscription устанавливает транскрипцию для задачи
 func (r *JobRepositoryPG) SetTranscription(ctx context.Context, id int64, transcription string) error {
+	plain, packed, err := r.compressor.pack(transcription)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE jobs
-		SET transcription = $1, updated_at = $2
-		WHERE id = $3
+		SET transcription = $1, transcription_gz = $2, updated_at = $3
+		WHERE id = $4
 	`
 
-	_, err := r.db.Exec(ctx, query, transcription, time.Now(), id)
+	_, err = r.db.Exec(ctx, query, plain, packed, time.Now(), id)
 	if err != nil {
 		return fmt.Errorf("failed to set transcription: %w", err)
 	}
@@ -239,13 +584,18 @@ func (r *JobRepositoryPG) SetTranscription(ctx context.Context, id int64, transc
 
 // SetSummary устанавливает суммаризацию для задачи
 func (r *JobRepositoryPG) SetSummary(ctx context.Context, id int64, summary string) error {
+	plain, packed, err := r.compressor.pack(summary)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE jobs
-		SET summary = $1, updated_at = $2
-		WHERE id = $3
+		SET summary = $1, summary_gz = $2, updated_at = $3
+		WHERE id = $4
 	`
 
-	_, err := r.db.Exec(ctx, query, summary, time.Now(), id)
+	_, err = r.db.Exec(ctx, query, plain, packed, time.Now(), id)
 	if err != nil {
 		return fmt.Errorf("failed to set summary: %w", err)
 	}
@@ -253,6 +603,22 @@ func (r *JobRepositoryPG) SetSummary(ctx context.Context, id int64, summary stri
 	return nil
 }
 
+// SetSummaryUsage сохраняет модель, стоимость и число токенов суммаризации задачи
+func (r *JobRepositoryPG) SetSummaryUsage(ctx context.Context, id int64, model string, cost float64, tokens int) error {
+	query := `
+		UPDATE jobs
+		SET summary_model = $1, summary_cost = $2, summary_tokens = $3, updated_at = $4
+		WHERE id = $5
+	`
+
+	_, err := r.db.Exec(ctx, query, model, cost, tokens, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set summary usage: %w", err)
+	}
+
+	return nil
+}
+
 // SetNotionIDs устанавливает ID страницы и базы данных Notion для задачи
 func (r *JobRepositoryPG) SetNotionIDs(ctx context.Context, id int64, pageID, databaseID string) error {
 	query := `
@@ -268,3 +634,701 @@ func (r *JobRepositoryPG) SetNotionIDs(ctx context.Context, id int64, pageID, da
 
 	return nil
 }
+
+// SetNotionUpload сохраняет страницу Notion и число уже добавленных на нее блоков, чтобы прерванная выгрузка
+// продолжилась с последней добавленной части. Пустой pageID сбрасывает ход выгрузки
+func (r *JobRepositoryPG) SetNotionUpload(ctx context.Context, id int64, pageID string, cursor int) error {
+	query := `
+		UPDATE jobs
+		SET notion_upload_page_id = NULLIF($1, ''), notion_upload_cursor = $2, updated_at = $3
+		WHERE id = $4
+	`
+
+	_, err := r.db.Exec(ctx, query, pageID, cursor, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set notion upload: %w", err)
+	}
+
+	return nil
+}
+
+// SetAudioProfile устанавливает профиль предобработки аудио, пока обработка задачи не началась.
+// Возвращает false, если задача уже взята в работу
+func (r *JobRepositoryPG) SetAudioProfile(ctx context.Context, id int64, profile entity.AudioProfile) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET audio_profile = $1, updated_at = $2
+		WHERE id = $3 AND status::text IN ('created', 'pending', 'queued')
+	`
+
+	tag, err := r.db.Exec(ctx, query, profile, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to set audio profile: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetSummaryStyle устанавливает стиль краткого содержания задачи, пока ее транскрибация не завершена.
+// Возвращает false, если суммаризация уже поставлена в очередь
+func (r *JobRepositoryPG) SetSummaryStyle(ctx context.Context, id int64, style entity.SummaryStyle) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET summary_style = $1, updated_at = $2
+		WHERE id = $3 AND status::text IN ('created', 'pending', 'queued', 'processing')
+	`
+
+	tag, err := r.db.Exec(ctx, query, style, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to set summary style: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetProcessedAudio сохраняет подготовленный к распознаванию WAV задачи и профиль, с которым он получен,
+// и отсчитывает срок его хранения заново. Пустой path забывает WAV
+func (r *JobRepositoryPG) SetProcessedAudio(ctx context.Context, id int64, path string, profile entity.AudioProfile) error {
+	query := `
+		UPDATE jobs
+		SET processed_audio_path = NULLIF($1, ''), processed_audio_profile = NULLIF($2, ''),
+			processed_audio_at = CASE WHEN $1 = '' THEN NULL ELSE $3::timestamptz END
+		WHERE id = $4
+	`
+
+	_, err := r.db.Exec(ctx, query, path, string(profile), time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set processed audio: %w", err)
+	}
+
+	return nil
+}
+
+// GetProcessedAudioBefore возвращает задачи, подготовленные WAV которых сохранены или использованы до before,
+// начиная с самых старых
+func (r *JobRepositoryPG) GetProcessedAudioBefore(ctx context.Context, before time.Time, limit int) ([]*entity.Job, error) {
+	query := `
+		SELECT id, user_id, processed_audio_path
+		FROM jobs
+		WHERE processed_audio_at < $1
+		ORDER BY processed_audio_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs with processed audio: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*entity.Job, 0)
+	for rows.Next() {
+		job := &entity.Job{}
+		if err := rows.Scan(&job.ID, &job.UserID, &job.ProcessedAudioPath); err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ClearProcessedAudio забывает подготовленный WAV задачи, если он не использовался после before.
+// Возвращает false, если WAV использован повторно и удалять его рано
+func (r *JobRepositoryPG) ClearProcessedAudio(ctx context.Context, id int64, before time.Time) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET processed_audio_path = NULL, processed_audio_profile = NULL, processed_audio_at = NULL
+		WHERE id = $1 AND processed_audio_at < $2
+	`
+
+	tag, err := r.db.Exec(ctx, query, id, before)
+	if err != nil {
+		return false, fmt.Errorf("failed to clear processed audio: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetTranscriptSignature сохраняет сигнатуру транскрипции задачи для поиска повторно присланных записей
+func (r *JobRepositoryPG) SetTranscriptSignature(ctx context.Context, id int64, signature string) error {
+	query := `
+		UPDATE jobs
+		SET transcript_minhash = NULLIF($1, '')
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, signature, id)
+	if err != nil {
+		return fmt.Errorf("failed to set transcript signature: %w", err)
+	}
+
+	return nil
+}
+
+// GetTranscriptSignatures возвращает ID и сигнатуры транскрипций задач пользователя, созданных после since,
+// кроме excludeID и задач, которые сами помечены как повтор, начиная с самых новых
+func (r *JobRepositoryPG) GetTranscriptSignatures(ctx context.Context, userID, excludeID int64, since time.Time, limit int) ([]*entity.Job, error) {
+	query := `
+		SELECT id, transcript_minhash
+		FROM jobs
+		WHERE user_id = $1 AND id <> $2 AND created_at >= $3
+			AND transcript_minhash IS NOT NULL AND duplicate_of_job_id IS NULL
+		ORDER BY created_at DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, excludeID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcript signatures: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*entity.Job, 0)
+	for rows.Next() {
+		job := &entity.Job{UserID: userID}
+		if err := rows.Scan(&job.ID, &job.TranscriptSignature); err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// SetSpeakerSegments сохраняет фразы транскрипции задачи с говорящими; пустой список забывает их
+func (r *JobRepositoryPG) SetSpeakerSegments(ctx context.Context, id int64, segments []entity.SpeakerSegment) error {
+	var segmentsJSON []byte
+	if len(segments) > 0 {
+		var err error
+		segmentsJSON, err = json.Marshal(segments)
+		if err != nil {
+			return fmt.Errorf("failed to marshal speaker segments: %w", err)
+		}
+	}
+
+	query := `
+		UPDATE jobs
+		SET speaker_segments = $1
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, segmentsJSON, id)
+	if err != nil {
+		return fmt.Errorf("failed to set speaker segments: %w", err)
+	}
+
+	return nil
+}
+
+// SetDuplicateOf помечает задачу как повтор задачи originalID с долей совпадения similarity; originalID 0 снимает пометку
+func (r *JobRepositoryPG) SetDuplicateOf(ctx context.Context, id, originalID int64, similarity float64) error {
+	query := `
+		UPDATE jobs
+		SET duplicate_of_job_id = NULLIF($1, 0), duplicate_similarity = CASE WHEN $1 = 0 THEN NULL ELSE $2::real END
+		WHERE id = $3
+	`
+
+	_, err := r.db.Exec(ctx, query, originalID, similarity, id)
+	if err != nil {
+		return fmt.Errorf("failed to set duplicate job: %w", err)
+	}
+
+	return nil
+}
+
+// GetForNotionReconcile возвращает завершенные задачи пользователей с настроенным Notion,
+// у которых ID больше afterID, в порядке возрастания ID
+func (r *JobRepositoryPG) GetForNotionReconcile(ctx context.Context, afterID int64, limit int) ([]*entity.Job, error) {
+	query := `
+		SELECT
+			j.id, j.user_id, j.type, j.status, j.file_name, COALESCE(j.transcription, ''), j.transcription_gz,
+			COALESCE(j.summary, ''), j.summary_gz,
+			COALESCE(j.notion_page_id, ''), COALESCE(j.notion_database_id, ''), j.created_at
+		FROM jobs j
+		JOIN users u ON u.id = j.user_id
+		WHERE j.id > $1
+			AND j.status = $2
+			AND j.notion_unlinked_at IS NULL
+			AND COALESCE(u.notion_token, '') <> ''
+			AND COALESCE(u.notion_database_id, '') <> ''
+		ORDER BY j.id
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, afterID, entity.JobStatusCompleted, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs for Notion reconcile: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*entity.Job, 0)
+	for rows.Next() {
+		job := &entity.Job{}
+		var packed packedJobTexts
+		err := rows.Scan(
+			&job.ID,
+			&job.UserID,
+			&job.Type,
+			&job.Status,
+			&job.FileName,
+			&job.Transcription,
+			&packed.transcription,
+			&job.Summary,
+			&packed.summary,
+			&job.NotionPageID,
+			&job.NotionDatabaseID,
+			&job.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		if err := packed.unpack(job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ClearNotionPage удаляет ссылку на страницу Notion, которую удалил пользователь.
+// Задача помечается отвязанной, чтобы сверка не создавала страницу заново
+func (r *JobRepositoryPG) ClearNotionPage(ctx context.Context, id int64) error {
+	query := `
+		UPDATE jobs
+		SET notion_page_id = '', notion_unlinked_at = $1, updated_at = $1
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to clear notion page: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserStats возвращает агрегированную статистику задач пользователя начиная с указанного момента
+func (r *JobRepositoryPG) GetUserStats(ctx context.Context, userID int64, since time.Time) (*entity.UserStats, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE created_at >= $2),
+			COALESCE(SUM(duration) FILTER (WHERE created_at >= $2 AND status = $3), 0),
+			COUNT(*) FILTER (WHERE status NOT IN ($3, $4))
+		FROM jobs
+		WHERE user_id = $1
+	`
+
+	stats := &entity.UserStats{}
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		userID,
+		since,
+		entity.JobStatusCompleted,
+		entity.JobStatusFailed,
+	).Scan(
+		&stats.TotalJobs,
+		&stats.JobsSince,
+		&stats.SecondsTranscribed,
+		&stats.PendingJobs,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetUserUsage возвращает итоги использования бота пользователем за все время
+func (r *JobRepositoryPG) GetUserUsage(ctx context.Context, userID int64) (*entity.UserUsage, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = $2),
+			COUNT(*) FILTER (WHERE status = $3),
+			COALESCE(SUM(duration) FILTER (WHERE transcription <> '' OR transcription_gz IS NOT NULL), 0),
+			COALESCE(SUM(summary_tokens), 0),
+			COALESCE(SUM(summary_cost), 0)
+		FROM jobs
+		WHERE user_id = $1
+	`
+
+	usage := &entity.UserUsage{}
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		userID,
+		entity.JobStatusCompleted,
+		entity.JobStatusFailed,
+	).Scan(
+		&usage.TotalJobs,
+		&usage.CompletedJobs,
+		&usage.FailedJobs,
+		&usage.SecondsTranscribed,
+		&usage.SummaryTokens,
+		&usage.SummaryCost,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// GetTotals возвращает сводку по задачам всех пользователей; итоги за период считаются начиная с since
+func (r *JobRepositoryPG) GetTotals(ctx context.Context, since time.Time) (*entity.JobTotals, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE created_at >= $1),
+			COUNT(*) FILTER (WHERE created_at >= $1 AND status = $2),
+			COUNT(*) FILTER (WHERE created_at >= $1 AND status = $3),
+			COUNT(*) FILTER (WHERE status NOT IN ($2, $3)),
+			COUNT(DISTINCT user_id) FILTER (WHERE created_at >= $1),
+			COALESCE(SUM(duration) FILTER (WHERE created_at >= $1 AND (transcription <> '' OR transcription_gz IS NOT NULL)), 0),
+			COALESCE(SUM(summary_cost) FILTER (WHERE created_at >= $1), 0)
+		FROM jobs
+	`
+
+	totals := &entity.JobTotals{}
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		since,
+		entity.JobStatusCompleted,
+		entity.JobStatusFailed,
+	).Scan(
+		&totals.TotalJobs,
+		&totals.JobsSince,
+		&totals.CompletedSince,
+		&totals.FailedSince,
+		&totals.PendingJobs,
+		&totals.ActiveUsersSince,
+		&totals.SecondsTranscribed,
+		&totals.SummaryCostSince,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job totals: %w", err)
+	}
+
+	return totals, nil
+}
+
+// GetForKeepalive возвращает незавершенные задачи, созданные в промежутке [createdAfter, createdBefore),
+// о ходе которых пользователю не сообщали после keepaliveBefore, в порядке создания
+func (r *JobRepositoryPG) GetForKeepalive(ctx context.Context, createdAfter, createdBefore, keepaliveBefore time.Time, limit int) ([]*entity.Job, error) {
+	query := `
+		SELECT id, user_id, status, file_name, COALESCE(duration, 0), COALESCE(source_message_id, 0),
+			COALESCE(keepalive_message_id, 0), created_at
+		FROM jobs
+		WHERE status::text NOT IN ('completed', 'failed')
+			AND created_at >= $1
+			AND created_at < $2
+			AND (keepalive_at IS NULL OR keepalive_at < $3)
+		ORDER BY created_at
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(ctx, query, createdAfter, createdBefore, keepaliveBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs for keepalive: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*entity.Job, 0)
+	for rows.Next() {
+		job := &entity.Job{}
+		err := rows.Scan(
+			&job.ID,
+			&job.UserID,
+			&job.Status,
+			&job.FileName,
+			&job.Duration,
+			&job.SourceMessageID,
+			&job.KeepaliveMessageID,
+			&job.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ClaimKeepalive отмечает время сообщения о ходе задачи, если о нем не сообщали после keepaliveBefore.
+// Возвращает false, если сообщение уже отправил другой экземпляр приложения
+func (r *JobRepositoryPG) ClaimKeepalive(ctx context.Context, id int64, keepaliveBefore, now time.Time) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET keepalive_at = $1
+		WHERE id = $2 AND (keepalive_at IS NULL OR keepalive_at < $3)
+	`
+
+	tag, err := r.db.Exec(ctx, query, now, id, keepaliveBefore)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim job keepalive: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetKeepaliveMessage сохраняет ID сообщения о ходе задачи
+func (r *JobRepositoryPG) SetKeepaliveMessage(ctx context.Context, id int64, messageID int) error {
+	query := `
+		UPDATE jobs
+		SET keepalive_message_id = $1
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, messageID, id)
+	if err != nil {
+		return fmt.Errorf("failed to set keepalive message: %w", err)
+	}
+
+	return nil
+}
+
+// SetProgressMessage сохраняет ID сообщения о ходе обработки задачи, если в задаче все еще сохранен previousID.
+// Так сообщение, отправленное вместе с ответом на запись, и сообщение первого этапа не затирают друг друга
+func (r *JobRepositoryPG) SetProgressMessage(ctx context.Context, id int64, previousID, messageID int) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET progress_message_id = $1
+		WHERE id = $2 AND COALESCE(progress_message_id, 0) = $3
+	`
+
+	tag, err := r.db.Exec(ctx, query, messageID, id, previousID)
+	if err != nil {
+		return false, fmt.Errorf("failed to set progress message: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetFailedForCleanup возвращает задачи пользователя с ошибкой, обновленные после since, вместе со временем
+// их перезапуска ежедневным разбором ошибок, в порядке создания
+func (r *JobRepositoryPG) GetFailedForCleanup(ctx context.Context, userID int64, since time.Time, limit int) ([]*entity.Job, error) {
+	query := `
+		SELECT id, user_id, status, COALESCE(audio_file_path, ''), file_name, COALESCE(transcription, ''),
+			transcription_gz, COALESCE(error_message, ''), updated_at, cleanup_retried_at
+		FROM jobs
+		WHERE user_id = $1
+			AND status::text = 'failed'
+			AND updated_at >= $2
+		ORDER BY created_at
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failed jobs for cleanup: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*entity.Job, 0)
+	for rows.Next() {
+		job := &entity.Job{}
+		var packed packedJobTexts
+		err := rows.Scan(
+			&job.ID,
+			&job.UserID,
+			&job.Status,
+			&job.AudioFilePath,
+			&job.FileName,
+			&job.Transcription,
+			&packed.transcription,
+			&job.ErrorMessage,
+			&job.UpdatedAt,
+			&job.CleanupRetriedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		if err := packed.unpack(job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// MarkCleanupRetried отмечает перезапуск задачи разбором ошибок, если она еще не перезапускалась.
+// Возвращает false, если задача уже перезапущена
+func (r *JobRepositoryPG) MarkCleanupRetried(ctx context.Context, id int64, now time.Time) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET cleanup_retried_at = $1
+		WHERE id = $2 AND cleanup_retried_at IS NULL
+	`
+
+	tag, err := r.db.Exec(ctx, query, now, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark job cleanup retry: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetInstructionsBySourceMessage заменяет указания к конспекту задач пользователя, созданных по сообщению
+// sourceMessageID, пока их краткое содержание не составлено: этап суммаризации читает указания из задачи
+func (r *JobRepositoryPG) SetInstructionsBySourceMessage(ctx context.Context, userID int64, sourceMessageID int, instructions string) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET instructions = $1, updated_at = $2
+		WHERE user_id = $3
+			AND source_message_id = $4
+			AND status::text IN ('created', 'queued', 'pending', 'processing', 'transcribed')
+	`
+
+	tag, err := r.db.Exec(ctx, query, instructions, time.Now(), userID, sourceMessageID)
+	if err != nil {
+		return false, fmt.Errorf("failed to set job instructions: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetLatestByFileUniqueID возвращает последнюю задачу пользователя без ошибки по постоянному идентификатору
+// файла в Telegram или nil, если такой задачи нет
+func (r *JobRepositoryPG) GetLatestByFileUniqueID(ctx context.Context, userID int64, fileUniqueID string) (*entity.Job, error) {
+	query := `
+		SELECT
+			id, user_id, type, status, audio_file_path, file_name, duration, transcription, transcription_gz, summary, summary_gz,
+			notion_page_id, notion_database_id, COALESCE(source_message_id, 0), COALESCE(instructions, ''),
+			audio_profile, created_at, updated_at, completed_at, error_message
+		FROM jobs
+		WHERE user_id = $1
+			AND file_unique_id = $2
+			AND status::text <> 'failed'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	job := &entity.Job{}
+	var packed packedJobTexts
+	err := r.db.QueryRow(ctx, query, userID, fileUniqueID).Scan(
+		&job.ID,
+		&job.UserID,
+		&job.Type,
+		&job.Status,
+		&job.AudioFilePath,
+		&job.FileName,
+		&job.Duration,
+		&job.Transcription,
+		&packed.transcription,
+		&job.Summary,
+		&packed.summary,
+		&job.NotionPageID,
+		&job.NotionDatabaseID,
+		&job.SourceMessageID,
+		&job.Instructions,
+		&job.AudioProfile,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.CompletedAt,
+		&job.ErrorMessage,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job by file unique id: %w", err)
+	}
+	if err := packed.unpack(job); err != nil {
+		return nil, err
+	}
+
+	job.FileUniqueID = fileUniqueID
+	return job, nil
+}
+
+// GetLatestBySourceMessage возвращает последнюю задачу пользователя без ошибки, созданную по сообщению
+// sourceMessageID, или nil, если такой задачи нет
+func (r *JobRepositoryPG) GetLatestBySourceMessage(ctx context.Context, userID int64, sourceMessageID int) (*entity.Job, error) {
+	query := `
+		SELECT
+			id, user_id, type, status, audio_file_path, file_name, duration, transcription, transcription_gz, summary, summary_gz,
+			notion_page_id, notion_database_id, COALESCE(source_message_id, 0), COALESCE(instructions, ''),
+			audio_profile, created_at, updated_at, completed_at, error_message
+		FROM jobs
+		WHERE user_id = $1
+			AND source_message_id = $2
+			AND status::text <> 'failed'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	job := &entity.Job{}
+	var packed packedJobTexts
+	err := r.db.QueryRow(ctx, query, userID, sourceMessageID).Scan(
+		&job.ID,
+		&job.UserID,
+		&job.Type,
+		&job.Status,
+		&job.AudioFilePath,
+		&job.FileName,
+		&job.Duration,
+		&job.Transcription,
+		&packed.transcription,
+		&job.Summary,
+		&packed.summary,
+		&job.NotionPageID,
+		&job.NotionDatabaseID,
+		&job.SourceMessageID,
+		&job.Instructions,
+		&job.AudioProfile,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.CompletedAt,
+		&job.ErrorMessage,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job by source message: %w", err)
+	}
+	if err := packed.unpack(job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Delete удаляет задачу. Этапы, публичные ссылки, эмбеддинги и другие связанные записи удаляются каскадно
+func (r *JobRepositoryPG) Delete(ctx context.Context, id int64) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM jobs WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
+
+	return nil
+}
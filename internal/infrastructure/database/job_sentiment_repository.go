@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// JobSentimentRepositoryPG реализует интерфейс JobSentimentRepository для PostgreSQL
+type JobSentimentRepositoryPG struct {
+	db *PostgresDB
+}
+
+// NewJobSentimentRepository создает новый репозиторий для работы с разборами настроения встреч
+func NewJobSentimentRepository(db *PostgresDB) repository.JobSentimentRepository {
+	return &JobSentimentRepositoryPG{db: db}
+}
+
+// Save сохраняет разбор настроения задачи, заменяя прежний
+func (r *JobSentimentRepositoryPG) Save(ctx context.Context, sentiment *entity.JobSentiment) error {
+	points := sentiment.TensionPoints
+	if points == nil {
+		points = []entity.TensionPoint{}
+	}
+	pointsJSON, err := json.Marshal(points)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tension points: %w", err)
+	}
+
+	query := `
+		INSERT INTO job_sentiments (job_id, mood, tone, tension_points, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (job_id) DO UPDATE
+		SET mood = EXCLUDED.mood, tone = EXCLUDED.tone,
+			tension_points = EXCLUDED.tension_points, created_at = EXCLUDED.created_at
+	`
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		sentiment.JobID,
+		sentiment.Mood,
+		sentiment.Tone,
+		pointsJSON,
+		sentiment.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to save job sentiment: %w", err)
+	}
+
+	return nil
+}
+
+// GetByJobID возвращает разбор настроения задачи или nil
+func (r *JobSentimentRepositoryPG) GetByJobID(ctx context.Context, jobID int64) (*entity.JobSentiment, error) {
+	query := `
+		SELECT job_id, mood, tone, tension_points, created_at
+		FROM job_sentiments
+		WHERE job_id = $1
+	`
+
+	sentiment := &entity.JobSentiment{}
+	var pointsJSON []byte
+	err := r.db.QueryRow(ctx, query, jobID).Scan(
+		&sentiment.JobID,
+		&sentiment.Mood,
+		&sentiment.Tone,
+		&pointsJSON,
+		&sentiment.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job sentiment: %w", err)
+	}
+
+	if err := json.Unmarshal(pointsJSON, &sentiment.TensionPoints); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tension points: %w", err)
+	}
+
+	return sentiment, nil
+}
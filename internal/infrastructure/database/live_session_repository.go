@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// LiveSessionRepositoryPG реализует интерфейс LiveSessionRepository для PostgreSQL
+type LiveSessionRepositoryPG struct {
+	db *PostgresDB
+}
+
+// NewLiveSessionRepository создает новый репозиторий для работы с live-сессиями
+func NewLiveSessionRepository(db *PostgresDB) repository.LiveSessionRepository {
+	return &LiveSessionRepositoryPG{db: db}
+}
+
+// Set сохраняет live-сессию пользователя, заменяя предыдущую
+func (r *LiveSessionRepositoryPG) Set(ctx context.Context, session *entity.LiveSession) error {
+	session.UpdatedAt = time.Now()
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = session.UpdatedAt
+	}
+
+	query := `
+		INSERT INTO live_sessions (user_id, message_id, transcript, summary, segments, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE
+		SET message_id = EXCLUDED.message_id, transcript = EXCLUDED.transcript, summary = EXCLUDED.summary,
+			segments = EXCLUDED.segments, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Exec(ctx, query, session.UserID, session.MessageID, session.Transcript, session.Summary,
+		session.Segments, session.CreatedAt, session.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set live session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID возвращает активную live-сессию пользователя или nil
+func (r *LiveSessionRepositoryPG) GetByUserID(ctx context.Context, userID int64) (*entity.LiveSession, error) {
+	query := `
+		SELECT user_id, message_id, transcript, summary, segments, created_at, updated_at
+		FROM live_sessions
+		WHERE user_id = $1
+	`
+
+	session := &entity.LiveSession{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&session.UserID,
+		&session.MessageID,
+		&session.Transcript,
+		&session.Summary,
+		&session.Segments,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get live session: %w", err)
+	}
+
+	return session, nil
+}
+
+// Delete завершает live-сессию пользователя и сообщает, была ли она активна
+func (r *LiveSessionRepositoryPG) Delete(ctx context.Context, userID int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM live_sessions WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete live session: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
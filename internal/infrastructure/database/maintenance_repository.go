@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// maintenanceKey - ключ режима технического обслуживания. Режим хранится в Redis, а не в PostgreSQL,
+// чтобы его можно было включить на время миграций базы данных
+const maintenanceKey = "maintenance"
+
+// MaintenanceRepositoryRedis реализует интерфейс MaintenanceRepository для Redis. Ключ не истекает:
+// режим действует, пока администратор его не выключит, в том числе после перезапуска приложения
+type MaintenanceRepositoryRedis struct {
+	redis *RedisClient
+}
+
+// NewMaintenanceRepository создает новый репозиторий режима технического обслуживания
+func NewMaintenanceRepository(redis *RedisClient) repository.MaintenanceRepository {
+	return &MaintenanceRepositoryRedis{redis: redis}
+}
+
+// Get возвращает включенный режим обслуживания или nil, если он выключен
+func (r *MaintenanceRepositoryRedis) Get(ctx context.Context) (*entity.Maintenance, error) {
+	value, err := r.redis.Get(ctx, maintenanceKey)
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance: %w", err)
+	}
+
+	var maintenance entity.Maintenance
+	if err := json.Unmarshal([]byte(value), &maintenance); err != nil {
+		return nil, fmt.Errorf("failed to parse maintenance: %w", err)
+	}
+
+	return &maintenance, nil
+}
+
+// Save включает режим обслуживания или обновляет его параметры
+func (r *MaintenanceRepositoryRedis) Save(ctx context.Context, maintenance *entity.Maintenance) error {
+	data, err := json.Marshal(maintenance)
+	if err != nil {
+		return fmt.Errorf("failed to marshal maintenance: %w", err)
+	}
+
+	if err := r.redis.Set(ctx, maintenanceKey, data, 0); err != nil {
+		return fmt.Errorf("failed to save maintenance: %w", err)
+	}
+
+	return nil
+}
+
+// Clear выключает режим обслуживания
+func (r *MaintenanceRepositoryRedis) Clear(ctx context.Context) error {
+	if err := r.redis.Del(ctx, maintenanceKey); err != nil {
+		return fmt.Errorf("failed to clear maintenance: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// messageDeletionsKey - ключ отсортированного множества запланированных удалений
+const messageDeletionsKey = "telegram:message_deletions"
+
+// MessageDeletionRepositoryRedis реализует интерфейс MessageDeletionRepository для Redis
+type MessageDeletionRepositoryRedis struct {
+	redis *RedisClient
+}
+
+// NewMessageDeletionRepository создает новый репозиторий для работы с запланированными удалениями сообщений
+func NewMessageDeletionRepository(redis *RedisClient) repository.MessageDeletionRepository {
+	return &MessageDeletionRepositoryRedis{redis: redis}
+}
+
+// Schedule планирует удаление сообщения
+func (r *MessageDeletionRepositoryRedis) Schedule(ctx context.Context, deletion entity.ScheduledDeletion) error {
+	member := fmt.Sprintf("%d:%d", deletion.ChatID, deletion.MessageID)
+
+	err := r.redis.ZAdd(ctx, messageDeletionsKey, float64(deletion.DeleteAt.Unix()), member)
+	if err != nil {
+		return fmt.Errorf("failed to schedule message deletion: %w", err)
+	}
+
+	return nil
+}
+
+// PopDue извлекает удаления, время которых наступило к указанному моменту
+func (r *MessageDeletionRepositoryRedis) PopDue(ctx context.Context, now time.Time) ([]entity.ScheduledDeletion, error) {
+	members, err := r.redis.ZRangeByScore(ctx, messageDeletionsKey, "-inf", strconv.FormatInt(now.Unix(), 10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due message deletions: %w", err)
+	}
+
+	deletions := make([]entity.ScheduledDeletion, 0, len(members))
+	for _, member := range members {
+		// Элемент забирает тот, кто успел удалить его из множества
+		removed, err := r.redis.ZRem(ctx, messageDeletionsKey, member)
+		if err != nil {
+			return deletions, fmt.Errorf("failed to remove message deletion: %w", err)
+		}
+		if removed == 0 {
+			continue
+		}
+
+		chatIDStr, messageIDStr, ok := strings.Cut(member, ":")
+		if !ok {
+			continue
+		}
+		chatID, err := strconv.ParseInt(chatIDStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		messageID, err := strconv.Atoi(messageIDStr)
+		if err != nil {
+			continue
+		}
+
+		deletions = append(deletions, entity.ScheduledDeletion{
+			ChatID:    chatID,
+			MessageID: messageID,
+			DeleteAt:  now,
+		})
+	}
+
+	return deletions, nil
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// ModelComparisonRepositoryPG реализует интерфейс ModelComparisonRepository для PostgreSQL
+type ModelComparisonRepositoryPG struct {
+	db *PostgresDB
+}
+
+// NewModelComparisonRepository создает новый репозиторий для работы со сравнениями моделей суммаризации
+func NewModelComparisonRepository(db *PostgresDB) repository.ModelComparisonRepository {
+	return &ModelComparisonRepositoryPG{db: db}
+}
+
+// Create создает сравнение без голоса
+func (r *ModelComparisonRepositoryPG) Create(ctx context.Context, comparison *entity.ModelComparison) error {
+	comparison.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO model_comparisons (job_id, user_id, model_a, model_b, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		comparison.JobID,
+		comparison.UserID,
+		comparison.ModelA,
+		comparison.ModelB,
+		comparison.CreatedAt,
+	).Scan(&comparison.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create model comparison: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID возвращает сравнение или nil, если его нет
+func (r *ModelComparisonRepositoryPG) GetByID(ctx context.Context, id int64) (*entity.ModelComparison, error) {
+	query := `
+		SELECT id, job_id, user_id, model_a, model_b, COALESCE(vote, ''), created_at, voted_at
+		FROM model_comparisons
+		WHERE id = $1
+	`
+
+	comparison := &entity.ModelComparison{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&comparison.ID,
+		&comparison.JobID,
+		&comparison.UserID,
+		&comparison.ModelA,
+		&comparison.ModelB,
+		&comparison.Vote,
+		&comparison.CreatedAt,
+		&comparison.VotedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get model comparison: %w", err)
+	}
+
+	return comparison, nil
+}
+
+// Vote сохраняет голос в сравнении. Возвращает false, если голос в нем уже отдан
+func (r *ModelComparisonRepositoryPG) Vote(ctx context.Context, id int64, vote entity.ModelComparisonVote, now time.Time) (bool, error) {
+	query := `
+		UPDATE model_comparisons
+		SET vote = $1, voted_at = $2
+		WHERE id = $3 AND vote IS NULL
+	`
+
+	tag, err := r.db.Exec(ctx, query, vote, now, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to vote in model comparison: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetModelStats возвращает итоги голосований по моделям, начиная с модели с наибольшим числом побед.
+// Каждое сравнение с голосом учитывается для обеих моделей: победа засчитывается модели выбранного варианта
+func (r *ModelComparisonRepositoryPG) GetModelStats(ctx context.Context) ([]*entity.ModelVoteStats, error) {
+	query := `
+		SELECT model, COUNT(*),
+			SUM(CASE WHEN vote = side THEN 1 ELSE 0 END),
+			SUM(CASE WHEN vote = 'tie' THEN 1 ELSE 0 END)
+		FROM (
+			SELECT model_a AS model, 'a' AS side, vote FROM model_comparisons WHERE vote IS NOT NULL
+			UNION ALL
+			SELECT model_b AS model, 'b' AS side, vote FROM model_comparisons WHERE vote IS NOT NULL
+		) AS votes
+		GROUP BY model
+		ORDER BY 3 DESC, model
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model vote stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]*entity.ModelVoteStats, 0)
+	for rows.Next() {
+		modelStats := &entity.ModelVoteStats{}
+		if err := rows.Scan(&modelStats.Model, &modelStats.Comparisons, &modelStats.Wins, &modelStats.Ties); err != nil {
+			return nil, fmt.Errorf("failed to scan model vote stats: %w", err)
+		}
+		stats = append(stats, modelStats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate model vote stats: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// notionOAuthStateKeyPrefix - префикс ключей параметров state входа в Notion
+const notionOAuthStateKeyPrefix = "notion:oauth_state:"
+
+// NotionOAuthStateRepositoryRedis реализует интерфейс NotionOAuthStateRepository для Redis.
+// Срок действия state задается TTL ключа
+type NotionOAuthStateRepositoryRedis struct {
+	redis *RedisClient
+}
+
+// NewNotionOAuthStateRepository создает новый репозиторий параметров state входа в Notion
+func NewNotionOAuthStateRepository(redis *RedisClient) repository.NotionOAuthStateRepository {
+	return &NotionOAuthStateRepositoryRedis{redis: redis}
+}
+
+// Save сохраняет state, выданный пользователю, на время ttl
+func (r *NotionOAuthStateRepositoryRedis) Save(ctx context.Context, state string, userID int64, ttl time.Duration) error {
+	if err := r.redis.Set(ctx, notionOAuthStateKeyPrefix+state, userID, ttl); err != nil {
+		return fmt.Errorf("failed to save notion oauth state: %w", err)
+	}
+
+	return nil
+}
+
+// Pop возвращает ID пользователя, которому выдан state, и удаляет state. GETDEL выполняется атомарно,
+// поэтому при повторной доставке одного обратного вызова state достается только первому
+func (r *NotionOAuthStateRepositoryRedis) Pop(ctx context.Context, state string) (int64, error) {
+	value, err := r.redis.Client().GetDel(ctx, notionOAuthStateKeyPrefix+state).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get notion oauth state: %w", err)
+	}
+
+	userID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse notion oauth state: %w", err)
+	}
+
+	return userID, nil
+}
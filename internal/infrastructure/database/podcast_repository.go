@@ -0,0 +1,237 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// PodcastRepositoryPG реализует интерфейс PodcastRepository для PostgreSQL
+type PodcastRepositoryPG struct {
+	db *PostgresDB
+}
+
+// NewPodcastRepository создает новый репозиторий для работы с подписками на подкасты
+func NewPodcastRepository(db *PostgresDB) repository.PodcastRepository {
+	return &PodcastRepositoryPG{db: db}
+}
+
+// CreateFeed создает подписку на ленту
+func (r *PodcastRepositoryPG) CreateFeed(ctx context.Context, feed *entity.PodcastFeed) error {
+	feed.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO podcast_feeds (user_id, url, title, notion_database_id, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		ON CONFLICT (user_id, url) DO NOTHING
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		feed.UserID,
+		feed.URL,
+		feed.Title,
+		feed.NotionDatabaseID,
+		feed.CreatedAt,
+	).Scan(&feed.ID)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("podcast feed already exists")
+		}
+		return fmt.Errorf("failed to create podcast feed: %w", err)
+	}
+
+	return nil
+}
+
+// GetFeedsByUserID возвращает подписки пользователя
+func (r *PodcastRepositoryPG) GetFeedsByUserID(ctx context.Context, userID int64) ([]*entity.PodcastFeed, error) {
+	query := `
+		SELECT id, user_id, url, title, COALESCE(notion_database_id, ''), last_checked_at, created_at
+		FROM podcast_feeds
+		WHERE user_id = $1
+		ORDER BY id
+	`
+
+	return r.getFeeds(ctx, query, userID)
+}
+
+// GetAllFeeds возвращает подписки всех пользователей
+func (r *PodcastRepositoryPG) GetAllFeeds(ctx context.Context) ([]*entity.PodcastFeed, error) {
+	query := `
+		SELECT id, user_id, url, title, COALESCE(notion_database_id, ''), last_checked_at, created_at
+		FROM podcast_feeds
+		ORDER BY id
+	`
+
+	return r.getFeeds(ctx, query)
+}
+
+// getFeeds выполняет запрос на получение списка подписок
+func (r *PodcastRepositoryPG) getFeeds(ctx context.Context, query string, args ...interface{}) ([]*entity.PodcastFeed, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get podcast feeds: %w", err)
+	}
+	defer rows.Close()
+
+	feeds := make([]*entity.PodcastFeed, 0)
+	for rows.Next() {
+		feed := &entity.PodcastFeed{}
+		err := rows.Scan(
+			&feed.ID,
+			&feed.UserID,
+			&feed.URL,
+			&feed.Title,
+			&feed.NotionDatabaseID,
+			&feed.LastCheckedAt,
+			&feed.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan podcast feed: %w", err)
+		}
+		feeds = append(feeds, feed)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate podcast feeds: %w", err)
+	}
+
+	return feeds, nil
+}
+
+// DeleteFeed удаляет подписку пользователя и сообщает, была ли она найдена
+func (r *PodcastRepositoryPG) DeleteFeed(ctx context.Context, userID, feedID int64) (bool, error) {
+	query := `
+		DELETE FROM podcast_feeds
+		WHERE id = $1 AND user_id = $2
+	`
+
+	tag, err := r.db.Exec(ctx, query, feedID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete podcast feed: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetFeedChecked отмечает время последней проверки ленты
+func (r *PodcastRepositoryPG) SetFeedChecked(ctx context.Context, feedID int64, checkedAt time.Time) error {
+	query := `
+		UPDATE podcast_feeds
+		SET last_checked_at = $1
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, checkedAt, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to update podcast feed: %w", err)
+	}
+
+	return nil
+}
+
+// AddEpisode учитывает выпуск для пользователя и возвращает false, если он уже был учтен
+func (r *PodcastRepositoryPG) AddEpisode(ctx context.Context, episode *entity.PodcastEpisode) (bool, error) {
+	episode.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO podcast_episodes (feed_id, user_id, guid, title, audio_url, published_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, guid) DO NOTHING
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		episode.FeedID,
+		episode.UserID,
+		episode.GUID,
+		episode.Title,
+		episode.AudioURL,
+		episode.PublishedAt,
+		episode.CreatedAt,
+	).Scan(&episode.ID)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to add podcast episode: %w", err)
+	}
+
+	return true, nil
+}
+
+// AddEpisodes учитывает выпуски одним пакетом запросов и возвращает число новых выпусков.
+// ID заполняется только у новых выпусков; уже учтенные выпуски пропускаются, как в AddEpisode
+func (r *PodcastRepositoryPG) AddEpisodes(ctx context.Context, episodes []*entity.PodcastEpisode) (int, error) {
+	if len(episodes) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		INSERT INTO podcast_episodes (feed_id, user_id, guid, title, audio_url, published_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, guid) DO NOTHING
+		RETURNING id
+	`
+
+	now := time.Now()
+	batch := &pgx.Batch{}
+	for _, episode := range episodes {
+		episode.CreatedAt = now
+		batch.Queue(
+			query,
+			episode.FeedID,
+			episode.UserID,
+			episode.GUID,
+			episode.Title,
+			episode.AudioURL,
+			episode.PublishedAt,
+			episode.CreatedAt,
+		)
+	}
+
+	results := r.db.Pool().SendBatch(ctx, batch)
+	defer results.Close()
+
+	added := 0
+	for _, episode := range episodes {
+		err := results.QueryRow().Scan(&episode.ID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return added, fmt.Errorf("failed to add podcast episode: %w", err)
+		}
+		added++
+	}
+
+	return added, nil
+}
+
+// SetEpisodeJob связывает выпуск с задачей обработки
+func (r *PodcastRepositoryPG) SetEpisodeJob(ctx context.Context, episodeID, jobID int64) error {
+	query := `
+		UPDATE podcast_episodes
+		SET job_id = $1
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, jobID, episodeID)
+	if err != nil {
+		return fmt.Errorf("failed to set podcast episode job: %w", err)
+	}
+
+	return nil
+}
@@ -47,6 +47,11 @@ func (db *PostgresDB) Close() {
 	}
 }
 
+// Ping проверяет соединение с базой данных
+func (db *PostgresDB) Ping(ctx context.Context) error {
+	return db.pool.Ping(ctx)
+}
+
 // Pool возвращает пул соединений
 func (db *PostgresDB) Pool() *pgxpool.Pool {
 	return db.pool
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// rateLimitKeyPrefix - префикс ключей отсортированных множеств событий, учитываемых ограничением частоты
+const rateLimitKeyPrefix = "rate_limit:"
+
+// takeScript учитывает событие в скользящем окне: удаляет события старше окна и добавляет новое, если их
+// меньше лимита. Возвращает 0, если событие учтено, или число миллисекунд до освобождения места в окне.
+// Проверка и добавление выполняются атомарно, поэтому одновременные сообщения не превышают лимит
+var takeScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+if redis.call("ZCARD", KEYS[1]) < tonumber(ARGV[3]) then
+	redis.call("ZADD", KEYS[1], now, ARGV[4])
+	redis.call("PEXPIRE", KEYS[1], window)
+	return 0
+end
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+return math.max(tonumber(oldest[2]) + window - now, 1)
+`)
+
+// RateLimitRepositoryRedis реализует интерфейс RateLimitRepository для Redis: события ключа хранятся
+// в отсортированном множестве по времени, а само множество удаляется по TTL через окно после последнего события
+type RateLimitRepositoryRedis struct {
+	redis *RedisClient
+}
+
+// NewRateLimitRepository создает новый репозиторий учета событий для ограничения частоты
+func NewRateLimitRepository(redis *RedisClient) repository.RateLimitRepository {
+	return &RateLimitRepositoryRedis{redis: redis}
+}
+
+// Take учитывает событие key, если за последние window событий было меньше limit, и возвращает 0.
+// Иначе возвращает время, через которое можно будет повторить попытку
+func (r *RateLimitRepositoryRedis) Take(ctx context.Context, key string, limit int, window time.Duration) (time.Duration, error) {
+	// Элемент множества должен быть уникальным, иначе события одной миллисекунды сольются в одно
+	member, err := generateLockToken()
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate rate limit event id: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+	retryAfter, err := takeScript.Run(ctx, r.redis.Client(), []string{rateLimitKeyPrefix + key}, now, window.Milliseconds(), limit, member).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to take rate limit: %w", err)
+	}
+
+	return time.Duration(retryAfter) * time.Millisecond, nil
+}
@@ -39,6 +39,11 @@ func (r *RedisClient) Close() error {
 	return nil
 }
 
+// Ping проверяет соединение с Redis
+func (r *RedisClient) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
 // Client возвращает клиент Redis
 func (r *RedisClient) Client() *redis.Client {
 	return r.client
@@ -93,3 +98,18 @@ func (r *RedisClient) BRPop(ctx context.Context, timeout time.Duration, keys ...
 func (r *RedisClient) LLen(ctx context.Context, key string) (int64, error) {
 	return r.client.LLen(ctx, key).Result()
 }
+
+// ZAdd добавляет элемент в отсортированное множество
+func (r *RedisClient) ZAdd(ctx context.Context, key string, score float64, member interface{}) error {
+	return r.client.ZAdd(ctx, key, redis.Z{Score: score, Member: member}).Err()
+}
+
+// ZRangeByScore возвращает элементы отсортированного множества в диапазоне оценок
+func (r *RedisClient) ZRangeByScore(ctx context.Context, key string, min, max string) ([]string, error) {
+	return r.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: min, Max: max}).Result()
+}
+
+// ZRem удаляет элементы из отсортированного множества и возвращает количество удаленных
+func (r *RedisClient) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return r.client.ZRem(ctx, key, members...).Result()
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// lockRetryInterval - пауза между попытками захватить занятую блокировку
+const lockRetryInterval = 100 * time.Millisecond
+
+// lockReleaseTimeout - ограничение времени освобождения блокировки
+const lockReleaseTimeout = 5 * time.Second
+
+// unlockScript удаляет блокировку, только если она все еще принадлежит владельцу токена
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// extendScript продлевает блокировку, только если она все еще принадлежит владельцу токена
+var extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// RedisLockService реализует интерфейс LockService на Redis: блокировка - ключ с токеном владельца
+// и сроком жизни ttl, который продлевается, пока блокировка удерживается. Если экземпляр приложения
+// аварийно завершится, блокировка освободится сама по истечении ttl
+type RedisLockService struct {
+	redis  *RedisClient
+	ttl    time.Duration
+	logger *logger.Logger
+}
+
+// NewRedisLockService создает сервис блокировок на Redis со сроком жизни блокировки ttl
+func NewRedisLockService(redis *RedisClient, ttl time.Duration, logger *logger.Logger) service.LockService {
+	return &RedisLockService{
+		redis:  redis,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// Lock захватывает блокировку key, ожидая ее освобождения не дольше wait, и возвращает функцию освобождения
+func (s *RedisLockService) Lock(ctx context.Context, key string, wait time.Duration) (func(), error) {
+	token, err := generateLockToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate lock token: %w", err)
+	}
+
+	startedAt := time.Now()
+	deadline := startedAt.Add(wait)
+	for {
+		acquired, err := s.redis.Client().SetNX(ctx, key, token, s.ttl).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+		}
+		if acquired {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s after %s", key, wait)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to acquire lock %s: %w", key, ctx.Err())
+		case <-time.After(lockRetryInterval):
+		}
+	}
+
+	if waited := time.Since(startedAt); waited > lockRetryInterval {
+		s.logger.Debug("Lock acquired after waiting",
+			"key", key,
+			"waited", waited,
+		)
+	}
+
+	// Продление блокировки, пока она удерживается
+	stop := make(chan struct{})
+	go s.keepAlive(key, token, stop)
+
+	released := false
+	return func() {
+		if released {
+			return
+		}
+		released = true
+		close(stop)
+
+		releaseCtx, cancel := context.WithTimeout(context.Background(), lockReleaseTimeout)
+		defer cancel()
+
+		if err := unlockScript.Run(releaseCtx, s.redis.Client(), []string{key}, token).Err(); err != nil && !errors.Is(err, redis.Nil) {
+			s.logger.Warn("Failed to release lock",
+				"error", err,
+				"key", key,
+			)
+		}
+	}, nil
+}
+
+// keepAlive продлевает блокировку на ttl каждую треть ttl до закрытия stop
+func (s *RedisLockService) keepAlive(key, token string, stop <-chan struct{}) {
+	ticker := time.NewTicker(s.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), lockReleaseTimeout)
+			extended, err := extendScript.Run(ctx, s.redis.Client(), []string{key}, token, s.ttl.Milliseconds()).Int()
+			cancel()
+			if err != nil {
+				s.logger.Warn("Failed to extend lock",
+					"error", err,
+					"key", key,
+				)
+				continue
+			}
+			if extended == 0 {
+				s.logger.Warn("Lock lost before release",
+					"key", key,
+				)
+				return
+			}
+		}
+	}
+}
+
+// generateLockToken генерирует случайный токен владельца блокировки
+func generateLockToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
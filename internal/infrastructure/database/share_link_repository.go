@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// ShareLinkRepositoryPG реализует интерфейс ShareLinkRepository для PostgreSQL
+type ShareLinkRepositoryPG struct {
+	db *PostgresDB
+}
+
+// NewShareLinkRepository создает новый репозиторий для работы с публичными ссылками
+func NewShareLinkRepository(db *PostgresDB) repository.ShareLinkRepository {
+	return &ShareLinkRepositoryPG{db: db}
+}
+
+// Create создает новую ссылку
+func (r *ShareLinkRepositoryPG) Create(ctx context.Context, link *entity.ShareLink) error {
+	link.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO share_links (job_id, user_id, token, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		link.JobID,
+		link.UserID,
+		link.Token,
+		link.ExpiresAt,
+		link.CreatedAt,
+	).Scan(&link.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	return nil
+}
+
+// GetByToken возвращает ссылку по её токену
+func (r *ShareLinkRepositoryPG) GetByToken(ctx context.Context, token string) (*entity.ShareLink, error) {
+	query := `
+		SELECT id, job_id, user_id, token, expires_at, revoked_at, created_at
+		FROM share_links
+		WHERE token = $1
+	`
+
+	link := &entity.ShareLink{}
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		token,
+	).Scan(
+		&link.ID,
+		&link.JobID,
+		&link.UserID,
+		&link.Token,
+		&link.ExpiresAt,
+		&link.RevokedAt,
+		&link.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("share link not found")
+		}
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+
+	return link, nil
+}
+
+// RevokeByJobID отзывает все активные ссылки задачи
+func (r *ShareLinkRepositoryPG) RevokeByJobID(ctx context.Context, jobID int64) (int64, error) {
+	query := `
+		UPDATE share_links
+		SET revoked_at = $1
+		WHERE job_id = $2 AND revoked_at IS NULL
+	`
+
+	tag, err := r.db.Exec(ctx, query, time.Now(), jobID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke share links: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
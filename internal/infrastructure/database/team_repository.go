@@ -0,0 +1,315 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// teamColumns - колонки команды в порядке сканирования scanTeam
+const teamColumns = `t.id, t.name, t.owner_id, t.invite_code, COALESCE(t.channel_id, 0), t.digest_hour, t.last_digest_at, t.created_at`
+
+// TeamRepositoryPG реализует интерфейс TeamRepository для PostgreSQL
+type TeamRepositoryPG struct {
+	db *PostgresDB
+}
+
+// NewTeamRepository создает новый репозиторий для работы с командами
+func NewTeamRepository(db *PostgresDB) repository.TeamRepository {
+	return &TeamRepositoryPG{db: db}
+}
+
+// Create создает команду и добавляет в нее владельца
+func (r *TeamRepositoryPG) Create(ctx context.Context, team *entity.Team) error {
+	team.CreatedAt = time.Now()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO teams (name, owner_id, invite_code, channel_id, digest_hour, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, 0), $5, $6)
+		RETURNING id
+	`
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		team.Name,
+		team.OwnerID,
+		team.InviteCode,
+		team.ChannelID,
+		team.DigestHour,
+		team.CreatedAt,
+	).Scan(&team.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create team: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO team_members (user_id, team_id, joined_at) VALUES ($1, $2, $3)`, team.OwnerID, team.ID, team.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add team owner: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID возвращает команду, в которой состоит пользователь, или nil
+func (r *TeamRepositoryPG) GetByUserID(ctx context.Context, userID int64) (*entity.Team, error) {
+	query := `
+		SELECT ` + teamColumns + `
+		FROM teams t
+		JOIN team_members m ON m.team_id = t.id
+		WHERE m.user_id = $1
+	`
+
+	team, err := scanTeam(r.db.QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	return team, nil
+}
+
+// GetByInviteCode возвращает команду по коду приглашения
+func (r *TeamRepositoryPG) GetByInviteCode(ctx context.Context, code string) (*entity.Team, error) {
+	query := `
+		SELECT ` + teamColumns + `
+		FROM teams t
+		WHERE t.invite_code = $1
+	`
+
+	team, err := scanTeam(r.db.QueryRow(ctx, query, code))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("team not found")
+		}
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	return team, nil
+}
+
+// GetWithChannel возвращает команды, для которых задан канал дайджеста
+func (r *TeamRepositoryPG) GetWithChannel(ctx context.Context) ([]*entity.Team, error) {
+	query := `
+		SELECT ` + teamColumns + `
+		FROM teams t
+		WHERE t.channel_id IS NOT NULL
+		ORDER BY t.id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+	defer rows.Close()
+
+	teams := make([]*entity.Team, 0)
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan team: %w", err)
+		}
+		teams = append(teams, team)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate teams: %w", err)
+	}
+
+	return teams, nil
+}
+
+// Delete удаляет команду вместе со списком участников
+func (r *TeamRepositoryPG) Delete(ctx context.Context, teamID int64) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM teams WHERE id = $1`, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to delete team: %w", err)
+	}
+
+	return nil
+}
+
+// AddMember добавляет пользователя в команду
+func (r *TeamRepositoryPG) AddMember(ctx context.Context, teamID, userID int64) error {
+	query := `
+		INSERT INTO team_members (user_id, team_id, joined_at)
+		VALUES ($1, $2, $3)
+	`
+
+	_, err := r.db.Exec(ctx, query, userID, teamID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to add team member: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember исключает пользователя из команды и сообщает, состоял ли он в ней
+func (r *TeamRepositoryPG) RemoveMember(ctx context.Context, teamID, userID int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2`, teamID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove team member: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetMembers возвращает участников команды в порядке вступления
+func (r *TeamRepositoryPG) GetMembers(ctx context.Context, teamID int64) ([]*entity.TeamMember, error) {
+	query := `
+		SELECT m.team_id, m.user_id, u.telegram_id, COALESCE(u.username, ''), COALESCE(u.first_name, ''), m.joined_at
+		FROM team_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.team_id = $1
+		ORDER BY m.joined_at, m.user_id
+	`
+
+	rows, err := r.db.Query(ctx, query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+	defer rows.Close()
+
+	members := make([]*entity.TeamMember, 0)
+	for rows.Next() {
+		member := &entity.TeamMember{}
+		err := rows.Scan(
+			&member.TeamID,
+			&member.UserID,
+			&member.TelegramID,
+			&member.Username,
+			&member.FirstName,
+			&member.JoinedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan team member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate team members: %w", err)
+	}
+
+	return members, nil
+}
+
+// SetChannel задает канал для публикации дайджеста (0 - отключить публикацию)
+func (r *TeamRepositoryPG) SetChannel(ctx context.Context, teamID, channelID int64) error {
+	_, err := r.db.Exec(ctx, `UPDATE teams SET channel_id = NULLIF($1, 0) WHERE id = $2`, channelID, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to set team channel: %w", err)
+	}
+
+	return nil
+}
+
+// SetDigestHour задает час публикации дайджеста
+func (r *TeamRepositoryPG) SetDigestHour(ctx context.Context, teamID int64, hour int) error {
+	_, err := r.db.Exec(ctx, `UPDATE teams SET digest_hour = $1 WHERE id = $2`, hour, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to set team digest hour: %w", err)
+	}
+
+	return nil
+}
+
+// SetDigestSent отмечает время публикации дайджеста
+func (r *TeamRepositoryPG) SetDigestSent(ctx context.Context, teamID int64, sentAt time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE teams SET last_digest_at = $1 WHERE id = $2`, sentAt, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to set team digest sent: %w", err)
+	}
+
+	return nil
+}
+
+// GetDigestEntries возвращает задачи участников команды, завершенные в промежутке [since, until),
+// в порядке участников и времени завершения
+func (r *TeamRepositoryPG) GetDigestEntries(ctx context.Context, teamID int64, since, until time.Time) ([]*entity.TeamDigestEntry, error) {
+	query := `
+		SELECT j.id, j.user_id, u.telegram_id, COALESCE(u.username, ''), COALESCE(u.first_name, ''),
+			COALESCE(j.file_name, ''), COALESCE(j.summary, ''), j.summary_gz, COALESCE(j.notion_page_id, ''), j.completed_at
+		FROM jobs j
+		JOIN team_members m ON m.user_id = j.user_id
+		JOIN users u ON u.id = j.user_id
+		WHERE m.team_id = $1
+			AND j.status = 'completed'
+			AND j.completed_at >= $2 AND j.completed_at < $3
+			AND (COALESCE(j.summary, '') <> '' OR j.summary_gz IS NOT NULL)
+		ORDER BY m.joined_at, j.user_id, j.completed_at
+	`
+
+	rows, err := r.db.Query(ctx, query, teamID, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team digest entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*entity.TeamDigestEntry, 0)
+	for rows.Next() {
+		entry := &entity.TeamDigestEntry{}
+		var packedSummary []byte
+		err := rows.Scan(
+			&entry.JobID,
+			&entry.UserID,
+			&entry.TelegramID,
+			&entry.Username,
+			&entry.FirstName,
+			&entry.FileName,
+			&entry.Summary,
+			&packedSummary,
+			&entry.NotionPageID,
+			&entry.CompletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan team digest entry: %w", err)
+		}
+		if entry.Summary, err = unpackText(entry.Summary, packedSummary); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate team digest entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// scanTeam считывает команду из строки результата запроса с колонками teamColumns
+func scanTeam(row pgx.Row) (*entity.Team, error) {
+	team := &entity.Team{}
+	err := row.Scan(
+		&team.ID,
+		&team.Name,
+		&team.OwnerID,
+		&team.InviteCode,
+		&team.ChannelID,
+		&team.DigestHour,
+		&team.LastDigestAt,
+		&team.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
@@ -0,0 +1,242 @@
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// compressionBatchSize - сколько задач сжимает одна транзакция при сжатии ранее сохраненных текстов
+const compressionBatchSize = 100
+
+// textCompressor сжимает длинные транскрипции и резюме задач перед записью в PostgreSQL.
+// Сжатый текст хранится в колонке *_gz, а текстовая колонка остается пустой. Тексты короче minSize байт
+// и все тексты при minSize <= 0 хранятся как есть
+type textCompressor struct {
+	minSize int
+}
+
+// pack возвращает значения текстовой и сжатой колонки для текста
+func (c textCompressor) pack(text string) (string, []byte, error) {
+	if c.minSize <= 0 || len(text) < c.minSize {
+		return text, nil, nil
+	}
+
+	packed, err := gzipText(text)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Несжимаемый текст выгоднее хранить как есть
+	if len(packed) >= len(text) {
+		return text, nil, nil
+	}
+
+	return "", packed, nil
+}
+
+// gzipText сжимает текст
+func gzipText(text string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := io.WriteString(writer, text); err != nil {
+		return nil, fmt.Errorf("failed to compress text: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress text: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// unpackText возвращает текст из текстовой и сжатой колонки. Сжатая колонка заполнена только у сжатого текста
+func unpackText(plain string, packed []byte) (string, error) {
+	if packed == nil {
+		return plain, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(packed))
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress text: %w", err)
+	}
+	defer reader.Close()
+
+	var builder strings.Builder
+	if _, err := io.Copy(&builder, reader); err != nil {
+		return "", fmt.Errorf("failed to decompress text: %w", err)
+	}
+
+	return builder.String(), nil
+}
+
+// storedJobTexts - транскрипция и резюме задачи в том виде, в котором они хранятся в таблице jobs
+type storedJobTexts struct {
+	transcription   string
+	transcriptionGz []byte
+	summary         string
+	summaryGz       []byte
+}
+
+// packJobTexts сжимает транскрипцию и резюме задачи для записи
+func (c textCompressor) packJobTexts(job *entity.Job) (storedJobTexts, error) {
+	var texts storedJobTexts
+	var err error
+
+	texts.transcription, texts.transcriptionGz, err = c.pack(job.Transcription)
+	if err != nil {
+		return texts, err
+	}
+	texts.summary, texts.summaryGz, err = c.pack(job.Summary)
+	if err != nil {
+		return texts, err
+	}
+
+	return texts, nil
+}
+
+// packedJobTexts - сжатые колонки транскрипции и резюме, прочитанные вместе с задачей
+type packedJobTexts struct {
+	transcription []byte
+	summary       []byte
+}
+
+// unpack заменяет транскрипцию и резюме задачи, прочитанные из текстовых колонок, распакованными текстами
+func (p packedJobTexts) unpack(job *entity.Job) error {
+	var err error
+
+	if job.Transcription, err = unpackText(job.Transcription, p.transcription); err != nil {
+		return err
+	}
+	if job.Summary, err = unpackText(job.Summary, p.summary); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CompressJobTexts сжимает транскрипции и резюме задач, сохраненные до включения сжатия или с большим minSize.
+// Задачи обрабатываются пакетами в отдельных транзакциях, а занятые другими запросами строки пропускаются,
+// поэтому сжатие можно выполнять на работающем приложении. Возвращает число сжатых задач
+func CompressJobTexts(ctx context.Context, db *PostgresDB, minSize int, logger *logger.Logger) (int64, error) {
+	if minSize <= 0 {
+		return 0, nil
+	}
+
+	start := time.Now()
+	compressor := textCompressor{minSize: minSize}
+	var total int64
+	var afterID int64
+	for {
+		count, lastID, err := compressJobTextsBatch(ctx, db, compressor, afterID)
+		if err != nil {
+			return total, err
+		}
+		total += count
+		if lastID == 0 {
+			break
+		}
+		afterID = lastID
+	}
+
+	// Логирование результата сжатия
+	logger.Info("Stored job texts compressed",
+		"jobs", total,
+		"min_size", minSize,
+		"duration", time.Since(start),
+	)
+
+	return total, nil
+}
+
+// compressJobTextsBatch сжимает пакет задач с ID больше afterID и возвращает число сжатых задач и ID последней
+// просмотренной задачи (0, если задач больше нет). Каждая колонка обновляется отдельно, чтобы не затереть
+// текст, который успели сжать при записи
+func compressJobTextsBatch(ctx context.Context, db *PostgresDB, compressor textCompressor, afterID int64) (int64, int64, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT id, COALESCE(transcription, ''), COALESCE(summary, '')
+		FROM jobs
+		WHERE id > $1
+			AND ((transcription_gz IS NULL AND length(transcription) >= $2)
+				OR (summary_gz IS NULL AND length(summary) >= $2))
+		ORDER BY id
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	`
+
+	// length считает символы, а порог задан в байтах: символов не больше, чем байт, поэтому
+	// отбор по символам не пропускает подходящие тексты, а лишние отсеивает pack
+	rows, err := tx.Query(ctx, query, afterID, compressor.minSize, compressionBatchSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get jobs for text compression: %w", err)
+	}
+
+	type jobTexts struct {
+		id            int64
+		transcription string
+		summary       string
+	}
+	jobs := make([]jobTexts, 0, compressionBatchSize)
+	for rows.Next() {
+		var job jobTexts
+		if err := rows.Scan(&job.id, &job.transcription, &job.summary); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("failed to scan job texts: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("failed to iterate job texts: %w", err)
+	}
+	if len(jobs) == 0 {
+		return 0, 0, nil
+	}
+
+	var count int64
+	for _, job := range jobs {
+		compressed := false
+		for _, column := range []struct{ name, text string }{
+			{"transcription", job.transcription},
+			{"summary", job.summary},
+		} {
+			plain, packed, err := compressor.pack(column.text)
+			if err != nil {
+				return 0, 0, err
+			}
+			if packed == nil {
+				continue
+			}
+
+			_, err = tx.Exec(ctx,
+				`UPDATE jobs SET `+column.name+` = $1, `+column.name+`_gz = $2 WHERE id = $3 AND `+column.name+`_gz IS NULL`,
+				plain, packed, job.id,
+			)
+			if err != nil {
+				return 0, 0, fmt.Errorf("failed to compress job %s: %w", column.name, err)
+			}
+			compressed = true
+		}
+		if compressed {
+			count++
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return count, jobs[len(jobs)-1].id, nil
+}
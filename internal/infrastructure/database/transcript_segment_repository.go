@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// likeEscaper экранирует спецсимволы шаблона LIKE, чтобы фраза искалась буквально
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// TranscriptSegmentRepositoryPG реализует интерфейс TranscriptSegmentRepository для PostgreSQL
+type TranscriptSegmentRepositoryPG struct {
+	db *PostgresDB
+}
+
+// NewTranscriptSegmentRepository создает новый репозиторий для работы с фрагментами транскрипций
+func NewTranscriptSegmentRepository(db *PostgresDB) repository.TranscriptSegmentRepository {
+	return &TranscriptSegmentRepositoryPG{db: db}
+}
+
+// ReplaceForJob заменяет все фрагменты транскрипции задачи переданными
+func (r *TranscriptSegmentRepositoryPG) ReplaceForJob(ctx context.Context, jobID int64, segments []*entity.TranscriptSegment) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Удаление старых фрагментов задачи
+	_, err = tx.Exec(ctx, `DELETE FROM transcript_segments WHERE job_id = $1`, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to delete transcript segments: %w", err)
+	}
+
+	query := `
+		INSERT INTO transcript_segments (job_id, position, start_seconds, text)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	for _, segment := range segments {
+		segment.JobID = jobID
+		err = tx.QueryRow(
+			ctx,
+			query,
+			jobID,
+			segment.Position,
+			segment.StartSeconds,
+			segment.Text,
+		).Scan(&segment.ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert transcript segment: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CountByJobID возвращает число фрагментов транскрипции задачи
+func (r *TranscriptSegmentRepositoryPG) CountByJobID(ctx context.Context, jobID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM transcript_segments WHERE job_id = $1`, jobID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count transcript segments: %w", err)
+	}
+
+	return count, nil
+}
+
+// Search возвращает фрагменты транскрипции задачи, содержащие фразу без учета регистра,
+// вместе с предыдущим и следующим фрагментами
+func (r *TranscriptSegmentRepositoryPG) Search(ctx context.Context, jobID int64, phrase string, limit int) ([]*entity.TranscriptExcerpt, error) {
+	query := `
+		SELECT s.start_seconds, COALESCE(prev.text, ''), s.text, COALESCE(next.text, '')
+		FROM transcript_segments s
+		LEFT JOIN transcript_segments prev ON prev.job_id = s.job_id AND prev.position = s.position - 1
+		LEFT JOIN transcript_segments next ON next.job_id = s.job_id AND next.position = s.position + 1
+		WHERE s.job_id = $1 AND s.text ILIKE '%' || $2 || '%'
+		ORDER BY s.position
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, jobID, likeEscaper.Replace(phrase), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transcript segments: %w", err)
+	}
+	defer rows.Close()
+
+	excerpts := make([]*entity.TranscriptExcerpt, 0)
+	for rows.Next() {
+		excerpt := &entity.TranscriptExcerpt{}
+		err := rows.Scan(
+			&excerpt.StartSeconds,
+			&excerpt.Before,
+			&excerpt.Text,
+			&excerpt.After,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transcript segment: %w", err)
+		}
+		excerpts = append(excerpts, excerpt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate transcript segments: %w", err)
+	}
+
+	return excerpts, nil
+}
+
+// GetRange возвращает фрагменты транскрипции задачи, начинающиеся в промежутке [fromSeconds, toSeconds),
+// в порядке следования
+func (r *TranscriptSegmentRepositoryPG) GetRange(ctx context.Context, jobID int64, fromSeconds, toSeconds int) ([]*entity.TranscriptSegment, error) {
+	query := `
+		SELECT id, job_id, position, start_seconds, text
+		FROM transcript_segments
+		WHERE job_id = $1 AND start_seconds >= $2 AND start_seconds < $3
+		ORDER BY position
+	`
+
+	rows, err := r.db.Query(ctx, query, jobID, fromSeconds, toSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcript segments: %w", err)
+	}
+	defer rows.Close()
+
+	segments := make([]*entity.TranscriptSegment, 0)
+	for rows.Next() {
+		segment := &entity.TranscriptSegment{}
+		err := rows.Scan(
+			&segment.ID,
+			&segment.JobID,
+			&segment.Position,
+			&segment.StartSeconds,
+			&segment.Text,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transcript segment: %w", err)
+		}
+		segments = append(segments, segment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate transcript segments: %w", err)
+	}
+
+	return segments, nil
+}
+
+// LastStartSeconds возвращает начало последнего фрагмента с меткой времени или nil, если меток времени нет
+func (r *TranscriptSegmentRepositoryPG) LastStartSeconds(ctx context.Context, jobID int64) (*int, error) {
+	var lastStart *int
+	err := r.db.QueryRow(ctx, `SELECT MAX(start_seconds) FROM transcript_segments WHERE job_id = $1`, jobID).Scan(&lastStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last transcript segment start: %w", err)
+	}
+
+	return lastStart, nil
+}
@@ -0,0 +1,212 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+)
+
+// userCacheName - имя кэша пользователей в метриках
+const userCacheName = "user"
+
+// userCacheTombstone - значение ключа профиля после сброса кэша. Пока отметка жива, профиль не кэшируется заново:
+// иначе чтение, загрузившее профиль из базы до обновления, записало бы в кэш старую версию уже после сброса
+const userCacheTombstone = "-"
+
+// userCacheTombstoneTTL - сколько живет отметка о сбросе профиля; за это время завершаются чтения,
+// начавшиеся до обновления пользователя
+const userCacheTombstoneTTL = 10 * time.Second
+
+// cachedUser - профиль пользователя в кэше. Токен Notion в Redis не хранится: вместо него записывается
+// только признак, что Notion подключен
+type cachedUser struct {
+	*entity.User
+	NotionConnected bool `json:"notion_connected"`
+}
+
+// CachedUserRepository кэширует профили пользователей в Redis поверх репозитория PostgreSQL.
+// Профиль хранится под двумя ключами (по ID и по Telegram ID) и сбрасывается из кэша при обновлении и удалении пользователя.
+// Токен Notion в кэш не попадает, поэтому профили пользователей с подключенным Notion читаются из базы,
+// а из кэша для них берется только блокировка. Ошибки Redis не мешают работе: при недоступном кэше запросы уходят в базу
+type CachedUserRepository struct {
+	repo  repository.UserRepository
+	redis *RedisClient
+	ttl   time.Duration
+}
+
+// NewCachedUserRepository создает репозиторий пользователей с кэшем в Redis.
+// ttl <= 0 отключает кэш и возвращает исходный репозиторий
+func NewCachedUserRepository(repo repository.UserRepository, redis *RedisClient, ttl time.Duration) repository.UserRepository {
+	if ttl <= 0 {
+		return repo
+	}
+	return &CachedUserRepository{repo: repo, redis: redis, ttl: ttl}
+}
+
+// Create создает нового пользователя. Отсутствие пользователя не кэшируется, поэтому сбрасывать нечего
+func (r *CachedUserRepository) Create(ctx context.Context, user *entity.User) error {
+	return r.repo.Create(ctx, user)
+}
+
+// GetByTelegramID возвращает пользователя по его Telegram ID
+func (r *CachedUserRepository) GetByTelegramID(ctx context.Context, telegramID int64) (*entity.User, error) {
+	return r.get(ctx, userByTelegramIDKey(telegramID), func() (*entity.User, error) {
+		return r.repo.GetByTelegramID(ctx, telegramID)
+	})
+}
+
+// GetByID возвращает пользователя по его ID
+func (r *CachedUserRepository) GetByID(ctx context.Context, id int64) (*entity.User, error) {
+	return r.get(ctx, userByIDKey(id), func() (*entity.User, error) {
+		return r.repo.GetByID(ctx, id)
+	})
+}
+
+// Update обновляет информацию о пользователе и сбрасывает его профиль из кэша.
+// Если сбросить профиль не удалось, возвращается ошибка: иначе до истечения TTL читался бы старый профиль
+func (r *CachedUserRepository) Update(ctx context.Context, user *entity.User) error {
+	if err := r.repo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return r.invalidate(ctx, user.ID, user.TelegramID)
+}
+
+// Delete удаляет пользователя и сбрасывает его профиль из кэша
+func (r *CachedUserRepository) Delete(ctx context.Context, user *entity.User) error {
+	if err := r.repo.Delete(ctx, user); err != nil {
+		return err
+	}
+
+	return r.invalidate(ctx, user.ID, user.TelegramID)
+}
+
+// SetAdmins отмечает администраторов. Профили в кэше не сбрасываются: отметка меняется только при запуске
+// приложения и используется лишь в статистике, поэтому до истечения TTL может читаться прежняя
+func (r *CachedUserRepository) SetAdmins(ctx context.Context, telegramIDs []int64) error {
+	return r.repo.SetAdmins(ctx, telegramIDs)
+}
+
+// SetBan блокирует пользователя или снимает блокировку и сбрасывает его профиль из кэша
+func (r *CachedUserRepository) SetBan(ctx context.Context, telegramID int64, bannedAt *time.Time, reason string) (bool, error) {
+	updated, err := r.repo.SetBan(ctx, telegramID, bannedAt, reason)
+	if err != nil || !updated {
+		return updated, err
+	}
+
+	// ID пользователя нужен для ключа кэша по ID
+	user, err := r.repo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		return false, err
+	}
+	if err := r.invalidate(ctx, user.ID, telegramID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// IsBanned сообщает, что пользователь заблокирован. Проверка выполняется для каждого сообщения, поэтому
+// блокировка берется из профиля в кэше (в том числе у пользователей с подключенным Notion);
+// незарегистрированного пользователя проверяет база
+func (r *CachedUserRepository) IsBanned(ctx context.Context, telegramID int64) (bool, error) {
+	key := userByTelegramIDKey(telegramID)
+	if cached, ok := r.lookup(ctx, key); ok {
+		metrics.Default.ObserveCache(userCacheName, metrics.CacheHit)
+		return cached.BannedAt != nil, nil
+	}
+	metrics.Default.ObserveCache(userCacheName, metrics.CacheMiss)
+
+	user, err := r.repo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		return r.repo.IsBanned(ctx, telegramID)
+	}
+	r.fill(ctx, user)
+	return user.BannedAt != nil, nil
+}
+
+// GetBroadcastRecipients возвращает получателей рассылки без кэша
+func (r *CachedUserRepository) GetBroadcastRecipients(ctx context.Context, afterID int64, limit int) ([]*entity.User, error) {
+	return r.repo.GetBroadcastRecipients(ctx, afterID, limit)
+}
+
+// GetBaseStats возвращает сводку по пользователям без кэша
+func (r *CachedUserRepository) GetBaseStats(ctx context.Context, since time.Time) (*entity.UserBaseStats, error) {
+	return r.repo.GetBaseStats(ctx, since)
+}
+
+// get возвращает профиль из кэша, а при промахе загружает его из базы и кэширует под обоими ключами.
+// Профиль пользователя с подключенным Notion всегда загружается из базы, так как в кэше нет токена
+func (r *CachedUserRepository) get(ctx context.Context, key string, load func() (*entity.User, error)) (*entity.User, error) {
+	cached, ok := r.lookup(ctx, key)
+	if ok && !cached.NotionConnected {
+		metrics.Default.ObserveCache(userCacheName, metrics.CacheHit)
+		return cached.User, nil
+	}
+	metrics.Default.ObserveCache(userCacheName, metrics.CacheMiss)
+
+	user, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		r.fill(ctx, user)
+	}
+
+	return user, nil
+}
+
+// lookup возвращает профиль из кэша или false, если профиля в кэше нет или он сброшен
+func (r *CachedUserRepository) lookup(ctx context.Context, key string) (*cachedUser, bool) {
+	data, err := r.redis.Get(ctx, key)
+	if err != nil || data == userCacheTombstone {
+		return nil, false
+	}
+
+	cached := &cachedUser{User: &entity.User{}}
+	if err := json.Unmarshal([]byte(data), cached); err != nil {
+		return nil, false
+	}
+	return cached, true
+}
+
+// fill кэширует загруженный из базы профиль под обоими ключами без токена Notion. Профиль записывается,
+// только если ключа нет: отметка о сбросе после обновления не дает записать загруженную до него версию.
+// Ошибка записи в кэш только приводит к повторному запросу в базу
+func (r *CachedUserRepository) fill(ctx context.Context, user *entity.User) {
+	profile := *user
+	profile.NotionToken = ""
+	data, err := json.Marshal(cachedUser{User: &profile, NotionConnected: user.NotionToken != ""})
+	if err != nil {
+		return
+	}
+
+	for _, key := range []string{userByIDKey(user.ID), userByTelegramIDKey(user.TelegramID)} {
+		_ = r.redis.Client().SetNX(ctx, key, data, r.ttl).Err()
+	}
+}
+
+// invalidate сбрасывает профиль пользователя из кэша, записывая под обоими ключами отметку о сбросе
+func (r *CachedUserRepository) invalidate(ctx context.Context, id, telegramID int64) error {
+	for _, key := range []string{userByIDKey(id), userByTelegramIDKey(telegramID)} {
+		if err := r.redis.Set(ctx, key, userCacheTombstone, userCacheTombstoneTTL); err != nil {
+			return fmt.Errorf("failed to invalidate user cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// userByIDKey возвращает ключ кэша профиля по ID пользователя
+func userByIDKey(id int64) string {
+	return fmt.Sprintf("cache:user:id:%d", id)
+}
+
+// userByTelegramIDKey возвращает ключ кэша профиля по Telegram ID пользователя
+func userByTelegramIDKey(telegramID int64) string {
+	return fmt.Sprintf("cache:user:tg:%d", telegramID)
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -26,10 +27,13 @@ func (r *UserRepositoryPG) Create(ctx context.Context, user *entity.User) error
 	now := time.Now()
 	user.CreatedAt = now
 	user.UpdatedAt = now
+	if user.ChatType == "" {
+		user.ChatType = entity.ChatTypePrivate
+	}
 
 	query := `
-		INSERT INTO users (telegram_id, username, first_name, last_name, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (telegram_id, username, first_name, last_name, chat_type, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id
 	`
 
@@ -40,6 +44,7 @@ func (r *UserRepositoryPG) Create(ctx context.Context, user *entity.User) error
 		user.Username,
 		user.FirstName,
 		user.LastName,
+		user.ChatType,
 		user.CreatedAt,
 		user.UpdatedAt,
 	).Scan(&user.ID)
@@ -54,22 +59,58 @@ func (r *UserRepositoryPG) Create(ctx context.Context, user *entity.User) error
 // GetByTelegramID возвращает пользователя по его Telegram ID
 func (r *UserRepositoryPG) GetByTelegramID(ctx context.Context, telegramID int64) (*entity.User, error) {
 	query := `
-		SELECT id, telegram_id, username, first_name, last_name, created_at, updated_at
+		SELECT id, telegram_id, username, first_name, last_name,
+			COALESCE(notion_token, ''), COALESCE(notion_database_id, ''), COALESCE(notion_hub_page_id, ''),
+			result_retention_hours, transcription_options, COALESCE(summary_language, ''),
+			COALESCE(language_code, ''), chat_type, is_admin, banned_at, COALESCE(ban_reason, ''),
+			created_at, updated_at
 		FROM users
 		WHERE telegram_id = $1
 	`
 
+	return r.getUser(ctx, query, telegramID)
+}
+
+// GetByID возвращает пользователя по его ID
+func (r *UserRepositoryPG) GetByID(ctx context.Context, id int64) (*entity.User, error) {
+	query := `
+		SELECT id, telegram_id, username, first_name, last_name,
+			COALESCE(notion_token, ''), COALESCE(notion_database_id, ''), COALESCE(notion_hub_page_id, ''),
+			result_retention_hours, transcription_options, COALESCE(summary_language, ''),
+			COALESCE(language_code, ''), chat_type, is_admin, banned_at, COALESCE(ban_reason, ''),
+			created_at, updated_at
+		FROM users
+		WHERE id = $1
+	`
+
+	return r.getUser(ctx, query, id)
+}
+
+// getUser выполняет запрос на получение одного пользователя
+func (r *UserRepositoryPG) getUser(ctx context.Context, query string, args ...interface{}) (*entity.User, error) {
 	user := &entity.User{}
+	var transcriptionOptions []byte
 	err := r.db.QueryRow(
 		ctx,
 		query,
-		telegramID,
+		args...,
 	).Scan(
 		&user.ID,
 		&user.TelegramID,
 		&user.Username,
 		&user.FirstName,
 		&user.LastName,
+		&user.NotionToken,
+		&user.NotionDatabaseID,
+		&user.NotionHubPageID,
+		&user.ResultRetentionHours,
+		&transcriptionOptions,
+		&user.SummaryLanguage,
+		&user.LanguageCode,
+		&user.ChatType,
+		&user.IsAdmin,
+		&user.BannedAt,
+		&user.BanReason,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -81,6 +122,10 @@ func (r *UserRepositoryPG) GetByTelegramID(ctx context.Context, telegramID int64
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
+	if err := json.Unmarshal(transcriptionOptions, &user.TranscriptionOptions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transcription options: %w", err)
+	}
+
 	return user, nil
 }
 
@@ -88,18 +133,35 @@ func (r *UserRepositoryPG) GetByTelegramID(ctx context.Context, telegramID int64
 func (r *UserRepositoryPG) Update(ctx context.Context, user *entity.User) error {
 	user.UpdatedAt = time.Now()
 
+	transcriptionOptions, err := json.Marshal(user.TranscriptionOptions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transcription options: %w", err)
+	}
+
 	query := `
 		UPDATE users
-		SET username = $1, first_name = $2, last_name = $3, updated_at = $4
-		WHERE id = $5
+		SET telegram_id = $1, username = $2, first_name = $3, last_name = $4,
+			notion_token = $5, notion_database_id = $6, notion_hub_page_id = $7,
+			result_retention_hours = $8, transcription_options = $9, summary_language = NULLIF($10, ''),
+			language_code = NULLIF($11, ''), chat_type = COALESCE(NULLIF($12, ''), chat_type), updated_at = $13
+		WHERE id = $14
 	`
 
-	_, err := r.db.Exec(
+	_, err = r.db.Exec(
 		ctx,
 		query,
+		user.TelegramID,
 		user.Username,
 		user.FirstName,
 		user.LastName,
+		user.NotionToken,
+		user.NotionDatabaseID,
+		user.NotionHubPageID,
+		user.ResultRetentionHours,
+		transcriptionOptions,
+		user.SummaryLanguage,
+		user.LanguageCode,
+		user.ChatType,
 		user.UpdatedAt,
 		user.ID,
 	)
@@ -110,3 +172,115 @@ func (r *UserRepositoryPG) Update(ctx context.Context, user *entity.User) error
 
 	return nil
 }
+
+// Delete удаляет пользователя. Задачи, настройки, подписки и другие связанные записи удаляются каскадно
+func (r *UserRepositoryPG) Delete(ctx context.Context, user *entity.User) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM users WHERE id = $1`, user.ID); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return nil
+}
+
+// SetAdmins отмечает администраторами пользователей с указанными Telegram ID и снимает отметку с остальных
+func (r *UserRepositoryPG) SetAdmins(ctx context.Context, telegramIDs []int64) error {
+	query := `
+		UPDATE users
+		SET is_admin = telegram_id = ANY($1)
+		WHERE is_admin <> (telegram_id = ANY($1))
+	`
+
+	if _, err := r.db.Exec(ctx, query, telegramIDs); err != nil {
+		return fmt.Errorf("failed to set admins: %w", err)
+	}
+
+	return nil
+}
+
+// SetBan блокирует пользователя или снимает блокировку, если bannedAt - nil
+func (r *UserRepositoryPG) SetBan(ctx context.Context, telegramID int64, bannedAt *time.Time, reason string) (bool, error) {
+	query := `
+		UPDATE users
+		SET banned_at = $1, ban_reason = NULLIF($2, ''), updated_at = $3
+		WHERE telegram_id = $4
+	`
+
+	tag, err := r.db.Exec(ctx, query, bannedAt, reason, time.Now(), telegramID)
+	if err != nil {
+		return false, fmt.Errorf("failed to set user ban: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// IsBanned сообщает, что пользователь с указанным Telegram ID заблокирован
+func (r *UserRepositoryPG) IsBanned(ctx context.Context, telegramID int64) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM users WHERE telegram_id = $1 AND banned_at IS NOT NULL)`
+
+	var banned bool
+	if err := r.db.QueryRow(ctx, query, telegramID).Scan(&banned); err != nil {
+		return false, fmt.Errorf("failed to check user ban: %w", err)
+	}
+
+	return banned, nil
+}
+
+// GetBroadcastRecipients возвращает незаблокированных пользователей личных чатов с ID больше afterID
+func (r *UserRepositoryPG) GetBroadcastRecipients(ctx context.Context, afterID int64, limit int) ([]*entity.User, error) {
+	query := `
+		SELECT id, telegram_id, COALESCE(language_code, '')
+		FROM users
+		WHERE id > $1 AND chat_type = $2 AND banned_at IS NULL
+		ORDER BY id
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, afterID, entity.ChatTypePrivate, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broadcast recipients: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*entity.User, 0)
+	for rows.Next() {
+		user := &entity.User{}
+		if err := rows.Scan(&user.ID, &user.TelegramID, &user.LanguageCode); err != nil {
+			return nil, fmt.Errorf("failed to scan broadcast recipient: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate broadcast recipients: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetBaseStats возвращает сводку по пользователям; новыми считаются зарегистрированные начиная с since
+func (r *UserRepositoryPG) GetBaseStats(ctx context.Context, since time.Time) (*entity.UserBaseStats, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE chat_type = $2),
+			COUNT(*) FILTER (WHERE chat_type <> $2),
+			COUNT(*) FILTER (WHERE chat_type = $2 AND created_at >= $1),
+			COUNT(*) FILTER (WHERE banned_at IS NOT NULL),
+			COUNT(*) FILTER (WHERE is_admin)
+		FROM users
+	`
+
+	stats := &entity.UserBaseStats{}
+	err := r.db.QueryRow(ctx, query, since, entity.ChatTypePrivate).Scan(
+		&stats.Users,
+		&stats.Groups,
+		&stats.NewSince,
+		&stats.Banned,
+		&stats.Admins,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user base stats: %w", err)
+	}
+
+	return stats, nil
+}
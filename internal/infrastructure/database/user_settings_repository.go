@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// UserSettingsRepositoryPG реализует интерфейс UserSettingsRepository для PostgreSQL
+type UserSettingsRepositoryPG struct {
+	db *PostgresDB
+}
+
+// NewUserSettingsRepository создает новый репозиторий для работы с настройками пользователей
+func NewUserSettingsRepository(db *PostgresDB) repository.UserSettingsRepository {
+	return &UserSettingsRepositoryPG{db: db}
+}
+
+// GetByUserID возвращает настройки пользователя или nil, если он их не менял
+func (r *UserSettingsRepositoryPG) GetByUserID(ctx context.Context, userID int64) (*entity.UserSettings, error) {
+	query := `
+		SELECT user_id, transcription_language, summary_style, notion_auto_sync,
+			failed_jobs_cleanup, failed_jobs_cleanup_at, usage_analytics_opt_out, COALESCE(interface_language, ''),
+			sentiment_analysis, summary_style_remembered, notion_transcript, notion_summary_only,
+			notion_audio_stats, notion_usage_cost, updated_at
+		FROM user_settings
+		WHERE user_id = $1
+	`
+
+	settings := &entity.UserSettings{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&settings.UserID,
+		&settings.TranscriptionLanguage,
+		&settings.SummaryStyle,
+		&settings.NotionAutoSync,
+		&settings.FailedJobsCleanup,
+		&settings.FailedJobsCleanupAt,
+		&settings.UsageAnalyticsOptOut,
+		&settings.InterfaceLanguage,
+		&settings.SentimentAnalysis,
+		&settings.SummaryStyleRemembered,
+		&settings.NotionTranscript,
+		&settings.NotionSummaryOnly,
+		&settings.NotionAudioStats,
+		&settings.NotionUsageCost,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Upsert сохраняет настройки пользователя
+func (r *UserSettingsRepositoryPG) Upsert(ctx context.Context, settings *entity.UserSettings) error {
+	settings.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO user_settings (user_id, transcription_language, summary_style, notion_auto_sync, failed_jobs_cleanup, usage_analytics_opt_out, interface_language, sentiment_analysis, summary_style_remembered,
+			notion_transcript, notion_summary_only, notion_audio_stats, notion_usage_cost, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (user_id) DO UPDATE
+		SET transcription_language = EXCLUDED.transcription_language,
+			summary_style = EXCLUDED.summary_style,
+			notion_auto_sync = EXCLUDED.notion_auto_sync,
+			failed_jobs_cleanup = EXCLUDED.failed_jobs_cleanup,
+			usage_analytics_opt_out = EXCLUDED.usage_analytics_opt_out,
+			interface_language = EXCLUDED.interface_language,
+			sentiment_analysis = EXCLUDED.sentiment_analysis,
+			summary_style_remembered = EXCLUDED.summary_style_remembered,
+			notion_transcript = EXCLUDED.notion_transcript,
+			notion_summary_only = EXCLUDED.notion_summary_only,
+			notion_audio_stats = EXCLUDED.notion_audio_stats,
+			notion_usage_cost = EXCLUDED.notion_usage_cost,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		settings.UserID,
+		settings.TranscriptionLanguage,
+		settings.SummaryStyle,
+		settings.NotionAutoSync,
+		settings.FailedJobsCleanup,
+		settings.UsageAnalyticsOptOut,
+		settings.InterfaceLanguage,
+		settings.SentimentAnalysis,
+		settings.SummaryStyleRemembered,
+		settings.NotionTranscript,
+		settings.NotionSummaryOnly,
+		settings.NotionAudioStats,
+		settings.NotionUsageCost,
+		settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert user settings: %w", err)
+	}
+
+	return nil
+}
+
+// GetForFailedJobsCleanup возвращает настройки пользователей с включенным разбором задач с ошибками,
+// у которых разбора не было после before
+func (r *UserSettingsRepositoryPG) GetForFailedJobsCleanup(ctx context.Context, before time.Time, limit int) ([]*entity.UserSettings, error) {
+	query := `
+		SELECT user_id, transcription_language, summary_style, notion_auto_sync,
+			failed_jobs_cleanup, failed_jobs_cleanup_at, usage_analytics_opt_out, COALESCE(interface_language, ''),
+			sentiment_analysis, summary_style_remembered, notion_transcript, notion_summary_only,
+			notion_audio_stats, notion_usage_cost, updated_at
+		FROM user_settings
+		WHERE failed_jobs_cleanup
+			AND (failed_jobs_cleanup_at IS NULL OR failed_jobs_cleanup_at < $1)
+		ORDER BY user_id
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user settings for failed jobs cleanup: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.UserSettings, 0)
+	for rows.Next() {
+		settings := &entity.UserSettings{}
+		err := rows.Scan(
+			&settings.UserID,
+			&settings.TranscriptionLanguage,
+			&settings.SummaryStyle,
+			&settings.NotionAutoSync,
+			&settings.FailedJobsCleanup,
+			&settings.FailedJobsCleanupAt,
+			&settings.UsageAnalyticsOptOut,
+			&settings.InterfaceLanguage,
+			&settings.SentimentAnalysis,
+			&settings.SummaryStyleRemembered,
+			&settings.NotionTranscript,
+			&settings.NotionSummaryOnly,
+			&settings.NotionAudioStats,
+			&settings.NotionUsageCost,
+			&settings.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user settings: %w", err)
+		}
+		result = append(result, settings)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate user settings: %w", err)
+	}
+
+	return result, nil
+}
+
+// ClaimFailedJobsCleanup отмечает время разбора задач с ошибками пользователя, если разбора не было после before.
+// Возвращает false, если разбор уже выполнил другой экземпляр приложения
+func (r *UserSettingsRepositoryPG) ClaimFailedJobsCleanup(ctx context.Context, userID int64, before, now time.Time) (bool, error) {
+	query := `
+		UPDATE user_settings
+		SET failed_jobs_cleanup_at = $1
+		WHERE user_id = $2 AND (failed_jobs_cleanup_at IS NULL OR failed_jobs_cleanup_at < $3)
+	`
+
+	tag, err := r.db.Exec(ctx, query, now, userID, before)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim failed jobs cleanup: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
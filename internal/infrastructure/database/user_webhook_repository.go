@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// UserWebhookRepositoryPG реализует интерфейс UserWebhookRepository для PostgreSQL
+type UserWebhookRepositoryPG struct {
+	db *PostgresDB
+}
+
+// NewUserWebhookRepository создает новый репозиторий для работы с вебхуками пользователей
+func NewUserWebhookRepository(db *PostgresDB) repository.UserWebhookRepository {
+	return &UserWebhookRepositoryPG{db: db}
+}
+
+// Save создает или обновляет вебхук пользователя
+func (r *UserWebhookRepositoryPG) Save(ctx context.Context, webhook *entity.UserWebhook) error {
+	events := make([]string, len(webhook.Events))
+	for i, event := range webhook.Events {
+		events[i] = string(event)
+	}
+
+	headers := webhook.Headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	headersJSON, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook headers: %w", err)
+	}
+
+	webhook.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO user_webhooks (user_id, url, events, headers, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET url = EXCLUDED.url, events = EXCLUDED.events, headers = EXCLUDED.headers, updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`
+
+	err = r.db.QueryRow(ctx, query, webhook.UserID, webhook.URL, events, headersJSON, webhook.UpdatedAt).Scan(&webhook.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save user webhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID возвращает вебхук пользователя или nil
+func (r *UserWebhookRepositoryPG) GetByUserID(ctx context.Context, userID int64) (*entity.UserWebhook, error) {
+	query := `
+		SELECT user_id, url, events, headers, created_at, updated_at
+		FROM user_webhooks
+		WHERE user_id = $1
+	`
+
+	webhook := &entity.UserWebhook{}
+	var events []string
+	var headersJSON []byte
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&webhook.UserID,
+		&webhook.URL,
+		&events,
+		&headersJSON,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user webhook: %w", err)
+	}
+
+	webhook.Events = make([]entity.WebhookEvent, len(events))
+	for i, event := range events {
+		webhook.Events[i] = entity.WebhookEvent(event)
+	}
+	if err := json.Unmarshal(headersJSON, &webhook.Headers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook headers: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// Delete удаляет вебхук пользователя и сообщает, был ли он настроен
+func (r *UserWebhookRepositoryPG) Delete(ctx context.Context, userID int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM user_webhooks WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete user webhook: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
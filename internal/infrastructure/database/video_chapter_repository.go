@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// VideoChapterRepositoryPG реализует интерфейс VideoChapterRepository для PostgreSQL
+type VideoChapterRepositoryPG struct {
+	db *PostgresDB
+}
+
+// NewVideoChapterRepository создает новый репозиторий для работы с главами видео
+func NewVideoChapterRepository(db *PostgresDB) repository.VideoChapterRepository {
+	return &VideoChapterRepositoryPG{db: db}
+}
+
+// ReplaceForJob заменяет все главы видео задачи переданными
+func (r *VideoChapterRepositoryPG) ReplaceForJob(ctx context.Context, jobID int64, chapters []*entity.VideoChapter) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Удаление старых глав задачи
+	_, err = tx.Exec(ctx, `DELETE FROM video_chapters WHERE job_id = $1`, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to delete video chapters: %w", err)
+	}
+
+	query := `
+		INSERT INTO video_chapters (job_id, position, title, start_seconds, end_seconds)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	for _, chapter := range chapters {
+		chapter.JobID = jobID
+		_, err = tx.Exec(
+			ctx,
+			query,
+			jobID,
+			chapter.Position,
+			chapter.Title,
+			chapter.StartSeconds,
+			chapter.EndSeconds,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert video chapter: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByJobID возвращает главы видео задачи по порядку
+func (r *VideoChapterRepositoryPG) GetByJobID(ctx context.Context, jobID int64) ([]*entity.VideoChapter, error) {
+	query := `
+		SELECT job_id, position, title, start_seconds, end_seconds
+		FROM video_chapters
+		WHERE job_id = $1
+		ORDER BY position
+	`
+
+	rows, err := r.db.Query(ctx, query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video chapters: %w", err)
+	}
+	defer rows.Close()
+
+	chapters := make([]*entity.VideoChapter, 0)
+	for rows.Next() {
+		chapter := &entity.VideoChapter{}
+		err := rows.Scan(
+			&chapter.JobID,
+			&chapter.Position,
+			&chapter.Title,
+			&chapter.StartSeconds,
+			&chapter.EndSeconds,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan video chapter: %w", err)
+		}
+		chapters = append(chapters, chapter)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate video chapters: %w", err)
+	}
+
+	return chapters, nil
+}
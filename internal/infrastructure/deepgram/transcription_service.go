@@ -0,0 +1,273 @@
+package deepgram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
+)
+
+// Адреса Deepgram API
+const (
+	listenURL   = "https://api.deepgram.com/v1/listen"
+	projectsURL = "https://api.deepgram.com/v1/projects"
+)
+
+// defaultModel - модель распознавания по умолчанию
+const defaultModel = "nova-2"
+
+// maxErrorBodySize - сколько байт ответа с ошибкой попадает в текст ошибки
+const maxErrorBodySize = 1024
+
+// TranscriptionService представляет собой сервис транскрибации аудио через Deepgram API
+type TranscriptionService struct {
+	client      *http.Client
+	apiKey      func() string
+	model       string
+	smartFormat bool
+	defaults    entity.TranscriptionOptions
+	logger      *logger.Logger
+}
+
+// NewTranscriptionService создает новый сервис транскрибации через Deepgram. smartFormat включает пунктуацию,
+// абзацы и форматирование чисел. defaults задает параметры, которые пользователь может переопределить;
+// из них Deepgram учитывает язык и детализацию. Разделение по говорящим выполняет TranscribeWithSpeakers
+func NewTranscriptionService(apiKey func() string, model string, smartFormat bool, defaults entity.TranscriptionOptions, logger *logger.Logger) *TranscriptionService {
+	if model == "" {
+		model = defaultModel
+	}
+	if defaults.Granularity == "" {
+		defaults.Granularity = entity.TranscriptionGranularityText
+	}
+
+	return &TranscriptionService{
+		// Длительность распознавания ограничивает контекст этапа
+		client:      &http.Client{},
+		apiKey:      apiKey,
+		model:       model,
+		smartFormat: smartFormat,
+		defaults:    defaults,
+		logger:      logger,
+	}
+}
+
+// listenResponse - часть ответа Deepgram, из которой собирается текст
+type listenResponse struct {
+	Results struct {
+		Channels []struct {
+			Alternatives []struct {
+				Transcript string `json:"transcript"`
+				Paragraphs *struct {
+					Transcript string `json:"transcript"`
+				} `json:"paragraphs"`
+			} `json:"alternatives"`
+		} `json:"channels"`
+		Utterances []utterance `json:"utterances"`
+	} `json:"results"`
+}
+
+// utterance - фраза одного говорящего
+type utterance struct {
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Speaker    int     `json:"speaker"`
+	Transcript string  `json:"transcript"`
+}
+
+// Transcribe выполняет транскрибацию аудиофайла с параметрами по умолчанию
+func (s *TranscriptionService) Transcribe(ctx context.Context, audioFilePath string) (string, error) {
+	return s.TranscribeWithOptions(ctx, audioFilePath, entity.TranscriptionOptions{})
+}
+
+// TranscribeWithOptions транскрибирует аудиофайл с параметрами, переопределяющими значения по умолчанию.
+// Температура и подсказка относятся к Whisper и в Deepgram не передаются
+func (s *TranscriptionService) TranscribeWithOptions(ctx context.Context, audioFilePath string, overrides entity.TranscriptionOptions) (string, error) {
+	opts := s.defaults.Merge(overrides)
+
+	// Логирование начала транскрибации
+	s.logger.Info("Transcribing audio with Deepgram",
+		"path", audioFilePath,
+		"model", s.model,
+		"granularity", opts.Granularity,
+		"language", opts.Language,
+	)
+
+	// Выполнение запроса
+	var resp listenResponse
+	err := metrics.Track(ctx, s.logger, metrics.ProviderDeepgram, "transcription", func(ctx context.Context) error {
+		return s.post(ctx, audioFilePath, s.query(opts, false), &resp)
+	})
+	if err != nil {
+		s.logger.Error("Failed to transcribe audio with Deepgram",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to transcribe audio with Deepgram: %w", err)
+	}
+
+	text := formatText(resp, opts)
+
+	// Логирование успешной транскрибации
+	s.logger.Info("Audio transcribed with Deepgram successfully",
+		"text_length", len(text),
+	)
+
+	return text, nil
+}
+
+// TranscribeWithSpeakers транскрибирует аудиофайл с разделением по говорящим и возвращает фразы с номерами
+// говорящих. Подписи говорящих в тексте транскрипции расставляет сценарий обработки
+func (s *TranscriptionService) TranscribeWithSpeakers(ctx context.Context, audioFilePath string, overrides entity.TranscriptionOptions) ([]entity.SpeakerSegment, error) {
+	opts := s.defaults.Merge(overrides)
+
+	// Логирование начала транскрибации
+	s.logger.Info("Transcribing audio with speakers with Deepgram",
+		"path", audioFilePath,
+		"model", s.model,
+		"language", opts.Language,
+	)
+
+	// Выполнение запроса
+	var resp listenResponse
+	err := metrics.Track(ctx, s.logger, metrics.ProviderDeepgram, "transcription", func(ctx context.Context) error {
+		return s.post(ctx, audioFilePath, s.query(opts, true), &resp)
+	})
+	if err != nil {
+		s.logger.Error("Failed to transcribe audio with speakers with Deepgram",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to transcribe audio with speakers with Deepgram: %w", err)
+	}
+
+	// Deepgram нумерует говорящих с 0
+	segments := make([]entity.SpeakerSegment, 0, len(resp.Results.Utterances))
+	for _, u := range resp.Results.Utterances {
+		segments = append(segments, entity.SpeakerSegment{
+			Start:   u.Start,
+			End:     u.End,
+			Speaker: u.Speaker + 1,
+			Text:    strings.TrimSpace(u.Transcript),
+		})
+	}
+
+	// Логирование успешной транскрибации
+	s.logger.Info("Audio transcribed with speakers with Deepgram successfully",
+		"segments", len(segments),
+	)
+
+	return segments, nil
+}
+
+// post отправляет запись в Deepgram. Файл передается потоком, не загружаясь в память целиком
+func (s *TranscriptionService) post(ctx context.Context, audioFilePath string, query url.Values, out *listenResponse) error {
+	audioFile, err := os.Open(audioFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer audioFile.Close()
+
+	info, err := audioFile.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat audio file: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, listenURL+"?"+query.Encode(), audioFile)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Authorization", "Token "+s.apiKey())
+	req.Header.Set("Content-Type", "audio/wav")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fmt.Errorf("Deepgram returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// query собирает параметры распознавания. Фразы с началом и говорящим нужны для меток времени и разделения
+// по говорящим; без языка Deepgram определяет его сам
+func (s *TranscriptionService) query(opts entity.TranscriptionOptions, diarize bool) url.Values {
+	query := url.Values{}
+	query.Set("model", s.model)
+	query.Set("smart_format", strconv.FormatBool(s.smartFormat))
+	query.Set("diarize", strconv.FormatBool(diarize))
+	if diarize || useUtterances(opts) {
+		query.Set("utterances", "true")
+	}
+	if opts.Language != "" {
+		query.Set("language", opts.Language)
+	} else {
+		query.Set("detect_language", "true")
+	}
+	return query
+}
+
+// useUtterances сообщает, что текст собирается из фраз, а не из сплошной транскрипции
+func useUtterances(opts entity.TranscriptionOptions) bool {
+	return opts.Granularity == entity.TranscriptionGranularitySegment
+}
+
+// formatText собирает текст из ответа. Если нужны сегменты, каждая фраза начинается с новой строки
+// с меткой времени [мм:сс], как в транскрипциях OpenAI Whisper
+func formatText(resp listenResponse, opts entity.TranscriptionOptions) string {
+	if useUtterances(opts) && len(resp.Results.Utterances) > 0 {
+		var builder strings.Builder
+		for _, u := range resp.Results.Utterances {
+			builder.WriteString(textutil.SegmentTimestamp(u.Start) + " ")
+			builder.WriteString(strings.TrimSpace(u.Transcript))
+			builder.WriteString("\n")
+		}
+		return strings.TrimSpace(builder.String())
+	}
+
+	if len(resp.Results.Channels) == 0 || len(resp.Results.Channels[0].Alternatives) == 0 {
+		return ""
+	}
+	alternative := resp.Results.Channels[0].Alternatives[0]
+	// С форматированием Deepgram разбивает текст на абзацы
+	if alternative.Paragraphs != nil && alternative.Paragraphs.Transcript != "" {
+		return strings.TrimSpace(alternative.Paragraphs.Transcript)
+	}
+	return strings.TrimSpace(alternative.Transcript)
+}
+
+// Ping проверяет доступность Deepgram и ключ API: запрашивает список проектов, что не расходует кредиты
+func (s *TranscriptionService) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, projectsURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+s.apiKey())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Deepgram: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Deepgram returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package deepseek
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Answer отвечает на вопрос, опираясь на пронумерованные фрагменты заметок пользователя
+func (s *SummarizationService) Answer(ctx context.Context, question string, notes []string) (string, error) {
+	// Логирование начала ответа на вопрос
+	s.logger.Info("Answering question",
+		"question_length", len(question),
+		"notes", len(notes),
+		"model", s.model,
+	)
+
+	// Формирование контекста из фрагментов заметок
+	var builder strings.Builder
+	for i, note := range notes {
+		builder.WriteString(fmt.Sprintf("[%d] %s\n\n", i+1, note))
+	}
+
+	task := fmt.Sprintf(
+		"Ответь на вопрос пользователя, используя только приведенные фрагменты его заметок. "+
+			"После каждого утверждения указывай номер фрагмента-источника в квадратных скобках, например [1]. "+
+			"Если во фрагментах нет ответа, прямо скажи об этом.\n\n"+
+			"Вопрос: %s\n\nФрагменты заметок:", question)
+
+	req := CompletionRequest{
+		Model:       s.model,
+		Messages:    s.guardedMessages(task, builder.String()),
+		MaxTokens:   1000,
+		Temperature: 0.2,
+	}
+
+	// Выполнение запроса
+	answer, err := s.createCompletion(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to answer question",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to answer question: %w", err)
+	}
+
+	// Логирование успешного ответа
+	s.logger.Info("Question answered successfully",
+		"answer_length", len(answer),
+	)
+
+	return answer, nil
+}
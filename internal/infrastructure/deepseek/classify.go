@@ -0,0 +1,64 @@
+package deepseek
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxClassifyTextLength - сколько символов текста передается модели для определения темы
+const maxClassifyTextLength = 8000
+
+// classifyTask - задача определения темы текста; темы передаются нумерованным списком
+const classifyTask = "Определи, к какой из тем относится текст. Темы:\n%s\n" +
+	"Верни только номер подходящей темы. Если ни одна тема не подходит, верни 0."
+
+// ClassifyTopic относит текст к одной из тем. Модель выбирает номер темы из списка, поэтому ответ
+// всегда совпадает с одной из тем; пустая строка означает, что ни одна тема не подходит
+func (s *SummarizationService) ClassifyTopic(ctx context.Context, text string, topics []string) (string, error) {
+	// Логирование начала определения темы
+	s.logger.Info("Classifying text topic",
+		"text_length", len(text),
+		"topics", len(topics),
+		"model", s.model,
+	)
+
+	if len(topics) == 0 {
+		return "", nil
+	}
+	if runes := []rune(text); len(runes) > maxClassifyTextLength {
+		text = string(runes[:maxClassifyTextLength])
+	}
+
+	list := make([]string, len(topics))
+	for i, topic := range topics {
+		list[i] = fmt.Sprintf("%d. %s", i+1, topic)
+	}
+
+	req := CompletionRequest{
+		Model:       s.model,
+		Messages:    s.guardedMessages(fmt.Sprintf(classifyTask, strings.Join(list, "\n")), text),
+		MaxTokens:   10,
+		Temperature: 0.1,
+	}
+
+	response, err := s.createCompletion(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to classify text topic",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to classify text topic: %w", err)
+	}
+
+	return parseTopic(response, topics), nil
+}
+
+// parseTopic возвращает тему по номеру из ответа модели или пустую строку, если номер вне списка
+func parseTopic(response string, topics []string) string {
+	number, err := strconv.Atoi(strings.Trim(strings.TrimSpace(response), ".*"))
+	if err != nil || number < 1 || number > len(topics) {
+		return ""
+	}
+	return topics[number-1]
+}
@@ -0,0 +1,92 @@
+package deepseek
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxSuggestedTags - сколько тегов возвращается не больше
+const maxSuggestedTags = 7
+
+// Translate переводит текст на язык, заданный названием в предложном падеже (например, «английском»),
+// сохраняя разметку и структуру исходного текста
+func (s *SummarizationService) Translate(ctx context.Context, text string, language string) (string, error) {
+	// Логирование начала перевода
+	s.logger.Info("Translating text",
+		"text_length", len(text),
+		"language", language,
+		"model", s.model,
+	)
+
+	task := fmt.Sprintf(
+		"Перепиши приведенный текст на %s языке. Сохрани структуру, списки и разметку Markdown, "+
+			"имена и термины без общепринятого перевода оставь как есть. Верни только перевод без пояснений.", language)
+
+	req := CompletionRequest{
+		Model:       s.model,
+		Messages:    s.guardedMessages(task, text),
+		MaxTokens:   2000,
+		Temperature: 0.2,
+	}
+
+	translation, err := s.createCompletion(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to translate text",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to translate text: %w", err)
+	}
+
+	return strings.TrimSpace(translation), nil
+}
+
+// SuggestTags предлагает до maxSuggestedTags коротких тегов по теме текста в нижнем регистре, без символа #
+func (s *SummarizationService) SuggestTags(ctx context.Context, text string) ([]string, error) {
+	// Логирование начала подбора тегов
+	s.logger.Info("Suggesting tags",
+		"text_length", len(text),
+		"model", s.model,
+	)
+
+	task := fmt.Sprintf(
+		"Подбери от 3 до %d коротких тегов, описывающих темы текста, на языке текста. "+
+			"Каждый тег - одно слово или слова, соединенные подчеркиванием, в нижнем регистре. "+
+			"Верни только теги через запятую, без символа # и пояснений.", maxSuggestedTags)
+
+	req := CompletionRequest{
+		Model:       s.model,
+		Messages:    s.guardedMessages(task, text),
+		MaxTokens:   100,
+		Temperature: 0.2,
+	}
+
+	response, err := s.createCompletion(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to suggest tags",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to suggest tags: %w", err)
+	}
+
+	return parseTags(response), nil
+}
+
+// parseTags разбирает ответ модели со списком тегов: убирает символы #, пробелы внутри тегов и повторы
+func parseTags(response string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, field := range strings.FieldsFunc(response, func(r rune) bool { return r == ',' || r == '\n' || r == ';' }) {
+		tag := strings.ToLower(strings.Trim(strings.TrimSpace(field), "#.-*\"'"))
+		tag = strings.Join(strings.Fields(tag), "_")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+		if len(tags) == maxSuggestedTags {
+			break
+		}
+	}
+	return tags
+}
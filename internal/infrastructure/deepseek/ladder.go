@@ -0,0 +1,266 @@
+package deepseek
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
+)
+
+// charsPerToken - оценка числа символов на токен. Для кириллицы токенов больше, чем для латиницы,
+// поэтому оценка занижена: лучше переоценить размер запроса, чем упереться в контекст модели
+const charsPerToken = 2.5
+
+// ModelTier - ступень лестницы моделей суммаризации
+type ModelTier struct {
+	// Model - название модели в API
+	Model string
+	// ContextTokens - размер контекста модели в токенах (0 - не ограничен)
+	ContextTokens int
+	// InputPrice - цена миллиона токенов запроса, USD
+	InputPrice float64
+	// OutputPrice - цена миллиона токенов ответа, USD
+	OutputPrice float64
+	// MaxJobCost - потолок стоимости суммаризации одной задачи на этой ступени, USD (0 - без ограничения)
+	MaxJobCost float64
+}
+
+// cost возвращает стоимость запросов с указанным числом токенов
+func (t ModelTier) cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*t.InputPrice + float64(completionTokens)*t.OutputPrice) / 1e6
+}
+
+// ParseModelLadder разбирает лестницу моделей из строки вида
+// "model:context:input_price:output_price:max_job_cost,...". Название модели может содержать двоеточия,
+// поэтому числовые поля отсчитываются с конца
+func ParseModelLadder(spec string) ([]ModelTier, error) {
+	tiers := make([]ModelTier, 0)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.Split(part, ":")
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("invalid model tier %q: expected model:context:input_price:output_price:max_job_cost", part)
+		}
+		numbers := fields[len(fields)-4:]
+
+		tier := ModelTier{Model: strings.Join(fields[:len(fields)-4], ":")}
+		var err error
+		if tier.ContextTokens, err = strconv.Atoi(numbers[0]); err != nil {
+			return nil, fmt.Errorf("invalid context size in model tier %q: %w", part, err)
+		}
+		if tier.InputPrice, err = strconv.ParseFloat(numbers[1], 64); err != nil {
+			return nil, fmt.Errorf("invalid input price in model tier %q: %w", part, err)
+		}
+		if tier.OutputPrice, err = strconv.ParseFloat(numbers[2], 64); err != nil {
+			return nil, fmt.Errorf("invalid output price in model tier %q: %w", part, err)
+		}
+		if tier.MaxJobCost, err = strconv.ParseFloat(numbers[3], 64); err != nil {
+			return nil, fmt.Errorf("invalid cost ceiling in model tier %q: %w", part, err)
+		}
+		tiers = append(tiers, tier)
+	}
+
+	return tiers, nil
+}
+
+// plannedTier - ступень лестницы с оценкой стоимости суммаризации конкретного текста
+type plannedTier struct {
+	tier     ModelTier
+	estimate float64
+}
+
+// ladderRun накапливает расход токенов суммаризации на одной ступени лестницы.
+// Фрагменты суммаризируются параллельно, поэтому счетчики защищены мьютексом
+type ladderRun struct {
+	tier ModelTier
+
+	mu               sync.Mutex
+	promptTokens     int
+	completionTokens int
+}
+
+// add учитывает расход токенов одного запроса
+func (r *ladderRun) add(usage CompletionUsage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.promptTokens += usage.PromptTokens
+	r.completionTokens += usage.CompletionTokens
+}
+
+// SummarizeJob суммаризирует текст задачи по лестнице моделей: начинает с самой дешевой модели,
+// в контекст которой помещается запрос, и при ошибке переходит к следующей. Ступень пропускается,
+// если с учетом уже потраченного ее оценка превышает потолок стоимости задачи
+func (s *SummarizationService) SummarizeJob(ctx context.Context, text string, instructions string) (*entity.SummaryResult, error) {
+	return s.summarizeJob(ctx, text, limitInstructions(instructions))
+}
+
+// summarizeJob суммаризирует текст по лестнице моделей с уже подготовленными указаниями
+func (s *SummarizationService) summarizeJob(ctx context.Context, text string, instructions string) (*entity.SummaryResult, error) {
+	// Логирование начала суммаризации
+	s.logger.Info("Summarizing text",
+		"text_length", len(text),
+		"has_instructions", instructions != "",
+	)
+
+	chunks := textutil.SplitWords(text, s.chunking.Size)
+	plan := s.planLadder(chunks, instructions)
+	if len(plan) == 0 {
+		s.logger.Error("No summarization model fits the text",
+			"text_length", len(text),
+			"chunks", len(chunks),
+		)
+		return nil, fmt.Errorf("no summarization model fits the text within its context and cost ceiling")
+	}
+
+	result := &entity.SummaryResult{}
+	var lastErr error
+	for _, planned := range plan {
+		if planned.tier.MaxJobCost > 0 && result.CostUSD+planned.estimate > planned.tier.MaxJobCost {
+			s.logger.Warn("Skipping summarization model over cost ceiling",
+				"model", planned.tier.Model,
+				"spent", result.CostUSD,
+				"estimate", planned.estimate,
+				"ceiling", planned.tier.MaxJobCost,
+			)
+			continue
+		}
+
+		run := &ladderRun{tier: planned.tier}
+		summary, err := s.summarize(ctx, run, chunks, instructions)
+
+		// Неудачные попытки тоже оплачиваются
+		result.PromptTokens += run.promptTokens
+		result.CompletionTokens += run.completionTokens
+		result.CostUSD += planned.tier.cost(run.promptTokens, run.completionTokens)
+
+		if err == nil {
+			result.Summary = summary
+			result.Model = planned.tier.Model
+
+			// Логирование успешной суммаризации
+			s.logger.Info("Text summarized successfully",
+				"summary_length", len(summary),
+				"model", result.Model,
+				"cost_usd", result.CostUSD,
+			)
+			return result, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		lastErr = err
+
+		s.logger.Warn("Summarization model failed, escalating",
+			"model", planned.tier.Model,
+			"error", err,
+		)
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("cost ceiling reached")
+	}
+	s.logger.Error("Failed to summarize text",
+		"error", lastErr,
+	)
+	return nil, fmt.Errorf("failed to summarize text: %w", lastErr)
+}
+
+// SummarizeWithModel суммаризирует текст задачи одной моделью, минуя лестницу: так модели сравниваются
+// командой /compare. Стоимость считается по ценам ступени с той же моделью, если она есть в лестнице
+func (s *SummarizationService) SummarizeWithModel(ctx context.Context, text string, instructions string, model string) (*entity.SummaryResult, error) {
+	tier := ModelTier{Model: model}
+	for _, ladderTier := range s.ladder {
+		if ladderTier.Model == model {
+			tier = ladderTier
+			break
+		}
+	}
+
+	// Логирование начала суммаризации
+	s.logger.Info("Summarizing text with model",
+		"text_length", len(text),
+		"model", model,
+	)
+
+	run := &ladderRun{tier: tier}
+	summary, err := s.summarize(ctx, run, textutil.SplitWords(text, s.chunking.Size), limitInstructions(instructions))
+	if err != nil {
+		s.logger.Error("Failed to summarize text with model",
+			"error", err,
+			"model", model,
+		)
+		return nil, fmt.Errorf("failed to summarize text with model %s: %w", model, err)
+	}
+
+	return &entity.SummaryResult{
+		Summary:          summary,
+		Model:            model,
+		CostUSD:          tier.cost(run.promptTokens, run.completionTokens),
+		PromptTokens:     run.promptTokens,
+		CompletionTokens: run.completionTokens,
+	}, nil
+}
+
+// summarize суммаризирует текст моделью ступени; длинный текст суммаризируется по частям
+func (s *SummarizationService) summarize(ctx context.Context, run *ladderRun, chunks []string, instructions string) (string, error) {
+	if len(chunks) > 1 {
+		return s.summarizeMapReduce(ctx, run, chunks, instructions)
+	}
+	return s.summarizeChunk(ctx, run, chunks[0], instructions)
+}
+
+// planLadder отбирает ступени, в контекст которых помещается самый большой запрос суммаризации
+// и оценка стоимости которых не превышает потолок, и упорядочивает их от дешевых к дорогим.
+// При равной оценке сохраняется порядок из конфигурации
+func (s *SummarizationService) planLadder(chunks []string, instructions string) []plannedTier {
+	overhead := estimateTokens(s.systemPrompt) + estimateTokens(withInstructions(mergeSummaryTask, instructions))
+
+	// Размер запросов: фрагменты и, для длинного текста, объединение резюме фрагментов
+	var promptTokens, completionTokens, largestRequest int
+	for _, chunk := range chunks {
+		tokens := overhead + estimateTokens(chunk)
+		promptTokens += tokens
+		largestRequest = max(largestRequest, tokens+chunkSummaryMaxTokens)
+	}
+	completionTokens = len(chunks) * chunkSummaryMaxTokens
+	if len(chunks) > 1 {
+		mergeTokens := overhead + len(chunks)*chunkSummaryMaxTokens
+		promptTokens += mergeTokens
+		completionTokens += mergeSummaryMaxTokens
+		largestRequest = max(largestRequest, mergeTokens+mergeSummaryMaxTokens)
+	}
+
+	plan := make([]plannedTier, 0, len(s.ladder))
+	for _, tier := range s.ladder {
+		if tier.ContextTokens > 0 && largestRequest > tier.ContextTokens {
+			continue
+		}
+		estimate := tier.cost(promptTokens, completionTokens)
+		if tier.MaxJobCost > 0 && estimate > tier.MaxJobCost {
+			continue
+		}
+		plan = append(plan, plannedTier{tier: tier, estimate: estimate})
+	}
+
+	sort.SliceStable(plan, func(i, j int) bool {
+		return plan[i].estimate < plan[j].estimate
+	})
+
+	return plan
+}
+
+// estimateTokens оценивает число токенов в тексте
+func estimateTokens(text string) int {
+	return int(float64(utf8.RuneCountInString(text))/charsPerToken) + 1
+}
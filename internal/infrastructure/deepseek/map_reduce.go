@@ -0,0 +1,149 @@
+package deepseek
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	"github.com/112Alex/project_obsidian/pkg/parallel"
+)
+
+// Значения по умолчанию для суммаризации по частям
+const (
+	defaultChunkSize        = 12000
+	defaultChunkConcurrency = 4
+	defaultChunkRetries     = 3
+	chunkRetryBaseDelay     = 2 * time.Second
+)
+
+// ChunkingOptions содержит настройки суммаризации длинных текстов по частям (map-reduce)
+type ChunkingOptions struct {
+	// Size - максимальный размер фрагмента в символах
+	Size int
+	// Concurrency - количество фрагментов, суммаризируемых одновременно
+	Concurrency int
+	// Retries - количество попыток суммаризации одного фрагмента
+	Retries int
+}
+
+// withDefaults возвращает настройки с подставленными значениями по умолчанию
+func (o ChunkingOptions) withDefaults() ChunkingOptions {
+	if o.Size <= 0 {
+		o.Size = defaultChunkSize
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = defaultChunkConcurrency
+	}
+	if o.Retries <= 0 {
+		o.Retries = defaultChunkRetries
+	}
+	return o
+}
+
+// summarizeMapReduce суммаризирует фрагменты параллельно и объединяет их резюме в исходном порядке
+func (s *SummarizationService) summarizeMapReduce(ctx context.Context, run *ladderRun, chunks []string, instructions string) (string, error) {
+	s.logger.Info("Summarizing text in chunks",
+		"chunks", len(chunks),
+		"concurrency", s.chunking.Concurrency,
+		"model", run.tier.Model,
+	)
+
+	startedAt := time.Now()
+
+	// Map: суммаризация фрагментов
+	chunkSummaries, err := s.summarizeChunks(ctx, run, chunks, instructions)
+	if err != nil {
+		s.logger.Error("Failed to summarize chunks",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to summarize chunks: %w", err)
+	}
+
+	// Reduce: объединение резюме фрагментов в итоговое резюме
+	var builder strings.Builder
+	for i, chunkSummary := range chunkSummaries {
+		builder.WriteString(fmt.Sprintf("Часть %d:\n%s\n\n", i+1, chunkSummary))
+	}
+
+	req := CompletionRequest{
+		Model:       run.tier.Model,
+		Messages:    s.guardedMessages(withInstructions(mergeSummaryTask, instructions), builder.String()),
+		MaxTokens:   mergeSummaryMaxTokens,
+		Temperature: 0.3,
+	}
+
+	summary, err := s.createMeteredCompletion(ctx, run, req)
+	if err != nil {
+		s.logger.Error("Failed to merge chunk summaries",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to merge chunk summaries: %w", err)
+	}
+
+	// Логирование успешной суммаризации
+	s.logger.Info("Text summarized in chunks successfully",
+		"chunks", len(chunks),
+		"summary_length", len(summary),
+		"elapsed", time.Since(startedAt),
+	)
+
+	return summary, nil
+}
+
+// summarizeChunks суммаризирует фрагменты с ограниченным параллелизмом; результат сохраняет порядок фрагментов.
+// Остальные фрагменты не имеют смысла без фрагмента с ошибкой, поэтому первая ошибка их останавливает
+func (s *SummarizationService) summarizeChunks(ctx context.Context, run *ladderRun, chunks []string, instructions string) ([]string, error) {
+	results := make([]string, len(chunks))
+	i, err := parallel.Run(ctx, len(chunks), s.chunking.Concurrency, func(ctx context.Context, i int) error {
+		var err error
+		results[i], err = s.summarizeChunkWithRetry(ctx, run, i, chunks[i], instructions)
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("chunk %d: %w", i+1, err)
+	}
+
+	return results, nil
+}
+
+// summarizeChunkWithRetry суммаризирует фрагмент, повторяя попытки с экспоненциальной задержкой
+func (s *SummarizationService) summarizeChunkWithRetry(ctx context.Context, run *ladderRun, index int, chunk string, instructions string) (string, error) {
+	var lastErr error
+	for attempt := 1; attempt <= s.chunking.Retries; attempt++ {
+		summary, err := s.summarizeChunk(ctx, run, chunk, instructions)
+		if err == nil {
+			return summary, nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		s.logger.Warn("Failed to summarize chunk, retrying",
+			"chunk", index+1,
+			"attempt", attempt,
+			"error", err,
+		)
+
+		if attempt == s.chunking.Retries {
+			break
+		}
+
+		// Ожидание перед следующей попыткой
+		delay := chunkRetryBaseDelay * time.Duration(1<<(attempt-1))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		metrics.Retry(ctx, metrics.ProviderDeepSeek, "chat_completion")
+	}
+
+	return "", lastErr
+}
@@ -0,0 +1,34 @@
+package deepseek
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+)
+
+// meetingSummaryInstructions - указания к резюме записи встречи, для которой прислана повестка
+const meetingSummaryInstructions = "Это запись встречи с повесткой. Построй резюме по пунктам повестки: " +
+	"для каждого пункта сделай раздел с его номером и названием и кратко изложи, что обсуждали и какие решения приняли. " +
+	"Если по пункту не принято решение или он не обсуждался, начни раздел пометкой «⚠️ Не решено». " +
+	"Обсуждения, не относящиеся к повестке, вынеси в раздел «Вне повестки». " +
+	"В конце перечисли нерешенные пункты отдельным списком «Нерешенные вопросы».\n\nПовестка встречи:\n%s"
+
+// SummarizeMeeting суммаризирует запись встречи по пунктам повестки и отмечает нерешенные пункты.
+// Повестка не укорачивается, в отличие от указаний пользователя: ее длину ограничивает сценарий,
+// который ее принимает
+func (s *SummarizationService) SummarizeMeeting(ctx context.Context, text string, agenda string, instructions string) (*entity.SummaryResult, error) {
+	// Логирование начала суммаризации встречи
+	s.logger.Info("Summarizing meeting by agenda",
+		"text_length", len(text),
+		"agenda_length", len(agenda),
+	)
+
+	meetingInstructions := fmt.Sprintf(meetingSummaryInstructions, strings.TrimSpace(agenda))
+	if instructions = strings.TrimSpace(limitInstructions(instructions)); instructions != "" {
+		meetingInstructions += "\n\n" + instructions
+	}
+
+	return s.summarizeJob(ctx, text, meetingInstructions)
+}
@@ -0,0 +1,176 @@
+package deepseek
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Настройки подстройки параллелизма под лимиты DeepSeek
+const (
+	defaultMaxConcurrency = 8
+	// rateLimitRetries - сколько раз запрос, отклоненный из-за лимита, повторяется после паузы
+	rateLimitRetries = 3
+	// defaultRateLimitPause - пауза после отказа из-за лимита, если DeepSeek не сообщил, когда повторить
+	defaultRateLimitPause = 2 * time.Second
+	// maxRateLimitPause - наибольшая пауза: дольше ждать нет смысла, задача упадет по таймауту этапа
+	maxRateLimitPause = time.Minute
+	// rateLimitHeadroom - доля оставшихся в окне запросов, ниже которой параллелизм больше не увеличивается
+	rateLimitHeadroom = 0.5
+)
+
+// adaptiveLimiter ограничивает число одновременных запросов к DeepSeek со всех задач. Лимит подстраивается
+// под заголовки ответов: уменьшается вдвое при отказе из-за лимита и когда запросов в окне почти не осталось,
+// и растет на единицу после успешного ответа с запасом, но не выше max. Пока действует пауза из Retry-After
+// или до сброса окна лимита, новые запросы не отправляются
+type adaptiveLimiter struct {
+	mu          sync.Mutex
+	max         int
+	limit       int
+	active      int
+	pausedUntil time.Time
+	// changed закрывается при освобождении слота или изменении лимита, чтобы ожидающие запросы проверили его снова
+	changed chan struct{}
+}
+
+// newAdaptiveLimiter создает ограничитель с наибольшим параллелизмом maxConcurrency
+func newAdaptiveLimiter(maxConcurrency int) *adaptiveLimiter {
+	if maxConcurrency <= 0 {
+		maxConcurrency = defaultMaxConcurrency
+	}
+	return &adaptiveLimiter{
+		max:     maxConcurrency,
+		limit:   maxConcurrency,
+		changed: make(chan struct{}),
+	}
+}
+
+// Acquire ждет свободного слота для запроса; слот освобождается вызовом Release
+func (l *adaptiveLimiter) Acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		wait := time.Until(l.pausedUntil)
+		if wait <= 0 && l.active < l.limit {
+			l.active++
+			l.mu.Unlock()
+			return nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		if wait <= 0 {
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			continue
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-changed:
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+		timer.Stop()
+	}
+}
+
+// Release освобождает слот запроса
+func (l *adaptiveLimiter) Release() {
+	l.mu.Lock()
+	l.active--
+	l.notify()
+	l.mu.Unlock()
+}
+
+// Observe подстраивает лимит по ответу DeepSeek; после отказа из-за лимита новые запросы ждут паузу
+func (l *adaptiveLimiter) Observe(status int, header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if status == http.StatusTooManyRequests {
+		l.decrease()
+		l.pause(retryAfter(header))
+		return
+	}
+	if status != http.StatusOK {
+		return
+	}
+
+	remaining, hasRemaining := headerInt(header, "X-Ratelimit-Remaining-Requests")
+	total, _ := headerInt(header, "X-Ratelimit-Limit-Requests")
+	switch {
+	case hasRemaining && remaining == 0:
+		// Окно лимита исчерпано: ждем его сброса, а не отказов
+		l.decrease()
+		l.pause(resetAfter(header))
+	case hasRemaining && total > 0 && float64(remaining) < float64(total)*rateLimitHeadroom:
+		// Запас тает: дальше не разгоняемся, а если слотов больше, чем осталось запросов, сокращаем их
+		if remaining < l.limit {
+			l.limit = max(1, remaining)
+			l.notify()
+		}
+	case l.limit < l.max:
+		l.limit++
+		l.notify()
+	}
+}
+
+// Limit возвращает текущий лимит одновременных запросов
+func (l *adaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// decrease уменьшает лимит вдвое, но не ниже одного запроса
+func (l *adaptiveLimiter) decrease() {
+	l.limit = max(1, l.limit/2)
+}
+
+// pause откладывает новые запросы на pause
+func (l *adaptiveLimiter) pause(pause time.Duration) {
+	until := time.Now().Add(pause)
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.notify()
+}
+
+// notify будит ожидающие запросы; вызывается под блокировкой
+func (l *adaptiveLimiter) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// retryAfter возвращает паузу из заголовка Retry-After (в секундах) или паузу по умолчанию
+func retryAfter(header http.Header) time.Duration {
+	if seconds, ok := headerInt(header, "Retry-After"); ok && seconds > 0 {
+		return min(time.Duration(seconds)*time.Second, maxRateLimitPause)
+	}
+	return resetAfter(header)
+}
+
+// resetAfter возвращает время до сброса окна лимита запросов (например, 1s или 6m0s) или паузу по умолчанию
+func resetAfter(header http.Header) time.Duration {
+	if reset, err := time.ParseDuration(strings.TrimSpace(header.Get("X-Ratelimit-Reset-Requests"))); err == nil && reset > 0 {
+		return min(reset, maxRateLimitPause)
+	}
+	return defaultRateLimitPause
+}
+
+// headerInt возвращает целое значение заголовка
+func headerInt(header http.Header, name string) (int, bool) {
+	value, err := strconv.Atoi(strings.TrimSpace(header.Get(name)))
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
@@ -0,0 +1,106 @@
+package deepseek
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+)
+
+// Ограничения разбора настроения встречи
+const (
+	// maxSentimentTextLength - сколько символов транскрипции передается модели; в длинной записи
+	// разбирается начало, где обычно и задается тон встречи
+	maxSentimentTextLength = 30000
+	// maxTensionPoints - сколько напряженных моментов сохраняется не больше
+	maxTensionPoints = 5
+)
+
+// sentimentTask - задача разбора настроения встречи с форматом ответа
+const sentimentTask = "Оцени настроение и тон встречи по транскрипции. Верни только JSON без пояснений и разметки вида " +
+	`{"mood": "positive|neutral|negative|mixed", "tone": "...", "tension_points": [{"topic": "...", "quote": "..."}]}. ` +
+	"В mood укажи общее настроение, в tone - одно-два предложения о тоне обсуждения на языке транскрипции. " +
+	"В tension_points перечисли до %d моментов, где возникли споры, недовольство или напряжение: тему и дословную " +
+	"короткую цитату из транскрипции. Если напряженных моментов не было, верни пустой список."
+
+// AnalyzeSentiment оценивает общее настроение и тон встречи и находит напряженные моменты с цитатами
+func (s *SummarizationService) AnalyzeSentiment(ctx context.Context, text string) (*entity.JobSentiment, error) {
+	// Логирование начала разбора настроения
+	s.logger.Info("Analyzing meeting sentiment",
+		"text_length", len(text),
+		"model", s.model,
+	)
+
+	if runes := []rune(text); len(runes) > maxSentimentTextLength {
+		text = string(runes[:maxSentimentTextLength])
+	}
+
+	req := CompletionRequest{
+		Model:       s.model,
+		Messages:    s.guardedMessages(fmt.Sprintf(sentimentTask, maxTensionPoints), text),
+		MaxTokens:   800,
+		Temperature: 0.2,
+	}
+
+	response, err := s.createCompletion(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to analyze sentiment",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to analyze sentiment: %w", err)
+	}
+
+	sentiment, err := parseSentiment(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze sentiment: %w", err)
+	}
+
+	return sentiment, nil
+}
+
+// parseSentiment разбирает ответ модели с разбором настроения: убирает обрамление блоком кода,
+// приводит неизвестное настроение к нейтральному и отбрасывает пустые напряженные моменты
+func parseSentiment(response string) (*entity.JobSentiment, error) {
+	response = strings.TrimSpace(response)
+	if start, end := strings.Index(response, "{"), strings.LastIndex(response, "}"); start >= 0 && end > start {
+		response = response[start : end+1]
+	}
+
+	var parsed struct {
+		Mood          string                `json:"mood"`
+		Tone          string                `json:"tone"`
+		TensionPoints []entity.TensionPoint `json:"tension_points"`
+	}
+	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse sentiment response: %w", err)
+	}
+
+	sentiment := &entity.JobSentiment{
+		Mood:          entity.SentimentMood(strings.ToLower(strings.TrimSpace(parsed.Mood))),
+		Tone:          strings.TrimSpace(parsed.Tone),
+		TensionPoints: make([]entity.TensionPoint, 0, len(parsed.TensionPoints)),
+		CreatedAt:     time.Now(),
+	}
+	switch sentiment.Mood {
+	case entity.SentimentMoodPositive, entity.SentimentMoodNeutral, entity.SentimentMoodNegative, entity.SentimentMoodMixed:
+	default:
+		sentiment.Mood = entity.SentimentMoodNeutral
+	}
+
+	for _, point := range parsed.TensionPoints {
+		point.Topic = strings.TrimSpace(point.Topic)
+		point.Quote = strings.Trim(strings.TrimSpace(point.Quote), "«»\"")
+		if point.Topic == "" {
+			continue
+		}
+		sentiment.TensionPoints = append(sentiment.TensionPoints, point)
+		if len(sentiment.TensionPoints) == maxTensionPoints {
+			break
+		}
+	}
+
+	return sentiment, nil
+}
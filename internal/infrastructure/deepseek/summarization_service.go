@@ -9,18 +9,38 @@ import (
 	"net/http"
 
 	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
 )
 
 // SummarizationService представляет собой сервис для суммаризации текста с использованием DeepSeek API
 type SummarizationService struct {
-	apiKey     string
-	apiBaseURL string
-	model      string
-	logger     *logger.Logger
+	apiKey       func() string
+	apiBaseURL   string
+	model        string
+	systemPrompt string
+	chunking     ChunkingOptions
+	ladder       []ModelTier
+	limiter      *adaptiveLimiter
+	logger       *logger.Logger
 }
 
-// NewSummarizationService создает новый сервис для суммаризации текста
-func NewSummarizationService(apiKey string, apiBaseURL string, model string, logger *logger.Logger) *SummarizationService {
+// Задачи и ограничения длины ответа для запросов суммаризации
+const (
+	chunkSummaryTask = "Пожалуйста, создай краткое и информативное резюме следующего текста. " +
+		"Сохрани ключевые идеи, факты и выводы."
+	mergeSummaryTask = "Ниже приведены краткие резюме последовательных частей одного длинного текста. " +
+		"Объедини их в одно связное краткое и информативное резюме, сохраняя порядок изложения, " +
+		"ключевые идеи, факты и выводы. Не упоминай деление на части."
+	chunkSummaryMaxTokens = 1000
+	mergeSummaryMaxTokens = 1500
+)
+
+// NewSummarizationService создает новый сервис для суммаризации текста.
+// Пустая лестница моделей означает суммаризацию одной моделью model без учета стоимости.
+// apiKey вызывается перед каждым запросом, поэтому ротация ключа подхватывается без перезапуска.
+// maxConcurrency - наибольшее число одновременных запросов со всех задач: ниже него параллелизм
+// подстраивается под лимиты DeepSeek
+func NewSummarizationService(apiKey func() string, apiBaseURL string, model string, systemPrompt string, chunking ChunkingOptions, maxConcurrency int, ladder []ModelTier, logger *logger.Logger) *SummarizationService {
 	// Если базовый URL не указан, используем стандартный
 	if apiBaseURL == "" {
 		apiBaseURL = "https://api.deepseek.com"
@@ -31,11 +51,25 @@ func NewSummarizationService(apiKey string, apiBaseURL string, model string, log
 		model = "deepseek-chat"
 	}
 
+	// Если системный промпт не указан, используем защитный промпт по умолчанию
+	if systemPrompt == "" {
+		systemPrompt = DefaultSystemPrompt
+	}
+
+	// Без лестницы суммаризация выполняется основной моделью
+	if len(ladder) == 0 {
+		ladder = []ModelTier{{Model: model}}
+	}
+
 	return &SummarizationService{
-		apiKey:     apiKey,
-		apiBaseURL: apiBaseURL,
-		model:      model,
-		logger:     logger,
+		apiKey:       apiKey,
+		apiBaseURL:   apiBaseURL,
+		model:        model,
+		systemPrompt: systemPrompt,
+		chunking:     chunking.withDefaults(),
+		ladder:       ladder,
+		limiter:      newAdaptiveLimiter(maxConcurrency),
+		logger:       logger,
 	}
 }
 
@@ -67,54 +101,41 @@ type CompletionResponse struct {
 		} `json:"message"`
 		FinishReason string `json:"finish_reason"`
 	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
+	Usage CompletionUsage `json:"usage"`
+}
+
+// CompletionUsage представляет собой расход токенов на запрос
+type CompletionUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
 }
 
 // SummarizeText суммаризирует текст
 func (s *SummarizationService) SummarizeText(ctx context.Context, text string) (string, error) {
-	// Логирование начала суммаризации
-	s.logger.Info("Summarizing text",
-		"text_length", len(text),
-		"model", s.model,
-	)
+	return s.SummarizeWithInstructions(ctx, text, "")
+}
 
-	// Создание запроса на суммаризацию
-	prompt := fmt.Sprintf(
-		"Пожалуйста, создай краткое и информативное резюме следующего текста. "+
-			"Сохрани ключевые идеи, факты и выводы. "+
-			"Текст: %s", text)
+// SummarizeWithInstructions суммаризирует текст с учетом дополнительных указаний пользователя
+func (s *SummarizationService) SummarizeWithInstructions(ctx context.Context, text string, instructions string) (string, error) {
+	result, err := s.SummarizeJob(ctx, text, instructions)
+	if err != nil {
+		return "", err
+	}
+	return result.Summary, nil
+}
 
+// summarizeChunk выполняет суммаризацию одного фрагмента текста моделью ступени лестницы
+func (s *SummarizationService) summarizeChunk(ctx context.Context, run *ladderRun, text string, instructions string) (string, error) {
+	// Создание запроса на суммаризацию
 	req := CompletionRequest{
-		Model: s.model,
-		Messages: []Message{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
-		MaxTokens:   1000,
+		Model:       run.tier.Model,
+		Messages:    s.guardedMessages(withInstructions(chunkSummaryTask, instructions), text),
+		MaxTokens:   chunkSummaryMaxTokens,
 		Temperature: 0.3,
 	}
 
-	// Выполнение запроса
-	summary, err := s.createCompletion(ctx, req)
-	if err != nil {
-		s.logger.Error("Failed to summarize text",
-			"error", err,
-		)
-		return "", fmt.Errorf("failed to summarize text: %w", err)
-	}
-
-	// Логирование успешной суммаризации
-	s.logger.Info("Text summarized successfully",
-		"summary_length", len(summary),
-	)
-
-	return summary, nil
+	return s.createMeteredCompletion(ctx, run, req)
 }
 
 // Summarize выполняет суммаризацию текста по умолчанию
@@ -131,19 +152,12 @@ func (s *SummarizationService) SummarizeTextWithBulletPoints(ctx context.Context
 	)
 
 	// Создание запроса на суммаризацию
-	prompt := fmt.Sprintf(
-		"Пожалуйста, создай краткое и информативное резюме следующего текста в виде маркированного списка. "+
-			"Сохрани ключевые идеи, факты и выводы. "+
-			"Текст: %s", text)
+	task := "Пожалуйста, создай краткое и информативное резюме следующего текста в виде маркированного списка. " +
+		"Сохрани ключевые идеи, факты и выводы."
 
 	req := CompletionRequest{
-		Model: s.model,
-		Messages: []Message{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
+		Model:       s.model,
+		Messages:    s.guardedMessages(task, text),
 		MaxTokens:   1000,
 		Temperature: 0.3,
 	}
@@ -174,20 +188,13 @@ func (s *SummarizationService) SummarizeTextWithMarkdown(ctx context.Context, te
 	)
 
 	// Создание запроса на суммаризацию
-	prompt := fmt.Sprintf(
-		"Пожалуйста, создай краткое и информативное резюме следующего текста с использованием разметки Markdown. "+
-			"Используй заголовки, подзаголовки, маркированные списки и другие элементы Markdown для структурирования резюме. "+
-			"Сохрани ключевые идеи, факты и выводы. "+
-			"Текст: %s", text)
+	task := "Пожалуйста, создай краткое и информативное резюме следующего текста с использованием разметки Markdown. " +
+		"Используй заголовки, подзаголовки, маркированные списки и другие элементы Markdown для структурирования резюме. " +
+		"Сохрани ключевые идеи, факты и выводы."
 
 	req := CompletionRequest{
-		Model: s.model,
-		Messages: []Message{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
+		Model:       s.model,
+		Messages:    s.guardedMessages(task, text),
 		MaxTokens:   1500,
 		Temperature: 0.3,
 	}
@@ -209,14 +216,84 @@ func (s *SummarizationService) SummarizeTextWithMarkdown(ctx context.Context, te
 	return summary, nil
 }
 
-// createCompletion отправляет запрос на создание завершения
+// createCompletion отправляет запрос на создание завершения, записывая метрики вызова
 func (s *SummarizationService) createCompletion(ctx context.Context, req CompletionRequest) (string, error) {
+	var content string
+	err := metrics.Track(ctx, s.logger, metrics.ProviderDeepSeek, "chat_completion", func(ctx context.Context) error {
+		var err error
+		content, _, err = s.doCompletion(ctx, req)
+		return err
+	})
+	return content, err
+}
+
+// createMeteredCompletion отправляет запрос на создание завершения и учитывает расход токенов на ступени лестницы
+func (s *SummarizationService) createMeteredCompletion(ctx context.Context, run *ladderRun, req CompletionRequest) (string, error) {
+	var content string
+	err := metrics.Track(ctx, s.logger, metrics.ProviderDeepSeek, "chat_completion", func(ctx context.Context) error {
+		var usage CompletionUsage
+		var err error
+		content, usage, err = s.doCompletion(ctx, req)
+		run.add(usage)
+		return err
+	})
+	return content, err
+}
+
+// doCompletion выполняет HTTP запрос на создание завершения. Запросы, отклоненные из-за лимита DeepSeek,
+// повторяются после паузы, которую выдерживают и остальные запросы
+func (s *SummarizationService) doCompletion(ctx context.Context, req CompletionRequest) (string, CompletionUsage, error) {
 	// Сериализация запроса
 	reqBody, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return "", CompletionUsage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	for attempt := 0; ; attempt++ {
+		content, usage, status, err := s.sendCompletion(ctx, reqBody)
+		if status != http.StatusTooManyRequests || attempt == rateLimitRetries {
+			return content, usage, err
+		}
+
+		s.logger.Warn("DeepSeek rate limit reached, throttling requests",
+			"attempt", attempt+1,
+			"concurrency", s.limiter.Limit(),
+		)
+		metrics.Retry(ctx, metrics.ProviderDeepSeek, "chat_completion")
+	}
+}
+
+// Ping проверяет, что DeepSeek API принимает текущий ключ: запрашивает список моделей, что не расходует токены.
+// Запрос не занимает слот ограничителя, чтобы проверка не ждала за задачами суммаризации
+func (s *SummarizationService) Ping(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/models", s.apiBaseURL), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey()))
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("API returned error: %s, status code: %d", string(respBody), resp.StatusCode)
+	}
+
+	return nil
+}
+
+// sendCompletion отправляет запрос на создание завершения, дождавшись слота ограничителя запросов,
+// и возвращает также статус ответа
+func (s *SummarizationService) sendCompletion(ctx context.Context, reqBody []byte) (string, CompletionUsage, int, error) {
+	if err := s.limiter.Acquire(ctx); err != nil {
+		return "", CompletionUsage{}, 0, err
+	}
+	defer s.limiter.Release()
+
 	// Создание HTTP запроса
 	httpReq, err := http.NewRequestWithContext(
 		ctx,
@@ -225,42 +302,45 @@ func (s *SummarizationService) createCompletion(ctx context.Context, req Complet
 		bytes.NewReader(reqBody),
 	)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", CompletionUsage{}, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Установка заголовков
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey()))
 
 	// Выполнение запроса
 	client := &http.Client{}
 	resp, err := client.Do(httpReq)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return "", CompletionUsage{}, 0, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	// Лимит подстраивается по заголовкам ответа, в том числе ответа с ошибкой
+	s.limiter.Observe(resp.StatusCode, resp.Header)
+
 	// Чтение ответа
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return "", CompletionUsage{}, resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Проверка статуса ответа
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API returned error: %s, status code: %d", string(respBody), resp.StatusCode)
+		return "", CompletionUsage{}, resp.StatusCode, fmt.Errorf("API returned error: %s, status code: %d", string(respBody), resp.StatusCode)
 	}
 
 	// Десериализация ответа
 	var completionResp CompletionResponse
 	if err := json.Unmarshal(respBody, &completionResp); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w, response: %s", err, string(respBody))
+		return "", CompletionUsage{}, resp.StatusCode, fmt.Errorf("failed to unmarshal response: %w, response: %s", err, string(respBody))
 	}
 
-	// Проверка наличия выбора
+	// Проверка наличия выбора; токены запроса оплачиваются и при пустом ответе
 	if len(completionResp.Choices) == 0 {
-		return "", fmt.Errorf("no choices in response: %s", string(respBody))
+		return "", completionResp.Usage, resp.StatusCode, fmt.Errorf("no choices in response: %s", string(respBody))
 	}
 
-	return completionResp.Choices[0].Message.Content, nil
+	return completionResp.Choices[0].Message.Content, completionResp.Usage, resp.StatusCode, nil
 }
@@ -7,20 +7,32 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
 	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/tempfs"
 )
 
+// wavBytesPerSecond - объем WAV после конвертации: 16 кГц, моно, 16 бит
+const wavBytesPerSecond = 16000 * 2
+
 // AudioService представляет собой сервис для работы с аудио файлами
 type AudioService struct {
 	ffmpegPath string
+	tempSpace  *tempfs.Space
+	executor   *executor
 	logger     *logger.Logger
 }
 
-// NewAudioService создает новый сервис для работы с аудио файлами
-func NewAudioService(ffmpegPath string, logger *logger.Logger) *AudioService {
+// NewAudioService создает новый сервис для работы с аудио файлами.
+// Загруженные и промежуточные файлы хранятся во временном каталоге tempSpace.
+// maxConcurrency - сколько команд FFmpeg выполняется одновременно (<= 0 - по числу процессоров)
+func NewAudioService(ffmpegPath string, maxConcurrency int, tempSpace *tempfs.Space, logger *logger.Logger) *AudioService {
 	return &AudioService{
 		ffmpegPath: ffmpegPath,
+		tempSpace:  tempSpace,
+		executor:   newExecutor(maxConcurrency),
 		logger:     logger,
 	}
 }
@@ -28,7 +40,7 @@ func NewAudioService(ffmpegPath string, logger *logger.Logger) *AudioService {
 // SaveAudio сохраняет аудиофайл
 func (s *AudioService) SaveAudio(ctx context.Context, userID int64, audioData io.Reader, filename string) (string, error) {
 	// Создание директории для сохранения файлов пользователя
-	userDir := filepath.Join("uploads", fmt.Sprintf("user_%d", userID))
+	userDir := filepath.Join(s.tempSpace.Dir(), "uploads", fmt.Sprintf("user_%d", userID))
 	if err := os.MkdirAll(userDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create user directory: %w", err)
 	}
@@ -49,6 +61,20 @@ func (s *AudioService) SaveAudio(ctx context.Context, userID int64, audioData io
 	return filePath, nil
 }
 
+// DeleteUserFiles удаляет каталог с загруженными файлами пользователя вместе с результатами их обработки
+func (s *AudioService) DeleteUserFiles(ctx context.Context, userID int64) error {
+	userDir := filepath.Join(s.tempSpace.Dir(), "uploads", fmt.Sprintf("user_%d", userID))
+	if err := os.RemoveAll(userDir); err != nil {
+		return fmt.Errorf("failed to remove user directory: %w", err)
+	}
+
+	s.logger.Info("User files removed",
+		"user_id", userID,
+	)
+
+	return nil
+}
+
 // ConvertToWAV конвертирует аудио файл в формат WAV
 func (s *AudioService) ConvertToWAV(ctx context.Context, inputPath string) (string, error) {
 	// Создание выходного пути
@@ -73,7 +99,7 @@ func (s *AudioService) ConvertToWAV(ctx context.Context, inputPath string) (stri
 	)
 
 	// Выполнение команды
-	output, err := cmd.CombinedOutput()
+	output, err := s.combinedOutput(ctx, cmd)
 	if err != nil {
 		s.logger.Error("Failed to convert audio",
 			"error", err,
@@ -90,6 +116,101 @@ func (s *AudioService) ConvertToWAV(ctx context.Context, inputPath string) (stri
 	return outputPath, nil
 }
 
+// ExtractAudioFromVideo извлекает звуковую дорожку из видеофайла (кружка или загруженного видео) в OGG/Opus;
+// дальше аудио проходит обычную обработку. Видео без звуковой дорожки возвращает ошибку
+func (s *AudioService) ExtractAudioFromVideo(ctx context.Context, videoPath string) (string, error) {
+	// Создание выходного пути
+	outputPath := changeExt(videoPath, ".ogg")
+	if outputPath == videoPath {
+		outputPath = addSuffix(videoPath, "_audio")
+	}
+
+	// Логирование начала извлечения
+	s.logger.Info("Extracting audio from video",
+		"input", videoPath,
+		"output", outputPath,
+	)
+
+	// Формирование команды FFmpeg: видео отбрасывается, звук сжимается в моно Opus
+	cmd := exec.CommandContext(
+		ctx,
+		s.ffmpegPath,
+		"-i", videoPath,
+		"-vn",
+		"-map", "0:a:0",
+		"-acodec", "libopus",
+		"-b:a", "48k",
+		"-ac", "1",
+		"-y",
+		outputPath,
+	)
+
+	// Выполнение команды
+	output, err := s.combinedOutput(ctx, cmd)
+	if err != nil {
+		s.logger.Error("Failed to extract audio from video",
+			"error", err,
+			"output", string(output),
+		)
+		return "", fmt.Errorf("failed to extract audio from video: %w\nOutput: %s", err, string(output))
+	}
+
+	// Проверка существования выходного файла
+	if _, err := os.Stat(outputPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("output file not created: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// TrimAudio вырезает из аудиофайла промежуток с fromSeconds по toSeconds и возвращает путь к новому файлу;
+// исходный файл не меняется
+func (s *AudioService) TrimAudio(ctx context.Context, audioPath string, fromSeconds int, toSeconds int) (string, error) {
+	// Создание выходного пути
+	outputPath := changeExt(addSuffix(audioPath, fmt.Sprintf("_trim_%d_%d", fromSeconds, toSeconds)), ".ogg")
+
+	// Логирование начала вырезания
+	s.logger.Info("Trimming audio",
+		"input", audioPath,
+		"output", outputPath,
+		"from", fromSeconds,
+		"to", toSeconds,
+	)
+
+	// Формирование команды FFmpeg: поиск начала до открытия файла быстрее, а перекодирование
+	// в моно Opus дает точные границы независимо от формата исходной записи
+	cmd := exec.CommandContext(
+		ctx,
+		s.ffmpegPath,
+		"-ss", strconv.Itoa(fromSeconds),
+		"-i", audioPath,
+		"-t", strconv.Itoa(toSeconds-fromSeconds),
+		"-vn",
+		"-acodec", "libopus",
+		"-b:a", "48k",
+		"-ac", "1",
+		"-y",
+		outputPath,
+	)
+
+	// Выполнение команды
+	output, err := s.combinedOutput(ctx, cmd)
+	if err != nil {
+		s.logger.Error("Failed to trim audio",
+			"error", err,
+			"output", string(output),
+		)
+		return "", fmt.Errorf("failed to trim audio: %w\nOutput: %s", err, string(output))
+	}
+
+	// Проверка существования выходного файла
+	if _, err := os.Stat(outputPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("output file not created: %w", err)
+	}
+
+	return outputPath, nil
+}
+
 // NormalizeAudio нормализует громкость аудио файла
 func (s *AudioService) NormalizeAudio(ctx context.Context, inputPath string) (string, error) {
 	// Создание выходного пути
@@ -112,7 +233,7 @@ func (s *AudioService) NormalizeAudio(ctx context.Context, inputPath string) (st
 	)
 
 	// Выполнение команды
-	output, err := cmd.CombinedOutput()
+	output, err := s.combinedOutput(ctx, cmd)
 	if err != nil {
 		s.logger.Error("Failed to normalize audio",
 			"error", err,
@@ -151,7 +272,7 @@ func (s *AudioService) RemoveNoise(ctx context.Context, inputPath string) (strin
 	)
 
 	// Выполнение команды
-	output, err := cmd.CombinedOutput()
+	output, err := s.combinedOutput(ctx, cmd)
 	if err != nil {
 		s.logger.Error("Failed to remove noise",
 			"error", err,
@@ -168,27 +289,20 @@ func (s *AudioService) RemoveNoise(ctx context.Context, inputPath string) (strin
 	return outputPath, nil
 }
 
-// ProcessAudioForTranscription обрабатывает аудио файл для транскрибации
+// ProcessAudioForTranscription обрабатывает аудио файл для транскрибации с профилем обычной речи
 func (s *AudioService) ProcessAudioForTranscription(ctx context.Context, inputPath string) (string, error) {
-	// Конвертация в WAV
-	wavPath, err := s.ConvertToWAV(ctx, inputPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to convert to WAV: %w", err)
-	}
-
-	// Нормализация аудио
-	normalizedPath, err := s.NormalizeAudio(ctx, wavPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to normalize audio: %w", err)
-	}
+	outputPath, _, err := s.ProcessAudioWithProfile(ctx, inputPath, entity.AudioProfileSpeech)
+	return outputPath, err
+}
 
-	// Удаление шума
-	denoisedPath, err := s.RemoveNoise(ctx, normalizedPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to remove noise: %w", err)
+// removeIntermediate удаляет промежуточный файл обработки
+func (s *AudioService) removeIntermediate(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		s.logger.Warn("Failed to remove intermediate audio file",
+			"error", err,
+			"path", path,
+		)
 	}
-
-	return denoisedPath, nil
 }
 
 // GetAudioDuration возвращает длительность аудио файла в секундах
@@ -0,0 +1,103 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+)
+
+// wavHeaderReserve - запас на заголовок WAV при расчете длительности фрагмента
+const wavHeaderReserve = 4096
+
+// SplitAudio нарезает запись на фрагменты WAV (16 кГц, моно) не больше maxBytes, чтобы распознать запись,
+// которую провайдер не принимает одним файлом. Соседние фрагменты перекрываются на overlap: слово на стыке
+// целиком попадает хотя бы в один из них. Запись, которая помещается в один фрагмент, возвращается без нарезки
+func (s *AudioService) SplitAudio(ctx context.Context, audioPath string, maxBytes int64, overlap time.Duration) ([]entity.AudioChunk, error) {
+	chunkSeconds := float64(maxBytes-wavHeaderReserve) / wavBytesPerSecond
+	step := chunkSeconds - overlap.Seconds()
+	if step <= 0 {
+		return nil, fmt.Errorf("chunk of %d bytes is shorter than overlap %s", maxBytes, overlap)
+	}
+
+	duration, err := s.GetAudioDuration(ctx, audioPath)
+	if err != nil {
+		return nil, err
+	}
+	if duration <= chunkSeconds {
+		return []entity.AudioChunk{{Path: audioPath}}, nil
+	}
+
+	// Логирование начала нарезки
+	s.logger.Info("Splitting audio into chunks",
+		"input", audioPath,
+		"duration", duration,
+		"chunk_seconds", chunkSeconds,
+		"overlap", overlap,
+	)
+
+	chunks := make([]entity.AudioChunk, 0, int(duration/step)+1)
+	for start := 0.0; start < duration; start += step {
+		outputPath := changeExt(addSuffix(audioPath, fmt.Sprintf("_chunk_%d", len(chunks))), ".wav")
+		if err := s.cutChunk(ctx, audioPath, outputPath, start, chunkSeconds); err != nil {
+			for _, chunk := range chunks {
+				s.removeIntermediate(chunk.Path)
+			}
+			return nil, err
+		}
+		chunks = append(chunks, entity.AudioChunk{Path: outputPath, Offset: start})
+
+		// Последний фрагмент доходит до конца записи
+		if start+chunkSeconds >= duration {
+			break
+		}
+	}
+
+	// Логирование успешной нарезки
+	s.logger.Info("Audio split into chunks successfully",
+		"input", audioPath,
+		"chunks", len(chunks),
+	)
+
+	return chunks, nil
+}
+
+// cutChunk вырезает из записи seconds секунд начиная с start в WAV, в котором записи распознаются
+func (s *AudioService) cutChunk(ctx context.Context, inputPath, outputPath string, start, seconds float64) error {
+	// Формирование команды FFmpeg: поиск начала до открытия файла быстрее, а перекодирование
+	// дает точные границы независимо от формата исходной записи
+	cmd := exec.CommandContext(
+		ctx,
+		s.ffmpegPath,
+		"-ss", strconv.FormatFloat(start, 'f', 3, 64),
+		"-i", inputPath,
+		"-t", strconv.FormatFloat(seconds, 'f', 3, 64),
+		"-vn",
+		"-acodec", "pcm_s16le",
+		"-ar", "16000",
+		"-ac", "1",
+		"-y",
+		outputPath,
+	)
+
+	// Выполнение команды
+	output, err := s.combinedOutput(ctx, cmd)
+	if err != nil {
+		s.logger.Error("Failed to cut audio chunk",
+			"error", err,
+			"output", string(output),
+		)
+		return fmt.Errorf("failed to cut audio chunk: %w\nOutput: %s", err, string(output))
+	}
+
+	// Проверка существования выходного файла
+	if _, err := os.Stat(outputPath); os.IsNotExist(err) {
+		return fmt.Errorf("output file not created: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// executor ограничивает число одновременно выполняемых команд FFmpeg. Нормализация, шумоподавление и анализ
+// записи загружают процессор, и без ограничения параллельные задачи вытесняют остальные процессы хоста.
+// Ограничение отдельно от параллельности запросов к LLM, которые ждут сеть, а не процессор
+type executor struct {
+	slots chan struct{}
+}
+
+// newExecutor создает ограничитель на concurrency одновременных команд; при concurrency <= 0
+// одновременно выполняется не больше команд, чем доступно процессоров (GOMAXPROCS)
+func newExecutor(concurrency int) *executor {
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	return &executor{slots: make(chan struct{}, concurrency)}
+}
+
+// acquire занимает место для команды и возвращает функцию его освобождения; ожидание прерывается отменой ctx
+func (e *executor) acquire(ctx context.Context) (func(), error) {
+	select {
+	case e.slots <- struct{}{}:
+		return func() { <-e.slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to wait for ffmpeg slot: %w", ctx.Err())
+	}
+}
+
+// combinedOutput выполняет команду FFmpeg, дождавшись свободного места, и возвращает ее вывод и журнал
+func (s *AudioService) combinedOutput(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	release, err := s.acquireSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return cmd.CombinedOutput()
+}
+
+// acquireSlot занимает место для команды FFmpeg; долгое ожидание записывается в журнал,
+// чтобы было видно, что обработку задерживает ограничение параллельности
+func (s *AudioService) acquireSlot(ctx context.Context) (func(), error) {
+	start := time.Now()
+	release, err := s.executor.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if waited := time.Since(start); waited > time.Second {
+		s.logger.Info("Waited for ffmpeg slot",
+			"waited", waited,
+			"concurrency", cap(s.executor.slots),
+		)
+	}
+
+	return release, nil
+}
+
+// Version проверяет, что FFmpeg и FFprobe установлены, и возвращает версию FFmpeg. Проверка не ждет
+// свободного места для команды: она должна отвечать и тогда, когда все места заняты
+func (s *AudioService) Version(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, s.ffmpegPath, "-version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s: %w", s.ffmpegPath, err)
+	}
+	if _, err := exec.LookPath("ffprobe"); err != nil {
+		return "", fmt.Errorf("ffprobe not found: %w", err)
+	}
+
+	version, _, _ := strings.Cut(string(output), "\n")
+	version = strings.TrimPrefix(strings.TrimSpace(version), "ffmpeg version ")
+	if fields := strings.Fields(version); len(fields) > 0 {
+		version = fields[0]
+	}
+
+	return version, nil
+}
+
+// Slots возвращает число занятых мест для команд FFmpeg и их общее число
+func (s *AudioService) Slots() (int, int) {
+	return len(s.executor.slots), cap(s.executor.slots)
+}
@@ -0,0 +1,276 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+)
+
+// profileFilters - цепочки фильтров FFmpeg для профилей предобработки.
+// Все цепочки завершаются нормализацией громкости, чтобы Whisper получал сигнал одного уровня
+var profileFilters = map[entity.AudioProfile]string{
+	// Обычная речь: нормализация громкости и шумоподавление
+	entity.AudioProfileSpeech: "loudnorm=I=-16:TP=-1.5:LRA=11,afftdn=nf=-25",
+	// Телефонный разговор: оставляем полосу 300–3400 Гц, выравниваем громкость собеседников
+	entity.AudioProfilePhone: "highpass=f=300,lowpass=f=3400,afftdn=nf=-20,dynaudnorm=f=150:g=15,loudnorm=I=-16:TP=-1.5:LRA=11",
+	// Лекция в зале: гейт приглушает хвосты реверберации между словами, компрессор выравнивает
+	// голос лектора и вопросы из зала
+	entity.AudioProfileLecture: "highpass=f=80,afftdn=nf=-30,agate=threshold=0.02:ratio=2:attack=5:release=250," +
+		"acompressor=threshold=0.1:ratio=3:attack=20:release=250,loudnorm=I=-16:TP=-1.5:LRA=7",
+	// Речь на фоне музыки: шумоподавление съедает музыку вместе с голосом, поэтому только нормализация
+	entity.AudioProfileMusic: "loudnorm=I=-16:TP=-1.5:LRA=11",
+}
+
+// Параметры автоматического выбора профиля
+const (
+	// profileAnalysisSeconds - сколько секунд от начала записи анализируется
+	profileAnalysisSeconds = 300
+	// phoneMaxSampleRate - записи с такой частотой дискретизации и ниже считаются телефонными
+	phoneMaxSampleRate = 8000
+	// phoneBandGapDB - насколько сигнал выше 4000 Гц тише всего сигнала у узкополосной записи
+	phoneBandGapDB = 40
+	// musicMaxSilenceRatio - доля пауз, ниже которой запись считается речью на фоне музыки
+	musicMaxSilenceRatio = 0.02
+	// silenceFloorDB - громкость, которая считается тишиной при поиске пауз
+	silenceFloorDB = -35
+)
+
+var (
+	meanVolumePattern      = regexp.MustCompile(`mean_volume:\s*(-?[\d.]+) dB`)
+	silenceDurationPattern = regexp.MustCompile(`silence_duration:\s*([\d.]+)`)
+)
+
+// audioStats - характеристики записи, по которым выбирается профиль
+type audioStats struct {
+	SampleRate     int     // Частота дискретизации исходного файла, Гц
+	MeanVolume     float64 // Средняя громкость, дБ
+	HighBandVolume float64 // Средняя громкость выше 4000 Гц, дБ
+	SilenceRatio   float64 // Доля пауз в проанализированном фрагменте
+}
+
+// ProcessAudioWithProfile конвертирует аудио в WAV и применяет цепочку фильтров профиля.
+// Для профиля auto профиль выбирается по характеристикам записи и возвращается вместе с путем к файлу
+func (s *AudioService) ProcessAudioWithProfile(ctx context.Context, inputPath string, profile entity.AudioProfile) (string, entity.AudioProfile, error) {
+	// Выбор профиля по исходному файлу: после конвертации частота дискретизации уже 16 кГц
+	if profile == "" || profile == entity.AudioProfileAuto {
+		detected, err := s.DetectAudioProfile(ctx, inputPath)
+		if err != nil {
+			s.logger.Warn("Failed to detect audio profile, using speech",
+				"error", err,
+				"input", inputPath,
+			)
+			detected = entity.AudioProfileSpeech
+		}
+		profile = detected
+	}
+
+	filters, ok := profileFilters[profile]
+	if !ok {
+		return "", "", fmt.Errorf("unknown audio profile: %s", profile)
+	}
+
+	// Резервирование места под промежуточный WAV и результат
+	duration, err := s.GetAudioDuration(ctx, inputPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to estimate temp footprint: %w", err)
+	}
+	release, err := s.tempSpace.Reserve(ctx, int64(duration*wavBytesPerSecond)*2)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to reserve temp space: %w", err)
+	}
+	defer release()
+
+	// Конвертация в WAV
+	wavPath, err := s.ConvertToWAV(ctx, inputPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to convert to WAV: %w", err)
+	}
+	if wavPath != inputPath {
+		defer s.removeIntermediate(wavPath)
+	}
+
+	// Применение фильтров профиля
+	outputPath, err := s.applyFilters(ctx, wavPath, addSuffix(wavPath, "_"+string(profile)), filters)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to apply audio profile %s: %w", profile, err)
+	}
+
+	return outputPath, profile, nil
+}
+
+// applyFilters применяет цепочку фильтров за один проход FFmpeg.
+// loudnorm повышает частоту дискретизации, поэтому результат приводится обратно к 16 кГц
+func (s *AudioService) applyFilters(ctx context.Context, inputPath, outputPath, filters string) (string, error) {
+	// Логирование начала обработки
+	s.logger.Info("Applying audio filters",
+		"input", inputPath,
+		"output", outputPath,
+		"filters", filters,
+	)
+
+	// Формирование команды FFmpeg
+	cmd := exec.CommandContext(
+		ctx,
+		s.ffmpegPath,
+		"-i", inputPath,
+		"-af", filters,
+		"-ar", "16000",
+		"-y",
+		outputPath,
+	)
+
+	// Выполнение команды
+	output, err := s.combinedOutput(ctx, cmd)
+	if err != nil {
+		s.logger.Error("Failed to apply audio filters",
+			"error", err,
+			"output", string(output),
+		)
+		return "", fmt.Errorf("failed to apply audio filters: %w\nOutput: %s", err, string(output))
+	}
+
+	// Проверка существования выходного файла
+	if _, err := os.Stat(outputPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("output file not created: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// DetectAudioProfile выбирает профиль предобработки по характеристикам первых минут записи.
+// Профиль лекции по статистике надежно не отличить от обычной речи, поэтому он выбирается только вручную
+func (s *AudioService) DetectAudioProfile(ctx context.Context, inputPath string) (entity.AudioProfile, error) {
+	stats, err := s.analyzeAudio(ctx, inputPath)
+	if err != nil {
+		return "", err
+	}
+
+	profile := classifyAudio(stats)
+
+	s.logger.Info("Audio profile detected",
+		"input", inputPath,
+		"profile", profile,
+		"sample_rate", stats.SampleRate,
+		"mean_volume", stats.MeanVolume,
+		"high_band_volume", stats.HighBandVolume,
+		"silence_ratio", stats.SilenceRatio,
+	)
+
+	return profile, nil
+}
+
+// classifyAudio сопоставляет характеристики записи с профилем
+func classifyAudio(stats *audioStats) entity.AudioProfile {
+	// Узкополосная запись: телефон или мессенджер в режиме экономии трафика
+	if stats.SampleRate > 0 && stats.SampleRate <= phoneMaxSampleRate {
+		return entity.AudioProfilePhone
+	}
+	if stats.MeanVolume-stats.HighBandVolume > phoneBandGapDB {
+		return entity.AudioProfilePhone
+	}
+
+	// В речи есть паузы; сигнал без пауз означает фоновую музыку
+	if stats.SilenceRatio < musicMaxSilenceRatio {
+		return entity.AudioProfileMusic
+	}
+
+	return entity.AudioProfileSpeech
+}
+
+// analyzeAudio измеряет частоту дискретизации, громкость во всей полосе и выше 4000 Гц и долю пауз
+func (s *AudioService) analyzeAudio(ctx context.Context, inputPath string) (*audioStats, error) {
+	stats := &audioStats{}
+
+	// Частота дискретизации исходного файла
+	cmd := exec.CommandContext(
+		ctx,
+		"ffprobe",
+		"-v", "quiet",
+		"-select_streams", "a:0",
+		"-show_entries", "stream=sample_rate",
+		"-of", "csv=p=0",
+		inputPath,
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sample rate: %w", err)
+	}
+	stats.SampleRate, _ = strconv.Atoi(strings.TrimSpace(string(output)))
+
+	duration, err := s.GetAudioDuration(ctx, inputPath)
+	if err != nil {
+		return nil, err
+	}
+	analyzed := math.Min(duration, profileAnalysisSeconds)
+	if analyzed <= 0 {
+		return nil, fmt.Errorf("audio is empty")
+	}
+
+	// Громкость и паузы во всей полосе
+	fullBand, err := s.runAnalysis(ctx, inputPath, fmt.Sprintf("silencedetect=noise=%ddB:d=0.5,volumedetect", silenceFloorDB))
+	if err != nil {
+		return nil, err
+	}
+	stats.MeanVolume = parseMeanVolume(fullBand)
+
+	var silence float64
+	for _, match := range silenceDurationPattern.FindAllStringSubmatch(fullBand, -1) {
+		value, _ := strconv.ParseFloat(match[1], 64)
+		silence += value
+	}
+	stats.SilenceRatio = math.Min(silence/analyzed, 1)
+
+	// Громкость выше полосы телефонного канала; фильтр повторен для более крутого спада
+	highBand, err := s.runAnalysis(ctx, inputPath, "highpass=f=4000,highpass=f=4000,volumedetect")
+	if err != nil {
+		return nil, err
+	}
+	stats.HighBandVolume = parseMeanVolume(highBand)
+
+	return stats, nil
+}
+
+// runAnalysis прогоняет начало записи через фильтры анализа и возвращает журнал FFmpeg
+func (s *AudioService) runAnalysis(ctx context.Context, inputPath, filters string) (string, error) {
+	cmd := exec.CommandContext(
+		ctx,
+		s.ffmpegPath,
+		"-t", strconv.Itoa(profileAnalysisSeconds),
+		"-i", inputPath,
+		"-af", filters,
+		"-f", "null",
+		"-",
+	)
+
+	output, err := s.combinedOutput(ctx, cmd)
+	if err != nil {
+		s.logger.Error("Failed to analyze audio",
+			"error", err,
+			"output", string(output),
+		)
+		return "", fmt.Errorf("failed to analyze audio: %w", err)
+	}
+
+	return string(output), nil
+}
+
+// parseMeanVolume извлекает среднюю громкость из журнала volumedetect.
+// Для полной тишины volumedetect выводит -inf, что соответствует минимуму 16-битного сигнала
+func parseMeanVolume(output string) float64 {
+	match := meanVolumePattern.FindStringSubmatch(output)
+	if match == nil {
+		return -91
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return -91
+	}
+	return value
+}
@@ -0,0 +1,174 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/config"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/deepgram"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/deepseek"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/ffmpeg"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/notion"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/openai"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/pyannote"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/queue"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/whisperlocal"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/tempfs"
+)
+
+// queueStallThreshold - сколько воркер, не занятый задачей, может не обращаться к очереди,
+// прежде чем самодиагностика сочтет его зависшим. Пустую очередь воркер опрашивает каждую секунду
+const queueStallThreshold = time.Minute
+
+// minFreeDiskBytes - свободное место в каталоге временных файлов, ниже которого проверка диска не проходит
+const minFreeDiskBytes = 1 << 30
+
+// healthCheck - проверка самодиагностики из названия и функции проверки. Название - имя зависимости
+// или идентификатор его перевода в каталоге сообщений
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) (string, error)
+}
+
+// Name возвращает название проверки
+func (c healthCheck) Name() string {
+	return c.name
+}
+
+// Check выполняет проверку
+func (c healthCheck) Check(ctx context.Context) (string, error) {
+	return c.check(ctx)
+}
+
+// pingCheck возвращает проверку, которая только измеряет задержку ответа зависимости
+func pingCheck(name string, ping func(ctx context.Context) error) healthCheck {
+	return healthCheck{name: name, check: func(ctx context.Context) (string, error) {
+		return "", ping(ctx)
+	}}
+}
+
+// newHealthChecks создает проверки самодиагностики для команды /admin doctor в порядке вывода в отчете:
+// хранилище данных, FFmpeg, ключи провайдеров, воркер очереди и место в каталоге временных файлов
+func newHealthChecks(
+	cfg *config.Config,
+	store *storage,
+	audioService *ffmpeg.AudioService,
+	transcriptionService *openai.TranscriptionService,
+	localTranscriptionService *whisperlocal.TranscriptionService,
+	deepgramTranscriptionService *deepgram.TranscriptionService,
+	pyannoteDiarizationService *pyannote.DiarizationService,
+	summarizationService *deepseek.SummarizationService,
+	notionService *notion.NotionService,
+	queueService *queue.QueueService,
+	tempSpace *tempfs.Space,
+) []service.HealthCheck {
+	checks := make([]service.HealthCheck, 0)
+
+	// Хранилище данных, выбранное в DB_DRIVER
+	if store.postgresDB != nil {
+		checks = append(checks, pingCheck("PostgreSQL", store.postgresDB.Ping))
+	}
+	if store.redisClient != nil {
+		checks = append(checks, pingCheck("Redis", store.redisClient.Ping))
+	}
+	if store.sqliteDB != nil {
+		checks = append(checks, pingCheck("SQLite", store.sqliteDB.Ping))
+	}
+
+	checks = append(checks,
+		healthCheck{name: "FFmpeg", check: func(ctx context.Context) (string, error) {
+			version, err := audioService.Version(ctx)
+			if err != nil {
+				return "", err
+			}
+			busy, total := audioService.Slots()
+			return i18n.T(ctx, "health.ffmpeg", version, busy, total), nil
+		}},
+		pingCheck("OpenAI", transcriptionService.Ping),
+	)
+	// Локальный сервер распознавания, если записи распознаются им
+	if localTranscriptionService != nil {
+		checks = append(checks, healthCheck{name: "health.local_whisper", check: func(ctx context.Context) (string, error) {
+			if err := localTranscriptionService.Ping(ctx); err != nil {
+				return "", err
+			}
+			busy, total := localTranscriptionService.Slots()
+			return i18n.T(ctx, "health.local_whisper.busy", busy, total), nil
+		}})
+	}
+	if deepgramTranscriptionService != nil {
+		checks = append(checks, pingCheck("Deepgram", deepgramTranscriptionService.Ping))
+	}
+	if pyannoteDiarizationService != nil {
+		checks = append(checks, pingCheck("health.diarization", pyannoteDiarizationService.Ping))
+	}
+	checks = append(checks,
+		pingCheck("DeepSeek", summarizationService.Ping),
+		healthCheck{name: "Notion", check: func(ctx context.Context) (string, error) {
+			// Общий ключ Notion необязателен: пользователи подключают Notion своими ключами
+			if cfg.Notion.CurrentAPIKey() == "" {
+				return i18n.T(ctx, "health.notion.no_key"), nil
+			}
+			return "", notionService.Ping(ctx)
+		}},
+		healthCheck{name: "health.queue_worker", check: func(ctx context.Context) (string, error) {
+			return checkQueueWorker(ctx, queueService)
+		}},
+		healthCheck{name: "health.disk", check: func(ctx context.Context) (string, error) {
+			return checkDiskSpace(ctx, tempSpace.Dir())
+		}},
+	)
+
+	return checks
+}
+
+// checkQueueWorker проверяет, что воркер очереди запущен и либо обрабатывает задачу, либо недавно обращался к очереди
+func checkQueueWorker(ctx context.Context, queueService *queue.QueueService) (string, error) {
+	size, err := queueService.GetQueueSize(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	state := queueService.WorkerState()
+	switch {
+	case !state.Running:
+		return "", errors.New("queue worker is not running")
+	case state.JobID != 0:
+		return i18n.T(ctx, "health.queue.busy",
+			state.JobID, time.Since(state.JobStartedAt).Round(time.Second), size), nil
+	case state.Paused:
+		return i18n.T(ctx, "health.queue.paused", size), nil
+	case time.Since(state.LastPoll) > queueStallThreshold:
+		return "", fmt.Errorf("queue worker has not polled the queue for %s, queue size %d",
+			time.Since(state.LastPoll).Round(time.Second), size)
+	default:
+		return i18n.T(ctx, "health.queue.idle", size), nil
+	}
+}
+
+// checkDiskSpace проверяет свободное место на диске каталога dir
+func checkDiskSpace(ctx context.Context, dir string) (string, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return "", fmt.Errorf("failed to get disk stats for %s: %w", dir, err)
+	}
+
+	free := uint64(stat.Bavail) * uint64(stat.Bsize)
+	total := uint64(stat.Blocks) * uint64(stat.Bsize)
+	detail := i18n.T(ctx, "health.disk.free", gigabytes(free), gigabytes(total), dir)
+	if free < minFreeDiskBytes {
+		return detail, fmt.Errorf("less than %.1f GB free in %s", gigabytes(minFreeDiskBytes), dir)
+	}
+
+	return detail, nil
+}
+
+// gigabytes переводит байты в гигабайты
+func gigabytes(bytes uint64) float64 {
+	return float64(bytes) / (1 << 30)
+}
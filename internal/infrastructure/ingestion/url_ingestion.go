@@ -0,0 +1,181 @@
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	"github.com/112Alex/project_obsidian/pkg/safehttp"
+	"github.com/112Alex/project_obsidian/pkg/tempfs"
+)
+
+// Ограничения загрузки файлов по ссылкам
+const (
+	downloadTimeout = 15 * time.Minute
+	// defaultExtension - расширение файла, если его нельзя определить ни по ссылке, ни по типу содержимого
+	defaultExtension = ".mp3"
+)
+
+// octetStreamTypes - типы содержимого, под которыми хранилища отдают файлы любого формата.
+// Такие файлы принимаются: формат записи проверит FFmpeg при обработке
+var octetStreamTypes = map[string]bool{
+	"application/octet-stream": true,
+	"binary/octet-stream":      true,
+	"application/ogg":          true,
+}
+
+// URLIngestionService представляет собой сервис загрузки записей по прямым ссылкам на аудиофайлы.
+// Ссылки присылают пользователи, поэтому сервис не обращается к адресам внутренней сети и проверяет
+// тип и размер файла до и во время загрузки
+type URLIngestionService struct {
+	client    *http.Client
+	maxSize   int64
+	tempSpace *tempfs.Space
+	logger    *logger.Logger
+}
+
+// NewURLIngestionService создает новый сервис загрузки записей по ссылкам. maxSize - наибольший размер файла в байтах
+func NewURLIngestionService(maxSize int64, tempSpace *tempfs.Space, logger *logger.Logger) *URLIngestionService {
+	return &URLIngestionService{
+		// Длительность загрузки ограничивает контекст
+		client:    safehttp.NewClient(0),
+		maxSize:   maxSize,
+		tempSpace: tempSpace,
+		logger:    logger,
+	}
+}
+
+// MaxSize возвращает наибольший размер загружаемого файла в байтах
+func (s *URLIngestionService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Download загружает файл по ссылке во временный каталог пользователя и возвращает путь к файлу и его имя
+func (s *URLIngestionService) Download(ctx context.Context, userID int64, fileURL string) (string, string, error) {
+	// Логирование начала загрузки файла
+	s.logger.Info("Downloading file by URL",
+		"url", fileURL,
+		"user_id", userID,
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+
+	// Создание директории для сохранения файлов пользователя
+	userDir := filepath.Join(s.tempSpace.Dir(), "uploads", fmt.Sprintf("user_%d", userID))
+	if err := os.MkdirAll(userDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create user directory: %w", err)
+	}
+
+	var filePath, fileName string
+	err := metrics.Track(ctx, s.logger, metrics.ProviderURL, "download_file", func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		// Проверка типа и заявленного размера до загрузки
+		contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if !isAudioContentType(contentType) {
+			return fmt.Errorf("%w: content type %s", service.ErrRemoteFileNotAudio, contentType)
+		}
+		if resp.ContentLength > s.maxSize {
+			return fmt.Errorf("%w: %d bytes", service.ErrRemoteFileTooLarge, resp.ContentLength)
+		}
+
+		// Резервирование места во временном каталоге: без заявленного размера - под наибольший файл
+		reservation := resp.ContentLength
+		if reservation <= 0 {
+			reservation = s.maxSize
+		}
+		release, err := s.tempSpace.Reserve(ctx, reservation)
+		if err != nil {
+			return fmt.Errorf("failed to reserve temp space: %w", err)
+		}
+		defer release()
+
+		// Имя файла берется из ссылки после перенаправлений
+		fileName = path.Base(resp.Request.URL.Path)
+		if fileName == "." || fileName == "/" {
+			fileName = "audio"
+		}
+		ext := path.Ext(fileName)
+		if ext == "" || len(ext) > 5 {
+			ext = extensionByType(contentType)
+			fileName += ext
+		}
+		filePath = filepath.Join(userDir, fmt.Sprintf("link_%d%s", time.Now().UnixNano(), ext))
+
+		file, err := os.Create(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		// Размер проверяется и во время загрузки: сервер может не указать его или указать неверно
+		written, err := io.Copy(file, io.LimitReader(resp.Body, s.maxSize+1))
+		if err != nil {
+			return err
+		}
+		if written > s.maxSize {
+			return fmt.Errorf("%w: more than %d bytes", service.ErrRemoteFileTooLarge, s.maxSize)
+		}
+		return nil
+	})
+	if err != nil {
+		if filePath != "" {
+			os.Remove(filePath)
+		}
+		s.logger.Warn("Failed to download file by URL",
+			"error", err,
+			"url", fileURL,
+		)
+		return "", "", fmt.Errorf("failed to download file by URL: %w", err)
+	}
+
+	// Логирование успешной загрузки файла
+	s.logger.Info("File downloaded by URL successfully",
+		"path", filePath,
+	)
+
+	return filePath, fileName, nil
+}
+
+// isAudioContentType проверяет, что по типу содержимого файл может быть записью.
+// Пустой тип допускается: его не указывают некоторые файловые хранилища
+func isAudioContentType(contentType string) bool {
+	return contentType == "" ||
+		strings.HasPrefix(contentType, "audio/") ||
+		strings.HasPrefix(contentType, "video/") ||
+		octetStreamTypes[contentType]
+}
+
+// extensionByType возвращает расширение файла по типу содержимого
+func extensionByType(contentType string) string {
+	if strings.HasPrefix(contentType, "audio/") || strings.HasPrefix(contentType, "video/") {
+		if extensions, err := mime.ExtensionsByType(contentType); err == nil && len(extensions) > 0 {
+			return extensions[0]
+		}
+	}
+	return defaultExtension
+}
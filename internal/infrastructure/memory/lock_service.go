@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+)
+
+// LockService реализует интерфейс LockService в памяти процесса для запуска без Redis.
+// Блокировки действуют только внутри одного экземпляра приложения
+type LockService struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+// keyLock - блокировка одного ключа; holders учитывает владельца и ожидающих,
+// чтобы удалить блокировку из карты, когда она больше никому не нужна
+type keyLock struct {
+	sem     chan struct{}
+	holders int
+}
+
+// NewLockService создает сервис блокировок в памяти
+func NewLockService() service.LockService {
+	return &LockService{
+		locks: make(map[string]*keyLock),
+	}
+}
+
+// Lock захватывает блокировку key, ожидая ее освобождения не дольше wait, и возвращает функцию освобождения
+func (s *LockService) Lock(ctx context.Context, key string, wait time.Duration) (func(), error) {
+	lock := s.acquireRef(key)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case lock.sem <- struct{}{}:
+	case <-timer.C:
+		s.releaseRef(key, lock)
+		return nil, fmt.Errorf("timed out waiting for lock %s after %s", key, wait)
+	case <-ctx.Done():
+		s.releaseRef(key, lock)
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", key, ctx.Err())
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-lock.sem
+			s.releaseRef(key, lock)
+		})
+	}, nil
+}
+
+// acquireRef возвращает блокировку ключа, создавая ее при необходимости
+func (s *LockService) acquireRef(key string) *keyLock {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, ok := s.locks[key]
+	if !ok {
+		lock = &keyLock{sem: make(chan struct{}, 1)}
+		s.locks[key] = lock
+	}
+	lock.holders++
+	return lock
+}
+
+// releaseRef удаляет блокировку ключа из карты, когда ее больше никто не удерживает и не ждет
+func (s *LockService) releaseRef(key string, lock *keyLock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock.holders--
+	if lock.holders == 0 {
+		delete(s.locks, key)
+	}
+}
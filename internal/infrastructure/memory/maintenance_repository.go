@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// MaintenanceRepository реализует интерфейс MaintenanceRepository в памяти процесса для запуска без Redis.
+// Режим не переживает перезапуск приложения: после него бот сразу возобновляет работу
+type MaintenanceRepository struct {
+	mu          sync.Mutex
+	maintenance *entity.Maintenance
+}
+
+// NewMaintenanceRepository создает новое хранилище режима технического обслуживания в памяти
+func NewMaintenanceRepository() repository.MaintenanceRepository {
+	return &MaintenanceRepository{}
+}
+
+// Get возвращает включенный режим обслуживания или nil, если он выключен
+func (r *MaintenanceRepository) Get(ctx context.Context) (*entity.Maintenance, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maintenance == nil {
+		return nil, nil
+	}
+	maintenance := *r.maintenance
+	return &maintenance, nil
+}
+
+// Save включает режим обслуживания или обновляет его параметры
+func (r *MaintenanceRepository) Save(ctx context.Context, maintenance *entity.Maintenance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	saved := *maintenance
+	r.maintenance = &saved
+	return nil
+}
+
+// Clear выключает режим обслуживания
+func (r *MaintenanceRepository) Clear(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.maintenance = nil
+	return nil
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// notionOAuthState - выданный пользователю state входа в Notion
+type notionOAuthState struct {
+	userID    int64
+	expiresAt time.Time
+}
+
+// NotionOAuthStateRepository реализует интерфейс NotionOAuthStateRepository в памяти процесса для запуска
+// без Redis. Выданные state не переживают перезапуск приложения: пользователю придется запросить вход заново
+type NotionOAuthStateRepository struct {
+	mu     sync.Mutex
+	states map[string]notionOAuthState
+}
+
+// NewNotionOAuthStateRepository создает новое хранилище параметров state входа в Notion в памяти
+func NewNotionOAuthStateRepository() repository.NotionOAuthStateRepository {
+	return &NotionOAuthStateRepository{
+		states: make(map[string]notionOAuthState),
+	}
+}
+
+// Save сохраняет state, выданный пользователю, на время ttl. Истекшие state удаляются здесь же,
+// чтобы незавершенные входы не накапливались
+func (r *NotionOAuthStateRepository) Save(ctx context.Context, state string, userID int64, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for key, saved := range r.states {
+		if !now.Before(saved.expiresAt) {
+			delete(r.states, key)
+		}
+	}
+	r.states[state] = notionOAuthState{userID: userID, expiresAt: now.Add(ttl)}
+
+	return nil
+}
+
+// Pop возвращает ID пользователя, которому выдан state, и удаляет state
+func (r *NotionOAuthStateRepository) Pop(ctx context.Context, state string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	saved, ok := r.states[state]
+	if !ok {
+		return 0, nil
+	}
+	delete(r.states, state)
+
+	if !time.Now().Before(saved.expiresAt) {
+		return 0, nil
+	}
+	return saved.userID, nil
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// popTimeout - сколько Pop ждет задачу в пустой очереди, как BLPop в очереди Redis
+const popTimeout = time.Second
+
+// QueueRepository реализует интерфейс QueueRepository в памяти процесса для запуска без Redis.
+// Задачи хранятся в JSON, как в Redis, чтобы обработчики получали полезную нагрузку в том же виде.
+// Очередь не переживает перезапуск приложения
+type QueueRepository struct {
+	mu     sync.Mutex
+	queues map[string][][]byte
+	// wake закрывается при добавлении задачи, чтобы разбудить ожидающие Pop
+	wake chan struct{}
+}
+
+// NewQueueRepository создает новую очередь задач в памяти
+func NewQueueRepository() repository.QueueRepository {
+	return &QueueRepository{
+		queues: make(map[string][][]byte),
+		wake:   make(chan struct{}),
+	}
+}
+
+// Push добавляет задачу в очередь
+func (r *QueueRepository) Push(ctx context.Context, queueName string, job *entity.QueueJob) error {
+	// Устанавливаем время создания задачи
+	job.CreatedAt = time.Now()
+
+	// Сериализуем задачу в JSON
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	r.mu.Lock()
+	r.queues[queueName] = append(r.queues[queueName], jobJSON)
+	close(r.wake)
+	r.wake = make(chan struct{})
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Pop извлекает задачу из очереди, ожидая ее не дольше popTimeout; для пустой очереди возвращает nil
+func (r *QueueRepository) Pop(ctx context.Context, queueName string) (*entity.QueueJob, error) {
+	timer := time.NewTimer(popTimeout)
+	defer timer.Stop()
+
+	for {
+		r.mu.Lock()
+		queue := r.queues[queueName]
+		if len(queue) > 0 {
+			jobJSON := queue[0]
+			queue[0] = nil
+			r.queues[queueName] = queue[1:]
+			r.mu.Unlock()
+
+			// Десериализуем задачу из JSON
+			var job entity.QueueJob
+			if err := json.Unmarshal(jobJSON, &job); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+			}
+			return &job, nil
+		}
+		wake := r.wake
+		r.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-timer.C:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, nil
+		}
+	}
+}
+
+// Size возвращает размер очереди
+func (r *QueueRepository) Size(ctx context.Context, queueName string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return int64(len(r.queues[queueName])), nil
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// RateLimitRepository реализует интерфейс RateLimitRepository в памяти процесса для запуска без Redis.
+// Учтенные события не переживают перезапуск приложения: после него лимиты начинаются заново
+type RateLimitRepository struct {
+	mu     sync.Mutex
+	events map[string][]time.Time
+}
+
+// NewRateLimitRepository создает новое хранилище событий для ограничения частоты в памяти
+func NewRateLimitRepository() repository.RateLimitRepository {
+	return &RateLimitRepository{
+		events: make(map[string][]time.Time),
+	}
+}
+
+// Take учитывает событие key, если за последние window событий было меньше limit, и возвращает 0.
+// Иначе возвращает время, через которое можно будет повторить попытку
+func (r *RateLimitRepository) Take(ctx context.Context, key string, limit int, window time.Duration) (time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// События упорядочены по времени, поэтому устаревшие находятся в начале
+	now := time.Now()
+	events := r.events[key]
+	for len(events) > 0 && !events[0].After(now.Add(-window)) {
+		events = events[1:]
+	}
+
+	if len(events) < limit {
+		r.events[key] = append(events, now)
+		return 0, nil
+	}
+
+	r.events[key] = events
+	return max(events[0].Add(window).Sub(now), time.Millisecond), nil
+}
@@ -2,31 +2,52 @@ package notion
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/112Alex/project_obsidian/pkg/i18n"
 	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
 	"github.com/jomei/notionapi"
 )
 
 // NotionService представляет собой сервис для работы с Notion API
 type NotionService struct {
-	client *notionapi.Client
+	apiKey func() string
 	logger *logger.Logger
-}
 
-// NewNotionService создает новый сервис для работы с Notion API
-func NewNotionService(apiKey string, logger *logger.Logger) *NotionService {
-	// Создание клиента Notion API
-	client := notionapi.NewClient(notionapi.Token(apiKey))
+	mu        sync.Mutex
+	clientKey string
+	client    *notionapi.Client
+}
 
+// NewNotionService создает новый сервис для работы с Notion API.
+// apiKey вызывается перед каждым запросом, поэтому ротация ключа подхватывается без перезапуска
+func NewNotionService(apiKey func() string, logger *logger.Logger) *NotionService {
 	return &NotionService{
-		client: client,
+		apiKey: apiKey,
 		logger: logger,
 	}
 }
 
+// getClient возвращает клиент Notion API с текущим ключом, пересоздавая его после ротации ключа
+func (s *NotionService) getClient() *notionapi.Client {
+	key := s.apiKey()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == nil || s.clientKey != key {
+		s.client = notionapi.NewClient(notionapi.Token(key))
+		s.clientKey = key
+	}
+	return s.client
+}
+
 // CreateDatabase создает новую базу данных в Notion
 func (s *NotionService) CreateDatabase(ctx context.Context, userID int64, title string) (string, error) {
 	parentPageID := fmt.Sprintf("%d", userID)
@@ -108,11 +129,23 @@ func (s *NotionService) CreateDatabase(ctx context.Context, userID int64, title
 				Type:   "number",
 				Number: notionapi.NumberFormat{Format: notionapi.FormatNumberWithCommas},
 			},
+			// Неделя записи для группировки в представлении "По неделям"
+			"Week": notionapi.FormulaPropertyConfig{
+				Type: "formula",
+				Formula: notionapi.FormulaConfig{
+					Expression: `formatDate(prop("Date"), "GGGG-[W]WW")`,
+				},
+			},
 		},
 	}
 
 	// Выполнение запроса
-	database, err := s.client.Database.Create(ctx, req)
+	var database *notionapi.Database
+	err := metrics.Track(ctx, s.logger, metrics.ProviderNotion, "create_database", func(ctx context.Context) error {
+		var err error
+		database, err = s.getClient().Database.Create(ctx, req)
+		return err
+	})
 	if err != nil {
 		s.logger.Error("Failed to create Notion database",
 			"error", err,
@@ -128,8 +161,8 @@ func (s *NotionService) CreateDatabase(ctx context.Context, userID int64, title
 	return string(database.ID), nil
 }
 
-// CreatePage создает новую страницу в базе данных Notion
-func (s *NotionService) CreatePage(ctx context.Context, databaseID, title, content string) (string, error) {
+// CreatePage создает новую пустую страницу в базе данных Notion. Содержимое добавляется частями через AppendMarkdown
+func (s *NotionService) CreatePage(ctx context.Context, databaseID, title string) (string, error) {
 	// Логирование начала создания страницы
 	s.logger.Info("Creating Notion page",
 		"database_id", databaseID,
@@ -170,11 +203,15 @@ func (s *NotionService) CreatePage(ctx context.Context, databaseID, title, conte
 				},
 			},
 		},
-		Children: s.convertMarkdownToBlocks(content),
 	}
 
 	// Выполнение запроса
-	page, err := s.client.Page.Create(ctx, req)
+	var page *notionapi.Page
+	err := metrics.Track(ctx, s.logger, metrics.ProviderNotion, "create_page", func(ctx context.Context) error {
+		var err error
+		page, err = s.getClient().Page.Create(ctx, req)
+		return err
+	})
 	if err != nil {
 		s.logger.Error("Failed to create Notion page",
 			"error", err,
@@ -190,6 +227,363 @@ func (s *NotionService) CreatePage(ctx context.Context, databaseID, title, conte
 	return string(page.ID), nil
 }
 
+// PageExists проверяет, что страница существует и не перемещена в корзину
+func (s *NotionService) PageExists(ctx context.Context, pageID string) (bool, error) {
+	exists := true
+	err := metrics.Track(ctx, s.logger, metrics.ProviderNotion, "get_page", func(ctx context.Context) error {
+		page, err := s.getClient().Page.Get(ctx, notionapi.PageID(pageID))
+		if err != nil {
+			// Удаленная страница или страница, к которой у интеграции больше нет доступа, не является сбоем API
+			var apiErr *notionapi.Error
+			if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+				exists = false
+				return nil
+			}
+			return err
+		}
+		exists = !page.Archived
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to get Notion page",
+			"error", err,
+			"page_id", pageID,
+		)
+		return false, fmt.Errorf("failed to get Notion page: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ArchivePage перемещает страницу в корзину Notion, откуда пользователь может ее восстановить.
+// Страница, которую уже удалили или к которой у интеграции больше нет доступа, не считается ошибкой
+func (s *NotionService) ArchivePage(ctx context.Context, pageID string) error {
+	err := metrics.Track(ctx, s.logger, metrics.ProviderNotion, "archive_page", func(ctx context.Context) error {
+		_, err := s.getClient().Page.Update(ctx, notionapi.PageID(pageID), &notionapi.PageUpdateRequest{
+			Properties: notionapi.Properties{},
+			Archived:   true,
+		})
+		var apiErr *notionapi.Error
+		if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to archive Notion page",
+			"error", err,
+			"page_id", pageID,
+		)
+		return fmt.Errorf("failed to archive Notion page: %w", err)
+	}
+
+	return nil
+}
+
+// Ping проверяет, что Notion API принимает текущий ключ: запрашивает пользователя-бота, которому выдан ключ
+func (s *NotionService) Ping(ctx context.Context) error {
+	if _, err := s.getClient().User.Me(ctx); err != nil {
+		return fmt.Errorf("failed to get Notion bot user: %w", err)
+	}
+	return nil
+}
+
+// notionTextLimit - максимальная длина одного текстового фрагмента в Notion API
+const notionTextLimit = 2000
+
+// notionAppendLimit - максимальное число блоков в одном запросе добавления
+const notionAppendLimit = 100
+
+// AppendImage добавляет в конец страницы изображение с подписью и распознанный на нем текст.
+// Изображение передается внешней ссылкой: Notion загружает его по адресу при показе страницы
+func (s *NotionService) AppendImage(ctx context.Context, pageID, imageURL, caption, text string) error {
+	// Логирование начала добавления изображения
+	s.logger.Info("Appending image to Notion page",
+		"page_id", pageID,
+		"text_length", len(text),
+	)
+
+	blocks := []notionapi.Block{
+		notionapi.ImageBlock{
+			Image: notionapi.Image{
+				Type:     notionapi.FileTypeExternal,
+				External: &notionapi.FileObject{URL: imageURL},
+				Caption: []notionapi.RichText{
+					{
+						Type: "text",
+						Text: &notionapi.Text{
+							Content: caption,
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		for _, chunk := range splitRunes(strings.TrimSpace(paragraph), notionTextLimit) {
+			blocks = append(blocks, notionapi.ParagraphBlock{
+				Paragraph: notionapi.Paragraph{
+					RichText: []notionapi.RichText{
+						{
+							Type: "text",
+							Text: &notionapi.Text{
+								Content: chunk,
+							},
+						},
+					},
+				},
+			})
+		}
+	}
+
+	// Остаток текста, не поместившийся в один запрос, не добавляется: он сохранен вместе с фотографией
+	if len(blocks) > notionAppendLimit {
+		blocks = blocks[:notionAppendLimit]
+	}
+
+	err := metrics.Track(ctx, s.logger, metrics.ProviderNotion, "append_blocks", func(ctx context.Context) error {
+		_, err := s.getClient().Block.AppendChildren(ctx, notionapi.BlockID(pageID), &notionapi.AppendBlockChildrenRequest{
+			Children: blocks,
+		})
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to append image to Notion page",
+			"error", err,
+			"page_id", pageID,
+		)
+		return fmt.Errorf("failed to append image to Notion page: %w", err)
+	}
+
+	return nil
+}
+
+// AppendMarkdown добавляет в конец страницы следующую часть содержимого в Markdown: не больше notionAppendLimit
+// блоков начиная с блока offset. Возвращает номер блока, с которого продолжится выгрузка, и признак того,
+// что содержимое добавлено полностью. Выгрузку, прерванную ошибкой, можно продолжить с последнего offset
+func (s *NotionService) AppendMarkdown(ctx context.Context, pageID, content string, offset int) (int, bool, error) {
+	blocks := s.convertMarkdownToBlocks(content)
+	if offset >= len(blocks) {
+		return len(blocks), true, nil
+	}
+
+	end := min(offset+notionAppendLimit, len(blocks))
+
+	// Логирование добавления части содержимого
+	s.logger.Info("Appending blocks to Notion page",
+		"page_id", pageID,
+		"offset", offset,
+		"count", end-offset,
+		"total", len(blocks),
+	)
+
+	err := metrics.Track(ctx, s.logger, metrics.ProviderNotion, "append_blocks", func(ctx context.Context) error {
+		_, err := s.getClient().Block.AppendChildren(ctx, notionapi.BlockID(pageID), &notionapi.AppendBlockChildrenRequest{
+			Children: blocks[offset:end],
+		})
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to append blocks to Notion page",
+			"error", err,
+			"page_id", pageID,
+			"offset", offset,
+		)
+		return offset, false, fmt.Errorf("failed to append blocks to Notion page: %w", err)
+	}
+
+	return end, end == len(blocks), nil
+}
+
+// AppendRelatedPages добавляет в конец страницы раздел «Связанные заметки» со списком упоминаний связанных страниц.
+// Упоминание показывает актуальное название страницы и ведет на нее; заголовок раздела - на языке из контекста
+func (s *NotionService) AppendRelatedPages(ctx context.Context, pageID string, relatedPageIDs []string) error {
+	// Логирование добавления связанных заметок
+	s.logger.Info("Appending related pages to Notion page",
+		"page_id", pageID,
+		"count", len(relatedPageIDs),
+	)
+
+	blocks := []notionapi.Block{
+		notionapi.Heading2Block{
+			Heading2: notionapi.Heading{
+				RichText: []notionapi.RichText{
+					{
+						Type: "text",
+						Text: &notionapi.Text{
+							Content: i18n.T(ctx, "notion.related_notes"),
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, relatedPageID := range relatedPageIDs {
+		blocks = append(blocks, notionapi.BulletedListItemBlock{
+			BulletedListItem: notionapi.ListItem{
+				RichText: []notionapi.RichText{
+					{
+						Type: "mention",
+						Mention: &notionapi.Mention{
+							Type: notionapi.MentionTypePage,
+							Page: &notionapi.PageMention{ID: notionapi.ObjectID(relatedPageID)},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	err := metrics.Track(ctx, s.logger, metrics.ProviderNotion, "append_blocks", func(ctx context.Context) error {
+		_, err := s.getClient().Block.AppendChildren(ctx, notionapi.BlockID(pageID), &notionapi.AppendBlockChildrenRequest{
+			Children: blocks,
+		})
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to append related pages to Notion page",
+			"error", err,
+			"page_id", pageID,
+		)
+		return fmt.Errorf("failed to append related pages to Notion page: %w", err)
+	}
+
+	return nil
+}
+
+// splitRunes разбивает текст на части не длиннее limit символов; пустой текст дает пустой список
+func splitRunes(text string, limit int) []string {
+	runes := []rune(text)
+	parts := make([]string, 0, len(runes)/limit+1)
+	for len(runes) > 0 {
+		n := min(limit, len(runes))
+		parts = append(parts, string(runes[:n]))
+		runes = runes[n:]
+	}
+	return parts
+}
+
+// CreateViewsPage создает рядом с базой данных страницу навигации с выносками-ссылками на представления.
+// Notion API не позволяет создавать представления базы, поэтому выноски ведут в базу
+// и подсказывают, по какому свойству сгруппировать записи. Тексты страницы - на языке из контекста
+func (s *NotionService) CreateViewsPage(ctx context.Context, databaseID string) (string, error) {
+	// Логирование начала создания страницы навигации
+	s.logger.Info("Creating Notion views page",
+		"database_id", databaseID,
+	)
+
+	// Получение базы данных, чтобы разместить страницу рядом с ней
+	var database *notionapi.Database
+	err := metrics.Track(ctx, s.logger, metrics.ProviderNotion, "get_database", func(ctx context.Context) error {
+		var err error
+		database, err = s.getClient().Database.Get(ctx, notionapi.DatabaseID(databaseID))
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to get Notion database",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get Notion database: %w", err)
+	}
+
+	// Создание запроса на создание страницы
+	req := &notionapi.PageCreateRequest{
+		Parent: notionapi.Parent{
+			Type:   notionapi.ParentTypePageID,
+			PageID: database.Parent.PageID,
+		},
+		Properties: notionapi.Properties{
+			"title": notionapi.TitleProperty{
+				Title: []notionapi.RichText{
+					{
+						Type: "text",
+						Text: &notionapi.Text{
+							Content: i18n.T(ctx, "notion.hub.title"),
+						},
+					},
+				},
+			},
+		},
+		Children: []notionapi.Block{
+			linkedCallout("🏷", i18n.T(ctx, "notion.hub.tags.title"), i18n.T(ctx, "notion.hub.tags.text"), database.URL),
+			linkedCallout("📅", i18n.T(ctx, "notion.hub.weeks.title"), i18n.T(ctx, "notion.hub.weeks.text"), database.URL),
+			linkedCallout("🗂", i18n.T(ctx, "notion.hub.all.title"), i18n.T(ctx, "notion.hub.all.text"), database.URL),
+		},
+	}
+
+	// Выполнение запроса
+	var page *notionapi.Page
+	err = metrics.Track(ctx, s.logger, metrics.ProviderNotion, "create_page", func(ctx context.Context) error {
+		var err error
+		page, err = s.getClient().Page.Create(ctx, req)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to create Notion views page",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to create Notion views page: %w", err)
+	}
+
+	// Логирование успешного создания страницы навигации
+	s.logger.Info("Notion views page created successfully",
+		"page_id", page.ID,
+	)
+
+	return string(page.ID), nil
+}
+
+// linkedCallout создает выноску с заголовком-ссылкой и пояснением
+func linkedCallout(emoji, title, description, url string) notionapi.CalloutBlock {
+	icon := notionapi.Emoji(emoji)
+	return notionapi.CalloutBlock{
+		Callout: notionapi.Callout{
+			RichText: []notionapi.RichText{
+				{
+					Type: "text",
+					Text: &notionapi.Text{
+						Content: title,
+						Link:    &notionapi.Link{Url: url},
+					},
+					Annotations: &notionapi.Annotations{Bold: true},
+				},
+				{
+					Type: "text",
+					Text: &notionapi.Text{
+						Content: "\n" + description,
+					},
+				},
+			},
+			Icon: &notionapi.Icon{
+				Type:  "emoji",
+				Emoji: &icon,
+			},
+		},
+	}
+}
+
+// calloutMarker - пометка цитаты Markdown, которая выгружается в Notion выноской
+const calloutMarker = "[!callout]"
+
+// textCallout создает выноску с текстом и значком
+func textCallout(emoji, text string) notionapi.CalloutBlock {
+	icon := notionapi.Emoji(emoji)
+	return notionapi.CalloutBlock{
+		Callout: notionapi.Callout{
+			RichText: []notionapi.RichText{
+				{
+					Type: "text",
+					Text: &notionapi.Text{Content: text},
+				},
+			},
+			Icon: &notionapi.Icon{
+				Type:  "emoji",
+				Emoji: &icon,
+			},
+		},
+	}
+}
+
 // ConvertMarkdownToBlocks satisfies the service.NotionService interface
 func (s *NotionService) ConvertMarkdownToBlocks(ctx context.Context, markdown string) (interface{}, error) {
 	return s.convertMarkdownToBlocks(markdown), nil
@@ -294,6 +688,12 @@ func (s *NotionService) convertMarkdownToBlocks(markdown string) []notionapi.Blo
 				},
 			})
 		case "quote":
+			// Цитата с пометкой [!callout] становится выноской; первое слово после пометки - значок выноски
+			if body, ok := strings.CutPrefix(text, calloutMarker); ok {
+				emoji, body, _ := strings.Cut(strings.TrimSpace(body), " ")
+				blocks = append(blocks, textCallout(emoji, body))
+				break
+			}
 			blocks = append(blocks, notionapi.QuoteBlock{
 				Quote: notionapi.Quote{
 					RichText: []notionapi.RichText{
@@ -0,0 +1,118 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+)
+
+// Адреса OAuth Notion
+const (
+	oauthAuthorizeURL = "https://api.notion.com/v1/oauth/authorize"
+	oauthTokenURL     = "https://api.notion.com/v1/oauth/token"
+)
+
+// oauthRequestTimeout - ограничение времени обмена кода на токен
+const oauthRequestTimeout = 30 * time.Second
+
+// OAuthService подключает Notion пользователя через публичную интеграцию: пользователь разрешает доступ
+// на странице Notion, а Notion возвращает его на redirectURL с кодом, который обменивается на токен
+type OAuthService struct {
+	clientID     string
+	clientSecret func() string
+	redirectURL  string
+	client       *http.Client
+	logger       *logger.Logger
+}
+
+// NewOAuthService создает сервис OAuth Notion. clientSecret вызывается перед каждым обменом кода,
+// поэтому ротация секрета подхватывается без перезапуска
+func NewOAuthService(clientID string, clientSecret func() string, redirectURL string, logger *logger.Logger) *OAuthService {
+	return &OAuthService{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		client:       &http.Client{Timeout: oauthRequestTimeout},
+		logger:       logger,
+	}
+}
+
+// AuthorizeURL возвращает адрес страницы Notion, на которой пользователь разрешает доступ к своему
+// рабочему пространству
+func (s *OAuthService) AuthorizeURL(state string) string {
+	query := url.Values{}
+	query.Set("client_id", s.clientID)
+	query.Set("response_type", "code")
+	query.Set("owner", "user")
+	query.Set("redirect_uri", s.redirectURL)
+	query.Set("state", state)
+	return oauthAuthorizeURL + "?" + query.Encode()
+}
+
+// oauthTokenResponse - ответ Notion на обмен кода
+type oauthTokenResponse struct {
+	AccessToken   string `json:"access_token"`
+	WorkspaceName string `json:"workspace_name"`
+	Error         string `json:"error"`
+	ErrorMessage  string `json:"error_description"`
+}
+
+// ExchangeCode обменивает код из обратного вызова на токен доступа и возвращает токен и название
+// рабочего пространства
+func (s *OAuthService) ExchangeCode(ctx context.Context, code string) (string, string, error) {
+	body, err := json.Marshal(map[string]string{
+		"grant_type":   "authorization_code",
+		"code":         code,
+		"redirect_uri": s.redirectURL,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal notion oauth request: %w", err)
+	}
+
+	var token oauthTokenResponse
+	err = metrics.Track(ctx, s.logger, metrics.ProviderNotion, "oauth_token", func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, oauthTokenURL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(s.clientID, s.clientSecret())
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &token); err != nil {
+			return fmt.Errorf("unexpected response with status code %d", resp.StatusCode)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status code %d: %s %s", resp.StatusCode, token.Error, token.ErrorMessage)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to exchange Notion OAuth code",
+			"error", err,
+		)
+		return "", "", fmt.Errorf("failed to exchange notion oauth code: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", "", fmt.Errorf("failed to exchange notion oauth code: empty access token")
+	}
+
+	return token.AccessToken, token.WorkspaceName, nil
+}
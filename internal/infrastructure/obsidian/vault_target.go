@@ -0,0 +1,109 @@
+package obsidian
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// fileNameReplacer убирает из названия заметки символы, недопустимые в именах файлов и ссылках Obsidian
+var fileNameReplacer = strings.NewReplacer(
+	"/", "-", `\`, "-", ":", "-", "*", "", "?", "", `"`, "", "<", "", ">", "", "|", "-",
+	"#", "", "^", "", "[", "(", "]", ")", "\n", " ", "\r", "",
+)
+
+// maxNoteTitleLength - ограничение длины названия в имени заметки в символах
+const maxNoteTitleLength = 80
+
+// VaultTarget выгружает результаты задач Markdown-заметками в хранилище Obsidian.
+// Заметки каждого пользователя складываются в отдельную папку по его Telegram ID
+type VaultTarget struct {
+	dir    string
+	logger *logger.Logger
+}
+
+// NewVaultTarget создает место синхронизации в хранилище Obsidian в каталоге dir
+func NewVaultTarget(dir string, logger *logger.Logger) *VaultTarget {
+	return &VaultTarget{
+		dir:    dir,
+		logger: logger,
+	}
+}
+
+// Name возвращает имя места синхронизации
+func (t *VaultTarget) Name() string {
+	return "obsidian"
+}
+
+// Enabled сообщает, что хранилище доступно всем пользователям
+func (t *VaultTarget) Enabled(user *entity.User) bool {
+	return true
+}
+
+// Sync записывает заметку задачи на языке из контекста и возвращает путь к ней относительно хранилища
+func (t *VaultTarget) Sync(ctx context.Context, user *entity.User, job *entity.Job) (string, error) {
+	userDir := fmt.Sprintf("user_%d", user.TelegramID)
+	if err := os.MkdirAll(filepath.Join(t.dir, userDir), 0755); err != nil {
+		return "", fmt.Errorf("failed to create vault directory: %w", err)
+	}
+
+	relPath := filepath.Join(userDir, noteName(ctx, job)+".md")
+
+	// Запись через временный файл, чтобы синхронизация хранилища не подхватила недописанную заметку
+	tmpPath := filepath.Join(t.dir, relPath+".tmp")
+	if err := os.WriteFile(tmpPath, []byte(noteContent(ctx, job)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write note: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(t.dir, relPath)); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write note: %w", err)
+	}
+
+	// Логирование успешной записи заметки
+	t.logger.Info("Note written to Obsidian vault",
+		"job_id", job.ID,
+		"path", relPath,
+	)
+
+	return relPath, nil
+}
+
+// noteName возвращает имя заметки: дату и ID задачи, для выпусков подкастов и видео - их название.
+// ID задачи в имени исключает совпадение имен заметок
+func noteName(ctx context.Context, job *entity.Job) string {
+	title := i18n.T(ctx, "obsidian.note.untitled")
+	if (job.Type == entity.JobTypePodcastEpisode || job.Type.IsMediaLink()) && job.FileName != "" {
+		title = job.FileName
+	}
+
+	title = fileNameReplacer.Replace(title)
+	if runes := []rune(title); len(runes) > maxNoteTitleLength {
+		title = string(runes[:maxNoteTitleLength])
+	}
+
+	return fmt.Sprintf("%s %s (%d)", job.CreatedAt.Format("2006-01-02 15-04"), strings.TrimSpace(title), job.ID)
+}
+
+// noteContent формирует заметку: свойства задачи во frontmatter, конспект и полную транскрипцию
+func noteContent(ctx context.Context, job *entity.Job) string {
+	var builder strings.Builder
+	builder.WriteString("---\n")
+	builder.WriteString(fmt.Sprintf("job_id: %d\n", job.ID))
+	builder.WriteString(fmt.Sprintf("created: %s\n", job.CreatedAt.Format("2006-01-02T15:04:05")))
+	builder.WriteString(fmt.Sprintf("type: %s\n", job.Type))
+	builder.WriteString("tags:\n  - transcription\n")
+	builder.WriteString("---\n\n")
+	builder.WriteString("## " + i18n.T(ctx, "obsidian.note.summary") + "\n\n")
+	builder.WriteString(job.Summary)
+	builder.WriteString("\n\n## " + i18n.T(ctx, "obsidian.note.transcription") + "\n\n")
+	builder.WriteString(job.Transcription)
+	builder.WriteString("\n")
+
+	return builder.String()
+}
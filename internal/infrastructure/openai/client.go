@@ -0,0 +1,35 @@
+package openai
+
+import (
+	"sync"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// clientCache выдает клиент OpenAI для текущего ключа API и пересоздает его после ротации ключа
+type clientCache struct {
+	apiKey func() string
+
+	mu     sync.Mutex
+	key    string
+	client *openai.Client
+}
+
+// newClientCache создает кэш клиента; apiKey вызывается перед каждым обращением к API
+func newClientCache(apiKey func() string) *clientCache {
+	return &clientCache{apiKey: apiKey}
+}
+
+// get возвращает клиент OpenAI с текущим ключом API
+func (c *clientCache) get() *openai.Client {
+	key := c.apiKey()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == nil || c.key != key {
+		c.client = openai.NewClient(key)
+		c.key = key
+	}
+	return c.client
+}
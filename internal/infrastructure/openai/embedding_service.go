@@ -0,0 +1,82 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// defaultEmbeddingModel - модель эмбеддингов по умолчанию
+const defaultEmbeddingModel = "text-embedding-3-small"
+
+// EmbeddingService представляет собой сервис для получения эмбеддингов текста с использованием OpenAI API
+type EmbeddingService struct {
+	clients *clientCache
+	logger  *logger.Logger
+	model   string
+}
+
+// NewEmbeddingService создает новый сервис для получения эмбеддингов текста
+func NewEmbeddingService(apiKey func() string, model string, logger *logger.Logger) *EmbeddingService {
+	// Если модель не указана, используем text-embedding-3-small
+	if model == "" {
+		model = defaultEmbeddingModel
+	}
+
+	// Клиент OpenAI создается для текущего ключа API и пересоздается после его ротации
+	clients := newClientCache(apiKey)
+
+	return &EmbeddingService{
+		clients: clients,
+		logger:  logger,
+		model:   model,
+	}
+}
+
+// Embed возвращает эмбеддинги для списка текстов в том же порядке
+func (s *EmbeddingService) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	// Логирование начала получения эмбеддингов
+	s.logger.Info("Creating embeddings",
+		"texts", len(texts),
+		"model", s.model,
+	)
+
+	// Создание запроса на получение эмбеддингов
+	req := openai.EmbeddingRequest{
+		Input: texts,
+		Model: openai.EmbeddingModel(s.model),
+	}
+
+	// Выполнение запроса
+	var resp openai.EmbeddingResponse
+	err := metrics.Track(ctx, s.logger, metrics.ProviderOpenAI, "embeddings", func(ctx context.Context) error {
+		var err error
+		resp, err = s.clients.get().CreateEmbeddings(ctx, req)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to create embeddings",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to create embeddings: %w", err)
+	}
+
+	// Проверка количества эмбеддингов
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("unexpected number of embeddings: got %d, want %d", len(resp.Data), len(texts))
+	}
+
+	// Упорядочивание эмбеддингов по индексу входного текста
+	embeddings := make([][]float32, len(texts))
+	for _, item := range resp.Data {
+		if item.Index < 0 || item.Index >= len(embeddings) {
+			return nil, fmt.Errorf("unexpected embedding index: %d", item.Index)
+		}
+		embeddings[item.Index] = item.Embedding
+	}
+
+	return embeddings, nil
+}
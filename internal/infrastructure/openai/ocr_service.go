@@ -0,0 +1,111 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// defaultOCRModel - модель с поддержкой изображений для распознавания текста по умолчанию
+const defaultOCRModel = "gpt-4o-mini"
+
+// ocrNoText - ответ модели, если на изображении нет текста
+const ocrNoText = "NO_TEXT"
+
+// ocrPrompt - инструкция для распознавания текста на фотографии
+const ocrPrompt = "Распознай весь текст на изображении (доска, слайд, записи, документ) и верни его как есть, " +
+	"сохраняя переносы строк, списки и порядок чтения. Не добавляй пояснений и не переводи текст. " +
+	"Если текста нет, ответь " + ocrNoText + "."
+
+// OCRService представляет собой сервис распознавания текста на изображениях с использованием OpenAI API
+type OCRService struct {
+	clients *clientCache
+	logger  *logger.Logger
+	model   string
+}
+
+// NewOCRService создает новый сервис распознавания текста на изображениях
+func NewOCRService(apiKey func() string, model string, logger *logger.Logger) *OCRService {
+	// Если модель не указана, используем gpt-4o-mini
+	if model == "" {
+		model = defaultOCRModel
+	}
+
+	// Клиент OpenAI создается для текущего ключа API и пересоздается после его ротации
+	clients := newClientCache(apiKey)
+
+	return &OCRService{
+		clients: clients,
+		logger:  logger,
+		model:   model,
+	}
+}
+
+// RecognizeText возвращает текст, распознанный на изображении (пустой, если текста нет)
+func (s *OCRService) RecognizeText(ctx context.Context, image []byte, contentType string) (string, error) {
+	// Логирование начала распознавания
+	s.logger.Info("Recognizing text on image",
+		"image_size", len(image),
+		"model", s.model,
+	)
+
+	// Изображение передается в запросе как data URL, чтобы не публиковать его до распознавания
+	dataURL := fmt.Sprintf("data:%s;base64,%s", contentType, base64.StdEncoding.EncodeToString(image))
+	req := openai.ChatCompletionRequest{
+		Model: s.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role: openai.ChatMessageRoleUser,
+				MultiContent: []openai.ChatMessagePart{
+					{
+						Type: openai.ChatMessagePartTypeText,
+						Text: ocrPrompt,
+					},
+					{
+						Type: openai.ChatMessagePartTypeImageURL,
+						ImageURL: &openai.ChatMessageImageURL{
+							URL:    dataURL,
+							Detail: openai.ImageURLDetailHigh,
+						},
+					},
+				},
+			},
+		},
+		Temperature: 0,
+	}
+
+	// Выполнение запроса
+	var resp openai.ChatCompletionResponse
+	err := metrics.Track(ctx, s.logger, metrics.ProviderOpenAI, "ocr", func(ctx context.Context) error {
+		var err error
+		resp, err = s.clients.get().CreateChatCompletion(ctx, req)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to recognize text on image",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to recognize text on image: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("empty OCR response")
+	}
+
+	text := strings.TrimSpace(resp.Choices[0].Message.Content)
+	if text == ocrNoText {
+		text = ""
+	}
+
+	// Логирование успешного распознавания
+	s.logger.Info("Text recognized on image",
+		"text_length", len(text),
+	)
+
+	return text, nil
+}
@@ -0,0 +1,90 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Модель и голос синтеза речи по умолчанию
+const (
+	defaultSpeechModel = "tts-1"
+	defaultSpeechVoice = "alloy"
+)
+
+// SpeechService представляет собой сервис синтеза речи с использованием OpenAI API
+type SpeechService struct {
+	clients *clientCache
+	logger  *logger.Logger
+	model   string
+	voice   string
+}
+
+// NewSpeechService создает новый сервис синтеза речи
+func NewSpeechService(apiKey func() string, model, voice string, logger *logger.Logger) *SpeechService {
+	// Если модель или голос не указаны, используем tts-1 и alloy
+	if model == "" {
+		model = defaultSpeechModel
+	}
+	if voice == "" {
+		voice = defaultSpeechVoice
+	}
+
+	// Клиент OpenAI создается для текущего ключа API и пересоздается после его ротации
+	clients := newClientCache(apiKey)
+
+	return &SpeechService{
+		clients: clients,
+		logger:  logger,
+		model:   model,
+		voice:   voice,
+	}
+}
+
+// Synthesize озвучивает текст и возвращает аудио в формате OGG/Opus, который Telegram показывает
+// как голосовое сообщение. Текст не должен превышать service.MaxSpeechTextLength символов
+func (s *SpeechService) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	// Логирование начала синтеза
+	s.logger.Info("Synthesizing speech",
+		"text_length", len(text),
+		"model", s.model,
+		"voice", s.voice,
+	)
+
+	req := openai.CreateSpeechRequest{
+		Model:          openai.SpeechModel(s.model),
+		Input:          text,
+		Voice:          openai.SpeechVoice(s.voice),
+		ResponseFormat: openai.SpeechResponseFormatOpus,
+	}
+
+	// Выполнение запроса; ответ читается целиком внутри замера, чтобы учесть время загрузки аудио
+	var audio []byte
+	err := metrics.Track(ctx, s.logger, metrics.ProviderOpenAI, "speech", func(ctx context.Context) error {
+		body, err := s.clients.get().CreateSpeech(ctx, req)
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+
+		audio, err = io.ReadAll(body)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to synthesize speech",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to synthesize speech: %w", err)
+	}
+
+	// Логирование успешного синтеза
+	s.logger.Info("Speech synthesized",
+		"audio_size", len(audio),
+	)
+
+	return audio, nil
+}
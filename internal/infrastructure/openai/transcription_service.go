@@ -4,32 +4,49 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
 	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
 	openai "github.com/sashabaranov/go-openai"
 )
 
 // TranscriptionService представляет собой сервис для транскрибации аудио с использованием OpenAI Whisper API
 type TranscriptionService struct {
-	client *openai.Client
-	logger *logger.Logger
-	model  string
+	clients  *clientCache
+	logger   *logger.Logger
+	model    string
+	defaults entity.TranscriptionOptions
 }
 
-// NewTranscriptionService создает новый сервис для транскрибации аудио
-func NewTranscriptionService(apiKey string, model string, logger *logger.Logger) *TranscriptionService {
+// NewTranscriptionService создает новый сервис для транскрибации аудио.
+// defaults задает параметры декодирования, которые пользователь может переопределить
+func NewTranscriptionService(apiKey func() string, model string, defaults entity.TranscriptionOptions, logger *logger.Logger) *TranscriptionService {
 	// Если модель не указана, используем whisper-1
 	if model == "" {
 		model = openai.Whisper1
 	}
 
-	// Создание клиента OpenAI
-	client := openai.NewClient(apiKey)
+	// Клиент OpenAI создается для текущего ключа API и пересоздается после его ротации
+	clients := newClientCache(apiKey)
+
+	// По умолчанию декодируем без случайности: с ненулевой температурой Whisper
+	// чаще "дописывает" несуществующий текст на тихих записях
+	if defaults.Temperature == nil {
+		temperature := float32(0)
+		defaults.Temperature = &temperature
+	}
+	if defaults.Granularity == "" {
+		defaults.Granularity = entity.TranscriptionGranularityText
+	}
 
 	return &TranscriptionService{
-		client: client,
-		logger: logger,
-		model:  model,
+		clients:  clients,
+		logger:   logger,
+		model:    model,
+		defaults: defaults,
 	}
 }
 
@@ -58,7 +75,12 @@ func (s *TranscriptionService) TranscribeAudio(ctx context.Context, audioPath st
 	}
 
 	// Выполнение запроса
-	resp, err := s.client.CreateTranscription(ctx, req)
+	var resp openai.AudioResponse
+	err = metrics.Track(ctx, s.logger, metrics.ProviderWhisper, "transcription", func(ctx context.Context) error {
+		var err error
+		resp, err = s.clients.get().CreateTranscription(ctx, req)
+		return err
+	})
 	if err != nil {
 		s.logger.Error("Failed to transcribe audio",
 			"error", err,
@@ -76,7 +98,119 @@ func (s *TranscriptionService) TranscribeAudio(ctx context.Context, audioPath st
 
 // Transcribe performs default transcription using Whisper
 func (s *TranscriptionService) Transcribe(ctx context.Context, audioFilePath string) (string, error) {
-	return s.TranscribeAudio(ctx, audioFilePath, "")
+	return s.TranscribeWithOptions(ctx, audioFilePath, entity.TranscriptionOptions{})
+}
+
+// TranscribeWithOptions транскрибирует аудио файл с параметрами декодирования,
+// переопределяющими значения по умолчанию из конфигурации
+func (s *TranscriptionService) TranscribeWithOptions(ctx context.Context, audioFilePath string, overrides entity.TranscriptionOptions) (string, error) {
+	opts := s.defaults.Merge(overrides)
+
+	// Логирование начала транскрибации
+	s.logger.Info("Transcribing audio with options",
+		"path", audioFilePath,
+		"model", s.model,
+		"temperature", *opts.Temperature,
+		"prompt_length", len(opts.Prompt),
+		"granularity", opts.Granularity,
+		"language", opts.Language,
+	)
+
+	resp, err := s.request(ctx, audioFilePath, opts)
+	if err != nil {
+		s.logger.Error("Failed to transcribe audio with options",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to transcribe audio with options: %w", err)
+	}
+
+	text := resp.Text
+	if opts.Granularity == entity.TranscriptionGranularitySegment && len(resp.Segments) > 0 {
+		text = formatSegments(resp)
+	}
+
+	// Логирование успешной транскрибации
+	s.logger.Info("Audio transcribed with options successfully",
+		"text_length", len(text),
+	)
+
+	return text, nil
+}
+
+// TranscribeWithSpeakers транскрибирует аудио файл по сегментам. Whisper не различает говорящих,
+// поэтому сегменты возвращаются без них
+func (s *TranscriptionService) TranscribeWithSpeakers(ctx context.Context, audioFilePath string, overrides entity.TranscriptionOptions) ([]entity.SpeakerSegment, error) {
+	opts := s.defaults.Merge(overrides)
+	opts.Granularity = entity.TranscriptionGranularitySegment
+
+	// Логирование начала транскрибации
+	s.logger.Info("Transcribing audio by segments",
+		"path", audioFilePath,
+		"model", s.model,
+		"language", opts.Language,
+	)
+
+	resp, err := s.request(ctx, audioFilePath, opts)
+	if err != nil {
+		s.logger.Error("Failed to transcribe audio by segments",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to transcribe audio by segments: %w", err)
+	}
+
+	segments := make([]entity.SpeakerSegment, 0, len(resp.Segments))
+	for _, segment := range resp.Segments {
+		segments = append(segments, entity.SpeakerSegment{
+			Start: segment.Start,
+			End:   segment.End,
+			Text:  strings.TrimSpace(segment.Text),
+		})
+	}
+	if len(segments) == 0 && strings.TrimSpace(resp.Text) != "" {
+		segments = append(segments, entity.SpeakerSegment{Text: strings.TrimSpace(resp.Text)})
+	}
+
+	// Логирование успешной транскрибации
+	s.logger.Info("Audio transcribed by segments successfully",
+		"segments", len(segments),
+	)
+
+	return segments, nil
+}
+
+// request отправляет запись в Whisper с параметрами декодирования; с детализацией по сегментам
+// ответ содержит сегменты с метками времени
+func (s *TranscriptionService) request(ctx context.Context, audioFilePath string, opts entity.TranscriptionOptions) (openai.AudioResponse, error) {
+	// Создание запроса на транскрибацию
+	req := openai.AudioRequest{
+		Model:       s.model,
+		FilePath:    audioFilePath,
+		Prompt:      opts.Prompt,
+		Temperature: *opts.Temperature,
+		Language:    opts.Language,
+		Format:      openai.AudioResponseFormatText,
+	}
+	if opts.Granularity == entity.TranscriptionGranularitySegment {
+		req.Format = openai.AudioResponseFormatVerboseJSON
+	}
+
+	// Выполнение запроса
+	var resp openai.AudioResponse
+	err := metrics.Track(ctx, s.logger, metrics.ProviderWhisper, "transcription", func(ctx context.Context) error {
+		var err error
+		resp, err = s.clients.get().CreateTranscription(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// formatSegments собирает текст из сегментов ответа, начиная каждый с метки времени [мм:сс]
+func formatSegments(resp openai.AudioResponse) string {
+	segments := make([]textutil.Segment, 0, len(resp.Segments))
+	for _, segment := range resp.Segments {
+		segments = append(segments, textutil.Segment{Start: segment.Start, Text: segment.Text})
+	}
+	return textutil.FormatSegments(segments)
 }
 
 // TranscribeAudioWithTimestamps транскрибирует аудио файл с временными метками
@@ -104,7 +238,12 @@ func (s *TranscriptionService) TranscribeAudioWithTimestamps(ctx context.Context
 	}
 
 	// Выполнение запроса
-	resp, err := s.client.CreateTranscription(ctx, req)
+	var resp openai.AudioResponse
+	err = metrics.Track(ctx, s.logger, metrics.ProviderWhisper, "transcription", func(ctx context.Context) error {
+		var err error
+		resp, err = s.clients.get().CreateTranscription(ctx, req)
+		return err
+	})
 	if err != nil {
 		s.logger.Error("Failed to transcribe audio with timestamps",
 			"error", err,
@@ -145,7 +284,12 @@ func (s *TranscriptionService) TranscribeAudioWithVTT(ctx context.Context, audio
 	}
 
 	// Выполнение запроса
-	resp, err := s.client.CreateTranscription(ctx, req)
+	var resp openai.AudioResponse
+	err = metrics.Track(ctx, s.logger, metrics.ProviderWhisper, "transcription", func(ctx context.Context) error {
+		var err error
+		resp, err = s.clients.get().CreateTranscription(ctx, req)
+		return err
+	})
 	if err != nil {
 		s.logger.Error("Failed to transcribe audio with VTT format",
 			"error", err,
@@ -186,7 +330,12 @@ func (s *TranscriptionService) TranscribeAudioWithVerbose(ctx context.Context, a
 	}
 
 	// Выполнение запроса
-	resp, err := s.client.CreateTranscription(ctx, req)
+	var resp openai.AudioResponse
+	err = metrics.Track(ctx, s.logger, metrics.ProviderWhisper, "transcription", func(ctx context.Context) error {
+		var err error
+		resp, err = s.clients.get().CreateTranscription(ctx, req)
+		return err
+	})
 	if err != nil {
 		s.logger.Error("Failed to transcribe audio with verbose output",
 			"error", err,
@@ -201,3 +350,11 @@ func (s *TranscriptionService) TranscribeAudioWithVerbose(ctx context.Context, a
 
 	return resp.Text, nil
 }
+
+// Ping проверяет, что OpenAI API принимает текущий ключ: запрашивает список моделей, что не расходует токены
+func (s *TranscriptionService) Ping(ctx context.Context) error {
+	if _, err := s.clients.get().ListModels(ctx); err != nil {
+		return fmt.Errorf("failed to list OpenAI models: %w", err)
+	}
+	return nil
+}
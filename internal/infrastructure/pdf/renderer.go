@@ -0,0 +1,248 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	"github.com/112Alex/project_obsidian/pkg/tempfs"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
+)
+
+// Ограничения отрисовки документа
+const (
+	renderTimeout = 2 * time.Minute
+	// pageReservation - резерв места во временном каталоге под страницу HTML и готовый PDF
+	pageReservation = 32 << 20
+)
+
+// boldPattern находит выделение **текст** в кратком содержании
+var boldPattern = regexp.MustCompile(`\*\*(.+?)\*\*`)
+
+// Renderer представляет собой сервис выгрузки результатов задач в PDF: документ верстается страницей HTML
+// и печатается в PDF через wkhtmltopdf
+type Renderer struct {
+	binaryPath string
+	tempSpace  *tempfs.Space
+	logger     *logger.Logger
+}
+
+// NewRenderer создает новый сервис выгрузки в PDF
+func NewRenderer(binaryPath string, tempSpace *tempfs.Space, logger *logger.Logger) *Renderer {
+	return &Renderer{
+		binaryPath: binaryPath,
+		tempSpace:  tempSpace,
+		logger:     logger,
+	}
+}
+
+// summaryBlock представляет собой абзац, заголовок или пункт списка краткого содержания
+type summaryBlock struct {
+	Heading  bool
+	ListItem bool
+	Text     template.HTML
+}
+
+// transcriptLine представляет собой строку транскрипции с меткой времени
+type transcriptLine struct {
+	Timestamp string
+	Text      string
+}
+
+// page содержит данные шаблона документа
+type page struct {
+	Lang              string
+	Title             string
+	Metadata          []entity.DocumentField
+	SummaryHeading    string
+	Summary           []summaryBlock
+	TranscriptHeading string
+	Transcript        []transcriptLine
+	Generated         string
+}
+
+// RenderJob верстает документ с результатом задачи и возвращает содержимое PDF
+func (r *Renderer) RenderJob(ctx context.Context, document *entity.JobDocument) ([]byte, error) {
+	// Логирование начала выгрузки в PDF
+	r.logger.Info("Rendering job document to PDF",
+		"title", document.Title,
+		"segments", len(document.Transcript),
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, renderTimeout)
+	defer cancel()
+
+	// Резервирование места во временном каталоге на время печати
+	release, err := r.tempSpace.Reserve(ctx, pageReservation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve temp space: %w", err)
+	}
+	defer release()
+
+	// wkhtmltopdf определяет формат входного файла по расширению, поэтому страница сохраняется как .html
+	htmlFile, err := r.tempSpace.CreateTemp("document_*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(htmlFile.Name())
+
+	err = documentTemplate.Execute(htmlFile, newPage(document))
+	if closeErr := htmlFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write document page: %w", err)
+	}
+
+	pdfFile, err := r.tempSpace.CreateTemp("document_*.pdf")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	pdfFile.Close()
+	defer os.Remove(pdfFile.Name())
+
+	err = metrics.Track(ctx, r.logger, metrics.ProviderPDF, "render", func(ctx context.Context) error {
+		cmd := exec.CommandContext(
+			ctx,
+			r.binaryPath,
+			"--quiet",
+			"--encoding", "utf-8",
+			"--page-size", "A4",
+			"--margin-top", "18mm",
+			"--margin-bottom", "18mm",
+			"--margin-left", "16mm",
+			"--margin-right", "16mm",
+			"--footer-center", "[page] / [topage]",
+			"--footer-font-size", "8",
+			"--disable-javascript",
+			htmlFile.Name(),
+			pdfFile.Name(),
+		)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to render PDF",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+
+	data, err := os.ReadFile(pdfFile.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	// Логирование успешной выгрузки в PDF
+	r.logger.Info("Job document rendered successfully",
+		"size", len(data),
+	)
+
+	return data, nil
+}
+
+// newPage готовит данные шаблона документа: размечает краткое содержание, форматирует метки времени
+// и подписывает разделы на языке документа
+func newPage(document *entity.JobDocument) page {
+	language := i18n.Normalize(document.Language)
+	p := page{
+		Lang:              language,
+		Title:             document.Title,
+		Metadata:          document.Metadata,
+		SummaryHeading:    i18n.Translate(language, "pdf.summary"),
+		Summary:           summaryBlocks(document.Summary),
+		TranscriptHeading: i18n.Translate(language, "pdf.transcription"),
+		Transcript:        make([]transcriptLine, 0, len(document.Transcript)),
+		Generated:         i18n.Translate(language, "pdf.generated", time.Now().Format("02.01.2006 15:04")),
+	}
+
+	for _, segment := range document.Transcript {
+		line := transcriptLine{Text: segment.Text}
+		if segment.StartSeconds != nil {
+			line.Timestamp = textutil.FormatTimestamp(*segment.StartSeconds)
+		}
+		p.Transcript = append(p.Transcript, line)
+	}
+
+	return p
+}
+
+// summaryBlocks разбивает краткое содержание в Markdown на заголовки, пункты списков и абзацы
+func summaryBlocks(summary string) []summaryBlock {
+	blocks := make([]summaryBlock, 0)
+	for _, line := range strings.Split(summary, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		block := summaryBlock{}
+		switch {
+		case strings.HasPrefix(line, "#"):
+			block.Heading = true
+			line = strings.TrimSpace(strings.TrimLeft(line, "#"))
+		case strings.HasPrefix(line, "- "), strings.HasPrefix(line, "* "), strings.HasPrefix(line, "• "):
+			block.ListItem = true
+			_, line, _ = strings.Cut(line, " ")
+		}
+
+		// Текст экранируется до разметки выделения, поэтому вставлять его в шаблон как HTML безопасно
+		escaped := template.HTMLEscapeString(strings.TrimSpace(line))
+		block.Text = template.HTML(boldPattern.ReplaceAllString(escaped, "<strong>$1</strong>"))
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// documentTemplate - шаблон документа с результатом задачи
+var documentTemplate = template.Must(template.New("document").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: "DejaVu Sans", "Noto Sans", Arial, sans-serif; font-size: 11pt; line-height: 1.5; color: #222; }
+.brand { color: #6b4fbb; font-size: 9pt; font-weight: bold; letter-spacing: 0.15em; text-transform: uppercase; border-bottom: 2px solid #6b4fbb; padding-bottom: 4pt; }
+h1 { font-size: 20pt; margin: 14pt 0 8pt; }
+h2 { font-size: 14pt; color: #6b4fbb; margin: 18pt 0 6pt; page-break-after: avoid; }
+h3 { font-size: 12pt; margin: 10pt 0 4pt; page-break-after: avoid; }
+table.meta { border-collapse: collapse; font-size: 9.5pt; color: #555; }
+table.meta td { padding: 1pt 12pt 1pt 0; vertical-align: top; }
+table.meta td.name { color: #888; }
+p { margin: 0 0 6pt; }
+ul { margin: 0 0 6pt; padding-left: 16pt; }
+.line { margin: 0 0 4pt; page-break-inside: avoid; }
+.time { display: inline-block; width: 52pt; color: #6b4fbb; font-family: "DejaVu Sans Mono", monospace; font-size: 9pt; }
+.footer { margin-top: 18pt; color: #999; font-size: 8pt; }
+</style>
+</head>
+<body>
+<div class="brand">Project Obsidian</div>
+<h1>{{.Title}}</h1>
+{{if .Metadata}}<table class="meta">
+{{range .Metadata}}<tr><td class="name">{{.Name}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Summary}}<h2>{{.SummaryHeading}}</h2>
+{{range .Summary}}{{if .Heading}}<h3>{{.Text}}</h3>
+{{else if .ListItem}}<ul><li>{{.Text}}</li></ul>
+{{else}}<p>{{.Text}}</p>
+{{end}}{{end}}{{end}}
+{{if .Transcript}}<h2>{{.TranscriptHeading}}</h2>
+{{range .Transcript}}<div class="line">{{if .Timestamp}}<span class="time">{{.Timestamp}}</span>{{end}}{{.Text}}</div>
+{{end}}{{end}}
+<div class="footer">{{.Generated}}</div>
+</body>
+</html>
+`))
@@ -0,0 +1,251 @@
+package podcast
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	"github.com/112Alex/project_obsidian/pkg/safehttp"
+	"github.com/112Alex/project_obsidian/pkg/tempfs"
+)
+
+// Ограничения загрузки лент и выпусков
+const (
+	feedFetchTimeout       = 30 * time.Second
+	episodeDownloadTimeout = 15 * time.Minute
+	maxFeedSize            = 10 * 1024 * 1024
+)
+
+// rssFeed описывает нужные поля RSS-ленты подкаста
+type rssFeed struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+// rssItem описывает выпуск в RSS-ленте
+type rssItem struct {
+	Title     string `xml:"title"`
+	GUID      string `xml:"guid"`
+	PubDate   string `xml:"pubDate"`
+	Enclosure struct {
+		URL    string `xml:"url,attr"`
+		Type   string `xml:"type,attr"`
+		Length string `xml:"length,attr"`
+	} `xml:"enclosure"`
+}
+
+// FeedService представляет собой сервис для работы с RSS-лентами подкастов
+type FeedService struct {
+	client    *http.Client
+	maxSize   int64
+	tempSpace *tempfs.Space
+	logger    *logger.Logger
+}
+
+// NewFeedService создает новый сервис для работы с RSS-лентами подкастов. Ленты и аудио выпусков загружаются
+// по адресам, которые присылают пользователи, поэтому адреса внутренней сети недоступны.
+// maxSize - наибольший размер аудио выпуска в байтах
+func NewFeedService(maxSize int64, tempSpace *tempfs.Space, logger *logger.Logger) *FeedService {
+	return &FeedService{
+		// Длительность загрузки ограничивает контекст
+		client:    safehttp.NewClient(0),
+		maxSize:   maxSize,
+		tempSpace: tempSpace,
+		logger:    logger,
+	}
+}
+
+// FetchFeed загружает ленту и возвращает ее название и выпуски, новые первыми
+func (s *FeedService) FetchFeed(ctx context.Context, feedURL string) (string, []*entity.PodcastEpisode, error) {
+	// Логирование начала загрузки ленты
+	s.logger.Info("Fetching podcast feed",
+		"url", feedURL,
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, feedFetchTimeout)
+	defer cancel()
+
+	// Выполнение запроса
+	var feed rssFeed
+	err := metrics.Track(ctx, s.logger, metrics.ProviderPodcast, "fetch_feed", func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		return xml.NewDecoder(io.LimitReader(resp.Body, maxFeedSize)).Decode(&feed)
+	})
+	if err != nil {
+		s.logger.Error("Failed to fetch podcast feed",
+			"error", err,
+		)
+		return "", nil, fmt.Errorf("failed to fetch podcast feed: %w", err)
+	}
+
+	// Выпуски без аудио пропускаем
+	episodes := make([]*entity.PodcastEpisode, 0, len(feed.Channel.Items))
+	for _, item := range feed.Channel.Items {
+		if item.Enclosure.URL == "" {
+			continue
+		}
+
+		episode := &entity.PodcastEpisode{
+			GUID:     strings.TrimSpace(item.GUID),
+			Title:    strings.TrimSpace(item.Title),
+			AudioURL: item.Enclosure.URL,
+		}
+		if episode.GUID == "" {
+			episode.GUID = episode.AudioURL
+		}
+		if size, err := strconv.ParseInt(item.Enclosure.Length, 10, 64); err == nil {
+			episode.AudioSize = size
+		}
+		if publishedAt, ok := parsePubDate(item.PubDate); ok {
+			episode.PublishedAt = &publishedAt
+		}
+
+		episodes = append(episodes, episode)
+	}
+
+	// Ленты обычно уже отсортированы, но порядок не гарантирован
+	sort.SliceStable(episodes, func(i, j int) bool {
+		if episodes[i].PublishedAt == nil || episodes[j].PublishedAt == nil {
+			return false
+		}
+		return episodes[i].PublishedAt.After(*episodes[j].PublishedAt)
+	})
+
+	// Логирование успешной загрузки ленты
+	s.logger.Info("Podcast feed fetched successfully",
+		"url", feedURL,
+		"episodes", len(episodes),
+	)
+
+	return strings.TrimSpace(feed.Channel.Title), episodes, nil
+}
+
+// DownloadEpisode загружает аудио выпуска во временный каталог и возвращает путь к файлу
+func (s *FeedService) DownloadEpisode(ctx context.Context, userID int64, episode *entity.PodcastEpisode) (string, error) {
+	// Логирование начала загрузки выпуска
+	s.logger.Info("Downloading podcast episode",
+		"url", episode.AudioURL,
+		"size", episode.AudioSize,
+	)
+
+	// Размер из ленты указывает ее автор, поэтому он только позволяет отказаться от загрузки заранее
+	if episode.AudioSize > s.maxSize {
+		return "", fmt.Errorf("podcast episode is larger than %d bytes: %d bytes", s.maxSize, episode.AudioSize)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, episodeDownloadTimeout)
+	defer cancel()
+
+	// Создание директории для сохранения файлов пользователя
+	userDir := filepath.Join(s.tempSpace.Dir(), "uploads", fmt.Sprintf("user_%d", userID))
+	if err := os.MkdirAll(userDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create user directory: %w", err)
+	}
+
+	ext := path.Ext(strings.SplitN(episode.AudioURL, "?", 2)[0])
+	if ext == "" || len(ext) > 5 {
+		ext = ".mp3"
+	}
+	filePath := filepath.Join(userDir, fmt.Sprintf("podcast_%d%s", time.Now().UnixNano(), ext))
+
+	// Загрузка файла
+	err := metrics.Track(ctx, s.logger, metrics.ProviderPodcast, "download_episode", func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, episode.AudioURL, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		if resp.ContentLength > s.maxSize {
+			return fmt.Errorf("podcast episode is larger than %d bytes: %d bytes", s.maxSize, resp.ContentLength)
+		}
+
+		// Резервирование места во временном каталоге на время загрузки: без заявленного размера - под наибольший выпуск
+		reservation := resp.ContentLength
+		if reservation <= 0 {
+			reservation = s.maxSize
+		}
+		release, err := s.tempSpace.Reserve(ctx, reservation)
+		if err != nil {
+			return fmt.Errorf("failed to reserve temp space: %w", err)
+		}
+		defer release()
+
+		file, err := os.Create(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		// Размер проверяется и во время загрузки: сервер может не указать его или указать неверно
+		written, err := io.Copy(file, io.LimitReader(resp.Body, s.maxSize+1))
+		if err != nil {
+			return err
+		}
+		if written > s.maxSize {
+			return fmt.Errorf("podcast episode is larger than %d bytes", s.maxSize)
+		}
+		return nil
+	})
+	if err != nil {
+		os.Remove(filePath)
+		s.logger.Error("Failed to download podcast episode",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to download podcast episode: %w", err)
+	}
+
+	// Логирование успешной загрузки выпуска
+	s.logger.Info("Podcast episode downloaded successfully",
+		"path", filePath,
+	)
+
+	return filePath, nil
+}
+
+// parsePubDate разбирает дату публикации выпуска в распространенных форматах RSS
+func parsePubDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, time.RFC822Z, time.RFC822, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,163 @@
+package pyannote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+)
+
+// diarizePath - путь разделения записи по говорящим на сервере
+const diarizePath = "/diarize"
+
+// maxErrorBodySize - сколько байт ответа с ошибкой попадает в текст ошибки
+const maxErrorBodySize = 1024
+
+// DiarizationService представляет собой сервис разделения записей по говорящим, работающий рядом с ботом
+// на pyannote.audio. Сервер принимает запись полем file и возвращает промежутки с метками говорящих
+// в виде {"segments": [{"start": 0.5, "end": 3.2, "speaker": "SPEAKER_00"}]}
+type DiarizationService struct {
+	client  *http.Client
+	baseURL string
+	logger  *logger.Logger
+}
+
+// NewDiarizationService создает новый сервис разделения записей по говорящим
+func NewDiarizationService(baseURL string, logger *logger.Logger) *DiarizationService {
+	return &DiarizationService{
+		// Длительность разделения ограничивает контекст этапа распознавания
+		client:  &http.Client{},
+		baseURL: strings.TrimRight(baseURL, "/"),
+		logger:  logger,
+	}
+}
+
+// diarizeResponse - ответ сервера разделения
+type diarizeResponse struct {
+	Segments []struct {
+		Start   float64 `json:"start"`
+		End     float64 `json:"end"`
+		Speaker string  `json:"speaker"`
+	} `json:"segments"`
+}
+
+// Diarize возвращает промежутки записи с номерами говорящих. Метки pyannote (SPEAKER_00, SPEAKER_01, ...)
+// заменяются номерами с 1 в порядке первого появления говорящего в записи
+func (s *DiarizationService) Diarize(ctx context.Context, audioFilePath string) ([]entity.SpeakerTurn, error) {
+	// Логирование начала разделения
+	s.logger.Info("Diarizing audio",
+		"path", audioFilePath,
+	)
+
+	var resp diarizeResponse
+	err := metrics.Track(ctx, s.logger, metrics.ProviderPyannote, "diarization", func(ctx context.Context) error {
+		return s.post(ctx, audioFilePath, &resp)
+	})
+	if err != nil {
+		s.logger.Error("Failed to diarize audio",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to diarize audio: %w", err)
+	}
+
+	speakers := make(map[string]int)
+	turns := make([]entity.SpeakerTurn, 0, len(resp.Segments))
+	for _, segment := range resp.Segments {
+		speaker, ok := speakers[segment.Speaker]
+		if !ok {
+			speaker = len(speakers) + 1
+			speakers[segment.Speaker] = speaker
+		}
+		turns = append(turns, entity.SpeakerTurn{
+			Start:   segment.Start,
+			End:     segment.End,
+			Speaker: speaker,
+		})
+	}
+
+	// Логирование успешного разделения
+	s.logger.Info("Audio diarized successfully",
+		"turns", len(turns),
+		"speakers", len(speakers),
+	)
+
+	return turns, nil
+}
+
+// post отправляет запись на сервер разделения. Файл передается потоком, не загружаясь в память целиком
+func (s *DiarizationService) post(ctx context.Context, audioFilePath string, out *diarizeResponse) error {
+	audioFile, err := os.Open(audioFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer audioFile.Close()
+
+	pipeReader, pipeWriter := io.Pipe()
+	form := multipart.NewWriter(pipeWriter)
+	go func() {
+		pipeWriter.CloseWithError(writeForm(form, audioFile))
+	}()
+	defer pipeReader.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+diarizePath, pipeReader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fmt.Errorf("diarization server returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// writeForm записывает файл записи в запрос
+func writeForm(form *multipart.Writer, audioFile *os.File) error {
+	part, err := form.CreateFormFile("file", filepath.Base(audioFile.Name()))
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, audioFile); err != nil {
+		return fmt.Errorf("failed to write audio file: %w", err)
+	}
+	return form.Close()
+}
+
+// Ping проверяет, что сервер разделения отвечает: запрашивает корневую страницу, что не запускает разделение
+func (s *DiarizationService) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach diarization server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("diarization server returned status %d", resp.StatusCode)
+	}
+	return nil
+}
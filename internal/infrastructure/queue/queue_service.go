@@ -2,7 +2,11 @@ package queue
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/112Alex/project_obsidian/internal/domain/entity"
@@ -13,31 +17,126 @@ import (
 // DefaultQueueName - имя очереди по умолчанию
 const DefaultQueueName = "default"
 
+// PoisonQueueName - имя очереди для задач, обработчик которых аварийно завершается раз за разом.
+// Задачи из нее не обрабатываются автоматически и остаются для ручного разбора
+const PoisonQueueName = "poison"
+
+// defaultMaxPanicAttempts - число аварийных завершений, после которого задача изолируется
+const defaultMaxPanicAttempts = 3
+
 // QueueService представляет собой сервис для работы с очередью задач
 type QueueService struct {
-	queueRepo repository.QueueRepository
-	jobRepo   repository.JobRepository
-	logger    *logger.Logger
-	worker    *Worker
+	queueRepo        repository.QueueRepository
+	jobRepo          repository.JobRepository
+	maxPanicAttempts int
+	// inlineSlots ограничивает число задач, которые одновременно выполняются сразу, минуя очередь
+	inlineSlots chan struct{}
+	// paused сообщает, что задачи брать нельзя (nil - очередь не приостанавливается)
+	paused func() bool
+	logger *logger.Logger
+	worker *Worker
 }
 
-// NewQueueService создает новый сервис для работы с очередью задач
+// NewQueueService создает новый сервис для работы с очередью задач.
+// maxPanicAttempts - после скольких аварийных завершений обработчика задача переносится в очередь PoisonQueueName,
+// inlineConcurrency - сколько задач может одновременно выполняться сразу, минуя очередь (0 - ни одной)
 func NewQueueService(
 	queueRepo repository.QueueRepository,
 	jobRepo repository.JobRepository,
+	maxPanicAttempts int,
+	inlineConcurrency int,
 	logger *logger.Logger,
 ) *QueueService {
+	if maxPanicAttempts <= 0 {
+		maxPanicAttempts = defaultMaxPanicAttempts
+	}
+
 	s := &QueueService{
-		queueRepo: queueRepo,
-		jobRepo:   jobRepo,
-		logger:    logger,
+		queueRepo:        queueRepo,
+		jobRepo:          jobRepo,
+		maxPanicAttempts: maxPanicAttempts,
+		inlineSlots:      make(chan struct{}, max(inlineConcurrency, 0)),
+		logger:           logger,
 	}
 	s.worker = NewWorker(s, logger)
 	return s
 }
 
-// PushJob добавляет задачу в очередь
+// inlineRun - задачи, которые обработчики поставили в очередь во время выполнения задачи сразу.
+// Они выполняются той же горутиной по порядку после завершения текущего обработчика, как если бы
+// их по очереди извлек воркер
+type inlineRun struct {
+	mu      sync.Mutex
+	pending []entity.QueueJob
+}
+
+// inlineRunKey - ключ контекста, по которому обработчики задачи, выполняемой сразу, находят ее inlineRun
+type inlineRunKey struct{}
+
+// push откладывает задачу до завершения текущего обработчика
+func (r *inlineRun) push(job entity.QueueJob) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = append(r.pending, job)
+}
+
+// next возвращает следующую отложенную задачу
+func (r *inlineRun) next() (entity.QueueJob, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) == 0 {
+		return entity.QueueJob{}, false
+	}
+	job := r.pending[0]
+	r.pending = r.pending[1:]
+	return job, true
+}
+
+// RunNow выполняет задачу сразу в вызывающей горутине, минуя очередь, вместе со всеми последующими этапами,
+// которые ставят в очередь ее обработчики. Возвращает false, если все места для таких задач заняты или очередь
+// приостановлена: тогда задачу нужно поставить в очередь обычным образом. Ошибки обработчиков обрабатываются так же,
+// как в воркере: задача с аварийно завершившимся обработчиком возвращается в очередь
+func (s *QueueService) RunNow(ctx context.Context, job entity.QueueJob) bool {
+	if s.isPaused() {
+		return false
+	}
+
+	select {
+	case s.inlineSlots <- struct{}{}:
+	default:
+		return false
+	}
+	defer func() { <-s.inlineSlots }()
+
+	s.logger.Info("Running job bypassing queue",
+		"job_id", job.JobID,
+		"job_type", job.JobType,
+	)
+
+	run := &inlineRun{pending: []entity.QueueJob{job}}
+	ctx = context.WithValue(ctx, inlineRunKey{}, run)
+	for next, ok := run.next(); ok; next, ok = run.next() {
+		// Статус обновляется так же, как при извлечении задачи из очереди
+		if err := s.jobRepo.UpdateStatus(ctx, next.JobID, entity.JobStatusProcessing, ""); err != nil {
+			s.logger.Error("Failed to update job status",
+				"error", err,
+				"job_id", next.JobID,
+			)
+		}
+		s.worker.processJob(ctx, next)
+	}
+
+	return true
+}
+
+// PushJob добавляет задачу в очередь. Внутри задачи, выполняемой сразу (RunNow), задача не попадает
+// в очередь, а выполняется после завершения текущего обработчика
 func (s *QueueService) PushJob(ctx context.Context, job entity.QueueJob) error {
+	if run, ok := ctx.Value(inlineRunKey{}).(*inlineRun); ok {
+		run.push(job)
+		return nil
+	}
+
 	// Логирование начала добавления задачи
 	s.logger.Info("Pushing job to queue",
 		"job_id", job.JobID,
@@ -104,6 +203,60 @@ func (s *QueueService) PopJob(ctx context.Context, queueName string) (*entity.Qu
 	return job, nil
 }
 
+// handlePanickedJob помечает задачу, обработчик которой аварийно завершился, как проваленную
+// и возвращает ее в очередь для повторной попытки. После maxPanicAttempts попыток задача
+// переносится в очередь PoisonQueueName, чтобы она не роняла обработку снова и снова
+func (s *QueueService) handlePanickedJob(ctx context.Context, job entity.QueueJob, panicErr error) {
+	job.Attempts++
+	quarantined := job.Attempts >= s.maxPanicAttempts
+
+	errorMessage := panicErr.Error()
+	if quarantined {
+		errorMessage = fmt.Sprintf("job quarantined after %d crashes: %s", job.Attempts, errorMessage)
+	}
+
+	// Обновление статуса задачи в базе данных
+	err := s.jobRepo.UpdateStatus(ctx, job.JobID, entity.JobStatusFailed, errorMessage)
+	if err != nil {
+		s.logger.Error("Failed to update job status",
+			"error", err,
+			"job_id", job.JobID,
+		)
+	}
+
+	// Статус задачи уже выставлен, поэтому кладем ее в очередь напрямую, минуя PushJob
+	queueName := string(job.JobType)
+	if quarantined {
+		queueName = PoisonQueueName
+	}
+
+	err = s.queueRepo.Push(ctx, queueName, &job)
+	if err != nil {
+		s.logger.Error("Failed to requeue panicked job",
+			"error", err,
+			"job_id", job.JobID,
+			"queue", queueName,
+		)
+		return
+	}
+
+	if quarantined {
+		s.logger.Error("Job moved to poison queue",
+			"job_id", job.JobID,
+			"job_type", job.JobType,
+			"attempts", job.Attempts,
+		)
+		return
+	}
+
+	s.logger.Warn("Panicked job requeued",
+		"job_id", job.JobID,
+		"job_type", job.JobType,
+		"attempts", job.Attempts,
+		"max_attempts", s.maxPanicAttempts,
+	)
+}
+
 // GetQueueSize возвращает размер очереди
 func (s *QueueService) GetQueueSize(ctx context.Context) (int64, error) {
 	// Получение размера очереди для очереди по умолчанию
@@ -118,26 +271,27 @@ func (s *QueueService) GetQueueSize(ctx context.Context) (int64, error) {
 	return size, nil
 }
 
-// EnqueueTranscriptionJob добавляет задачу транскрибации в очередь
+// EnqueueTranscriptionJob добавляет задачу транскрибации в очередь. Путь к записи передается объектом
+// с полем audio_path: этап транскрибации читает его оттуда
 func (s *QueueService) EnqueueTranscriptionJob(ctx context.Context, jobID, userID int64, audioFilePath string) error {
 	job := entity.QueueJob{
 		JobID:     jobID,
 		UserID:    userID,
 		JobType:   entity.JobTypeTranscription,
 		CreatedAt: time.Now(),
-		Payload:   audioFilePath,
+		Payload:   map[string]interface{}{"audio_path": audioFilePath},
 	}
 	return s.PushJob(ctx, job)
 }
 
-// EnqueueSummarizationJob добавляет задачу суммаризации в очередь
-func (s *QueueService) EnqueueSummarizationJob(ctx context.Context, jobID, userID int64, transcription string) error {
+// EnqueueSummarizationJob добавляет задачу суммаризации в очередь.
+// Транскрипция должна быть сохранена в задаче: этап суммаризации читает ее из базы данных
+func (s *QueueService) EnqueueSummarizationJob(ctx context.Context, jobID, userID int64) error {
 	job := entity.QueueJob{
 		JobID:     jobID,
 		UserID:    userID,
 		JobType:   entity.JobTypeSummarization,
 		CreatedAt: time.Now(),
-		Payload:   transcription,
 	}
 	return s.PushJob(ctx, job)
 }
@@ -163,21 +317,57 @@ func (s *QueueService) RegisterHandler(jobType entity.JobType, handler func(ctx
 	s.worker.RegisterHandler(jobType, handler)
 }
 
-// StartWorker запускает обработчик задач из очереди
-func (s *QueueService) StartWorker(ctx context.Context) error {
+// RegisterPauseCheck регистрирует проверку, при которой воркер приостанавливает обработку очереди: текущая задача
+// дорабатывается, а следующие остаются в очереди, пока проверка не вернет false
+func (s *QueueService) RegisterPauseCheck(paused func() bool) {
+	s.paused = paused
+}
+
+// isPaused сообщает, что обработка очереди приостановлена
+func (s *QueueService) isPaused() bool {
+	return s.paused != nil && s.paused()
+}
+
+// RunWorker обрабатывает задачи из очереди до отмены контекста или остановки воркера.
+// Аварийное завершение цикла вне обработчика задачи не перехватывается: его перезапускает вызывающий
+func (s *QueueService) RunWorker(ctx context.Context) error {
 	if s.worker == nil {
 		s.worker = NewWorker(s, s.logger)
 	}
-	s.worker.Start(ctx)
+	s.worker.Run(ctx)
 	return nil
 }
 
+// WorkerState - состояние воркера очереди для самодиагностики
+type WorkerState struct {
+	Running      bool      // Запущен ли цикл воркера
+	Paused       bool      // Приостановлена ли обработка очереди
+	LastPoll     time.Time // Когда воркер последний раз обращался к очереди
+	JobID        int64     // Задача, которую воркер обрабатывает сейчас (0 - ни одной)
+	JobStartedAt time.Time // Когда началась обработка текущей задачи
+}
+
+// WorkerState возвращает состояние воркера очереди: по времени последнего обращения к очереди видно,
+// что воркер не остановился и не завис
+func (s *QueueService) WorkerState() WorkerState {
+	if s.worker == nil {
+		return WorkerState{}
+	}
+	return s.worker.state()
+}
+
 // Worker представляет собой воркер для обработки задач из очереди
 type Worker struct {
 	queueService *QueueService
 	handlers     map[entity.JobType]JobHandler
 	logger       *logger.Logger
 	shutdown     chan struct{}
+
+	// Состояние цикла воркера для самодиагностики; время хранится в наносекундах Unix
+	running      atomic.Bool
+	lastPoll     atomic.Int64
+	jobID        atomic.Int64
+	jobStartedAt atomic.Int64
 }
 
 // JobHandler представляет собой обработчик задачи
@@ -198,41 +388,66 @@ func (w *Worker) RegisterHandler(jobType entity.JobType, handler JobHandler) {
 	w.handlers[jobType] = handler
 }
 
-// Start запускает воркер
-func (w *Worker) Start(ctx context.Context) {
+// Run обрабатывает задачи до отмены контекста или остановки воркера
+func (w *Worker) Run(ctx context.Context) {
 	w.logger.Info("Starting worker")
 
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				w.logger.Info("Worker stopped due to context cancellation")
-				return
-			case <-w.shutdown:
-				w.logger.Info("Worker stopped due to shutdown signal")
-				return
-			default:
-				// Извлечение задачи из очереди для очереди по умолчанию
-				job, err := w.queueService.PopJob(ctx, DefaultQueueName)
-				if err != nil {
-					w.logger.Error("Failed to pop job from queue",
-						"error", err,
-					)
-					time.Sleep(1 * time.Second)
-					continue
-				}
-
-				// Если очередь пуста, ждем некоторое время
-				if job == nil {
-					time.Sleep(1 * time.Second)
-					continue
-				}
-
-				// Обработка задачи
-				w.processJob(ctx, *job)
+	w.running.Store(true)
+	defer w.running.Store(false)
+	defer w.jobID.Store(0)
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Worker stopped due to context cancellation")
+			return
+		case <-w.shutdown:
+			w.logger.Info("Worker stopped due to shutdown signal")
+			return
+		default:
+			// Извлечение задачи из очереди для очереди по умолчанию
+			w.lastPoll.Store(time.Now().UnixNano())
+			if w.queueService.isPaused() {
+				time.Sleep(1 * time.Second)
+				continue
 			}
+			job, err := w.queueService.PopJob(ctx, DefaultQueueName)
+			if err != nil {
+				w.logger.Error("Failed to pop job from queue",
+					"error", err,
+				)
+				time.Sleep(1 * time.Second)
+				continue
+			}
+
+			// Если очередь пуста, ждем некоторое время
+			if job == nil {
+				time.Sleep(1 * time.Second)
+				continue
+			}
+
+			// Обработка задачи
+			w.jobStartedAt.Store(time.Now().UnixNano())
+			w.jobID.Store(job.JobID)
+			w.processJob(ctx, *job)
+			w.jobID.Store(0)
 		}
-	}()
+	}
+}
+
+// state возвращает состояние цикла воркера
+func (w *Worker) state() WorkerState {
+	state := WorkerState{
+		Running: w.running.Load(),
+		Paused:  w.queueService.isPaused(),
+		JobID:   w.jobID.Load(),
+	}
+	if lastPoll := w.lastPoll.Load(); lastPoll != 0 {
+		state.LastPoll = time.Unix(0, lastPoll)
+	}
+	if state.JobID != 0 {
+		state.JobStartedAt = time.Unix(0, w.jobStartedAt.Load())
+	}
+	return state
 }
 
 // Stop останавливает воркер
@@ -259,8 +474,14 @@ func (w *Worker) processJob(ctx context.Context, job entity.QueueJob) {
 	}
 
 	// Вызов обработчика
-	err := handler(ctx, job)
+	err := w.runHandler(ctx, handler, job)
 	if err != nil {
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) {
+			w.queueService.handlePanickedJob(ctx, job, panicErr)
+			return
+		}
+
 		w.logger.Error("Failed to process job",
 			"error", err,
 		)
@@ -272,3 +493,32 @@ func (w *Worker) processJob(ctx context.Context, job entity.QueueJob) {
 		"job_id", job.JobID,
 	)
 }
+
+// PanicError описывает аварийное завершение обработчика задачи
+type PanicError struct {
+	Value any
+}
+
+// Error возвращает описание аварийного завершения
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// runHandler вызывает обработчик, превращая его аварийное завершение в ошибку *PanicError,
+// чтобы паника одного обработчика не останавливала воркер
+func (w *Worker) runHandler(ctx context.Context, handler JobHandler, job entity.QueueJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.logger.Error("Job handler panicked",
+				"job_id", job.JobID,
+				"job_type", job.JobType,
+				"attempts", job.Attempts,
+				"panic", fmt.Sprint(r),
+				"stack", string(debug.Stack()),
+			)
+			err = &PanicError{Value: r}
+		}
+	}()
+
+	return handler(ctx, job)
+}
@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// AdminAuditRepositorySQLite реализует интерфейс AdminAuditRepository для SQLite
+type AdminAuditRepositorySQLite struct {
+	db *DB
+}
+
+// NewAdminAuditRepository создает новый репозиторий для работы с журналом действий администраторов
+func NewAdminAuditRepository(db *DB) repository.AdminAuditRepository {
+	return &AdminAuditRepositorySQLite{db: db}
+}
+
+// Create добавляет запись в журнал
+func (r *AdminAuditRepositorySQLite) Create(ctx context.Context, entry *entity.AdminAuditEntry) error {
+	entry.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO admin_audit_log (admin_telegram_id, action, target_telegram_id, details, created_at)
+		VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, ''), $5)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		entry.AdminTelegramID,
+		entry.Action,
+		entry.TargetTelegramID,
+		entry.Details,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create admin audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecent возвращает последние записи журнала, начиная с самых новых
+func (r *AdminAuditRepositorySQLite) GetRecent(ctx context.Context, limit int) ([]*entity.AdminAuditEntry, error) {
+	query := `
+		SELECT id, admin_telegram_id, action, COALESCE(target_telegram_id, 0), COALESCE(details, ''), created_at
+		FROM admin_audit_log
+		ORDER BY id DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*entity.AdminAuditEntry, 0)
+	for rows.Next() {
+		entry := &entity.AdminAuditEntry{}
+		err := rows.Scan(
+			&entry.ID,
+			&entry.AdminTelegramID,
+			&entry.Action,
+			&entry.TargetTelegramID,
+			&entry.Details,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan admin audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate admin audit entries: %w", err)
+	}
+
+	return entries, nil
+}
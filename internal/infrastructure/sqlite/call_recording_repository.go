@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// CallRecordingRepositorySQLite реализует интерфейс CallRecordingRepository для SQLite
+type CallRecordingRepositorySQLite struct {
+	db *DB
+}
+
+// NewCallRecordingRepository создает новый репозиторий для работы со звонками, записанными VoIP провайдерами
+func NewCallRecordingRepository(db *DB) repository.CallRecordingRepository {
+	return &CallRecordingRepositorySQLite{db: db}
+}
+
+// callRecordingColumns - столбцы звонка в порядке полей scanCallRecording
+const callRecordingColumns = `id, provider, call_id, user_id, COALESCE(job_id, 0), COALESCE(direction, ''),
+	COALESCE(caller, ''), COALESCE(callee, ''), started_at, duration, created_at`
+
+// Create создает звонок
+func (r *CallRecordingRepositorySQLite) Create(ctx context.Context, call *entity.CallRecording) error {
+	call.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO call_recordings (provider, call_id, user_id, direction, caller, callee, started_at, duration, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		call.Provider,
+		call.CallID,
+		call.UserID,
+		call.Direction,
+		call.Caller,
+		call.Callee,
+		call.StartedAt,
+		call.Duration,
+		call.CreatedAt,
+	).Scan(&call.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create call recording: %w", err)
+	}
+
+	return nil
+}
+
+// GetByCallID возвращает звонок по ID у провайдера или nil, если его нет
+func (r *CallRecordingRepositorySQLite) GetByCallID(ctx context.Context, provider, callID string) (*entity.CallRecording, error) {
+	query := `SELECT ` + callRecordingColumns + ` FROM call_recordings WHERE provider = $1 AND call_id = $2`
+
+	return r.scanCallRecording(r.db.QueryRow(ctx, query, provider, callID))
+}
+
+// GetByJobID возвращает звонок, по записи которого создана задача, или nil, если задача не из звонка
+func (r *CallRecordingRepositorySQLite) GetByJobID(ctx context.Context, jobID int64) (*entity.CallRecording, error) {
+	query := `SELECT ` + callRecordingColumns + ` FROM call_recordings WHERE job_id = $1`
+
+	return r.scanCallRecording(r.db.QueryRow(ctx, query, jobID))
+}
+
+// scanCallRecording читает звонок из строки результата; отсутствие строки - не ошибка
+func (r *CallRecordingRepositorySQLite) scanCallRecording(row *sql.Row) (*entity.CallRecording, error) {
+	call := &entity.CallRecording{}
+	err := row.Scan(
+		&call.ID,
+		&call.Provider,
+		&call.CallID,
+		&call.UserID,
+		&call.JobID,
+		&call.Direction,
+		&call.Caller,
+		&call.Callee,
+		&call.StartedAt,
+		&call.Duration,
+		&call.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get call recording: %w", err)
+	}
+
+	return call, nil
+}
+
+// UpdateMetadata дополняет данные о звонке непустыми полями call
+func (r *CallRecordingRepositorySQLite) UpdateMetadata(ctx context.Context, call *entity.CallRecording) error {
+	query := `
+		UPDATE call_recordings
+		SET direction = COALESCE(NULLIF($1, ''), direction),
+			caller = COALESCE(NULLIF($2, ''), caller),
+			callee = COALESCE(NULLIF($3, ''), callee),
+			started_at = COALESCE($4, started_at),
+			duration = CASE WHEN $5 > 0 THEN $5 ELSE duration END
+		WHERE id = $6
+	`
+
+	_, err := r.db.Exec(ctx, query, string(call.Direction), call.Caller, call.Callee, call.StartedAt, call.Duration, call.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update call recording metadata: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimRecording отмечает получение записи звонка. Возвращает false, если запись уже получена
+func (r *CallRecordingRepositorySQLite) ClaimRecording(ctx context.Context, id int64, now time.Time) (bool, error) {
+	query := `
+		UPDATE call_recordings
+		SET recording_received_at = $1
+		WHERE id = $2 AND recording_received_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, now, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim call recording: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim call recording: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// SetJob связывает звонок с задачей обработки записи
+func (r *CallRecordingRepositorySQLite) SetJob(ctx context.Context, id, jobID int64) error {
+	query := `UPDATE call_recordings SET job_id = $1 WHERE id = $2`
+
+	if _, err := r.db.Exec(ctx, query, jobID, id); err != nil {
+		return fmt.Errorf("failed to set call recording job: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	// Драйвер SQLite на чистом Go: приложение собирается без cgo в один бинарный файл
+	_ "modernc.org/sqlite"
+)
+
+// dsnParams - параметры подключения: внешние ключи (для каскадного удаления), журнал WAL,
+// ожидание занятой базы и хранение времени в формате, который SQLite сравнивает как строки
+const dsnParams = "?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_time_format=sqlite"
+
+// rowScanner - строка результата запроса: *sql.Row или *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// DB представляет собой обертку над подключением к файлу базы данных SQLite
+type DB struct {
+	db *sql.DB
+}
+
+// NewDB открывает базу данных SQLite по пути path, создавая файл при необходимости, и применяет схему
+func NewDB(ctx context.Context, path string) (*DB, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create sqlite directory: %w", err)
+		}
+	}
+
+	sqlDB, err := sql.Open("sqlite", "file:"+path+dsnParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// SQLite допускает одного писателя: запросы выполняются по очереди через одно соединение,
+	// чтобы не получать ошибку занятой базы
+	sqlDB.SetMaxOpenConns(1)
+
+	// Проверка соединения
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to ping sqlite: %w", err)
+	}
+
+	db := &DB{db: sqlDB}
+	if err := db.migrate(ctx); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// migrate применяет шаги схемы, которые еще не применены к базе. Номер последнего
+// примененного шага хранится в PRAGMA user_version
+func (db *DB) migrate(ctx context.Context) error {
+	var version int
+	if err := db.db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("failed to get sqlite schema version: %w", err)
+	}
+
+	for i := version; i < len(schemaMigrations); i++ {
+		tx, err := db.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, schemaMigrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply sqlite schema step %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to set sqlite schema version: %w", err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Close закрывает соединение с базой данных
+func (db *DB) Close() {
+	if db.db != nil {
+		db.db.Close()
+	}
+}
+
+// Ping проверяет соединение с базой данных. Соединение одно, поэтому Ping ждет завершения текущего запроса
+func (db *DB) Ping(ctx context.Context) error {
+	return db.db.PingContext(ctx)
+}
+
+// Exec выполняет SQL-запрос без возврата результатов
+func (db *DB) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.db.ExecContext(ctx, query, normalizeArgs(args)...)
+}
+
+// Query выполняет SQL-запрос и возвращает результаты
+func (db *DB) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return db.db.QueryContext(ctx, query, normalizeArgs(args)...)
+}
+
+// QueryRow выполняет SQL-запрос и возвращает одну строку результата
+func (db *DB) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return db.db.QueryRowContext(ctx, query, normalizeArgs(args)...)
+}
+
+// Begin начинает новую транзакцию
+func (db *DB) Begin(ctx context.Context) (*Tx, error) {
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{tx: tx}, nil
+}
+
+// Tx представляет собой транзакцию с тем же приведением аргументов, что и DB
+type Tx struct {
+	tx *sql.Tx
+}
+
+// Exec выполняет SQL-запрос в транзакции без возврата результатов
+func (t *Tx) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, query, normalizeArgs(args)...)
+}
+
+// QueryRow выполняет SQL-запрос в транзакции и возвращает одну строку результата
+func (t *Tx) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.tx.QueryRowContext(ctx, query, normalizeArgs(args)...)
+}
+
+// Commit фиксирует транзакцию
+func (t *Tx) Commit() error {
+	return t.tx.Commit()
+}
+
+// Rollback откатывает транзакцию; после Commit ничего не делает
+func (t *Tx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// normalizeArgs приводит время к UTC: SQLite хранит время строкой, и сравнения в запросах
+// корректны, только если все значения записаны в одном часовом поясе
+func normalizeArgs(args []interface{}) []interface{} {
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			args[i] = v.UTC()
+		case *time.Time:
+			if v == nil {
+				args[i] = nil
+			} else {
+				args[i] = v.UTC()
+			}
+		}
+	}
+	return args
+}
@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// EmbeddingRepositorySQLite реализует интерфейс EmbeddingRepository для SQLite.
+// Векторных индексов в SQLite нет, поэтому поиск перебирает заметки пользователя и считает
+// косинусное расстояние в приложении; для личной базы заметок этого достаточно
+type EmbeddingRepositorySQLite struct {
+	db *DB
+}
+
+// NewEmbeddingRepository создает новый репозиторий для работы с эмбеддингами заметок
+func NewEmbeddingRepository(db *DB) repository.EmbeddingRepository {
+	return &EmbeddingRepositorySQLite{db: db}
+}
+
+// ReplaceForJob заменяет все эмбеддинги задачи переданными
+func (r *EmbeddingRepositorySQLite) ReplaceForJob(ctx context.Context, jobID int64, embeddings []*entity.NoteEmbedding) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Удаление старых эмбеддингов задачи
+	_, err = tx.Exec(ctx, `DELETE FROM note_embeddings WHERE job_id = $1`, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to delete embeddings: %w", err)
+	}
+
+	query := `
+		INSERT INTO note_embeddings (job_id, user_id, kind, chunk_index, content, embedding, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	now := time.Now()
+	for _, e := range embeddings {
+		e.CreatedAt = now
+		err = tx.QueryRow(
+			ctx,
+			query,
+			jobID,
+			e.UserID,
+			e.Kind,
+			e.ChunkIndex,
+			e.Content,
+			encodeVector(e.Embedding),
+			e.CreatedAt,
+		).Scan(&e.ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert embedding: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SearchSimilar возвращает ближайшие к вектору фрагменты заметок пользователя по косинусному расстоянию
+func (r *EmbeddingRepositorySQLite) SearchSimilar(ctx context.Context, userID int64, embedding []float32, limit int) ([]*entity.NoteEmbedding, error) {
+	query := `
+		SELECT id, job_id, user_id, kind, chunk_index, content, embedding, created_at
+		FROM note_embeddings
+		WHERE user_id = $1
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*entity.NoteEmbedding
+	for rows.Next() {
+		e := &entity.NoteEmbedding{}
+		var vector []byte
+		err := rows.Scan(
+			&e.ID,
+			&e.JobID,
+			&e.UserID,
+			&e.Kind,
+			&e.ChunkIndex,
+			&e.Content,
+			&vector,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan embedding: %w", err)
+		}
+		e.Distance = cosineDistance(embedding, decodeVector(vector))
+		results = append(results, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating embeddings: %w", err)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Distance < results[j].Distance
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// encodeVector кодирует вектор в BLOB: float32 в порядке little-endian
+func encodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// decodeVector декодирует вектор из BLOB, записанного encodeVector
+func decodeVector(buf []byte) []float32 {
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return vector
+}
+
+// cosineDistance возвращает косинусное расстояние между векторами, как оператор <=> pgvector.
+// Для векторов разной длины или нулевых векторов расстояние максимально
+func cosineDistance(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 2
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 2
+	}
+
+	return 1 - dot/(math.Sqrt(normA)*math.Sqrt(normB))
+}
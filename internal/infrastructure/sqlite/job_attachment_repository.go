@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// JobAttachmentRepositorySQLite реализует интерфейс JobAttachmentRepository для SQLite
+type JobAttachmentRepositorySQLite struct {
+	db *DB
+}
+
+// NewJobAttachmentRepository создает новый репозиторий для работы с фотографиями задач
+func NewJobAttachmentRepository(db *DB) repository.JobAttachmentRepository {
+	return &JobAttachmentRepositorySQLite{db: db}
+}
+
+// Create сохраняет фотографию задачи
+func (r *JobAttachmentRepositorySQLite) Create(ctx context.Context, attachment *entity.JobAttachment) error {
+	attachment.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO job_attachments (job_id, token, content_type, image, ocr_text, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		attachment.JobID,
+		attachment.Token,
+		attachment.ContentType,
+		attachment.Image,
+		attachment.OCRText,
+		attachment.CreatedAt,
+	).Scan(&attachment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create job attachment: %w", err)
+	}
+
+	return nil
+}
+
+// GetByToken возвращает фотографию по ее токену
+func (r *JobAttachmentRepositorySQLite) GetByToken(ctx context.Context, token string) (*entity.JobAttachment, error) {
+	query := `
+		SELECT id, job_id, token, content_type, image, ocr_text, created_at
+		FROM job_attachments
+		WHERE token = $1
+	`
+
+	attachment := &entity.JobAttachment{}
+	err := r.db.QueryRow(ctx, query, token).Scan(
+		&attachment.ID,
+		&attachment.JobID,
+		&attachment.Token,
+		&attachment.ContentType,
+		&attachment.Image,
+		&attachment.OCRText,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("job attachment not found")
+		}
+		return nil, fmt.Errorf("failed to get job attachment: %w", err)
+	}
+
+	return attachment, nil
+}
@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// JobLogRepositorySQLite реализует интерфейс JobLogRepository для SQLite
+type JobLogRepositorySQLite struct {
+	db *DB
+}
+
+// NewJobLogRepository создает новый репозиторий для работы с индексом логов задач
+func NewJobLogRepository(db *DB) repository.JobLogRepository {
+	return &JobLogRepositorySQLite{db: db}
+}
+
+// Create добавляет записи лога в одной транзакции; записи удаленных задач пропускаются
+func (r *JobLogRepositorySQLite) Create(ctx context.Context, lines []*entity.JobLogLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO job_logs (job_id, level, message, attrs, created_at)
+		SELECT $1, $2, $3, $4, $5
+		WHERE EXISTS (SELECT 1 FROM jobs WHERE id = $1)
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, line := range lines {
+		attrs, err := json.Marshal(line.Attrs)
+		if err != nil {
+			return fmt.Errorf("failed to marshal job log attrs: %w", err)
+		}
+		if _, err := tx.Exec(ctx, query, line.JobID, line.Level, line.Message, string(attrs), line.CreatedAt); err != nil {
+			return fmt.Errorf("failed to create job log line: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByJobID возвращает последние limit записей задачи в порядке их появления
+func (r *JobLogRepositorySQLite) GetByJobID(ctx context.Context, jobID int64, limit int) ([]*entity.JobLogLine, error) {
+	query := `
+		SELECT id, job_id, level, message, attrs, created_at
+		FROM (
+			SELECT id, job_id, level, message, attrs, created_at
+			FROM job_logs
+			WHERE job_id = $1
+			ORDER BY id DESC
+			LIMIT $2
+		)
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query, jobID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job log lines: %w", err)
+	}
+	defer rows.Close()
+
+	lines := make([]*entity.JobLogLine, 0)
+	for rows.Next() {
+		line := &entity.JobLogLine{}
+		var attrs string
+		if err := rows.Scan(&line.ID, &line.JobID, &line.Level, &line.Message, &attrs, &line.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan job log line: %w", err)
+		}
+		if err := json.Unmarshal([]byte(attrs), &line.Attrs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal job log attrs: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate job log lines: %w", err)
+	}
+
+	return lines, nil
+}
+
+// DeleteBefore удаляет записи, сделанные раньше before, и возвращает их число
+func (r *JobLogRepositorySQLite) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM job_logs WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete job log lines: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete job log lines: %w", err)
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,1089 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// jobColumns - колонки задачи в порядке сканирования scanJob
+const jobColumns = `id, user_id, type, status, audio_file_path, file_name, COALESCE(duration, 0),
+	COALESCE(transcription, ''), COALESCE(summary, ''), COALESCE(notion_page_id, ''), COALESCE(notion_database_id, ''),
+	COALESCE(source_message_id, 0), COALESCE(instructions, ''), audio_profile, COALESCE(summary_model, ''),
+	COALESCE(summary_cost, 0), summary_tokens, created_at, updated_at, completed_at, COALESCE(error_message, ''), COALESCE(failed_stage, ''),
+	COALESCE(notion_upload_page_id, ''), notion_upload_cursor, COALESCE(progress_message_id, 0), COALESCE(summary_style, ''),
+	COALESCE(processed_audio_path, ''), COALESCE(processed_audio_profile, ''), COALESCE(duplicate_of_job_id, 0),
+	COALESCE(duplicate_similarity, 0), COALESCE(speaker_segments, '')`
+
+// jobInsertQuery - запрос добавления задачи с аргументами из jobInsertArgs
+const jobInsertQuery = `
+	INSERT INTO jobs (
+		user_id, type, status, audio_file_path, file_name, transcription, summary,
+		notion_page_id, notion_database_id, source_message_id, instructions, audio_profile, created_at, updated_at,
+		completed_at, error_message, file_unique_id
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12, $13, $14, $15, $16, NULLIF($17, ''))
+	RETURNING id
+`
+
+// JobRepositorySQLite реализует интерфейс JobRepository для SQLite
+type JobRepositorySQLite struct {
+	db *DB
+}
+
+// NewJobRepository создает новый репозиторий для работы с задачами
+func NewJobRepository(db *DB) repository.JobRepository {
+	return &JobRepositorySQLite{db: db}
+}
+
+// Create создает новую задачу
+func (r *JobRepositorySQLite) Create(ctx context.Context, job *entity.Job) error {
+	prepareNewJob(job, time.Now())
+
+	err := r.db.QueryRow(ctx, jobInsertQuery, jobInsertArgs(job)...).Scan(&job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	return nil
+}
+
+// CreateBatch создает задачи в одной транзакции и заполняет их ID.
+// Если не удалось создать хотя бы одну задачу, не создается ни одна
+func (r *JobRepositorySQLite) CreateBatch(ctx context.Context, jobs []*entity.Job) error {
+	return r.insertBatch(ctx, jobs, prepareNewJob)
+}
+
+// Import загружает задачи в одной транзакции и возвращает число загруженных задач. В SQLite нет COPY,
+// поэтому задачи добавляются тем же запросом, что и в Create, но со статусом и временем создания из архива
+func (r *JobRepositorySQLite) Import(ctx context.Context, jobs []*entity.Job) (int64, error) {
+	if err := r.insertBatch(ctx, jobs, prepareImportedJob); err != nil {
+		return 0, fmt.Errorf("failed to import jobs: %w", err)
+	}
+
+	return int64(len(jobs)), nil
+}
+
+// insertBatch добавляет задачи, подготовленные prepare, в одной транзакции и заполняет их ID
+func (r *JobRepositorySQLite) insertBatch(ctx context.Context, jobs []*entity.Job, prepare func(*entity.Job, time.Time)) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, job := range jobs {
+		prepare(job, now)
+		if err := tx.QueryRow(ctx, jobInsertQuery, jobInsertArgs(job)...).Scan(&job.ID); err != nil {
+			return fmt.Errorf("failed to create job: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// prepareNewJob заполняет время создания, начальный статус и значения по умолчанию новой задачи
+func prepareNewJob(job *entity.Job, now time.Time) {
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	job.Status = entity.JobStatusPending
+
+	if job.Type == "" {
+		job.Type = entity.JobTypeTranscription
+	}
+	if job.AudioProfile == "" {
+		job.AudioProfile = entity.AudioProfileAuto
+	}
+}
+
+// prepareImportedJob заполняет значения по умолчанию импортируемой задачи. В отличие от prepareNewJob,
+// статус и время создания задачи сохраняются, если они заданы
+func prepareImportedJob(job *entity.Job, now time.Time) {
+	status, createdAt, updatedAt := job.Status, job.CreatedAt, job.UpdatedAt
+	prepareNewJob(job, now)
+
+	if status != "" {
+		job.Status = status
+	}
+	if !createdAt.IsZero() {
+		job.CreatedAt = createdAt
+	}
+	if !updatedAt.IsZero() {
+		job.UpdatedAt = updatedAt
+	}
+}
+
+// jobInsertArgs возвращает аргументы jobInsertQuery
+func jobInsertArgs(job *entity.Job) []interface{} {
+	return []interface{}{
+		job.UserID,
+		job.Type,
+		job.Status,
+		job.AudioFilePath,
+		job.FileName,
+		job.Transcription,
+		job.Summary,
+		job.NotionPageID,
+		job.NotionDatabaseID,
+		job.SourceMessageID,
+		job.Instructions,
+		job.AudioProfile,
+		job.CreatedAt,
+		job.UpdatedAt,
+		job.CompletedAt,
+		job.ErrorMessage,
+		job.FileUniqueID,
+	}
+}
+
+// GetByID возвращает задачу по её ID
+func (r *JobRepositorySQLite) GetByID(ctx context.Context, id int64) (*entity.Job, error) {
+	job, err := scanJob(r.db.QueryRow(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("job not found")
+		}
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return job, nil
+}
+
+// GetByUserID возвращает задачи пользователя
+func (r *JobRepositorySQLite) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*entity.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*entity.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// jobStatusFilterConditions - условия на статус задачи для отборов списка задач
+var jobStatusFilterConditions = map[entity.JobStatusFilter]string{
+	entity.JobStatusFilterAll:       "1 = 1",
+	entity.JobStatusFilterActive:    "status NOT IN ('completed', 'failed')",
+	entity.JobStatusFilterCompleted: "status = 'completed'",
+	entity.JobStatusFilterFailed:    "status = 'failed'",
+}
+
+// GetByUserIDAndStatus возвращает задачи пользователя, подходящие под отбор по статусу, от новых к старым
+func (r *JobRepositorySQLite) GetByUserIDAndStatus(ctx context.Context, userID int64, filter entity.JobStatusFilter, limit, offset int) ([]*entity.Job, error) {
+	condition, ok := jobStatusFilterConditions[filter]
+	if !ok {
+		return nil, fmt.Errorf("unknown job status filter %q", filter)
+	}
+
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE user_id = $1 AND ` + condition + `
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*entity.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// scanJob считывает задачу из строки результата запроса с колонками jobColumns
+func scanJob(row rowScanner) (*entity.Job, error) {
+	job := &entity.Job{}
+	var segmentsJSON string
+	err := row.Scan(
+		&job.ID,
+		&job.UserID,
+		&job.Type,
+		&job.Status,
+		&job.AudioFilePath,
+		&job.FileName,
+		&job.Duration,
+		&job.Transcription,
+		&job.Summary,
+		&job.NotionPageID,
+		&job.NotionDatabaseID,
+		&job.SourceMessageID,
+		&job.Instructions,
+		&job.AudioProfile,
+		&job.SummaryModel,
+		&job.SummaryCost,
+		&job.SummaryTokens,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.CompletedAt,
+		&job.ErrorMessage,
+		&job.FailedStage,
+		&job.NotionUploadPageID,
+		&job.NotionUploadCursor,
+		&job.ProgressMessageID,
+		&job.SummaryStyle,
+		&job.ProcessedAudioPath,
+		&job.ProcessedAudioProfile,
+		&job.DuplicateOfJobID,
+		&job.DuplicateSimilarity,
+		&segmentsJSON,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if segmentsJSON != "" {
+		if err := json.Unmarshal([]byte(segmentsJSON), &job.Segments); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal speaker segments: %w", err)
+		}
+	}
+	return job, nil
+}
+
+// Update обновляет информацию о задаче
+func (r *JobRepositorySQLite) Update(ctx context.Context, job *entity.Job) error {
+	job.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE jobs
+		SET
+			status = $1,
+			audio_file_path = $2,
+			file_name = $3,
+			duration = $4,
+			transcription = $5,
+			summary = $6,
+			notion_page_id = $7,
+			notion_database_id = $8,
+			updated_at = $9,
+			completed_at = $10,
+			error_message = $11
+		WHERE id = $12
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		job.Status,
+		job.AudioFilePath,
+		job.FileName,
+		job.Duration,
+		job.Transcription,
+		job.Summary,
+		job.NotionPageID,
+		job.NotionDatabaseID,
+		job.UpdatedAt,
+		job.CompletedAt,
+		job.ErrorMessage,
+		job.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStatus обновляет статус задачи
+func (r *JobRepositorySQLite) UpdateStatus(ctx context.Context, id int64, status entity.JobStatus, errorMessage string) error {
+	now := time.Now()
+	var completedAt *time.Time
+
+	if status == entity.JobStatusCompleted || status == entity.JobStatusFailed {
+		completedAt = &now
+	}
+
+	// Этап ошибки остается только у задачи с ошибкой: перезапущенная задача его теряет
+	query := `
+		UPDATE jobs
+		SET status = $1, updated_at = $2, completed_at = $3, error_message = $4,
+			failed_stage = CASE WHEN $6 THEN failed_stage END
+		WHERE id = $5
+	`
+
+	_, err := r.db.Exec(ctx, query, status, now, completedAt, errorMessage, id, status == entity.JobStatusFailed)
+	if err != nil {
+		return fmt.Errorf("failed to update job status: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed переводит задачу в статус ошибки и запоминает этап, на котором она произошла
+func (r *JobRepositorySQLite) MarkFailed(ctx context.Context, id int64, stage entity.JobStageName, errorMessage string) error {
+	now := time.Now()
+
+	query := `
+		UPDATE jobs
+		SET status = $1, failed_stage = $2, error_message = $3, completed_at = $4, updated_at = $4
+		WHERE id = $5
+	`
+
+	_, err := r.db.Exec(ctx, query, entity.JobStatusFailed, stage, errorMessage, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark job failed: %w", err)
+	}
+
+	return nil
+}
+
+// SetTranscription устанавливает транскрипцию для задачи
+func (r *JobRepositorySQLite) SetTranscription(ctx context.Context, id int64, transcription string) error {
+	_, err := r.db.Exec(ctx, `UPDATE jobs SET transcription = $1, updated_at = $2 WHERE id = $3`, transcription, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set transcription: %w", err)
+	}
+
+	return nil
+}
+
+// SetSummary устанавливает суммаризацию для задачи
+func (r *JobRepositorySQLite) SetSummary(ctx context.Context, id int64, summary string) error {
+	_, err := r.db.Exec(ctx, `UPDATE jobs SET summary = $1, updated_at = $2 WHERE id = $3`, summary, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set summary: %w", err)
+	}
+
+	return nil
+}
+
+// SetSummaryUsage сохраняет модель, стоимость и число токенов суммаризации задачи
+func (r *JobRepositorySQLite) SetSummaryUsage(ctx context.Context, id int64, model string, cost float64, tokens int) error {
+	query := `
+		UPDATE jobs
+		SET summary_model = $1, summary_cost = $2, summary_tokens = $3, updated_at = $4
+		WHERE id = $5
+	`
+
+	_, err := r.db.Exec(ctx, query, model, cost, tokens, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set summary usage: %w", err)
+	}
+
+	return nil
+}
+
+// SetNotionIDs устанавливает ID страницы и базы данных Notion для задачи
+func (r *JobRepositorySQLite) SetNotionIDs(ctx context.Context, id int64, pageID, databaseID string) error {
+	query := `
+		UPDATE jobs
+		SET notion_page_id = $1, notion_database_id = $2, updated_at = $3
+		WHERE id = $4
+	`
+
+	_, err := r.db.Exec(ctx, query, pageID, databaseID, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set notion IDs: %w", err)
+	}
+
+	return nil
+}
+
+// SetNotionUpload сохраняет страницу Notion и число уже добавленных на нее блоков, чтобы прерванная выгрузка
+// продолжилась с последней добавленной части. Пустой pageID сбрасывает ход выгрузки
+func (r *JobRepositorySQLite) SetNotionUpload(ctx context.Context, id int64, pageID string, cursor int) error {
+	query := `
+		UPDATE jobs
+		SET notion_upload_page_id = NULLIF($1, ''), notion_upload_cursor = $2, updated_at = $3
+		WHERE id = $4
+	`
+
+	_, err := r.db.Exec(ctx, query, pageID, cursor, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set notion upload: %w", err)
+	}
+
+	return nil
+}
+
+// SetAudioProfile устанавливает профиль предобработки аудио, пока обработка задачи не началась.
+// Возвращает false, если задача уже взята в работу
+func (r *JobRepositorySQLite) SetAudioProfile(ctx context.Context, id int64, profile entity.AudioProfile) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET audio_profile = $1, updated_at = $2
+		WHERE id = $3 AND status IN ('created', 'pending', 'queued')
+	`
+
+	result, err := r.db.Exec(ctx, query, profile, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to set audio profile: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to set audio profile: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// SetSummaryStyle устанавливает стиль краткого содержания задачи, пока ее транскрибация не завершена.
+// Возвращает false, если суммаризация уже поставлена в очередь
+func (r *JobRepositorySQLite) SetSummaryStyle(ctx context.Context, id int64, style entity.SummaryStyle) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET summary_style = $1, updated_at = $2
+		WHERE id = $3 AND status IN ('created', 'pending', 'queued', 'processing')
+	`
+
+	result, err := r.db.Exec(ctx, query, style, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to set summary style: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to set summary style: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// SetProcessedAudio сохраняет подготовленный к распознаванию WAV задачи и профиль, с которым он получен,
+// и отсчитывает срок его хранения заново. Пустой path забывает WAV
+func (r *JobRepositorySQLite) SetProcessedAudio(ctx context.Context, id int64, path string, profile entity.AudioProfile) error {
+	var processedAt *time.Time
+	if path != "" {
+		now := time.Now()
+		processedAt = &now
+	}
+
+	query := `
+		UPDATE jobs
+		SET processed_audio_path = NULLIF($1, ''), processed_audio_profile = NULLIF($2, ''), processed_audio_at = $3
+		WHERE id = $4
+	`
+
+	_, err := r.db.Exec(ctx, query, path, string(profile), processedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to set processed audio: %w", err)
+	}
+
+	return nil
+}
+
+// GetProcessedAudioBefore возвращает задачи, подготовленные WAV которых сохранены или использованы до before,
+// начиная с самых старых
+func (r *JobRepositorySQLite) GetProcessedAudioBefore(ctx context.Context, before time.Time, limit int) ([]*entity.Job, error) {
+	query := `
+		SELECT id, user_id, processed_audio_path
+		FROM jobs
+		WHERE processed_audio_at < $1
+		ORDER BY processed_audio_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs with processed audio: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*entity.Job, 0)
+	for rows.Next() {
+		job := &entity.Job{}
+		if err := rows.Scan(&job.ID, &job.UserID, &job.ProcessedAudioPath); err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ClearProcessedAudio забывает подготовленный WAV задачи, если он не использовался после before.
+// Возвращает false, если WAV использован повторно и удалять его рано
+func (r *JobRepositorySQLite) ClearProcessedAudio(ctx context.Context, id int64, before time.Time) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET processed_audio_path = NULL, processed_audio_profile = NULL, processed_audio_at = NULL
+		WHERE id = $1 AND processed_audio_at < $2
+	`
+
+	result, err := r.db.Exec(ctx, query, id, before)
+	if err != nil {
+		return false, fmt.Errorf("failed to clear processed audio: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to clear processed audio: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// SetTranscriptSignature сохраняет сигнатуру транскрипции задачи для поиска повторно присланных записей
+func (r *JobRepositorySQLite) SetTranscriptSignature(ctx context.Context, id int64, signature string) error {
+	query := `
+		UPDATE jobs
+		SET transcript_minhash = NULLIF($1, '')
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, signature, id)
+	if err != nil {
+		return fmt.Errorf("failed to set transcript signature: %w", err)
+	}
+
+	return nil
+}
+
+// GetTranscriptSignatures возвращает ID и сигнатуры транскрипций задач пользователя, созданных после since,
+// кроме excludeID и задач, которые сами помечены как повтор, начиная с самых новых
+func (r *JobRepositorySQLite) GetTranscriptSignatures(ctx context.Context, userID, excludeID int64, since time.Time, limit int) ([]*entity.Job, error) {
+	query := `
+		SELECT id, transcript_minhash
+		FROM jobs
+		WHERE user_id = $1 AND id <> $2 AND created_at >= $3
+			AND transcript_minhash IS NOT NULL AND duplicate_of_job_id IS NULL
+		ORDER BY created_at DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, excludeID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcript signatures: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*entity.Job, 0)
+	for rows.Next() {
+		job := &entity.Job{UserID: userID}
+		if err := rows.Scan(&job.ID, &job.TranscriptSignature); err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// SetSpeakerSegments сохраняет фразы транскрипции задачи с говорящими; пустой список забывает их
+func (r *JobRepositorySQLite) SetSpeakerSegments(ctx context.Context, id int64, segments []entity.SpeakerSegment) error {
+	var segmentsJSON *string
+	if len(segments) > 0 {
+		data, err := json.Marshal(segments)
+		if err != nil {
+			return fmt.Errorf("failed to marshal speaker segments: %w", err)
+		}
+		encoded := string(data)
+		segmentsJSON = &encoded
+	}
+
+	query := `
+		UPDATE jobs
+		SET speaker_segments = $1
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, segmentsJSON, id)
+	if err != nil {
+		return fmt.Errorf("failed to set speaker segments: %w", err)
+	}
+
+	return nil
+}
+
+// SetDuplicateOf помечает задачу как повтор задачи originalID с долей совпадения similarity; originalID 0 снимает пометку
+func (r *JobRepositorySQLite) SetDuplicateOf(ctx context.Context, id, originalID int64, similarity float64) error {
+	query := `
+		UPDATE jobs
+		SET duplicate_of_job_id = NULLIF($1, 0), duplicate_similarity = CASE WHEN $1 = 0 THEN NULL ELSE $2 END
+		WHERE id = $3
+	`
+
+	_, err := r.db.Exec(ctx, query, originalID, similarity, id)
+	if err != nil {
+		return fmt.Errorf("failed to set duplicate job: %w", err)
+	}
+
+	return nil
+}
+
+// GetForNotionReconcile возвращает завершенные задачи пользователей с настроенным Notion,
+// у которых ID больше afterID, в порядке возрастания ID
+func (r *JobRepositorySQLite) GetForNotionReconcile(ctx context.Context, afterID int64, limit int) ([]*entity.Job, error) {
+	query := `
+		SELECT
+			j.id, j.user_id, j.type, j.status, j.file_name, COALESCE(j.transcription, ''), COALESCE(j.summary, ''),
+			COALESCE(j.notion_page_id, ''), COALESCE(j.notion_database_id, ''), j.created_at
+		FROM jobs j
+		JOIN users u ON u.id = j.user_id
+		WHERE j.id > $1
+			AND j.status = $2
+			AND j.notion_unlinked_at IS NULL
+			AND COALESCE(u.notion_token, '') <> ''
+			AND COALESCE(u.notion_database_id, '') <> ''
+		ORDER BY j.id
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, afterID, entity.JobStatusCompleted, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs for Notion reconcile: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*entity.Job, 0)
+	for rows.Next() {
+		job := &entity.Job{}
+		err := rows.Scan(
+			&job.ID,
+			&job.UserID,
+			&job.Type,
+			&job.Status,
+			&job.FileName,
+			&job.Transcription,
+			&job.Summary,
+			&job.NotionPageID,
+			&job.NotionDatabaseID,
+			&job.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ClearNotionPage удаляет ссылку на страницу Notion, которую удалил пользователь.
+// Задача помечается отвязанной, чтобы сверка не создавала страницу заново
+func (r *JobRepositorySQLite) ClearNotionPage(ctx context.Context, id int64) error {
+	query := `
+		UPDATE jobs
+		SET notion_page_id = '', notion_unlinked_at = $1, updated_at = $1
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to clear notion page: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserStats возвращает агрегированную статистику задач пользователя начиная с указанного момента
+func (r *JobRepositorySQLite) GetUserStats(ctx context.Context, userID int64, since time.Time) (*entity.UserStats, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE created_at >= $2),
+			COALESCE(SUM(duration) FILTER (WHERE created_at >= $2 AND status = $3), 0),
+			COUNT(*) FILTER (WHERE status NOT IN ($3, $4))
+		FROM jobs
+		WHERE user_id = $1
+	`
+
+	stats := &entity.UserStats{}
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		userID,
+		since,
+		entity.JobStatusCompleted,
+		entity.JobStatusFailed,
+	).Scan(
+		&stats.TotalJobs,
+		&stats.JobsSince,
+		&stats.SecondsTranscribed,
+		&stats.PendingJobs,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetUserUsage возвращает итоги использования бота пользователем за все время
+func (r *JobRepositorySQLite) GetUserUsage(ctx context.Context, userID int64) (*entity.UserUsage, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = $2),
+			COUNT(*) FILTER (WHERE status = $3),
+			COALESCE(SUM(duration) FILTER (WHERE transcription IS NOT NULL AND transcription <> ''), 0),
+			COALESCE(SUM(summary_tokens), 0),
+			COALESCE(SUM(summary_cost), 0)
+		FROM jobs
+		WHERE user_id = $1
+	`
+
+	usage := &entity.UserUsage{}
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		userID,
+		entity.JobStatusCompleted,
+		entity.JobStatusFailed,
+	).Scan(
+		&usage.TotalJobs,
+		&usage.CompletedJobs,
+		&usage.FailedJobs,
+		&usage.SecondsTranscribed,
+		&usage.SummaryTokens,
+		&usage.SummaryCost,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// GetTotals возвращает сводку по задачам всех пользователей; итоги за период считаются начиная с since
+func (r *JobRepositorySQLite) GetTotals(ctx context.Context, since time.Time) (*entity.JobTotals, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE created_at >= $1),
+			COUNT(*) FILTER (WHERE created_at >= $1 AND status = $2),
+			COUNT(*) FILTER (WHERE created_at >= $1 AND status = $3),
+			COUNT(*) FILTER (WHERE status NOT IN ($2, $3)),
+			COUNT(DISTINCT user_id) FILTER (WHERE created_at >= $1),
+			COALESCE(SUM(duration) FILTER (WHERE created_at >= $1 AND transcription IS NOT NULL AND transcription <> ''), 0),
+			COALESCE(SUM(summary_cost) FILTER (WHERE created_at >= $1), 0)
+		FROM jobs
+	`
+
+	totals := &entity.JobTotals{}
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		since,
+		entity.JobStatusCompleted,
+		entity.JobStatusFailed,
+	).Scan(
+		&totals.TotalJobs,
+		&totals.JobsSince,
+		&totals.CompletedSince,
+		&totals.FailedSince,
+		&totals.PendingJobs,
+		&totals.ActiveUsersSince,
+		&totals.SecondsTranscribed,
+		&totals.SummaryCostSince,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job totals: %w", err)
+	}
+
+	return totals, nil
+}
+
+// GetForKeepalive возвращает незавершенные задачи, созданные в промежутке [createdAfter, createdBefore),
+// о ходе которых пользователю не сообщали после keepaliveBefore, в порядке создания
+func (r *JobRepositorySQLite) GetForKeepalive(ctx context.Context, createdAfter, createdBefore, keepaliveBefore time.Time, limit int) ([]*entity.Job, error) {
+	query := `
+		SELECT id, user_id, status, file_name, COALESCE(duration, 0), COALESCE(source_message_id, 0),
+			COALESCE(keepalive_message_id, 0), created_at
+		FROM jobs
+		WHERE status NOT IN ('completed', 'failed')
+			AND created_at >= $1
+			AND created_at < $2
+			AND (keepalive_at IS NULL OR keepalive_at < $3)
+		ORDER BY created_at
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(ctx, query, createdAfter, createdBefore, keepaliveBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs for keepalive: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*entity.Job, 0)
+	for rows.Next() {
+		job := &entity.Job{}
+		err := rows.Scan(
+			&job.ID,
+			&job.UserID,
+			&job.Status,
+			&job.FileName,
+			&job.Duration,
+			&job.SourceMessageID,
+			&job.KeepaliveMessageID,
+			&job.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ClaimKeepalive отмечает время сообщения о ходе задачи, если о нем не сообщали после keepaliveBefore.
+// Возвращает false, если сообщение уже отправил другой экземпляр приложения
+func (r *JobRepositorySQLite) ClaimKeepalive(ctx context.Context, id int64, keepaliveBefore, now time.Time) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET keepalive_at = $1
+		WHERE id = $2 AND (keepalive_at IS NULL OR keepalive_at < $3)
+	`
+
+	result, err := r.db.Exec(ctx, query, now, id, keepaliveBefore)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim job keepalive: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim job keepalive: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// SetKeepaliveMessage сохраняет ID сообщения о ходе задачи
+func (r *JobRepositorySQLite) SetKeepaliveMessage(ctx context.Context, id int64, messageID int) error {
+	query := `
+		UPDATE jobs
+		SET keepalive_message_id = $1
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, messageID, id)
+	if err != nil {
+		return fmt.Errorf("failed to set keepalive message: %w", err)
+	}
+
+	return nil
+}
+
+// SetProgressMessage сохраняет ID сообщения о ходе обработки задачи, если в задаче все еще сохранен previousID.
+// Так сообщение, отправленное вместе с ответом на запись, и сообщение первого этапа не затирают друг друга
+func (r *JobRepositorySQLite) SetProgressMessage(ctx context.Context, id int64, previousID, messageID int) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET progress_message_id = $1
+		WHERE id = $2 AND COALESCE(progress_message_id, 0) = $3
+	`
+
+	result, err := r.db.Exec(ctx, query, messageID, id, previousID)
+	if err != nil {
+		return false, fmt.Errorf("failed to set progress message: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to set progress message: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetFailedForCleanup возвращает задачи пользователя с ошибкой, обновленные после since, вместе со временем
+// их перезапуска ежедневным разбором ошибок, в порядке создания
+func (r *JobRepositorySQLite) GetFailedForCleanup(ctx context.Context, userID int64, since time.Time, limit int) ([]*entity.Job, error) {
+	query := `
+		SELECT id, user_id, status, COALESCE(audio_file_path, ''), file_name, COALESCE(transcription, ''),
+			COALESCE(error_message, ''), updated_at, cleanup_retried_at
+		FROM jobs
+		WHERE user_id = $1
+			AND status = 'failed'
+			AND updated_at >= $2
+		ORDER BY created_at
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failed jobs for cleanup: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*entity.Job, 0)
+	for rows.Next() {
+		job := &entity.Job{}
+		err := rows.Scan(
+			&job.ID,
+			&job.UserID,
+			&job.Status,
+			&job.AudioFilePath,
+			&job.FileName,
+			&job.Transcription,
+			&job.ErrorMessage,
+			&job.UpdatedAt,
+			&job.CleanupRetriedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// MarkCleanupRetried отмечает перезапуск задачи разбором ошибок, если она еще не перезапускалась.
+// Возвращает false, если задача уже перезапущена
+func (r *JobRepositorySQLite) MarkCleanupRetried(ctx context.Context, id int64, now time.Time) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET cleanup_retried_at = $1
+		WHERE id = $2 AND cleanup_retried_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, now, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark job cleanup retry: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark job cleanup retry: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// SetInstructionsBySourceMessage заменяет указания к конспекту задач пользователя, созданных по сообщению
+// sourceMessageID, пока их краткое содержание не составлено: этап суммаризации читает указания из задачи
+func (r *JobRepositorySQLite) SetInstructionsBySourceMessage(ctx context.Context, userID int64, sourceMessageID int, instructions string) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET instructions = $1, updated_at = $2
+		WHERE user_id = $3
+			AND source_message_id = $4
+			AND status IN ('created', 'queued', 'pending', 'processing', 'transcribed')
+	`
+
+	result, err := r.db.Exec(ctx, query, instructions, time.Now(), userID, sourceMessageID)
+	if err != nil {
+		return false, fmt.Errorf("failed to set job instructions: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to set job instructions: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetLatestByFileUniqueID возвращает последнюю задачу пользователя без ошибки по постоянному идентификатору
+// файла в Telegram или nil, если такой задачи нет
+func (r *JobRepositorySQLite) GetLatestByFileUniqueID(ctx context.Context, userID int64, fileUniqueID string) (*entity.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs
+		WHERE user_id = $1 AND file_unique_id = $2 AND status <> 'failed'
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	job, err := scanJob(r.db.QueryRow(ctx, query, userID, fileUniqueID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job by file unique id: %w", err)
+	}
+
+	job.FileUniqueID = fileUniqueID
+	return job, nil
+}
+
+// GetLatestBySourceMessage возвращает последнюю задачу пользователя без ошибки, созданную по сообщению
+// sourceMessageID, или nil, если такой задачи нет
+func (r *JobRepositorySQLite) GetLatestBySourceMessage(ctx context.Context, userID int64, sourceMessageID int) (*entity.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs
+		WHERE user_id = $1 AND source_message_id = $2 AND status <> 'failed'
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	job, err := scanJob(r.db.QueryRow(ctx, query, userID, sourceMessageID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job by source message: %w", err)
+	}
+
+	return job, nil
+}
+
+// Delete удаляет задачу. Этапы, публичные ссылки, эмбеддинги и другие связанные записи удаляются каскадно
+func (r *JobRepositorySQLite) Delete(ctx context.Context, id int64) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM jobs WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// jobStageColumns - колонки этапа в порядке сканирования scanJobStages
+const jobStageColumns = `id, job_id, stage, status, attempts, COALESCE(error_message, ''),
+	COALESCE(external_calls, '[]'), started_at, finished_at, created_at, updated_at`
+
+// JobStageRepositorySQLite реализует интерфейс JobStageRepository для SQLite
+type JobStageRepositorySQLite struct {
+	db *DB
+}
+
+// NewJobStageRepository создает новый репозиторий для работы с этапами обработки задач
+func NewJobStageRepository(db *DB) repository.JobStageRepository {
+	return &JobStageRepositorySQLite{db: db}
+}
+
+// Start отмечает начало очередной попытки этапа
+func (r *JobStageRepositorySQLite) Start(ctx context.Context, jobID int64, stage entity.JobStageName) error {
+	query := `
+		INSERT INTO job_stages (job_id, stage, status, attempts, started_at, created_at, updated_at)
+		VALUES ($1, $2, $3, 1, $4, $4, $4)
+		ON CONFLICT (job_id, stage) DO UPDATE
+		SET status = excluded.status, attempts = job_stages.attempts + 1,
+			error_message = NULL, started_at = excluded.started_at, finished_at = NULL,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(ctx, query, jobID, stage, entity.JobStageStatusRunning, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to start job stage: %w", err)
+	}
+
+	return nil
+}
+
+// Finish отмечает завершение этапа с указанным статусом
+func (r *JobStageRepositorySQLite) Finish(ctx context.Context, jobID int64, stage entity.JobStageName, status entity.JobStageStatus, errorMessage string, calls []entity.ExternalCall) error {
+	if calls == nil {
+		calls = []entity.ExternalCall{}
+	}
+	callsJSON, err := json.Marshal(calls)
+	if err != nil {
+		return fmt.Errorf("failed to marshal external calls: %w", err)
+	}
+
+	query := `
+		UPDATE job_stages
+		SET status = $1, error_message = NULLIF($2, ''), external_calls = $3, finished_at = $4, updated_at = $4
+		WHERE job_id = $5 AND stage = $6
+	`
+
+	_, err = r.db.Exec(ctx, query, status, errorMessage, string(callsJSON), time.Now(), jobID, stage)
+	if err != nil {
+		return fmt.Errorf("failed to finish job stage: %w", err)
+	}
+
+	return nil
+}
+
+// GetByJobID возвращает этапы задачи в порядке их начала
+func (r *JobStageRepositorySQLite) GetByJobID(ctx context.Context, jobID int64) ([]*entity.JobStage, error) {
+	query := `
+		SELECT ` + jobStageColumns + `
+		FROM job_stages
+		WHERE job_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job stages: %w", err)
+	}
+	defer rows.Close()
+
+	return scanJobStages(rows)
+}
+
+// GetFailed возвращает проваленные этапы, завершенные в промежутке [since, until)
+func (r *JobStageRepositorySQLite) GetFailed(ctx context.Context, since, until time.Time) ([]*entity.JobStage, error) {
+	query := `
+		SELECT ` + jobStageColumns + `
+		FROM job_stages
+		WHERE status = $1 AND finished_at >= $2 AND finished_at < $3
+		ORDER BY finished_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, entity.JobStageStatusFailed, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failed job stages: %w", err)
+	}
+	defer rows.Close()
+
+	return scanJobStages(rows)
+}
+
+// scanJobStages читает этапы обработки задач из результата запроса
+func scanJobStages(rows *sql.Rows) ([]*entity.JobStage, error) {
+	stages := make([]*entity.JobStage, 0)
+	for rows.Next() {
+		stage := &entity.JobStage{}
+		var callsJSON string
+		err := rows.Scan(
+			&stage.ID,
+			&stage.JobID,
+			&stage.Stage,
+			&stage.Status,
+			&stage.Attempts,
+			&stage.ErrorMessage,
+			&callsJSON,
+			&stage.StartedAt,
+			&stage.FinishedAt,
+			&stage.CreatedAt,
+			&stage.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job stage: %w", err)
+		}
+		if err := json.Unmarshal([]byte(callsJSON), &stage.ExternalCalls); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal external calls: %w", err)
+		}
+		stages = append(stages, stage)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate job stages: %w", err)
+	}
+
+	return stages, nil
+}
@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// MeetingAgendaRepositorySQLite реализует интерфейс MeetingAgendaRepository для SQLite
+type MeetingAgendaRepositorySQLite struct {
+	db *DB
+}
+
+// NewMeetingAgendaRepository создает новый репозиторий для работы с повестками встреч
+func NewMeetingAgendaRepository(db *DB) repository.MeetingAgendaRepository {
+	return &MeetingAgendaRepositorySQLite{db: db}
+}
+
+// SetPending сохраняет ожидающую повестку пользователя, заменяя прежнюю
+func (r *MeetingAgendaRepositorySQLite) SetPending(ctx context.Context, agenda *entity.MeetingAgenda) error {
+	agenda.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO meeting_agendas (user_id, agenda, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) WHERE job_id IS NULL DO UPDATE
+		SET agenda = excluded.agenda, created_at = excluded.created_at
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(ctx, query, agenda.UserID, agenda.Text, agenda.CreatedAt).Scan(&agenda.ID)
+	if err != nil {
+		return fmt.Errorf("failed to set meeting agenda: %w", err)
+	}
+
+	agenda.JobID = nil
+	return nil
+}
+
+// GetPending возвращает ожидающую повестку пользователя, сохраненную после createdAfter, или nil
+func (r *MeetingAgendaRepositorySQLite) GetPending(ctx context.Context, userID int64, createdAfter time.Time) (*entity.MeetingAgenda, error) {
+	query := `
+		SELECT id, user_id, job_id, agenda, created_at
+		FROM meeting_agendas
+		WHERE user_id = $1 AND job_id IS NULL AND created_at > $2
+	`
+
+	return r.scan(r.db.QueryRow(ctx, query, userID, createdAfter))
+}
+
+// DeletePending удаляет ожидающую повестку пользователя и сообщает, была ли она
+func (r *MeetingAgendaRepositorySQLite) DeletePending(ctx context.Context, userID int64) (bool, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM meeting_agendas WHERE user_id = $1 AND job_id IS NULL`, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete meeting agenda: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete meeting agenda: %w", err)
+	}
+
+	return deleted > 0, nil
+}
+
+// AttachPending привязывает к задаче ожидающую повестку пользователя, сохраненную после createdAfter
+func (r *MeetingAgendaRepositorySQLite) AttachPending(ctx context.Context, userID, jobID int64, createdAfter time.Time) (*entity.MeetingAgenda, error) {
+	query := `
+		UPDATE meeting_agendas
+		SET job_id = $2
+		WHERE user_id = $1 AND job_id IS NULL AND created_at > $3
+		RETURNING id, user_id, job_id, agenda, created_at
+	`
+
+	return r.scan(r.db.QueryRow(ctx, query, userID, jobID, createdAfter))
+}
+
+// GetByJobID возвращает повестку задачи или nil
+func (r *MeetingAgendaRepositorySQLite) GetByJobID(ctx context.Context, jobID int64) (*entity.MeetingAgenda, error) {
+	query := `
+		SELECT id, user_id, job_id, agenda, created_at
+		FROM meeting_agendas
+		WHERE job_id = $1
+	`
+
+	return r.scan(r.db.QueryRow(ctx, query, jobID))
+}
+
+// scan считывает повестку из строки результата; отсутствие строки не считается ошибкой
+func (r *MeetingAgendaRepositorySQLite) scan(row *sql.Row) (*entity.MeetingAgenda, error) {
+	agenda := &entity.MeetingAgenda{}
+	err := row.Scan(&agenda.ID, &agenda.UserID, &agenda.JobID, &agenda.Text, &agenda.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get meeting agenda: %w", err)
+	}
+
+	return agenda, nil
+}
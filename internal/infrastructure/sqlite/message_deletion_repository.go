@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// MessageDeletionRepositorySQLite реализует интерфейс MessageDeletionRepository для SQLite
+type MessageDeletionRepositorySQLite struct {
+	db *DB
+}
+
+// NewMessageDeletionRepository создает новый репозиторий для работы с запланированными удалениями сообщений
+func NewMessageDeletionRepository(db *DB) repository.MessageDeletionRepository {
+	return &MessageDeletionRepositorySQLite{db: db}
+}
+
+// Schedule планирует удаление сообщения; повторное планирование переносит время удаления
+func (r *MessageDeletionRepositorySQLite) Schedule(ctx context.Context, deletion entity.ScheduledDeletion) error {
+	query := `
+		INSERT INTO message_deletions (chat_id, message_id, delete_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (chat_id, message_id) DO UPDATE
+		SET delete_at = excluded.delete_at
+	`
+
+	_, err := r.db.Exec(ctx, query, deletion.ChatID, deletion.MessageID, deletion.DeleteAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to schedule message deletion: %w", err)
+	}
+
+	return nil
+}
+
+// PopDue извлекает удаления, время которых наступило к указанному моменту
+func (r *MessageDeletionRepositorySQLite) PopDue(ctx context.Context, now time.Time) ([]entity.ScheduledDeletion, error) {
+	rows, err := r.db.Query(ctx, `DELETE FROM message_deletions WHERE delete_at <= $1 RETURNING chat_id, message_id`, now.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due message deletions: %w", err)
+	}
+	defer rows.Close()
+
+	deletions := make([]entity.ScheduledDeletion, 0)
+	for rows.Next() {
+		deletion := entity.ScheduledDeletion{DeleteAt: now}
+		if err := rows.Scan(&deletion.ChatID, &deletion.MessageID); err != nil {
+			return deletions, fmt.Errorf("failed to scan message deletion: %w", err)
+		}
+		deletions = append(deletions, deletion)
+	}
+
+	if err := rows.Err(); err != nil {
+		return deletions, fmt.Errorf("failed to iterate message deletions: %w", err)
+	}
+
+	return deletions, nil
+}
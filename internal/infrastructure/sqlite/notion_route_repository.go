@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// NotionRouteRepositorySQLite реализует интерфейс NotionRouteRepository для SQLite
+type NotionRouteRepositorySQLite struct {
+	db *DB
+}
+
+// NewNotionRouteRepository создает новый репозиторий для работы с правилами выгрузки в Notion по темам записей
+func NewNotionRouteRepository(db *DB) repository.NotionRouteRepository {
+	return &NotionRouteRepositorySQLite{db: db}
+}
+
+// Create создает правило
+func (r *NotionRouteRepositorySQLite) Create(ctx context.Context, route *entity.NotionRoute) error {
+	route.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO notion_routes (user_id, category, database_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(ctx, query, route.UserID, route.Category, route.DatabaseID, route.CreatedAt).Scan(&route.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create notion route: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID возвращает правила пользователя в порядке создания
+func (r *NotionRouteRepositorySQLite) GetByUserID(ctx context.Context, userID int64) ([]*entity.NotionRoute, error) {
+	query := `
+		SELECT id, user_id, category, database_id, created_at
+		FROM notion_routes
+		WHERE user_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notion routes: %w", err)
+	}
+	defer rows.Close()
+
+	routes := make([]*entity.NotionRoute, 0)
+	for rows.Next() {
+		route := &entity.NotionRoute{}
+		if err := rows.Scan(&route.ID, &route.UserID, &route.Category, &route.DatabaseID, &route.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notion route: %w", err)
+		}
+		routes = append(routes, route)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate notion routes: %w", err)
+	}
+
+	return routes, nil
+}
+
+// Delete удаляет правило пользователя. Возвращает false, если такого правила нет
+func (r *NotionRouteRepositorySQLite) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	query := `
+		DELETE FROM notion_routes
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete notion route: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete notion route: %w", err)
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,214 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// episodeInsertQuery - запрос учета выпуска; для уже учтенного выпуска не возвращает строк
+const episodeInsertQuery = `
+	INSERT INTO podcast_episodes (feed_id, user_id, guid, title, audio_url, published_at, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (user_id, guid) DO NOTHING
+	RETURNING id
+`
+
+// PodcastRepositorySQLite реализует интерфейс PodcastRepository для SQLite
+type PodcastRepositorySQLite struct {
+	db *DB
+}
+
+// NewPodcastRepository создает новый репозиторий для работы с подписками на подкасты
+func NewPodcastRepository(db *DB) repository.PodcastRepository {
+	return &PodcastRepositorySQLite{db: db}
+}
+
+// CreateFeed создает подписку на ленту
+func (r *PodcastRepositorySQLite) CreateFeed(ctx context.Context, feed *entity.PodcastFeed) error {
+	feed.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO podcast_feeds (user_id, url, title, notion_database_id, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		ON CONFLICT (user_id, url) DO NOTHING
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		feed.UserID,
+		feed.URL,
+		feed.Title,
+		feed.NotionDatabaseID,
+		feed.CreatedAt,
+	).Scan(&feed.ID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("podcast feed already exists")
+		}
+		return fmt.Errorf("failed to create podcast feed: %w", err)
+	}
+
+	return nil
+}
+
+// GetFeedsByUserID возвращает подписки пользователя
+func (r *PodcastRepositorySQLite) GetFeedsByUserID(ctx context.Context, userID int64) ([]*entity.PodcastFeed, error) {
+	query := `
+		SELECT id, user_id, url, title, COALESCE(notion_database_id, ''), last_checked_at, created_at
+		FROM podcast_feeds
+		WHERE user_id = $1
+		ORDER BY id
+	`
+
+	return r.getFeeds(ctx, query, userID)
+}
+
+// GetAllFeeds возвращает подписки всех пользователей
+func (r *PodcastRepositorySQLite) GetAllFeeds(ctx context.Context) ([]*entity.PodcastFeed, error) {
+	query := `
+		SELECT id, user_id, url, title, COALESCE(notion_database_id, ''), last_checked_at, created_at
+		FROM podcast_feeds
+		ORDER BY id
+	`
+
+	return r.getFeeds(ctx, query)
+}
+
+// getFeeds выполняет запрос на получение списка подписок
+func (r *PodcastRepositorySQLite) getFeeds(ctx context.Context, query string, args ...interface{}) ([]*entity.PodcastFeed, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get podcast feeds: %w", err)
+	}
+	defer rows.Close()
+
+	feeds := make([]*entity.PodcastFeed, 0)
+	for rows.Next() {
+		feed := &entity.PodcastFeed{}
+		err := rows.Scan(
+			&feed.ID,
+			&feed.UserID,
+			&feed.URL,
+			&feed.Title,
+			&feed.NotionDatabaseID,
+			&feed.LastCheckedAt,
+			&feed.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan podcast feed: %w", err)
+		}
+		feeds = append(feeds, feed)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate podcast feeds: %w", err)
+	}
+
+	return feeds, nil
+}
+
+// DeleteFeed удаляет подписку пользователя и сообщает, была ли она найдена
+func (r *PodcastRepositorySQLite) DeleteFeed(ctx context.Context, userID, feedID int64) (bool, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM podcast_feeds WHERE id = $1 AND user_id = $2`, feedID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete podcast feed: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete podcast feed: %w", err)
+	}
+
+	return deleted > 0, nil
+}
+
+// SetFeedChecked отмечает время последней проверки ленты
+func (r *PodcastRepositorySQLite) SetFeedChecked(ctx context.Context, feedID int64, checkedAt time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE podcast_feeds SET last_checked_at = $1 WHERE id = $2`, checkedAt, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to update podcast feed: %w", err)
+	}
+
+	return nil
+}
+
+// AddEpisode учитывает выпуск для пользователя и возвращает false, если он уже был учтен
+func (r *PodcastRepositorySQLite) AddEpisode(ctx context.Context, episode *entity.PodcastEpisode) (bool, error) {
+	episode.CreatedAt = time.Now()
+
+	err := r.db.QueryRow(ctx, episodeInsertQuery, episodeInsertArgs(episode)...).Scan(&episode.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to add podcast episode: %w", err)
+	}
+
+	return true, nil
+}
+
+// AddEpisodes учитывает выпуски в одной транзакции и возвращает число новых выпусков.
+// ID заполняется только у новых выпусков; уже учтенные выпуски пропускаются, как в AddEpisode
+func (r *PodcastRepositorySQLite) AddEpisodes(ctx context.Context, episodes []*entity.PodcastEpisode) (int, error) {
+	if len(episodes) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	added := 0
+	for _, episode := range episodes {
+		episode.CreatedAt = now
+		err := tx.QueryRow(ctx, episodeInsertQuery, episodeInsertArgs(episode)...).Scan(&episode.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return 0, fmt.Errorf("failed to add podcast episode: %w", err)
+		}
+		added++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return added, nil
+}
+
+// episodeInsertArgs возвращает аргументы episodeInsertQuery
+func episodeInsertArgs(episode *entity.PodcastEpisode) []interface{} {
+	return []interface{}{
+		episode.FeedID,
+		episode.UserID,
+		episode.GUID,
+		episode.Title,
+		episode.AudioURL,
+		episode.PublishedAt,
+		episode.CreatedAt,
+	}
+}
+
+// SetEpisodeJob связывает выпуск с задачей обработки
+func (r *PodcastRepositorySQLite) SetEpisodeJob(ctx context.Context, episodeID, jobID int64) error {
+	_, err := r.db.Exec(ctx, `UPDATE podcast_episodes SET job_id = $1 WHERE id = $2`, jobID, episodeID)
+	if err != nil {
+		return fmt.Errorf("failed to set podcast episode job: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// ReadingLessonRepositorySQLite реализует интерфейс ReadingLessonRepository для SQLite
+type ReadingLessonRepositorySQLite struct {
+	db *DB
+}
+
+// NewReadingLessonRepository создает новый репозиторий для работы с уроками чтения вслух
+func NewReadingLessonRepository(db *DB) repository.ReadingLessonRepository {
+	return &ReadingLessonRepositorySQLite{db: db}
+}
+
+// Set начинает урок пользователя, заменяя предыдущий
+func (r *ReadingLessonRepositorySQLite) Set(ctx context.Context, lesson *entity.ReadingLesson) error {
+	lesson.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO reading_lessons (user_id, reference_text, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET reference_text = excluded.reference_text, created_at = excluded.created_at
+	`
+
+	_, err := r.db.Exec(ctx, query, lesson.UserID, lesson.ReferenceText, lesson.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set reading lesson: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID возвращает активный урок пользователя или nil
+func (r *ReadingLessonRepositorySQLite) GetByUserID(ctx context.Context, userID int64) (*entity.ReadingLesson, error) {
+	query := `
+		SELECT user_id, reference_text, created_at
+		FROM reading_lessons
+		WHERE user_id = $1
+	`
+
+	lesson := &entity.ReadingLesson{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(&lesson.UserID, &lesson.ReferenceText, &lesson.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get reading lesson: %w", err)
+	}
+
+	return lesson, nil
+}
+
+// Delete завершает урок пользователя и сообщает, был ли он активен
+func (r *ReadingLessonRepositorySQLite) Delete(ctx context.Context, userID int64) (bool, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM reading_lessons WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete reading lesson: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete reading lesson: %w", err)
+	}
+
+	return deleted > 0, nil
+}
@@ -0,0 +1,428 @@
+package sqlite
+
+// schemaMigrations - шаги схемы базы данных SQLite в порядке применения. Шаги только добавляются
+// в конец: изменения схемы PostgreSQL из migrations/ переносятся сюда новым шагом
+var schemaMigrations = []string{
+	// Начальная схема, соответствующая configs/init.sql
+	`
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    telegram_id INTEGER UNIQUE NOT NULL,
+    username TEXT,
+    first_name TEXT,
+    last_name TEXT,
+    notion_token TEXT,
+    notion_database_id TEXT,
+    notion_hub_page_id TEXT,
+    result_retention_hours INTEGER NOT NULL DEFAULT 0,
+    transcription_options TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL DEFAULT 'transcription',
+    audio_file_path TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    duration INTEGER,
+    status TEXT NOT NULL DEFAULT 'pending',
+    transcription TEXT,
+    summary TEXT,
+    notion_page_id TEXT,
+    notion_database_id TEXT,
+    source_message_id INTEGER,
+    instructions TEXT,
+    notion_unlinked_at TIMESTAMP,
+    audio_profile TEXT NOT NULL DEFAULT 'auto',
+    summary_model TEXT,
+    summary_cost REAL,
+    error_message TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_user_id ON jobs(user_id);
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status);
+
+CREATE TABLE IF NOT EXISTS share_links (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token TEXT UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_links_job_id ON share_links(job_id);
+
+CREATE TABLE IF NOT EXISTS job_stages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    stage TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT,
+    external_calls TEXT NOT NULL DEFAULT '[]',
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (job_id, stage)
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_stages_failed_finished_at ON job_stages(finished_at) WHERE status = 'failed';
+
+-- Векторы хранятся как float32 в порядке little-endian; близость считается в приложении
+CREATE TABLE IF NOT EXISTS note_embeddings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    chunk_index INTEGER NOT NULL DEFAULT 0,
+    content TEXT NOT NULL,
+    embedding BLOB NOT NULL,
+    created_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_embeddings_user_id ON note_embeddings(user_id);
+CREATE INDEX IF NOT EXISTS idx_note_embeddings_job_id ON note_embeddings(job_id);
+
+CREATE TABLE IF NOT EXISTS podcast_feeds (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    notion_database_id TEXT,
+    last_checked_at TIMESTAMP,
+    created_at TIMESTAMP,
+    UNIQUE (user_id, url)
+);
+
+CREATE TABLE IF NOT EXISTS podcast_episodes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    feed_id INTEGER REFERENCES podcast_feeds(id) ON DELETE SET NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    guid TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    audio_url TEXT NOT NULL,
+    published_at TIMESTAMP,
+    job_id INTEGER REFERENCES jobs(id) ON DELETE SET NULL,
+    created_at TIMESTAMP,
+    UNIQUE (user_id, guid)
+);
+
+CREATE TABLE IF NOT EXISTS transcript_segments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    start_seconds INTEGER,
+    text TEXT NOT NULL,
+    UNIQUE (job_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS job_sync_results (
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    target TEXT NOT NULL,
+    status TEXT NOT NULL,
+    external_id TEXT,
+    error_message TEXT,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (job_id, target)
+);
+
+CREATE TABLE IF NOT EXISTS teams (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invite_code TEXT UNIQUE NOT NULL,
+    channel_id INTEGER,
+    digest_hour INTEGER NOT NULL DEFAULT 9,
+    last_digest_at TIMESTAMP,
+    created_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    joined_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_team_id ON team_members(team_id);
+
+CREATE TABLE IF NOT EXISTS reading_lessons (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    reference_text TEXT NOT NULL,
+    created_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS job_attachments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    token TEXT UNIQUE NOT NULL,
+    content_type TEXT NOT NULL,
+    image BLOB NOT NULL,
+    ocr_text TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_attachments_job_id ON job_attachments(job_id);
+
+-- События и заголовки хранятся в JSON
+CREATE TABLE IF NOT EXISTS user_webhooks (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    events TEXT NOT NULL DEFAULT '[]',
+    headers TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS failure_rollups (
+    day DATE NOT NULL,
+    stage TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    category TEXT NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP,
+    PRIMARY KEY (day, stage, provider, category)
+);
+
+-- Запланированные удаления сообщений (в режиме PostgreSQL хранятся в Redis); время - Unix-секунды
+CREATE TABLE IF NOT EXISTS message_deletions (
+    chat_id INTEGER NOT NULL,
+    message_id INTEGER NOT NULL,
+    delete_at INTEGER NOT NULL,
+    PRIMARY KEY (chat_id, message_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_deletions_delete_at ON message_deletions(delete_at);
+`,
+	// 000027: язык кратких содержаний пользователя
+	`ALTER TABLE users ADD COLUMN summary_language TEXT;`,
+	// 000028: сообщения о ходе длинных задач
+	`
+ALTER TABLE jobs ADD COLUMN keepalive_message_id INTEGER;
+ALTER TABLE jobs ADD COLUMN keepalive_at TIMESTAMP;
+`,
+	// 000029: журнал действий администраторов
+	`
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    admin_telegram_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    target_telegram_id INTEGER,
+    details TEXT,
+    created_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_created_at ON admin_audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_target ON admin_audit_log(target_telegram_id);
+`,
+	// 000030: главы видео
+	`
+CREATE TABLE IF NOT EXISTS video_chapters (
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    start_seconds INTEGER NOT NULL,
+    end_seconds INTEGER NOT NULL,
+    PRIMARY KEY (job_id, position)
+);
+`,
+	// 000031: повестки встреч
+	`
+CREATE TABLE IF NOT EXISTS meeting_agendas (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job_id INTEGER UNIQUE REFERENCES jobs(id) ON DELETE CASCADE,
+    agenda TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_meeting_agendas_pending ON meeting_agendas(user_id) WHERE job_id IS NULL;
+`,
+	// 000032: настройки пользователей
+	`
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    transcription_language TEXT NOT NULL DEFAULT '',
+    summary_style TEXT NOT NULL DEFAULT 'standard',
+    notion_auto_sync BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	// 000033: ежедневный разбор задач с ошибками
+	`
+ALTER TABLE user_settings ADD COLUMN failed_jobs_cleanup BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_settings ADD COLUMN failed_jobs_cleanup_at TIMESTAMP;
+ALTER TABLE jobs ADD COLUMN cleanup_retried_at TIMESTAMP;
+`,
+	// 000034: постоянный идентификатор файла задачи в Telegram
+	`
+ALTER TABLE jobs ADD COLUMN file_unique_id TEXT;
+CREATE INDEX IF NOT EXISTS idx_jobs_user_file_unique_id ON jobs(user_id, file_unique_id) WHERE file_unique_id IS NOT NULL;
+`,
+	// 000035: обезличенные события использования бота
+	`
+CREATE TABLE IF NOT EXISTS usage_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_key TEXT NOT NULL,
+    event TEXT NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_usage_events_created_at ON usage_events(created_at);
+CREATE INDEX IF NOT EXISTS idx_usage_events_user_key ON usage_events(user_key);
+ALTER TABLE user_settings ADD COLUMN usage_analytics_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
+`,
+	// 000036: этап, на котором задача завершилась ошибкой
+	`ALTER TABLE jobs ADD COLUMN failed_stage TEXT;`,
+	// 000037: ход выгрузки содержимого задачи в Notion
+	`
+ALTER TABLE jobs ADD COLUMN notion_upload_page_id TEXT;
+ALTER TABLE jobs ADD COLUMN notion_upload_cursor INTEGER NOT NULL DEFAULT 0;
+`,
+	// 000038: сообщение о ходе обработки задачи
+	`ALTER TABLE jobs ADD COLUMN progress_message_id INTEGER;`,
+	// 000039: язык интерфейса бота
+	`
+ALTER TABLE users ADD COLUMN language_code TEXT;
+ALTER TABLE user_settings ADD COLUMN interface_language TEXT;
+`,
+	// 000040: тип чата пользователя
+	`
+ALTER TABLE users ADD COLUMN chat_type TEXT NOT NULL DEFAULT 'private';
+UPDATE users SET chat_type = 'group' WHERE telegram_id < 0;
+`,
+	// 000041: число токенов суммаризации задачи
+	`
+ALTER TABLE jobs ADD COLUMN summary_tokens INTEGER NOT NULL DEFAULT 0;
+`,
+	// 000042: сжатые транскрипция и резюме задачи. В SQLite тексты хранятся несжатыми,
+	// колонки добавлены для совпадения схем
+	`
+ALTER TABLE jobs ADD COLUMN transcription_gz BLOB;
+ALTER TABLE jobs ADD COLUMN summary_gz BLOB;
+`,
+	// 000043: разборы настроения встреч
+	`
+CREATE TABLE IF NOT EXISTS job_sentiments (
+    job_id INTEGER PRIMARY KEY REFERENCES jobs(id) ON DELETE CASCADE,
+    mood TEXT NOT NULL,
+    tone TEXT NOT NULL DEFAULT '',
+    tension_points TEXT NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE user_settings ADD COLUMN sentiment_analysis BOOLEAN NOT NULL DEFAULT FALSE;
+`,
+	// 000044: стиль краткого содержания задачи и запомненный выбор стиля
+	`
+ALTER TABLE jobs ADD COLUMN summary_style TEXT;
+ALTER TABLE user_settings ADD COLUMN summary_style_remembered BOOLEAN NOT NULL DEFAULT FALSE;
+`,
+	// 000045: индекс задач по исходному сообщению в Telegram
+	`
+CREATE INDEX IF NOT EXISTS idx_jobs_user_source_message_id ON jobs(user_id, source_message_id) WHERE source_message_id IS NOT NULL;
+`,
+	// 000046: сравнения моделей суммаризации
+	`
+CREATE TABLE IF NOT EXISTS model_comparisons (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    model_a TEXT NOT NULL,
+    model_b TEXT NOT NULL,
+    vote TEXT,
+    created_at TIMESTAMP,
+    voted_at TIMESTAMP
+);
+`,
+	// 000047: администраторы бота и блокировка пользователей
+	`
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN banned_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN ban_reason TEXT;
+`,
+	// 000048: звонки из вебхуков VoIP провайдеров
+	`
+CREATE TABLE IF NOT EXISTS call_recordings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    provider TEXT NOT NULL,
+    call_id TEXT NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job_id INTEGER REFERENCES jobs(id) ON DELETE SET NULL,
+    direction TEXT,
+    caller TEXT,
+    callee TEXT,
+    started_at TIMESTAMP,
+    duration INTEGER NOT NULL DEFAULT 0,
+    recording_received_at TIMESTAMP,
+    created_at TIMESTAMP,
+    UNIQUE (provider, call_id)
+);
+CREATE INDEX IF NOT EXISTS idx_call_recordings_job_id ON call_recordings(job_id);
+`,
+	// 000049: настройки содержимого страниц задач в Notion
+	`
+ALTER TABLE user_settings ADD COLUMN notion_transcript BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE user_settings ADD COLUMN notion_summary_only BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_settings ADD COLUMN notion_audio_stats BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_settings ADD COLUMN notion_usage_cost BOOLEAN NOT NULL DEFAULT FALSE;
+`,
+	// 000050: правила выгрузки в Notion по темам записей
+	`
+CREATE TABLE IF NOT EXISTS notion_routes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category TEXT NOT NULL,
+    database_id TEXT NOT NULL,
+    created_at TIMESTAMP,
+    UNIQUE (user_id, category)
+);
+`,
+	// 000051: индекс логов задач
+	`
+CREATE TABLE IF NOT EXISTS job_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    level TEXT NOT NULL,
+    message TEXT NOT NULL,
+    attrs TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_job_logs_job_id ON job_logs(job_id, id);
+CREATE INDEX IF NOT EXISTS idx_job_logs_created_at ON job_logs(created_at);
+`,
+	// 000052: live-сессии
+	`
+CREATE TABLE IF NOT EXISTS live_sessions (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    message_id INTEGER NOT NULL DEFAULT 0,
+    transcript TEXT NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    segments INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	// 000053: подготовленный к распознаванию WAV задачи
+	`
+ALTER TABLE jobs ADD COLUMN processed_audio_path TEXT;
+ALTER TABLE jobs ADD COLUMN processed_audio_profile TEXT;
+ALTER TABLE jobs ADD COLUMN processed_audio_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_jobs_processed_audio_at ON jobs(processed_audio_at) WHERE processed_audio_at IS NOT NULL;
+`,
+	// 000054: поиск повторно присланных записей по сходству транскрипций
+	`
+ALTER TABLE jobs ADD COLUMN transcript_minhash TEXT;
+ALTER TABLE jobs ADD COLUMN duplicate_of_job_id INTEGER REFERENCES jobs(id) ON DELETE SET NULL;
+ALTER TABLE jobs ADD COLUMN duplicate_similarity REAL;
+`,
+	// 000055: фразы транскрипции с говорящими
+	`
+ALTER TABLE jobs ADD COLUMN speaker_segments TEXT;
+`,
+}
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// SyncResultRepositorySQLite реализует интерфейс SyncResultRepository для SQLite
+type SyncResultRepositorySQLite struct {
+	db *DB
+}
+
+// NewSyncResultRepository создает новый репозиторий для работы с результатами выгрузки задач
+func NewSyncResultRepository(db *DB) repository.SyncResultRepository {
+	return &SyncResultRepositorySQLite{db: db}
+}
+
+// Save сохраняет результат выгрузки задачи, заменяя предыдущий результат для того же места
+func (r *SyncResultRepositorySQLite) Save(ctx context.Context, result *entity.SyncResult) error {
+	result.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO job_sync_results (job_id, target, status, external_id, error_message, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6)
+		ON CONFLICT (job_id, target) DO UPDATE
+		SET status = excluded.status,
+			external_id = excluded.external_id,
+			error_message = excluded.error_message,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		result.JobID,
+		result.Target,
+		result.Status,
+		result.ExternalID,
+		result.ErrorMessage,
+		result.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save sync result: %w", err)
+	}
+
+	return nil
+}
+
+// GetByJobID возвращает результаты выгрузки задачи в порядке мест синхронизации
+func (r *SyncResultRepositorySQLite) GetByJobID(ctx context.Context, jobID int64) ([]*entity.SyncResult, error) {
+	query := `
+		SELECT job_id, target, status, COALESCE(external_id, ''), COALESCE(error_message, ''), updated_at
+		FROM job_sync_results
+		WHERE job_id = $1
+		ORDER BY target
+	`
+
+	rows, err := r.db.Query(ctx, query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync results: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*entity.SyncResult, 0)
+	for rows.Next() {
+		result := &entity.SyncResult{}
+		err := rows.Scan(
+			&result.JobID,
+			&result.Target,
+			&result.Status,
+			&result.ExternalID,
+			&result.ErrorMessage,
+			&result.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sync result: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sync results: %w", err)
+	}
+
+	return results, nil
+}
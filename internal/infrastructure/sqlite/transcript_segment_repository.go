@@ -0,0 +1,167 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// TranscriptSegmentRepositorySQLite реализует интерфейс TranscriptSegmentRepository для SQLite
+type TranscriptSegmentRepositorySQLite struct {
+	db *DB
+}
+
+// NewTranscriptSegmentRepository создает новый репозиторий для работы с фрагментами транскрипций
+func NewTranscriptSegmentRepository(db *DB) repository.TranscriptSegmentRepository {
+	return &TranscriptSegmentRepositorySQLite{db: db}
+}
+
+// ReplaceForJob заменяет все фрагменты транскрипции задачи переданными
+func (r *TranscriptSegmentRepositorySQLite) ReplaceForJob(ctx context.Context, jobID int64, segments []*entity.TranscriptSegment) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Удаление старых фрагментов задачи
+	_, err = tx.Exec(ctx, `DELETE FROM transcript_segments WHERE job_id = $1`, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to delete transcript segments: %w", err)
+	}
+
+	query := `
+		INSERT INTO transcript_segments (job_id, position, start_seconds, text)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	for _, segment := range segments {
+		segment.JobID = jobID
+		err = tx.QueryRow(
+			ctx,
+			query,
+			jobID,
+			segment.Position,
+			segment.StartSeconds,
+			segment.Text,
+		).Scan(&segment.ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert transcript segment: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CountByJobID возвращает число фрагментов транскрипции задачи
+func (r *TranscriptSegmentRepositorySQLite) CountByJobID(ctx context.Context, jobID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM transcript_segments WHERE job_id = $1`, jobID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count transcript segments: %w", err)
+	}
+
+	return count, nil
+}
+
+// Search возвращает фрагменты транскрипции задачи, содержащие фразу без учета регистра,
+// вместе с предыдущим и следующим фрагментами. LIKE и lower() в SQLite не учитывают регистр
+// только для латиницы, поэтому фрагменты задачи сравниваются с фразой в приложении
+func (r *TranscriptSegmentRepositorySQLite) Search(ctx context.Context, jobID int64, phrase string, limit int) ([]*entity.TranscriptExcerpt, error) {
+	segments, err := r.getSegments(ctx, `SELECT id, job_id, position, start_seconds, text FROM transcript_segments WHERE job_id = $1 ORDER BY position`, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transcript segments: %w", err)
+	}
+
+	byPosition := make(map[int]string, len(segments))
+	for _, segment := range segments {
+		byPosition[segment.Position] = segment.Text
+	}
+
+	phrase = strings.ToLower(phrase)
+	excerpts := make([]*entity.TranscriptExcerpt, 0)
+	for _, segment := range segments {
+		if len(excerpts) >= limit {
+			break
+		}
+		if !strings.Contains(strings.ToLower(segment.Text), phrase) {
+			continue
+		}
+		excerpts = append(excerpts, &entity.TranscriptExcerpt{
+			StartSeconds: segment.StartSeconds,
+			Before:       byPosition[segment.Position-1],
+			Text:         segment.Text,
+			After:        byPosition[segment.Position+1],
+		})
+	}
+
+	return excerpts, nil
+}
+
+// GetRange возвращает фрагменты транскрипции задачи, начинающиеся в промежутке [fromSeconds, toSeconds),
+// в порядке следования
+func (r *TranscriptSegmentRepositorySQLite) GetRange(ctx context.Context, jobID int64, fromSeconds, toSeconds int) ([]*entity.TranscriptSegment, error) {
+	query := `
+		SELECT id, job_id, position, start_seconds, text
+		FROM transcript_segments
+		WHERE job_id = $1 AND start_seconds >= $2 AND start_seconds < $3
+		ORDER BY position
+	`
+
+	segments, err := r.getSegments(ctx, query, jobID, fromSeconds, toSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcript segments: %w", err)
+	}
+
+	return segments, nil
+}
+
+// LastStartSeconds возвращает начало последнего фрагмента с меткой времени или nil, если меток времени нет
+func (r *TranscriptSegmentRepositorySQLite) LastStartSeconds(ctx context.Context, jobID int64) (*int, error) {
+	var lastStart *int
+	err := r.db.QueryRow(ctx, `SELECT MAX(start_seconds) FROM transcript_segments WHERE job_id = $1`, jobID).Scan(&lastStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last transcript segment start: %w", err)
+	}
+
+	return lastStart, nil
+}
+
+// getSegments выполняет запрос на получение списка фрагментов
+func (r *TranscriptSegmentRepositorySQLite) getSegments(ctx context.Context, query string, args ...interface{}) ([]*entity.TranscriptSegment, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	segments := make([]*entity.TranscriptSegment, 0)
+	for rows.Next() {
+		segment := &entity.TranscriptSegment{}
+		err := rows.Scan(
+			&segment.ID,
+			&segment.JobID,
+			&segment.Position,
+			&segment.StartSeconds,
+			&segment.Text,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transcript segment: %w", err)
+		}
+		segments = append(segments, segment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate transcript segments: %w", err)
+	}
+
+	return segments, nil
+}
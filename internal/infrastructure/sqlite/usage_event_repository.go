@@ -0,0 +1,131 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// UsageEventRepositorySQLite реализует интерфейс UsageEventRepository для SQLite
+type UsageEventRepositorySQLite struct {
+	db *DB
+}
+
+// NewUsageEventRepository создает новый репозиторий для работы с событиями использования бота
+func NewUsageEventRepository(db *DB) repository.UsageEventRepository {
+	return &UsageEventRepositorySQLite{db: db}
+}
+
+// Create добавляет событие
+func (r *UsageEventRepositorySQLite) Create(ctx context.Context, event *entity.UsageEvent) error {
+	event.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO usage_events (user_key, event, detail, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		event.UserKey,
+		event.Event,
+		event.Detail,
+		event.CreatedAt,
+	).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create usage event: %w", err)
+	}
+
+	return nil
+}
+
+// CountSince возвращает число событий и пользователей по виду события и уточнению начиная с since
+func (r *UsageEventRepositorySQLite) CountSince(ctx context.Context, since time.Time) ([]*entity.UsageEventCount, error) {
+	query := `
+		SELECT event, detail, COUNT(*), COUNT(DISTINCT user_key)
+		FROM usage_events
+		WHERE created_at >= $1
+		GROUP BY event, detail
+		ORDER BY event, COUNT(*) DESC, detail
+	`
+
+	rows, err := r.db.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count usage events: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make([]*entity.UsageEventCount, 0)
+	for rows.Next() {
+		count := &entity.UsageEventCount{}
+		err := rows.Scan(
+			&count.Event,
+			&count.Detail,
+			&count.Events,
+			&count.Users,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan usage event count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate usage event counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetFunnelSince возвращает воронку пользователей, выполнивших /start начиная с since: учитываются только
+// задачи, созданные после первого /start за период, и завершения после первой такой задачи
+func (r *UsageEventRepositorySQLite) GetFunnelSince(ctx context.Context, since time.Time) (*entity.UsageFunnel, error) {
+	query := `
+		WITH started AS (
+			SELECT user_key, MIN(created_at) AS at
+			FROM usage_events
+			WHERE event = $2 AND created_at >= $1
+			GROUP BY user_key
+		), created AS (
+			SELECT s.user_key, MIN(e.created_at) AS at
+			FROM started s
+			JOIN usage_events e ON e.user_key = s.user_key AND e.event = $3 AND e.created_at >= s.at
+			GROUP BY s.user_key
+		), completed AS (
+			SELECT DISTINCT c.user_key
+			FROM created c
+			JOIN usage_events e ON e.user_key = c.user_key AND e.event = $4 AND e.created_at >= c.at
+		)
+		SELECT (SELECT COUNT(*) FROM started), (SELECT COUNT(*) FROM created), (SELECT COUNT(*) FROM completed)
+	`
+
+	funnel := &entity.UsageFunnel{}
+	err := r.db.QueryRow(ctx, query,
+		since,
+		entity.UsageEventStart,
+		entity.UsageEventJobCreated,
+		entity.UsageEventJobCompleted,
+	).Scan(
+		&funnel.Started,
+		&funnel.CreatedJob,
+		&funnel.CompletedJob,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage funnel: %w", err)
+	}
+
+	return funnel, nil
+}
+
+// DeleteByUserKey удаляет все события пользователя
+func (r *UsageEventRepositorySQLite) DeleteByUserKey(ctx context.Context, userKey string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM usage_events WHERE user_key = $1`, userKey)
+	if err != nil {
+		return fmt.Errorf("failed to delete usage events: %w", err)
+	}
+
+	return nil
+}
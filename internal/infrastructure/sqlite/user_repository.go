@@ -0,0 +1,283 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// userColumns - колонки пользователя в порядке сканирования getUser
+const userColumns = `id, telegram_id, COALESCE(username, ''), COALESCE(first_name, ''), COALESCE(last_name, ''),
+	COALESCE(notion_token, ''), COALESCE(notion_database_id, ''), COALESCE(notion_hub_page_id, ''),
+	result_retention_hours, transcription_options, COALESCE(summary_language, ''),
+	COALESCE(language_code, ''), chat_type, is_admin, banned_at, COALESCE(ban_reason, ''),
+	created_at, updated_at`
+
+// UserRepositorySQLite реализует интерфейс UserRepository для SQLite
+type UserRepositorySQLite struct {
+	db *DB
+}
+
+// NewUserRepository создает новый репозиторий для работы с пользователями
+func NewUserRepository(db *DB) repository.UserRepository {
+	return &UserRepositorySQLite{db: db}
+}
+
+// Create создает нового пользователя
+func (r *UserRepositorySQLite) Create(ctx context.Context, user *entity.User) error {
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	if user.ChatType == "" {
+		user.ChatType = entity.ChatTypePrivate
+	}
+
+	query := `
+		INSERT INTO users (telegram_id, username, first_name, last_name, chat_type, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		user.TelegramID,
+		user.Username,
+		user.FirstName,
+		user.LastName,
+		user.ChatType,
+		user.CreatedAt,
+		user.UpdatedAt,
+	).Scan(&user.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTelegramID возвращает пользователя по его Telegram ID
+func (r *UserRepositorySQLite) GetByTelegramID(ctx context.Context, telegramID int64) (*entity.User, error) {
+	return r.getUser(ctx, `SELECT `+userColumns+` FROM users WHERE telegram_id = $1`, telegramID)
+}
+
+// GetByID возвращает пользователя по его ID
+func (r *UserRepositorySQLite) GetByID(ctx context.Context, id int64) (*entity.User, error) {
+	return r.getUser(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
+}
+
+// getUser выполняет запрос на получение одного пользователя
+func (r *UserRepositorySQLite) getUser(ctx context.Context, query string, args ...interface{}) (*entity.User, error) {
+	user := &entity.User{}
+	var transcriptionOptions string
+	err := r.db.QueryRow(ctx, query, args...).Scan(
+		&user.ID,
+		&user.TelegramID,
+		&user.Username,
+		&user.FirstName,
+		&user.LastName,
+		&user.NotionToken,
+		&user.NotionDatabaseID,
+		&user.NotionHubPageID,
+		&user.ResultRetentionHours,
+		&transcriptionOptions,
+		&user.SummaryLanguage,
+		&user.LanguageCode,
+		&user.ChatType,
+		&user.IsAdmin,
+		&user.BannedAt,
+		&user.BanReason,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(transcriptionOptions), &user.TranscriptionOptions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transcription options: %w", err)
+	}
+
+	return user, nil
+}
+
+// Update обновляет информацию о пользователе
+func (r *UserRepositorySQLite) Update(ctx context.Context, user *entity.User) error {
+	user.UpdatedAt = time.Now()
+
+	transcriptionOptions, err := json.Marshal(user.TranscriptionOptions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transcription options: %w", err)
+	}
+
+	query := `
+		UPDATE users
+		SET telegram_id = $1, username = $2, first_name = $3, last_name = $4,
+			notion_token = $5, notion_database_id = $6, notion_hub_page_id = $7,
+			result_retention_hours = $8, transcription_options = $9, summary_language = NULLIF($10, ''),
+			language_code = NULLIF($11, ''), chat_type = COALESCE(NULLIF($12, ''), chat_type), updated_at = $13
+		WHERE id = $14
+	`
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		user.TelegramID,
+		user.Username,
+		user.FirstName,
+		user.LastName,
+		user.NotionToken,
+		user.NotionDatabaseID,
+		user.NotionHubPageID,
+		user.ResultRetentionHours,
+		string(transcriptionOptions),
+		user.SummaryLanguage,
+		user.LanguageCode,
+		user.ChatType,
+		user.UpdatedAt,
+		user.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет пользователя. Задачи, настройки, подписки и другие связанные записи удаляются каскадно
+func (r *UserRepositorySQLite) Delete(ctx context.Context, user *entity.User) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM users WHERE id = $1`, user.ID); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return nil
+}
+
+// SetAdmins отмечает администраторами пользователей с указанными Telegram ID и снимает отметку с остальных.
+// Список передается массивом JSON, так как в SQLite нет массивов
+func (r *UserRepositorySQLite) SetAdmins(ctx context.Context, telegramIDs []int64) error {
+	if telegramIDs == nil {
+		telegramIDs = []int64{}
+	}
+	ids, err := json.Marshal(telegramIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal admin ids: %w", err)
+	}
+
+	query := `
+		UPDATE users
+		SET is_admin = telegram_id IN (SELECT value FROM json_each($1))
+		WHERE is_admin <> (telegram_id IN (SELECT value FROM json_each($1)))
+	`
+
+	if _, err := r.db.Exec(ctx, query, string(ids)); err != nil {
+		return fmt.Errorf("failed to set admins: %w", err)
+	}
+
+	return nil
+}
+
+// SetBan блокирует пользователя или снимает блокировку, если bannedAt - nil
+func (r *UserRepositorySQLite) SetBan(ctx context.Context, telegramID int64, bannedAt *time.Time, reason string) (bool, error) {
+	query := `
+		UPDATE users
+		SET banned_at = $1, ban_reason = NULLIF($2, ''), updated_at = $3
+		WHERE telegram_id = $4
+	`
+
+	result, err := r.db.Exec(ctx, query, bannedAt, reason, time.Now(), telegramID)
+	if err != nil {
+		return false, fmt.Errorf("failed to set user ban: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to set user ban: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// IsBanned сообщает, что пользователь с указанным Telegram ID заблокирован
+func (r *UserRepositorySQLite) IsBanned(ctx context.Context, telegramID int64) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM users WHERE telegram_id = $1 AND banned_at IS NOT NULL)`
+
+	var banned bool
+	if err := r.db.QueryRow(ctx, query, telegramID).Scan(&banned); err != nil {
+		return false, fmt.Errorf("failed to check user ban: %w", err)
+	}
+
+	return banned, nil
+}
+
+// GetBroadcastRecipients возвращает незаблокированных пользователей личных чатов с ID больше afterID
+func (r *UserRepositorySQLite) GetBroadcastRecipients(ctx context.Context, afterID int64, limit int) ([]*entity.User, error) {
+	query := `
+		SELECT id, telegram_id, COALESCE(language_code, '')
+		FROM users
+		WHERE id > $1 AND chat_type = $2 AND banned_at IS NULL
+		ORDER BY id
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, afterID, entity.ChatTypePrivate, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broadcast recipients: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*entity.User, 0)
+	for rows.Next() {
+		user := &entity.User{}
+		if err := rows.Scan(&user.ID, &user.TelegramID, &user.LanguageCode); err != nil {
+			return nil, fmt.Errorf("failed to scan broadcast recipient: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate broadcast recipients: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetBaseStats возвращает сводку по пользователям; новыми считаются зарегистрированные начиная с since
+func (r *UserRepositorySQLite) GetBaseStats(ctx context.Context, since time.Time) (*entity.UserBaseStats, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE chat_type = $2),
+			COUNT(*) FILTER (WHERE chat_type <> $2),
+			COUNT(*) FILTER (WHERE chat_type = $2 AND created_at >= $1),
+			COUNT(*) FILTER (WHERE banned_at IS NOT NULL),
+			COUNT(*) FILTER (WHERE is_admin)
+		FROM users
+	`
+
+	stats := &entity.UserBaseStats{}
+	err := r.db.QueryRow(ctx, query, since, entity.ChatTypePrivate).Scan(
+		&stats.Users,
+		&stats.Groups,
+		&stats.NewSince,
+		&stats.Banned,
+		&stats.Admins,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user base stats: %w", err)
+	}
+
+	return stats, nil
+}
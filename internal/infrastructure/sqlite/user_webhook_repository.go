@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+)
+
+// UserWebhookRepositorySQLite реализует интерфейс UserWebhookRepository для SQLite
+type UserWebhookRepositorySQLite struct {
+	db *DB
+}
+
+// NewUserWebhookRepository создает новый репозиторий для работы с вебхуками пользователей
+func NewUserWebhookRepository(db *DB) repository.UserWebhookRepository {
+	return &UserWebhookRepositorySQLite{db: db}
+}
+
+// Save создает или обновляет вебхук пользователя
+func (r *UserWebhookRepositorySQLite) Save(ctx context.Context, webhook *entity.UserWebhook) error {
+	events := webhook.Events
+	if events == nil {
+		events = []entity.WebhookEvent{}
+	}
+	eventsJSON, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook events: %w", err)
+	}
+
+	headers := webhook.Headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	headersJSON, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook headers: %w", err)
+	}
+
+	webhook.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO user_webhooks (user_id, url, events, headers, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET url = excluded.url, events = excluded.events, headers = excluded.headers, updated_at = excluded.updated_at
+		RETURNING created_at
+	`
+
+	err = r.db.QueryRow(ctx, query, webhook.UserID, webhook.URL, string(eventsJSON), string(headersJSON), webhook.UpdatedAt).Scan(&webhook.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save user webhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID возвращает вебхук пользователя или nil
+func (r *UserWebhookRepositorySQLite) GetByUserID(ctx context.Context, userID int64) (*entity.UserWebhook, error) {
+	query := `
+		SELECT user_id, url, events, headers, created_at, updated_at
+		FROM user_webhooks
+		WHERE user_id = $1
+	`
+
+	webhook := &entity.UserWebhook{}
+	var eventsJSON, headersJSON string
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&webhook.UserID,
+		&webhook.URL,
+		&eventsJSON,
+		&headersJSON,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user webhook: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(eventsJSON), &webhook.Events); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook events: %w", err)
+	}
+	if err := json.Unmarshal([]byte(headersJSON), &webhook.Headers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook headers: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// Delete удаляет вебхук пользователя и сообщает, был ли он настроен
+func (r *UserWebhookRepositorySQLite) Delete(ctx context.Context, userID int64) (bool, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM user_webhooks WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete user webhook: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete user webhook: %w", err)
+	}
+
+	return deleted > 0, nil
+}
@@ -0,0 +1,180 @@
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/112Alex/project_obsidian/internal/config"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/database"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/memory"
+	"github.com/112Alex/project_obsidian/internal/infrastructure/sqlite"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// storage содержит подключения и репозитории хранилища данных, выбранного в DB_DRIVER
+type storage struct {
+	postgresDB  *database.PostgresDB
+	redisClient *database.RedisClient
+	sqliteDB    *sqlite.DB
+
+	userRepo              repository.UserRepository
+	jobRepo               repository.JobRepository
+	jobStageRepo          repository.JobStageRepository
+	queueRepo             repository.QueueRepository
+	shareLinkRepo         repository.ShareLinkRepository
+	embeddingRepo         repository.EmbeddingRepository
+	messageDeletionRepo   repository.MessageDeletionRepository
+	podcastRepo           repository.PodcastRepository
+	transcriptSegmentRepo repository.TranscriptSegmentRepository
+	syncResultRepo        repository.SyncResultRepository
+	teamRepo              repository.TeamRepository
+	readingLessonRepo     repository.ReadingLessonRepository
+	jobAttachmentRepo     repository.JobAttachmentRepository
+	userWebhookRepo       repository.UserWebhookRepository
+	failureRollupRepo     repository.FailureRollupRepository
+	adminAuditRepo        repository.AdminAuditRepository
+	videoChapterRepo      repository.VideoChapterRepository
+	meetingAgendaRepo     repository.MeetingAgendaRepository
+	userSettingsRepo      repository.UserSettingsRepository
+	usageEventRepo        repository.UsageEventRepository
+	jobSentimentRepo      repository.JobSentimentRepository
+	notionOAuthStateRepo  repository.NotionOAuthStateRepository
+	rateLimitRepo         repository.RateLimitRepository
+	modelComparisonRepo   repository.ModelComparisonRepository
+	callRecordingRepo     repository.CallRecordingRepository
+	notionRouteRepo       repository.NotionRouteRepository
+	jobLogRepo            repository.JobLogRepository
+	liveSessionRepo       repository.LiveSessionRepository
+	maintenanceRepo       repository.MaintenanceRepository
+	lockService           service.LockService
+}
+
+// newStorage инициализирует хранилище данных, выбранное в конфигурации
+func newStorage(ctx context.Context, cfg *config.Config, logger *logger.Logger) (*storage, error) {
+	if cfg.Database.Driver == config.DatabaseDriverSQLite {
+		return newSQLiteStorage(ctx, cfg, logger)
+	}
+	return newPostgresStorage(ctx, cfg, logger)
+}
+
+// newPostgresStorage инициализирует хранилище на PostgreSQL с очередями, кэшем и блокировками в Redis
+func newPostgresStorage(ctx context.Context, cfg *config.Config, logger *logger.Logger) (*storage, error) {
+	// Инициализация PostgreSQL
+	postgresDB, err := database.NewPostgresDB(ctx, cfg.Postgres)
+	if err != nil {
+		logger.Error("Failed to initialize PostgreSQL",
+			"error", err,
+		)
+		return nil, err
+	}
+
+	// Инициализация Redis
+	redisClient, err := database.NewRedisClient(ctx, cfg.Redis)
+	if err != nil {
+		logger.Error("Failed to initialize Redis",
+			"error", err,
+		)
+		postgresDB.Close()
+		return nil, err
+	}
+
+	return &storage{
+		postgresDB:            postgresDB,
+		redisClient:           redisClient,
+		userRepo:              database.NewCachedUserRepository(database.NewUserRepository(postgresDB), redisClient, cfg.Redis.UserCacheTTL),
+		jobRepo:               database.NewJobRepository(postgresDB, cfg.Postgres.CompressMinBytes),
+		jobStageRepo:          database.NewJobStageRepository(postgresDB),
+		queueRepo:             database.NewQueueRepository(redisClient),
+		shareLinkRepo:         database.NewShareLinkRepository(postgresDB),
+		embeddingRepo:         database.NewEmbeddingRepository(postgresDB),
+		messageDeletionRepo:   database.NewMessageDeletionRepository(redisClient),
+		podcastRepo:           database.NewPodcastRepository(postgresDB),
+		transcriptSegmentRepo: database.NewTranscriptSegmentRepository(postgresDB),
+		syncResultRepo:        database.NewSyncResultRepository(postgresDB),
+		teamRepo:              database.NewTeamRepository(postgresDB),
+		readingLessonRepo:     database.NewReadingLessonRepository(postgresDB),
+		jobAttachmentRepo:     database.NewJobAttachmentRepository(postgresDB),
+		userWebhookRepo:       database.NewUserWebhookRepository(postgresDB),
+		failureRollupRepo:     database.NewFailureRollupRepository(postgresDB),
+		adminAuditRepo:        database.NewAdminAuditRepository(postgresDB),
+		videoChapterRepo:      database.NewVideoChapterRepository(postgresDB),
+		meetingAgendaRepo:     database.NewMeetingAgendaRepository(postgresDB),
+		userSettingsRepo:      database.NewUserSettingsRepository(postgresDB),
+		usageEventRepo:        database.NewUsageEventRepository(postgresDB),
+		jobSentimentRepo:      database.NewJobSentimentRepository(postgresDB),
+		modelComparisonRepo:   database.NewModelComparisonRepository(postgresDB),
+		callRecordingRepo:     database.NewCallRecordingRepository(postgresDB),
+		notionRouteRepo:       database.NewNotionRouteRepository(postgresDB),
+		jobLogRepo:            database.NewJobLogRepository(postgresDB),
+		liveSessionRepo:       database.NewLiveSessionRepository(postgresDB),
+		notionOAuthStateRepo:  database.NewNotionOAuthStateRepository(redisClient),
+		rateLimitRepo:         database.NewRateLimitRepository(redisClient),
+		maintenanceRepo:       database.NewMaintenanceRepository(redisClient),
+		lockService:           database.NewRedisLockService(redisClient, cfg.Notion.UserLockTTL, logger),
+	}, nil
+}
+
+// newSQLiteStorage инициализирует хранилище в файле SQLite с очередью и блокировками в памяти процесса.
+// Такой режим рассчитан на один экземпляр приложения: PostgreSQL и Redis не нужны
+func newSQLiteStorage(ctx context.Context, cfg *config.Config, logger *logger.Logger) (*storage, error) {
+	sqliteDB, err := sqlite.NewDB(ctx, cfg.Database.SQLitePath)
+	if err != nil {
+		logger.Error("Failed to initialize SQLite",
+			"error", err,
+			"path", cfg.Database.SQLitePath,
+		)
+		return nil, err
+	}
+
+	logger.Info("Using SQLite storage",
+		"path", cfg.Database.SQLitePath,
+	)
+
+	return &storage{
+		sqliteDB:              sqliteDB,
+		userRepo:              sqlite.NewUserRepository(sqliteDB),
+		jobRepo:               sqlite.NewJobRepository(sqliteDB),
+		jobStageRepo:          sqlite.NewJobStageRepository(sqliteDB),
+		queueRepo:             memory.NewQueueRepository(),
+		shareLinkRepo:         sqlite.NewShareLinkRepository(sqliteDB),
+		embeddingRepo:         sqlite.NewEmbeddingRepository(sqliteDB),
+		messageDeletionRepo:   sqlite.NewMessageDeletionRepository(sqliteDB),
+		podcastRepo:           sqlite.NewPodcastRepository(sqliteDB),
+		transcriptSegmentRepo: sqlite.NewTranscriptSegmentRepository(sqliteDB),
+		syncResultRepo:        sqlite.NewSyncResultRepository(sqliteDB),
+		teamRepo:              sqlite.NewTeamRepository(sqliteDB),
+		readingLessonRepo:     sqlite.NewReadingLessonRepository(sqliteDB),
+		jobAttachmentRepo:     sqlite.NewJobAttachmentRepository(sqliteDB),
+		userWebhookRepo:       sqlite.NewUserWebhookRepository(sqliteDB),
+		failureRollupRepo:     sqlite.NewFailureRollupRepository(sqliteDB),
+		adminAuditRepo:        sqlite.NewAdminAuditRepository(sqliteDB),
+		videoChapterRepo:      sqlite.NewVideoChapterRepository(sqliteDB),
+		meetingAgendaRepo:     sqlite.NewMeetingAgendaRepository(sqliteDB),
+		userSettingsRepo:      sqlite.NewUserSettingsRepository(sqliteDB),
+		usageEventRepo:        sqlite.NewUsageEventRepository(sqliteDB),
+		jobSentimentRepo:      sqlite.NewJobSentimentRepository(sqliteDB),
+		modelComparisonRepo:   sqlite.NewModelComparisonRepository(sqliteDB),
+		callRecordingRepo:     sqlite.NewCallRecordingRepository(sqliteDB),
+		notionRouteRepo:       sqlite.NewNotionRouteRepository(sqliteDB),
+		jobLogRepo:            sqlite.NewJobLogRepository(sqliteDB),
+		liveSessionRepo:       sqlite.NewLiveSessionRepository(sqliteDB),
+		notionOAuthStateRepo:  memory.NewNotionOAuthStateRepository(),
+		rateLimitRepo:         memory.NewRateLimitRepository(),
+		maintenanceRepo:       memory.NewMaintenanceRepository(),
+		lockService:           memory.NewLockService(),
+	}, nil
+}
+
+// close закрывает подключения хранилища
+func (s *storage) close() {
+	if s.redisClient != nil {
+		s.redisClient.Close()
+	}
+	if s.postgresDB != nil {
+		s.postgresDB.Close()
+	}
+	if s.sqliteDB != nil {
+		s.sqliteDB.Close()
+	}
+}
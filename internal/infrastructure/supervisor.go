@@ -0,0 +1,113 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+)
+
+// supervisorStableRun - сколько подсистема должна проработать без сбоев, чтобы пауза перед следующим
+// перезапуском снова стала начальной
+const supervisorStableRun = 10 * time.Minute
+
+// supervisor выполняет подсистемы приложения и перезапускает упавшие: подсистема, завершившаяся с ошибкой
+// или аварийно, запускается снова после паузы, которая удваивается при сбоях подряд. О каждом перезапуске
+// сообщают метрика subsystem_restarts_total и сообщение администраторам
+type supervisor struct {
+	backoff    time.Duration
+	maxBackoff time.Duration
+	alert      func(id string, args ...interface{})
+	logger     *logger.Logger
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newSupervisor создает супервизор подсистем. alert отправляет администраторам сообщение из каталога i18n
+func newSupervisor(backoff, maxBackoff time.Duration, alert func(id string, args ...interface{}), logger *logger.Logger) *supervisor {
+	return &supervisor{
+		backoff:    backoff,
+		maxBackoff: maxBackoff,
+		alert:      alert,
+		logger:     logger,
+		done:       make(chan struct{}),
+	}
+}
+
+// start выполняет подсистему под наблюдением в отдельной горутине
+func (s *supervisor) start(ctx context.Context, name string, run func(ctx context.Context) error) {
+	go s.run(ctx, name, run)
+}
+
+// run выполняет подсистему под наблюдением, пока она не завершится штатно (без ошибки),
+// супервизор не будет остановлен или контекст не будет отменен
+func (s *supervisor) run(ctx context.Context, name string, run func(ctx context.Context) error) {
+	backoff := s.backoff
+	for {
+		startedAt := time.Now()
+		err := s.runOnce(ctx, name, run)
+		if err == nil || s.stopping() || ctx.Err() != nil {
+			return
+		}
+
+		// Подсистема долго работала без сбоев: новый сбой не считается продолжением прежних
+		if time.Since(startedAt) >= supervisorStableRun {
+			backoff = s.backoff
+		}
+
+		s.logger.Error("Subsystem crashed, restarting",
+			"subsystem", name,
+			"error", err,
+			"restart_in", backoff,
+		)
+		metrics.Default.ObserveRestart(name)
+		go s.alert("supervisor.restart", name, backoff, err)
+
+		select {
+		case <-s.done:
+			return
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, s.maxBackoff)
+	}
+}
+
+// runOnce выполняет подсистему один раз, превращая ее аварийное завершение в ошибку
+func (s *supervisor) runOnce(ctx context.Context, name string, run func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("Subsystem panicked",
+				"subsystem", name,
+				"panic", fmt.Sprint(r),
+				"stack", string(debug.Stack()),
+			)
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return run(ctx)
+}
+
+// stopping сообщает, остановлен ли супервизор
+func (s *supervisor) stopping() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop прекращает перезапуск подсистем; сами подсистемы останавливаются своими методами Stop
+func (s *supervisor) stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+}
@@ -0,0 +1,82 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// accessCacheTTL - сколько хранится результат проверки подписки на канал, чтобы не запрашивать
+// Telegram на каждое сообщение; отписка от канала закрывает доступ не позже чем через это время
+const accessCacheTTL = 10 * time.Minute
+
+// accessEntry представляет собой сохраненный результат проверки подписки на канал
+type accessEntry struct {
+	member    bool
+	checkedAt time.Time
+}
+
+// AccessList представляет собой список доступа приватного экземпляра бота: ботом пользуются только
+// пользователи из списка Telegram ID и подписчики канала, если он задан
+type AccessList struct {
+	bot     *Bot
+	allowed map[int64]bool
+	channel string
+	logger  *logger.Logger
+
+	mu      sync.Mutex
+	members map[int64]accessEntry
+}
+
+// NewAccessList создает список доступа. channel - числовой ID или @username канала, подписчикам которого
+// разрешен доступ; пустое значение - доступ только по списку allowedIDs
+func NewAccessList(bot *Bot, allowedIDs []int64, channel string, logger *logger.Logger) *AccessList {
+	allowed := make(map[int64]bool, len(allowedIDs))
+	for _, id := range allowedIDs {
+		allowed[id] = true
+	}
+
+	return &AccessList{
+		bot:     bot,
+		allowed: allowed,
+		channel: channel,
+		logger:  logger,
+		members: make(map[int64]accessEntry),
+	}
+}
+
+// Allowed сообщает, что пользователю разрешено пользоваться ботом
+func (l *AccessList) Allowed(ctx context.Context, userID int64) (bool, error) {
+	if l.allowed[userID] {
+		return true, nil
+	}
+	if l.channel == "" {
+		return false, nil
+	}
+
+	l.mu.Lock()
+	entry, ok := l.members[userID]
+	l.mu.Unlock()
+	if ok && time.Since(entry.checkedAt) < accessCacheTTL {
+		return entry.member, nil
+	}
+
+	member, err := l.bot.IsChatMember(ctx, l.channel, userID)
+	if err != nil {
+		return false, err
+	}
+
+	l.mu.Lock()
+	l.members[userID] = accessEntry{member: member, checkedAt: time.Now()}
+	l.mu.Unlock()
+
+	if !member {
+		l.logger.Debug("User is not a member of access channel",
+			"user_id", userID,
+			"channel", l.channel,
+		)
+	}
+	return member, nil
+}
@@ -7,20 +7,70 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/112Alex/project_obsidian/pkg/i18n"
 	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	"github.com/112Alex/project_obsidian/pkg/tempfs"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+const (
+	// PhotoContentType - тип содержимого фотографий: Telegram пережимает их в JPEG
+	PhotoContentType = "image/jpeg"
+	// maxPhotoSize - максимальный размер загружаемой фотографии в байтах
+	maxPhotoSize = 10 << 20
+	// maxDownloadSize - максимальный размер файла в байтах, который бот может загрузить через api.telegram.org
+	maxDownloadSize = 20 << 20
+	// maxLocalDownloadSize - максимальный размер файла в байтах, который бот может загрузить через
+	// собственный сервер Bot API
+	maxLocalDownloadSize = 2000 << 20
+)
+
+// Адреса методов и файлов на собственном сервере Bot API; параметры - токен бота и метод или путь к файлу
+const (
+	localAPIEndpoint  = "%s/bot%%s/%%s"
+	localFileEndpoint = "%s/file/bot%%s/%%s"
+)
+
 // Bot представляет собой обертку над Telegram ботом
 type Bot struct {
-	api    *tgbotapi.BotAPI
-	logger *logger.Logger
+	api       *tgbotapi.BotAPI
+	tempSpace *tempfs.Space
+	logger    *logger.Logger
+
+	// fileEndpoint - адрес загрузки файлов с параметрами токена и пути к файлу
+	fileEndpoint string
+	// maxDownloadSize - наибольший размер файла, который бот может загрузить
+	maxDownloadSize int
+	// downloads ограничивает число и общую скорость одновременных загрузок файлов
+	downloads *downloadManager
 
 	// Обработчики команд и сообщений
-	commandHandlers map[string]CommandHandler
-	messageHandler  MessageHandler
-	audioHandler    AudioHandler
+	commandHandlers  map[string]CommandHandler
+	commandListener  CommandListener
+	callbackHandlers map[string]CallbackHandler
+	messageHandler   MessageHandler
+	editedHandler    MessageHandler
+	audioHandler     AudioHandler
+	photoHandler     PhotoHandler
+	documentHandler  DocumentHandler
+	audioGuard       MessageGuard
+	banCheck         BanCheck
+	accessCheck      AccessCheck
+	rateLimit        RateLimit
+	languageResolver LanguageResolver
+	// Обработчики событий групп
+	membershipHandler    MembershipHandler
+	chatMigrationHandler ChatMigrationHandler
+
+	// updates - канал обновлений опроса; создается при первом запуске, чтобы после перезапуска бот читал
+	// тот же канал, а не запускал второй опрос, конфликтующий с первым
+	updates tgbotapi.UpdatesChannel
 
 	stop chan struct{}
 }
@@ -38,26 +88,79 @@ func (b *Bot) Stop() {
 // CommandHandler представляет собой обработчик команды
 type CommandHandler func(ctx context.Context, message *tgbotapi.Message) error
 
+// CommandListener вызывается после успешной обработки известной команды, например для учета ее использования
+type CommandListener func(ctx context.Context, message *tgbotapi.Message)
+
 // MessageHandler представляет собой обработчик текстового сообщения
 type MessageHandler func(ctx context.Context, message *tgbotapi.Message) error
 
 // AudioHandler представляет собой обработчик аудио сообщения
 type AudioHandler func(ctx context.Context, message *tgbotapi.Message, filePath string, fileName string) error
 
-// NewBot создает нового Telegram бота
-func NewBot(token string, logger *logger.Logger) (*Bot, error) {
+// PhotoHandler представляет собой обработчик фотографии; image - содержимое фотографии наибольшего размера
+type PhotoHandler func(ctx context.Context, message *tgbotapi.Message, image []byte) error
+
+// DocumentHandler представляет собой обработчик документа. Файл не загружается заранее:
+// обработчик сам решает по подписи, нужен ли он, и загружает его через DownloadDocument
+type DocumentHandler func(ctx context.Context, message *tgbotapi.Message) error
+
+// MessageGuard проверяет предусловия обработки сообщения. false означает, что сообщение
+// обрабатывать не нужно: ответ пользователю guard отправляет сам
+type MessageGuard func(ctx context.Context, message *tgbotapi.Message) (bool, error)
+
+// BanCheck сообщает, что пользователь с указанным Telegram ID заблокирован и его сообщения не обрабатываются
+type BanCheck func(ctx context.Context, userID int64) (bool, error)
+
+// AccessCheck сообщает, что пользователю с указанным Telegram ID разрешено пользоваться ботом
+type AccessCheck func(ctx context.Context, userID int64) (bool, error)
+
+// RateLimit учитывает аудио сообщение пользователя с указанным Telegram ID и возвращает 0, если лимит
+// не превышен, или время, через которое пользователь сможет прислать следующее
+type RateLimit func(ctx context.Context, userID int64) (time.Duration, error)
+
+// LanguageResolver возвращает язык интерфейса по Telegram ID чата и коду языка из профиля Telegram отправителя
+type LanguageResolver func(ctx context.Context, chatID int64, languageCode string) string
+
+// MembershipHandler вызывается, когда бота добавляют в группу (added) или удаляют из нее
+type MembershipHandler func(ctx context.Context, chat *tgbotapi.Chat, added bool) error
+
+// ChatMigrationHandler вызывается, когда группа становится супергруппой и получает новый ID чата
+type ChatMigrationHandler func(ctx context.Context, fromChatID int64, toChatID int64) error
+
+// CallbackHandler представляет собой обработчик нажатия inline-кнопки.
+// data - данные кнопки без префикса; возвращаемый текст показывается пользователю во всплывающем уведомлении
+type CallbackHandler func(ctx context.Context, query *tgbotapi.CallbackQuery, data string) (string, error)
+
+// NewBot создает нового Telegram бота. apiURL - адрес собственного сервера Bot API; с пустым адресом
+// бот работает через api.telegram.org и загружает файлы размером до 20 МБ. Одновременно загружается
+// не больше downloadConcurrency файлов с общей скоростью до downloadRateMB МБ/с (0 - без ограничения)
+func NewBot(token string, apiURL string, downloadConcurrency int, downloadRateMB int64, tempSpace *tempfs.Space, logger *logger.Logger) (*Bot, error) {
+	apiEndpoint := tgbotapi.APIEndpoint
+	fileEndpoint := tgbotapi.FileEndpoint
+	downloadSize := maxDownloadSize
+	if apiURL != "" {
+		apiEndpoint = fmt.Sprintf(localAPIEndpoint, apiURL)
+		fileEndpoint = fmt.Sprintf(localFileEndpoint, apiURL)
+		downloadSize = maxLocalDownloadSize
+	}
+
 	// Создание клиента Telegram Bot API
-	api, err := tgbotapi.NewBotAPI(token)
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint(token, apiEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create telegram bot: %w", err)
 	}
 
 	// Создание бота
 	bot := &Bot{
-		api:             api,
-		logger:          logger,
-		commandHandlers: make(map[string]CommandHandler),
-		stop:            make(chan struct{}),
+		api:              api,
+		tempSpace:        tempSpace,
+		logger:           logger,
+		fileEndpoint:     fileEndpoint,
+		maxDownloadSize:  downloadSize,
+		downloads:        newDownloadManager(downloadConcurrency, downloadRateMB<<20),
+		commandHandlers:  make(map[string]CommandHandler),
+		callbackHandlers: make(map[string]CallbackHandler),
+		stop:             make(chan struct{}),
 	}
 
 	return bot, nil
@@ -68,27 +171,123 @@ func (b *Bot) RegisterCommandHandler(command string, handler CommandHandler) {
 	b.commandHandlers[command] = handler
 }
 
+// RegisterCommandListener регистрирует слушателя успешно обработанных команд
+func (b *Bot) RegisterCommandListener(listener CommandListener) {
+	b.commandListener = listener
+}
+
+// RegisterCallbackHandler регистрирует обработчик inline-кнопок, данные которых начинаются с prefix и двоеточия
+func (b *Bot) RegisterCallbackHandler(prefix string, handler CallbackHandler) {
+	b.callbackHandlers[prefix] = handler
+}
+
 // RegisterMessageHandler регистрирует обработчик текстовых сообщений
 func (b *Bot) RegisterMessageHandler(handler MessageHandler) {
 	b.messageHandler = handler
 }
 
+// RegisterEditedMessageHandler регистрирует обработчик отредактированных сообщений. Без него правки
+// сообщений, уже обработанных ботом, игнорируются
+func (b *Bot) RegisterEditedMessageHandler(handler MessageHandler) {
+	b.editedHandler = handler
+}
+
 // RegisterAudioHandler регистрирует обработчик аудио сообщений
 func (b *Bot) RegisterAudioHandler(handler AudioHandler) {
 	b.audioHandler = handler
 }
 
-// Start запускает бота
+// RegisterPhotoHandler регистрирует обработчик фотографий
+func (b *Bot) RegisterPhotoHandler(handler PhotoHandler) {
+	b.photoHandler = handler
+}
+
+// RegisterDocumentHandler регистрирует обработчик документов
+func (b *Bot) RegisterDocumentHandler(handler DocumentHandler) {
+	b.documentHandler = handler
+}
+
+// RegisterBanCheck регистрирует проверку блокировки отправителя, выполняемую до обработки любого сообщения
+func (b *Bot) RegisterBanCheck(check BanCheck) {
+	b.banCheck = check
+}
+
+// RegisterAccessCheck регистрирует проверку доступа к боту в приватном режиме. Без нее бот отвечает всем
+func (b *Bot) RegisterAccessCheck(check AccessCheck) {
+	b.accessCheck = check
+}
+
+// RegisterRateLimit регистрирует ограничение частоты аудио сообщений пользователя. Без него частота не ограничена
+func (b *Bot) RegisterRateLimit(limit RateLimit) {
+	b.rateLimit = limit
+}
+
+// RegisterAudioGuard регистрирует проверку, выполняемую до загрузки аудио сообщения
+func (b *Bot) RegisterAudioGuard(guard MessageGuard) {
+	b.audioGuard = guard
+}
+
+// RegisterLanguageResolver регистрирует выбор языка интерфейса пользователя. Без него язык
+// определяется только по профилю Telegram
+func (b *Bot) RegisterLanguageResolver(resolver LanguageResolver) {
+	b.languageResolver = resolver
+}
+
+// RegisterMembershipHandler регистрирует обработчик добавления бота в группу и удаления из нее
+func (b *Bot) RegisterMembershipHandler(handler MembershipHandler) {
+	b.membershipHandler = handler
+}
+
+// RegisterChatMigrationHandler регистрирует обработчик перехода группы в супергруппу
+func (b *Bot) RegisterChatMigrationHandler(handler ChatMigrationHandler) {
+	b.chatMigrationHandler = handler
+}
+
+// SetCommands регистрирует меню команд бота в Telegram. Описание команды берется из каталога i18n
+// по идентификатору «command.<команда>»; для каждого языка интерфейса регистрируется свой список.
+// Английский список регистрируется как список по умолчанию: на английском бот отвечает всем,
+// для чьего языка нет каталога сообщений
+func (b *Bot) SetCommands(commands []string) error {
+	for _, language := range i18n.Languages {
+		menu := make([]tgbotapi.BotCommand, 0, len(commands))
+		for _, command := range commands {
+			menu = append(menu, tgbotapi.BotCommand{
+				Command:     command,
+				Description: i18n.Translate(language, "command."+command),
+			})
+		}
+
+		config := tgbotapi.SetMyCommandsConfig{Commands: menu, LanguageCode: language}
+		if language == i18n.English {
+			config.LanguageCode = ""
+		}
+		err := metrics.Track(context.Background(), b.logger, metrics.ProviderTelegram, "set_my_commands", func(ctx context.Context) error {
+			_, err := b.api.Request(config)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set bot commands for language %s: %w", language, err)
+		}
+	}
+	return nil
+}
+
+// Start запускает бота с получением обновлений опросом (long polling)
 func (b *Bot) Start() error {
 	ctx := context.Background()
 	b.logger.Info("Starting Telegram bot", "username", b.api.Self.UserName)
 
-	// Настройка получения обновлений
-	updateConfig := tgbotapi.NewUpdate(0)
-	updateConfig.Timeout = 60
+	// Вебхук, оставшийся от запуска в режиме вебхука, не дает получать обновления опросом
+	if _, err := b.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		b.logger.Warn("Failed to delete Telegram webhook", "error", err)
+	}
 
 	// Получение канала обновлений
-	updates := b.api.GetUpdatesChan(updateConfig)
+	if b.updates == nil {
+		updateConfig := tgbotapi.NewUpdate(0)
+		updateConfig.Timeout = 60
+		b.updates = b.api.GetUpdatesChan(updateConfig)
+	}
 
 	// Обработка обновлений
 	for {
@@ -96,18 +295,135 @@ func (b *Bot) Start() error {
 		case <-b.stop:
 			b.logger.Info("Stopping Telegram bot")
 			return nil
-		case update := <-updates:
+		case update := <-b.updates:
 			go b.handleUpdate(ctx, update)
 		}
 	}
 }
 
-// handleUpdate обрабатывает обновление от Telegram
+// handleUpdate обрабатывает обновление от Telegram. Обновления обрабатываются в отдельных горутинах,
+// поэтому аварийное завершение обработчика перехватывается здесь, чтобы не останавливать весь процесс
 func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error("Telegram update handler panicked",
+				"update_id", update.UpdateID,
+				"panic", fmt.Sprint(r),
+				"stack", string(debug.Stack()),
+			)
+		}
+	}()
+
+	// Ответы бота переводятся на язык пользователя
+	ctx = i18n.WithLanguage(ctx, b.resolveLanguage(ctx, update.FromChat(), update.SentFrom()))
+
 	// Обработка сообщений
 	if update.Message != nil {
 		b.handleMessage(ctx, update.Message)
 	}
+
+	// Обработка отредактированных сообщений
+	if update.EditedMessage != nil {
+		b.handleEditedMessage(ctx, update.EditedMessage)
+	}
+
+	// Обработка нажатий inline-кнопок
+	if update.CallbackQuery != nil {
+		b.handleCallbackQuery(ctx, update.CallbackQuery)
+	}
+
+	// Добавление бота в группу и удаление из нее
+	if update.MyChatMember != nil {
+		b.handleMyChatMember(ctx, update.MyChatMember)
+	}
+}
+
+// resolveLanguage возвращает язык интерфейса для отправителя обновления в чате chat. Язык ищется
+// по ID чата: в группе действует язык, выбранный в ее настройках
+func (b *Bot) resolveLanguage(ctx context.Context, chat *tgbotapi.Chat, from *tgbotapi.User) string {
+	if from == nil {
+		return i18n.Default
+	}
+	if b.languageResolver == nil {
+		return i18n.Normalize(from.LanguageCode)
+	}
+	chatID := from.ID
+	if chat != nil {
+		chatID = chat.ID
+	}
+	return b.languageResolver(ctx, chatID, from.LanguageCode)
+}
+
+// handleEditedMessage обрабатывает отредактированное сообщение. Команды повторно не выполняются:
+// правка текста команды не должна повторять ее действие. Правки посторонних и заблокированных пользователей
+// молча пропускаются, как и их сообщения в группах
+func (b *Bot) handleEditedMessage(ctx context.Context, message *tgbotapi.Message) {
+	if b.editedHandler == nil || message.IsCommand() {
+		return
+	}
+	if !b.isAllowed(ctx, message.From) || b.isBanned(ctx, message.From) {
+		return
+	}
+
+	if err := b.editedHandler(ctx, message); err != nil {
+		b.logger.Error("Failed to handle edited message", "chat_id", message.Chat.ID, "message_id", message.MessageID, "error", err)
+	}
+}
+
+// handleMyChatMember обрабатывает изменение статуса бота в чате. В личных чатах такое обновление
+// приходит, когда пользователь блокирует бота, а смена прав администратора не меняет членство в группе
+func (b *Bot) handleMyChatMember(ctx context.Context, update *tgbotapi.ChatMemberUpdated) {
+	if b.membershipHandler == nil || (!update.Chat.IsGroup() && !update.Chat.IsSuperGroup()) {
+		return
+	}
+
+	added := isChatMember(update.NewChatMember)
+	if added == isChatMember(update.OldChatMember) {
+		return
+	}
+
+	if err := b.membershipHandler(ctx, &update.Chat, added); err != nil {
+		b.logger.Error("Failed to handle chat membership", "chat_id", update.Chat.ID, "added", added, "error", err)
+	}
+}
+
+// isChatMember сообщает, что участник состоит в чате
+func isChatMember(member tgbotapi.ChatMember) bool {
+	return !member.HasLeft() && !member.WasKicked()
+}
+
+// handleCallbackQuery обрабатывает нажатие inline-кнопки. Кнопки видны всем участникам группы и остаются
+// под старыми сообщениями, поэтому доступ и блокировка проверяются при каждом нажатии, как и для сообщений
+func (b *Bot) handleCallbackQuery(ctx context.Context, query *tgbotapi.CallbackQuery) {
+	// Получение префикса обработчика
+	prefix, data, _ := strings.Cut(query.Data, ":")
+
+	text := i18n.T(ctx, "callback.unknown")
+	handler, ok := b.callbackHandlers[prefix]
+	switch {
+	case !b.isAllowed(ctx, query.From):
+		text = i18n.T(ctx, "error.private")
+	case b.isBanned(ctx, query.From):
+		text = i18n.T(ctx, "error.banned")
+	case ok:
+		var err error
+		text, err = handler(ctx, query, data)
+		if err != nil {
+			b.logger.Error("Failed to handle callback query", "prefix", prefix, "error", err)
+			text = i18n.T(ctx, "callback.error")
+		}
+	default:
+		b.logger.Warn("Unknown callback query", "data", query.Data)
+	}
+
+	// Ответ на нажатие обязателен, иначе кнопка остается в состоянии загрузки
+	err := metrics.Track(ctx, b.logger, metrics.ProviderTelegram, "answer_callback_query", func(ctx context.Context) error {
+		_, err := b.api.Request(tgbotapi.NewCallback(query.ID, text))
+		return err
+	})
+	if err != nil {
+		b.logger.Error("Failed to answer callback query", "error", err)
+	}
 }
 
 // handleMessage обрабатывает сообщение
@@ -120,20 +436,58 @@ func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 		"text", message.Text,
 	)
 
+	// В приватном режиме сообщения посторонних не обрабатываются; отказ, как и ответ о блокировке,
+	// приходит только в личном чате
+	if !b.isAllowed(ctx, message.From) {
+		if message.Chat.IsPrivate() {
+			b.sendErrorMessage(ctx, message.Chat.ID, "error.private")
+		}
+		return
+	}
+
+	// Сообщения заблокированных пользователей не обрабатываются; ответ о блокировке приходит только
+	// в личном чате, чтобы не засорять группы
+	if b.isBanned(ctx, message.From) {
+		if message.Chat.IsPrivate() {
+			b.sendErrorMessage(ctx, message.Chat.ID, "error.banned")
+		}
+		return
+	}
+
+	// Группа стала супергруппой: сообщение об этом приходит в старый чат
+	if message.MigrateToChatID != 0 {
+		if b.chatMigrationHandler != nil {
+			if err := b.chatMigrationHandler(ctx, message.Chat.ID, message.MigrateToChatID); err != nil {
+				b.logger.Error("Failed to migrate chat", "chat_id", message.Chat.ID, "error", err)
+			}
+		}
+		return
+	}
+
 	// Обработка команд
 	if message.IsCommand() {
 		b.handleCommand(ctx, message)
 		return
 	}
 
-	// Обработка аудио сообщений
-	if message.Voice != nil && b.audioHandler != nil {
-		b.handleVoice(ctx, message)
+	// Обработка аудио, голосовых сообщений и видео
+	if b.HandleMediaMessage(ctx, message) {
 		return
 	}
 
-	if message.Audio != nil && b.audioHandler != nil {
-		b.handleAudio(ctx, message)
+	// Обработка фотографий
+	if len(message.Photo) > 0 && b.photoHandler != nil {
+		b.handlePhoto(ctx, message)
+		return
+	}
+
+	// Обработка документов
+	if message.Document != nil && b.documentHandler != nil {
+		err := b.documentHandler(ctx, message)
+		if err != nil {
+			b.logger.Error("Failed to handle document", "error", err)
+			b.sendErrorMessage(ctx, message.Chat.ID, "error.document")
+		}
 		return
 	}
 
@@ -142,9 +496,104 @@ func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 		err := b.messageHandler(ctx, message)
 		if err != nil {
 			b.logger.Error("Failed to handle message", "error", err)
-			b.sendErrorMessage(message.Chat.ID, "Произошла ошибка при обработке сообщения")
+			b.sendErrorMessage(ctx, message.Chat.ID, "error.message")
+		}
+	}
+}
+
+// isBanned сообщает, что отправитель сообщения или нажавший кнопку заблокирован. Если проверить блокировку
+// не удалось, обновление обрабатывается: недоступность базы не должна останавливать бота для всех пользователей
+func (b *Bot) isBanned(ctx context.Context, from *tgbotapi.User) bool {
+	if b.banCheck == nil || from == nil {
+		return false
+	}
+
+	banned, err := b.banCheck(ctx, from.ID)
+	if err != nil {
+		b.logger.Error("Failed to check user ban", "user_id", from.ID, "error", err)
+		return false
+	}
+	if banned {
+		b.logger.Info("Ignoring update from banned user", "user_id", from.ID)
+	}
+	return banned
+}
+
+// isAllowed сообщает, что отправителю сообщения или нажавшему кнопку разрешено пользоваться ботом. В отличие
+// от блокировки, при ошибке проверки обновление не обрабатывается: приватный экземпляр не должен открываться посторонним
+func (b *Bot) isAllowed(ctx context.Context, from *tgbotapi.User) bool {
+	if b.accessCheck == nil {
+		return true
+	}
+	if from == nil {
+		return false
+	}
+
+	allowed, err := b.accessCheck(ctx, from.ID)
+	if err != nil {
+		b.logger.Error("Failed to check user access", "user_id", from.ID, "error", err)
+		return false
+	}
+	if !allowed {
+		b.logger.Info("Ignoring update from user outside allowlist", "user_id", from.ID)
+	}
+	return allowed
+}
+
+// withinRateLimit учитывает аудио сообщение отправителя и сообщает, что лимит частоты не превышен. Если лимит
+// превышен, пользователь получает сообщение о том, когда можно прислать следующее. При ошибке проверки сообщение
+// обрабатывается: ограничение частоты защищает от перегрузки и не должно останавливать работу бота вместе с Redis
+func (b *Bot) withinRateLimit(ctx context.Context, message *tgbotapi.Message) bool {
+	if b.rateLimit == nil || message.From == nil {
+		return true
+	}
+
+	retryAfter, err := b.rateLimit(ctx, message.From.ID)
+	if err != nil {
+		b.logger.Warn("Failed to check audio rate limit", "user_id", message.From.ID, "error", err)
+		return true
+	}
+	if retryAfter <= 0 {
+		return true
+	}
+
+	minutes := int((retryAfter + time.Minute - 1) / time.Minute)
+	b.sendErrorMessage(ctx, message.Chat.ID, "error.rate_limited", minutes)
+	return false
+}
+
+// HandleMediaMessage обрабатывает аудио, голосовое сообщение или видео: проверяет предусловия, загружает файл
+// и передает его обработчику аудио. Так же обрабатывается и ранее присланная запись, на которую ответил
+// пользователь. Возвращает false, если записи в сообщении нет
+func (b *Bot) HandleMediaMessage(ctx context.Context, message *tgbotapi.Message) bool {
+	hasVideo := message.VideoNote != nil || message.Video != nil
+	if (message.Voice == nil && message.Audio == nil && !hasVideo) || b.audioHandler == nil {
+		return false
+	}
+
+	// Проверка предусловий до загрузки аудио, чтобы не скачивать файл впустую
+	if b.audioGuard != nil {
+		ok, err := b.audioGuard(ctx, message)
+		if err != nil {
+			b.logger.Error("Failed to check audio message requirements", "error", err)
+			b.sendErrorMessage(ctx, message.Chat.ID, "error.message")
+			return true
 		}
+		if !ok {
+			return true
+		}
+	}
+
+	switch {
+	case message.Voice != nil:
+		b.handleVoice(ctx, message)
+	case message.Audio != nil:
+		b.handleAudio(ctx, message)
+	default:
+		// Кружки и видео обрабатываются как аудио: звуковую дорожку извлекает обработчик
+		b.handleVideo(ctx, message)
 	}
+	return true
 }
 
 // handleCommand обрабатывает команду
@@ -152,11 +601,19 @@ func (b *Bot) handleCommand(ctx context.Context, message *tgbotapi.Message) {
 	// Получение имени команды
 	command := message.Command()
 
-	// Поиск обработчика команды
+	// В группе команда может быть адресована другому боту: /start@other_bot
+	if _, botName, ok := strings.Cut(message.CommandWithAt(), "@"); ok && !strings.EqualFold(botName, b.api.Self.UserName) {
+		return
+	}
+
+	// Поиск обработчика команды. В группе неизвестные команды могут предназначаться
+	// другим ботам, поэтому ответ об ошибке отправляется только в личном чате
 	handler, ok := b.commandHandlers[command]
 	if !ok {
 		b.logger.Warn("Unknown command", "command", command)
-		b.sendErrorMessage(message.Chat.ID, "Неизвестная команда")
+		if message.Chat.IsPrivate() {
+			b.sendErrorMessage(ctx, message.Chat.ID, "error.unknown_command")
+		}
 		return
 	}
 
@@ -164,30 +621,38 @@ func (b *Bot) handleCommand(ctx context.Context, message *tgbotapi.Message) {
 	err := handler(ctx, message)
 	if err != nil {
 		b.logger.Error("Failed to handle command", "command", command, "error", err)
-		b.sendErrorMessage(message.Chat.ID, "Произошла ошибка при обработке команды")
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.command")
+		return
+	}
+
+	if b.commandListener != nil {
+		b.commandListener(ctx, message)
 	}
 }
 
 // handleVoice обрабатывает голосовое сообщение
 func (b *Bot) handleVoice(ctx context.Context, message *tgbotapi.Message) {
+	if !b.withinRateLimit(ctx, message) {
+		return
+	}
+
 	// Получение информации о голосовом сообщении
 	voiceFileID := message.Voice.FileID
 	voiceFileName := fmt.Sprintf("%s.ogg", voiceFileID)
 
 	// Получение файла
-	voiceFile, err := b.api.GetFile(tgbotapi.FileConfig{FileID: voiceFileID})
+	voiceFile, err := b.getFile(ctx, voiceFileID)
 	if err != nil {
 		b.logger.Error("Failed to get voice file", "error", err)
-		b.sendErrorMessage(message.Chat.ID, "Не удалось получить голосовое сообщение")
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.voice.get")
 		return
 	}
 
 	// Загрузка файла
-	voiceURL := voiceFile.Link(b.api.Token)
-	voiceReader, err := b.downloadFile(voiceURL)
+	voiceReader, err := b.downloadFile(ctx, voiceFile, int64(message.Voice.FileSize))
 	if err != nil {
 		b.logger.Error("Failed to download voice file", "error", err)
-		b.sendErrorMessage(message.Chat.ID, "Не удалось загрузить голосовое сообщение")
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.voice.download")
 		return
 	}
 	defer voiceReader.Close()
@@ -196,7 +661,7 @@ func (b *Bot) handleVoice(ctx context.Context, message *tgbotapi.Message) {
 	filePath, err := b.SaveAudioFile(voiceReader, message.From.ID, voiceFileName)
 	if err != nil {
 		b.logger.Error("Failed to save voice file", "error", err)
-		b.sendErrorMessage(message.Chat.ID, "Не удалось сохранить голосовое сообщение")
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.voice.save")
 		return
 	}
 
@@ -204,7 +669,7 @@ func (b *Bot) handleVoice(ctx context.Context, message *tgbotapi.Message) {
 	err = b.audioHandler(ctx, message, filePath, voiceFileName)
 	if err != nil {
 		b.logger.Error("Failed to handle voice message", "error", err)
-		b.sendErrorMessage(message.Chat.ID, "Произошла ошибка при обработке голосового сообщения")
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.voice")
 	}
 }
 
@@ -217,20 +682,27 @@ func (b *Bot) handleAudio(ctx context.Context, message *tgbotapi.Message) {
 		audioFileName = fmt.Sprintf("%s.mp3", audioFileID)
 	}
 
+	if message.Audio.FileSize > b.maxDownloadSize {
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.audio.too_large", b.maxDownloadSize>>20)
+		return
+	}
+	if !b.withinRateLimit(ctx, message) {
+		return
+	}
+
 	// Получение файла
-	audioFile, err := b.api.GetFile(tgbotapi.FileConfig{FileID: audioFileID})
+	audioFile, err := b.getFile(ctx, audioFileID)
 	if err != nil {
 		b.logger.Error("Failed to get audio file", "error", err)
-		b.sendErrorMessage(message.Chat.ID, "Не удалось получить аудио файл")
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.audio.get")
 		return
 	}
 
 	// Загрузка файла
-	audioURL := audioFile.Link(b.api.Token)
-	audioReader, err := b.downloadFile(audioURL)
+	audioReader, err := b.downloadFile(ctx, audioFile, int64(message.Audio.FileSize))
 	if err != nil {
 		b.logger.Error("Failed to download audio file", "error", err)
-		b.sendErrorMessage(message.Chat.ID, "Не удалось загрузить аудио файл")
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.audio.download")
 		return
 	}
 	defer audioReader.Close()
@@ -239,7 +711,7 @@ func (b *Bot) handleAudio(ctx context.Context, message *tgbotapi.Message) {
 	filePath, err := b.SaveAudioFile(audioReader, message.From.ID, audioFileName)
 	if err != nil {
 		b.logger.Error("Failed to save audio file", "error", err)
-		b.sendErrorMessage(message.Chat.ID, "Не удалось сохранить аудио файл")
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.audio.save")
 		return
 	}
 
@@ -247,32 +719,194 @@ func (b *Bot) handleAudio(ctx context.Context, message *tgbotapi.Message) {
 	err = b.audioHandler(ctx, message, filePath, audioFileName)
 	if err != nil {
 		b.logger.Error("Failed to handle audio message", "error", err)
-		b.sendErrorMessage(message.Chat.ID, "Произошла ошибка при обработке аудио файла")
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.audio")
+	}
+}
+
+// handleVideo обрабатывает видеосообщение (кружок) или видеофайл: загружает его и передает обработчику аудио
+func (b *Bot) handleVideo(ctx context.Context, message *tgbotapi.Message) {
+	// Получение информации о видео
+	var videoFileID, videoFileName string
+	var videoFileSize int
+	if message.VideoNote != nil {
+		videoFileID = message.VideoNote.FileID
+		videoFileSize = message.VideoNote.FileSize
+		videoFileName = fmt.Sprintf("%s.mp4", videoFileID)
+	} else {
+		videoFileID = message.Video.FileID
+		videoFileSize = message.Video.FileSize
+		videoFileName = message.Video.FileName
+		if videoFileName == "" {
+			videoFileName = fmt.Sprintf("%s.mp4", videoFileID)
+		}
+	}
+
+	if videoFileSize > b.maxDownloadSize {
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.video.too_large", b.maxDownloadSize>>20)
+		return
+	}
+	if !b.withinRateLimit(ctx, message) {
+		return
+	}
+
+	// Получение файла
+	videoFile, err := b.getFile(ctx, videoFileID)
+	if err != nil {
+		b.logger.Error("Failed to get video file", "error", err)
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.video.get")
+		return
+	}
+
+	// Загрузка файла
+	videoReader, err := b.downloadFile(ctx, videoFile, int64(videoFileSize))
+	if err != nil {
+		b.logger.Error("Failed to download video file", "error", err)
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.video.download")
+		return
+	}
+	defer videoReader.Close()
+
+	// Сохранение файла
+	filePath, err := b.SaveAudioFile(videoReader, message.From.ID, videoFileName)
+	if err != nil {
+		b.logger.Error("Failed to save video file", "error", err)
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.video.save")
+		return
+	}
+
+	// Вызов обработчика аудио
+	err = b.audioHandler(ctx, message, filePath, videoFileName)
+	if err != nil {
+		b.logger.Error("Failed to handle video message", "error", err)
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.video")
+	}
+}
+
+// handlePhoto обрабатывает фотографию: загружает вариант наибольшего размера и передает его обработчику
+func (b *Bot) handlePhoto(ctx context.Context, message *tgbotapi.Message) {
+	// Telegram присылает фотографию в нескольких размерах, последний - наибольший
+	photo := message.Photo[len(message.Photo)-1]
+	if photo.FileSize > maxPhotoSize {
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.photo.too_large")
+		return
+	}
+
+	// Получение файла
+	photoFile, err := b.getFile(ctx, photo.FileID)
+	if err != nil {
+		b.logger.Error("Failed to get photo file", "error", err)
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.photo.get")
+		return
+	}
+
+	// Загрузка файла
+	photoReader, err := b.downloadFile(ctx, photoFile, int64(photo.FileSize))
+	if err != nil {
+		b.logger.Error("Failed to download photo file", "error", err)
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.photo.download")
+		return
+	}
+	defer photoReader.Close()
+
+	image, err := io.ReadAll(io.LimitReader(photoReader, maxPhotoSize+1))
+	if err != nil || len(image) > maxPhotoSize {
+		b.logger.Error("Failed to read photo file", "error", err, "size", len(image))
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.photo.read")
+		return
+	}
+
+	// Вызов обработчика фотографии
+	err = b.photoHandler(ctx, message, image)
+	if err != nil {
+		b.logger.Error("Failed to handle photo", "error", err)
+		b.sendErrorMessage(ctx, message.Chat.ID, "error.photo")
+	}
+}
+
+// DownloadDocument загружает содержимое документа размером не больше maxSize байт
+func (b *Bot) DownloadDocument(ctx context.Context, document *tgbotapi.Document, maxSize int) ([]byte, error) {
+	if document.FileSize > maxSize {
+		return nil, fmt.Errorf("document is too large: %d bytes", document.FileSize)
+	}
+
+	// Получение файла
+	documentFile, err := b.getFile(ctx, document.FileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document file: %w", err)
+	}
+
+	// Загрузка файла
+	documentReader, err := b.downloadFile(ctx, documentFile, int64(document.FileSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download document file: %w", err)
 	}
+	defer documentReader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(documentReader, int64(maxSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document file: %w", err)
+	}
+	if len(data) > maxSize {
+		return nil, fmt.Errorf("document is too large: %d bytes", len(data))
+	}
+
+	return data, nil
 }
 
-// downloadFile загружает файл по URL
-func (b *Bot) downloadFile(url string) (io.ReadCloser, error) {
+// getFile получает информацию о файле, записывая метрики вызова
+func (b *Bot) getFile(ctx context.Context, fileID string) (tgbotapi.File, error) {
+	var file tgbotapi.File
+	err := metrics.Track(ctx, b.logger, metrics.ProviderTelegram, "get_file", func(ctx context.Context) error {
+		var err error
+		file, err = b.api.GetFile(tgbotapi.FileConfig{FileID: fileID})
+		return err
+	})
+	return file, err
+}
+
+// downloadFile загружает файл Telegram во временный каталог, резервируя в нем size байт
+func (b *Bot) downloadFile(ctx context.Context, telegramFile tgbotapi.File, size int64) (io.ReadCloser, error) {
+	// Резервирование места во временном каталоге
+	release, err := b.tempSpace.Reserve(ctx, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve temp space: %w", err)
+	}
+
+	// Ожидание очереди загрузок: соединение и временный файл открываются только для загрузки, которая идет
+	releaseSlot, err := b.acquireDownloadSlot(ctx)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	defer releaseSlot()
+
 	// Создание временного файла
-	tmpFile, err := os.CreateTemp("", "tg-audio-*")
+	tmpFile, err := b.tempSpace.CreateTemp("tg-audio-*")
 	if err != nil {
+		release()
 		return nil, fmt.Errorf("failed to create temp file: %w", err)
 	}
 
 	// Загрузка файла
-	resp, err := http.Get(url)
+	body, err := b.openFile(ctx, telegramFile)
 	if err != nil {
 		tmpFile.Close()
 		os.Remove(tmpFile.Name())
+		release()
 		return nil, fmt.Errorf("failed to download file: %w", err)
 	}
-	defer resp.Body.Close()
+	defer body.Close()
 
-	// Копирование содержимого в файл
-	_, err = io.Copy(tmpFile, resp.Body)
+	// Копирование содержимого в файл; ограничение скорости касается только загрузки по сети
+	var reader io.Reader = body
+	if !filepath.IsAbs(telegramFile.FilePath) {
+		reader = b.downloads.throttle(ctx, body)
+	}
+	_, err = io.Copy(tmpFile, reader)
 	if err != nil {
 		tmpFile.Close()
 		os.Remove(tmpFile.Name())
+		release()
 		return nil, fmt.Errorf("failed to save file: %w", err)
 	}
 
@@ -280,6 +914,7 @@ func (b *Bot) downloadFile(url string) (io.ReadCloser, error) {
 	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
 		tmpFile.Close()
 		os.Remove(tmpFile.Name())
+		release()
 		return nil, fmt.Errorf("failed to seek file: %w", err)
 	}
 
@@ -289,16 +924,68 @@ func (b *Bot) downloadFile(url string) (io.ReadCloser, error) {
 	if _, err := file.Seek(0, io.SeekStart); err != nil {
 		file.Close()
 		os.Remove(file.Name())
+		release()
 		return nil, fmt.Errorf("failed to seek file: %w", err)
 	}
 
 	// Создание ReadCloser, который удаляет файл при закрытии
-	return &fileReadCloser{file: file}, nil
+	return &fileReadCloser{file: file, release: release}, nil
+}
+
+// acquireDownloadSlot занимает место в очереди загрузок; долгое ожидание записывается в журнал,
+// чтобы было видно, что обработку задерживает ограничение одновременных загрузок
+func (b *Bot) acquireDownloadSlot(ctx context.Context) (func(), error) {
+	start := time.Now()
+	release, err := b.downloads.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if waited := time.Since(start); waited > time.Second {
+		b.logger.Info("Waited for download slot",
+			"waited", waited,
+			"concurrency", cap(b.downloads.slots),
+		)
+	}
+
+	return release, nil
+}
+
+// openFile открывает содержимое файла Telegram. Собственный сервер Bot API в режиме --local возвращает
+// вместо пути для загрузки абсолютный путь к файлу на своем диске: такой файл читается напрямую,
+// поэтому каталог данных сервера должен быть доступен боту по тому же пути
+func (b *Bot) openFile(ctx context.Context, telegramFile tgbotapi.File) (io.ReadCloser, error) {
+	if filepath.IsAbs(telegramFile.FilePath) {
+		return os.Open(telegramFile.FilePath)
+	}
+
+	var resp *http.Response
+	err := metrics.Track(ctx, b.logger, metrics.ProviderTelegram, "download_file", func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(b.fileEndpoint, b.api.Token, telegramFile.FilePath), nil)
+		if err != nil {
+			return err
+		}
+		resp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
 }
 
 // fileReadCloser представляет собой обертку над файлом, которая удаляет файл при закрытии
 type fileReadCloser struct {
-	file *os.File
+	file    *os.File
+	release func() // Освобождение резерва во временном каталоге
 }
 
 // Read реализует интерфейс io.Reader
@@ -316,6 +1003,7 @@ func (f *fileReadCloser) Close() error {
 
 	// Удаление файла
 	os.Remove(fileName)
+	f.release()
 
 	return err
 }
@@ -323,20 +1011,234 @@ func (f *fileReadCloser) Close() error {
 // SendMessage отправляет текстовое сообщение
 func (b *Bot) SendMessage(chatID int64, text string) (tgbotapi.Message, error) {
 	msg := tgbotapi.NewMessage(chatID, text)
-	return b.api.Send(msg)
+	return b.send("send_message", msg)
 }
 
 // SendMarkdownMessage отправляет сообщение с разметкой Markdown
 func (b *Bot) SendMarkdownMessage(chatID int64, text string) (tgbotapi.Message, error) {
 	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = tgbotapi.ModeMarkdown
-	return b.api.Send(msg)
+	return b.sendMarkdown(msg)
+}
+
+// SendMarkdownMessageWithKeyboard отправляет сообщение с разметкой Markdown и inline-клавиатурой
+func (b *Bot) SendMarkdownMessageWithKeyboard(chatID int64, text string, keyboard tgbotapi.InlineKeyboardMarkup) (tgbotapi.Message, error) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	return b.sendMarkdown(msg)
+}
+
+// SendMessageWithKeyboard отправляет текстовое сообщение с inline-клавиатурой
+func (b *Bot) SendMessageWithKeyboard(chatID int64, text string, keyboard tgbotapi.InlineKeyboardMarkup) (tgbotapi.Message, error) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	return b.send("send_message", msg)
+}
+
+// SendReplyMessage отправляет текстовое сообщение в ответ на указанное сообщение
+func (b *Bot) SendReplyMessage(chatID int64, replyToMessageID int, text string) (tgbotapi.Message, error) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyToMessageID = replyToMessageID
+	// Если исходное сообщение уже удалено, отправляем без ответа
+	msg.AllowSendingWithoutReply = true
+	return b.send("send_message", msg)
+}
+
+// SendReplyMessageWithKeyboard отправляет текстовое сообщение с inline-клавиатурой в ответ на указанное сообщение
+func (b *Bot) SendReplyMessageWithKeyboard(chatID int64, replyToMessageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) (tgbotapi.Message, error) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyToMessageID = replyToMessageID
+	msg.AllowSendingWithoutReply = true
+	msg.ReplyMarkup = keyboard
+	return b.send("send_message", msg)
 }
 
-// sendErrorMessage отправляет сообщение об ошибке
-func (b *Bot) sendErrorMessage(chatID int64, text string) {
+// SendMarkdownReplyMessage отправляет сообщение с разметкой Markdown в ответ на указанное сообщение
+func (b *Bot) SendMarkdownReplyMessage(chatID int64, replyToMessageID int, text string) (tgbotapi.Message, error) {
 	msg := tgbotapi.NewMessage(chatID, text)
-	_, err := b.api.Send(msg)
+	msg.ReplyToMessageID = replyToMessageID
+	msg.AllowSendingWithoutReply = true
+	return b.sendMarkdown(msg)
+}
+
+// SendMarkdownReplyMessageWithKeyboard отправляет сообщение с разметкой Markdown и inline-клавиатурой
+// в ответ на указанное сообщение
+func (b *Bot) SendMarkdownReplyMessageWithKeyboard(chatID int64, replyToMessageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) (tgbotapi.Message, error) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyToMessageID = replyToMessageID
+	msg.AllowSendingWithoutReply = true
+	msg.ReplyMarkup = keyboard
+	return b.sendMarkdown(msg)
+}
+
+// sendMarkdown отправляет сообщение, переведя его текст в MarkdownV2. Если Telegram все же не смог
+// разобрать разметку, сообщение отправляется заново без нее, чтобы ответ не потерялся
+func (b *Bot) sendMarkdown(msg tgbotapi.MessageConfig) (tgbotapi.Message, error) {
+	text := msg.Text
+	msg.Text = renderMarkdownV2(text)
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+
+	sent, err := b.send("send_message", msg)
+	if !isMarkdownError(err) {
+		return sent, err
+	}
+
+	b.logger.Warn("Failed to send message with markdown, sending plain text", "error", err)
+	msg.Text = stripMarkdown(text)
+	msg.ParseMode = ""
+	return b.send("send_message", msg)
+}
+
+// SendDocument отправляет файл с содержимым data под именем fileName
+func (b *Bot) SendDocument(chatID int64, fileName string, data []byte, caption string) (tgbotapi.Message, error) {
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: fileName, Bytes: data})
+	doc.Caption = caption
+	return b.send("send_document", doc)
+}
+
+// SendReplyDocument отправляет файл с содержимым data под именем fileName в ответ на указанное сообщение
+func (b *Bot) SendReplyDocument(chatID int64, replyToMessageID int, fileName string, data []byte, caption string) (tgbotapi.Message, error) {
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: fileName, Bytes: data})
+	doc.Caption = caption
+	doc.ReplyToMessageID = replyToMessageID
+	// Если исходное сообщение уже удалено, отправляем без ответа
+	doc.AllowSendingWithoutReply = true
+	return b.send("send_document", doc)
+}
+
+// SendReplyVoice отправляет голосовое сообщение в формате OGG/Opus в ответ на указанное сообщение
+func (b *Bot) SendReplyVoice(chatID int64, replyToMessageID int, fileName string, data []byte, caption string) (tgbotapi.Message, error) {
+	voice := tgbotapi.NewVoice(chatID, tgbotapi.FileBytes{Name: fileName, Bytes: data})
+	voice.Caption = caption
+	voice.ReplyToMessageID = replyToMessageID
+	// Если исходное сообщение уже удалено, отправляем без ответа
+	voice.AllowSendingWithoutReply = true
+	return b.send("send_voice", voice)
+}
+
+// RemoveInlineKeyboard убирает inline-клавиатуру из сообщения
+func (b *Bot) RemoveInlineKeyboard(chatID int64, messageID int) error {
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, tgbotapi.InlineKeyboardMarkup{
+		InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{},
+	})
+	err := metrics.Track(context.Background(), b.logger, metrics.ProviderTelegram, "edit_message_reply_markup", func(ctx context.Context) error {
+		_, err := b.api.Request(edit)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove inline keyboard: %w", err)
+	}
+	return nil
+}
+
+// EditMessageText заменяет текст сообщения
+func (b *Bot) EditMessageText(chatID int64, messageID int, text string) error {
+	err := metrics.Track(context.Background(), b.logger, metrics.ProviderTelegram, "edit_message_text", func(ctx context.Context) error {
+		_, err := b.api.Request(tgbotapi.NewEditMessageText(chatID, messageID, text))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+	return nil
+}
+
+// EditMessageTextWithKeyboard заменяет текст и inline-клавиатуру сообщения
+func (b *Bot) EditMessageTextWithKeyboard(chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, keyboard)
+	err := metrics.Track(context.Background(), b.logger, metrics.ProviderTelegram, "edit_message_text", func(ctx context.Context) error {
+		_, err := b.api.Request(edit)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+	return nil
+}
+
+// EditMarkdownMessageTextWithKeyboard заменяет текст с разметкой Markdown и inline-клавиатуру сообщения
+func (b *Bot) EditMarkdownMessageTextWithKeyboard(chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, renderMarkdownV2(text), keyboard)
+	edit.ParseMode = tgbotapi.ModeMarkdownV2
+	err := metrics.Track(context.Background(), b.logger, metrics.ProviderTelegram, "edit_message_text", func(ctx context.Context) error {
+		_, err := b.api.Request(edit)
+		return err
+	})
+	if isMarkdownError(err) {
+		// Как и при отправке, сообщение с неразобранной разметкой показывается без нее
+		b.logger.Warn("Failed to edit message with markdown, using plain text", "error", err)
+		return b.EditMessageTextWithKeyboard(chatID, messageID, stripMarkdown(text), keyboard)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+	return nil
+}
+
+// IsChatAdmin сообщает, что пользователь - создатель или администратор группы
+func (b *Bot) IsChatAdmin(ctx context.Context, chatID int64, userID int64) (bool, error) {
+	var member tgbotapi.ChatMember
+	err := metrics.Track(ctx, b.logger, metrics.ProviderTelegram, "get_chat_member", func(ctx context.Context) error {
+		var err error
+		member, err = b.api.GetChatMember(tgbotapi.GetChatMemberConfig{
+			ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatID, UserID: userID},
+		})
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get chat member: %w", err)
+	}
+	return member.IsCreator() || member.IsAdministrator(), nil
+}
+
+// IsChatMember сообщает, что пользователь состоит в чате или канале. chat - числовой ID чата или
+// @username публичного канала; чтобы видеть подписчиков канала, бот должен быть его администратором
+func (b *Bot) IsChatMember(ctx context.Context, chat string, userID int64) (bool, error) {
+	config := tgbotapi.ChatConfigWithUser{UserID: userID}
+	if id, err := strconv.ParseInt(chat, 10, 64); err == nil {
+		config.ChatID = id
+	} else {
+		config.SuperGroupUsername = chat
+	}
+
+	var member tgbotapi.ChatMember
+	err := metrics.Track(ctx, b.logger, metrics.ProviderTelegram, "get_chat_member", func(ctx context.Context) error {
+		var err error
+		member, err = b.api.GetChatMember(tgbotapi.GetChatMemberConfig{ChatConfigWithUser: config})
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get chat member: %w", err)
+	}
+	return isChatMember(member), nil
+}
+
+// DeleteMessage удаляет сообщение из чата
+func (b *Bot) DeleteMessage(chatID int64, messageID int) error {
+	err := metrics.Track(context.Background(), b.logger, metrics.ProviderTelegram, "delete_message", func(ctx context.Context) error {
+		_, err := b.api.Request(tgbotapi.NewDeleteMessage(chatID, messageID))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+	return nil
+}
+
+// send отправляет запрос в Telegram, записывая метрики вызова
+func (b *Bot) send(operation string, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	var msg tgbotapi.Message
+	err := metrics.Track(context.Background(), b.logger, metrics.ProviderTelegram, operation, func(ctx context.Context) error {
+		var err error
+		msg, err = b.api.Send(c)
+		return err
+	})
+	return msg, err
+}
+
+// sendErrorMessage отправляет сообщение об ошибке; id - идентификатор сообщения в каталоге i18n
+func (b *Bot) sendErrorMessage(ctx context.Context, chatID int64, id string, args ...interface{}) {
+	msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, id, args...))
+	_, err := b.send("send_message", msg)
 	if err != nil {
 		b.logger.Error("Failed to send error message", "error", err)
 	}
@@ -345,7 +1247,7 @@ func (b *Bot) sendErrorMessage(chatID int64, text string) {
 // SaveAudioFile сохраняет аудиофайл на диск
 func (b *Bot) SaveAudioFile(reader io.Reader, userID int64, fileName string) (string, error) {
 	// Создание директории для сохранения файлов пользователя
-	userDir := filepath.Join("uploads", fmt.Sprintf("user_%d", userID))
+	userDir := filepath.Join(b.tempSpace.Dir(), "uploads", fmt.Sprintf("user_%d", userID))
 	if err := os.MkdirAll(userDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create user directory: %w", err)
 	}
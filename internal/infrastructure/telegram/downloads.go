@@ -0,0 +1,95 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// downloadChunkSize - сколько байт загрузки читается за раз между проверками ограничения скорости
+const downloadChunkSize = 64 << 10
+
+// downloadManager ограничивает загрузки файлов из Telegram: одновременно идет не больше concurrency загрузок,
+// остальные ждут в очереди, а общая скорость загрузок не превышает заданную. Без ограничения поток больших
+// записей открывал по соединению и временному файлу на каждую запись сразу
+type downloadManager struct {
+	slots chan struct{}
+
+	// Общее ограничение скорости (bytesPerSecond <= 0 - без ограничения): next - время, к которому
+	// скорость позволит прочитать следующий фрагмент любой из загрузок
+	bytesPerSecond int64
+	mu             sync.Mutex
+	next           time.Time
+}
+
+// newDownloadManager создает ограничитель на concurrency одновременных загрузок (не меньше одной)
+// с общей скоростью bytesPerSecond байт в секунду (0 - без ограничения)
+func newDownloadManager(concurrency int, bytesPerSecond int64) *downloadManager {
+	return &downloadManager{
+		slots:          make(chan struct{}, max(concurrency, 1)),
+		bytesPerSecond: bytesPerSecond,
+	}
+}
+
+// acquire занимает место для загрузки и возвращает функцию его освобождения; ожидание прерывается отменой ctx
+func (m *downloadManager) acquire(ctx context.Context) (func(), error) {
+	select {
+	case m.slots <- struct{}{}:
+		return func() { <-m.slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to wait for download slot: %w", ctx.Err())
+	}
+}
+
+// throttle возвращает reader, чтение из которого укладывается в общее ограничение скорости загрузок
+func (m *downloadManager) throttle(ctx context.Context, reader io.Reader) io.Reader {
+	if m.bytesPerSecond <= 0 {
+		return reader
+	}
+	return &throttledReader{ctx: ctx, reader: reader, manager: m}
+}
+
+// wait ждет, пока общее ограничение скорости позволит прочитать n байт
+func (m *downloadManager) wait(ctx context.Context, n int) error {
+	m.mu.Lock()
+	now := time.Now()
+	if m.next.Before(now) {
+		m.next = now
+	}
+	delay := m.next.Sub(now)
+	m.next = m.next.Add(time.Duration(int64(n) * int64(time.Second) / m.bytesPerSecond))
+	m.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttledReader читает загрузку фрагментами, дожидаясь перед каждым разрешения общего ограничения скорости
+type throttledReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	manager *downloadManager
+}
+
+// Read реализует интерфейс io.Reader
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > downloadChunkSize {
+		p = p[:downloadChunkSize]
+	}
+	if err := r.manager.wait(r.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
@@ -0,0 +1,76 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Префиксы данных inline-кнопок
+const (
+	AudioProfileCallbackPrefix = "profile"  // Выбор профиля предобработки аудио
+	PipelineCallbackPrefix     = "pipeline" // Выбор набора настроек обработки
+	SetupCallbackPrefix        = "setup"    // Настройка, которой не хватает для выполнения команды
+)
+
+// pipelineLabels - идентификаторы сообщений с подписями кнопок выбора набора настроек обработки
+var pipelineLabels = map[entity.Pipeline]string{
+	entity.PipelineFast:    "button.pipeline.fast",
+	entity.PipelineQuality: "button.pipeline.quality",
+}
+
+// audioProfileLabels - идентификаторы сообщений с подписями кнопок выбора профиля предобработки аудио
+var audioProfileLabels = map[entity.AudioProfile]string{
+	entity.AudioProfileAuto:    "button.profile.auto",
+	entity.AudioProfileSpeech:  "button.profile.speech",
+	entity.AudioProfilePhone:   "button.profile.phone",
+	entity.AudioProfileLecture: "button.profile.lecture",
+	entity.AudioProfileMusic:   "button.profile.music",
+}
+
+// NewAudioProfileKeyboard создает клавиатуру выбора профиля предобработки для задачи. Первый ряд предлагает
+// наборы настроек обработки с данными pipeline:<ID задачи>:<набор>, остальные - профили с данными
+// profile:<ID задачи>:<профиль>. Подписи кнопок переводятся на язык из контекста запроса
+func NewAudioProfileKeyboard(ctx context.Context, jobID int64) tgbotapi.InlineKeyboardMarkup {
+	pipelines := make([]tgbotapi.InlineKeyboardButton, 0, len(entity.Pipelines))
+	for _, pipeline := range entity.Pipelines {
+		data := fmt.Sprintf("%s:%d:%s", PipelineCallbackPrefix, jobID, pipeline)
+		pipelines = append(pipelines, tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, pipelineLabels[pipeline]), data))
+	}
+
+	buttons := make([]tgbotapi.InlineKeyboardButton, 0, len(entity.AudioProfiles))
+	for _, profile := range entity.AudioProfiles {
+		data := fmt.Sprintf("%s:%d:%s", AudioProfileCallbackPrefix, jobID, profile)
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, audioProfileLabels[profile]), data))
+	}
+
+	// Пять кнопок в одном ряду не помещаются на узких экранах
+	return tgbotapi.NewInlineKeyboardMarkup(pipelines, buttons[:3], buttons[3:])
+}
+
+// NewSetupKeyboard создает клавиатуру с кнопкой настройки из ответа на команду с невыполненным предусловием
+func NewSetupKeyboard(label, action string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, SetupCallbackPrefix+":"+action)),
+	)
+}
+
+// NewButtonsKeyboard создает клавиатуру из рядов кнопок, описанных в сценариях
+func NewButtonsKeyboard(buttons [][]entity.MessageButton) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(buttons))
+	for _, row := range buttons {
+		keyboardRow := make([]tgbotapi.InlineKeyboardButton, 0, len(row))
+		for _, button := range row {
+			if button.URL != "" {
+				keyboardRow = append(keyboardRow, tgbotapi.NewInlineKeyboardButtonURL(button.Text, button.URL))
+			} else {
+				keyboardRow = append(keyboardRow, tgbotapi.NewInlineKeyboardButtonData(button.Text, button.Data))
+			}
+		}
+		rows = append(rows, keyboardRow)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
@@ -0,0 +1,134 @@
+package telegram
+
+import (
+	"strings"
+)
+
+// markdownV2Special - символы, которые в MarkdownV2 нужно экранировать вне разметки
+const markdownV2Special = "_*[]()~`>#+-=|{}.!\\"
+
+// EscapeMarkdownV2 экранирует текст, чтобы Telegram показал его в сообщении с разметкой MarkdownV2 как есть
+func EscapeMarkdownV2(text string) string {
+	var builder strings.Builder
+	builder.Grow(len(text))
+	for _, r := range text {
+		if strings.ContainsRune(markdownV2Special, r) {
+			builder.WriteByte('\\')
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// renderMarkdownV2 переводит текст ответов бота в MarkdownV2. Ответы пишутся в упрощенной разметке:
+// *жирный*, `код` и [ссылка](адрес) в пределах одной строки. Все остальное, в том числе одиночные
+// звездочки и подчеркивания в именах пользователей и транскрипциях, экранируется и показывается как есть
+func renderMarkdownV2(text string) string {
+	var builder strings.Builder
+	builder.Grow(len(text) + len(text)/8)
+
+	for i := 0; i < len(text); {
+		switch text[i] {
+		case '*':
+			if end, ok := markdownSpan(text, i, '*'); ok {
+				builder.WriteString("*" + EscapeMarkdownV2(text[i+1:end]) + "*")
+				i = end + 1
+				continue
+			}
+		case '`':
+			if end, ok := markdownSpan(text, i, '`'); ok {
+				// Внутри кода экранируются только обратная кавычка и обратная косая черта
+				code := strings.NewReplacer("\\", "\\\\", "`", "\\`").Replace(text[i+1 : end])
+				builder.WriteString("`" + code + "`")
+				i = end + 1
+				continue
+			}
+		case '[':
+			if label, url, end, ok := markdownLink(text, i); ok {
+				link := strings.NewReplacer("\\", "\\\\", ")", "\\)").Replace(url)
+				builder.WriteString("[" + EscapeMarkdownV2(label) + "](" + link + ")")
+				i = end + 1
+				continue
+			}
+		}
+
+		if strings.IndexByte(markdownV2Special, text[i]) >= 0 {
+			builder.WriteByte('\\')
+		}
+		builder.WriteByte(text[i])
+		i++
+	}
+
+	return builder.String()
+}
+
+// markdownSpan ищет закрывающий маркер для маркера в позиции start. Выделение должно быть непустым,
+// умещаться в одной строке и не начинаться и не заканчиваться пробелом, иначе маркер - обычный символ
+func markdownSpan(text string, start int, marker byte) (int, bool) {
+	line := text[start+1:]
+	if newline := strings.IndexByte(line, '\n'); newline >= 0 {
+		line = line[:newline]
+	}
+
+	end := strings.IndexByte(line, marker)
+	if end <= 0 {
+		return 0, false
+	}
+	if marker == '*' && (line[0] == ' ' || line[end-1] == ' ') {
+		return 0, false
+	}
+	return start + 1 + end, true
+}
+
+// markdownLink разбирает ссылку [текст](адрес) в позиции start; адрес должен начинаться с http:// или https://
+func markdownLink(text string, start int) (string, string, int, bool) {
+	rest := text[start+1:]
+	labelEnd := strings.Index(rest, "](")
+	if labelEnd <= 0 || strings.ContainsAny(rest[:labelEnd], "[]\n") {
+		return "", "", 0, false
+	}
+
+	url := rest[labelEnd+2:]
+	urlEnd := strings.IndexByte(url, ')')
+	if urlEnd <= 0 || strings.ContainsAny(url[:urlEnd], " \n") {
+		return "", "", 0, false
+	}
+	url = url[:urlEnd]
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return "", "", 0, false
+	}
+
+	return rest[:labelEnd], url, start + 1 + labelEnd + 2 + urlEnd, true
+}
+
+// stripMarkdown убирает из текста упрощенную разметку ответов бота для отправки без разметки
+func stripMarkdown(text string) string {
+	var builder strings.Builder
+	builder.Grow(len(text))
+
+	for i := 0; i < len(text); {
+		switch text[i] {
+		case '*', '`':
+			if end, ok := markdownSpan(text, i, text[i]); ok {
+				builder.WriteString(text[i+1 : end])
+				i = end + 1
+				continue
+			}
+		case '[':
+			if label, url, end, ok := markdownLink(text, i); ok {
+				builder.WriteString(label + " (" + url + ")")
+				i = end + 1
+				continue
+			}
+		}
+		builder.WriteByte(text[i])
+		i++
+	}
+
+	return builder.String()
+}
+
+// isMarkdownError сообщает, что Telegram отклонил сообщение из-за ошибки в разметке
+func isMarkdownError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "can't parse entities")
+}
@@ -0,0 +1,71 @@
+package telegram
+
+import (
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+)
+
+// Messenger реализует интерфейс MessengerService поверх Telegram бота
+type Messenger struct {
+	bot *Bot
+}
+
+// NewMessenger создает новый сервис отправки сообщений через Telegram бота
+func NewMessenger(bot *Bot) service.MessengerService {
+	return &Messenger{bot: bot}
+}
+
+// SendMessage отправляет текстовое сообщение и возвращает его ID
+func (m *Messenger) SendMessage(chatID int64, text string) (int, error) {
+	msg, err := m.bot.SendMessage(chatID, text)
+	if err != nil {
+		return 0, err
+	}
+	return msg.MessageID, nil
+}
+
+// SendReply отправляет текстовое сообщение в ответ на указанное сообщение и возвращает его ID
+func (m *Messenger) SendReply(chatID int64, replyToMessageID int, text string) (int, error) {
+	msg, err := m.bot.SendReplyMessage(chatID, replyToMessageID, text)
+	if err != nil {
+		return 0, err
+	}
+	return msg.MessageID, nil
+}
+
+// SendReplyWithButtons отправляет сообщение с inline-кнопками в ответ на указанное сообщение и возвращает его ID
+func (m *Messenger) SendReplyWithButtons(chatID int64, replyToMessageID int, text string, buttons [][]entity.MessageButton) (int, error) {
+	msg, err := m.bot.SendReplyMessageWithKeyboard(chatID, replyToMessageID, text, NewButtonsKeyboard(buttons))
+	if err != nil {
+		return 0, err
+	}
+	return msg.MessageID, nil
+}
+
+// SendDocument отправляет файл в ответ на указанное сообщение и возвращает ID сообщения с ним
+func (m *Messenger) SendDocument(chatID int64, replyToMessageID int, fileName string, data []byte, caption string) (int, error) {
+	msg, err := m.bot.SendReplyDocument(chatID, replyToMessageID, fileName, data, caption)
+	if err != nil {
+		return 0, err
+	}
+	return msg.MessageID, nil
+}
+
+// SendVoice отправляет голосовое сообщение в ответ на указанное сообщение и возвращает его ID
+func (m *Messenger) SendVoice(chatID int64, replyToMessageID int, fileName string, data []byte, caption string) (int, error) {
+	msg, err := m.bot.SendReplyVoice(chatID, replyToMessageID, fileName, data, caption)
+	if err != nil {
+		return 0, err
+	}
+	return msg.MessageID, nil
+}
+
+// EditMessage заменяет текст ранее отправленного сообщения
+func (m *Messenger) EditMessage(chatID int64, messageID int, text string) error {
+	return m.bot.EditMessageText(chatID, messageID, text)
+}
+
+// DeleteMessage удаляет ранее отправленное сообщение
+func (m *Messenger) DeleteMessage(chatID int64, messageID int) error {
+	return m.bot.DeleteMessage(chatID, messageID)
+}
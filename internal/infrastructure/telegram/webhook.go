@@ -0,0 +1,116 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// webhookSecretHeader - заголовок, в котором Telegram передает секрет, указанный при регистрации вебхука
+const webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// webhookShutdownTimeout - сколько ждать завершения принятых запросов при остановке бота
+const webhookShutdownTimeout = 10 * time.Second
+
+// WebhookOptions содержит настройки получения обновлений через вебхук
+type WebhookOptions struct {
+	URL            string // Публичный адрес вебхука (HTTPS); путь адреса обслуживается слушателем
+	ListenAddr     string // Адрес, на котором слушатель принимает запросы от Telegram или балансировщика
+	Secret         string // Секрет, которым Telegram подписывает запросы; запросы без него отклоняются
+	MaxConnections int    // Сколько одновременных запросов Telegram отправляет на вебхук (0 - по умолчанию Telegram)
+}
+
+// StartWebhook регистрирует вебхук в Telegram и принимает обновления HTTP-запросами вместо опроса.
+// Обновления обрабатываются так же, как при опросе. Возвращает управление после остановки бота
+func (b *Bot) StartWebhook(opts WebhookOptions) error {
+	ctx := context.Background()
+	b.logger.Info("Starting Telegram bot with webhook",
+		"username", b.api.Self.UserName,
+		"listen_addr", opts.ListenAddr,
+	)
+
+	webhookURL, err := url.Parse(opts.URL)
+	if err != nil || webhookURL.Scheme != "https" || webhookURL.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q: an absolute https URL is required", opts.URL)
+	}
+	if opts.Secret == "" {
+		return errors.New("webhook secret is required")
+	}
+	path := webhookURL.Path
+	if path == "" {
+		path = "/"
+	}
+
+	// Регистрация вебхука: секрет передается параметром, которого нет в WebhookConfig библиотеки
+	params := tgbotapi.Params{}
+	params.AddNonEmpty("url", webhookURL.String())
+	params.AddNonEmpty("secret_token", opts.Secret)
+	params.AddNonZero("max_connections", opts.MaxConnections)
+	if _, err := b.api.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("failed to set telegram webhook: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		b.handleWebhookRequest(ctx, w, r, opts.Secret)
+	})
+	server := &http.Server{
+		Addr:              opts.ListenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- fmt.Errorf("failed to start webhook listener: %w", err)
+			return
+		}
+		serveErr <- nil
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-b.stop:
+		// Вебхук в Telegram не удаляется: при работе за балансировщиком обновления получают другие экземпляры
+		b.logger.Info("Stopping Telegram bot")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to stop webhook listener: %w", err)
+		}
+		return nil
+	}
+}
+
+// handleWebhookRequest принимает обновление от Telegram и передает его на обработку.
+// Ответ отправляется сразу: Telegram повторяет обновления, на которые долго нет ответа
+func (b *Bot) handleWebhookRequest(ctx context.Context, w http.ResponseWriter, r *http.Request, secret string) {
+	if secret == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get(webhookSecretHeader)), []byte(secret)) != 1 {
+		b.logger.Warn("Rejected webhook request with invalid secret",
+			"remote_addr", r.RemoteAddr,
+		)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	update, err := b.api.HandleUpdate(r)
+	if err != nil {
+		b.logger.Warn("Failed to decode webhook update",
+			"error", err,
+		)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	go b.handleUpdate(ctx, *update)
+}
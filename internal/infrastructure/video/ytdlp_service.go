@@ -0,0 +1,203 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	"github.com/112Alex/project_obsidian/pkg/tempfs"
+)
+
+// Ограничения загрузки видео
+const (
+	infoFetchTimeout     = time.Minute
+	audioDownloadTimeout = 30 * time.Minute
+	// audioBytesPerSecond - оценка размера звуковой дорожки (около 190 кбит/с) для резервирования места
+	audioBytesPerSecond = 24 << 10
+	// minAudioReservation - резерв места для видео неизвестной длительности
+	minAudioReservation = 64 << 20
+)
+
+// ytdlpInfo описывает нужные поля описания видео, которое выдает yt-dlp
+type ytdlpInfo struct {
+	Title      string  `json:"title"`
+	Duration   float64 `json:"duration"`
+	WebpageURL string  `json:"webpage_url"`
+	Chapters   []struct {
+		Title     string  `json:"title"`
+		StartTime float64 `json:"start_time"`
+		EndTime   float64 `json:"end_time"`
+	} `json:"chapters"`
+}
+
+// YTDLPService представляет собой сервис загрузки видео по ссылке через yt-dlp
+type YTDLPService struct {
+	binaryPath string
+	tempSpace  *tempfs.Space
+	logger     *logger.Logger
+}
+
+// NewYTDLPService создает новый сервис загрузки видео по ссылке
+func NewYTDLPService(binaryPath string, tempSpace *tempfs.Space, logger *logger.Logger) *YTDLPService {
+	return &YTDLPService{
+		binaryPath: binaryPath,
+		tempSpace:  tempSpace,
+		logger:     logger,
+	}
+}
+
+// FetchInfo возвращает название, длительность и главы видео, не загружая его
+func (s *YTDLPService) FetchInfo(ctx context.Context, videoURL string) (*entity.VideoInfo, error) {
+	// Логирование начала получения описания видео
+	s.logger.Info("Fetching video info",
+		"url", videoURL,
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, infoFetchTimeout)
+	defer cancel()
+
+	var output []byte
+	err := metrics.Track(ctx, s.logger, metrics.ProviderVideo, "fetch_info", func(ctx context.Context) error {
+		cmd := exec.CommandContext(ctx, s.binaryPath, "--dump-single-json", "--no-playlist", "--no-warnings", "--", videoURL)
+		var err error
+		output, err = cmd.Output()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to fetch video info",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to fetch video info: %w", err)
+	}
+
+	var info ytdlpInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse video info: %w", err)
+	}
+
+	video := &entity.VideoInfo{
+		URL:      videoURL,
+		Title:    strings.TrimSpace(info.Title),
+		Duration: info.Duration,
+		Chapters: make([]*entity.VideoChapter, 0, len(info.Chapters)),
+	}
+	if info.WebpageURL != "" {
+		video.URL = info.WebpageURL
+	}
+
+	for _, chapter := range info.Chapters {
+		end := chapter.EndTime
+		if end <= 0 {
+			end = info.Duration
+		}
+		// Главы без продолжительности не дают фрагментов для краткого содержания
+		if end <= chapter.StartTime {
+			continue
+		}
+		video.Chapters = append(video.Chapters, &entity.VideoChapter{
+			Position:     len(video.Chapters),
+			Title:        strings.TrimSpace(chapter.Title),
+			StartSeconds: int(chapter.StartTime),
+			EndSeconds:   int(end),
+		})
+	}
+
+	// Логирование успешного получения описания видео
+	s.logger.Info("Video info fetched successfully",
+		"title", video.Title,
+		"duration", video.Duration,
+		"chapters", len(video.Chapters),
+	)
+
+	return video, nil
+}
+
+// DownloadAudio загружает звуковую дорожку видео во временный каталог и возвращает путь к файлу
+func (s *YTDLPService) DownloadAudio(ctx context.Context, userID int64, video *entity.VideoInfo) (string, error) {
+	// Логирование начала загрузки звуковой дорожки
+	s.logger.Info("Downloading video audio",
+		"url", video.URL,
+		"duration", video.Duration,
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, audioDownloadTimeout)
+	defer cancel()
+
+	// Резервирование места во временном каталоге на время загрузки
+	size := int64(video.Duration * audioBytesPerSecond)
+	if size < minAudioReservation {
+		size = minAudioReservation
+	}
+	release, err := s.tempSpace.Reserve(ctx, size)
+	if err != nil {
+		return "", fmt.Errorf("failed to reserve temp space: %w", err)
+	}
+	defer release()
+
+	// Создание директории для сохранения файлов пользователя
+	userDir := filepath.Join(s.tempSpace.Dir(), "uploads", fmt.Sprintf("user_%d", userID))
+	if err := os.MkdirAll(userDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create user directory: %w", err)
+	}
+
+	// Расширение файла зависит от формата дорожки, поэтому yt-dlp сообщает итоговый путь сам
+	outputTemplate := filepath.Join(userDir, fmt.Sprintf("video_%d.%%(ext)s", time.Now().UnixNano()))
+
+	var filePath string
+	err = metrics.Track(ctx, s.logger, metrics.ProviderVideo, "download_audio", func(ctx context.Context) error {
+		cmd := exec.CommandContext(
+			ctx,
+			s.binaryPath,
+			"--no-playlist",
+			"--no-warnings",
+			"-f", "bestaudio/best",
+			"--max-filesize", strconv.FormatInt(size, 10),
+			"-o", outputTemplate,
+			"--no-simulate",
+			"--print", "after_move:filepath",
+			"--",
+			video.URL,
+		)
+		output, err := cmd.Output()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		if err != nil {
+			return err
+		}
+
+		filePath = strings.TrimSpace(string(output))
+		if filePath == "" {
+			return fmt.Errorf("audio file was not downloaded")
+		}
+		return nil
+	})
+	if err != nil {
+		if filePath != "" {
+			os.Remove(filePath)
+		}
+		s.logger.Error("Failed to download video audio",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to download video audio: %w", err)
+	}
+
+	// Логирование успешной загрузки звуковой дорожки
+	s.logger.Info("Video audio downloaded successfully",
+		"path", filePath,
+	)
+
+	return filePath, nil
+}
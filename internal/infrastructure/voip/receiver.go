@@ -0,0 +1,83 @@
+package voip
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Провайдеры, вебхуки которых принимаются по адресу /voip/<провайдер>
+const (
+	ProviderTwilio  = "twilio"
+	ProviderZadarma = "zadarma"
+)
+
+// requestTimeout - ограничение времени запроса к API провайдера
+const requestTimeout = 30 * time.Second
+
+var (
+	// ErrUnknownProvider - провайдер неизвестен или не настроен
+	ErrUnknownProvider = errors.New("voip provider is not configured")
+	// ErrInvalidSignature - подпись вебхука не совпала: запрос пришел не от провайдера
+	ErrInvalidSignature = errors.New("invalid voip webhook signature")
+	// ErrInvalidRequest - уведомление некорректно (например, недопустимое значение проверки адреса)
+	ErrInvalidRequest = errors.New("invalid voip webhook request")
+)
+
+// Receiver представляет собой приемник вебхуков VoIP провайдеров о звонках и их записях. Он проверяет
+// подпись уведомления секретом провайдера и приводит уведомления разных провайдеров к entity.CallEvent
+type Receiver struct {
+	publicURL        string
+	twilioAuthToken  string
+	zadarmaAPIKey    string
+	zadarmaAPISecret string
+	client           *http.Client
+	logger           *logger.Logger
+}
+
+// NewReceiver создает новый приемник вебхуков VoIP провайдеров. publicURL - публичный адрес HTTP сервера,
+// по которому Twilio подписывает запросы; провайдер с пустым секретом отключен
+func NewReceiver(publicURL, twilioAuthToken, zadarmaAPIKey, zadarmaAPISecret string, logger *logger.Logger) *Receiver {
+	return &Receiver{
+		publicURL:        strings.TrimRight(publicURL, "/"),
+		twilioAuthToken:  twilioAuthToken,
+		zadarmaAPIKey:    zadarmaAPIKey,
+		zadarmaAPISecret: zadarmaAPISecret,
+		client:           &http.Client{Timeout: requestTimeout},
+		logger:           logger,
+	}
+}
+
+// Parse проверяет подпись уведомления провайдера и разбирает его. Возвращает событие и тело ответа провайдеру;
+// событие равно nil, если уведомление не о завершенном звонке или его записи (например, проверка адреса)
+func (r *Receiver) Parse(provider string, req *http.Request) (*entity.CallEvent, string, error) {
+	switch {
+	case provider == ProviderTwilio && r.twilioAuthToken != "":
+		return r.parseTwilio(req)
+	case provider == ProviderZadarma && r.zadarmaAPISecret != "":
+		return r.parseZadarma(req)
+	}
+	return nil, "", ErrUnknownProvider
+}
+
+// ResolveRecording запрашивает ссылку на запись, если провайдер сообщил о записи без ссылки
+func (r *Receiver) ResolveRecording(ctx context.Context, event *entity.CallEvent) error {
+	if event.RecordingURL != "" || event.RecordingID == "" {
+		return nil
+	}
+	if event.Call.Provider != ProviderZadarma {
+		return ErrUnknownProvider
+	}
+
+	link, err := r.zadarmaRecordingLink(ctx, event.RecordingID)
+	if err != nil {
+		return err
+	}
+	event.RecordingURL = link
+	return nil
+}
@@ -0,0 +1,115 @@
+package voip
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+)
+
+// twilioEmptyResponse - пустой ответ TwiML: Twilio ждет TwiML в ответ на обратные вызовы <Record> и <Dial>
+const twilioEmptyResponse = `<?xml version="1.0" encoding="UTF-8"?><Response></Response>`
+
+// parseTwilio разбирает обратный вызов Twilio: RecordingStatusCallback с готовой записью, action глаголов
+// <Record> и <Dial record> или StatusCallback завершенного звонка. Обратный вызов записи не содержит номеров,
+// поэтому их можно передать в адресе обратного вызова параметрами from и to
+func (r *Receiver) parseTwilio(req *http.Request) (*entity.CallEvent, string, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, "", err
+	}
+	if !r.validTwilioSignature(req) {
+		return nil, "", ErrInvalidSignature
+	}
+
+	form := req.Form
+	callID := form.Get("CallSid")
+	if callID == "" {
+		return nil, twilioEmptyResponse, nil
+	}
+
+	call := entity.CallRecording{
+		Provider: ProviderTwilio,
+		CallID:   callID,
+		Caller:   firstNonEmpty(form.Get("From"), form.Get("from")),
+		Callee:   firstNonEmpty(form.Get("To"), form.Get("to")),
+	}
+	if direction := form.Get("Direction"); direction != "" {
+		call.Direction = entity.CallDirectionIncoming
+		if strings.HasPrefix(direction, "outbound") {
+			call.Direction = entity.CallDirectionOutgoing
+		}
+	}
+	if startedAt, err := time.Parse(time.RFC1123Z, form.Get("RecordingStartTime")); err == nil {
+		call.StartedAt = &startedAt
+	}
+	call.Duration, _ = strconv.Atoi(firstNonEmpty(form.Get("CallDuration"), form.Get("RecordingDuration")))
+
+	event := &entity.CallEvent{Call: call}
+
+	// Запись без статуса приходит в action глаголов; в RecordingStatusCallback учитывается только готовая запись
+	status := form.Get("RecordingStatus")
+	if recordingURL := form.Get("RecordingUrl"); recordingURL != "" && (status == "" || status == "completed") {
+		// Без расширения Twilio отдает запись в WAV; MP3 в несколько раз меньше
+		event.RecordingURL = recordingURL + ".mp3"
+	} else if status == "" && form.Get("CallStatus") != "completed" {
+		// Промежуточные статусы звонка не нужны: данные о звонке берутся из его завершения
+		return nil, twilioEmptyResponse, nil
+	}
+
+	return event, twilioEmptyResponse, nil
+}
+
+// validTwilioSignature проверяет заголовок X-Twilio-Signature: HMAC-SHA1 публичного адреса запроса
+// с параметрами тела POST, отсортированными по имени и записанными подряд как имя и значение
+func (r *Receiver) validTwilioSignature(req *http.Request) bool {
+	signature, err := base64.StdEncoding.DecodeString(req.Header.Get("X-Twilio-Signature"))
+	if err != nil || len(signature) == 0 {
+		return false
+	}
+
+	var data strings.Builder
+	data.WriteString(r.publicURL + req.URL.RequestURI())
+	if req.Method == http.MethodPost {
+		data.WriteString(sortedFormString(req.PostForm))
+	}
+
+	mac := hmac.New(sha1.New, []byte(r.twilioAuthToken))
+	mac.Write([]byte(data.String()))
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
+// sortedFormString записывает параметры формы подряд в порядке имен: имя и значение без разделителей
+func sortedFormString(form url.Values) string {
+	keys := make([]string, 0, len(form))
+	for key := range form {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for _, key := range keys {
+		values := append([]string(nil), form[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			builder.WriteString(key + value)
+		}
+	}
+	return builder.String()
+}
+
+// firstNonEmpty возвращает первое непустое значение
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
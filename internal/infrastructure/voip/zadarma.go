@@ -0,0 +1,161 @@
+package voip
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+)
+
+// Адрес API Zadarma и метод получения ссылки на запись разговора
+const (
+	zadarmaAPIURL        = "https://api.zadarma.com"
+	zadarmaRecordRequest = "/v1/pbx/record/request/"
+)
+
+// zadarmaTimeLayout - формат времени начала звонка в уведомлениях Zadarma (часовой пояс личного кабинета)
+const zadarmaTimeLayout = "2006-01-02 15:04:05"
+
+// Уведомления АТС Zadarma, которые учитываются приемником
+const (
+	zadarmaNotifyEnd    = "NOTIFY_END"     // Завершение входящего звонка
+	zadarmaNotifyOutEnd = "NOTIFY_OUT_END" // Завершение исходящего звонка
+	zadarmaNotifyRecord = "NOTIFY_RECORD"  // Запись разговора готова
+)
+
+// zadarmaEchoPattern - допустимое значение zd_echo: Zadarma присылает короткий буквенно-цифровой токен,
+// а все остальное отклоняется, чтобы адрес вебхука не возвращал произвольный текст
+var zadarmaEchoPattern = regexp.MustCompile(`^[A-Za-z0-9]{1,64}$`)
+
+// zadarmaRecordResponse представляет собой ответ API Zadarma со ссылкой на запись разговора
+type zadarmaRecordResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Link    string `json:"link"`
+}
+
+// parseZadarma разбирает уведомление АТС Zadarma. Проверка адреса уведомлений приходит GET-запросом
+// с параметром zd_echo, значение которого нужно вернуть; данные о звонке приходят в уведомлении
+// о его завершении, а о готовой записи - отдельным уведомлением NOTIFY_RECORD без ссылки на нее
+func (r *Receiver) parseZadarma(req *http.Request) (*entity.CallEvent, string, error) {
+	if echo := req.URL.Query().Get("zd_echo"); req.Method == http.MethodGet && echo != "" {
+		if !zadarmaEchoPattern.MatchString(echo) {
+			return nil, "", ErrInvalidRequest
+		}
+		return nil, echo, nil
+	}
+	if err := req.ParseForm(); err != nil {
+		return nil, "", err
+	}
+
+	form := req.PostForm
+	var signed string
+	call := entity.CallRecording{
+		Provider: ProviderZadarma,
+		CallID:   form.Get("pbx_call_id"),
+	}
+	switch event := form.Get("event"); event {
+	case zadarmaNotifyEnd:
+		signed = form.Get("caller_id") + form.Get("called_did") + form.Get("call_start")
+		call.Direction = entity.CallDirectionIncoming
+		call.Caller = form.Get("caller_id")
+		call.Callee = form.Get("called_did")
+	case zadarmaNotifyOutEnd:
+		signed = form.Get("internal") + form.Get("destination") + form.Get("call_start")
+		call.Direction = entity.CallDirectionOutgoing
+		call.Caller = form.Get("internal")
+		call.Callee = form.Get("destination")
+	case zadarmaNotifyRecord:
+		signed = form.Get("pbx_call_id") + form.Get("call_id_with_rec")
+	default:
+		// Начало звонка и остальные уведомления не нужны
+		return nil, "", nil
+	}
+	if !r.validZadarmaSignature(req.Header.Get("Signature"), signed) {
+		return nil, "", ErrInvalidSignature
+	}
+	if call.CallID == "" {
+		return nil, "", nil
+	}
+
+	if form.Get("event") == zadarmaNotifyRecord {
+		return &entity.CallEvent{Call: call, RecordingID: form.Get("call_id_with_rec")}, "", nil
+	}
+
+	// Неотвеченные звонки не записываются
+	if disposition := form.Get("disposition"); disposition != "" && disposition != "answered" {
+		return nil, "", nil
+	}
+	if startedAt, err := time.ParseInLocation(zadarmaTimeLayout, form.Get("call_start"), time.Local); err == nil {
+		call.StartedAt = &startedAt
+	}
+	call.Duration, _ = strconv.Atoi(form.Get("duration"))
+
+	return &entity.CallEvent{Call: call}, "", nil
+}
+
+// validZadarmaSignature проверяет заголовок Signature: Base64 от шестнадцатеричной записи HMAC-SHA1
+// полей уведомления секретом API
+func (r *Receiver) validZadarmaSignature(signature, data string) bool {
+	expected := r.zadarmaSign(data)
+	return signature != "" && hmac.Equal([]byte(signature), []byte(expected))
+}
+
+// zadarmaSign возвращает подпись Zadarma: Base64 от шестнадцатеричной записи HMAC-SHA1 данных секретом API
+func (r *Receiver) zadarmaSign(data string) string {
+	mac := hmac.New(sha1.New, []byte(r.zadarmaAPISecret))
+	mac.Write([]byte(data))
+	return base64.StdEncoding.EncodeToString([]byte(hex.EncodeToString(mac.Sum(nil))))
+}
+
+// zadarmaRecordingLink запрашивает временную ссылку на запись разговора по ID звонка с записью
+func (r *Receiver) zadarmaRecordingLink(ctx context.Context, recordingID string) (string, error) {
+	params := url.Values{"call_id": {recordingID}}.Encode()
+	md5Sum := md5.Sum([]byte(params))
+	signature := r.zadarmaSign(zadarmaRecordRequest + params + hex.EncodeToString(md5Sum[:]))
+
+	var response zadarmaRecordResponse
+	err := metrics.Track(ctx, r.logger, metrics.ProviderZadarma, "record_request", func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, zadarmaAPIURL+zadarmaRecordRequest+"?"+params, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", r.zadarmaAPIKey+":"+signature)
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return fmt.Errorf("failed to decode zadarma response (status %d): %w", resp.StatusCode, err)
+		}
+		if response.Status != "success" || response.Link == "" {
+			return fmt.Errorf("zadarma returned %s: %s", response.Status, strings.TrimSpace(response.Message))
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Warn("Failed to request Zadarma recording link",
+			"error", err,
+			"call_id", recordingID,
+		)
+		return "", fmt.Errorf("failed to request zadarma recording link: %w", err)
+	}
+
+	return response.Link, nil
+}
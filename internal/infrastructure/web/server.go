@@ -0,0 +1,361 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+)
+
+// ShareResolver представляет собой функцию получения задачи по токену публичной ссылки
+type ShareResolver func(ctx context.Context, token string) (*entity.Job, error)
+
+// AttachmentResolver представляет собой функцию получения приложенной к задаче фотографии по токену
+type AttachmentResolver func(ctx context.Context, token string) (*entity.JobAttachment, error)
+
+// NotionOAuthHandler представляет собой функцию завершения входа в Notion по параметрам обратного вызова
+type NotionOAuthHandler func(ctx context.Context, state, code string) error
+
+// CallWebhookHandler представляет собой функцию обработки вебхука VoIP провайдера о звонке или его записи.
+// Возвращает тело ответа провайдеру
+type CallWebhookHandler func(ctx context.Context, provider string, r *http.Request) (string, error)
+
+// NotionCallbackPath - путь обратного вызова OAuth Notion; адрес HTTP_PUBLIC_URL с этим путем указывается
+// в настройках публичной интеграции Notion
+const NotionCallbackPath = "/notion/callback"
+
+// ErrNotionOAuthExpired - ссылка на вход в Notion устарела или уже использована
+var ErrNotionOAuthExpired = errors.New("notion oauth link expired")
+
+var (
+	// ErrCallWebhookProvider - VoIP провайдер из адреса вебхука неизвестен или не настроен
+	ErrCallWebhookProvider = errors.New("unknown voip provider")
+	// ErrCallWebhookSignature - подпись вебхука VoIP провайдера не прошла проверку
+	ErrCallWebhookSignature = errors.New("invalid voip webhook signature")
+	// ErrCallWebhookRequest - вебхук VoIP провайдера некорректен
+	ErrCallWebhookRequest = errors.New("invalid voip webhook request")
+)
+
+// maxCallWebhookBodySize - ограничение размера тела вебхука VoIP провайдера
+const maxCallWebhookBodySize = 1 << 20
+
+// Server представляет собой HTTP сервер приложения
+type Server struct {
+	server        *http.Server
+	mux           *http.ServeMux
+	logger        *logger.Logger
+	shareResolver ShareResolver
+
+	attachmentResolver AttachmentResolver
+	notionOAuthHandler NotionOAuthHandler
+	callWebhookHandler CallWebhookHandler
+}
+
+// NewServer создает новый HTTP сервер
+func NewServer(addr string, logger *logger.Logger) *Server {
+	mux := http.NewServeMux()
+
+	s := &Server{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		mux:    mux,
+		logger: logger,
+	}
+
+	mux.HandleFunc("GET /share/{token}", s.handleShare)
+	mux.HandleFunc("GET /attachments/{token}", s.handleAttachment)
+	mux.HandleFunc("GET "+NotionCallbackPath, s.handleNotionCallback)
+	mux.HandleFunc("GET /voip/{provider}", s.handleCallWebhook)
+	mux.HandleFunc("POST /voip/{provider}", s.handleCallWebhook)
+	mux.Handle("GET /metrics", metrics.Default.Handler())
+
+	return s
+}
+
+// RegisterShareResolver регистрирует функцию получения задачи по токену публичной ссылки
+func (s *Server) RegisterShareResolver(resolver ShareResolver) {
+	s.shareResolver = resolver
+}
+
+// RegisterAttachmentResolver регистрирует функцию получения приложенной к задаче фотографии по токену
+func (s *Server) RegisterAttachmentResolver(resolver AttachmentResolver) {
+	s.attachmentResolver = resolver
+}
+
+// RegisterNotionOAuthHandler регистрирует функцию завершения входа в Notion через OAuth
+func (s *Server) RegisterNotionOAuthHandler(handler NotionOAuthHandler) {
+	s.notionOAuthHandler = handler
+}
+
+// RegisterCallWebhookHandler регистрирует функцию обработки вебхуков VoIP провайдеров
+func (s *Server) RegisterCallWebhookHandler(handler CallWebhookHandler) {
+	s.callWebhookHandler = handler
+}
+
+// Start запускает HTTP сервер
+func (s *Server) Start() error {
+	s.logger.Info("Starting HTTP server", "addr", s.server.Addr)
+
+	err := s.server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to start http server: %w", err)
+	}
+
+	return nil
+}
+
+// Stop останавливает HTTP сервер
+func (s *Server) Stop(ctx context.Context) error {
+	s.logger.Info("Stopping HTTP server")
+	return s.server.Shutdown(ctx)
+}
+
+// handleShare отдает страницу с результатами задачи по публичной ссылке
+func (s *Server) handleShare(w http.ResponseWriter, r *http.Request) {
+	if s.shareResolver == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	token := r.PathValue("token")
+
+	// Получение задачи по токену
+	job, err := s.shareResolver(r.Context(), token)
+	if err != nil {
+		s.logger.Warn("Failed to resolve share link", "error", err)
+		http.NotFound(w, r)
+		return
+	}
+
+	// Запрет индексации и кэширования публичных страниц
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+
+	if err := sharePageTemplate.Execute(w, newSharePage(requestLanguage(r), job)); err != nil {
+		s.logger.Error("Failed to render share page", "error", err)
+	}
+}
+
+// handleAttachment отдает приложенную к задаче фотографию; адрес используется страницей задачи в Notion
+func (s *Server) handleAttachment(w http.ResponseWriter, r *http.Request) {
+	if s.attachmentResolver == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	token := r.PathValue("token")
+
+	// Получение фотографии по токену
+	attachment, err := s.attachmentResolver(r.Context(), token)
+	if err != nil {
+		s.logger.Warn("Failed to resolve attachment", "error", err)
+		http.NotFound(w, r)
+		return
+	}
+
+	// Содержимое по токену не меняется, поэтому его можно кэшировать
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	w.Header().Set("X-Robots-Tag", "noindex")
+
+	if _, err := w.Write(attachment.Image); err != nil {
+		s.logger.Error("Failed to write attachment", "error", err)
+	}
+}
+
+// handleNotionCallback завершает вход в Notion: Notion возвращает сюда пользователя с кодом и state
+// или с ошибкой, если пользователь отказался дать доступ
+func (s *Server) handleNotionCallback(w http.ResponseWriter, r *http.Request) {
+	if s.notionOAuthHandler == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	language := requestLanguage(r)
+	page := newNotionCallbackPage(language, "connected")
+	status := http.StatusOK
+	switch {
+	case query.Get("error") != "":
+		page = newNotionCallbackPage(language, "cancelled")
+	case query.Get("state") == "" || query.Get("code") == "":
+		status = http.StatusBadRequest
+		page = newNotionCallbackPage(language, "invalid")
+	default:
+		// Настройка интеграции не прерывается, если пользователь закроет страницу
+		err := s.notionOAuthHandler(context.WithoutCancel(r.Context()), query.Get("state"), query.Get("code"))
+		switch {
+		case errors.Is(err, ErrNotionOAuthExpired):
+			status = http.StatusBadRequest
+			page = newNotionCallbackPage(language, "expired")
+		case err != nil:
+			s.logger.Error("Failed to complete Notion OAuth", "error", err)
+			status = http.StatusInternalServerError
+			page = newNotionCallbackPage(language, "failed")
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.WriteHeader(status)
+
+	if err := notionCallbackTemplate.Execute(w, page); err != nil {
+		s.logger.Error("Failed to render Notion callback page", "error", err)
+	}
+}
+
+// handleCallWebhook принимает вебхук VoIP провайдера о звонке или готовой записи звонка
+func (s *Server) handleCallWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.callWebhookHandler == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxCallWebhookBodySize)
+	body, err := s.callWebhookHandler(r.Context(), r.PathValue("provider"), r)
+	switch {
+	case errors.Is(err, ErrCallWebhookProvider):
+		http.NotFound(w, r)
+		return
+	case errors.Is(err, ErrCallWebhookSignature):
+		s.logger.Warn("Rejected VoIP webhook with invalid signature",
+			"provider", r.PathValue("provider"),
+			"remote_addr", r.RemoteAddr,
+		)
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	case errors.Is(err, ErrCallWebhookRequest):
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	case err != nil:
+		s.logger.Error("Failed to handle VoIP webhook", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	// Тип ответа задается явно, чтобы браузер не определял его по содержимому: Twilio ждет TwiML,
+	// остальным провайдерам возвращается простой текст
+	contentType := "text/plain; charset=utf-8"
+	if strings.HasPrefix(body, "<?xml") {
+		contentType = "text/xml; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err := w.Write([]byte(body)); err != nil {
+		s.logger.Error("Failed to write VoIP webhook response", "error", err)
+	}
+}
+
+// notionCallbackPage - содержимое страницы результата входа в Notion
+type notionCallbackPage struct {
+	Lang  string
+	Title string
+	Text  string
+}
+
+// newNotionCallbackPage возвращает страницу результата входа в Notion на языке language;
+// result - исход входа, по которому выбираются тексты из каталога сообщений
+func newNotionCallbackPage(language, result string) notionCallbackPage {
+	return notionCallbackPage{
+		Lang:  language,
+		Title: i18n.Translate(language, "notion_oauth.page."+result+".title"),
+		Text:  i18n.Translate(language, "notion_oauth.page."+result+".text"),
+	}
+}
+
+// sharePage - содержимое страницы с результатами задачи: задача и подписи на языке посетителя
+type sharePage struct {
+	Lang              string
+	Title             string
+	Created           string
+	SummaryHeading    string
+	TranscriptHeading string
+	Summary           string
+	Transcription     string
+}
+
+// newSharePage возвращает страницу с результатами задачи job на языке language
+func newSharePage(language string, job *entity.Job) sharePage {
+	title := job.FileName
+	if job.Type == entity.JobTypeText {
+		title = i18n.Translate(language, "jobs.text_title")
+	}
+	if title == "" {
+		title = i18n.Translate(language, "share.page.untitled")
+	}
+	return sharePage{
+		Lang:              language,
+		Title:             title,
+		Created:           i18n.Translate(language, "share.page.created", job.CreatedAt.Format("02.01.2006 15:04")),
+		SummaryHeading:    i18n.Translate(language, "share.page.summary"),
+		TranscriptHeading: i18n.Translate(language, "share.page.transcription"),
+		Summary:           job.Summary,
+		Transcription:     job.Transcription,
+	}
+}
+
+// requestLanguage возвращает язык интерфейса по первому языку из заголовка Accept-Language браузера
+func requestLanguage(r *http.Request) string {
+	header := r.Header.Get("Accept-Language")
+	code, _, _ := strings.Cut(header, ",")
+	code, _, _ = strings.Cut(code, ";")
+	return i18n.Normalize(code)
+}
+
+// notionCallbackTemplate - шаблон страницы результата входа в Notion
+var notionCallbackTemplate = template.Must(template.New("notion_callback").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; max-width: 760px; margin: 2em auto; padding: 0 1em; line-height: 1.5; color: #222; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Text}}</p>
+</body>
+</html>
+`))
+
+// sharePageTemplate - шаблон страницы с результатами задачи
+var sharePageTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; max-width: 760px; margin: 2em auto; padding: 0 1em; line-height: 1.5; color: #222; }
+pre { white-space: pre-wrap; font-family: inherit; }
+.meta { color: #777; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{.Created}}</p>
+{{if .Summary}}<h2>{{.SummaryHeading}}</h2>
+<pre>{{.Summary}}</pre>{{end}}
+{{if .Transcription}}<h2>{{.TranscriptHeading}}</h2>
+<pre>{{.Transcription}}</pre>{{end}}
+</body>
+</html>
+`))
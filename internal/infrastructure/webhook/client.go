@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	"github.com/112Alex/project_obsidian/pkg/safehttp"
+)
+
+// Client доставляет события задач на вебхуки пользователей. Тело запроса совпадает
+// с телом вебхука синхронизации и дополнено полями event и error
+type Client struct {
+	client *http.Client
+	logger *logger.Logger
+}
+
+// NewClient создает клиент доставки событий на вебхуки пользователей. Адреса вебхуков задают пользователи,
+// поэтому адреса локальной и внутренней сети недоступны
+func NewClient(logger *logger.Logger) *Client {
+	return &Client{
+		client: safehttp.NewClient(requestTimeout),
+		logger: logger,
+	}
+}
+
+// Deliver отправляет событие задачи на вебхук пользователя
+func (c *Client) Deliver(ctx context.Context, webhook *entity.UserWebhook, event entity.WebhookEvent, user *entity.User, job *entity.Job, errorMessage string) error {
+	p := newPayload(user, job)
+	p.Event = string(event)
+	p.Error = errorMessage
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	// Выполнение запроса
+	err = metrics.Track(ctx, c.logger, metrics.ProviderWebhook, "deliver_event", func(ctx context.Context) error {
+		return post(ctx, c.client, webhook.URL, webhook.Headers, body)
+	})
+	if err != nil {
+		c.logger.Warn("Failed to deliver webhook event",
+			"error", err,
+			"user_id", user.ID,
+			"event", event,
+		)
+		return fmt.Errorf("failed to deliver webhook event: %w", err)
+	}
+
+	return nil
+}
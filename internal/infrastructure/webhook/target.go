@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	"github.com/112Alex/project_obsidian/pkg/safehttp"
+)
+
+// requestTimeout - ограничение времени одного вызова вебхука
+const requestTimeout = 30 * time.Second
+
+// payload - тело запроса вебхука с результатами задачи
+type payload struct {
+	Event         string    `json:"event,omitempty"` // Событие задачи; заполняется для вебхуков пользователей
+	JobID         int64     `json:"job_id"`
+	TelegramID    int64     `json:"telegram_id"`
+	Type          string    `json:"type"`
+	FileName      string    `json:"file_name"`
+	CreatedAt     time.Time `json:"created_at"`
+	Summary       string    `json:"summary"`
+	Transcription string    `json:"transcription"`
+	Error         string    `json:"error,omitempty"` // Ошибка обработки для события job.failed
+}
+
+// newPayload формирует тело запроса вебхука с результатами задачи
+func newPayload(user *entity.User, job *entity.Job) payload {
+	return payload{
+		JobID:         job.ID,
+		TelegramID:    user.TelegramID,
+		Type:          string(job.Type),
+		FileName:      job.FileName,
+		CreatedAt:     job.CreatedAt,
+		Summary:       job.Summary,
+		Transcription: job.Transcription,
+	}
+}
+
+// post отправляет тело запроса POST-запросом с JSON на адрес url с дополнительными заголовками
+func post(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Target выгружает результаты задач POST-запросом с JSON на заданный URL
+type Target struct {
+	url    string
+	client *http.Client
+	logger *logger.Logger
+}
+
+// NewTarget создает место синхронизации, вызывающее вебхук по адресу url. Как и вебхуки пользователей,
+// он не может указывать на адрес локальной или внутренней сети
+func NewTarget(url string, logger *logger.Logger) *Target {
+	return &Target{
+		url:    url,
+		client: safehttp.NewClient(requestTimeout),
+		logger: logger,
+	}
+}
+
+// Name возвращает имя места синхронизации
+func (t *Target) Name() string {
+	return "webhook"
+}
+
+// Enabled сообщает, что вебхук вызывается для задач всех пользователей
+func (t *Target) Enabled(user *entity.User) bool {
+	return true
+}
+
+// Sync отправляет результаты задачи на вебхук. Ответ вебхука не используется,
+// поэтому ID результата не возвращается
+func (t *Target) Sync(ctx context.Context, user *entity.User, job *entity.Job) (string, error) {
+	body, err := json.Marshal(newPayload(user, job))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	// Выполнение запроса
+	err = metrics.Track(ctx, t.logger, metrics.ProviderWebhook, "post_result", func(ctx context.Context) error {
+		return post(ctx, t.client, t.url, nil, body)
+	})
+	if err != nil {
+		t.logger.Error("Failed to call webhook",
+			"error", err,
+			"job_id", job.ID,
+		)
+		return "", fmt.Errorf("failed to call webhook: %w", err)
+	}
+
+	return "", nil
+}
@@ -0,0 +1,302 @@
+package whisperlocal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/config"
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
+)
+
+// Пути распознавания на серверах разных API
+const (
+	whisperCppPath = "/inference"
+	openAIPath     = "/v1/audio/transcriptions"
+)
+
+// defaultModel - модель распознавания по умолчанию
+const defaultModel = "base"
+
+// maxErrorBodySize - сколько байт ответа с ошибкой попадает в текст ошибки
+const maxErrorBodySize = 1024
+
+// TranscriptionService представляет собой сервис транскрибации аудио локальным сервером whisper.cpp
+// или faster-whisper: записи распознаются без отправки в OpenAI
+type TranscriptionService struct {
+	client   *http.Client
+	baseURL  string
+	api      string
+	model    string
+	slots    chan struct{}
+	defaults entity.TranscriptionOptions
+	logger   *logger.Logger
+}
+
+// NewTranscriptionService создает новый сервис транскрибации локальным сервером. api - config.WhisperLocalAPIWhisperCpp
+// или config.WhisperLocalAPIOpenAI, concurrency - сколько записей распознается одновременно (<= 0 - одна).
+// defaults задает параметры декодирования, которые пользователь может переопределить
+func NewTranscriptionService(baseURL, api, model string, concurrency int, defaults entity.TranscriptionOptions, logger *logger.Logger) *TranscriptionService {
+	if model == "" {
+		model = defaultModel
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	// Как и в OpenAI, по умолчанию декодируем без случайности
+	if defaults.Temperature == nil {
+		temperature := float32(0)
+		defaults.Temperature = &temperature
+	}
+	if defaults.Granularity == "" {
+		defaults.Granularity = entity.TranscriptionGranularityText
+	}
+
+	return &TranscriptionService{
+		// Длительность распознавания ограничивает контекст этапа: локальная модель может работать дольше API
+		client:   &http.Client{},
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		api:      api,
+		model:    model,
+		slots:    make(chan struct{}, concurrency),
+		defaults: defaults,
+		logger:   logger,
+	}
+}
+
+// transcriptionResponse - ответ сервера в формате json или verbose_json; оба API возвращают его в виде OpenAI
+type transcriptionResponse struct {
+	Text     string `json:"text"`
+	Segments []struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+		Text  string  `json:"text"`
+	} `json:"segments"`
+}
+
+// Transcribe выполняет транскрибацию аудиофайла с параметрами по умолчанию
+func (s *TranscriptionService) Transcribe(ctx context.Context, audioFilePath string) (string, error) {
+	return s.TranscribeWithOptions(ctx, audioFilePath, entity.TranscriptionOptions{})
+}
+
+// TranscribeWithOptions транскрибирует аудиофайл с параметрами декодирования, переопределяющими значения
+// по умолчанию. Если сервер занят другими записями, ждет своей очереди
+func (s *TranscriptionService) TranscribeWithOptions(ctx context.Context, audioFilePath string, overrides entity.TranscriptionOptions) (string, error) {
+	opts := s.defaults.Merge(overrides)
+
+	// Логирование начала транскрибации
+	s.logger.Info("Transcribing audio with local server",
+		"path", audioFilePath,
+		"api", s.api,
+		"model", s.model,
+		"granularity", opts.Granularity,
+		"language", opts.Language,
+	)
+
+	resp, err := s.transcribe(ctx, audioFilePath, opts)
+	if err != nil {
+		s.logger.Error("Failed to transcribe audio with local server",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to transcribe audio with local server: %w", err)
+	}
+
+	text := strings.TrimSpace(resp.Text)
+	if opts.Granularity == entity.TranscriptionGranularitySegment && len(resp.Segments) > 0 {
+		text = formatSegments(resp)
+	}
+
+	// Логирование успешной транскрибации
+	s.logger.Info("Audio transcribed with local server successfully",
+		"text_length", len(text),
+	)
+
+	return text, nil
+}
+
+// TranscribeWithSpeakers транскрибирует аудиофайл по сегментам. Whisper не различает говорящих,
+// поэтому сегменты возвращаются без них
+func (s *TranscriptionService) TranscribeWithSpeakers(ctx context.Context, audioFilePath string, overrides entity.TranscriptionOptions) ([]entity.SpeakerSegment, error) {
+	opts := s.defaults.Merge(overrides)
+	opts.Granularity = entity.TranscriptionGranularitySegment
+
+	// Логирование начала транскрибации
+	s.logger.Info("Transcribing audio by segments with local server",
+		"path", audioFilePath,
+		"api", s.api,
+		"model", s.model,
+		"language", opts.Language,
+	)
+
+	resp, err := s.transcribe(ctx, audioFilePath, opts)
+	if err != nil {
+		s.logger.Error("Failed to transcribe audio by segments with local server",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to transcribe audio by segments with local server: %w", err)
+	}
+
+	segments := make([]entity.SpeakerSegment, 0, len(resp.Segments))
+	for _, segment := range resp.Segments {
+		segments = append(segments, entity.SpeakerSegment{
+			Start: segment.Start,
+			End:   segment.End,
+			Text:  strings.TrimSpace(segment.Text),
+		})
+	}
+	if len(segments) == 0 && strings.TrimSpace(resp.Text) != "" {
+		segments = append(segments, entity.SpeakerSegment{Text: strings.TrimSpace(resp.Text)})
+	}
+
+	// Логирование успешной транскрибации
+	s.logger.Info("Audio transcribed by segments with local server successfully",
+		"segments", len(segments),
+	)
+
+	return segments, nil
+}
+
+// transcribe дожидается свободного места на сервере и распознает запись
+func (s *TranscriptionService) transcribe(ctx context.Context, audioFilePath string, opts entity.TranscriptionOptions) (transcriptionResponse, error) {
+	var resp transcriptionResponse
+
+	// Ожидание свободного места на сервере
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		return resp, fmt.Errorf("failed to wait for local transcription slot: %w", ctx.Err())
+	}
+
+	// Выполнение запроса
+	err := metrics.Track(ctx, s.logger, metrics.ProviderWhisperLocal, "transcription", func(ctx context.Context) error {
+		return s.post(ctx, audioFilePath, opts, &resp)
+	})
+	return resp, err
+}
+
+// post отправляет запись на сервер распознавания. Файл передается потоком, не загружаясь в память целиком
+func (s *TranscriptionService) post(ctx context.Context, audioFilePath string, opts entity.TranscriptionOptions, out *transcriptionResponse) error {
+	audioFile, err := os.Open(audioFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer audioFile.Close()
+
+	pipeReader, pipeWriter := io.Pipe()
+	form := multipart.NewWriter(pipeWriter)
+	go func() {
+		pipeWriter.CloseWithError(s.writeForm(form, audioFile, opts))
+	}()
+	defer pipeReader.Close()
+
+	path := whisperCppPath
+	if s.api == config.WhisperLocalAPIOpenAI {
+		path = openAIPath
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+path, pipeReader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fmt.Errorf("local transcription server returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// writeForm записывает поля запроса распознавания и файл записи
+func (s *TranscriptionService) writeForm(form *multipart.Writer, audioFile *os.File, opts entity.TranscriptionOptions) error {
+	format := "json"
+	if opts.Granularity == entity.TranscriptionGranularitySegment {
+		format = "verbose_json"
+	}
+
+	// whisper.cpp без языка распознает речь на языке, заданном при запуске сервера, поэтому просим определить его
+	language := opts.Language
+	if language == "" && s.api == config.WhisperLocalAPIWhisperCpp {
+		language = "auto"
+	}
+
+	fields := map[string]string{
+		"model":           s.model,
+		"response_format": format,
+		"temperature":     strconv.FormatFloat(float64(*opts.Temperature), 'f', -1, 32),
+		"language":        language,
+		"prompt":          opts.Prompt,
+	}
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := form.WriteField(name, value); err != nil {
+			return fmt.Errorf("failed to write form field %s: %w", name, err)
+		}
+	}
+
+	part, err := form.CreateFormFile("file", filepath.Base(audioFile.Name()))
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, audioFile); err != nil {
+		return fmt.Errorf("failed to write audio file: %w", err)
+	}
+
+	return form.Close()
+}
+
+// formatSegments собирает текст из сегментов ответа, начиная каждый с метки времени [мм:сс]
+func formatSegments(resp transcriptionResponse) string {
+	segments := make([]textutil.Segment, 0, len(resp.Segments))
+	for _, segment := range resp.Segments {
+		segments = append(segments, textutil.Segment{Start: segment.Start, Text: segment.Text})
+	}
+	return textutil.FormatSegments(segments)
+}
+
+// Ping проверяет, что сервер распознавания отвечает: запрашивает корневую страницу, что не запускает распознавание
+func (s *TranscriptionService) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach local transcription server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("local transcription server returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Slots возвращает число записей, которые распознаются сейчас, и наибольшее их число
+func (s *TranscriptionService) Slots() (int, int) {
+	return len(s.slots), cap(s.slots)
+}
@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// AccountCallbackPrefix - префикс данных кнопок подтверждения команды /delete_account.
+// Данные кнопки имеют вид account:<действие>
+const AccountCallbackPrefix = "account"
+
+// Действия кнопок подтверждения удаления аккаунта
+const (
+	accountActionDelete  = "delete"
+	accountActionArchive = "archive"
+	accountActionCancel  = "cancel"
+)
+
+// accountDeletionPageSize - по сколько задач загружается при удалении аккаунта
+const accountDeletionPageSize = 100
+
+// accountArchiveFileName - имя файла со списком баз Notion, который присылается перед удалением аккаунта
+const accountArchiveFileName = "notion_databases.txt"
+
+// AccountDeletionUseCase представляет собой сценарий удаления пользователем своего аккаунта командой /delete_account.
+// Задачи, настройки, подписки и другие связанные записи удаляются из базы каскадно вместе с пользователем,
+// а файлы записей, кэш профиля и статистика использования - отдельно. Задачи, которые остались в очереди,
+// завершатся ошибкой, потому что их больше нет в базе
+type AccountDeletionUseCase struct {
+	userRepo              repository.UserRepository
+	jobRepo               repository.JobRepository
+	podcastRepo           repository.PodcastRepository
+	audioService          service.AudioService
+	messenger             service.MessengerService
+	usageAnalyticsUseCase *UsageAnalyticsUseCase
+	logger                *logger.Logger
+}
+
+// NewAccountDeletionUseCase создает новый сценарий удаления аккаунта
+func NewAccountDeletionUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	podcastRepo repository.PodcastRepository,
+	audioService service.AudioService,
+	messenger service.MessengerService,
+	usageAnalyticsUseCase *UsageAnalyticsUseCase,
+	logger *logger.Logger,
+) *AccountDeletionUseCase {
+	return &AccountDeletionUseCase{
+		userRepo:              userRepo,
+		jobRepo:               jobRepo,
+		podcastRepo:           podcastRepo,
+		audioService:          audioService,
+		messenger:             messenger,
+		usageAnalyticsUseCase: usageAnalyticsUseCase,
+		logger:                logger,
+	}
+}
+
+// HandleDeleteAccount обрабатывает команду /delete_account и возвращает предупреждение с кнопками подтверждения.
+// Если у пользователя подключен Notion, перед удалением можно получить список его баз Notion
+func (uc *AccountDeletionUseCase) HandleDeleteAccount(ctx context.Context, telegramID int64) (string, [][]entity.MessageButton, error) {
+	// Логирование начала обработки команды /delete_account
+	uc.logger.Info("Handling /delete_account command",
+		"telegram_id", telegramID,
+	)
+
+	// Пользователь, который не выполнял /start, еще не сохранен
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil || user == nil {
+		return i18n.T(ctx, "delete_account.no_data"), nil, nil
+	}
+
+	buttons := [][]entity.MessageButton{
+		{{Text: i18n.T(ctx, "delete_account.button.delete"), Data: AccountCallbackPrefix + ":" + accountActionDelete}},
+	}
+	if user.NotionDatabaseID != "" {
+		buttons = append(buttons, []entity.MessageButton{
+			{Text: i18n.T(ctx, "delete_account.button.archive"), Data: AccountCallbackPrefix + ":" + accountActionArchive},
+		})
+	}
+	buttons = append(buttons, []entity.MessageButton{
+		{Text: i18n.T(ctx, "delete_account.button.cancel"), Data: AccountCallbackPrefix + ":" + accountActionCancel},
+	})
+
+	return i18n.T(ctx, "delete_account.warning"), buttons, nil
+}
+
+// HandleAction обрабатывает нажатие кнопки подтверждения удаления аккаунта и возвращает текст,
+// которым заменяется предупреждение
+func (uc *AccountDeletionUseCase) HandleAction(ctx context.Context, telegramID int64, data string) (string, error) {
+	switch data {
+	case accountActionCancel:
+		return i18n.T(ctx, "delete_account.cancelled"), nil
+	case accountActionDelete, accountActionArchive:
+	default:
+		return "", fmt.Errorf("unknown account action: %s", data)
+	}
+
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil || user == nil {
+		// Повторное нажатие кнопки после удаления
+		return i18n.T(ctx, "delete_account.already_deleted"), nil
+	}
+
+	if err := uc.Delete(ctx, user, data == accountActionArchive); err != nil {
+		return "", err
+	}
+
+	return i18n.T(ctx, "delete_account.deleted"), nil
+}
+
+// Delete удаляет пользователя со всеми его данными. Если archive равен true, перед удалением пользователю
+// присылается файл со списком его баз Notion; если отправить его не удалось, данные не удаляются
+func (uc *AccountDeletionUseCase) Delete(ctx context.Context, user *entity.User, archive bool) error {
+	// Логирование начала удаления аккаунта
+	uc.logger.Info("Deleting user account",
+		"user_id", user.ID,
+		"archive", archive,
+	)
+
+	jobs, err := uc.loadJobs(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if archive {
+		if err := uc.sendNotionArchive(ctx, user, jobs); err != nil {
+			return err
+		}
+	}
+
+	// Удаление файлов записей. Файлы задач обычно лежат в каталоге пользователя, но задачи подкастов
+	// и перенесенные из другой установки могут ссылаться и на другие места
+	for _, job := range jobs {
+		if job.AudioFilePath == "" {
+			continue
+		}
+		if err := os.Remove(job.AudioFilePath); err != nil && !os.IsNotExist(err) {
+			uc.logger.Warn("Failed to remove job audio file",
+				"error", err,
+				"job_id", job.ID,
+			)
+		}
+	}
+	if err := uc.audioService.DeleteUserFiles(ctx, user.TelegramID); err != nil {
+		uc.logger.Error("Failed to delete user files",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return fmt.Errorf("failed to delete user files: %w", err)
+	}
+
+	// Удаление пользователя вместе со связанными записями и его профиля из кэша
+	if err := uc.userRepo.Delete(ctx, user); err != nil {
+		uc.logger.Error("Failed to delete user",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	// События аналитики не связаны с пользователем в базе, поэтому удаляются отдельно
+	if err := uc.usageAnalyticsUseCase.Forget(ctx, user.TelegramID); err != nil {
+		uc.logger.Warn("Failed to delete usage events of deleted user",
+			"error", err,
+			"user_id", user.ID,
+		)
+	}
+
+	// Логирование успешного удаления аккаунта
+	uc.logger.Info("User account deleted",
+		"user_id", user.ID,
+		"jobs", len(jobs),
+	)
+
+	return nil
+}
+
+// loadJobs возвращает все задачи пользователя
+func (uc *AccountDeletionUseCase) loadJobs(ctx context.Context, userID int64) ([]*entity.Job, error) {
+	var jobs []*entity.Job
+	for offset := 0; ; offset += accountDeletionPageSize {
+		page, err := uc.jobRepo.GetByUserID(ctx, userID, accountDeletionPageSize, offset)
+		if err != nil {
+			uc.logger.Error("Failed to get user jobs",
+				"error", err,
+			)
+			return nil, fmt.Errorf("failed to get user jobs: %w", err)
+		}
+		jobs = append(jobs, page...)
+		if len(page) < accountDeletionPageSize {
+			return jobs, nil
+		}
+	}
+}
+
+// sendNotionArchive присылает пользователю файл со списком его баз Notion: основной базы, страницы-хаба,
+// баз подписок на подкасты и баз, в которые сохранялись задачи
+func (uc *AccountDeletionUseCase) sendNotionArchive(ctx context.Context, user *entity.User, jobs []*entity.Job) error {
+	feeds, err := uc.podcastRepo.GetFeedsByUserID(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get podcast feeds: %w", err)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, "delete_account.archive.header", time.Now().Format("02.01.2006")) + "\n\n")
+	if user.NotionDatabaseID != "" {
+		builder.WriteString(i18n.T(ctx, "delete_account.archive.database", notionURL(user.NotionDatabaseID)) + "\n")
+	}
+	if user.NotionHubPageID != "" {
+		builder.WriteString(i18n.T(ctx, "delete_account.archive.hub", notionURL(user.NotionHubPageID)) + "\n")
+	}
+
+	seen := map[string]bool{user.NotionDatabaseID: true}
+	for _, feed := range feeds {
+		if feed.NotionDatabaseID == "" || seen[feed.NotionDatabaseID] {
+			continue
+		}
+		seen[feed.NotionDatabaseID] = true
+		builder.WriteString(i18n.T(ctx, "delete_account.archive.podcast", feed.Title, notionURL(feed.NotionDatabaseID)) + "\n")
+	}
+	for _, job := range jobs {
+		if job.NotionDatabaseID == "" || seen[job.NotionDatabaseID] {
+			continue
+		}
+		seen[job.NotionDatabaseID] = true
+		builder.WriteString(i18n.T(ctx, "delete_account.archive.job", job.ID, notionURL(job.NotionDatabaseID)) + "\n")
+	}
+
+	caption := i18n.T(ctx, "delete_account.archive.caption")
+	if _, err := uc.messenger.SendDocument(user.TelegramID, 0, accountArchiveFileName, []byte(builder.String()), caption); err != nil {
+		uc.logger.Error("Failed to send Notion archive",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return fmt.Errorf("failed to send Notion archive: %w", err)
+	}
+
+	return nil
+}
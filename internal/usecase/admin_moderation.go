@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Параметры рассылки администратора
+const (
+	broadcastPageSize = 100
+	// broadcastInterval - пауза между сообщениями рассылки: Telegram ограничивает отправку ботом
+	// примерно 30 сообщениями в секунду, часть которых нужна для обычной работы бота
+	broadcastInterval = 50 * time.Millisecond
+	// maxAuditDetailsLength - ограничение длины причины блокировки и текста рассылки в журнале
+	maxAuditDetailsLength = 200
+)
+
+// AdminModerationUseCase представляет собой сценарий управления пользователями администраторами бота:
+// блокировка пользователей, рассылка сообщения всем пользователям и общая статистика бота. Администраторы
+// задаются списком TELEGRAM_ADMIN_IDS; их нельзя заблокировать. Блокировки и рассылки записываются в журнал
+type AdminModerationUseCase struct {
+	userRepo  repository.UserRepository
+	jobRepo   repository.JobRepository
+	auditRepo repository.AdminAuditRepository
+	messenger service.MessengerService
+	admins    *Admins
+	logger    *logger.Logger
+
+	// broadcasting не дает запустить вторую рассылку, пока идет первая
+	broadcasting atomic.Bool
+}
+
+// NewAdminModerationUseCase создает новый сценарий управления пользователями администраторами
+func NewAdminModerationUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	auditRepo repository.AdminAuditRepository,
+	messenger service.MessengerService,
+	admins *Admins,
+	logger *logger.Logger,
+) *AdminModerationUseCase {
+	return &AdminModerationUseCase{
+		userRepo:  userRepo,
+		jobRepo:   jobRepo,
+		auditRepo: auditRepo,
+		messenger: messenger,
+		admins:    admins,
+		logger:    logger,
+	}
+}
+
+// SyncAdmins отмечает администраторами пользователей из конфигурации и снимает отметку с остальных.
+// Вызывается при запуске приложения, поэтому изменение TELEGRAM_ADMIN_IDS вступает в силу после перезапуска
+func (uc *AdminModerationUseCase) SyncAdmins(ctx context.Context) error {
+	if err := uc.userRepo.SetAdmins(ctx, uc.admins.IDs()); err != nil {
+		uc.logger.Error("Failed to sync bot admins",
+			"error", err,
+		)
+		return fmt.Errorf("failed to sync bot admins: %w", err)
+	}
+	return nil
+}
+
+// IsBanned сообщает, что пользователь заблокирован и его сообщения не нужно обрабатывать.
+// Администраторы бота не блокируются
+func (uc *AdminModerationUseCase) IsBanned(ctx context.Context, telegramID int64) (bool, error) {
+	if uc.admins.IsAdmin(telegramID) {
+		return false, nil
+	}
+	return uc.userRepo.IsBanned(ctx, telegramID)
+}
+
+// HandleBan обрабатывает команду /admin_ban: блокирует пользователя с необязательной причиной
+// или снимает блокировку (/admin_ban off <telegram id>)
+func (uc *AdminModerationUseCase) HandleBan(ctx context.Context, adminTelegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /admin_ban
+	uc.logger.Info("Handling /admin_ban command",
+		"telegram_id", adminTelegramID,
+	)
+
+	usage := i18n.T(ctx, "admin_ban.usage")
+
+	fields := strings.Fields(args)
+	unban := len(fields) > 0 && strings.EqualFold(fields[0], "off")
+	if unban {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return usage, nil
+	}
+	targetID, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return usage, nil
+	}
+	reason := truncateRunes(strings.Join(fields[1:], " "), maxAuditDetailsLength, false)
+
+	if unban {
+		updated, err := uc.userRepo.SetBan(ctx, targetID, nil, "")
+		if err != nil {
+			return "", fmt.Errorf("failed to unban user: %w", err)
+		}
+		if !updated {
+			return i18n.T(ctx, "admin_ban.not_found", targetID), nil
+		}
+		if err := uc.audit(ctx, adminTelegramID, entity.AdminAuditActionUnban, targetID, ""); err != nil {
+			return "", err
+		}
+		return i18n.T(ctx, "admin_ban.unbanned", targetID), nil
+	}
+
+	if uc.admins.IsAdmin(targetID) {
+		return i18n.T(ctx, "admin_ban.admin"), nil
+	}
+
+	now := time.Now()
+	updated, err := uc.userRepo.SetBan(ctx, targetID, &now, reason)
+	if err != nil {
+		return "", fmt.Errorf("failed to ban user: %w", err)
+	}
+	if !updated {
+		return i18n.T(ctx, "admin_ban.never_wrote", targetID), nil
+	}
+	if err := uc.audit(ctx, adminTelegramID, entity.AdminAuditActionBan, targetID, reason); err != nil {
+		return "", err
+	}
+
+	return i18n.T(ctx, "admin_ban.banned", targetID, targetID), nil
+}
+
+// HandleBroadcast обрабатывает команду /admin_broadcast: запускает рассылку текста всем незаблокированным
+// пользователям личных чатов. Рассылка идет в фоне с паузами между сообщениями, итоги приходят администратору
+// отдельным сообщением. Рассылка прерывается при остановке приложения и заново не запускается
+func (uc *AdminModerationUseCase) HandleBroadcast(ctx context.Context, adminTelegramID int64, text string) (string, error) {
+	// Логирование начала обработки команды /admin_broadcast
+	uc.logger.Info("Handling /admin_broadcast command",
+		"telegram_id", adminTelegramID,
+		"text_length", len(text),
+	)
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return i18n.T(ctx, "admin_broadcast.usage"), nil
+	}
+	if utf8.RuneCountInString(text) > telegramMessageLimit {
+		return i18n.T(ctx, "admin_broadcast.too_long", telegramMessageLimit), nil
+	}
+
+	if !uc.broadcasting.CompareAndSwap(false, true) {
+		return i18n.T(ctx, "admin_broadcast.running"), nil
+	}
+
+	if err := uc.audit(ctx, adminTelegramID, entity.AdminAuditActionBroadcast, 0, truncateRunes(text, maxAuditDetailsLength, false)); err != nil {
+		uc.broadcasting.Store(false)
+		return "", err
+	}
+
+	// Рассылка переживает обработку команды, но не остановку приложения
+	go uc.broadcast(context.WithoutCancel(ctx), adminTelegramID, text)
+
+	return i18n.T(ctx, "admin_broadcast.started"), nil
+}
+
+// broadcast отправляет текст получателям рассылки постранично и сообщает итоги администратору
+func (uc *AdminModerationUseCase) broadcast(ctx context.Context, adminTelegramID int64, text string) {
+	defer uc.broadcasting.Store(false)
+
+	started := time.Now()
+	sent, failed := 0, 0
+	var afterID int64
+	for {
+		recipients, err := uc.userRepo.GetBroadcastRecipients(ctx, afterID, broadcastPageSize)
+		if err != nil {
+			uc.logger.Error("Failed to get broadcast recipients",
+				"error", err,
+			)
+			break
+		}
+		if len(recipients) == 0 {
+			break
+		}
+
+		for _, recipient := range recipients {
+			afterID = recipient.ID
+			// Пользователи, заблокировавшие бота, не получат сообщение: это не ошибка рассылки, а только ее итог
+			if _, err := uc.messenger.SendMessage(recipient.TelegramID, text); err != nil {
+				failed++
+				uc.logger.Warn("Failed to send broadcast message",
+					"error", err,
+					"telegram_id", recipient.TelegramID,
+				)
+			} else {
+				sent++
+			}
+			time.Sleep(broadcastInterval)
+		}
+	}
+
+	// Логирование итогов рассылки
+	uc.logger.Info("Broadcast finished",
+		"admin_telegram_id", adminTelegramID,
+		"sent", sent,
+		"failed", failed,
+		"duration", time.Since(started),
+	)
+
+	report := i18n.T(ctx, "admin_broadcast.finished", time.Since(started).Round(time.Second), sent, failed)
+	if _, err := uc.messenger.SendMessage(adminTelegramID, report); err != nil {
+		uc.logger.Error("Failed to send broadcast report",
+			"error", err,
+		)
+	}
+}
+
+// HandleStats обрабатывает команду /admin_stats: сводка по пользователям и задачам бота за все время и за сутки
+func (uc *AdminModerationUseCase) HandleStats(ctx context.Context, adminTelegramID int64) (string, error) {
+	// Логирование начала обработки команды /admin_stats
+	uc.logger.Info("Handling /admin_stats command",
+		"telegram_id", adminTelegramID,
+	)
+
+	since := time.Now().Add(-24 * time.Hour)
+	users, err := uc.userRepo.GetBaseStats(ctx, since)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user stats: %w", err)
+	}
+	jobs, err := uc.jobRepo.GetTotals(ctx, since)
+	if err != nil {
+		return "", fmt.Errorf("failed to get job totals: %w", err)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, "admin_stats.header") + "\n\n")
+	builder.WriteString(i18n.T(ctx, "admin_stats.users"))
+	builder.WriteString("\n" + i18n.T(ctx, "admin_stats.users.total", users.Users, users.NewSince))
+	builder.WriteString("\n" + i18n.T(ctx, "admin_stats.users.groups", users.Groups))
+	builder.WriteString("\n" + i18n.T(ctx, "admin_stats.users.banned", users.Banned))
+	builder.WriteString("\n" + i18n.T(ctx, "admin_stats.users.admins", users.Admins, len(uc.admins.IDs())))
+	builder.WriteString("\n\n" + i18n.T(ctx, "admin_stats.jobs"))
+	builder.WriteString("\n" + i18n.T(ctx, "admin_stats.jobs.total", jobs.TotalJobs, jobs.PendingJobs))
+	builder.WriteString("\n" + i18n.T(ctx, "admin_stats.jobs.since",
+		jobs.JobsSince, jobs.CompletedSince, jobs.FailedSince, jobs.ActiveUsersSince))
+	builder.WriteString("\n" + i18n.T(ctx, "admin_stats.jobs.transcribed", jobs.SecondsTranscribed/60))
+	builder.WriteString("\n" + i18n.T(ctx, "admin_stats.jobs.summary_cost", jobs.SummaryCostSince))
+
+	return builder.String(), nil
+}
+
+// audit записывает действие администратора в журнал
+func (uc *AdminModerationUseCase) audit(ctx context.Context, adminTelegramID int64, action entity.AdminAuditAction, targetTelegramID int64, details string) error {
+	entry := &entity.AdminAuditEntry{
+		AdminTelegramID:  adminTelegramID,
+		Action:           action,
+		TargetTelegramID: targetTelegramID,
+		Details:          details,
+	}
+
+	if err := uc.auditRepo.Create(ctx, entry); err != nil {
+		uc.logger.Error("Failed to write admin audit entry",
+			"error", err,
+			"admin_telegram_id", adminTelegramID,
+			"action", action,
+		)
+		return fmt.Errorf("failed to write admin audit entry: %w", err)
+	}
+
+	uc.logger.Warn("Admin moderation action",
+		"admin_telegram_id", adminTelegramID,
+		"action", action,
+		"target_telegram_id", targetTelegramID,
+	)
+
+	return nil
+}
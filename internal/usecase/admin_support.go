@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Параметры просмотра данных пользователей администраторами
+const (
+	adminInspectJobsLimit = 10 // Сколько последних задач пользователя показывается
+	adminAuditLimit       = 20 // Сколько последних записей журнала показывается
+)
+
+// AdminSupportUseCase представляет собой сценарий просмотра задач, ошибок и настроек пользователя
+// администратором для разбора обращений без доступа к базе данных. Каждый просмотр записывается
+// в журнал до выдачи данных: если запись не удалась, данные не показываются
+type AdminSupportUseCase struct {
+	userRepo        repository.UserRepository
+	jobRepo         repository.JobRepository
+	jobStageRepo    repository.JobStageRepository
+	userWebhookRepo repository.UserWebhookRepository
+	auditRepo       repository.AdminAuditRepository
+	logger          *logger.Logger
+}
+
+// NewAdminSupportUseCase создает новый сценарий просмотра данных пользователей администраторами
+func NewAdminSupportUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	jobStageRepo repository.JobStageRepository,
+	userWebhookRepo repository.UserWebhookRepository,
+	auditRepo repository.AdminAuditRepository,
+	logger *logger.Logger,
+) *AdminSupportUseCase {
+	return &AdminSupportUseCase{
+		userRepo:        userRepo,
+		jobRepo:         jobRepo,
+		jobStageRepo:    jobStageRepo,
+		userWebhookRepo: userWebhookRepo,
+		auditRepo:       auditRepo,
+		logger:          logger,
+	}
+}
+
+// InspectUser возвращает последние задачи, последнюю ошибку и настройки пользователя; reason - указанная
+// администратором причина просмотра (например, номер обращения). Для неизвестного пользователя возвращает nil,
+// попытка просмотра все равно записывается в журнал
+func (uc *AdminSupportUseCase) InspectUser(ctx context.Context, adminTelegramID, targetTelegramID int64, reason string) (*entity.UserInspection, error) {
+	if err := uc.audit(ctx, adminTelegramID, entity.AdminAuditActionUserView, targetTelegramID, reason); err != nil {
+		return nil, err
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, targetTelegramID)
+	if err != nil {
+		uc.logger.Warn("Failed to get inspected user",
+			"error", err,
+			"target_telegram_id", targetTelegramID,
+		)
+		return nil, nil
+	}
+
+	// Получение последних задач
+	jobs, err := uc.jobRepo.GetByUserID(ctx, user.ID, adminInspectJobsLimit, 0)
+	if err != nil {
+		uc.logger.Error("Failed to get user jobs",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get user jobs: %w", err)
+	}
+
+	// Получение вебхука
+	webhook, err := uc.userWebhookRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get user webhook",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get user webhook: %w", err)
+	}
+
+	inspection := &entity.UserInspection{
+		User:       user,
+		Webhook:    webhook,
+		RecentJobs: jobs,
+	}
+
+	// Этапы последней задачи с ошибкой показывают, на каком шаге и у какого сервиса она упала
+	for _, job := range jobs {
+		if job.Status != entity.JobStatusFailed {
+			continue
+		}
+
+		stages, err := uc.jobStageRepo.GetByJobID(ctx, job.ID)
+		if err != nil {
+			uc.logger.Error("Failed to get job stages",
+				"error", err,
+			)
+			return nil, fmt.Errorf("failed to get job stages: %w", err)
+		}
+
+		inspection.LastFailure = job
+		inspection.FailureStages = stages
+		break
+	}
+
+	return inspection, nil
+}
+
+// RecentAudit возвращает последние записи журнала; сам просмотр журнала тоже записывается в журнал
+func (uc *AdminSupportUseCase) RecentAudit(ctx context.Context, adminTelegramID int64) ([]*entity.AdminAuditEntry, error) {
+	if err := uc.audit(ctx, adminTelegramID, entity.AdminAuditActionAuditView, 0, ""); err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.auditRepo.GetRecent(ctx, adminAuditLimit)
+	if err != nil {
+		uc.logger.Error("Failed to get admin audit entries",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get admin audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// audit записывает действие администратора в журнал и в лог
+func (uc *AdminSupportUseCase) audit(ctx context.Context, adminTelegramID int64, action entity.AdminAuditAction, targetTelegramID int64, details string) error {
+	entry := &entity.AdminAuditEntry{
+		AdminTelegramID:  adminTelegramID,
+		Action:           action,
+		TargetTelegramID: targetTelegramID,
+		Details:          details,
+	}
+
+	if err := uc.auditRepo.Create(ctx, entry); err != nil {
+		uc.logger.Error("Failed to write admin audit entry",
+			"error", err,
+			"admin_telegram_id", adminTelegramID,
+			"action", action,
+		)
+		return fmt.Errorf("failed to write admin audit entry: %w", err)
+	}
+
+	uc.logger.Warn("Admin accessed user data",
+		"admin_telegram_id", adminTelegramID,
+		"action", action,
+		"target_telegram_id", targetTelegramID,
+	)
+
+	return nil
+}
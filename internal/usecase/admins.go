@@ -0,0 +1,24 @@
+package usecase
+
+import "slices"
+
+// Admins представляет собой список администраторов бота из TELEGRAM_ADMIN_IDS. Один список передается
+// всем сценариям, которым нужно отличать администраторов от остальных пользователей
+type Admins struct {
+	ids []int64
+}
+
+// NewAdmins создает список администраторов бота
+func NewAdmins(ids []int64) *Admins {
+	return &Admins{ids: ids}
+}
+
+// IsAdmin проверяет, входит ли пользователь в список администраторов бота
+func (a *Admins) IsAdmin(telegramID int64) bool {
+	return slices.Contains(a.ids, telegramID)
+}
+
+// IDs возвращает Telegram ID администраторов бота
+func (a *Admins) IDs() []int64 {
+	return a.ids
+}
@@ -15,18 +15,76 @@ type App struct {
 	Logger                         *logger.Logger
 	UserRepo                       repository.UserRepository
 	JobRepo                        repository.JobRepository
+	JobStageRepo                   repository.JobStageRepository
 	QueueRepo                      repository.QueueRepository
+	ShareLinkRepo                  repository.ShareLinkRepository
+	EmbeddingRepo                  repository.EmbeddingRepository
+	MessageDeletionRepo            repository.MessageDeletionRepository
+	TranscriptSegmentRepo          repository.TranscriptSegmentRepository
+	PodcastRepo                    repository.PodcastRepository
+	SyncResultRepo                 repository.SyncResultRepository
+	TeamRepo                       repository.TeamRepository
+	ReadingLessonRepo              repository.ReadingLessonRepository
+	JobAttachmentRepo              repository.JobAttachmentRepository
+	UserWebhookRepo                repository.UserWebhookRepository
+	FailureRollupRepo              repository.FailureRollupRepository
+	AdminAuditRepo                 repository.AdminAuditRepository
+	VideoChapterRepo               repository.VideoChapterRepository
+	MeetingAgendaRepo              repository.MeetingAgendaRepository
+	UserSettingsRepo               repository.UserSettingsRepository
+	UsageEventRepo                 repository.UsageEventRepository
+	JobSentimentRepo               repository.JobSentimentRepository
 	AudioService                   service.AudioService
 	TranscriptionService           service.TranscriptionService
 	SummarizationService           service.SummarizationService
 	NotionService                  service.NotionService
+	EmbeddingService               service.EmbeddingService
 	QueueService                   service.QueueService
+	PodcastService                 service.PodcastService
+	Messenger                      service.MessengerService
 	AudioProcessingUseCase         *AudioProcessingUseCase
 	TranscriptionProcessingUseCase *TranscriptionProcessingUseCase
 	SummarizationProcessingUseCase *SummarizationProcessingUseCase
 	NotionProcessingUseCase        *NotionProcessingUseCase
+	SyncUseCase                    *SyncUseCase
 	TelegramHandlersUseCase        *TelegramHandlersUseCase
 	QueueHandlersUseCase           *QueueHandlersUseCase
+	ShareLinkUseCase               *ShareLinkUseCase
+	TextProcessingUseCase          *TextProcessingUseCase
+	KnowledgeBaseUseCase           *KnowledgeBaseUseCase
+	MessageRetentionUseCase        *MessageRetentionUseCase
+	PodcastUseCase                 *PodcastUseCase
+	NotionReconcileUseCase         *NotionReconcileUseCase
+	TranscriptSearchUseCase        *TranscriptSearchUseCase
+	TeamDigestUseCase              *TeamDigestUseCase
+	ReadingLessonUseCase           *ReadingLessonUseCase
+	PhotoAttachmentUseCase         *PhotoAttachmentUseCase
+	UserWebhookUseCase             *UserWebhookUseCase
+	FailureAnalyticsUseCase        *FailureAnalyticsUseCase
+	JobKeepaliveUseCase            *JobKeepaliveUseCase
+	AdminSupportUseCase            *AdminSupportUseCase
+	UserConfigUseCase              *UserConfigUseCase
+	VideoUseCase                   *VideoUseCase
+	MeetingAgendaUseCase           *MeetingAgendaUseCase
+	UserSettingsUseCase            *UserSettingsUseCase
+	FailedJobsCleanupUseCase       *FailedJobsCleanupUseCase
+	JobFollowUpUseCase             *JobFollowUpUseCase
+	QuickSummaryUseCase            *QuickSummaryUseCase
+	UsageAnalyticsUseCase          *UsageAnalyticsUseCase
+	InterfaceLanguageUseCase       *InterfaceLanguageUseCase
+	GroupChatUseCase               *GroupChatUseCase
+	AccountDeletionUseCase         *AccountDeletionUseCase
+	DiagnosticsUseCase             *DiagnosticsUseCase
+	NotionOAuthUseCase             *NotionOAuthUseCase
+	JobExportUseCase               *JobExportUseCase
+	ModelComparisonUseCase         *ModelComparisonUseCase
+	AdminModerationUseCase         *AdminModerationUseCase
+	CallRecordingUseCase           *CallRecordingUseCase
+	NotionRoutingUseCase           *NotionRoutingUseCase
+	JobLogUseCase                  *JobLogUseCase
+	AudioRateLimitUseCase          *AudioRateLimitUseCase
+	LiveSessionUseCase             *LiveSessionUseCase
+	MaintenanceUseCase             *MaintenanceUseCase
 }
 
 // NewApp создает новое приложение
@@ -35,45 +93,432 @@ func NewApp(
 	logger *logger.Logger,
 	userRepo repository.UserRepository,
 	jobRepo repository.JobRepository,
+	jobStageRepo repository.JobStageRepository,
 	queueRepo repository.QueueRepository,
+	shareLinkRepo repository.ShareLinkRepository,
+	embeddingRepo repository.EmbeddingRepository,
+	messageDeletionRepo repository.MessageDeletionRepository,
+	podcastRepo repository.PodcastRepository,
+	transcriptSegmentRepo repository.TranscriptSegmentRepository,
+	syncResultRepo repository.SyncResultRepository,
+	teamRepo repository.TeamRepository,
+	readingLessonRepo repository.ReadingLessonRepository,
+	jobAttachmentRepo repository.JobAttachmentRepository,
+	userWebhookRepo repository.UserWebhookRepository,
+	failureRollupRepo repository.FailureRollupRepository,
+	adminAuditRepo repository.AdminAuditRepository,
+	videoChapterRepo repository.VideoChapterRepository,
+	meetingAgendaRepo repository.MeetingAgendaRepository,
+	userSettingsRepo repository.UserSettingsRepository,
+	usageEventRepo repository.UsageEventRepository,
+	jobSentimentRepo repository.JobSentimentRepository,
+	notionOAuthStateRepo repository.NotionOAuthStateRepository,
+	modelComparisonRepo repository.ModelComparisonRepository,
+	callRecordingRepo repository.CallRecordingRepository,
+	notionRouteRepo repository.NotionRouteRepository,
+	jobLogRepo repository.JobLogRepository,
+	rateLimitRepo repository.RateLimitRepository,
+	liveSessionRepo repository.LiveSessionRepository,
+	maintenanceRepo repository.MaintenanceRepository,
 	audioService service.AudioService,
 	transcriptionService service.TranscriptionService,
+	diarizationService service.DiarizationService,
 	summarizationService service.SummarizationService,
 	notionService service.NotionService,
+	notionOAuthService service.NotionOAuthService,
+	embeddingService service.EmbeddingService,
+	answerService service.AnswerService,
 	queueService service.QueueService,
+	podcastService service.PodcastService,
+	ocrService service.OCRService,
+	speechService service.SpeechService,
+	webhookService service.WebhookService,
+	videoService service.VideoService,
+	urlIngestionService service.URLIngestionService,
+	callRecordingService service.CallRecordingService,
+	documentService service.DocumentService,
+	lockService service.LockService,
+	messenger service.MessengerService,
+	syncTargets []service.SyncTarget,
+	healthChecks []service.HealthCheck,
 ) *App {
+	// Создание сценария настроек обработки записей: их читают этапы обработки задач
+	userSettingsUseCase := NewUserSettingsUseCase(
+		userSettingsRepo,
+		logger,
+	)
+
+	// Создание сценария обезличенной аналитики использования
+	usageAnalyticsUseCase := NewUsageAnalyticsUseCase(
+		userRepo,
+		usageEventRepo,
+		userSettingsUseCase,
+		config.Analytics.UsageEvents,
+		config.Analytics.UsageSalt,
+		logger,
+	)
+
+	// Создание сценария выбора языка ответов бота
+	interfaceLanguageUseCase := NewInterfaceLanguageUseCase(
+		userRepo,
+		userSettingsUseCase,
+		logger,
+	)
+
+	// Создание сценария работы бота в группах
+	groupChatUseCase := NewGroupChatUseCase(
+		userRepo,
+		logger,
+	)
+
+	// Создание сценария удаления аккаунта
+	accountDeletionUseCase := NewAccountDeletionUseCase(
+		userRepo,
+		jobRepo,
+		podcastRepo,
+		audioService,
+		messenger,
+		usageAnalyticsUseCase,
+		logger,
+	)
+
 	// Создание сценария обработки аудио
 	audioProcessingUseCase := NewAudioProcessingUseCase(
 		userRepo,
 		jobRepo,
+		videoChapterRepo,
+		meetingAgendaRepo,
 		queueService,
 		audioService,
+		config.Queue.InlineMaxDuration,
 		logger,
 	)
 
-	// Создание сценария обработки транскрибации
-	transcriptionProcessingUseCase := NewTranscriptionProcessingUseCase(
+	// Создание сценария семантического поиска по заметкам: по нему подбираются связанные заметки на страницах Notion
+	knowledgeBaseUseCase := NewKnowledgeBaseUseCase(
+		userRepo,
+		jobRepo,
+		embeddingRepo,
+		embeddingService,
+		answerService,
+		logger,
+	)
+
+	// Создание сценария выгрузки в Notion по темам записей: без правил /route страницы задач
+	// сохраняются в основную базу
+	notionRoutingUseCase := NewNotionRoutingUseCase(
+		userRepo,
+		jobRepo,
+		notionRouteRepo,
+		summarizationService,
+		notionService,
+		logger,
+	)
+
+	// Создание сценария обработки интеграции с Notion
+	notionProcessingUseCase := NewNotionProcessingUseCase(
+		jobRepo,
+		userRepo,
+		jobSentimentRepo,
+		callRecordingRepo,
+		userSettingsUseCase,
+		notionRoutingUseCase,
+		notionService,
+		lockService,
+		knowledgeBaseUseCase,
+		interfaceLanguageUseCase,
+		config.Notion.UserLockWait,
+		config.Notion.RelatedNotes,
+		logger,
+	)
+
+	// Создание сценария выгрузки результатов: Notion пользователя и дополнительные места из конфигурации
+	syncUseCase := NewSyncUseCase(
+		jobRepo,
+		userRepo,
+		syncResultRepo,
+		queueService,
+		userSettingsUseCase,
+		interfaceLanguageUseCase,
+		logger,
+	)
+	syncUseCase.RegisterTarget(notionProcessingUseCase.SyncTarget())
+	for _, target := range syncTargets {
+		syncUseCase.RegisterTarget(target)
+	}
+
+	// Создание сценария работы с публичными ссылками
+	shareLinkUseCase := NewShareLinkUseCase(
+		userRepo,
+		jobRepo,
+		shareLinkRepo,
+		config.HTTP.PublicURL,
+		config.Share.LinkTTL,
+		logger,
+	)
+
+	// Создание сценария обработки присланного текста
+	textProcessingUseCase := NewTextProcessingUseCase(
+		userRepo,
 		jobRepo,
 		queueService,
+		logger,
+	)
+
+	// Создание сценария отправки результатов с автоудалением
+	messageRetentionUseCase := NewMessageRetentionUseCase(
+		userRepo,
+		messageDeletionRepo,
+		messenger,
+		logger,
+	)
+
+	// Создание сценария подписки на подкасты
+	podcastUseCase := NewPodcastUseCase(
+		userRepo,
+		podcastRepo,
+		podcastService,
+		notionService,
+		audioProcessingUseCase,
+		config.Podcast.PollInterval,
+		logger,
+	)
+
+	// Создание сценария сверки задач с Notion
+	notionReconcileUseCase := NewNotionReconcileUseCase(
+		jobRepo,
+		userRepo,
+		notionService,
+		notionProcessingUseCase,
+		config.Notion.ReconcileInterval,
+		logger,
+	)
+
+	// Создание сценария поиска внутри транскрипции
+	transcriptSearchUseCase := NewTranscriptSearchUseCase(
+		transcriptSegmentRepo,
+		summarizationService,
+		logger,
+	)
+
+	// Создание сценария командных дайджестов
+	teamDigestUseCase := NewTeamDigestUseCase(
+		teamRepo,
+		shareLinkUseCase,
+		interfaceLanguageUseCase,
+		messenger,
+		config.Team.DigestTimezone,
+		logger,
+	)
+
+	// Создание сценария уроков чтения вслух
+	readingLessonUseCase := NewReadingLessonUseCase(
+		userRepo,
+		jobRepo,
+		readingLessonRepo,
+		queueService,
 		audioService,
 		transcriptionService,
+		interfaceLanguageUseCase,
+		messenger,
 		logger,
 	)
 
-	// Создание сценария обработки суммаризации
-	summarizationProcessingUseCase := NewSummarizationProcessingUseCase(
+	// Создание сценария live-сессий: голосовые сообщения во время сессии транскрибируются сразу,
+	// без очереди, а по ее завершении транскрипция становится одной задачей
+	liveSessionUseCase := NewLiveSessionUseCase(
+		userRepo,
 		jobRepo,
+		liveSessionRepo,
 		queueService,
+		audioService,
+		transcriptionService,
 		summarizationService,
+		lockService,
+		messenger,
+		logger,
+	)
+
+	// Создание сценария приложения фотографий к задачам
+	photoAttachmentUseCase := NewPhotoAttachmentUseCase(
+		jobAttachmentRepo,
+		ocrService,
 		notionService,
+		config.HTTP.PublicURL,
 		logger,
 	)
 
-	// Создание сценария обработки интеграции с Notion
-	notionProcessingUseCase := NewNotionProcessingUseCase(
+	// Создание сценария вебхуков пользователей
+	userWebhookUseCase := NewUserWebhookUseCase(
+		userRepo,
 		jobRepo,
+		userWebhookRepo,
+		webhookService,
+		logger,
+	)
+
+	// Создание сценария сводок ошибок обработки задач
+	failureAnalyticsUseCase := NewFailureAnalyticsUseCase(
+		jobStageRepo,
+		failureRollupRepo,
+		config.Analytics.FailureRollupInterval,
+		logger,
+	)
+
+	// Создание сценария просмотра данных пользователей администраторами
+	adminSupportUseCase := NewAdminSupportUseCase(
 		userRepo,
-		notionService,
+		jobRepo,
+		jobStageRepo,
+		userWebhookRepo,
+		adminAuditRepo,
+		logger,
+	)
+
+	// Список администраторов бота, общий для сценариев, которые их отличают
+	admins := NewAdmins(config.Telegram.AdminIDs)
+
+	// Создание сценария управления пользователями администраторами: блокировки, рассылки и статистика бота
+	adminModerationUseCase := NewAdminModerationUseCase(
+		userRepo,
+		jobRepo,
+		adminAuditRepo,
+		messenger,
+		admins,
+		logger,
+	)
+
+	// Создание сценария подключения Notion через OAuth: без данных публичной интеграции Notion
+	// подключается токеном, отправленным командой /notion
+	notionOAuthUseCase := NewNotionOAuthUseCase(
+		userRepo,
+		notionOAuthStateRepo,
+		notionOAuthService,
+		notionProcessingUseCase,
+		interfaceLanguageUseCase,
+		messenger,
+		logger,
+	)
+
+	// Создание сценария выгрузки результатов задач в PDF: без пути к wkhtmltopdf команда /pdf отключена
+	jobExportUseCase := NewJobExportUseCase(
+		userRepo,
+		jobRepo,
+		documentService,
+		messageRetentionUseCase,
+		logger,
+	)
+
+	// Создание сценария сравнения моделей суммаризации: без двух моделей в конфигурации команда /compare отключена
+	modelComparisonUseCase := NewModelComparisonUseCase(
+		userRepo,
+		jobRepo,
+		modelComparisonRepo,
+		summarizationService,
+		messageRetentionUseCase,
+		config.DeepSeek.CompareModels,
+		logger,
+	)
+
+	// Создание сценария обработки записей звонков: без номеров телефонов в VOIP_PHONE_NUMBERS
+	// вебхуки VoIP провайдеров не принимаются
+	callRecordingUseCase := NewCallRecordingUseCase(
+		userRepo,
+		callRecordingRepo,
+		callRecordingService,
+		urlIngestionService,
+		audioProcessingUseCase,
+		usageAnalyticsUseCase,
+		interfaceLanguageUseCase,
+		messenger,
+		config.VoIP.PhoneNumbers,
+		logger,
+	)
+
+	// Создание сценария самодиагностики для администраторов
+	diagnosticsUseCase := NewDiagnosticsUseCase(
+		healthChecks,
+		logger,
+	)
+
+	// Создание сценария выгрузки и загрузки настроек пользователя
+	userConfigUseCase := NewUserConfigUseCase(
+		userRepo,
+		userWebhookRepo,
+		podcastRepo,
+		podcastUseCase,
+		userSettingsUseCase,
+		logger,
+	)
+
+	// Создание сценария обработки видео по ссылке: без пути к yt-dlp ссылки не обрабатываются
+	videoUseCase := NewVideoUseCase(
+		videoService,
+		audioProcessingUseCase,
+		config.Video.YTDLPPath != "",
+		config.Video.MaxDuration,
+		logger,
+	)
+
+	// Создание сценария повесток встреч
+	meetingAgendaUseCase := NewMeetingAgendaUseCase(
+		userRepo,
+		meetingAgendaRepo,
+		logger,
+	)
+
+	// Создание сценария сообщений о ходе длинных задач
+	jobKeepaliveUseCase := NewJobKeepaliveUseCase(
+		userRepo,
+		jobRepo,
+		interfaceLanguageUseCase,
+		messenger,
+		config.Progress.KeepaliveAfter,
+		config.Progress.KeepaliveInterval,
+		logger,
+	)
+
+	// Создание сценария ежедневного разбора задач с ошибками
+	failedJobsCleanupUseCase := NewFailedJobsCleanupUseCase(
+		userRepo,
+		jobRepo,
+		userSettingsRepo,
+		audioProcessingUseCase,
+		interfaceLanguageUseCase,
+		messenger,
+		config.Queue.FailedCleanupInterval,
+		logger,
+	)
+
+	// Создание сценария действий с результатом задачи по кнопкам под уведомлением
+	jobFollowUpUseCase := NewJobFollowUpUseCase(
+		userRepo,
+		jobRepo,
+		queueService,
+		summarizationService,
+		speechService,
+		syncUseCase,
+		messageRetentionUseCase,
+		interfaceLanguageUseCase,
+		logger,
+	)
+
+	// Создание сценария быстрой суммаризации текста командой /summarize
+	quickSummaryUseCase := NewQuickSummaryUseCase(
+		userRepo,
+		jobRepo,
+		summarizationService,
+		userSettingsUseCase,
+		jobFollowUpUseCase,
+		messageRetentionUseCase,
+		logger,
+	)
+
+	// Создание сценария режима технического обслуживания: пока он включен, новые записи не принимаются,
+	// а очередь не обрабатывается
+	maintenanceUseCase := NewMaintenanceUseCase(
+		maintenanceRepo,
+		adminAuditRepo,
 		logger,
 	)
 
@@ -81,18 +526,120 @@ func NewApp(
 	telegramHandlersUseCase := NewTelegramHandlersUseCase(
 		userRepo,
 		jobRepo,
+		jobStageRepo,
+		syncResultRepo,
+		jobSentimentRepo,
 		audioProcessingUseCase,
 		notionProcessingUseCase,
+		shareLinkUseCase,
+		textProcessingUseCase,
+		knowledgeBaseUseCase,
+		messageRetentionUseCase,
+		podcastUseCase,
+		notionReconcileUseCase,
+		transcriptSearchUseCase,
+		teamDigestUseCase,
+		readingLessonUseCase,
+		liveSessionUseCase,
+		photoAttachmentUseCase,
+		userWebhookUseCase,
+		failureAnalyticsUseCase,
+		adminSupportUseCase,
+		diagnosticsUseCase,
+		userConfigUseCase,
+		videoUseCase,
+		meetingAgendaUseCase,
+		userSettingsUseCase,
+		jobFollowUpUseCase,
+		usageAnalyticsUseCase,
+		interfaceLanguageUseCase,
+		modelComparisonUseCase,
+		maintenanceUseCase,
+		urlIngestionService,
+		messenger,
+		config.Text.MinLength,
+		admins,
+		config.Quota.DailyJobs,
+		logger,
+	)
+
+	// Создание сценария обработки транскрибации
+	transcriptionProcessingUseCase := NewTranscriptionProcessingUseCase(
+		jobRepo,
+		userRepo,
+		queueService,
+		audioService,
+		transcriptionService,
+		diarizationService,
+		userSettingsUseCase,
+		telegramHandlersUseCase,
+		transcriptSearchUseCase,
+		interfaceLanguageUseCase,
+		config.Stage.TranscriptionTimeoutBase,
+		config.Stage.TranscriptionTimeoutFactor,
+		config.FFmpeg.ArtifactRetention,
+		config.Duplicate.Threshold,
+		config.Duplicate.Window,
+		diarizationEnabled(config),
+		whisperChunkBytes(config),
+		config.OpenAI.WhisperChunkOverlap,
+		config.OpenAI.WhisperChunkConcurrency,
+		logger,
+	)
+
+	// Создание сценария обработки суммаризации
+	summarizationProcessingUseCase := NewSummarizationProcessingUseCase(
+		userRepo,
+		jobRepo,
+		videoChapterRepo,
+		meetingAgendaRepo,
+		jobSentimentRepo,
+		queueService,
+		summarizationService,
+		userSettingsUseCase,
+		telegramHandlersUseCase,
+		transcriptSearchUseCase,
+		config.Stage.SummarizationTimeoutBase,
+		config.Stage.SummarizationTimeoutPer1KChars,
 		logger,
 	)
 
 	// Создание сценария регистрации обработчиков задач в очереди
 	queueHandlersUseCase := NewQueueHandlersUseCase(
 		queueService,
+		jobRepo,
+		jobStageRepo,
 		transcriptionProcessingUseCase,
 		summarizationProcessingUseCase,
-		notionProcessingUseCase,
+		syncUseCase,
 		telegramHandlersUseCase,
+		knowledgeBaseUseCase,
+		readingLessonUseCase,
+		userWebhookUseCase,
+		jobFollowUpUseCase,
+		logger,
+	)
+
+	// Создание сценария индекса логов задач. Записи с атрибутом job_id попадают в индекс через хук логгера,
+	// поэтому хук ставится последним, когда все сценарии уже созданы
+	jobLogUseCase := NewJobLogUseCase(
+		userRepo,
+		jobRepo,
+		jobStageRepo,
+		jobLogRepo,
+		messenger,
+		config.Log.JobRetention,
+		logger,
+	)
+	if jobLogUseCase.Enabled() {
+		logger.SetHook(jobLogUseCase.Collect)
+	}
+
+	// Создание сценария ограничения частоты аудио сообщений по ролям пользователей
+	audioRateLimitUseCase := NewAudioRateLimitUseCase(
+		rateLimitRepo,
+		config.Quota,
+		admins,
 		logger,
 	)
 
@@ -101,34 +648,149 @@ func NewApp(
 		Logger:                         logger,
 		UserRepo:                       userRepo,
 		JobRepo:                        jobRepo,
+		JobStageRepo:                   jobStageRepo,
 		QueueRepo:                      queueRepo,
+		ShareLinkRepo:                  shareLinkRepo,
+		EmbeddingRepo:                  embeddingRepo,
+		MessageDeletionRepo:            messageDeletionRepo,
+		TranscriptSegmentRepo:          transcriptSegmentRepo,
+		PodcastRepo:                    podcastRepo,
+		SyncResultRepo:                 syncResultRepo,
+		TeamRepo:                       teamRepo,
+		ReadingLessonRepo:              readingLessonRepo,
+		JobAttachmentRepo:              jobAttachmentRepo,
+		UserWebhookRepo:                userWebhookRepo,
+		FailureRollupRepo:              failureRollupRepo,
+		AdminAuditRepo:                 adminAuditRepo,
+		VideoChapterRepo:               videoChapterRepo,
+		MeetingAgendaRepo:              meetingAgendaRepo,
+		UserSettingsRepo:               userSettingsRepo,
+		UsageEventRepo:                 usageEventRepo,
+		JobSentimentRepo:               jobSentimentRepo,
 		AudioService:                   audioService,
 		TranscriptionService:           transcriptionService,
 		SummarizationService:           summarizationService,
 		NotionService:                  notionService,
+		EmbeddingService:               embeddingService,
 		QueueService:                   queueService,
+		PodcastService:                 podcastService,
+		Messenger:                      messenger,
 		AudioProcessingUseCase:         audioProcessingUseCase,
 		TranscriptionProcessingUseCase: transcriptionProcessingUseCase,
 		SummarizationProcessingUseCase: summarizationProcessingUseCase,
 		NotionProcessingUseCase:        notionProcessingUseCase,
+		SyncUseCase:                    syncUseCase,
 		TelegramHandlersUseCase:        telegramHandlersUseCase,
 		QueueHandlersUseCase:           queueHandlersUseCase,
+		ShareLinkUseCase:               shareLinkUseCase,
+		TextProcessingUseCase:          textProcessingUseCase,
+		KnowledgeBaseUseCase:           knowledgeBaseUseCase,
+		MessageRetentionUseCase:        messageRetentionUseCase,
+		PodcastUseCase:                 podcastUseCase,
+		NotionReconcileUseCase:         notionReconcileUseCase,
+		TranscriptSearchUseCase:        transcriptSearchUseCase,
+		TeamDigestUseCase:              teamDigestUseCase,
+		ReadingLessonUseCase:           readingLessonUseCase,
+		PhotoAttachmentUseCase:         photoAttachmentUseCase,
+		UserWebhookUseCase:             userWebhookUseCase,
+		FailureAnalyticsUseCase:        failureAnalyticsUseCase,
+		JobKeepaliveUseCase:            jobKeepaliveUseCase,
+		AdminSupportUseCase:            adminSupportUseCase,
+		UserConfigUseCase:              userConfigUseCase,
+		VideoUseCase:                   videoUseCase,
+		MeetingAgendaUseCase:           meetingAgendaUseCase,
+		UserSettingsUseCase:            userSettingsUseCase,
+		FailedJobsCleanupUseCase:       failedJobsCleanupUseCase,
+		JobFollowUpUseCase:             jobFollowUpUseCase,
+		QuickSummaryUseCase:            quickSummaryUseCase,
+		UsageAnalyticsUseCase:          usageAnalyticsUseCase,
+		InterfaceLanguageUseCase:       interfaceLanguageUseCase,
+		GroupChatUseCase:               groupChatUseCase,
+		AccountDeletionUseCase:         accountDeletionUseCase,
+		DiagnosticsUseCase:             diagnosticsUseCase,
+		NotionOAuthUseCase:             notionOAuthUseCase,
+		JobExportUseCase:               jobExportUseCase,
+		ModelComparisonUseCase:         modelComparisonUseCase,
+		AdminModerationUseCase:         adminModerationUseCase,
+		CallRecordingUseCase:           callRecordingUseCase,
+		NotionRoutingUseCase:           notionRoutingUseCase,
+		JobLogUseCase:                  jobLogUseCase,
+		AudioRateLimitUseCase:          audioRateLimitUseCase,
+		LiveSessionUseCase:             liveSessionUseCase,
+		MaintenanceUseCase:             maintenanceUseCase,
 	}
 }
 
-// Start запускает приложение
+// Start готовит приложение к запуску. Фоновые подсистемы запускает вызывающий, см. Subsystems
 func (a *App) Start(ctx context.Context) error {
-	// Регистрируем обработчики задач в очереди
-	if err := a.QueueHandlersUseCase.RegisterHandlers(ctx); err != nil {
+	// Экземпляр, запущенный во время обслуживания, не должен начинать обработку очереди
+	a.MaintenanceUseCase.Refresh(ctx)
+
+	// Отмечаем администраторов бота из конфигурации
+	if err := a.AdminModerationUseCase.SyncAdmins(ctx); err != nil {
 		return err
 	}
 
-	// Запускаем воркер очереди
-	if err := a.QueueHandlersUseCase.StartWorker(ctx); err != nil {
-		return err
+	// Регистрируем обработчики задач в очереди
+	return a.QueueHandlersUseCase.RegisterHandlers(ctx)
+}
+
+// Subsystem - фоновая подсистема слоя usecase. Run работает до отмены контекста
+type Subsystem struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Subsystems возвращает фоновые подсистемы: воркер очереди и расписания. Каждая подсистема выполняется
+// в своей горутине, чтобы упавшую подсистему можно было перезапустить, не затрагивая остальные
+func (a *App) Subsystems() []Subsystem {
+	return []Subsystem{
+		// Воркер очереди
+		{Name: "queue_worker", Run: a.QueueHandlersUseCase.RunWorker},
+		// Удаление сообщений с истекшим сроком хранения
+		{Name: "message_retention", Run: untilDone(a.MessageRetentionUseCase.RunCleanup)},
+		// Проверка лент подкастов на новые выпуски
+		{Name: "podcast_polling", Run: untilDone(a.PodcastUseCase.RunPolling)},
+		// Сверка задач с Notion по расписанию
+		{Name: "notion_reconcile", Run: untilDone(a.NotionReconcileUseCase.RunSchedule)},
+		// Публикация дайджестов команд
+		{Name: "team_digest", Run: untilDone(a.TeamDigestUseCase.RunSchedule)},
+		// Пересчет сводок ошибок по расписанию
+		{Name: "failure_analytics", Run: untilDone(a.FailureAnalyticsUseCase.RunSchedule)},
+		// Сообщения о ходе длинных задач
+		{Name: "job_keepalive", Run: untilDone(a.JobKeepaliveUseCase.RunSchedule)},
+		// Ежедневный разбор задач с ошибками
+		{Name: "failed_jobs_cleanup", Run: untilDone(a.FailedJobsCleanupUseCase.RunSchedule)},
+		// Сохранение логов задач для /logs
+		{Name: "job_logs", Run: untilDone(a.JobLogUseCase.RunWriter)},
+		// Удаление подготовленных WAV с истекшим сроком хранения
+		{Name: "audio_artifacts", Run: untilDone(a.TranscriptionProcessingUseCase.RunArtifactCleanup)},
+		// Режим обслуживания, включенный на других экземплярах
+		{Name: "maintenance", Run: untilDone(a.MaintenanceUseCase.RunRefresh)},
 	}
+}
 
-	return nil
+// untilDone приводит расписание, которое работает до отмены контекста, к виду Subsystem.Run
+func untilDone(run func(ctx context.Context)) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		run(ctx)
+		return nil
+	}
+}
+
+// whisperChunkBytes возвращает наибольший файл, который распознается целиком. Ограничение есть только
+// у OpenAI Whisper: локальный сервер и Deepgram принимают запись любой длины
+func whisperChunkBytes(cfg *config.Config) int64 {
+	if cfg.WhisperLocal.Enabled() || cfg.Deepgram.Enabled() {
+		return 0
+	}
+	return int64(cfg.OpenAI.WhisperMaxFileMB) << 20
+}
+
+// diarizationEnabled сообщает, разделяются ли транскрипции по говорящим: везде с DIARIZATION_ENABLED
+// или с Deepgram и DEEPGRAM_DIARIZE
+func diarizationEnabled(cfg *config.Config) bool {
+	return cfg.Diarization.Enabled || (cfg.Deepgram.Enabled() && cfg.Deepgram.Diarize)
 }
 
 // Stop останавливает приложение
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+)
+
+// Параметры удаления подготовленных к распознаванию WAV
+const (
+	// audioArtifactCleanupPeriod - как часто удаляются WAV с истекшим сроком хранения
+	audioArtifactCleanupPeriod = time.Hour
+	// audioArtifactCleanupBatchSize - сколько WAV удаляется за один проход
+	audioArtifactCleanupBatchSize = 100
+)
+
+// preprocessAudio возвращает подготовленный к распознаванию WAV задачи и примененный профиль. Если WAV уже
+// получен при прошлой обработке с тем же профилем и еще не удален, он используется повторно без конвертации
+// и фильтров. Функцию release нужно вызвать после распознавания: она удаляет WAV, который не нужно хранить
+func (uc *TranscriptionProcessingUseCase) preprocessAudio(ctx context.Context, jobID int64, job *entity.Job, audioPath string) (string, entity.AudioProfile, func(), error) {
+	keep := func() {}
+
+	// WAV подходит, если профиль выбирается автоматически или совпадает с профилем, с которым WAV получен
+	profile := jobAudioProfile(job)
+	if processedAudioAvailable(job) && (profile == "" || profile == entity.AudioProfileAuto || profile == job.ProcessedAudioProfile) {
+		// Логирование повторного использования WAV
+		uc.logger.Info("Reusing processed audio",
+			"job_id", jobID,
+			"audio_profile", job.ProcessedAudioProfile,
+		)
+
+		// Использование продлевает срок хранения WAV, чтобы его не удалили во время распознавания
+		uc.saveArtifact(ctx, jobID, job.ProcessedAudioPath, job.ProcessedAudioProfile)
+		return job.ProcessedAudioPath, job.ProcessedAudioProfile, keep, nil
+	}
+
+	processedPath, profile, err := uc.audioService.ProcessAudioWithProfile(ctx, audioPath, profile)
+	if err != nil {
+		return "", "", keep, err
+	}
+	remove := func() { uc.removeArtifact(jobID, processedPath) }
+
+	// WAV с другим профилем больше не нужен
+	if job != nil && job.ProcessedAudioPath != "" && job.ProcessedAudioPath != processedPath {
+		uc.removeArtifact(jobID, job.ProcessedAudioPath)
+	}
+
+	// Без срока хранения WAV нужен только на время распознавания
+	if uc.artifactRetention <= 0 || !uc.saveArtifact(ctx, jobID, processedPath, profile) {
+		return processedPath, profile, remove, nil
+	}
+	return processedPath, profile, keep, nil
+}
+
+// saveArtifact запоминает подготовленный WAV задачи. Без него задача обрабатывается дальше,
+// а при следующем запуске WAV будет получен заново
+func (uc *TranscriptionProcessingUseCase) saveArtifact(ctx context.Context, jobID int64, path string, profile entity.AudioProfile) bool {
+	if err := uc.jobRepo.SetProcessedAudio(ctx, jobID, path, profile); err != nil {
+		uc.logger.Warn("Failed to save processed audio",
+			"error", err,
+			"job_id", jobID,
+		)
+		return false
+	}
+	return true
+}
+
+// removeArtifact удаляет файл подготовленного WAV; если его уже нет, ничего не делает
+func (uc *TranscriptionProcessingUseCase) removeArtifact(jobID int64, path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		uc.logger.Warn("Failed to remove processed audio",
+			"error", err,
+			"job_id", jobID,
+			"path", path,
+		)
+	}
+}
+
+// RunArtifactCleanup удаляет подготовленные WAV, срок хранения которых истек, по расписанию до отмены контекста.
+// При нулевом сроке удаляются и WAV, сохраненные до его отключения
+func (uc *TranscriptionProcessingUseCase) RunArtifactCleanup(ctx context.Context) {
+	ticker := time.NewTicker(audioArtifactCleanupPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			uc.removeExpiredArtifacts(ctx, now.Add(-uc.artifactRetention))
+		}
+	}
+}
+
+// removeExpiredArtifacts удаляет WAV, которые не использовались после before. Запись о WAV забывается
+// до удаления файла, чтобы повторная транскрибация не взяла уже удаленный файл
+func (uc *TranscriptionProcessingUseCase) removeExpiredArtifacts(ctx context.Context, before time.Time) {
+	jobs, err := uc.jobRepo.GetProcessedAudioBefore(ctx, before, audioArtifactCleanupBatchSize)
+	if err != nil {
+		uc.logger.Error("Failed to get expired processed audio",
+			"error", err,
+		)
+		return
+	}
+
+	removed := 0
+	for _, job := range jobs {
+		cleared, err := uc.jobRepo.ClearProcessedAudio(ctx, job.ID, before)
+		if err != nil {
+			uc.logger.Warn("Failed to clear processed audio",
+				"error", err,
+				"job_id", job.ID,
+			)
+			continue
+		}
+		if !cleared {
+			continue
+		}
+		uc.removeArtifact(job.ID, job.ProcessedAudioPath)
+		removed++
+	}
+
+	if removed > 0 {
+		uc.logger.Info("Expired processed audio removed",
+			"count", removed,
+		)
+	}
+}
+
+// processedAudioAvailable сообщает, что у задачи есть подготовленный WAV и его файл еще не удален
+func processedAudioAvailable(job *entity.Job) bool {
+	if job == nil || job.ProcessedAudioPath == "" {
+		return false
+	}
+	_, err := os.Stat(job.ProcessedAudioPath)
+	return err == nil
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/parallel"
+)
+
+// exceedsFileLimit сообщает, что запись больше файла, который принимает провайдер распознавания,
+// и распознается по фрагментам
+func (uc *TranscriptionProcessingUseCase) exceedsFileLimit(audioPath string) bool {
+	if uc.chunkBytes <= 0 {
+		return false
+	}
+	info, err := os.Stat(audioPath)
+	return err == nil && info.Size() > uc.chunkBytes
+}
+
+// transcribeChunks нарезает запись на перекрывающиеся фрагменты, распознает их параллельно и сшивает фразы
+// в одну транскрипцию с метками времени от начала записи. Фрагменты удаляются после распознавания
+func (uc *TranscriptionProcessingUseCase) transcribeChunks(ctx context.Context, jobID int64, audioPath string, opts entity.TranscriptionOptions) ([]entity.SpeakerSegment, error) {
+	chunks, err := uc.audioService.SplitAudio(ctx, audioPath, uc.chunkBytes, uc.chunkOverlap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split audio: %w", err)
+	}
+	defer func() {
+		for _, chunk := range chunks {
+			if chunk.Path != audioPath {
+				uc.removeArtifact(jobID, chunk.Path)
+			}
+		}
+	}()
+
+	// Логирование начала распознавания по фрагментам
+	uc.logger.Info("Transcribing audio by chunks",
+		"job_id", jobID,
+		"chunks", len(chunks),
+		"overlap", uc.chunkOverlap,
+	)
+
+	// Транскрипция без фрагмента неполная, поэтому первая ошибка останавливает остальные фрагменты
+	results := make([][]entity.SpeakerSegment, len(chunks))
+	i, err := parallel.Run(ctx, len(chunks), uc.chunkConcurrency, func(ctx context.Context, i int) error {
+		var err error
+		results[i], err = uc.transcriptionService.TranscribeWithSpeakers(ctx, chunks[i].Path, opts)
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("chunk %d: %w", i+1, err)
+	}
+
+	segments := stitchChunks(chunks, results, uc.chunkOverlap.Seconds())
+
+	// Логирование успешного распознавания по фрагментам
+	uc.logger.Info("Audio transcribed by chunks successfully",
+		"job_id", jobID,
+		"chunks", len(chunks),
+		"segments", len(segments),
+	)
+
+	return segments, nil
+}
+
+// stitchChunks сдвигает фразы фрагментов на начало фрагмента и убирает повторы на стыках. Перекрытие соседних
+// фрагментов делится пополам: фраза достается фрагменту, в чью половину попадает ее середина. Фразы без меток
+// времени сохраняются все, потому что их место в записи неизвестно
+func stitchChunks(chunks []entity.AudioChunk, results [][]entity.SpeakerSegment, overlap float64) []entity.SpeakerSegment {
+	var segments []entity.SpeakerSegment
+	for i, chunk := range chunks {
+		from, to := math.Inf(-1), math.Inf(1)
+		if i > 0 {
+			from = chunk.Offset + overlap/2
+		}
+		if i < len(chunks)-1 {
+			to = chunks[i+1].Offset + overlap/2
+		}
+
+		for _, segment := range results[i] {
+			if segment.End <= segment.Start {
+				segments = append(segments, segment)
+				continue
+			}
+
+			segment.Start += chunk.Offset
+			segment.End += chunk.Offset
+			if middle := (segment.Start + segment.End) / 2; middle < from || middle >= to {
+				continue
+			}
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+)
+
+// audioLinkExtensions - расширения файлов, прямые ссылки на которые обрабатываются как записи
+var audioLinkExtensions = map[string]bool{
+	".mp3":  true,
+	".m4a":  true,
+	".aac":  true,
+	".ogg":  true,
+	".oga":  true,
+	".opus": true,
+	".wav":  true,
+	".flac": true,
+	".wma":  true,
+	".amr":  true,
+	".weba": true,
+}
+
+// audioLinkTrimChars - знаки препинания, которые могут примыкать к ссылке в тексте
+const audioLinkTrimChars = ".,;:!?)»\"'"
+
+// ParseAudioLink ищет в сообщении прямую ссылку http(s) на аудиофайл и возвращает ее и остальной текст
+// сообщения, который учитывается как подпись к записи
+func ParseAudioLink(text string) (string, string, bool) {
+	for _, field := range strings.Fields(text) {
+		link := strings.TrimRight(field, audioLinkTrimChars)
+
+		parsed, err := url.Parse(link)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			continue
+		}
+		if !audioLinkExtensions[strings.ToLower(path.Ext(parsed.Path))] {
+			continue
+		}
+
+		caption := strings.TrimSpace(strings.Replace(text, field, "", 1))
+		return link, caption, true
+	}
+
+	return "", "", false
+}
+
+// HandleAudioLink обрабатывает прямую ссылку на аудиофайл, присланную текстом: загружает файл и создает
+// задачу его обработки, как для присланного аудиофайла. Остальной текст сообщения учитывается как подпись.
+// Возвращает ответ и ID созданной задачи (0, если файл не удалось загрузить)
+func (uc *TelegramHandlersUseCase) HandleAudioLink(ctx context.Context, telegramID int64, username string, messageID int, audioURL string, caption string) (string, int64, error) {
+	// Логирование начала обработки ссылки на аудиофайл
+	uc.logger.Info("Handling audio link",
+		"telegram_id", telegramID,
+		"url", audioURL,
+	)
+
+	// Получение или создание пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		// Если пользователь не найден, создаем нового
+		user = &entity.User{
+			TelegramID: telegramID,
+			Username:   username,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+
+		err = uc.userRepo.Create(ctx, user)
+		if err != nil {
+			uc.logger.Error("Failed to create user",
+				"error", err,
+			)
+			return "", 0, fmt.Errorf("failed to create user: %w", err)
+		}
+	}
+
+	// Загрузка файла занимает время, поэтому сразу сообщаем о ней
+	if _, err := uc.messenger.SendReply(telegramID, messageID, i18n.T(ctx, "audio_link.downloading")); err != nil {
+		uc.logger.Warn("Failed to send audio download message",
+			"error", err,
+			"telegram_id", telegramID,
+		)
+	}
+
+	filePath, fileName, err := uc.urlIngestionService.Download(ctx, telegramID, audioURL)
+	switch {
+	case errors.Is(err, service.ErrRemoteFileTooLarge):
+		return i18n.T(ctx, "audio_link.too_large", uc.urlIngestionService.MaxSize()>>20), 0, nil
+	case errors.Is(err, service.ErrRemoteFileNotAudio):
+		return i18n.T(ctx, "audio_link.not_audio"), 0, nil
+	case err != nil:
+		return i18n.T(ctx, "audio_link.failed"), 0, nil
+	}
+
+	// Ожидающая повестка встречи привязывается к задаче при ее создании
+	agendaNote, err := uc.meetingAgendaNote(ctx, user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// Обработка загруженного файла
+	jobID, err := uc.audioProcessingUseCase.ProcessAudio(ctx, telegramID, messageID, filePath, fileName, "", caption)
+	if err != nil {
+		uc.logger.Error("Failed to process audio link",
+			"error", err,
+		)
+		return "", 0, fmt.Errorf("failed to process audio link: %w", err)
+	}
+
+	// Формирование сообщения об успешном начале обработки
+	responseMessage := i18n.T(ctx, "accepted.audio") + i18n.T(ctx, "accepted.job_id", jobID)
+	if caption != "" {
+		responseMessage += i18n.T(ctx, "accepted.caption")
+	}
+	responseMessage += agendaNote
+	responseMessage += "\n\n" + i18n.T(ctx, "accepted.audio_profile")
+
+	// Учет созданной задачи в статистике использования
+	uc.usageAnalyticsUseCase.Track(ctx, telegramID, entity.UsageEventJobCreated, usageSourceAudioLink)
+
+	// Логирование успешного начала обработки записи по ссылке
+	uc.logger.Info("Successfully started processing audio link",
+		"telegram_id", telegramID,
+		"user_id", user.ID,
+		"job_id", jobID,
+	)
+
+	return responseMessage, jobID, nil
+}
@@ -2,48 +2,88 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"os"
 	"time"
 
 	"github.com/112Alex/project_obsidian/internal/domain/entity"
 	"github.com/112Alex/project_obsidian/internal/domain/repository"
 	"github.com/112Alex/project_obsidian/internal/domain/service"
 	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
 )
 
+// errRetrySourceMissing - задачу с ошибкой нельзя перезапустить: транскрипции нет, а исходный файл уже удален
+var errRetrySourceMissing = errors.New("job source audio is no longer available")
+
+// errTrimSourceMissing - из записи задачи нельзя вырезать фрагмент: исходный файл уже удален
+var errTrimSourceMissing = errors.New("parent job audio is no longer available")
+
+// errTrimOutOfRange - промежуток начинается после конца записи
+var errTrimOutOfRange = errors.New("trim range starts after the end of the recording")
+
 // AudioProcessingUseCase представляет собой сценарий обработки аудио
 type AudioProcessingUseCase struct {
 	userRepo     repository.UserRepository
 	jobRepo      repository.JobRepository
+	chapterRepo  repository.VideoChapterRepository
+	agendaRepo   repository.MeetingAgendaRepository
 	queueService service.QueueService
 	audioService service.AudioService
-	logger       *logger.Logger
+	// inlineMaxDuration - голосовые сообщения не длиннее этого обрабатываются сразу, минуя очередь
+	inlineMaxDuration time.Duration
+	logger            *logger.Logger
 }
 
 // NewAudioProcessingUseCase создает новый сценарий обработки аудио
 func NewAudioProcessingUseCase(
 	userRepo repository.UserRepository,
 	jobRepo repository.JobRepository,
+	chapterRepo repository.VideoChapterRepository,
+	agendaRepo repository.MeetingAgendaRepository,
 	queueService service.QueueService,
 	audioService service.AudioService,
+	inlineMaxDuration time.Duration,
 	logger *logger.Logger,
 ) *AudioProcessingUseCase {
 	return &AudioProcessingUseCase{
-		userRepo:     userRepo,
-		jobRepo:      jobRepo,
-		queueService: queueService,
-		audioService: audioService,
-		logger:       logger,
+		userRepo:          userRepo,
+		jobRepo:           jobRepo,
+		chapterRepo:       chapterRepo,
+		agendaRepo:        agendaRepo,
+		queueService:      queueService,
+		audioService:      audioService,
+		inlineMaxDuration: inlineMaxDuration,
+		logger:            logger,
 	}
 }
 
-// ProcessAudio обрабатывает аудио файл
-func (uc *AudioProcessingUseCase) ProcessAudio(ctx context.Context, userID int64, audioPath string, fileName string) (int64, error) {
+// ProcessAudio обрабатывает аудио файл; sourceMessageID - ID исходного сообщения в Telegram,
+// fileUniqueID - постоянный идентификатор файла в Telegram для поиска повторно присланных файлов,
+// instructions - дополнительные указания к конспекту из подписи к аудио. Ожидающая повестка встречи
+// пользователя привязывается к задаче
+func (uc *AudioProcessingUseCase) ProcessAudio(ctx context.Context, userID int64, sourceMessageID int, audioPath string, fileName string, fileUniqueID string, instructions string) (int64, error) {
+	jobID, _, err := uc.processAudio(ctx, userID, sourceMessageID, audioPath, fileName, fileUniqueID, instructions, false)
+	return jobID, err
+}
+
+// ProcessVoice обрабатывает голосовое сообщение так же, как ProcessAudio, но короткое сообщение обрабатывается
+// сразу, минуя очередь: для записи в несколько секунд ожидание в очереди занимает больше времени, чем сама
+// обработка. Возвращает ID задачи и признак того, что задача уже обработана и результат отправлен
+func (uc *AudioProcessingUseCase) ProcessVoice(ctx context.Context, userID int64, sourceMessageID int, audioPath string, fileName string, fileUniqueID string, instructions string) (int64, bool, error) {
+	return uc.processAudio(ctx, userID, sourceMessageID, audioPath, fileName, fileUniqueID, instructions, true)
+}
+
+// processAudio создает задачу обработки аудио; allowInline разрешает обработать короткую запись сразу
+func (uc *AudioProcessingUseCase) processAudio(ctx context.Context, userID int64, sourceMessageID int, audioPath string, fileName string, fileUniqueID string, instructions string, allowInline bool) (int64, bool, error) {
 	// Логирование начала обработки аудио
 	uc.logger.Info("Processing audio",
 		"user_id", userID,
 		"audio_path", audioPath,
 		"file_name", fileName,
+		"has_instructions", instructions != "",
 	)
 
 	// Получение пользователя
@@ -52,7 +92,7 @@ func (uc *AudioProcessingUseCase) ProcessAudio(ctx context.Context, userID int64
 		uc.logger.Error("Failed to get user",
 			"error", err,
 		)
-		return 0, fmt.Errorf("failed to get user: %w", err)
+		return 0, false, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	// Если пользователь не найден, создаем нового
@@ -68,10 +108,155 @@ func (uc *AudioProcessingUseCase) ProcessAudio(ctx context.Context, userID int64
 			uc.logger.Error("Failed to create user",
 				"error", err,
 			)
-			return 0, fmt.Errorf("failed to create user: %w", err)
+			return 0, false, fmt.Errorf("failed to create user: %w", err)
 		}
 	}
 
+	// Получение длительности аудио
+	duration, err := uc.audioService.GetAudioDuration(ctx, audioPath)
+	if err != nil {
+		uc.logger.Error("Failed to get audio duration",
+			"error", err,
+		)
+		return 0, false, fmt.Errorf("failed to get audio duration: %w", err)
+	}
+
+	// Создание задачи
+	job := entity.Job{
+		UserID:          user.ID,
+		Type:            entity.JobTypeTranscription,
+		Status:          entity.JobStatusCreated,
+		AudioFilePath:   audioPath,
+		FileName:        fileName,
+		Duration:        duration,
+		SourceMessageID: sourceMessageID,
+		Instructions:    instructions,
+		FileUniqueID:    fileUniqueID,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	// Короткое голосовое сообщение обрабатывается сразу, если для него есть свободное место; иначе - через очередь
+	if allowInline && uc.inlineMaxDuration > 0 && duration <= uc.inlineMaxDuration.Seconds() {
+		if err := uc.createJob(ctx, &job, nil, true); err != nil {
+			return 0, false, err
+		}
+		inline := uc.queueService.RunNow(ctx, entity.QueueJob{
+			JobID:     job.ID,
+			UserID:    job.UserID,
+			JobType:   entity.JobTypeTranscription,
+			CreatedAt: time.Now(),
+			Payload:   transcriptionPayload(job.AudioFilePath),
+		})
+		if inline {
+			return job.ID, true, nil
+		}
+		return job.ID, false, uc.enqueue(ctx, &job)
+	}
+
+	jobID, err := uc.createAndEnqueue(ctx, &job, nil, true)
+	return jobID, false, err
+}
+
+// ProcessVideoFile обрабатывает видеосообщение или видеофайл из Telegram: извлекает звуковую дорожку
+// и создает по ней задачу так же, как для аудио. Видеофайл после извлечения удаляется
+func (uc *AudioProcessingUseCase) ProcessVideoFile(ctx context.Context, userID int64, sourceMessageID int, videoPath string, fileName string, fileUniqueID string, instructions string) (int64, error) {
+	audioPath, err := uc.audioService.ExtractAudioFromVideo(ctx, videoPath)
+	if err != nil {
+		uc.logger.Error("Failed to extract audio from video",
+			"error", err,
+		)
+		return 0, fmt.Errorf("failed to extract audio from video: %w", err)
+	}
+
+	if err := os.Remove(videoPath); err != nil && !os.IsNotExist(err) {
+		uc.logger.Warn("Failed to remove video file",
+			"error", err,
+			"path", videoPath,
+		)
+	}
+
+	return uc.ProcessAudio(ctx, userID, sourceMessageID, audioPath, fileName, fileUniqueID, instructions)
+}
+
+// podcastEpisodeAudio - загруженный выпуск подкаста, для которого создается задача
+type podcastEpisodeAudio struct {
+	audioPath string
+	title     string
+}
+
+// ProcessPodcastEpisodes создает задачи обработки выпусков подкаста одним пакетом и ставит их в очередь.
+// Если notionDatabaseID не пуст, результат сохраняется в эту базу Notion вместо базы пользователя.
+// Возвращает ID задач в порядке выпусков; для выпуска, длительность которого не удалось определить,
+// задача не создается и ID равен 0. Ошибка постановки в очередь отдельной задачи только логируется
+func (uc *AudioProcessingUseCase) ProcessPodcastEpisodes(ctx context.Context, user *entity.User, episodes []podcastEpisodeAudio, notionDatabaseID string) ([]int64, error) {
+	// Логирование начала обработки выпусков
+	uc.logger.Info("Processing podcast episodes",
+		"user_id", user.ID,
+		"episodes", len(episodes),
+	)
+
+	jobIDs := make([]int64, len(episodes))
+	jobs := make([]*entity.Job, 0, len(episodes))
+	indexes := make([]int, 0, len(episodes))
+	for i, episode := range episodes {
+		// Получение длительности аудио
+		duration, err := uc.audioService.GetAudioDuration(ctx, episode.audioPath)
+		if err != nil {
+			uc.logger.Error("Failed to get audio duration",
+				"error", err,
+				"audio_path", episode.audioPath,
+			)
+			continue
+		}
+
+		jobs = append(jobs, &entity.Job{
+			UserID:           user.ID,
+			Type:             entity.JobTypePodcastEpisode,
+			Status:           entity.JobStatusCreated,
+			AudioFilePath:    episode.audioPath,
+			FileName:         episode.title,
+			Duration:         duration,
+			NotionDatabaseID: notionDatabaseID,
+		})
+		indexes = append(indexes, i)
+	}
+	if len(jobs) == 0 {
+		return jobIDs, nil
+	}
+
+	// Сохранение задач одним пакетом, чтобы не обращаться к базе отдельно за каждым выпуском
+	if err := uc.jobRepo.CreateBatch(ctx, jobs); err != nil {
+		uc.logger.Error("Failed to create jobs",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to create jobs: %w", err)
+	}
+
+	for i, job := range jobs {
+		jobIDs[indexes[i]] = job.ID
+		if err := uc.enqueue(ctx, job); err != nil {
+			uc.logger.Error("Failed to enqueue podcast episode",
+				"error", err,
+				"job_id", job.ID,
+			)
+		}
+	}
+
+	return jobIDs, nil
+}
+
+// ProcessVideo создает задачу с типом jobType обработки звуковой дорожки видео или выпуска подкаста,
+// присланного ссылкой. Главы сохраняются вместе с задачей: по ним составляется краткое содержание
+func (uc *AudioProcessingUseCase) ProcessVideo(ctx context.Context, user *entity.User, sourceMessageID int, audioPath string, video *entity.VideoInfo, jobType entity.JobType) (int64, error) {
+	// Логирование начала обработки видео
+	uc.logger.Info("Processing video",
+		"user_id", user.ID,
+		"job_type", jobType,
+		"audio_path", audioPath,
+		"chapters", len(video.Chapters),
+	)
+
 	// Получение длительности аудио
 	duration, err := uc.audioService.GetAudioDuration(ctx, audioPath)
 	if err != nil {
@@ -83,44 +268,284 @@ func (uc *AudioProcessingUseCase) ProcessAudio(ctx context.Context, userID int64
 
 	// Создание задачи
 	job := entity.Job{
-		UserID:        user.ID,
-		Type:          entity.JobTypeTranscription,
-		Status:        entity.JobStatusCreated,
-		AudioFilePath: audioPath,
-		FileName:      fileName,
-		Duration:      duration,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		UserID:          user.ID,
+		Type:            jobType,
+		Status:          entity.JobStatusCreated,
+		AudioFilePath:   audioPath,
+		FileName:        video.Title,
+		Duration:        duration,
+		SourceMessageID: sourceMessageID,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	return uc.createAndEnqueue(ctx, &job, video.Chapters, false)
+}
+
+// ProcessTrim вырезает из записи задачи parent промежуток с fromSeconds по toSeconds и создает по нему
+// новую задачу. Фрагмент берется из уже загруженного файла задачи, поэтому запись не нужно присылать заново;
+// sourceMessageID - ID сообщения с командой. Конец промежутка после конца записи обрезается до него
+func (uc *AudioProcessingUseCase) ProcessTrim(ctx context.Context, parent *entity.Job, sourceMessageID int, fromSeconds int, toSeconds int) (int64, error) {
+	// Логирование начала вырезания фрагмента
+	uc.logger.Info("Processing audio trim",
+		"parent_job_id", parent.ID,
+		"from", fromSeconds,
+		"to", toSeconds,
+	)
+
+	// Исходный файл мог быть удален при очистке временного каталога
+	if parent.AudioFilePath == "" {
+		return 0, errTrimSourceMissing
+	}
+	duration, err := uc.audioService.GetAudioDuration(ctx, parent.AudioFilePath)
+	if err != nil {
+		uc.logger.Warn("Parent job audio is unavailable",
+			"error", err,
+			"job_id", parent.ID,
+		)
+		return 0, errTrimSourceMissing
+	}
+	if float64(fromSeconds) >= duration {
+		return 0, errTrimOutOfRange
+	}
+	toSeconds = min(toSeconds, int(math.Ceil(duration)))
+
+	audioPath, err := uc.audioService.TrimAudio(ctx, parent.AudioFilePath, fromSeconds, toSeconds)
+	if err != nil {
+		uc.logger.Error("Failed to trim audio",
+			"error", err,
+		)
+		return 0, fmt.Errorf("failed to trim audio: %w", err)
+	}
+
+	// Фрагмент обрабатывается как отдельная запись с теми же указаниями и профилем. Постоянный
+	// идентификатор файла не копируется: иначе повторно присланная запись нашлась бы как этот фрагмент
+	job := entity.Job{
+		UserID:          parent.UserID,
+		Type:            entity.JobTypeTranscription,
+		Status:          entity.JobStatusCreated,
+		AudioFilePath:   audioPath,
+		FileName:        fmt.Sprintf("%s (%s–%s)", parent.FileName, textutil.FormatTimestamp(fromSeconds), textutil.FormatTimestamp(toSeconds)),
+		Duration:        min(duration, float64(toSeconds)) - float64(fromSeconds),
+		SourceMessageID: sourceMessageID,
+		Instructions:    parent.Instructions,
+		AudioProfile:    parent.AudioProfile,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
 	}
 
+	return uc.createAndEnqueue(ctx, &job, nil, false)
+}
+
+// createAndEnqueue сохраняет задачу и ставит ее в очередь на транскрибацию.
+// Главы видео и повестка встречи (если attachAgenda) привязываются до постановки в очередь,
+// чтобы обработчики их уже видели
+func (uc *AudioProcessingUseCase) createAndEnqueue(ctx context.Context, job *entity.Job, chapters []*entity.VideoChapter, attachAgenda bool) (int64, error) {
+	if err := uc.createJob(ctx, job, chapters, attachAgenda); err != nil {
+		return 0, err
+	}
+	if err := uc.enqueue(ctx, job); err != nil {
+		return 0, err
+	}
+	return job.ID, nil
+}
+
+// createJob сохраняет задачу и привязывает к ней главы видео и повестку встречи (если attachAgenda)
+func (uc *AudioProcessingUseCase) createJob(ctx context.Context, job *entity.Job, chapters []*entity.VideoChapter, attachAgenda bool) error {
 	// Сохранение задачи в базе данных
 	job.ID = 0 // Убедимся, что ID не задан
-	err = uc.jobRepo.Create(ctx, &job)
+	err := uc.jobRepo.Create(ctx, job)
 	if err != nil {
 		uc.logger.Error("Failed to create job",
 			"error", err,
 		)
-		return 0, fmt.Errorf("failed to create job: %w", err)
+		return fmt.Errorf("failed to create job: %w", err)
 	}
 	jobID := job.ID
 
-	// Создание задачи для очереди - используем напрямую EnqueueTranscriptionJob
+	if len(chapters) > 0 {
+		err = uc.chapterRepo.ReplaceForJob(ctx, jobID, chapters)
+		if err != nil {
+			uc.logger.Error("Failed to save video chapters",
+				"error", err,
+			)
+			return fmt.Errorf("failed to save video chapters: %w", err)
+		}
+	}
+
+	if attachAgenda {
+		agenda, err := uc.agendaRepo.AttachPending(ctx, job.UserID, jobID, time.Now().Add(-meetingAgendaTTL))
+		if err != nil {
+			uc.logger.Error("Failed to attach meeting agenda",
+				"error", err,
+			)
+			return fmt.Errorf("failed to attach meeting agenda: %w", err)
+		}
+		if agenda != nil {
+			uc.logger.Info("Meeting agenda attached to job",
+				"job_id", jobID,
+				"agenda_id", agenda.ID,
+			)
+		}
+	}
+
+	return nil
+}
 
+// enqueue ставит сохраненную задачу в очередь на транскрибацию
+func (uc *AudioProcessingUseCase) enqueue(ctx context.Context, job *entity.Job) error {
 	// Добавление задачи в очередь
-	err = uc.queueService.EnqueueTranscriptionJob(ctx, jobID, user.ID, audioPath)
+	err := uc.queueService.EnqueueTranscriptionJob(ctx, job.ID, job.UserID, job.AudioFilePath)
 	if err != nil {
 		uc.logger.Error("Failed to push job to queue",
 			"error", err,
 		)
-		return 0, fmt.Errorf("failed to push job to queue: %w", err)
+		return fmt.Errorf("failed to push job to queue: %w", err)
 	}
 
 	// Логирование успешной обработки аудио
 	uc.logger.Info("Audio processed successfully",
-		"job_id", jobID,
+		"job_id", job.ID,
+	)
+
+	return nil
+}
+
+// Resummarize ставит завершенную задачу в очередь на повторную суммаризацию сохраненной транскрипции.
+// Новое краткое содержание заменяет прежнее и приходит отдельным уведомлением
+func (uc *AudioProcessingUseCase) Resummarize(ctx context.Context, job *entity.Job) error {
+	// Логирование начала повторной суммаризации
+	uc.logger.Info("Resummarizing job",
+		"job_id", job.ID,
+		"user_id", job.UserID,
+	)
+
+	// Статус возвращается к транскрибированной задаче, чтобы повторное нажатие не ставило ее в очередь дважды
+	err := uc.jobRepo.UpdateStatus(ctx, job.ID, entity.JobStatusTranscribed, "")
+	if err != nil {
+		uc.logger.Error("Failed to update job status",
+			"error", err,
+		)
+		return fmt.Errorf("failed to update job status: %w", err)
+	}
+
+	// Транскрипцию этап суммаризации читает из базы данных; стиль, выбранный для задачи, сохраняется
+	summarizationJob := entity.QueueJob{
+		JobID:   job.ID,
+		UserID:  job.UserID,
+		JobType: entity.JobTypeSummarization,
+		Payload: summarizationPayload(job.SummaryStyle),
+	}
+
+	// Добавление задачи в очередь
+	err = uc.queueService.PushJob(ctx, summarizationJob)
+	if err != nil {
+		uc.logger.Error("Failed to push summarization job to queue",
+			"error", err,
+		)
+		return fmt.Errorf("failed to push summarization job to queue: %w", err)
+	}
+
+	return nil
+}
+
+// retryStage возвращает этап, с которого перезапускается задача с ошибкой: выгрузка повторяется, если на ней
+// задача и упала, а краткое содержание сохранилось; суммаризация - если сохранилась транскрипция
+func retryStage(job *entity.Job) entity.JobStageName {
+	switch {
+	case job.FailedStage == entity.JobStageNotionSync && job.Summary != "":
+		return entity.JobStageNotionSync
+	case job.Transcription != "":
+		return entity.JobStageSummarization
+	default:
+		return entity.JobStageTranscription
+	}
+}
+
+// Retry перезапускает задачу с ошибкой с этапа retryStage: повторяется выгрузка результатов, суммаризация
+// сохраненной транскрипции или вся обработка записи. Возвращает errRetrySourceMissing, если транскрипции нет,
+// а исходный файл и подготовленный к распознаванию WAV уже удалены
+func (uc *AudioProcessingUseCase) Retry(ctx context.Context, job *entity.Job) error {
+	switch retryStage(job) {
+	case entity.JobStageNotionSync:
+		return uc.resync(ctx, job)
+	case entity.JobStageSummarization:
+		return uc.Resummarize(ctx, job)
+	}
+
+	// Исходный файл мог быть удален при очистке временного каталога: без него перезапуск бесполезен,
+	// если не сохранился подготовленный WAV
+	if !processedAudioAvailable(job) {
+		if job.AudioFilePath == "" {
+			return errRetrySourceMissing
+		}
+		if _, err := uc.audioService.GetAudioDuration(ctx, job.AudioFilePath); err != nil {
+			uc.logger.Warn("Job source audio is unavailable",
+				"error", err,
+				"job_id", job.ID,
+			)
+			return errRetrySourceMissing
+		}
+	}
+
+	// Логирование перезапуска задачи
+	uc.logger.Info("Retrying job",
+		"job_id", job.ID,
+		"user_id", job.UserID,
+	)
+
+	err := uc.jobRepo.UpdateStatus(ctx, job.ID, entity.JobStatusQueued, "")
+	if err != nil {
+		uc.logger.Error("Failed to update job status",
+			"error", err,
+		)
+		return fmt.Errorf("failed to update job status: %w", err)
+	}
+
+	err = uc.queueService.EnqueueTranscriptionJob(ctx, job.ID, job.UserID, job.AudioFilePath)
+	if err != nil {
+		uc.logger.Error("Failed to push job to queue",
+			"error", err,
+		)
+		return fmt.Errorf("failed to push job to queue: %w", err)
+	}
+
+	return nil
+}
+
+// resync ставит задачу, упавшую при выгрузке, в очередь на повторную выгрузку сохраненных результатов.
+// Места, в которые задача уже выгружена, повторно не заполняются
+func (uc *AudioProcessingUseCase) resync(ctx context.Context, job *entity.Job) error {
+	// Логирование повторной выгрузки
+	uc.logger.Info("Retrying job sync",
+		"job_id", job.ID,
+		"user_id", job.UserID,
 	)
 
-	return jobID, nil
+	err := uc.jobRepo.UpdateStatus(ctx, job.ID, entity.JobStatusSummarized, "")
+	if err != nil {
+		uc.logger.Error("Failed to update job status",
+			"error", err,
+		)
+		return fmt.Errorf("failed to update job status: %w", err)
+	}
+
+	// Результаты этап выгрузки читает из базы данных
+	notionJob := entity.QueueJob{
+		JobID:   job.ID,
+		UserID:  job.UserID,
+		JobType: entity.JobTypeNotion,
+	}
+
+	err = uc.queueService.PushJob(ctx, notionJob)
+	if err != nil {
+		uc.logger.Error("Failed to push Notion job to queue",
+			"error", err,
+		)
+		return fmt.Errorf("failed to push Notion job to queue: %w", err)
+	}
+
+	return nil
 }
 
 // GetJobStatus возвращает статус задачи
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/config"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// audioRateLimitKeyPrefix - префикс ключа учета аудио сообщений пользователя
+const audioRateLimitKeyPrefix = "audio:"
+
+// AudioRateLimitUseCase представляет собой сценарий ограничения частоты аудио сообщений: пользователь может
+// прислать не больше лимита своей роли (бесплатный тариф, премиум, администратор) за скользящее окно.
+// В отличие от дневного лимита задач, ограничение проверяется до загрузки файла и защищает от всплесков
+type AudioRateLimitUseCase struct {
+	rateLimitRepo repository.RateLimitRepository
+	quota         config.QuotaConfig
+	admins        *Admins
+	logger        *logger.Logger
+}
+
+// NewAudioRateLimitUseCase создает новый сценарий ограничения частоты аудио сообщений
+func NewAudioRateLimitUseCase(
+	rateLimitRepo repository.RateLimitRepository,
+	quota config.QuotaConfig,
+	admins *Admins,
+	logger *logger.Logger,
+) *AudioRateLimitUseCase {
+	return &AudioRateLimitUseCase{
+		rateLimitRepo: rateLimitRepo,
+		quota:         quota,
+		admins:        admins,
+		logger:        logger,
+	}
+}
+
+// Take учитывает аудио сообщение пользователя и возвращает 0, если лимит его роли не превышен,
+// или время, через которое пользователь сможет прислать следующее
+func (uc *AudioRateLimitUseCase) Take(ctx context.Context, telegramID int64) (time.Duration, error) {
+	limit := uc.quota.AudioLimit(telegramID, uc.admins.IDs())
+	if limit <= 0 {
+		return 0, nil
+	}
+
+	retryAfter, err := uc.rateLimitRepo.Take(ctx, audioRateLimitKeyPrefix+strconv.FormatInt(telegramID, 10), limit, uc.quota.AudioWindow)
+	if err != nil {
+		return 0, fmt.Errorf("failed to take audio rate limit: %w", err)
+	}
+
+	if retryAfter > 0 {
+		// Логирование превышения лимита
+		uc.logger.Info("User exceeded audio rate limit",
+			"telegram_id", telegramID,
+			"limit", limit,
+			"window", uc.quota.AudioWindow,
+		)
+	}
+
+	return retryAfter, nil
+}
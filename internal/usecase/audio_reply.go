@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+)
+
+// audioReplySummaryRequests - ответы на запись, которыми просят прислать краткое содержание как есть
+var audioReplySummaryRequests = map[string]bool{
+	"саммари":             true,
+	"кратко":              true,
+	"краткое содержание":  true,
+	"summary":             true,
+	"tl;dr":               true,
+	summarizeReplyCommand: true,
+}
+
+// audioReplyLanguages - основы названий языков в просьбах вида «транскрипцию на английском» или «in English»
+var audioReplyLanguages = map[string][]string{
+	"ru": {"русск", "russian"},
+	"uk": {"украинск", "ukrainian"},
+	"en": {"английск", "english"},
+	"de": {"немецк", "german"},
+	"fr": {"французск", "french"},
+	"es": {"испанск", "spanish"},
+	"it": {"итальянск", "italian"},
+	"pt": {"португальск", "portuguese"},
+}
+
+// audioReplyRequest представляет собой действие, о котором пользователь попросил в ответ на запись
+type audioReplyRequest struct {
+	action   string
+	language string
+}
+
+// parseAudioReply определяет по тексту ответа на запись, что с ней сделать: прислать или перевести транскрипцию,
+// прислать или перевести краткое содержание, подобрать теги, выгрузить в Notion. Остальные просьбы, например
+// «составь список задач», выполняются по транскрипции как есть
+func parseAudioReply(text string) audioReplyRequest {
+	lower := strings.ToLower(strings.TrimSpace(text))
+	language := audioReplyLanguage(lower)
+	transcript := containsAnyOf(lower, "транскри", "расшифр", "transcri", "текст", "text")
+
+	switch {
+	case transcript && language != "":
+		return audioReplyRequest{action: followUpTranscriptTranslate, language: language}
+	case transcript:
+		return audioReplyRequest{action: followUpFullText}
+	case language != "" || containsAnyOf(lower, "перевед", "перевод", "translat"):
+		return audioReplyRequest{action: followUpTranslate, language: language}
+	case audioReplySummaryRequests[strings.Trim(lower, ".!")]:
+		return audioReplyRequest{action: followUpSummary}
+	case containsAnyOf(lower, "тег", "tags"):
+		return audioReplyRequest{action: followUpTags}
+	case containsAnyOf(lower, "notion", "ноушн"):
+		return audioReplyRequest{action: followUpNotion}
+	}
+	return audioReplyRequest{action: followUpInstruction}
+}
+
+// audioReplyLanguage возвращает код языка, названного в просьбе, или пустую строку
+func audioReplyLanguage(lower string) string {
+	for code, stems := range audioReplyLanguages {
+		if containsAnyOf(lower, stems...) {
+			return code
+		}
+	}
+	return ""
+}
+
+// containsAnyOf сообщает, что текст содержит хотя бы одну из подстрок
+func containsAnyOf(text string, substrings ...string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(text, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleAudioReply выполняет просьбу, которую пользователь написал в ответ на ранее присланную запись,
+// например «транскрипцию на английском» или «составь список задач». Задача ищется по сообщению с записью,
+// а если запись переслана заново - по файлу. Готовый результат обрабатывается в очереди, как действия
+// по кнопкам под уведомлением; пока задача обрабатывается, просьба становится указаниями к конспекту.
+// Если задачи по записи нет, возвращается true: запись нужно обработать заново с просьбой в качестве подписи
+func (uc *TelegramHandlersUseCase) HandleAudioReply(ctx context.Context, telegramID int64, messageID int, sourceMessageID int, fileUniqueID string, text string) (string, bool, error) {
+	// Логирование просьбы в ответ на запись
+	uc.logger.Info("Handling reply to audio",
+		"telegram_id", telegramID,
+		"source_message_id", sourceMessageID,
+	)
+
+	// Новый пользователь еще ничего не присылал
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil || user == nil {
+		return "", true, nil
+	}
+
+	job, err := uc.jobRepo.GetLatestBySourceMessage(ctx, user.ID, sourceMessageID)
+	if err == nil && job == nil && fileUniqueID != "" {
+		job, err = uc.jobRepo.GetLatestByFileUniqueID(ctx, user.ID, fileUniqueID)
+	}
+	if err != nil {
+		uc.logger.Error("Failed to find job by replied message",
+			"error", err,
+		)
+		return "", false, fmt.Errorf("failed to find job by replied message: %w", err)
+	}
+	if job == nil {
+		return "", true, nil
+	}
+
+	request := parseAudioReply(text)
+
+	// Пока краткое содержание не составлено, просьба дополняет указания к нему
+	if job.Status != entity.JobStatusCompleted {
+		if request.action == followUpInstruction && job.SourceMessageID != 0 {
+			updated, err := uc.jobRepo.SetInstructionsBySourceMessage(ctx, user.ID, job.SourceMessageID, strings.TrimSpace(text))
+			if err != nil {
+				return "", false, fmt.Errorf("failed to update job instructions: %w", err)
+			}
+			if updated {
+				return i18n.T(ctx, "audio_reply.instructions", job.ID), false, nil
+			}
+		}
+		return i18n.T(ctx, "audio_reply.processing", job.ID, job.ID), false, nil
+	}
+
+	switch {
+	case request.action == followUpFullText || request.action == followUpTranscriptTranslate || request.action == followUpInstruction:
+		if job.Transcription == "" {
+			return i18n.T(ctx, "audio_reply.no_transcript", job.ID), false, nil
+		}
+	case job.Summary == "":
+		return i18n.T(ctx, "audio_reply.no_summary", job.ID), false, nil
+	}
+	if request.action == followUpNotion && (user.NotionToken == "" || user.NotionDatabaseID == "") {
+		return i18n.T(ctx, "job_action.notion_not_connected"), false, nil
+	}
+
+	instructions := ""
+	if request.action == followUpInstruction {
+		instructions = strings.TrimSpace(text)
+	}
+	if err := uc.jobFollowUpUseCase.EnqueueRequest(ctx, job, request.action, messageID, request.language, instructions); err != nil {
+		return "", false, fmt.Errorf("failed to enqueue follow-up: %w", err)
+	}
+
+	// Логирование принятой просьбы
+	uc.logger.Info("Reply to audio enqueued",
+		"telegram_id", telegramID,
+		"job_id", job.ID,
+		"action", request.action,
+	)
+
+	return i18n.T(ctx, "audio_reply.accepted", job.ID), false, nil
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
+)
+
+// HandleTrim обрабатывает команду /trim, отправленную ответом на запись: вырезает из записи промежуток
+// и создает по нему новую задачу. Запись ищется по постоянному идентификатору файла fileUniqueID
+// среди задач пользователя, поэтому подходит как обработанная, так и еще ожидающая обработки запись.
+// Возвращает ответ и ID созданной задачи (0, если задача не создана)
+func (uc *TelegramHandlersUseCase) HandleTrim(ctx context.Context, telegramID int64, messageID int, fileUniqueID string, args string) (string, int64, error) {
+	// Логирование начала обработки команды /trim
+	uc.logger.Info("Handling /trim command",
+		"telegram_id", telegramID,
+	)
+
+	// Разбор промежутка
+	parts := strings.Fields(args)
+	if fileUniqueID == "" || len(parts) != 2 {
+		return i18n.T(ctx, "trim.usage"), 0, nil
+	}
+	fromSeconds, okFrom := parseTimestamp(parts[0])
+	toSeconds, okTo := parseTimestamp(parts[1])
+	if !okFrom || !okTo {
+		return i18n.T(ctx, "trim.usage"), 0, nil
+	}
+	if toSeconds <= fromSeconds {
+		return i18n.T(ctx, "trim.invalid_range"), 0, nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", 0, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Поиск задачи записи, на которую ответил пользователь
+	parent, err := uc.jobRepo.GetLatestByFileUniqueID(ctx, user.ID, fileUniqueID)
+	if err != nil {
+		uc.logger.Error("Failed to find job by file unique id",
+			"error", err,
+		)
+		return "", 0, fmt.Errorf("failed to find job by file unique id: %w", err)
+	}
+	if parent == nil {
+		return i18n.T(ctx, "trim.unknown_recording"), 0, nil
+	}
+
+	jobID, err := uc.audioProcessingUseCase.ProcessTrim(ctx, parent, messageID, fromSeconds, toSeconds)
+	switch {
+	case errors.Is(err, errTrimSourceMissing):
+		return i18n.T(ctx, "trim.source_missing"), 0, nil
+	case errors.Is(err, errTrimOutOfRange):
+		return i18n.T(ctx, "trim.out_of_range", textutil.FormatTimestamp(int(parent.Duration))), 0, nil
+	case err != nil:
+		return "", 0, err
+	}
+
+	// Формирование сообщения об успешном начале обработки
+	responseMessage := i18n.T(ctx, "trim.accepted", textutil.FormatTimestamp(fromSeconds), textutil.FormatTimestamp(toSeconds), parent.ID) +
+		"\n\n" + i18n.T(ctx, "accepted.job_id", jobID)
+
+	// Учет созданной задачи в статистике использования
+	uc.usageAnalyticsUseCase.Track(ctx, telegramID, entity.UsageEventJobCreated, usageSourceTrim)
+
+	// Логирование успешного начала обработки фрагмента
+	uc.logger.Info("Successfully started processing audio trim",
+		"telegram_id", telegramID,
+		"parent_job_id", parent.ID,
+		"job_id", jobID,
+	)
+
+	return responseMessage, jobID, nil
+}
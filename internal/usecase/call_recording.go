@@ -0,0 +1,307 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/config"
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
+)
+
+// callDirectionNames - идентификаторы сообщений с названиями направлений звонка на странице задачи в Notion
+var callDirectionNames = map[entity.CallDirection]string{
+	entity.CallDirectionIncoming: "call.direction.incoming",
+	entity.CallDirectionOutgoing: "call.direction.outgoing",
+}
+
+// CallRecordingUseCase представляет собой сценарий обработки записей звонков из вебхуков VoIP провайдеров.
+// Звонок относится к пользователю по номеру телефона из VOIP_PHONE_NUMBERS, а запись обрабатывается
+// как присланный пользователем аудиофайл; данные о звонке попадают на страницу задачи в Notion
+type CallRecordingUseCase struct {
+	userRepo               repository.UserRepository
+	callRepo               repository.CallRecordingRepository
+	callRecordingService   service.CallRecordingService
+	urlIngestionService    service.URLIngestionService
+	audioProcessingUseCase *AudioProcessingUseCase
+	usageAnalyticsUseCase  *UsageAnalyticsUseCase
+	languageUseCase        *InterfaceLanguageUseCase
+	messenger              service.MessengerService
+	phoneNumbers           map[string]int64
+	logger                 *logger.Logger
+}
+
+// NewCallRecordingUseCase создает новый сценарий обработки записей звонков.
+// phoneNumbers - Telegram ID пользователей по цифрам номеров телефонов и ключу config.PhoneNumberAny
+func NewCallRecordingUseCase(
+	userRepo repository.UserRepository,
+	callRepo repository.CallRecordingRepository,
+	callRecordingService service.CallRecordingService,
+	urlIngestionService service.URLIngestionService,
+	audioProcessingUseCase *AudioProcessingUseCase,
+	usageAnalyticsUseCase *UsageAnalyticsUseCase,
+	languageUseCase *InterfaceLanguageUseCase,
+	messenger service.MessengerService,
+	phoneNumbers map[string]int64,
+	logger *logger.Logger,
+) *CallRecordingUseCase {
+	return &CallRecordingUseCase{
+		userRepo:               userRepo,
+		callRepo:               callRepo,
+		callRecordingService:   callRecordingService,
+		urlIngestionService:    urlIngestionService,
+		audioProcessingUseCase: audioProcessingUseCase,
+		usageAnalyticsUseCase:  usageAnalyticsUseCase,
+		languageUseCase:        languageUseCase,
+		messenger:              messenger,
+		phoneNumbers:           phoneNumbers,
+		logger:                 logger,
+	}
+}
+
+// Ingest учитывает уведомление провайдера о звонке: сохраняет данные о звонке, а если запись готова -
+// загружает ее и создает задачу обработки. Повторное уведомление о той же записи задачу не создает.
+// Звонок, номера которого нет в списке, пропускается
+func (uc *CallRecordingUseCase) Ingest(ctx context.Context, event *entity.CallEvent) error {
+	// Логирование уведомления о звонке
+	uc.logger.Info("Ingesting VoIP call event",
+		"provider", event.Call.Provider,
+		"call_id", event.Call.CallID,
+		"has_recording", event.HasRecording(),
+	)
+
+	call, user, err := uc.saveCall(ctx, &event.Call)
+	if err != nil || call == nil {
+		return err
+	}
+	if !event.HasRecording() {
+		return nil
+	}
+
+	// Провайдеры повторяют уведомления, поэтому запись обрабатывается только по первому из них
+	claimed, err := uc.callRepo.ClaimRecording(ctx, call.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		uc.logger.Info("Call recording already received",
+			"call_id", call.CallID,
+		)
+		return nil
+	}
+	ctx = i18n.WithLanguage(ctx, uc.languageUseCase.ForUser(ctx, user))
+
+	jobID, err := uc.createJob(ctx, user, call, event)
+	if err != nil {
+		uc.logger.Error("Failed to process call recording",
+			"error", err,
+			"provider", call.Provider,
+			"call_id", call.CallID,
+		)
+		uc.notify(user.TelegramID, i18n.T(ctx, "call.failed", callTitle(ctx, call)))
+		return err
+	}
+
+	uc.notify(user.TelegramID, i18n.T(ctx, "call.received", callTitle(ctx, call), jobID, jobID))
+
+	// Логирование созданной задачи
+	uc.logger.Info("Call recording job created",
+		"call_id", call.CallID,
+		"user_id", user.ID,
+		"job_id", jobID,
+	)
+
+	return nil
+}
+
+// saveCall создает звонок или дополняет данные уже известного звонка. Возвращает сохраненный звонок и его
+// пользователя; звонок равен nil, если пользователь не найден или заблокирован
+func (uc *CallRecordingUseCase) saveCall(ctx context.Context, data *entity.CallRecording) (*entity.CallRecording, *entity.User, error) {
+	call, err := uc.callRepo.GetByCallID(ctx, data.Provider, data.CallID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if call != nil {
+		data.ID = call.ID
+		if err := uc.callRepo.UpdateMetadata(ctx, data); err != nil {
+			return nil, nil, err
+		}
+		mergeCallMetadata(call, data)
+
+		user, err := uc.userRepo.GetByID(ctx, call.UserID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get call user: %w", err)
+		}
+		if user.BannedAt != nil {
+			return nil, nil, nil
+		}
+		return call, user, nil
+	}
+
+	user := uc.callUser(ctx, data)
+	if user == nil {
+		return nil, nil, nil
+	}
+
+	call = &entity.CallRecording{}
+	*call = *data
+	call.UserID = user.ID
+	if err := uc.callRepo.Create(ctx, call); err != nil {
+		// Уведомления об одном звонке могут прийти одновременно: звонок уже создан параллельным уведомлением
+		existing, getErr := uc.callRepo.GetByCallID(ctx, data.Provider, data.CallID)
+		if getErr != nil || existing == nil {
+			return nil, nil, err
+		}
+		return existing, user, nil
+	}
+
+	return call, user, nil
+}
+
+// callUser возвращает пользователя звонка по номеру из списка: сначала по своему номеру пользователя
+// (номеру, на который звонили, или номеру исходящего звонка), затем по номеру собеседника и затем
+// пользователя для остальных номеров. Возвращает nil, если номера нет в списке, пользователь еще не писал боту
+// или заблокирован
+func (uc *CallRecordingUseCase) callUser(ctx context.Context, call *entity.CallRecording) *entity.User {
+	numbers := []string{call.Callee, call.Caller}
+	if call.Direction == entity.CallDirectionOutgoing {
+		numbers = []string{call.Caller, call.Callee}
+	}
+
+	telegramID, ok := int64(0), false
+	for _, number := range numbers {
+		if digits := config.PhoneDigits(number); digits != "" {
+			if telegramID, ok = uc.phoneNumbers[digits]; ok {
+				break
+			}
+		}
+	}
+	if !ok {
+		telegramID, ok = uc.phoneNumbers[config.PhoneNumberAny]
+	}
+	if !ok {
+		uc.logger.Warn("No user configured for call numbers",
+			"provider", call.Provider,
+			"call_id", call.CallID,
+		)
+		return nil
+	}
+
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil || user == nil {
+		uc.logger.Warn("Call user not found: the user has not started the bot yet",
+			"error", err,
+			"telegram_id", telegramID,
+		)
+		return nil
+	}
+	if user.BannedAt != nil {
+		uc.logger.Warn("Skipping call of banned user",
+			"telegram_id", telegramID,
+		)
+		return nil
+	}
+
+	return user
+}
+
+// createJob загружает запись звонка и создает задачу ее обработки
+func (uc *CallRecordingUseCase) createJob(ctx context.Context, user *entity.User, call *entity.CallRecording, event *entity.CallEvent) (int64, error) {
+	if err := uc.callRecordingService.ResolveRecording(ctx, event); err != nil {
+		return 0, err
+	}
+
+	filePath, _, err := uc.urlIngestionService.Download(ctx, user.TelegramID, event.RecordingURL)
+	if errors.Is(err, service.ErrRemoteFileTooLarge) {
+		return 0, fmt.Errorf("call recording is larger than %d MB: %w", uc.urlIngestionService.MaxSize()>>20, err)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to download call recording: %w", err)
+	}
+
+	jobID, err := uc.audioProcessingUseCase.ProcessAudio(ctx, user.TelegramID, 0, filePath, callTitle(ctx, call), "", "")
+	if err != nil {
+		return 0, err
+	}
+
+	if err := uc.callRepo.SetJob(ctx, call.ID, jobID); err != nil {
+		return 0, err
+	}
+
+	// Учет созданной задачи в статистике использования
+	uc.usageAnalyticsUseCase.Track(ctx, user.TelegramID, entity.UsageEventJobCreated, usageSourceCall)
+
+	return jobID, nil
+}
+
+// notify отправляет пользователю сообщение о записи звонка; ошибку отправки только логируем
+func (uc *CallRecordingUseCase) notify(telegramID int64, text string) {
+	if _, err := uc.messenger.SendMessage(telegramID, text); err != nil {
+		uc.logger.Warn("Failed to send call recording message",
+			"error", err,
+			"telegram_id", telegramID,
+		)
+	}
+}
+
+// mergeCallMetadata дополняет звонок непустыми полями data, как UpdateMetadata в хранилище
+func mergeCallMetadata(call, data *entity.CallRecording) {
+	if data.Direction != "" {
+		call.Direction = data.Direction
+	}
+	if data.Caller != "" {
+		call.Caller = data.Caller
+	}
+	if data.Callee != "" {
+		call.Callee = data.Callee
+	}
+	if data.StartedAt != nil {
+		call.StartedAt = data.StartedAt
+	}
+	if data.Duration > 0 {
+		call.Duration = data.Duration
+	}
+}
+
+// callTitle возвращает название задачи по записи звонка, например «Звонок +7495... → +7999...»
+func callTitle(ctx context.Context, call *entity.CallRecording) string {
+	if call.Caller == "" && call.Callee == "" {
+		return i18n.T(ctx, "call.title")
+	}
+	return i18n.T(ctx, "call.title.numbers", callNumber(call.Caller), callNumber(call.Callee))
+}
+
+// formatCallSection формирует раздел страницы задачи в Notion с данными о звонке
+func formatCallSection(ctx context.Context, call *entity.CallRecording) string {
+	lines := []string{"## " + i18n.T(ctx, "call.title"), ""}
+	if name, ok := callDirectionNames[call.Direction]; ok {
+		lines = append(lines, "- "+i18n.T(ctx, "notion.page.call.direction", i18n.T(ctx, name)))
+	}
+	lines = append(lines,
+		"- "+i18n.T(ctx, "notion.page.call.caller", callNumber(call.Caller)),
+		"- "+i18n.T(ctx, "notion.page.call.callee", callNumber(call.Callee)),
+	)
+	if call.StartedAt != nil {
+		lines = append(lines, "- "+i18n.T(ctx, "notion.page.call.started", call.StartedAt.Format("02.01.2006 15:04")))
+	}
+	if call.Duration > 0 {
+		lines = append(lines, "- "+i18n.T(ctx, "notion.page.call.duration", textutil.FormatTimestamp(call.Duration)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// callNumber возвращает номер участника звонка или прочерк, если провайдер его не сообщил
+func callNumber(number string) string {
+	if number == "" {
+		return "—"
+	}
+	return number
+}
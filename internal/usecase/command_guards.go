@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+)
+
+// SetupActionNotion - действие кнопки настройки интеграции с Notion
+const SetupActionNotion = "notion"
+
+// CheckRequirements проверяет предусловия команды по порядку и возвращает стандартный ответ
+// для первого невыполненного; nil означает, что команду можно выполнять
+func (uc *TelegramHandlersUseCase) CheckRequirements(ctx context.Context, telegramID int64, requirements ...entity.CommandRequirement) (*entity.GuardPrompt, error) {
+	if len(requirements) == 0 {
+		return nil, nil
+	}
+
+	// Пользователь может быть еще не зарегистрирован: тогда у него нет ни Notion, ни задач
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		user = nil
+	}
+
+	for _, requirement := range requirements {
+		prompt, err := uc.checkRequirement(ctx, telegramID, user, requirement)
+		if err != nil {
+			return nil, err
+		}
+		if prompt != nil {
+			uc.logger.Info("Command requirement not met",
+				"telegram_id", telegramID,
+				"requirement", requirement,
+			)
+			return prompt, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// checkRequirement проверяет одно предусловие
+func (uc *TelegramHandlersUseCase) checkRequirement(ctx context.Context, telegramID int64, user *entity.User, requirement entity.CommandRequirement) (*entity.GuardPrompt, error) {
+	switch requirement {
+	case entity.CommandRequirementNotion:
+		if user != nil && user.NotionToken != "" && user.NotionDatabaseID != "" {
+			return nil, nil
+		}
+		return &entity.GuardPrompt{
+			Text:        i18n.T(ctx, "guard.notion"),
+			SetupLabel:  i18n.T(ctx, "guard.notion_setup"),
+			SetupAction: SetupActionNotion,
+		}, nil
+
+	case entity.CommandRequirementAdmin:
+		if uc.admins.IsAdmin(telegramID) {
+			return nil, nil
+		}
+		return &entity.GuardPrompt{Text: i18n.T(ctx, "guard.admin")}, nil
+
+	case entity.CommandRequirementQuota:
+		if uc.dailyJobQuota <= 0 || user == nil {
+			return nil, nil
+		}
+		now := time.Now()
+		dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		stats, err := uc.jobRepo.GetUserStats(ctx, user.ID, dayStart)
+		if err != nil {
+			uc.logger.Error("Failed to get user stats",
+				"error", err,
+			)
+			return nil, fmt.Errorf("failed to get user stats: %w", err)
+		}
+		if stats.JobsSince < int64(uc.dailyJobQuota) {
+			return nil, nil
+		}
+		return &entity.GuardPrompt{
+			Text: i18n.T(ctx, "guard.quota", stats.JobsSince, uc.dailyJobQuota),
+		}, nil
+
+	case entity.CommandRequirementService:
+		if uc.maintenanceUseCase.Current() == nil {
+			return nil, nil
+		}
+		return &entity.GuardPrompt{Text: uc.maintenanceUseCase.Notice(ctx)}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown command requirement: %s", requirement)
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// diagnosticsCheckTimeout - сколько ждать одну проверку самодиагностики. Зависшая зависимость
+// отмечается ошибкой по таймауту, а не задерживает весь отчет
+const diagnosticsCheckTimeout = 10 * time.Second
+
+// maxDiagnosticsErrorLength - ограничение длины ошибки проверки в отчете
+const maxDiagnosticsErrorLength = 300
+
+// diagnosticsResult - результат одной проверки самодиагностики
+type diagnosticsResult struct {
+	name    string
+	detail  string
+	err     error
+	latency time.Duration
+}
+
+// DiagnosticsUseCase представляет собой сценарий самодиагностики для команды /admin doctor: одновременно
+// проверяет зависимости приложения (базы данных, FFmpeg, ключи провайдеров, воркер очереди, место на диске)
+// и собирает отчет с задержкой каждой проверки, чтобы при сбое сразу было видно, что сломалось
+type DiagnosticsUseCase struct {
+	checks []service.HealthCheck
+	logger *logger.Logger
+}
+
+// NewDiagnosticsUseCase создает новый сценарий самодиагностики. Проверки выводятся в отчете в порядке checks
+func NewDiagnosticsUseCase(checks []service.HealthCheck, logger *logger.Logger) *DiagnosticsUseCase {
+	return &DiagnosticsUseCase{
+		checks: checks,
+		logger: logger,
+	}
+}
+
+// Run выполняет все проверки одновременно и возвращает отчет
+func (uc *DiagnosticsUseCase) Run(ctx context.Context) string {
+	start := time.Now()
+
+	results := make([]diagnosticsResult, len(uc.checks))
+	var wg sync.WaitGroup
+	for i, check := range uc.checks {
+		wg.Add(1)
+		go func(i int, check service.HealthCheck) {
+			defer wg.Done()
+			results[i] = uc.runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.err != nil {
+			failed++
+			uc.logger.Warn("Health check failed",
+				"check", result.name,
+				"error", result.err,
+				"latency", result.latency,
+			)
+		}
+	}
+
+	// Логирование результата самодиагностики
+	uc.logger.Info("Diagnostics completed",
+		"checks", len(results),
+		"failed", failed,
+		"duration", time.Since(start),
+	)
+
+	return formatDiagnostics(ctx, results, failed, time.Since(start))
+}
+
+// runCheck выполняет одну проверку с ограничением по времени. Паника проверки считается ее ошибкой
+func (uc *DiagnosticsUseCase) runCheck(ctx context.Context, check service.HealthCheck) (result diagnosticsResult) {
+	result.name = i18n.T(ctx, check.Name())
+
+	ctx, cancel := context.WithTimeout(ctx, diagnosticsCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		result.latency = time.Since(start)
+		if r := recover(); r != nil {
+			result.err = fmt.Errorf("health check panicked: %v", r)
+		}
+	}()
+
+	result.detail, result.err = check.Check(ctx)
+	return result
+}
+
+// formatDiagnostics формирует отчет самодиагностики
+func formatDiagnostics(ctx context.Context, results []diagnosticsResult, failed int, duration time.Duration) string {
+	if len(results) == 0 {
+		return i18n.T(ctx, "health.report.empty")
+	}
+
+	var builder strings.Builder
+	if failed == 0 {
+		builder.WriteString(i18n.T(ctx, "health.report.ok", len(results)))
+	} else {
+		builder.WriteString(i18n.T(ctx, "health.report.failed", failed, len(results)))
+	}
+	builder.WriteString(i18n.T(ctx, "health.report.duration", duration.Milliseconds()) + "\n")
+
+	for _, result := range results {
+		mark := "✅"
+		if result.err != nil {
+			mark = "❌"
+		}
+		builder.WriteString(fmt.Sprintf("\n%s %s — %s", mark, result.name, i18n.T(ctx, "health.report.latency", result.latency.Milliseconds())))
+		if result.detail != "" {
+			builder.WriteString(" — " + result.detail)
+		}
+		if result.err != nil {
+			builder.WriteString("\n   " + truncateRunes(result.err.Error(), maxDiagnosticsErrorLength, false))
+		}
+	}
+
+	return builder.String()
+}
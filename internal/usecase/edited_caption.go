@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+)
+
+// HandleEditedCaption обрабатывает исправленную подпись к записи: пока краткое содержание задачи, созданной
+// по этому сообщению, не составлено, новая подпись заменяет указания к конспекту. Возвращает ответ
+// или пустую строку, если исправлять нечего
+func (uc *TelegramHandlersUseCase) HandleEditedCaption(ctx context.Context, telegramID int64, messageID int, caption string) (string, error) {
+	// Логирование исправления подписи
+	uc.logger.Info("Handling edited caption",
+		"telegram_id", telegramID,
+		"message_id", messageID,
+	)
+
+	// Сообщения незнакомых боту пользователей не могут быть записями задач
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		return "", nil
+	}
+
+	updated, err := uc.jobRepo.SetInstructionsBySourceMessage(ctx, user.ID, messageID, strings.TrimSpace(caption))
+	if err != nil {
+		uc.logger.Error("Failed to update job instructions",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to update job instructions: %w", err)
+	}
+	if !updated {
+		return "", nil
+	}
+
+	if strings.TrimSpace(caption) == "" {
+		return i18n.T(ctx, "caption.removed"), nil
+	}
+	return i18n.T(ctx, "caption.updated"), nil
+}
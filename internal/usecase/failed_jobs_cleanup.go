@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Параметры ежедневного разбора задач с ошибками
+const (
+	// failedJobsCleanupCheckInterval - как часто проверяется, у кого из пользователей наступило время разбора
+	failedJobsCleanupCheckInterval = time.Hour
+	// failedJobsCleanupWindow - разбираются только задачи, упавшие за это время: старые ошибки пользователь уже видел
+	failedJobsCleanupWindow = 7 * 24 * time.Hour
+	// failedJobsCleanupBatchSize - сколько пользователей разбирается за одну проверку
+	failedJobsCleanupBatchSize = 100
+	// failedJobsCleanupJobsLimit - сколько задач пользователя разбирается за один раз
+	failedJobsCleanupJobsLimit = 50
+	// failedJobsCleanupListLimit - сколько неисправимых задач перечисляется в сводке
+	failedJobsCleanupListLimit = 10
+)
+
+// failedJobAdvice - идентификаторы советов пользователю для задачи, не обработанной и после перезапуска, по категории ошибки
+var failedJobAdvice = map[entity.FailureCategory]string{
+	entity.FailureCategoryTimeout:      "failed_jobs.advice.too_long",
+	entity.FailureCategoryBudget:       "failed_jobs.advice.too_long",
+	entity.FailureCategoryRateLimit:    "failed_jobs.advice.rate_limit",
+	entity.FailureCategoryNetwork:      "failed_jobs.advice.network",
+	entity.FailureCategoryAuth:         "failed_jobs.advice.auth",
+	entity.FailureCategoryInvalidInput: "failed_jobs.advice.invalid_input",
+}
+
+// failedJobSourceMissingAdvice - идентификатор совета для задачи, которую нельзя перезапустить: исходный файл уже удален
+const failedJobSourceMissingAdvice = "failed_jobs.advice.source_missing"
+
+// failedJobReport представляет собой строку сводки о задаче, не обработанной и после перезапуска
+type failedJobReport struct {
+	job    *entity.Job
+	advice string
+}
+
+// FailedJobsCleanupUseCase представляет собой сценарий ежедневного разбора задач с ошибками: у пользователей,
+// включивших его в /settings, каждая упавшая задача один раз перезапускается, а о задачах, которые не удалось
+// обработать и после этого, приходит короткая сводка с советами
+type FailedJobsCleanupUseCase struct {
+	userRepo               repository.UserRepository
+	jobRepo                repository.JobRepository
+	settingsRepo           repository.UserSettingsRepository
+	audioProcessingUseCase *AudioProcessingUseCase
+	languageUseCase        *InterfaceLanguageUseCase
+	messenger              service.MessengerService
+	interval               time.Duration
+	logger                 *logger.Logger
+}
+
+// NewFailedJobsCleanupUseCase создает новый сценарий разбора задач с ошибками.
+// interval - период разбора для одного пользователя (<= 0 отключает разбор)
+func NewFailedJobsCleanupUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	settingsRepo repository.UserSettingsRepository,
+	audioProcessingUseCase *AudioProcessingUseCase,
+	languageUseCase *InterfaceLanguageUseCase,
+	messenger service.MessengerService,
+	interval time.Duration,
+	logger *logger.Logger,
+) *FailedJobsCleanupUseCase {
+	return &FailedJobsCleanupUseCase{
+		userRepo:               userRepo,
+		jobRepo:                jobRepo,
+		settingsRepo:           settingsRepo,
+		audioProcessingUseCase: audioProcessingUseCase,
+		languageUseCase:        languageUseCase,
+		messenger:              messenger,
+		interval:               interval,
+		logger:                 logger,
+	}
+}
+
+// RunSchedule разбирает задачи с ошибками по расписанию до отмены контекста
+func (uc *FailedJobsCleanupUseCase) RunSchedule(ctx context.Context) {
+	if uc.interval <= 0 {
+		return
+	}
+
+	checkInterval := failedJobsCleanupCheckInterval
+	if uc.interval < checkInterval {
+		checkInterval = uc.interval
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			uc.cleanupDue(ctx, now)
+		}
+	}
+}
+
+// cleanupDue разбирает задачи пользователей, у которых разбора не было дольше периода;
+// ошибка одного пользователя не мешает остальным
+func (uc *FailedJobsCleanupUseCase) cleanupDue(ctx context.Context, now time.Time) {
+	before := now.Add(-uc.interval)
+
+	due, err := uc.settingsRepo.GetForFailedJobsCleanup(ctx, before, failedJobsCleanupBatchSize)
+	if err != nil {
+		uc.logger.Error("Failed to get users for failed jobs cleanup",
+			"error", err,
+		)
+		return
+	}
+
+	for _, settings := range due {
+		// Разбор выполняет только тот экземпляр приложения, который первым отметил пользователя
+		claimed, err := uc.settingsRepo.ClaimFailedJobsCleanup(ctx, settings.UserID, before, now)
+		if err != nil {
+			uc.logger.Error("Failed to claim failed jobs cleanup",
+				"error", err,
+				"user_id", settings.UserID,
+			)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := uc.cleanupUser(ctx, settings, now); err != nil {
+			uc.logger.Error("Failed to clean up failed jobs",
+				"error", err,
+				"user_id", settings.UserID,
+			)
+		}
+	}
+}
+
+// cleanupUser перезапускает еще не перезапускавшиеся задачи пользователя с ошибкой и присылает сводку.
+// Задачи, перезапущенные прошлым разбором и снова упавшие, считаются неисправимыми и попадают в сводку один раз
+func (uc *FailedJobsCleanupUseCase) cleanupUser(ctx context.Context, settings *entity.UserSettings, now time.Time) error {
+	user, err := uc.userRepo.GetByID(ctx, settings.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	ctx = i18n.WithLanguage(ctx, uc.languageUseCase.ForUser(ctx, user))
+
+	jobs, err := uc.jobRepo.GetFailedForCleanup(ctx, user.ID, now.Add(-failedJobsCleanupWindow), failedJobsCleanupJobsLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get failed jobs: %w", err)
+	}
+
+	retried := 0
+	var failed []failedJobReport
+	for _, job := range jobs {
+		if job.CleanupRetriedAt != nil {
+			// FailedJobsCleanupAt - время прошлого разбора: отметка о новом уже сохранена, но в настройки не попала
+			if settings.FailedJobsCleanupAt != nil && !job.CleanupRetriedAt.Before(*settings.FailedJobsCleanupAt) {
+				failed = append(failed, failedJobReport{job: job, advice: adviseFailedJob(ctx, job)})
+			}
+			continue
+		}
+
+		marked, err := uc.jobRepo.MarkCleanupRetried(ctx, job.ID, now)
+		if err != nil {
+			uc.logger.Error("Failed to mark job cleanup retry",
+				"error", err,
+				"job_id", job.ID,
+			)
+			continue
+		}
+		if !marked {
+			continue
+		}
+
+		err = uc.audioProcessingUseCase.Retry(ctx, job)
+		switch {
+		case errors.Is(err, errRetrySourceMissing):
+			failed = append(failed, failedJobReport{job: job, advice: i18n.T(ctx, failedJobSourceMissingAdvice)})
+		case err != nil:
+			uc.logger.Error("Failed to retry job",
+				"error", err,
+				"job_id", job.ID,
+			)
+			failed = append(failed, failedJobReport{job: job, advice: adviseFailedJob(ctx, job)})
+		default:
+			retried++
+		}
+	}
+
+	// Логирование результатов разбора
+	uc.logger.Info("Failed jobs cleaned up",
+		"user_id", user.ID,
+		"retried", retried,
+		"failed", len(failed),
+	)
+
+	if retried == 0 && len(failed) == 0 {
+		return nil
+	}
+
+	if _, err := uc.messenger.SendMessage(user.TelegramID, formatFailedJobsCleanup(ctx, retried, failed)); err != nil {
+		return fmt.Errorf("failed to send failed jobs cleanup report: %w", err)
+	}
+
+	return nil
+}
+
+// adviseFailedJob возвращает совет для задачи, не обработанной и после перезапуска
+func adviseFailedJob(ctx context.Context, job *entity.Job) string {
+	// Упала суммаризация: транскрипция уже есть, и ее можно получить без повторной обработки
+	if job.Transcription != "" {
+		return i18n.T(ctx, "failed_jobs.advice.transcript", job.ID)
+	}
+
+	if advice, ok := failedJobAdvice[classifyFailure(job.ErrorMessage)]; ok {
+		return i18n.T(ctx, advice)
+	}
+	return i18n.T(ctx, "failed_jobs.advice.default")
+}
+
+// formatFailedJobsCleanup формирует сводку разбора задач с ошибками
+func formatFailedJobsCleanup(ctx context.Context, retried int, failed []failedJobReport) string {
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, "failed_jobs.header") + "\n")
+
+	if retried > 0 {
+		builder.WriteString("\n" + i18n.T(ctx, "failed_jobs.retried", retried) + "\n")
+	}
+
+	if len(failed) > 0 {
+		builder.WriteString("\n" + i18n.T(ctx, "failed_jobs.failed") + "\n")
+		for i, report := range failed {
+			if i == failedJobsCleanupListLimit {
+				builder.WriteString(i18n.T(ctx, "failed_jobs.more", len(failed)-i) + "\n")
+				break
+			}
+			name := jobTitle(ctx, report.job)
+			if name == "" {
+				name = i18n.T(ctx, "failed_jobs.untitled")
+			}
+			builder.WriteString(fmt.Sprintf("• %d. %s: %s\n", report.job.ID, name, report.advice))
+		}
+	}
+
+	builder.WriteString("\n" + i18n.T(ctx, "failed_jobs.footer"))
+	return builder.String()
+}
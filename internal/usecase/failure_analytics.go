@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
+)
+
+// Источники ошибок, не связанные с вызовами внешних сервисов
+const (
+	failureProviderFFmpeg   = "ffmpeg"   // Предобработка аудио
+	failureProviderInternal = "internal" // Собственный код и база данных
+)
+
+// failureCategoryMarkers - признаки категорий ошибок в тексте ошибки. Категории проверяются по порядку,
+// поэтому более частные признаки идут раньше общих
+var failureCategoryMarkers = []struct {
+	category entity.FailureCategory
+	markers  []string
+}{
+	{entity.FailureCategoryBudget, []string{"cost ceiling", "no summarization model fits"}},
+	{entity.FailureCategoryTimeout, []string{"context deadline exceeded", "timeout", "timed out"}},
+	{entity.FailureCategoryRateLimit, []string{"429", "rate limit", "too many requests"}},
+	{entity.FailureCategoryAuth, []string{"401", "403", "unauthorized", "forbidden", "invalid api key", "permission"}},
+	{entity.FailureCategoryNetwork, []string{"connection refused", "connection reset", "no such host", "eof", "tls"}},
+	{entity.FailureCategoryInvalidInput, []string{"invalid", "bad request", "not found in job payload", "unknown audio profile", "audio is empty"}},
+}
+
+// ffmpegFailureMarkers - признаки ошибки предобработки аудио в тексте ошибки
+var ffmpegFailureMarkers = []string{"failed to process audio", "failed to apply audio", "failed to convert"}
+
+// FailureAnalyticsUseCase представляет собой сценарий сбора дневных сводок ошибок обработки задач
+// по этапу, внешнему сервису и категории, чтобы операторы видели, откуда приходят ошибки
+type FailureAnalyticsUseCase struct {
+	jobStageRepo repository.JobStageRepository
+	rollupRepo   repository.FailureRollupRepository
+	interval     time.Duration
+	logger       *logger.Logger
+
+	running sync.Mutex
+}
+
+// NewFailureAnalyticsUseCase создает новый сценарий сводок ошибок.
+// interval <= 0 отключает пересчет по расписанию
+func NewFailureAnalyticsUseCase(
+	jobStageRepo repository.JobStageRepository,
+	rollupRepo repository.FailureRollupRepository,
+	interval time.Duration,
+	logger *logger.Logger,
+) *FailureAnalyticsUseCase {
+	return &FailureAnalyticsUseCase{
+		jobStageRepo: jobStageRepo,
+		rollupRepo:   rollupRepo,
+		interval:     interval,
+		logger:       logger,
+	}
+}
+
+// failureDay возвращает начало дня (UTC), к которому относится момент времени
+func failureDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// classifyFailure определяет категорию ошибки по ее тексту
+func classifyFailure(errorMessage string) entity.FailureCategory {
+	message := strings.ToLower(errorMessage)
+	for _, rule := range failureCategoryMarkers {
+		for _, marker := range rule.markers {
+			if strings.Contains(message, marker) {
+				return rule.category
+			}
+		}
+	}
+	return entity.FailureCategoryOther
+}
+
+// failureProvider определяет источник ошибки этапа: последний неудачный внешний вызов,
+// предобработку аудио или собственный код
+func failureProvider(stage *entity.JobStage) string {
+	for i := len(stage.ExternalCalls) - 1; i >= 0; i-- {
+		if stage.ExternalCalls[i].Status == metrics.StatusError {
+			return stage.ExternalCalls[i].Provider
+		}
+	}
+
+	message := strings.ToLower(stage.ErrorMessage)
+	for _, marker := range ffmpegFailureMarkers {
+		if strings.Contains(message, marker) {
+			return failureProviderFFmpeg
+		}
+	}
+	return failureProviderInternal
+}
+
+// RollupDay пересчитывает сводку ошибок за день
+func (uc *FailureAnalyticsUseCase) RollupDay(ctx context.Context, day time.Time) error {
+	day = failureDay(day)
+
+	stages, err := uc.jobStageRepo.GetFailed(ctx, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		uc.logger.Error("Failed to get failed job stages",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get failed job stages: %w", err)
+	}
+
+	// Группировка ошибок по этапу, источнику и категории
+	type rollupKey struct {
+		stage    entity.JobStageName
+		provider string
+		category entity.FailureCategory
+	}
+	counts := make(map[rollupKey]*entity.FailureRollup)
+	rollups := make([]*entity.FailureRollup, 0)
+	for _, stage := range stages {
+		key := rollupKey{
+			stage:    stage.Stage,
+			provider: failureProvider(stage),
+			category: classifyFailure(stage.ErrorMessage),
+		}
+		rollup, ok := counts[key]
+		if !ok {
+			rollup = &entity.FailureRollup{
+				Day:      day,
+				Stage:    key.stage,
+				Provider: key.provider,
+				Category: key.category,
+			}
+			counts[key] = rollup
+			rollups = append(rollups, rollup)
+		}
+		rollup.Failures++
+	}
+
+	err = uc.rollupRepo.ReplaceDay(ctx, day, rollups)
+	if err != nil {
+		uc.logger.Error("Failed to save failure rollups",
+			"error", err,
+		)
+		return fmt.Errorf("failed to save failure rollups: %w", err)
+	}
+
+	uc.logger.Debug("Failure rollup updated",
+		"day", day.Format(time.DateOnly),
+		"failures", len(stages),
+		"groups", len(rollups),
+	)
+
+	return nil
+}
+
+// Report возвращает сводки ошибок за последние days дней, включая текущий.
+// Сводка за текущий день перед этим пересчитывается, чтобы отчет не отставал от расписания
+func (uc *FailureAnalyticsUseCase) Report(ctx context.Context, days int) ([]*entity.FailureRollup, error) {
+	now := time.Now()
+	if err := uc.refresh(ctx, now); err != nil {
+		return nil, err
+	}
+
+	rollups, err := uc.rollupRepo.GetSince(ctx, failureDay(now).AddDate(0, 0, 1-days))
+	if err != nil {
+		uc.logger.Error("Failed to get failure rollups",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get failure rollups: %w", err)
+	}
+
+	return rollups, nil
+}
+
+// refresh пересчитывает сводки за текущий и предыдущий день: этапы, завершившиеся
+// незадолго до полуночи, попадают в сводку предыдущего дня
+func (uc *FailureAnalyticsUseCase) refresh(ctx context.Context, now time.Time) error {
+	uc.running.Lock()
+	defer uc.running.Unlock()
+
+	today := failureDay(now)
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		if err := uc.RollupDay(ctx, day); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunSchedule пересчитывает сводки ошибок по расписанию до отмены контекста
+func (uc *FailureAnalyticsUseCase) RunSchedule(ctx context.Context) {
+	if uc.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(uc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := uc.refresh(ctx, now); err != nil {
+				uc.logger.Error("Scheduled failure rollup failed",
+					"error", err,
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Действия команды /notion в группе
+const (
+	groupNotionLink   = "link"
+	groupNotionUnlink = "unlink"
+)
+
+// GroupAdminOnlyMessage - идентификатор ответа участнику группы, который пытается изменить ее настройки
+const GroupAdminOnlyMessage = "group.admin_only"
+
+// GroupChatUseCase представляет собой сценарий работы бота в группах. Группа хранится как отдельный
+// пользователь с ID чата: записи участников становятся задачами группы, а результаты сохраняются
+// в общую базу Notion, которую подключает администратор группы
+type GroupChatUseCase struct {
+	userRepo repository.UserRepository
+	logger   *logger.Logger
+}
+
+// NewGroupChatUseCase создает новый сценарий работы бота в группах
+func NewGroupChatUseCase(
+	userRepo repository.UserRepository,
+	logger *logger.Logger,
+) *GroupChatUseCase {
+	return &GroupChatUseCase{
+		userRepo: userRepo,
+		logger:   logger,
+	}
+}
+
+// HandleAdded регистрирует группу, в которую добавили бота, и возвращает приветствие для участников
+func (uc *GroupChatUseCase) HandleAdded(ctx context.Context, chatID int64, chatType entity.ChatType, title string) (string, error) {
+	uc.logger.Info("Bot added to group",
+		"chat_id", chatID,
+		"chat_type", chatType,
+	)
+
+	if _, err := uc.Register(ctx, chatID, chatType, title); err != nil {
+		return "", err
+	}
+	return i18n.T(ctx, "group.welcome"), nil
+}
+
+// Register создает группу с ID чата chatID или обновляет ее тип и название. Настройки и задачи
+// группы сохраняются, если бота удалили из нее и добавили снова
+func (uc *GroupChatUseCase) Register(ctx context.Context, chatID int64, chatType entity.ChatType, title string) (*entity.User, error) {
+	group, err := uc.userRepo.GetByTelegramID(ctx, chatID)
+	if err != nil || group == nil {
+		group = &entity.User{
+			TelegramID: chatID,
+			FirstName:  title,
+			ChatType:   chatType,
+		}
+		if err := uc.userRepo.Create(ctx, group); err != nil {
+			uc.logger.Error("Failed to create group",
+				"error", err,
+				"chat_id", chatID,
+			)
+			return nil, fmt.Errorf("failed to create group: %w", err)
+		}
+		return group, nil
+	}
+
+	if group.ChatType == chatType && group.FirstName == title {
+		return group, nil
+	}
+	group.ChatType = chatType
+	group.FirstName = title
+	if err := uc.userRepo.Update(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to update group: %w", err)
+	}
+	return group, nil
+}
+
+// Migrate переносит настройки и задачи группы на новый ID чата, когда группа становится супергруппой
+func (uc *GroupChatUseCase) Migrate(ctx context.Context, fromChatID int64, toChatID int64) error {
+	group, err := uc.userRepo.GetByTelegramID(ctx, fromChatID)
+	if err != nil || group == nil {
+		// Группа без задач и настроек зарегистрируется заново при первом сообщении
+		return nil
+	}
+
+	group.TelegramID = toChatID
+	group.ChatType = entity.ChatTypeSupergroup
+	if err := uc.userRepo.Update(ctx, group); err != nil {
+		uc.logger.Error("Failed to migrate group",
+			"error", err,
+			"from_chat_id", fromChatID,
+			"to_chat_id", toChatID,
+		)
+		return fmt.Errorf("failed to migrate group: %w", err)
+	}
+
+	uc.logger.Info("Group migrated to supergroup",
+		"user_id", group.ID,
+		"from_chat_id", fromChatID,
+		"to_chat_id", toChatID,
+	)
+	return nil
+}
+
+// HandleNotion обрабатывает команду /notion в группе. Токен Notion нельзя отправлять в группу, поэтому
+// администратор подключает Notion в личном чате с ботом, а в группе только привязывает свою базу.
+// isAdmin - является ли отправитель команды администратором группы
+func (uc *GroupChatUseCase) HandleNotion(ctx context.Context, chatID int64, fromID int64, isAdmin bool, args string) (string, error) {
+	uc.logger.Info("Handling /notion command in group",
+		"chat_id", chatID,
+		"telegram_id", fromID,
+	)
+
+	group, err := uc.userRepo.GetByTelegramID(ctx, chatID)
+	if err != nil {
+		uc.logger.Error("Failed to get group",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get group: %w", err)
+	}
+
+	action := strings.ToLower(strings.TrimSpace(args))
+	if action == "" {
+		if group.NotionDatabaseID == "" {
+			return i18n.T(ctx, "group.notion.not_connected") + "\n\n" + i18n.T(ctx, "group.notion.usage") + "\n\n" + i18n.T(ctx, GroupAdminOnlyMessage), nil
+		}
+		return formatNotionLinks(ctx, group), nil
+	}
+
+	if action != groupNotionLink && action != groupNotionUnlink {
+		// Вероятнее всего, это токен интеграции: его видят все участники группы
+		return i18n.T(ctx, "group.notion.token"), nil
+	}
+	if !isAdmin {
+		return i18n.T(ctx, GroupAdminOnlyMessage), nil
+	}
+
+	if action == groupNotionUnlink {
+		group.NotionToken = ""
+		group.NotionDatabaseID = ""
+		group.NotionHubPageID = ""
+		if err := uc.userRepo.Update(ctx, group); err != nil {
+			return "", fmt.Errorf("failed to update group: %w", err)
+		}
+		return i18n.T(ctx, "group.notion.unlinked"), nil
+	}
+
+	admin, err := uc.userRepo.GetByTelegramID(ctx, fromID)
+	if err != nil || admin == nil || admin.NotionToken == "" || admin.NotionDatabaseID == "" {
+		return i18n.T(ctx, "group.notion.connect_first"), nil
+	}
+
+	group.NotionToken = admin.NotionToken
+	group.NotionDatabaseID = admin.NotionDatabaseID
+	group.NotionHubPageID = admin.NotionHubPageID
+	if err := uc.userRepo.Update(ctx, group); err != nil {
+		return "", fmt.Errorf("failed to update group: %w", err)
+	}
+
+	uc.logger.Info("Linked Notion database to group",
+		"user_id", group.ID,
+		"admin_user_id", admin.ID,
+	)
+
+	return i18n.T(ctx, "group.notion.linked") + "\n\n" + formatNotionLinks(ctx, group), nil
+}
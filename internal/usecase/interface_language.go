@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// InterfaceLanguageUseCase представляет собой сценарий выбора языка ответов бота: язык, выбранный
+// пользователем в /settings, а если он не выбран - язык из профиля Telegram. Код языка Telegram
+// запоминается, чтобы уведомления о задачах приходили на том же языке
+type InterfaceLanguageUseCase struct {
+	userRepo            repository.UserRepository
+	userSettingsUseCase *UserSettingsUseCase
+	logger              *logger.Logger
+}
+
+// NewInterfaceLanguageUseCase создает новый сценарий выбора языка ответов бота
+func NewInterfaceLanguageUseCase(
+	userRepo repository.UserRepository,
+	userSettingsUseCase *UserSettingsUseCase,
+	logger *logger.Logger,
+) *InterfaceLanguageUseCase {
+	return &InterfaceLanguageUseCase{
+		userRepo:            userRepo,
+		userSettingsUseCase: userSettingsUseCase,
+		logger:              logger,
+	}
+}
+
+// Resolve возвращает язык ответов на сообщение в чате telegramID от отправителя с кодом языка Telegram
+// languageCode. Незарегистрированным пользователям бот отвечает на языке профиля Telegram
+func (uc *InterfaceLanguageUseCase) Resolve(ctx context.Context, telegramID int64, languageCode string) string {
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil || user == nil {
+		return i18n.Normalize(languageCode)
+	}
+
+	// В группе пишут разные люди: если язык группы не выбран, каждому отвечаем на языке его профиля
+	if user.IsGroup() {
+		if language := uc.userSettingsUseCase.GetOrDefault(ctx, user.ID).InterfaceLanguage; i18n.Supported(language) {
+			return language
+		}
+		return i18n.Normalize(languageCode)
+	}
+
+	if languageCode != "" && languageCode != user.LanguageCode {
+		user.LanguageCode = languageCode
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			uc.logger.Warn("Failed to save user language code",
+				"error", err,
+				"user_id", user.ID,
+			)
+		}
+	}
+
+	return uc.ForUser(ctx, user)
+}
+
+// ForUser возвращает язык ответов пользователю, например для уведомлений о задачах, которые приходят не в ответ на сообщение
+func (uc *InterfaceLanguageUseCase) ForUser(ctx context.Context, user *entity.User) string {
+	language := uc.userSettingsUseCase.GetOrDefault(ctx, user.ID).InterfaceLanguage
+	if i18n.Supported(language) {
+		return language
+	}
+	return i18n.Normalize(user.LanguageCode)
+}
+
+// ForUserID возвращает язык ответов пользователю с ID userID; если пользователь не найден - язык по умолчанию
+func (uc *InterfaceLanguageUseCase) ForUserID(ctx context.Context, userID int64) string {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return i18n.Default
+	}
+	return uc.ForUser(ctx, user)
+}
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+)
+
+// JobActionCallbackPrefix - префикс данных кнопок под уведомлением о завершении задачи.
+// Данные кнопки имеют вид job:<действие>:<ID задачи>
+const JobActionCallbackPrefix = "job"
+
+// Действия кнопок под уведомлением о завершении задачи. Кроме них кнопки вызывают действия,
+// которые выполняются в очереди (followUpSummary, followUpTranslate, followUpNotion, followUpTags, followUpListen)
+const (
+	jobActionTranscript  = "transcript"  // Прислать полную транскрипцию (в очереди, как followUpFullText)
+	jobActionResummarize = "resummarize" // Составить краткое содержание заново
+	jobActionDelete      = "delete"      // Удалить сообщение с результатом из чата
+	jobActionMerge       = "merge"       // Удалить повторно присланную запись, оставив задачу, повтором которой она считается
+	jobActionDistinct    = "distinct"    // Снять пометку повтора: записи разные
+)
+
+// jobFollowUpAnswers - идентификаторы всплывающих уведомлений о том, что действие с результатом поставлено в очередь
+var jobFollowUpAnswers = map[string]string{
+	followUpSummary:   "job_action.summary",
+	followUpTranslate: "job_action.translate",
+	followUpTags:      "job_action.tags",
+	followUpListen:    "job_action.listen",
+	followUpNotion:    "job_action.notion",
+}
+
+// jobResultButtons возвращает кнопки под уведомлением о завершении задачи
+func jobResultButtons(ctx context.Context, job *entity.Job) [][]entity.MessageButton {
+	data := func(action string) string {
+		return fmt.Sprintf("%s:%s:%d", JobActionCallbackPrefix, action, job.ID)
+	}
+
+	buttons := [][]entity.MessageButton{}
+	textRow := []entity.MessageButton{}
+	if job.Transcription != "" {
+		textRow = append(textRow, entity.MessageButton{Text: i18n.T(ctx, "job_action.button.transcript"), Data: data(jobActionTranscript)})
+	}
+	if job.Summary != "" {
+		textRow = append(textRow, entity.MessageButton{Text: i18n.T(ctx, "job_action.button.summary"), Data: data(followUpSummary)})
+		buttons = append(buttons, textRow, []entity.MessageButton{
+			{Text: i18n.T(ctx, "job_action.button.translate"), Data: data(followUpTranslate)},
+			{Text: i18n.T(ctx, "job_action.button.tags"), Data: data(followUpTags)},
+			{Text: i18n.T(ctx, "job_action.button.listen"), Data: data(followUpListen)},
+		})
+	} else if len(textRow) > 0 {
+		buttons = append(buttons, textRow)
+	}
+
+	repeatRow := []entity.MessageButton{}
+	if job.Transcription != "" {
+		repeatRow = append(repeatRow, entity.MessageButton{Text: i18n.T(ctx, "job_action.button.resummarize"), Data: data(jobActionResummarize)})
+	}
+	if job.Summary != "" {
+		repeatRow = append(repeatRow, entity.MessageButton{Text: i18n.T(ctx, "job_action.button.notion"), Data: data(followUpNotion)})
+	}
+	if len(repeatRow) > 0 {
+		buttons = append(buttons, repeatRow)
+	}
+
+	// Повторно присланную запись предлагается объединить с задачей, повтором которой она считается
+	if job.DuplicateOfJobID != 0 {
+		buttons = append(buttons, []entity.MessageButton{
+			{Text: i18n.T(ctx, "job_action.button.merge", job.DuplicateOfJobID), Data: data(jobActionMerge)},
+			{Text: i18n.T(ctx, "job_action.button.distinct"), Data: data(jobActionDistinct)},
+		})
+	}
+
+	lastRow := []entity.MessageButton{}
+	if job.NotionPageID != "" {
+		lastRow = append(lastRow, entity.MessageButton{Text: i18n.T(ctx, "job_action.button.open_notion"), URL: notionURL(job.NotionPageID)})
+	}
+	lastRow = append(lastRow, entity.MessageButton{Text: i18n.T(ctx, "job_action.button.delete"), Data: data(jobActionDelete)})
+
+	return append(buttons, lastRow)
+}
+
+// HandleJobAction обрабатывает нажатие кнопки под уведомлением о завершении задачи.
+// data - данные кнопки без префикса, messageID - сообщение, под которым нажата кнопка.
+// Возвращает текст всплывающего уведомления
+func (uc *TelegramHandlersUseCase) HandleJobAction(ctx context.Context, telegramID int64, chatID int64, messageID int, data string) (string, error) {
+	action, jobIDStr, _ := strings.Cut(data, ":")
+	jobID, err := strconv.ParseInt(jobIDStr, 10, 64)
+	if err != nil {
+		return i18n.T(ctx, "callback.invalid_button"), nil
+	}
+
+	// Логирование нажатия кнопки
+	uc.logger.Info("Handling job action",
+		"telegram_id", telegramID,
+		"job_id", jobID,
+		"action", action,
+	)
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Проверка, что задача принадлежит пользователю
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "callback.job_not_found"), nil
+	}
+
+	switch action {
+	case jobActionTranscript:
+		if job.Transcription == "" {
+			return i18n.T(ctx, "job_action.no_transcript"), nil
+		}
+		if err := uc.jobFollowUpUseCase.Enqueue(ctx, job, followUpFullText, messageID); err != nil {
+			return "", fmt.Errorf("failed to enqueue follow-up: %w", err)
+		}
+		return i18n.T(ctx, "job_action.transcript"), nil
+
+	case followUpSummary, followUpTranslate, followUpTags, followUpNotion, followUpListen:
+		if job.Summary == "" {
+			return i18n.T(ctx, "job_action.no_summary"), nil
+		}
+		if action == followUpNotion && (user.NotionToken == "" || user.NotionDatabaseID == "") {
+			return i18n.T(ctx, "job_action.notion_not_connected"), nil
+		}
+		if err := uc.jobFollowUpUseCase.Enqueue(ctx, job, action, messageID); err != nil {
+			return "", fmt.Errorf("failed to enqueue follow-up: %w", err)
+		}
+		return i18n.T(ctx, jobFollowUpAnswers[action]), nil
+
+	case jobActionResummarize:
+		if job.Transcription == "" {
+			return i18n.T(ctx, "job_action.no_transcript"), nil
+		}
+		if job.Status != entity.JobStatusCompleted {
+			return i18n.T(ctx, "job_action.processing"), nil
+		}
+		if err := uc.audioProcessingUseCase.Resummarize(ctx, job); err != nil {
+			return "", fmt.Errorf("failed to resummarize job: %w", err)
+		}
+		return i18n.T(ctx, "job_action.resummarize"), nil
+
+	case jobActionDelete:
+		// Удаляется только сообщение: результат остается доступен по /get
+		if err := uc.messenger.DeleteMessage(chatID, messageID); err != nil {
+			uc.logger.Warn("Failed to delete result message",
+				"error", err,
+				"job_id", jobID,
+			)
+			return i18n.T(ctx, "job_action.delete_failed"), nil
+		}
+		return i18n.T(ctx, "job_action.deleted", job.ID), nil
+
+	case jobActionMerge:
+		return uc.mergeDuplicateJob(ctx, job, chatID, messageID)
+
+	case jobActionDistinct:
+		if job.DuplicateOfJobID == 0 {
+			return i18n.T(ctx, "job_action.not_duplicate"), nil
+		}
+		if err := uc.jobRepo.SetDuplicateOf(ctx, job.ID, 0, 0); err != nil {
+			return "", fmt.Errorf("failed to clear duplicate job: %w", err)
+		}
+		return i18n.T(ctx, "job_action.distinct", job.DuplicateOfJobID, job.ID), nil
+
+	default:
+		return i18n.T(ctx, "callback.unknown_action"), nil
+	}
+}
+
+// mergeDuplicateJob объединяет повторно присланную запись с задачей, повтором которой она считается:
+// остается более ранняя задача, а повтор удаляется вместе с файлами, страницами в Notion и сообщением
+// с результатом. Страницы в Notion перемещаются в корзину, откуда их можно восстановить
+func (uc *TelegramHandlersUseCase) mergeDuplicateJob(ctx context.Context, job *entity.Job, chatID int64, messageID int) (string, error) {
+	if job.DuplicateOfJobID == 0 {
+		return i18n.T(ctx, "job_action.not_duplicate"), nil
+	}
+	if job.Status != entity.JobStatusCompleted && job.Status != entity.JobStatusFailed {
+		return i18n.T(ctx, "job_action.processing"), nil
+	}
+
+	// Оставшаяся задача должна существовать: иначе пользователь лишится обеих записей
+	original, err := uc.jobRepo.GetByID(ctx, job.DuplicateOfJobID)
+	if err != nil || original.UserID != job.UserID {
+		if err := uc.jobRepo.SetDuplicateOf(ctx, job.ID, 0, 0); err != nil {
+			uc.logger.Warn("Failed to clear duplicate job",
+				"error", err,
+				"job_id", job.ID,
+			)
+		}
+		return i18n.T(ctx, "job_action.merge_original_deleted", job.DuplicateOfJobID), nil
+	}
+
+	// Страницы в Notion убираются до удаления задачи: при ошибке Notion объединение можно повторить
+	if job.NotionPageID != "" || job.NotionUploadPageID != "" {
+		if err := uc.notionProcessingUseCase.ArchiveJobPages(ctx, job); err != nil {
+			uc.logger.Error("Failed to archive Notion page of duplicate job",
+				"error", err,
+				"job_id", job.ID,
+			)
+			return i18n.T(ctx, "job_action.merge_notion_failed"), nil
+		}
+	}
+
+	if err := uc.removeJob(ctx, job); err != nil {
+		return "", err
+	}
+
+	// Сообщение с результатом удаленной задачи больше не нужно; Telegram позволяет удалить его только 48 часов
+	if err := uc.messenger.DeleteMessage(chatID, messageID); err != nil {
+		uc.logger.Warn("Failed to delete result message of duplicate job",
+			"error", err,
+			"job_id", job.ID,
+		)
+	}
+
+	// Логирование объединения задач
+	uc.logger.Info("Duplicate job merged",
+		"job_id", job.ID,
+		"original_job_id", original.ID,
+	)
+
+	return i18n.T(ctx, "job_action.merged", original.ID), nil
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+)
+
+// deleteNotionArg - аргумент команды /delete, по которому страница задачи в Notion перемещается в корзину
+const deleteNotionArg = "notion"
+
+// HandleDelete обрабатывает команду /delete: удаляет задачу вместе с ее результатами, публичными ссылками
+// и файлом записи. С аргументом notion страница задачи перемещается в корзину Notion, откуда ее можно
+// восстановить. Задачу, которая еще обрабатывается, удалить нельзя: обработчик очереди сохранил бы
+// результаты уже удаленной задачи
+func (uc *TelegramHandlersUseCase) HandleDelete(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /delete
+	uc.logger.Info("Handling /delete command",
+		"telegram_id", telegramID,
+	)
+
+	// Разбор идентификатора задачи и флага очистки Notion
+	parts := strings.Fields(args)
+	if len(parts) == 0 || len(parts) > 2 {
+		return i18n.T(ctx, "delete.usage"), nil
+	}
+	jobID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return i18n.T(ctx, "delete.usage"), nil
+	}
+	archiveNotion := len(parts) == 2
+	if archiveNotion && strings.ToLower(parts[1]) != deleteNotionArg {
+		return i18n.T(ctx, "delete.usage"), nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Получение задачи; чужие задачи считаем несуществующими
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "job.not_found"), nil
+	}
+	if job.Status != entity.JobStatusCompleted && job.Status != entity.JobStatusFailed {
+		return i18n.T(ctx, "delete.in_progress"), nil
+	}
+
+	// Страница в Notion убирается до удаления задачи: при ошибке Notion задачу можно удалить повторно
+	notionArchived := false
+	if archiveNotion && (job.NotionPageID != "" || job.NotionUploadPageID != "") {
+		if err := uc.notionProcessingUseCase.ArchiveJobPages(ctx, job); err != nil {
+			uc.logger.Error("Failed to archive Notion page of deleted job",
+				"error", err,
+				"job_id", job.ID,
+			)
+			return i18n.T(ctx, "delete.notion_failed", job.ID), nil
+		}
+		notionArchived = true
+	}
+
+	if err := uc.removeJob(ctx, job); err != nil {
+		return "", err
+	}
+
+	// Логирование успешного удаления задачи
+	uc.logger.Info("Successfully deleted job",
+		"telegram_id", telegramID,
+		"job_id", job.ID,
+		"notion_archived", notionArchived,
+	)
+
+	switch {
+	case notionArchived:
+		return i18n.T(ctx, "delete.done_notion_archived", job.ID), nil
+	case job.NotionPageID != "":
+		return i18n.T(ctx, "delete.done_notion_kept", job.ID), nil
+	default:
+		return i18n.T(ctx, "delete.done", job.ID), nil
+	}
+}
+
+// removeJob удаляет задачу, а затем ее файлы записи: пока задача не удалена, ее можно перезапустить
+func (uc *TelegramHandlersUseCase) removeJob(ctx context.Context, job *entity.Job) error {
+	if err := uc.jobRepo.Delete(ctx, job.ID); err != nil {
+		uc.logger.Error("Failed to delete job",
+			"error", err,
+			"job_id", job.ID,
+		)
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
+
+	for _, path := range []string{job.AudioFilePath, job.ProcessedAudioPath} {
+		if path == "" {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			uc.logger.Warn("Failed to remove audio file of deleted job",
+				"error", err,
+				"job_id", job.ID,
+				"path", path,
+			)
+		}
+	}
+	return nil
+}
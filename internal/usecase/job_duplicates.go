@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Параметры поиска повторно присланных записей
+const (
+	// duplicateSignatureSize - число хеш-функций MinHash: от него зависит точность оценки совпадения
+	// (погрешность около 1/sqrt(duplicateSignatureSize)) и размер сигнатуры
+	duplicateSignatureSize = 64
+	// duplicateShingleWords - сколько слов подряд составляют один фрагмент сравнения
+	duplicateShingleWords = 2
+	// duplicateMinWords - транскрипции короче этого не сравниваются: короткие фразы вроде «проверка связи»
+	// совпадают у разных записей
+	duplicateMinWords = 20
+	// duplicateCandidateLimit - со сколькими последними задачами пользователя сравнивается транскрипция
+	duplicateCandidateLimit = 200
+)
+
+// timestampPattern - метки времени [мм:сс] и [чч:мм:сс] в транскрипциях с сегментами
+var timestampPattern = regexp.MustCompile(`\[\d{1,2}:\d{2}(?::\d{2})?\]`)
+
+// detectDuplicate сравнивает транскрипцию задачи с транскрипциями других задач пользователя за последнее время
+// и помечает задачу как повтор наиболее похожей, если совпадение не ниже порога. Одна и та же запись,
+// присланная голосовым сообщением и файлом, распознается с небольшими расхождениями, поэтому сравниваются
+// не файлы, а оценки сходства текстов по MinHash. Ошибки не прерывают обработку: задача просто не будет помечена
+func (uc *TranscriptionProcessingUseCase) detectDuplicate(ctx context.Context, jobID, userID int64, transcription string) {
+	if uc.duplicateThreshold <= 0 {
+		return
+	}
+
+	signature := transcriptSignature(transcription)
+	if signature == nil {
+		return
+	}
+	if err := uc.jobRepo.SetTranscriptSignature(ctx, jobID, encodeSignature(signature)); err != nil {
+		uc.logger.Warn("Failed to save transcript signature",
+			"error", err,
+			"job_id", jobID,
+		)
+		return
+	}
+
+	// Без срока ищем среди всех задач пользователя
+	var since time.Time
+	if uc.duplicateWindow > 0 {
+		since = time.Now().Add(-uc.duplicateWindow)
+	}
+	candidates, err := uc.jobRepo.GetTranscriptSignatures(ctx, userID, jobID, since, duplicateCandidateLimit)
+	if err != nil {
+		uc.logger.Warn("Failed to get transcript signatures",
+			"error", err,
+			"job_id", jobID,
+		)
+		return
+	}
+
+	var originalID int64
+	var best float64
+	for _, candidate := range candidates {
+		other := decodeSignature(candidate.TranscriptSignature)
+		if other == nil {
+			continue
+		}
+		if similarity := signatureSimilarity(signature, other); similarity > best {
+			originalID, best = candidate.ID, similarity
+		}
+	}
+	if originalID == 0 || best < uc.duplicateThreshold {
+		return
+	}
+
+	if err := uc.jobRepo.SetDuplicateOf(ctx, jobID, originalID, best); err != nil {
+		uc.logger.Warn("Failed to mark job as duplicate",
+			"error", err,
+			"job_id", jobID,
+		)
+		return
+	}
+
+	// Логирование найденного повтора
+	uc.logger.Info("Duplicate recording detected",
+		"job_id", jobID,
+		"original_job_id", originalID,
+		"similarity", best,
+	)
+}
+
+// transcriptSignature возвращает сигнатуру MinHash транскрипции по фрагментам из duplicateShingleWords слов
+// или nil, если транскрипция слишком короткая для сравнения. Регистр, пунктуация и метки времени
+// не учитываются: они отличаются у распознаваний одной записи в разных форматах
+func transcriptSignature(transcription string) []uint64 {
+	text := strings.ReplaceAll(strings.ToLower(timestampPattern.ReplaceAllString(transcription, " ")), "ё", "е")
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) < duplicateMinWords {
+		return nil
+	}
+
+	signature := make([]uint64, duplicateSignatureSize)
+	for i := range signature {
+		signature[i] = math.MaxUint64
+	}
+	for start := 0; start+duplicateShingleWords <= len(words); start++ {
+		hash := fnv.New64a()
+		hash.Write([]byte(strings.Join(words[start:start+duplicateShingleWords], " ")))
+		shingle := hash.Sum64()
+
+		// Хеш-функции MinHash получаются перемешиванием хеша фрагмента с номером функции
+		for i := range signature {
+			if value := mix64(shingle + uint64(i)*0x9e3779b97f4a7c15); value < signature[i] {
+				signature[i] = value
+			}
+		}
+	}
+	return signature
+}
+
+// mix64 перемешивает биты числа (финализатор SplitMix64)
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// signatureSimilarity оценивает долю общих фрагментов двух транскрипций (коэффициент Жаккара)
+// как долю совпадающих значений их сигнатур
+func signatureSimilarity(a, b []uint64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(a))
+}
+
+// encodeSignature упаковывает сигнатуру для хранения в базе данных
+func encodeSignature(signature []uint64) string {
+	buf := make([]byte, 8*len(signature))
+	for i, value := range signature {
+		binary.BigEndian.PutUint64(buf[8*i:], value)
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// decodeSignature распаковывает сохраненную сигнатуру; сигнатуру другого размера считает отсутствующей
+func decodeSignature(encoded string) []uint64 {
+	buf, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(buf) != 8*duplicateSignatureSize {
+		return nil
+	}
+	signature := make([]uint64, duplicateSignatureSize)
+	for i := range signature {
+		signature[i] = binary.BigEndian.Uint64(buf[8*i:])
+	}
+	return signature
+}
+
+// similarityPercent переводит долю совпадения в проценты для сообщений пользователю
+func similarityPercent(similarity float64) int {
+	return int(math.Round(similarity * 100))
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
+)
+
+// JobExportUseCase представляет собой сценарий выгрузки результата задачи в PDF командой /pdf
+type JobExportUseCase struct {
+	userRepo                repository.UserRepository
+	jobRepo                 repository.JobRepository
+	documentService         service.DocumentService
+	messageRetentionUseCase *MessageRetentionUseCase
+	logger                  *logger.Logger
+}
+
+// NewJobExportUseCase создает новый сценарий выгрузки результата задачи в PDF.
+// documentService равен nil, если путь к wkhtmltopdf не настроен: тогда команда /pdf отключена
+func NewJobExportUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	documentService service.DocumentService,
+	messageRetentionUseCase *MessageRetentionUseCase,
+	logger *logger.Logger,
+) *JobExportUseCase {
+	return &JobExportUseCase{
+		userRepo:                userRepo,
+		jobRepo:                 jobRepo,
+		documentService:         documentService,
+		messageRetentionUseCase: messageRetentionUseCase,
+		logger:                  logger,
+	}
+}
+
+// HandlePDF обрабатывает команду /pdf и присылает результат задачи документом PDF в ответ на сообщение
+// с командой. Ответ команды возвращается только для ошибок ввода
+func (uc *JobExportUseCase) HandlePDF(ctx context.Context, telegramID int64, messageID int, args string) (string, error) {
+	// Логирование начала обработки команды /pdf
+	uc.logger.Info("Handling /pdf command",
+		"telegram_id", telegramID,
+	)
+
+	if uc.documentService == nil {
+		return i18n.T(ctx, "pdf.disabled"), nil
+	}
+
+	// Разбор идентификатора задачи
+	jobID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil {
+		return i18n.T(ctx, "job.usage", "pdf"), nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Получение задачи; чужие задачи считаем несуществующими
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "job.not_found"), nil
+	}
+
+	if job.Transcription == "" && job.Summary == "" {
+		return i18n.T(ctx, "job.not_ready"), nil
+	}
+
+	data, err := uc.documentService.RenderJob(ctx, newJobDocument(ctx, job))
+	if err != nil {
+		return "", fmt.Errorf("failed to render job document: %w", err)
+	}
+
+	// Документ отправляется с учетом настройки автоудаления, как и остальные результаты
+	caption := i18n.T(ctx, "pdf.caption", job.ID)
+	err = uc.messageRetentionUseCase.SendResultDocument(ctx, telegramID, messageID, fmt.Sprintf("job_%d.pdf", job.ID), data, caption)
+	if err != nil {
+		return "", fmt.Errorf("failed to send job document: %w", err)
+	}
+
+	// Логирование успешной обработки команды /pdf
+	uc.logger.Info("Successfully handled /pdf command",
+		"telegram_id", telegramID,
+		"job_id", jobID,
+	)
+
+	return "", nil
+}
+
+// newJobDocument собирает документ с результатом задачи: заголовок, сведения о задаче, краткое содержание
+// и транскрипцию, разбитую на фрагменты с метками времени, если они есть
+func newJobDocument(ctx context.Context, job *entity.Job) *entity.JobDocument {
+	title := jobTitle(ctx, job)
+	if title == "" {
+		title = i18n.T(ctx, "pdf.title", job.ID)
+	}
+
+	metadata := []entity.DocumentField{
+		{Name: i18n.T(ctx, "pdf.field.job"), Value: strconv.FormatInt(job.ID, 10)},
+		{Name: i18n.T(ctx, "pdf.field.created"), Value: job.CreatedAt.Format("02.01.2006 15:04")},
+	}
+	if job.Duration > 0 {
+		metadata = append(metadata, entity.DocumentField{Name: i18n.T(ctx, "pdf.field.duration"), Value: textutil.FormatTimestamp(int(job.Duration))})
+	}
+	if job.SummaryModel != "" {
+		metadata = append(metadata, entity.DocumentField{Name: i18n.T(ctx, "pdf.field.model"), Value: job.SummaryModel})
+	}
+
+	return &entity.JobDocument{
+		Title:      title,
+		Language:   i18n.FromContext(ctx),
+		Metadata:   metadata,
+		Summary:    job.Summary,
+		Transcript: documentTranscript(job.Transcription),
+	}
+}
+
+// documentTranscript разбивает транскрипцию на строки документа: строки с метками времени выводятся
+// с ними, а транскрипция без меток - абзацами, как она записана, а не отдельными предложениями
+func documentTranscript(transcription string) []*entity.TranscriptSegment {
+	segments := splitTranscriptSegments(transcription)
+	for _, segment := range segments {
+		if segment.StartSeconds != nil {
+			return segments
+		}
+	}
+
+	segments = segments[:0]
+	for _, line := range strings.Split(transcription, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			segments = append(segments, &entity.TranscriptSegment{Position: len(segments), Text: line})
+		}
+	}
+	return segments
+}
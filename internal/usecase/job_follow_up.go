@@ -0,0 +1,353 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Действия с результатом задачи, которые выполняются в очереди
+const (
+	followUpFullText  = "full"      // Прислать полную транскрипцию
+	followUpSummary   = "summary"   // Прислать только краткое содержание
+	followUpTranslate = "translate" // Перевести краткое содержание
+	followUpNotion    = "notion"    // Выгрузить результат в Notion заново
+	followUpTags      = "tags"      // Подобрать теги
+	followUpListen    = "listen"    // Озвучить краткое содержание голосовым сообщением
+
+	followUpTranscriptTranslate = "translate_transcript" // Перевести транскрипцию на заданный язык
+	followUpInstruction         = "instruction"          // Выполнить просьбу пользователя по транскрипции
+)
+
+// Языки перевода краткого содержания: по умолчанию оно переводится на английский, а английское - на русский
+const (
+	followUpTranslationDefault = "en"
+	followUpTranslationReverse = "ru"
+)
+
+// maxListenParts - сколько голосовых сообщений можно прислать при озвучивании одного краткого содержания
+const maxListenParts = 3
+
+// JobFollowUpUseCase представляет собой сценарий действий с результатом завершенной задачи по кнопкам
+// под уведомлением: каждое действие ставится в очередь и присылает ответ отдельным сообщением
+type JobFollowUpUseCase struct {
+	userRepo                repository.UserRepository
+	jobRepo                 repository.JobRepository
+	queueService            service.QueueService
+	summarizationService    service.SummarizationService
+	speechService           service.SpeechService
+	syncUseCase             *SyncUseCase
+	messageRetentionUseCase *MessageRetentionUseCase
+	languageUseCase         *InterfaceLanguageUseCase
+	logger                  *logger.Logger
+}
+
+// NewJobFollowUpUseCase создает новый сценарий действий с результатом задачи
+func NewJobFollowUpUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	queueService service.QueueService,
+	summarizationService service.SummarizationService,
+	speechService service.SpeechService,
+	syncUseCase *SyncUseCase,
+	messageRetentionUseCase *MessageRetentionUseCase,
+	languageUseCase *InterfaceLanguageUseCase,
+	logger *logger.Logger,
+) *JobFollowUpUseCase {
+	return &JobFollowUpUseCase{
+		userRepo:                userRepo,
+		jobRepo:                 jobRepo,
+		queueService:            queueService,
+		summarizationService:    summarizationService,
+		speechService:           speechService,
+		syncUseCase:             syncUseCase,
+		messageRetentionUseCase: messageRetentionUseCase,
+		languageUseCase:         languageUseCase,
+		logger:                  logger,
+	}
+}
+
+// Enqueue ставит действие с результатом задачи в очередь; ответ придет в ответ на сообщение messageID
+func (uc *JobFollowUpUseCase) Enqueue(ctx context.Context, job *entity.Job, action string, messageID int) error {
+	return uc.EnqueueRequest(ctx, job, action, messageID, "", "")
+}
+
+// EnqueueRequest ставит в очередь действие с результатом задачи по просьбе пользователя: language - код языка
+// перевода (пустой - язык выбирается автоматически), instructions - текст просьбы для followUpInstruction
+func (uc *JobFollowUpUseCase) EnqueueRequest(ctx context.Context, job *entity.Job, action string, messageID int, language string, instructions string) error {
+	payload := map[string]interface{}{
+		"action":     action,
+		"message_id": strconv.Itoa(messageID),
+	}
+	if language != "" {
+		payload["language"] = language
+	}
+	if instructions != "" {
+		payload["instructions"] = instructions
+	}
+
+	followUpJob := entity.QueueJob{
+		JobID:   job.ID,
+		UserID:  job.UserID,
+		JobType: entity.JobTypeFollowUp,
+		Payload: payload,
+	}
+
+	err := uc.queueService.PushJob(ctx, followUpJob)
+	if err != nil {
+		uc.logger.Error("Failed to push follow-up job to queue",
+			"error", err,
+		)
+		return fmt.Errorf("failed to push follow-up job to queue: %w", err)
+	}
+
+	return nil
+}
+
+// ProcessFollowUp выполняет действие с результатом задачи и присылает ответ пользователю
+func (uc *JobFollowUpUseCase) ProcessFollowUp(ctx context.Context, job entity.QueueJob) error {
+	// Получение данных из задачи
+	payload, ok := job.Payload.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid payload type in job")
+	}
+
+	action, ok := payload["action"].(string)
+	if !ok {
+		return fmt.Errorf("action not found in job payload or has invalid type")
+	}
+
+	// Сообщение, под которым нажата кнопка; если его нет, ответ приходит отдельным сообщением
+	messageIDStr, _ := payload["message_id"].(string)
+	messageID, _ := strconv.Atoi(messageIDStr)
+	language, _ := payload["language"].(string)
+
+	// Логирование начала действия
+	uc.logger.Info("Processing job follow-up",
+		"job_id", job.JobID,
+		"action", action,
+	)
+
+	user, err := uc.userRepo.GetByID(ctx, job.UserID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	ctx = i18n.WithLanguage(ctx, uc.languageUseCase.ForUser(ctx, user))
+
+	storedJob, err := uc.jobRepo.GetByID(ctx, job.JobID)
+	if err != nil {
+		uc.logger.Error("Failed to get job",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	var text string
+	switch action {
+	case followUpFullText:
+		return uc.messageRetentionUseCase.SendResultText(ctx, user.TelegramID, messageID,
+			i18n.T(ctx, "follow_up.transcript", storedJob.ID, storedJob.Transcription),
+			fmt.Sprintf("transcript_%d.txt", storedJob.ID), storedJob.Transcription)
+
+	case followUpSummary:
+		return uc.messageRetentionUseCase.SendResultText(ctx, user.TelegramID, messageID,
+			i18n.T(ctx, "follow_up.summary", storedJob.ID, storedJob.Summary),
+			fmt.Sprintf("summary_%d.md", storedJob.ID), storedJob.Summary)
+
+	case followUpTranslate:
+		text, err = uc.translate(ctx, storedJob, language)
+
+	case followUpTranscriptTranslate:
+		// Переведенная транскрипция длинная, поэтому, как и исходная, может прийти файлом
+		translation, err := uc.translateTranscript(ctx, storedJob, language)
+		if err != nil {
+			return err
+		}
+		return uc.messageRetentionUseCase.SendResultText(ctx, user.TelegramID, messageID,
+			i18n.T(ctx, "follow_up.transcript_translation", storedJob.ID, i18n.T(ctx, "language.name."+language), translation),
+			fmt.Sprintf("transcript_%d_%s.txt", storedJob.ID, language), translation)
+
+	case followUpInstruction:
+		instructions, _ := payload["instructions"].(string)
+		text, err = uc.followInstruction(ctx, storedJob, instructions)
+
+	case followUpNotion:
+		text, err = uc.syncNotion(ctx, user, storedJob)
+
+	case followUpTags:
+		text, err = uc.suggestTags(ctx, storedJob)
+
+	case followUpListen:
+		return uc.listen(ctx, user, storedJob, messageID)
+
+	default:
+		return fmt.Errorf("unknown follow-up action %q", action)
+	}
+	if err != nil {
+		return err
+	}
+
+	return uc.messageRetentionUseCase.SendResult(ctx, user.TelegramID, messageID, text, nil)
+}
+
+// translate переводит краткое содержание задачи на язык language. Если язык не задан, краткое содержание
+// переводится на английский, а английское - на русский
+func (uc *JobFollowUpUseCase) translate(ctx context.Context, job *entity.Job, language string) (string, error) {
+	if _, ok := summaryLanguageNames[language]; !ok {
+		language = followUpTranslationDefault
+		if detectLanguage(job.Summary) == followUpTranslationDefault {
+			language = followUpTranslationReverse
+		}
+	}
+
+	translation, err := uc.summarizationService.Translate(ctx, job.Summary, summaryLanguageNames[language])
+	if err != nil {
+		uc.logger.Error("Failed to translate summary",
+			"error", err,
+			"job_id", job.ID,
+		)
+		return "", fmt.Errorf("failed to translate summary: %w", err)
+	}
+
+	return i18n.T(ctx, "follow_up.summary_translation", job.ID, i18n.T(ctx, "language.name."+language), translation), nil
+}
+
+// translateTranscript переводит транскрипцию задачи на язык language и возвращает перевод
+func (uc *JobFollowUpUseCase) translateTranscript(ctx context.Context, job *entity.Job, language string) (string, error) {
+	languageName, ok := summaryLanguageNames[language]
+	if !ok {
+		return "", fmt.Errorf("unsupported translation language %q", language)
+	}
+
+	translation, err := uc.summarizationService.Translate(ctx, job.Transcription, languageName)
+	if err != nil {
+		uc.logger.Error("Failed to translate transcription",
+			"error", err,
+			"job_id", job.ID,
+		)
+		return "", fmt.Errorf("failed to translate transcription: %w", err)
+	}
+
+	return translation, nil
+}
+
+// followInstruction выполняет просьбу пользователя по транскрипции задачи, например составляет список
+// договоренностей. Сохраненное краткое содержание задачи при этом не меняется
+func (uc *JobFollowUpUseCase) followInstruction(ctx context.Context, job *entity.Job, instructions string) (string, error) {
+	result, err := uc.summarizationService.SummarizeWithInstructions(ctx, job.Transcription, instructions)
+	if err != nil {
+		uc.logger.Error("Failed to follow job instructions",
+			"error", err,
+			"job_id", job.ID,
+		)
+		return "", fmt.Errorf("failed to follow job instructions: %w", err)
+	}
+
+	return i18n.T(ctx, "follow_up.instruction", job.ID, instructions, result), nil
+}
+
+// syncNotion выгружает результат задачи в Notion заново, например после пересуммаризации или удаления страницы
+func (uc *JobFollowUpUseCase) syncNotion(ctx context.Context, user *entity.User, job *entity.Job) (string, error) {
+	synced, err := uc.syncUseCase.SyncTargetAgain(ctx, user, job, NotionSyncTargetName)
+	if err != nil {
+		return "", fmt.Errorf("failed to sync job to Notion: %w", err)
+	}
+	if !synced {
+		return i18n.T(ctx, "job_action.notion_not_connected"), nil
+	}
+
+	// Ссылка на новую страницу сохранена в задаче при выгрузке
+	job, err = uc.jobRepo.GetByID(ctx, job.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return i18n.T(ctx, "follow_up.notion_synced", job.ID, notionURL(job.NotionPageID)), nil
+}
+
+// suggestTags подбирает теги по краткому содержанию задачи
+func (uc *JobFollowUpUseCase) suggestTags(ctx context.Context, job *entity.Job) (string, error) {
+	tags, err := uc.summarizationService.SuggestTags(ctx, job.Summary)
+	if err != nil {
+		uc.logger.Error("Failed to suggest tags",
+			"error", err,
+			"job_id", job.ID,
+		)
+		return "", fmt.Errorf("failed to suggest tags: %w", err)
+	}
+	if len(tags) == 0 {
+		return i18n.T(ctx, "follow_up.no_tags", job.ID), nil
+	}
+
+	return i18n.T(ctx, "follow_up.tags", job.ID, strings.Join(tags, " #")), nil
+}
+
+// listen озвучивает краткое содержание задачи и присылает его голосовыми сообщениями. Длинное краткое
+// содержание озвучивается по частям, но не больше maxListenParts сообщений
+func (uc *JobFollowUpUseCase) listen(ctx context.Context, user *entity.User, job *entity.Job, messageID int) error {
+	text := speechText(job.Summary)
+	if text == "" {
+		return uc.messageRetentionUseCase.SendResult(ctx, user.TelegramID, messageID,
+			i18n.T(ctx, "follow_up.nothing_to_listen", job.ID), nil)
+	}
+
+	parts := splitMessageText(text, service.MaxSpeechTextLength)
+	truncated := len(parts) > maxListenParts
+	if truncated {
+		parts = parts[:maxListenParts]
+	}
+
+	for i, part := range parts {
+		audio, err := uc.speechService.Synthesize(ctx, part)
+		if err != nil {
+			uc.logger.Error("Failed to synthesize summary",
+				"error", err,
+				"job_id", job.ID,
+			)
+			return fmt.Errorf("failed to synthesize summary: %w", err)
+		}
+
+		caption := i18n.T(ctx, "follow_up.listen", job.ID)
+		if len(parts) > 1 {
+			caption += fmt.Sprintf(" (%d/%d)", i+1, len(parts))
+		}
+		if truncated && i == len(parts)-1 {
+			caption += ". " + i18n.T(ctx, "follow_up.listen_truncated")
+		}
+
+		err = uc.messageRetentionUseCase.SendResultVoice(ctx, user.TelegramID, messageID,
+			fmt.Sprintf("summary_%d_%d.ogg", job.ID, i+1), audio, caption)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// speechMarkup - выделение Markdown, которое синтез речи произнес бы вслух
+var speechMarkup = strings.NewReplacer("**", "", "__", "", "`", "", "[ ]", "", "[x]", "")
+
+// speechText подготавливает краткое содержание к озвучиванию: убирает выделение, заголовки,
+// маркеры списков и пустые строки
+func speechText(summary string) string {
+	lines := strings.Split(speechMarkup.Replace(summary), "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#*-•"))
+		if line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Параметры сообщений о ходе длинных задач
+const (
+	// keepaliveMaxAge - задачам старше этого возраста сообщения не отправляются: скорее всего, они зависли
+	keepaliveMaxAge = 6 * time.Hour
+	// keepaliveBatchSize - сколько задач обрабатывается за одну проверку
+	keepaliveBatchSize = 100
+	// keepaliveEstimateBase и keepaliveEstimateRatio задают оценку времени обработки:
+	// постоянные затраты плюс доля длительности записи
+	keepaliveEstimateBase  = 2 * time.Minute
+	keepaliveEstimateRatio = 0.5
+)
+
+// JobKeepaliveUseCase представляет собой сценарий сообщений о ходе длинных задач: пока задача в работе,
+// пользователь видит, сколько времени прошло и сколько примерно осталось, и не отправляет файл заново
+type JobKeepaliveUseCase struct {
+	userRepo        repository.UserRepository
+	jobRepo         repository.JobRepository
+	languageUseCase *InterfaceLanguageUseCase
+	messenger       service.MessengerService
+	after           time.Duration
+	interval        time.Duration
+	logger          *logger.Logger
+}
+
+// NewJobKeepaliveUseCase создает новый сценарий сообщений о ходе длинных задач.
+// after - возраст задачи, после которого отправляется первое сообщение (<= 0 отключает сообщения),
+// interval - период обновления сообщения
+func NewJobKeepaliveUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	languageUseCase *InterfaceLanguageUseCase,
+	messenger service.MessengerService,
+	after time.Duration,
+	interval time.Duration,
+	logger *logger.Logger,
+) *JobKeepaliveUseCase {
+	return &JobKeepaliveUseCase{
+		userRepo:        userRepo,
+		jobRepo:         jobRepo,
+		languageUseCase: languageUseCase,
+		messenger:       messenger,
+		after:           after,
+		interval:        interval,
+		logger:          logger,
+	}
+}
+
+// RunSchedule отправляет сообщения о ходе длинных задач по расписанию до отмены контекста
+func (uc *JobKeepaliveUseCase) RunSchedule(ctx context.Context) {
+	if uc.after <= 0 || uc.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(uc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			uc.sendDue(ctx, now)
+		}
+	}
+}
+
+// sendDue отправляет или обновляет сообщения о ходе задач, которые выполняются дольше after;
+// ошибка одной задачи не мешает остальным
+func (uc *JobKeepaliveUseCase) sendDue(ctx context.Context, now time.Time) {
+	// Половина периода в запасе, чтобы неровный шаг таймера не пропускал обновления
+	keepaliveBefore := now.Add(-uc.interval / 2)
+
+	jobs, err := uc.jobRepo.GetForKeepalive(ctx, now.Add(-keepaliveMaxAge), now.Add(-uc.after), keepaliveBefore, keepaliveBatchSize)
+	if err != nil {
+		uc.logger.Error("Failed to get jobs for keepalive",
+			"error", err,
+		)
+		return
+	}
+
+	for _, job := range jobs {
+		// Сообщение отправляет только тот экземпляр приложения, который первым отметил задачу
+		claimed, err := uc.jobRepo.ClaimKeepalive(ctx, job.ID, keepaliveBefore, now)
+		if err != nil {
+			uc.logger.Error("Failed to claim job keepalive",
+				"error", err,
+				"job_id", job.ID,
+			)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := uc.sendKeepalive(ctx, job, now); err != nil {
+			uc.logger.Error("Failed to send job keepalive",
+				"error", err,
+				"job_id", job.ID,
+			)
+		}
+	}
+}
+
+// sendKeepalive обновляет сообщение о ходе задачи или, если его еще нет, отправляет новое
+func (uc *JobKeepaliveUseCase) sendKeepalive(ctx context.Context, job *entity.Job, now time.Time) error {
+	user, err := uc.userRepo.GetByID(ctx, job.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	ctx = i18n.WithLanguage(ctx, uc.languageUseCase.ForUser(ctx, user))
+	text := formatKeepalive(ctx, job, now.Sub(job.CreatedAt))
+
+	if job.KeepaliveMessageID != 0 {
+		err := uc.messenger.EditMessage(user.TelegramID, job.KeepaliveMessageID, text)
+		if err == nil {
+			return nil
+		}
+		// Сообщение могло быть удалено пользователем, поэтому отправляем новое
+		uc.logger.Warn("Failed to edit keepalive message",
+			"error", err,
+			"job_id", job.ID,
+		)
+	}
+
+	messageID, err := uc.messenger.SendReply(user.TelegramID, job.SourceMessageID, text)
+	if err != nil {
+		return fmt.Errorf("failed to send keepalive message: %w", err)
+	}
+
+	if err := uc.jobRepo.SetKeepaliveMessage(ctx, job.ID, messageID); err != nil {
+		return fmt.Errorf("failed to save keepalive message: %w", err)
+	}
+
+	uc.logger.Debug("Sent job keepalive",
+		"job_id", job.ID,
+		"elapsed", now.Sub(job.CreatedAt),
+	)
+
+	return nil
+}
+
+// estimateProcessingTime оценивает полное время обработки задачи по длительности записи.
+// Для задач без длительности (текст, неизвестная длительность) возвращает 0
+func estimateProcessingTime(durationSeconds float64) time.Duration {
+	if durationSeconds <= 0 {
+		return 0
+	}
+	return keepaliveEstimateBase + time.Duration(durationSeconds*keepaliveEstimateRatio*float64(time.Second))
+}
+
+// formatKeepalive формирует текст сообщения о ходе задачи
+func formatKeepalive(ctx context.Context, job *entity.Job, elapsed time.Duration) string {
+	message := i18n.T(ctx, "keepalive.elapsed", job.ID, int(elapsed.Minutes()))
+
+	if estimate := estimateProcessingTime(job.Duration); estimate > 0 {
+		remaining := int(math.Ceil((estimate - elapsed).Minutes()))
+		if remaining > 0 {
+			message += i18n.T(ctx, "keepalive.remaining", remaining)
+		} else {
+			message += i18n.T(ctx, "keepalive.almost_done")
+		}
+	}
+
+	return message + ".\n" + i18n.T(ctx, "keepalive.footer")
+}
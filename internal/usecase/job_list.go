@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+)
+
+// JobsCallbackPrefix - префикс данных кнопок списка задач /jobs.
+// Данные кнопки имеют вид jobs:<отбор>:<страница>, страницы нумеруются с нуля
+const JobsCallbackPrefix = "jobs"
+
+// jobsPageSize - сколько задач показывается на одной странице /jobs
+const jobsPageSize = 5
+
+// jobStatusFilterNames - идентификаторы сообщений с названиями отборов задач по статусу для кнопок и заголовка списка
+var jobStatusFilterNames = map[entity.JobStatusFilter]string{
+	entity.JobStatusFilterAll:       "jobs.filter.all",
+	entity.JobStatusFilterActive:    "jobs.filter.active",
+	entity.JobStatusFilterCompleted: "jobs.filter.completed",
+	entity.JobStatusFilterFailed:    "jobs.filter.failed",
+}
+
+// jobsPage формирует страницу списка задач пользователя с отбором по статусу и кнопки навигации
+func (uc *TelegramHandlersUseCase) jobsPage(ctx context.Context, userID int64, filter entity.JobStatusFilter, page int) (string, [][]entity.MessageButton, error) {
+	// Запрашивается на одну задачу больше страницы, чтобы узнать, есть ли следующая
+	jobs, err := uc.jobRepo.GetByUserIDAndStatus(ctx, userID, filter, jobsPageSize+1, page*jobsPageSize)
+	if err != nil {
+		uc.logger.Error("Failed to get user jobs",
+			"error", err,
+		)
+		return "", nil, fmt.Errorf("failed to get user jobs: %w", err)
+	}
+
+	hasNext := len(jobs) > jobsPageSize
+	if hasNext {
+		jobs = jobs[:jobsPageSize]
+	}
+
+	// У пользователя нет ни одной задачи: кнопки не нужны
+	if len(jobs) == 0 && filter == entity.JobStatusFilterAll && page == 0 {
+		return i18n.T(ctx, "jobs.empty"), nil, nil
+	}
+
+	return formatJobsPage(ctx, jobs, filter, page), jobsPageButtons(ctx, filter, page, hasNext), nil
+}
+
+// formatJobsPage формирует сообщение со страницей списка задач
+func formatJobsPage(ctx context.Context, jobs []*entity.Job, filter entity.JobStatusFilter, page int) string {
+	messageBuilder := strings.Builder{}
+	messageBuilder.WriteString(i18n.T(ctx, "jobs.header", strings.ToLower(i18n.T(ctx, jobStatusFilterNames[filter])), page+1) + "\n\n")
+
+	if len(jobs) == 0 {
+		messageBuilder.WriteString(i18n.T(ctx, "jobs.none"))
+		return messageBuilder.String()
+	}
+
+	for i, job := range jobs {
+		statusEmoji, statusText := jobStatusLabel(ctx, job.Status)
+
+		// Добавление информации о задаче
+		name := filepath.Base(job.AudioFilePath)
+		if job.AudioFilePath == "" {
+			name = jobTitle(ctx, job)
+		}
+		messageBuilder.WriteString(i18n.T(ctx, "jobs.item",
+			job.ID,
+			statusEmoji,
+			name,
+			statusText,
+			job.CreatedAt.Format("02.01.2006 15:04"),
+		))
+
+		// Если задача завершена и есть ID страницы Notion
+		if job.Status == entity.JobStatusCompleted && job.NotionPageID != "" {
+			messageBuilder.WriteString("   " + i18n.T(ctx, "jobs.in_notion") + "\n")
+		}
+
+		// Добавление разделителя между задачами
+		if i < len(jobs)-1 {
+			messageBuilder.WriteString("\n")
+		}
+	}
+
+	messageBuilder.WriteString("\n" + i18n.T(ctx, "jobs.footer"))
+	return messageBuilder.String()
+}
+
+// jobTitle возвращает название задачи для списков и ответов бота. У текстовых задач имени файла нет,
+// поэтому они подписываются на языке пользователя при показе
+func jobTitle(ctx context.Context, job *entity.Job) string {
+	if job.Type == entity.JobTypeText {
+		return i18n.T(ctx, "jobs.text_title")
+	}
+	return job.FileName
+}
+
+// jobStatusLabel возвращает значок и название статуса задачи для списка задач
+func jobStatusLabel(ctx context.Context, status entity.JobStatus) (string, string) {
+	switch status {
+	case entity.JobStatusPending:
+		return "⏳", i18n.T(ctx, "job_status.pending")
+	case entity.JobStatusProcessing:
+		return "⚙️", i18n.T(ctx, "job_status.processing")
+	case entity.JobStatusTranscribed:
+		return "📝", i18n.T(ctx, "job_status.transcribed")
+	case entity.JobStatusSummarized:
+		return "📊", i18n.T(ctx, "job_status.summarized")
+	case entity.JobStatusCompleted:
+		return "✅", i18n.T(ctx, "job_status.completed")
+	case entity.JobStatusFailed:
+		return "❌", i18n.T(ctx, "job_status.failed")
+	}
+	return "❓", i18n.T(ctx, "job_status.unknown")
+}
+
+// jobsPageButtons возвращает кнопки отбора по статусу (выбранный отмечен галочкой) и перехода между страницами.
+// При смене отбора список открывается с первой страницы
+func jobsPageButtons(ctx context.Context, filter entity.JobStatusFilter, page int, hasNext bool) [][]entity.MessageButton {
+	data := func(filter entity.JobStatusFilter, page int) string {
+		return fmt.Sprintf("%s:%s:%d", JobsCallbackPrefix, filter, page)
+	}
+
+	filters := make([]entity.MessageButton, 0, len(entity.JobStatusFilters))
+	for _, candidate := range entity.JobStatusFilters {
+		label := i18n.T(ctx, jobStatusFilterNames[candidate])
+		if candidate == filter {
+			label = "✅ " + label
+		}
+		filters = append(filters, entity.MessageButton{Text: label, Data: data(candidate, 0)})
+	}
+	buttons := [][]entity.MessageButton{filters[:2], filters[2:]}
+
+	navigation := []entity.MessageButton{}
+	if page > 0 {
+		navigation = append(navigation, entity.MessageButton{Text: i18n.T(ctx, "jobs.button.back"), Data: data(filter, page-1)})
+	}
+	if hasNext {
+		navigation = append(navigation, entity.MessageButton{Text: i18n.T(ctx, "jobs.button.next"), Data: data(filter, page+1)})
+	}
+	if len(navigation) > 0 {
+		buttons = append(buttons, navigation)
+	}
+
+	return buttons
+}
+
+// parseJobsPageData разбирает данные кнопки списка задач (<отбор>:<страница>)
+func parseJobsPageData(data string) (entity.JobStatusFilter, int, error) {
+	filterValue, pageValue, _ := strings.Cut(data, ":")
+
+	filter := entity.JobStatusFilter(filterValue)
+	if _, ok := jobStatusFilterNames[filter]; !ok {
+		return "", 0, fmt.Errorf("unknown job status filter %q", filterValue)
+	}
+
+	page, err := strconv.Atoi(pageValue)
+	if err != nil || page < 0 {
+		return "", 0, fmt.Errorf("invalid jobs page %q", pageValue)
+	}
+
+	return filter, page, nil
+}
@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Параметры индекса логов задач
+const (
+	// jobLogBufferSize - сколько записей ждут сохранения; при переполнении новые записи отбрасываются,
+	// чтобы запись в лог никогда не блокировала обработку задач
+	jobLogBufferSize    = 2000
+	jobLogBatchSize     = 200
+	jobLogFlushInterval = 2 * time.Second
+	jobLogPruneInterval = time.Hour
+	// maxJobLogLines - сколько последних записей задачи попадает в файл лога
+	maxJobLogLines = 2000
+	// maxJobLogValueLength - ограничение длины значения атрибута в индексе
+	maxJobLogValueLength = 500
+)
+
+// jobLogUserAttrs - атрибуты записей, которые видит пользователь в /logs. Остальные атрибуты (пути к файлам,
+// ID пользователей и сообщений, адреса) показываются только администраторам в /admin_logs
+var jobLogUserAttrs = map[string]bool{
+	"stage":         true,
+	"status":        true,
+	"attempt":       true,
+	"attempts":      true,
+	"duration":      true,
+	"model":         true,
+	"language":      true,
+	"profile":       true,
+	"audio_profile": true,
+	"summary_style": true,
+	"provider":      true,
+	"error":         true,
+}
+
+// Шаблоны данных, которые скрываются в логах для пользователя
+var (
+	jobLogURLPattern   = regexp.MustCompile(`https?://\S+`)
+	jobLogPathPattern  = regexp.MustCompile(`(?:/[\w.\-]+){2,}`)
+	jobLogEmailPattern = regexp.MustCompile(`[\w.+\-]+@[\w\-]+\.[\w.\-]+`)
+	jobLogTokenPattern = regexp.MustCompile(`[A-Za-z0-9_\-]{32,}`)
+)
+
+// JobLogUseCase представляет собой сценарий индекса логов задач: записи лога приложения с атрибутом job_id
+// сохраняются в базу и отдаются файлом командами /logs (своя задача, без служебных данных) и /admin_logs
+// (любая задача, все атрибуты) вместе с длительностью этапов, чтобы по обращению в поддержку не искать
+// их в логах сервера. Записи хранятся retention и удаляются по расписанию
+type JobLogUseCase struct {
+	userRepo  repository.UserRepository
+	jobRepo   repository.JobRepository
+	stageRepo repository.JobStageRepository
+	logRepo   repository.JobLogRepository
+	messenger service.MessengerService
+	retention time.Duration
+	logger    *logger.Logger
+
+	lines   chan *entity.JobLogLine
+	dropped atomic.Int64
+}
+
+// NewJobLogUseCase создает новый сценарий индекса логов задач. С нулевым retention индекс отключен
+func NewJobLogUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	stageRepo repository.JobStageRepository,
+	logRepo repository.JobLogRepository,
+	messenger service.MessengerService,
+	retention time.Duration,
+	logger *logger.Logger,
+) *JobLogUseCase {
+	return &JobLogUseCase{
+		userRepo:  userRepo,
+		jobRepo:   jobRepo,
+		stageRepo: stageRepo,
+		logRepo:   logRepo,
+		messenger: messenger,
+		retention: retention,
+		logger:    logger,
+		lines:     make(chan *entity.JobLogLine, jobLogBufferSize),
+	}
+}
+
+// Enabled сообщает, собирается ли индекс логов задач
+func (uc *JobLogUseCase) Enabled() bool {
+	return uc.retention > 0
+}
+
+// Collect принимает запись лога приложения и, если она относится к задаче, ставит ее в очередь на сохранение.
+// Вызывается логгером при каждой записи, поэтому не блокируется и сам ничего не пишет в лог
+func (uc *JobLogUseCase) Collect(entry logger.Entry) {
+	jobID := jobLogID(entry.Attrs["job_id"])
+	if jobID <= 0 {
+		return
+	}
+
+	attrs := make(map[string]string, len(entry.Attrs))
+	for key, value := range entry.Attrs {
+		if key == "job_id" {
+			continue
+		}
+		attrs[key] = truncateRunes(jobLogValue(value), maxJobLogValueLength, false)
+	}
+
+	line := &entity.JobLogLine{
+		JobID:     jobID,
+		Level:     entry.Level,
+		Message:   entry.Message,
+		Attrs:     attrs,
+		CreatedAt: entry.Time,
+	}
+	select {
+	case uc.lines <- line:
+	default:
+		uc.dropped.Add(1)
+	}
+}
+
+// RunWriter сохраняет собранные записи пакетами и удаляет устаревшие до отмены контекста.
+// Записи, собранные к моменту остановки, сохраняются перед выходом
+func (uc *JobLogUseCase) RunWriter(ctx context.Context) {
+	if !uc.Enabled() {
+		return
+	}
+
+	flushTicker := time.NewTicker(jobLogFlushInterval)
+	defer flushTicker.Stop()
+	pruneTicker := time.NewTicker(jobLogPruneInterval)
+	defer pruneTicker.Stop()
+
+	batch := make([]*entity.JobLogLine, 0, jobLogBatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			batch = uc.drain(batch)
+			uc.flush(context.WithoutCancel(ctx), batch)
+			return
+		case line := <-uc.lines:
+			batch = append(batch, line)
+			if len(batch) >= jobLogBatchSize {
+				batch = uc.flush(ctx, batch)
+			}
+		case <-flushTicker.C:
+			batch = uc.flush(ctx, batch)
+		case now := <-pruneTicker.C:
+			uc.prune(ctx, now)
+		}
+	}
+}
+
+// drain забирает из очереди все записи, которые в ней есть
+func (uc *JobLogUseCase) drain(batch []*entity.JobLogLine) []*entity.JobLogLine {
+	for {
+		select {
+		case line := <-uc.lines:
+			batch = append(batch, line)
+		default:
+			return batch
+		}
+	}
+}
+
+// flush сохраняет пакет записей и возвращает пустой пакет. Несохраненные записи теряются:
+// индекс логов вспомогательный, а сами записи остаются в логах сервера
+func (uc *JobLogUseCase) flush(ctx context.Context, batch []*entity.JobLogLine) []*entity.JobLogLine {
+	if dropped := uc.dropped.Swap(0); dropped > 0 {
+		uc.logger.Warn("Job log index buffer overflow",
+			"dropped", dropped,
+		)
+	}
+	if len(batch) == 0 {
+		return batch
+	}
+
+	if err := uc.logRepo.Create(ctx, batch); err != nil {
+		uc.logger.Error("Failed to save job log lines",
+			"error", err,
+			"lines", len(batch),
+		)
+	}
+	return batch[:0]
+}
+
+// prune удаляет записи старше срока хранения
+func (uc *JobLogUseCase) prune(ctx context.Context, now time.Time) {
+	deleted, err := uc.logRepo.DeleteBefore(ctx, now.Add(-uc.retention))
+	if err != nil {
+		uc.logger.Error("Failed to prune job log lines",
+			"error", err,
+		)
+		return
+	}
+	if deleted > 0 {
+		uc.logger.Info("Pruned job log lines",
+			"deleted", deleted,
+		)
+	}
+}
+
+// HandleLogs обрабатывает команду /logs: присылает файл с этапами и записями лога своей задачи
+// без служебных данных. Ответ команды возвращается только для ошибок ввода
+func (uc *JobLogUseCase) HandleLogs(ctx context.Context, telegramID int64, messageID int, args string) (string, error) {
+	// Логирование начала обработки команды /logs
+	uc.logger.Info("Handling /logs command",
+		"telegram_id", telegramID,
+	)
+
+	jobID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil {
+		return i18n.T(ctx, "logs.usage"), nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Чужие задачи считаем несуществующими
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "job.not_found"), nil
+	}
+
+	return uc.sendLog(ctx, telegramID, messageID, job, true)
+}
+
+// HandleAdminLogs обрабатывает команду /admin_logs: присылает администратору файл с этапами
+// и записями лога любой задачи со всеми атрибутами
+func (uc *JobLogUseCase) HandleAdminLogs(ctx context.Context, adminTelegramID int64, messageID int, args string) (string, error) {
+	// Логирование начала обработки команды /admin_logs
+	uc.logger.Info("Handling /admin_logs command",
+		"telegram_id", adminTelegramID,
+	)
+
+	jobID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil {
+		return i18n.T(ctx, "admin_logs.usage"), nil
+	}
+
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return i18n.T(ctx, "job.not_found"), nil
+	}
+
+	return uc.sendLog(ctx, adminTelegramID, messageID, job, false)
+}
+
+// sendLog собирает лог задачи и отправляет его файлом в ответ на команду
+func (uc *JobLogUseCase) sendLog(ctx context.Context, chatID int64, messageID int, job *entity.Job, redact bool) (string, error) {
+	if !uc.Enabled() {
+		return i18n.T(ctx, "logs.disabled"), nil
+	}
+
+	stages, err := uc.stageRepo.GetByJobID(ctx, job.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get job stages: %w", err)
+	}
+	lines, err := uc.logRepo.GetByJobID(ctx, job.ID, maxJobLogLines)
+	if err != nil {
+		return "", fmt.Errorf("failed to get job log lines: %w", err)
+	}
+	if len(stages) == 0 && len(lines) == 0 {
+		return i18n.T(ctx, "logs.empty", job.ID, formatRetention(ctx, uc.retention)), nil
+	}
+
+	data := []byte(formatJobLog(ctx, job, stages, lines, redact))
+	caption := i18n.T(ctx, "logs.caption", job.ID)
+	if _, err := uc.messenger.SendDocument(chatID, messageID, fmt.Sprintf("job_%d_log.txt", job.ID), data, caption); err != nil {
+		return "", fmt.Errorf("failed to send job log: %w", err)
+	}
+
+	// Логирование отправленного лога
+	uc.logger.Info("Job log sent",
+		"telegram_id", chatID,
+		"job_id", job.ID,
+		"lines", len(lines),
+		"redacted", redact,
+	)
+
+	return "", nil
+}
+
+// formatJobLog формирует текст файла лога задачи: сведения о задаче, этапы с длительностью и записи лога.
+// В логе для пользователя остаются только атрибуты из jobLogUserAttrs, а адреса, пути и токены скрыты
+func formatJobLog(ctx context.Context, job *entity.Job, stages []*entity.JobStage, lines []*entity.JobLogLine, redact bool) string {
+	builder := strings.Builder{}
+	builder.WriteString(i18n.T(ctx, "logs.file.job", job.ID) + "\n")
+	builder.WriteString(i18n.T(ctx, "logs.file.status", job.Status) + "\n")
+	builder.WriteString(i18n.T(ctx, "logs.file.created", job.CreatedAt.Format("02.01.2006 15:04:05 MST")) + "\n")
+	if job.ErrorMessage != "" {
+		builder.WriteString(i18n.T(ctx, "logs.file.error", redactJobLogValue(job.ErrorMessage, redact)) + "\n")
+	}
+
+	if len(stages) > 0 {
+		builder.WriteString("\n" + i18n.T(ctx, "logs.file.stages") + "\n")
+		writeJobStages(ctx, &builder, stages)
+	}
+
+	builder.WriteString("\n" + i18n.T(ctx, "logs.file.journal") + "\n")
+	if len(lines) == 0 {
+		builder.WriteString(i18n.T(ctx, "logs.file.no_lines") + "\n")
+	}
+	for _, line := range lines {
+		builder.WriteString(fmt.Sprintf("%s %-5s %s", line.CreatedAt.Format("15:04:05.000"), line.Level, redactJobLogValue(line.Message, redact)))
+
+		keys := make([]string, 0, len(line.Attrs))
+		for key := range line.Attrs {
+			if !redact || jobLogUserAttrs[key] {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			builder.WriteString(fmt.Sprintf(" %s=%q", key, redactJobLogValue(line.Attrs[key], redact)))
+		}
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// redactJobLogValue скрывает в значении для пользователя адреса, пути к файлам, почту и токены
+func redactJobLogValue(value string, redact bool) string {
+	if !redact {
+		return value
+	}
+	value = jobLogURLPattern.ReplaceAllString(value, "<url>")
+	value = jobLogEmailPattern.ReplaceAllString(value, "<email>")
+	value = jobLogPathPattern.ReplaceAllString(value, "<path>")
+	return jobLogTokenPattern.ReplaceAllString(value, "<token>")
+}
+
+// jobLogID возвращает ID задачи из атрибута job_id записи лога или 0, если атрибута нет
+func jobLogID(value any) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case uint64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
+
+// jobLogValue приводит значение атрибута записи лога к строке
+func jobLogValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatRetention возвращает срок хранения логов для пользователя: в днях или часах
+func formatRetention(ctx context.Context, retention time.Duration) string {
+	if days := int(retention.Hours() / 24); days >= 1 {
+		return i18n.T(ctx, "logs.retention.days", days)
+	}
+	return i18n.T(ctx, "logs.retention.hours", int(retention.Hours()))
+}
@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
+)
+
+// Параметры индексации и поиска по заметкам
+const (
+	embeddingChunkSize = 2000 // Максимальный размер фрагмента транскрипции в символах
+	askTopK            = 5    // Количество фрагментов, передаваемых в модель при ответе на вопрос
+
+	// relatedSearchFactor - во сколько раз больше фрагментов ищется для подбора связанных заметок:
+	// несколько фрагментов обычно принадлежат одной заметке
+	relatedSearchFactor = 5
+	// relatedMaxDistance - наибольшее косинусное расстояние, при котором заметки считаются связанными
+	relatedMaxDistance = 0.55
+)
+
+// KnowledgeBaseUseCase представляет собой сценарий семантического поиска по заметкам пользователя
+type KnowledgeBaseUseCase struct {
+	userRepo         repository.UserRepository
+	jobRepo          repository.JobRepository
+	embeddingRepo    repository.EmbeddingRepository
+	embeddingService service.EmbeddingService
+	answerService    service.AnswerService
+	logger           *logger.Logger
+}
+
+// NewKnowledgeBaseUseCase создает новый сценарий семантического поиска по заметкам
+func NewKnowledgeBaseUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	embeddingRepo repository.EmbeddingRepository,
+	embeddingService service.EmbeddingService,
+	answerService service.AnswerService,
+	logger *logger.Logger,
+) *KnowledgeBaseUseCase {
+	return &KnowledgeBaseUseCase{
+		userRepo:         userRepo,
+		jobRepo:          jobRepo,
+		embeddingRepo:    embeddingRepo,
+		embeddingService: embeddingService,
+		answerService:    answerService,
+		logger:           logger,
+	}
+}
+
+// ProcessEmbedding индексирует краткое содержание и транскрипцию задачи для семантического поиска
+func (uc *KnowledgeBaseUseCase) ProcessEmbedding(ctx context.Context, job entity.QueueJob) error {
+	// Логирование начала индексации
+	uc.logger.Info("Processing embedding",
+		"job_id", job.JobID,
+	)
+
+	// Получение задачи
+	storedJob, err := uc.jobRepo.GetByID(ctx, job.JobID)
+	if err != nil {
+		uc.logger.Error("Failed to get job",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	// Формирование фрагментов для индексации
+	notes := make([]*entity.NoteEmbedding, 0)
+	if storedJob.Summary != "" {
+		notes = append(notes, &entity.NoteEmbedding{
+			Kind:    entity.NoteEmbeddingKindSummary,
+			Content: storedJob.Summary,
+		})
+	}
+	for i, chunk := range textutil.SplitWords(storedJob.Transcription, embeddingChunkSize) {
+		notes = append(notes, &entity.NoteEmbedding{
+			Kind:       entity.NoteEmbeddingKindTranscription,
+			ChunkIndex: i,
+			Content:    chunk,
+		})
+	}
+
+	if len(notes) == 0 {
+		uc.logger.Warn("Job has nothing to index",
+			"job_id", job.JobID,
+		)
+		return nil
+	}
+
+	// Получение эмбеддингов
+	texts := make([]string, len(notes))
+	for i, note := range notes {
+		texts[i] = note.Content
+	}
+
+	embeddings, err := uc.embeddingService.Embed(ctx, texts)
+	if err != nil {
+		uc.logger.Error("Failed to create embeddings",
+			"error", err,
+		)
+		return fmt.Errorf("failed to create embeddings: %w", err)
+	}
+
+	for i, note := range notes {
+		note.JobID = storedJob.ID
+		note.UserID = storedJob.UserID
+		note.Embedding = embeddings[i]
+	}
+
+	// Сохранение эмбеддингов
+	err = uc.embeddingRepo.ReplaceForJob(ctx, storedJob.ID, notes)
+	if err != nil {
+		uc.logger.Error("Failed to save embeddings",
+			"error", err,
+		)
+		return fmt.Errorf("failed to save embeddings: %w", err)
+	}
+
+	// Логирование успешной индексации
+	uc.logger.Info("Embedding processed successfully",
+		"job_id", job.JobID,
+		"fragments", len(notes),
+	)
+
+	return nil
+}
+
+// Ask отвечает на вопрос пользователя по его прошлым заметкам и возвращает ответ со ссылками на источники
+func (uc *KnowledgeBaseUseCase) Ask(ctx context.Context, telegramID int64, question string) (string, error) {
+	// Логирование начала поиска ответа
+	uc.logger.Info("Answering question from notes",
+		"telegram_id", telegramID,
+	)
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Получение эмбеддинга вопроса
+	embeddings, err := uc.embeddingService.Embed(ctx, []string{question})
+	if err != nil {
+		uc.logger.Error("Failed to embed question",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to embed question: %w", err)
+	}
+
+	// Поиск ближайших фрагментов заметок
+	notes, err := uc.embeddingRepo.SearchSimilar(ctx, user.ID, embeddings[0], askTopK)
+	if err != nil {
+		uc.logger.Error("Failed to search notes",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to search notes: %w", err)
+	}
+
+	if len(notes) == 0 {
+		return "", nil
+	}
+
+	// Формирование фрагментов для модели
+	contents := make([]string, len(notes))
+	for i, note := range notes {
+		contents[i] = note.Content
+	}
+
+	answer, err := uc.answerService.Answer(ctx, question, contents)
+	if err != nil {
+		uc.logger.Error("Failed to answer question",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to answer question: %w", err)
+	}
+
+	// Добавление списка источников
+	var builder strings.Builder
+	builder.WriteString(answer)
+	builder.WriteString("\n\n" + i18n.T(ctx, "ask.sources") + "\n")
+	for i, note := range notes {
+		source := i18n.T(ctx, "ask.source", note.JobID)
+		if job, err := uc.jobRepo.GetByID(ctx, note.JobID); err == nil {
+			source = i18n.T(ctx, "ask.source_details", job.ID, jobTitle(ctx, job), job.CreatedAt.Format("02.01.2006"))
+		}
+		builder.WriteString(fmt.Sprintf("[%d] %s\n", i+1, source))
+	}
+
+	// Логирование успешного ответа
+	uc.logger.Info("Question answered from notes successfully",
+		"telegram_id", telegramID,
+		"sources", len(notes),
+	)
+
+	return builder.String(), nil
+}
+
+// RelatedJobs возвращает до limit прошлых задач пользователя со страницами в Notion, близких по смыслу к задаче,
+// от самой близкой. Задача сравнивается по краткому содержанию, а без него - по началу транскрипции
+func (uc *KnowledgeBaseUseCase) RelatedJobs(ctx context.Context, job *entity.Job, limit int) ([]*entity.Job, error) {
+	text := job.Summary
+	if text == "" {
+		text = truncateRunes(job.Transcription, embeddingChunkSize, false)
+	}
+	if text == "" || limit <= 0 {
+		return nil, nil
+	}
+
+	embeddings, err := uc.embeddingService.Embed(ctx, []string{text})
+	if err != nil {
+		uc.logger.Error("Failed to embed job for related notes",
+			"error", err,
+			"job_id", job.ID,
+		)
+		return nil, fmt.Errorf("failed to embed job: %w", err)
+	}
+
+	notes, err := uc.embeddingRepo.SearchSimilar(ctx, job.UserID, embeddings[0], limit*relatedSearchFactor)
+	if err != nil {
+		uc.logger.Error("Failed to search related notes",
+			"error", err,
+			"job_id", job.ID,
+		)
+		return nil, fmt.Errorf("failed to search related notes: %w", err)
+	}
+
+	// Фрагменты отсортированы по расстоянию, поэтому первый фрагмент заметки - самый близкий
+	seen := map[int64]bool{job.ID: true}
+	related := make([]*entity.Job, 0, limit)
+	for _, note := range notes {
+		if seen[note.JobID] || note.Distance > relatedMaxDistance {
+			continue
+		}
+		seen[note.JobID] = true
+
+		relatedJob, err := uc.jobRepo.GetByID(ctx, note.JobID)
+		if err != nil || relatedJob.NotionPageID == "" {
+			continue
+		}
+		related = append(related, relatedJob)
+		if len(related) == limit {
+			break
+		}
+	}
+
+	return related, nil
+}
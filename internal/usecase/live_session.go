@@ -0,0 +1,386 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Параметры live-сессий
+const (
+	// liveJobFileName - имя, под которым задачи завершенных live-сессий отображаются в списке задач
+	liveJobFileName = "Live-сессия"
+	// liveLockKey - ключ блокировки live-сессии пользователя: голосовые сообщения одной сессии
+	// добавляются по очереди, чтобы транскрипция не теряла части и не перемешивалась
+	liveLockKey = "live:%d"
+	// liveLockWait - сколько голосовое сообщение ждет, пока обработается предыдущее сообщение сессии
+	liveLockWait = 2 * time.Minute
+	// maxLiveTranscriptLength - ограничение длины транскрипции сессии в символах: текущее краткое
+	// содержание пересчитывается по всей транскрипции после каждого сообщения
+	maxLiveTranscriptLength = 30000
+	// maxLiveSummaryLength - ограничение длины текущего краткого содержания в сообщении сессии
+	maxLiveSummaryLength = 1500
+)
+
+// liveSummaryInstructions - указания к текущему краткому содержанию сессии: оно обновляется после
+// каждого сообщения и показывается вместе с транскрипцией, поэтому должно быть коротким
+const liveSummaryInstructions = "Это промежуточное краткое содержание разговора, который еще продолжается. " +
+	"Уложись в 3-7 коротких пунктов без заголовков и вступления."
+
+// LiveSessionUseCase представляет собой сценарий live-сессий (экспериментальный режим /live): пользователь
+// присылает подряд короткие голосовые сообщения, а бот транскрибирует каждое по мере поступления и ведет
+// транскрипцию и текущее краткое содержание в одном сообщении, которое редактирует. По /live stop транскрипция
+// сессии становится одной задачей: она суммаризируется и выгружается в Notion одной страницей
+type LiveSessionUseCase struct {
+	userRepo             repository.UserRepository
+	jobRepo              repository.JobRepository
+	sessionRepo          repository.LiveSessionRepository
+	queueService         service.QueueService
+	audioService         service.AudioService
+	transcriptionService service.TranscriptionService
+	summarizationService service.SummarizationService
+	lockService          service.LockService
+	messenger            service.MessengerService
+	logger               *logger.Logger
+}
+
+// NewLiveSessionUseCase создает новый сценарий live-сессий
+func NewLiveSessionUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	sessionRepo repository.LiveSessionRepository,
+	queueService service.QueueService,
+	audioService service.AudioService,
+	transcriptionService service.TranscriptionService,
+	summarizationService service.SummarizationService,
+	lockService service.LockService,
+	messenger service.MessengerService,
+	logger *logger.Logger,
+) *LiveSessionUseCase {
+	return &LiveSessionUseCase{
+		userRepo:             userRepo,
+		jobRepo:              jobRepo,
+		sessionRepo:          sessionRepo,
+		queueService:         queueService,
+		audioService:         audioService,
+		transcriptionService: transcriptionService,
+		summarizationService: summarizationService,
+		lockService:          lockService,
+		messenger:            messenger,
+		logger:               logger,
+	}
+}
+
+// HandleLive обрабатывает команду /live: начинает сессию или завершает ее (/live stop).
+// При начале сессии бот сам отправляет сообщение сессии, и ответ команды пуст
+func (uc *LiveSessionUseCase) HandleLive(ctx context.Context, telegramID int64, username string, args string) (string, error) {
+	// Логирование начала обработки команды /live
+	uc.logger.Info("Handling /live command",
+		"telegram_id", telegramID,
+	)
+
+	user, err := uc.getOrCreateUser(ctx, telegramID, username)
+	if err != nil {
+		return "", err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		return uc.start(ctx, user)
+	case "stop":
+		return uc.stop(ctx, user)
+	case "cancel":
+		ended, err := uc.sessionRepo.Delete(ctx, user.ID)
+		if err != nil {
+			return "", err
+		}
+		if !ended {
+			return i18n.T(ctx, "live.not_started"), nil
+		}
+		return i18n.T(ctx, "live.cancelled"), nil
+	}
+
+	return i18n.T(ctx, "live.usage"), nil
+}
+
+// getOrCreateUser возвращает пользователя по Telegram ID, создавая его при первом обращении
+func (uc *LiveSessionUseCase) getOrCreateUser(ctx context.Context, telegramID int64, username string) (*entity.User, error) {
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err == nil {
+		return user, nil
+	}
+
+	user = &entity.User{
+		TelegramID: telegramID,
+		Username:   username,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		uc.logger.Error("Failed to create user",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return user, nil
+}
+
+// start начинает live-сессию и отправляет сообщение, в котором будет вестись транскрипция
+func (uc *LiveSessionUseCase) start(ctx context.Context, user *entity.User) (string, error) {
+	session, err := uc.sessionRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+	if session != nil {
+		return i18n.T(ctx, "live.already_started"), nil
+	}
+
+	session = &entity.LiveSession{UserID: user.ID}
+	messageID, err := uc.messenger.SendMessage(user.TelegramID, formatLiveSession(ctx, session))
+	if err != nil {
+		return "", fmt.Errorf("failed to send live session message: %w", err)
+	}
+	session.MessageID = messageID
+
+	if err := uc.sessionRepo.Set(ctx, session); err != nil {
+		uc.logger.Error("Failed to start live session",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to start live session: %w", err)
+	}
+
+	// Логирование начала сессии
+	uc.logger.Info("Live session started",
+		"user_id", user.ID,
+	)
+
+	return "", nil
+}
+
+// GetSession возвращает активную live-сессию пользователя или nil
+func (uc *LiveSessionUseCase) GetSession(ctx context.Context, userID int64) (*entity.LiveSession, error) {
+	session, err := uc.sessionRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		uc.logger.Error("Failed to get live session",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get live session: %w", err)
+	}
+
+	return session, nil
+}
+
+// AddVoice транскрибирует голосовое сообщение, добавляет его к транскрипции сессии, пересчитывает
+// текущее краткое содержание и обновляет сообщение сессии. Возвращает ответ пользователю, если
+// сообщение не добавлено; при успешном добавлении ответ пуст - результат виден в сообщении сессии
+func (uc *LiveSessionUseCase) AddVoice(ctx context.Context, user *entity.User, audioPath string) (string, error) {
+	unlock, err := uc.lockService.Lock(ctx, fmt.Sprintf(liveLockKey, user.ID), liveLockWait)
+	if err != nil {
+		return "", fmt.Errorf("failed to lock live session: %w", err)
+	}
+	defer unlock()
+	defer uc.removeAudio(audioPath)
+
+	// Пока сообщение ждало блокировку, сессию могли завершить
+	session, err := uc.GetSession(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+	if session == nil {
+		return i18n.T(ctx, "live.already_stopped"), nil
+	}
+	if utf8.RuneCountInString(session.Transcript) >= maxLiveTranscriptLength {
+		return i18n.T(ctx, "live.too_long"), nil
+	}
+
+	// Логирование добавления сообщения
+	uc.logger.Info("Adding voice message to live session",
+		"user_id", user.ID,
+		"segment", session.Segments+1,
+	)
+
+	processedAudioPath, _, err := uc.audioService.ProcessAudioWithProfile(ctx, audioPath, entity.AudioProfileSpeech)
+	if err != nil {
+		uc.logger.Error("Failed to process live session audio",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to process live session audio: %w", err)
+	}
+	if processedAudioPath != audioPath {
+		defer uc.removeAudio(processedAudioPath)
+	}
+
+	text, err := uc.transcriptionService.TranscribeWithOptions(ctx, processedAudioPath, user.TranscriptionOptions)
+	if err != nil {
+		uc.logger.Error("Failed to transcribe live session audio",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to transcribe live session audio: %w", err)
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return i18n.T(ctx, "live.no_speech"), nil
+	}
+
+	if session.Transcript != "" {
+		session.Transcript += "\n\n"
+	}
+	session.Transcript += text
+	session.Segments++
+
+	// Без текущего краткого содержания сессия продолжается: итоговое все равно составляется при завершении
+	summary, err := uc.summarizationService.SummarizeWithInstructions(ctx, session.Transcript, liveSummaryInstructions)
+	if err != nil {
+		uc.logger.Warn("Failed to update live session summary",
+			"error", err,
+			"user_id", user.ID,
+		)
+	} else {
+		session.Summary = summary
+	}
+
+	if err := uc.sessionRepo.Set(ctx, session); err != nil {
+		uc.logger.Error("Failed to update live session",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to update live session: %w", err)
+	}
+
+	// Сообщение сессии могли удалить: тогда сессия продолжается в новом сообщении
+	if err := uc.messenger.EditMessage(user.TelegramID, session.MessageID, formatLiveSession(ctx, session)); err != nil {
+		uc.logger.Warn("Failed to edit live session message",
+			"error", err,
+			"user_id", user.ID,
+		)
+		messageID, err := uc.messenger.SendMessage(user.TelegramID, formatLiveSession(ctx, session))
+		if err != nil {
+			return "", fmt.Errorf("failed to send live session message: %w", err)
+		}
+		session.MessageID = messageID
+		if err := uc.sessionRepo.Set(ctx, session); err != nil {
+			return "", fmt.Errorf("failed to update live session: %w", err)
+		}
+	}
+
+	return "", nil
+}
+
+// stop завершает live-сессию: транскрипция сессии сохраняется задачей и отправляется на суммаризацию,
+// после которой задача, как и любая другая, выгружается в Notion и приходит уведомление о ее завершении
+func (uc *LiveSessionUseCase) stop(ctx context.Context, user *entity.User) (string, error) {
+	// Голосовое сообщение, которое еще транскрибируется, должно попасть в задачу
+	unlock, err := uc.lockService.Lock(ctx, fmt.Sprintf(liveLockKey, user.ID), liveLockWait)
+	if err != nil {
+		return "", fmt.Errorf("failed to lock live session: %w", err)
+	}
+	defer unlock()
+
+	session, err := uc.GetSession(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+	if session == nil {
+		return i18n.T(ctx, "live.stop_not_started"), nil
+	}
+	if strings.TrimSpace(session.Transcript) == "" {
+		if _, err := uc.sessionRepo.Delete(ctx, user.ID); err != nil {
+			return "", err
+		}
+		return i18n.T(ctx, "live.stopped_empty"), nil
+	}
+
+	job := entity.Job{
+		UserID:          user.ID,
+		Type:            entity.JobTypeLive,
+		Status:          entity.JobStatusCreated,
+		FileName:        fmt.Sprintf("%s от %s", liveJobFileName, session.CreatedAt.Format("02.01.2006 15:04")),
+		Transcription:   session.Transcript,
+		SourceMessageID: session.MessageID,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	if err := uc.jobRepo.Create(ctx, &job); err != nil {
+		uc.logger.Error("Failed to create job",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to create job: %w", err)
+	}
+
+	// Транскрипция уже сохранена в задаче, поэтому дальше она обрабатывается как присланный текст
+	summarizationJob := entity.QueueJob{
+		JobID:   job.ID,
+		UserID:  user.ID,
+		JobType: entity.JobTypeSummarization,
+	}
+	if err := uc.queueService.PushJob(ctx, summarizationJob); err != nil {
+		uc.logger.Error("Failed to push summarization job to queue",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to push summarization job to queue: %w", err)
+	}
+
+	// Задача уже создана: если сессию не удалось удалить, ее можно завершить еще раз без потери задачи
+	if _, err := uc.sessionRepo.Delete(ctx, user.ID); err != nil {
+		uc.logger.Error("Failed to delete live session",
+			"error", err,
+			"job_id", job.ID,
+		)
+	}
+
+	// Логирование завершения сессии
+	uc.logger.Info("Live session stopped",
+		"user_id", user.ID,
+		"job_id", job.ID,
+		"segments", session.Segments,
+	)
+
+	return i18n.T(ctx, "live.stopped", session.Segments, job.ID), nil
+}
+
+// formatLiveSession формирует сообщение live-сессии: текущее краткое содержание и конец транскрипции,
+// который помещается в сообщение Telegram
+func formatLiveSession(ctx context.Context, session *entity.LiveSession) string {
+	header := i18n.T(ctx, "live.header", session.Segments) + "\n\n"
+	footer := "\n\n" + i18n.T(ctx, "live.footer")
+	if session.Segments == 0 {
+		return header + i18n.T(ctx, "live.empty") + footer
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString(header)
+	if session.Summary != "" {
+		builder.WriteString(i18n.T(ctx, "live.summary") + "\n")
+		builder.WriteString(truncateRunes(session.Summary, maxLiveSummaryLength, false))
+		builder.WriteString("\n\n")
+	}
+	builder.WriteString(i18n.T(ctx, "live.transcript") + "\n")
+
+	transcript := session.Transcript
+	available := telegramMessageLimit - utf8.RuneCountInString(builder.String()) - utf8.RuneCountInString(footer) - 1
+	if utf8.RuneCountInString(transcript) > available {
+		transcript = "…" + truncateRunes(transcript, available, true)
+	}
+	builder.WriteString(transcript)
+	builder.WriteString(footer)
+
+	return builder.String()
+}
+
+// removeAudio удаляет файл голосового сообщения сессии: задачи для него не создается,
+// поэтому после транскрибации файл больше не нужен
+func (uc *LiveSessionUseCase) removeAudio(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		uc.logger.Warn("Failed to remove live session audio",
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// maintenanceRefreshPeriod - как часто экземпляр перечитывает режим обслуживания, включенный на другом экземпляре
+const maintenanceRefreshPeriod = 5 * time.Second
+
+// maxMaintenanceReasonLength - ограничение длины причины обслуживания
+const maxMaintenanceReasonLength = 200
+
+// MaintenanceUseCase представляет собой сценарий режима технического обслуживания для миграций базы данных
+// и сбоев провайдеров: пока режим включен, бот отвечает на новые записи уведомлением, а воркер очереди
+// дорабатывает текущую задачу и больше задач не берет. Режим общий для всех экземпляров приложения;
+// каждый экземпляр перечитывает его раз в maintenanceRefreshPeriod, чтобы проверки не обращались к хранилищу
+type MaintenanceUseCase struct {
+	maintenanceRepo repository.MaintenanceRepository
+	auditRepo       repository.AdminAuditRepository
+	logger          *logger.Logger
+
+	mu      sync.RWMutex
+	current *entity.Maintenance
+}
+
+// NewMaintenanceUseCase создает новый сценарий режима технического обслуживания
+func NewMaintenanceUseCase(
+	maintenanceRepo repository.MaintenanceRepository,
+	auditRepo repository.AdminAuditRepository,
+	logger *logger.Logger,
+) *MaintenanceUseCase {
+	return &MaintenanceUseCase{
+		maintenanceRepo: maintenanceRepo,
+		auditRepo:       auditRepo,
+		logger:          logger,
+	}
+}
+
+// Current возвращает включенный режим обслуживания или nil, если бот работает как обычно
+func (uc *MaintenanceUseCase) Current() *entity.Maintenance {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.current
+}
+
+// QueuePaused сообщает воркеру очереди, что брать новые задачи нельзя
+func (uc *MaintenanceUseCase) QueuePaused() bool {
+	return uc.Current() != nil
+}
+
+// Refresh перечитывает режим обслуживания. Если хранилище недоступно, действует прежнее значение:
+// сбой Redis не должен ни останавливать, ни возобновлять работу бота
+func (uc *MaintenanceUseCase) Refresh(ctx context.Context) {
+	maintenance, err := uc.maintenanceRepo.Get(ctx)
+	if err != nil {
+		uc.logger.Warn("Failed to refresh maintenance mode",
+			"error", err,
+		)
+		return
+	}
+	uc.set(maintenance)
+}
+
+// RunRefresh перечитывает режим обслуживания по расписанию до отмены контекста
+func (uc *MaintenanceUseCase) RunRefresh(ctx context.Context) {
+	ticker := time.NewTicker(maintenanceRefreshPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.Refresh(ctx)
+		}
+	}
+}
+
+// set запоминает режим обслуживания и логирует его включение и выключение
+func (uc *MaintenanceUseCase) set(maintenance *entity.Maintenance) {
+	uc.mu.Lock()
+	previous := uc.current
+	uc.current = maintenance
+	uc.mu.Unlock()
+
+	switch {
+	case previous == nil && maintenance != nil:
+		uc.logger.Warn("Maintenance mode enabled, queue consumption paused",
+			"reason", maintenance.Reason,
+			"admin_telegram_id", maintenance.AdminTelegramID,
+		)
+	case previous != nil && maintenance == nil:
+		uc.logger.Info("Maintenance mode disabled, queue consumption resumed")
+	}
+}
+
+// Notice возвращает уведомление для пользователя, приславшего запись во время обслуживания
+func (uc *MaintenanceUseCase) Notice(ctx context.Context) string {
+	maintenance := uc.Current()
+	if maintenance == nil {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, "maintenance.notice"))
+	if maintenance.Reason != "" {
+		builder.WriteString("\n" + i18n.T(ctx, "maintenance.reason", maintenance.Reason))
+	}
+	builder.WriteString("\n" + formatResume(ctx, maintenance.ResumeAt, time.Now()))
+	builder.WriteString("\n" + i18n.T(ctx, "maintenance.notice_queued"))
+	return builder.String()
+}
+
+// formatResume возвращает ожидаемое время возобновления работы. Время показывается относительным:
+// часовой пояс пользователя боту неизвестен
+func formatResume(ctx context.Context, resumeAt *time.Time, now time.Time) string {
+	if resumeAt == nil || !resumeAt.After(now) {
+		return i18n.T(ctx, "maintenance.resume.soon")
+	}
+
+	minutes := int(math.Ceil(resumeAt.Sub(now).Minutes()))
+	if minutes < 60 {
+		return i18n.T(ctx, "maintenance.resume.minutes", minutes)
+	}
+	return i18n.T(ctx, "maintenance.resume.hours", minutes/60, minutes%60)
+}
+
+// HandleMaintenance обрабатывает команду /admin_maintenance: без аргументов показывает состояние режима,
+// on [длительность] [причина] включает его с ожидаемой длительностью обслуживания (например, 30m или 2h),
+// off выключает
+func (uc *MaintenanceUseCase) HandleMaintenance(ctx context.Context, adminTelegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /admin_maintenance
+	uc.logger.Info("Handling /admin_maintenance command",
+		"admin_telegram_id", adminTelegramID,
+	)
+
+	action, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	switch strings.ToLower(action) {
+	case "":
+		return uc.status(ctx), nil
+	case "on":
+		return uc.enable(ctx, adminTelegramID, strings.TrimSpace(rest))
+	case "off":
+		return uc.disable(ctx, adminTelegramID)
+	default:
+		return i18n.T(ctx, "maintenance.usage"), nil
+	}
+}
+
+// status возвращает состояние режима обслуживания для администратора
+func (uc *MaintenanceUseCase) status(ctx context.Context) string {
+	maintenance := uc.Current()
+	if maintenance == nil {
+		return i18n.T(ctx, "maintenance.status.off")
+	}
+
+	text := i18n.T(ctx, "maintenance.status.on", maintenance.StartedAt.Format("02.01.2006 15:04"), maintenance.AdminTelegramID)
+	if maintenance.Reason != "" {
+		text += "\n" + i18n.T(ctx, "maintenance.reason", maintenance.Reason)
+	}
+	return text + "\n" + formatResume(ctx, maintenance.ResumeAt, time.Now())
+}
+
+// enable включает режим обслуживания. Первое слово args - ожидаемая длительность, если оно разбирается
+// как длительность, остальное - причина
+func (uc *MaintenanceUseCase) enable(ctx context.Context, adminTelegramID int64, args string) (string, error) {
+	now := time.Now()
+	maintenance := &entity.Maintenance{
+		AdminTelegramID: adminTelegramID,
+		StartedAt:       now,
+	}
+
+	first, rest, _ := strings.Cut(args, " ")
+	if duration, err := time.ParseDuration(first); err == nil && duration > 0 {
+		resumeAt := now.Add(duration)
+		maintenance.ResumeAt = &resumeAt
+		args = strings.TrimSpace(rest)
+	}
+	maintenance.Reason = truncateRunes(args, maxMaintenanceReasonLength, false)
+
+	// Повторное включение обновляет причину и срок, но не время начала обслуживания
+	if current := uc.Current(); current != nil {
+		maintenance.StartedAt = current.StartedAt
+	}
+
+	if err := uc.maintenanceRepo.Save(ctx, maintenance); err != nil {
+		uc.logger.Error("Failed to enable maintenance mode",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to enable maintenance mode: %w", err)
+	}
+	uc.set(maintenance)
+	uc.audit(ctx, adminTelegramID, entity.AdminAuditActionMaintenanceOn, maintenance.Reason)
+
+	return i18n.T(ctx, "maintenance.enabled"), nil
+}
+
+// disable выключает режим обслуживания: очередь возобновляется без перезапуска приложения
+func (uc *MaintenanceUseCase) disable(ctx context.Context, adminTelegramID int64) (string, error) {
+	if uc.Current() == nil {
+		return i18n.T(ctx, "maintenance.already_off"), nil
+	}
+
+	if err := uc.maintenanceRepo.Clear(ctx); err != nil {
+		uc.logger.Error("Failed to disable maintenance mode",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to disable maintenance mode: %w", err)
+	}
+	uc.set(nil)
+	uc.audit(ctx, adminTelegramID, entity.AdminAuditActionMaintenanceOff, "")
+
+	return i18n.T(ctx, "maintenance.disabled"), nil
+}
+
+// audit записывает переключение режима в журнал действий администраторов. Обслуживание часто включают
+// на время миграций, когда база данных недоступна, поэтому ошибка записи не отменяет переключение
+func (uc *MaintenanceUseCase) audit(ctx context.Context, adminTelegramID int64, action entity.AdminAuditAction, details string) {
+	entry := &entity.AdminAuditEntry{
+		AdminTelegramID: adminTelegramID,
+		Action:          action,
+		Details:         details,
+	}
+
+	if err := uc.auditRepo.Create(ctx, entry); err != nil {
+		uc.logger.Warn("Failed to write admin audit entry",
+			"error", err,
+			"admin_telegram_id", adminTelegramID,
+			"action", action,
+		)
+	}
+}
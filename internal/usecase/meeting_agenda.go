@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// meetingAgendaTTL - сколько повестка ожидает записи встречи. Повестка, присланная позже, чем за это
+// время до записи, к ней не относится и игнорируется
+const meetingAgendaTTL = 12 * time.Hour
+
+// MaxMeetingAgendaSize - максимальный размер документа с повесткой в байтах
+const MaxMeetingAgendaSize = 16 << 10
+
+// meetingAgendaMaxLength - максимальная длина повестки в символах: повестка целиком попадает в промпт
+const meetingAgendaMaxLength = 3000
+
+// meetingAgendaMaxItems - максимальное число пунктов повестки
+const meetingAgendaMaxItems = 30
+
+// MeetingAgendaUseCase представляет собой сценарий повесток встреч: повестка, присланная перед записью,
+// привязывается к следующему аудио пользователя, и краткое содержание составляется по ее пунктам
+type MeetingAgendaUseCase struct {
+	userRepo   repository.UserRepository
+	agendaRepo repository.MeetingAgendaRepository
+	logger     *logger.Logger
+}
+
+// NewMeetingAgendaUseCase создает новый сценарий повесток встреч
+func NewMeetingAgendaUseCase(
+	userRepo repository.UserRepository,
+	agendaRepo repository.MeetingAgendaRepository,
+	logger *logger.Logger,
+) *MeetingAgendaUseCase {
+	return &MeetingAgendaUseCase{
+		userRepo:   userRepo,
+		agendaRepo: agendaRepo,
+		logger:     logger,
+	}
+}
+
+// SetAgenda сохраняет повестку, ожидающую следующей записи пользователя, заменяя прежнюю.
+// Пункты повестки нумеруются заново, маркеры списков убираются
+func (uc *MeetingAgendaUseCase) SetAgenda(ctx context.Context, userID int64, text string) (*entity.MeetingAgenda, error) {
+	if !utf8.ValidString(text) {
+		return nil, fmt.Errorf("agenda is not valid UTF-8 text")
+	}
+	if utf8.RuneCountInString(text) > meetingAgendaMaxLength {
+		return nil, fmt.Errorf("agenda is too long: at most %d characters", meetingAgendaMaxLength)
+	}
+
+	items := parseAgendaItems(text)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("agenda is empty")
+	}
+	if len(items) > meetingAgendaMaxItems {
+		return nil, fmt.Errorf("agenda has too many items: %d, at most %d", len(items), meetingAgendaMaxItems)
+	}
+
+	agenda := &entity.MeetingAgenda{
+		UserID: userID,
+		Text:   formatAgendaItems(items),
+	}
+	if err := uc.agendaRepo.SetPending(ctx, agenda); err != nil {
+		uc.logger.Error("Failed to set meeting agenda",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to set meeting agenda: %w", err)
+	}
+
+	// Логирование сохранения повестки
+	uc.logger.Info("Meeting agenda set",
+		"user_id", userID,
+		"items", len(items),
+	)
+
+	return agenda, nil
+}
+
+// GetPending возвращает повестку, ожидающую следующей записи пользователя, или nil
+func (uc *MeetingAgendaUseCase) GetPending(ctx context.Context, userID int64) (*entity.MeetingAgenda, error) {
+	agenda, err := uc.agendaRepo.GetPending(ctx, userID, time.Now().Add(-meetingAgendaTTL))
+	if err != nil {
+		uc.logger.Error("Failed to get meeting agenda",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get meeting agenda: %w", err)
+	}
+
+	return agenda, nil
+}
+
+// Clear удаляет ожидающую повестку пользователя и сообщает, была ли она
+func (uc *MeetingAgendaUseCase) Clear(ctx context.Context, userID int64) (bool, error) {
+	deleted, err := uc.agendaRepo.DeletePending(ctx, userID)
+	if err != nil {
+		uc.logger.Error("Failed to delete meeting agenda",
+			"error", err,
+		)
+		return false, fmt.Errorf("failed to delete meeting agenda: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// CountAgendaItems возвращает число пунктов сохраненной повестки
+func CountAgendaItems(agenda *entity.MeetingAgenda) int {
+	return len(parseAgendaItems(agenda.Text))
+}
+
+// parseAgendaItems разбивает повестку на пункты по строкам, убирая маркеры списков и нумерацию
+func parseAgendaItems(text string) []string {
+	var items []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimLeft(line, "-*•–—")
+		// Нумерация вида «1.» или «2)» убирается, а число в начале названия пункта остается
+		if digits := strings.TrimLeftFunc(line, unicode.IsDigit); len(digits) < len(line) &&
+			(strings.HasPrefix(digits, ".") || strings.HasPrefix(digits, ")")) {
+			line = digits[1:]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			items = append(items, line)
+		}
+	}
+	return items
+}
+
+// formatAgendaItems собирает пункты повестки в нумерованный список
+func formatAgendaItems(items []string) string {
+	var b strings.Builder
+	for i, item := range items {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, item)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Параметры автоудаления сообщений с результатами
+const (
+	// MaxResultRetentionHours - Telegram позволяет боту удалять свои сообщения только в течение 48 часов
+	MaxResultRetentionHours = 48
+	retentionCleanupPeriod  = time.Minute
+	telegramMessageLimit    = 4000 // Запас до ограничения Telegram в 4096 символов
+)
+
+// MessageRetentionUseCase представляет собой сценарий отправки результатов с автоудалением из чата
+type MessageRetentionUseCase struct {
+	userRepo     repository.UserRepository
+	deletionRepo repository.MessageDeletionRepository
+	messenger    service.MessengerService
+	logger       *logger.Logger
+}
+
+// NewMessageRetentionUseCase создает новый сценарий отправки результатов с автоудалением
+func NewMessageRetentionUseCase(
+	userRepo repository.UserRepository,
+	deletionRepo repository.MessageDeletionRepository,
+	messenger service.MessengerService,
+	logger *logger.Logger,
+) *MessageRetentionUseCase {
+	return &MessageRetentionUseCase{
+		userRepo:     userRepo,
+		deletionRepo: deletionRepo,
+		messenger:    messenger,
+		logger:       logger,
+	}
+}
+
+// SendResult отправляет пользователю сообщение с результатами и планирует его удаление согласно настройкам.
+// Если replyToMessageID не равен нулю, первая часть отправляется ответом на это сообщение.
+// Кнопки buttons, если они есть, прикладываются к последней части
+func (uc *MessageRetentionUseCase) SendResult(ctx context.Context, telegramID int64, replyToMessageID int, text string, buttons [][]entity.MessageButton) error {
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	parts := splitMessageText(text, telegramMessageLimit)
+	for i, part := range parts {
+		replyTo := 0
+		if i == 0 {
+			replyTo = replyToMessageID
+		}
+
+		var messageID int
+		if i == len(parts)-1 && len(buttons) > 0 {
+			messageID, err = uc.messenger.SendReplyWithButtons(user.TelegramID, replyTo, part, buttons)
+		} else {
+			messageID, err = uc.messenger.SendReply(user.TelegramID, replyTo, part)
+		}
+		if err != nil {
+			uc.logger.Error("Failed to send result message",
+				"error", err,
+				"user_id", user.ID,
+			)
+			return fmt.Errorf("failed to send result message: %w", err)
+		}
+
+		if err := uc.scheduleDeletion(ctx, user, messageID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SendResultDocument отправляет пользователю файл с результатами в ответ на сообщение replyToMessageID
+// и планирует его удаление согласно настройкам, как и сообщения SendResult
+func (uc *MessageRetentionUseCase) SendResultDocument(ctx context.Context, telegramID int64, replyToMessageID int, fileName string, data []byte, caption string) error {
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	messageID, err := uc.messenger.SendDocument(user.TelegramID, replyToMessageID, fileName, data, caption)
+	if err != nil {
+		uc.logger.Error("Failed to send result document",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return fmt.Errorf("failed to send result document: %w", err)
+	}
+
+	return uc.scheduleDeletion(ctx, user, messageID)
+}
+
+// SendResultVoice отправляет пользователю голосовое сообщение в ответ на сообщение replyToMessageID
+// и планирует его удаление согласно настройкам, как и сообщения SendResult
+func (uc *MessageRetentionUseCase) SendResultVoice(ctx context.Context, telegramID int64, replyToMessageID int, fileName string, data []byte, caption string) error {
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	messageID, err := uc.messenger.SendVoice(user.TelegramID, replyToMessageID, fileName, data, caption)
+	if err != nil {
+		uc.logger.Error("Failed to send result voice",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return fmt.Errorf("failed to send result voice: %w", err)
+	}
+
+	return uc.scheduleDeletion(ctx, user, messageID)
+}
+
+// SendResultText присылает текст результата одним сообщением, а если он не помещается в сообщение - файлом
+// fileName с содержимым content и подписью из первой строки text
+func (uc *MessageRetentionUseCase) SendResultText(ctx context.Context, telegramID int64, replyToMessageID int, text, fileName, content string) error {
+	if utf8.RuneCountInString(text) <= telegramMessageLimit {
+		return uc.SendResult(ctx, telegramID, replyToMessageID, text, nil)
+	}
+
+	caption, _, _ := strings.Cut(text, "\n")
+	return uc.SendResultDocument(ctx, telegramID, replyToMessageID, fileName, []byte(content), caption)
+}
+
+// scheduleDeletion планирует удаление сообщения с результатами, если пользователь включил автоудаление
+func (uc *MessageRetentionUseCase) scheduleDeletion(ctx context.Context, user *entity.User, messageID int) error {
+	if user.ResultRetentionHours <= 0 {
+		return nil
+	}
+
+	// Планирование удаления сообщения
+	deletion := entity.ScheduledDeletion{
+		ChatID:    user.TelegramID,
+		MessageID: messageID,
+		DeleteAt:  time.Now().Add(time.Duration(user.ResultRetentionHours) * time.Hour),
+	}
+
+	err := uc.deletionRepo.Schedule(ctx, deletion)
+	if err != nil {
+		uc.logger.Error("Failed to schedule message deletion",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return fmt.Errorf("failed to schedule message deletion: %w", err)
+	}
+
+	return nil
+}
+
+// SetRetention задает, через сколько часов удалять сообщения с результатами (0 - не удалять)
+func (uc *MessageRetentionUseCase) SetRetention(ctx context.Context, telegramID int64, hours int) error {
+	if hours < 0 || hours > MaxResultRetentionHours {
+		return fmt.Errorf("retention must be between 0 and %d hours", MaxResultRetentionHours)
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Обновление настройки
+	user.ResultRetentionHours = hours
+	err = uc.userRepo.Update(ctx, user)
+	if err != nil {
+		uc.logger.Error("Failed to update user",
+			"error", err,
+		)
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// Логирование успешного обновления настройки
+	uc.logger.Info("Result retention updated",
+		"user_id", user.ID,
+		"hours", hours,
+	)
+
+	return nil
+}
+
+// RunCleanup удаляет сообщения, срок хранения которых истек, по расписанию до отмены контекста
+func (uc *MessageRetentionUseCase) RunCleanup(ctx context.Context) {
+	ticker := time.NewTicker(retentionCleanupPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			uc.deleteDue(ctx, now)
+		}
+	}
+}
+
+// deleteDue удаляет из чатов сообщения, время удаления которых наступило
+func (uc *MessageRetentionUseCase) deleteDue(ctx context.Context, now time.Time) {
+	deletions, err := uc.deletionRepo.PopDue(ctx, now)
+	if err != nil {
+		uc.logger.Error("Failed to get due message deletions",
+			"error", err,
+		)
+	}
+
+	for _, deletion := range deletions {
+		// Сообщение могло быть уже удалено пользователем, поэтому ошибку только логируем
+		if err := uc.messenger.DeleteMessage(deletion.ChatID, deletion.MessageID); err != nil {
+			uc.logger.Warn("Failed to delete message",
+				"error", err,
+				"chat_id", deletion.ChatID,
+				"message_id", deletion.MessageID,
+			)
+		}
+	}
+
+	if len(deletions) > 0 {
+		uc.logger.Info("Expired result messages deleted",
+			"count", len(deletions),
+		)
+	}
+}
+
+// splitMessageText разбивает текст на части не длиннее limit символов, по возможности по переносам строк
+func splitMessageText(text string, limit int) []string {
+	parts := make([]string, 0)
+	runes := []rune(text)
+
+	for len(runes) > limit {
+		cut := limit
+		if idx := strings.LastIndex(string(runes[:limit]), "\n"); idx > 0 {
+			cut = len([]rune(string(runes[:limit])[:idx]))
+		}
+		parts = append(parts, string(runes[:cut]))
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), "\n"))
+	}
+
+	if len(runes) > 0 {
+		parts = append(parts, string(runes))
+	}
+
+	return parts
+}
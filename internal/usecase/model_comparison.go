@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// ModelComparisonCallbackPrefix - префикс данных кнопок голосования в сравнении моделей.
+// Данные кнопки имеют вид compare:<ID сравнения>:<a|b|tie>
+const ModelComparisonCallbackPrefix = "compare"
+
+// modelComparisonVoteLabels - идентификаторы сообщений с подписями кнопок голосования и итогов сравнения
+var modelComparisonVoteLabels = map[entity.ModelComparisonVote]string{
+	entity.ModelComparisonVoteA:   "compare.vote.a",
+	entity.ModelComparisonVoteB:   "compare.vote.b",
+	entity.ModelComparisonVoteTie: "compare.vote.tie",
+}
+
+// ModelComparisonUseCase представляет собой сценарий сравнения моделей суммаризации командой /compare:
+// краткие содержания задачи двумя моделями из конфигурации показываются рядом без названий моделей,
+// а голоса пользователей сохраняются, чтобы по ним выбрать модель по умолчанию
+type ModelComparisonUseCase struct {
+	userRepo                repository.UserRepository
+	jobRepo                 repository.JobRepository
+	comparisonRepo          repository.ModelComparisonRepository
+	summarizationService    service.SummarizationService
+	messageRetentionUseCase *MessageRetentionUseCase
+	models                  []string
+	logger                  *logger.Logger
+}
+
+// NewModelComparisonUseCase создает новый сценарий сравнения моделей суммаризации.
+// Без двух моделей в конфигурации команда /compare отключена
+func NewModelComparisonUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	comparisonRepo repository.ModelComparisonRepository,
+	summarizationService service.SummarizationService,
+	messageRetentionUseCase *MessageRetentionUseCase,
+	models []string,
+	logger *logger.Logger,
+) *ModelComparisonUseCase {
+	return &ModelComparisonUseCase{
+		userRepo:                userRepo,
+		jobRepo:                 jobRepo,
+		comparisonRepo:          comparisonRepo,
+		summarizationService:    summarizationService,
+		messageRetentionUseCase: messageRetentionUseCase,
+		models:                  models,
+		logger:                  logger,
+	}
+}
+
+// Enabled сообщает, настроены ли модели для сравнения
+func (uc *ModelComparisonUseCase) Enabled() bool {
+	return len(uc.models) == 2
+}
+
+// HandleCompare обрабатывает команду /compare: составляет краткие содержания задачи обеими моделями
+// и присылает их в случайном порядке как варианты A и B с кнопками голосования. Ответ команды
+// возвращается только для ошибок ввода
+func (uc *ModelComparisonUseCase) HandleCompare(ctx context.Context, telegramID int64, messageID int, args string) (string, error) {
+	// Логирование начала обработки команды /compare
+	uc.logger.Info("Handling /compare command",
+		"telegram_id", telegramID,
+	)
+
+	if !uc.Enabled() {
+		return i18n.T(ctx, "compare.disabled"), nil
+	}
+
+	// Разбор идентификатора задачи
+	jobID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil {
+		return i18n.T(ctx, "job.usage", "compare"), nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Получение задачи; чужие задачи считаем несуществующими
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "job.not_found"), nil
+	}
+	if job.Transcription == "" {
+		return i18n.T(ctx, "job.transcript_not_ready"), nil
+	}
+
+	// Порядок вариантов случайный, чтобы пользователь не угадывал модель по месту
+	models := []string{uc.models[0], uc.models[1]}
+	rand.Shuffle(len(models), func(i, j int) {
+		models[i], models[j] = models[j], models[i]
+	})
+
+	// Обе модели суммаризируют транскрипцию одновременно и с одинаковыми указаниями к конспекту
+	results := make([]*entity.SummaryResult, len(models))
+	errs := make([]error, len(models))
+	var wg sync.WaitGroup
+	for i, model := range models {
+		wg.Add(1)
+		go func(i int, model string) {
+			defer wg.Done()
+			results[i], errs[i] = uc.summarizationService.SummarizeWithModel(ctx, job.Transcription, job.Instructions, model)
+		}(i, model)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return "", fmt.Errorf("failed to summarize job for comparison: %w", err)
+		}
+	}
+
+	comparison := &entity.ModelComparison{
+		JobID:  job.ID,
+		UserID: user.ID,
+		ModelA: models[0],
+		ModelB: models[1],
+	}
+	if err := uc.comparisonRepo.Create(ctx, comparison); err != nil {
+		uc.logger.Error("Failed to create model comparison",
+			"error", err,
+		)
+		return "", err
+	}
+
+	// Варианты отправляются с учетом настройки автоудаления, как и остальные результаты
+	variants := []string{"compare.variant.a", "compare.variant.b"}
+	for i, result := range results {
+		text := i18n.T(ctx, variants[i], job.ID) + "\n\n" + result.Summary
+		if err := uc.messageRetentionUseCase.SendResult(ctx, telegramID, messageID, text, nil); err != nil {
+			return "", fmt.Errorf("failed to send comparison variant: %w", err)
+		}
+	}
+
+	buttons := [][]entity.MessageButton{{
+		uc.voteButton(ctx, comparison.ID, entity.ModelComparisonVoteA),
+		uc.voteButton(ctx, comparison.ID, entity.ModelComparisonVoteB),
+		uc.voteButton(ctx, comparison.ID, entity.ModelComparisonVoteTie),
+	}}
+	err = uc.messageRetentionUseCase.SendResult(ctx, telegramID, messageID,
+		i18n.T(ctx, "compare.question"), buttons)
+	if err != nil {
+		return "", fmt.Errorf("failed to send comparison vote: %w", err)
+	}
+
+	// Логирование успешной обработки команды /compare
+	uc.logger.Info("Successfully handled /compare command",
+		"telegram_id", telegramID,
+		"job_id", jobID,
+		"comparison_id", comparison.ID,
+	)
+
+	return "", nil
+}
+
+// voteButton возвращает кнопку голосования за итог сравнения
+func (uc *ModelComparisonUseCase) voteButton(ctx context.Context, comparisonID int64, vote entity.ModelComparisonVote) entity.MessageButton {
+	return entity.MessageButton{
+		Text: i18n.T(ctx, modelComparisonVoteLabels[vote]),
+		Data: fmt.Sprintf("%s:%d:%s", ModelComparisonCallbackPrefix, comparisonID, vote),
+	}
+}
+
+// HandleVote сохраняет голос по нажатию кнопки под сравнением. Возвращает новый текст сообщения
+// с названиями моделей и ответ на нажатие; пустой текст означает, что сообщение менять не нужно
+func (uc *ModelComparisonUseCase) HandleVote(ctx context.Context, telegramID int64, data string) (string, string, error) {
+	idValue, voteValue, _ := strings.Cut(data, ":")
+	comparisonID, err := strconv.ParseInt(idValue, 10, 64)
+	vote := entity.ModelComparisonVote(voteValue)
+	if _, ok := modelComparisonVoteLabels[vote]; err != nil || !ok {
+		return "", i18n.T(ctx, "callback.invalid_button"), nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Голосовать может только тот, кто запросил сравнение
+	comparison, err := uc.comparisonRepo.GetByID(ctx, comparisonID)
+	if err != nil {
+		return "", "", err
+	}
+	if comparison == nil || comparison.UserID != user.ID {
+		return "", i18n.T(ctx, "compare.not_found"), nil
+	}
+
+	voted, err := uc.comparisonRepo.Vote(ctx, comparisonID, vote, time.Now())
+	if err != nil {
+		uc.logger.Error("Failed to save model comparison vote",
+			"error", err,
+		)
+		return "", "", err
+	}
+	if !voted {
+		return "", i18n.T(ctx, "compare.already_voted"), nil
+	}
+
+	// Логирование голоса
+	uc.logger.Info("Model comparison vote saved",
+		"comparison_id", comparisonID,
+		"vote", vote,
+	)
+
+	text := i18n.T(ctx, "compare.voted", i18n.T(ctx, modelComparisonVoteLabels[vote]), comparison.ModelA, comparison.ModelB)
+	return text, i18n.T(ctx, "compare.voted_answer"), nil
+}
+
+// Report возвращает итоги голосований по моделям для команды /admin models
+func (uc *ModelComparisonUseCase) Report(ctx context.Context) (string, error) {
+	stats, err := uc.comparisonRepo.GetModelStats(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get model vote stats: %w", err)
+	}
+	if len(stats) == 0 {
+		return i18n.T(ctx, "compare.report.empty"), nil
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString(i18n.T(ctx, "compare.report.header") + "\n")
+	for _, modelStats := range stats {
+		losses := modelStats.Comparisons - modelStats.Wins - modelStats.Ties
+		builder.WriteString("\n" + i18n.T(ctx, "compare.report.model",
+			modelStats.Model, modelStats.Wins, modelStats.Ties, losses,
+			modelStats.Wins*100/modelStats.Comparisons, modelStats.Comparisons))
+	}
+	return builder.String(), nil
+}
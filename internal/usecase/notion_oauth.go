@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Параметры входа в Notion через OAuth
+const (
+	// notionOAuthStateBytes - длина случайного параметра state в байтах
+	notionOAuthStateBytes = 24
+	// notionOAuthStateTTL - сколько действует ссылка на вход в Notion
+	notionOAuthStateTTL = 15 * time.Minute
+)
+
+// ErrNotionOAuthState - параметр state обратного вызова неизвестен или истек: ссылка на вход устарела
+// или уже использована
+var ErrNotionOAuthState = errors.New("unknown or expired notion oauth state")
+
+// NotionOAuthUseCase представляет собой сценарий подключения Notion через OAuth: бот выдает ссылку на вход
+// с одноразовым параметром state, а обратный вызов Notion по этому state находит пользователя, обменивает код
+// на токен и настраивает интеграцию так же, как команда /notion с токеном
+type NotionOAuthUseCase struct {
+	userRepo                repository.UserRepository
+	stateRepo               repository.NotionOAuthStateRepository
+	oauthService            service.NotionOAuthService
+	notionProcessingUseCase *NotionProcessingUseCase
+	languageUseCase         *InterfaceLanguageUseCase
+	messenger               service.MessengerService
+	logger                  *logger.Logger
+}
+
+// NewNotionOAuthUseCase создает новый сценарий подключения Notion через OAuth.
+// oauthService равен nil, если OAuth не настроен: тогда Notion подключается токеном интеграции
+func NewNotionOAuthUseCase(
+	userRepo repository.UserRepository,
+	stateRepo repository.NotionOAuthStateRepository,
+	oauthService service.NotionOAuthService,
+	notionProcessingUseCase *NotionProcessingUseCase,
+	languageUseCase *InterfaceLanguageUseCase,
+	messenger service.MessengerService,
+	logger *logger.Logger,
+) *NotionOAuthUseCase {
+	return &NotionOAuthUseCase{
+		userRepo:                userRepo,
+		stateRepo:               stateRepo,
+		oauthService:            oauthService,
+		notionProcessingUseCase: notionProcessingUseCase,
+		languageUseCase:         languageUseCase,
+		messenger:               messenger,
+		logger:                  logger,
+	}
+}
+
+// Enabled сообщает, настроено ли подключение Notion через OAuth
+func (uc *NotionOAuthUseCase) Enabled() bool {
+	return uc.oauthService != nil
+}
+
+// HandleNotion обрабатывает команду /notion в личном чате и возвращает статус интеграции и кнопку входа в Notion
+func (uc *NotionOAuthUseCase) HandleNotion(ctx context.Context, telegramID int64) (string, [][]entity.MessageButton, error) {
+	// Логирование начала обработки команды /notion
+	uc.logger.Info("Handling /notion command with OAuth",
+		"telegram_id", telegramID,
+	)
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Выдача одноразового state для обратного вызова
+	state, err := generateNotionOAuthState()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate notion oauth state: %w", err)
+	}
+	if err := uc.stateRepo.Save(ctx, state, user.ID, notionOAuthStateTTL); err != nil {
+		uc.logger.Error("Failed to save Notion OAuth state",
+			"error", err,
+		)
+		return "", nil, err
+	}
+
+	buttons := [][]entity.MessageButton{{{Text: i18n.T(ctx, "notion_oauth.button"), URL: uc.oauthService.AuthorizeURL(state)}}}
+
+	// Если интеграция уже настроена, показываем ссылки на базу и предлагаем подключить другое пространство
+	if user.NotionDatabaseID != "" {
+		return formatNotionLinks(ctx, user) + "\n\n" + i18n.T(ctx, "notion_oauth.reconnect"), buttons, nil
+	}
+
+	text := i18n.T(ctx, "notion_oauth.instructions", int(notionOAuthStateTTL.Minutes()))
+
+	return text, buttons, nil
+}
+
+// Complete завершает вход в Notion по обратному вызову: находит пользователя по state, обменивает код
+// на токен, настраивает интеграцию и сообщает пользователю результат в Telegram.
+// Для неизвестного или истекшего state возвращается ErrNotionOAuthState
+func (uc *NotionOAuthUseCase) Complete(ctx context.Context, state, code string) error {
+	if uc.oauthService == nil {
+		return fmt.Errorf("notion oauth is not configured")
+	}
+
+	userID, err := uc.stateRepo.Pop(ctx, state)
+	if err != nil {
+		uc.logger.Error("Failed to get Notion OAuth state",
+			"error", err,
+		)
+		return err
+	}
+	if userID == 0 {
+		return ErrNotionOAuthState
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+			"user_id", userID,
+		)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	// Обратный вызов приходит от Notion, а не из Telegram: язык ответа берется из настроек пользователя
+	ctx = i18n.WithLanguage(ctx, uc.languageUseCase.ForUser(ctx, user))
+
+	if err := uc.connect(ctx, user, code); err != nil {
+		uc.notify(user, i18n.T(ctx, "notion_oauth.failed"))
+		return err
+	}
+
+	return nil
+}
+
+// connect обменивает код на токен, настраивает интеграцию и сообщает пользователю ссылки на базу
+func (uc *NotionOAuthUseCase) connect(ctx context.Context, user *entity.User, code string) error {
+	token, workspaceName, err := uc.oauthService.ExchangeCode(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.notionProcessingUseCase.SetupNotionIntegration(ctx, user.ID, token); err != nil {
+		uc.logger.Error("Failed to setup Notion integration",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return fmt.Errorf("failed to setup Notion integration: %w", err)
+	}
+
+	// Получение пользователя с созданными базой и страницей навигации
+	user, err = uc.userRepo.GetByID(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	text := i18n.T(ctx, "notion_oauth.connected")
+	if workspaceName != "" {
+		text += "\n\n" + i18n.T(ctx, "notion_oauth.workspace", workspaceName)
+	}
+	text += "\n" + i18n.T(ctx, "notion_oauth.database", notionURL(user.NotionDatabaseID)) +
+		"\n\n" + i18n.T(ctx, "notion_oauth.next")
+	uc.notify(user, text)
+
+	// Логирование успешной настройки интеграции с Notion
+	uc.logger.Info("Successfully connected Notion via OAuth",
+		"user_id", user.ID,
+	)
+
+	return nil
+}
+
+// notify отправляет пользователю сообщение о результате входа; ошибка отправки только логируется,
+// потому что результат показывает и страница обратного вызова
+func (uc *NotionOAuthUseCase) notify(user *entity.User, text string) {
+	if _, err := uc.messenger.SendMessage(user.TelegramID, text); err != nil {
+		uc.logger.Warn("Failed to send Notion OAuth result",
+			"error", err,
+			"user_id", user.ID,
+		)
+	}
+}
+
+// generateNotionOAuthState генерирует случайный параметр state входа в Notion
+func generateNotionOAuthState() (string, error) {
+	buf := make([]byte, notionOAuthStateBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
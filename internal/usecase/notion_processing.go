@@ -3,124 +3,383 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/112Alex/project_obsidian/internal/domain/entity"
 	"github.com/112Alex/project_obsidian/internal/domain/repository"
 	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
 	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
 )
 
+// notionUserLockKey - ключ блокировки записи в Notion пользователя; параметр - ID пользователя
+const notionUserLockKey = "lock:notion:user:%d"
+
 // NotionProcessingUseCase представляет собой сценарий обработки интеграции с Notion
 type NotionProcessingUseCase struct {
-	jobRepo       repository.JobRepository
-	userRepo      repository.UserRepository
-	notionService service.NotionService
-	logger        *logger.Logger
+	jobRepo              repository.JobRepository
+	userRepo             repository.UserRepository
+	sentimentRepo        repository.JobSentimentRepository
+	callRepo             repository.CallRecordingRepository
+	userSettingsUseCase  *UserSettingsUseCase
+	notionRoutingUseCase *NotionRoutingUseCase
+	notionService        service.NotionService
+	lockService          service.LockService
+	knowledgeBaseUseCase *KnowledgeBaseUseCase
+	languageUseCase      *InterfaceLanguageUseCase
+	lockWait             time.Duration
+	relatedNotes         int
+	logger               *logger.Logger
 }
 
-// NewNotionProcessingUseCase создает новый сценарий обработки интеграции с Notion
+// NewNotionProcessingUseCase создает новый сценарий обработки интеграции с Notion.
+// lockWait ограничивает ожидание, пока завершится запись другой задачи того же пользователя,
+// relatedNotes - сколько похожих прошлых заметок упоминается на новой странице (0 - не упоминать)
 func NewNotionProcessingUseCase(
 	jobRepo repository.JobRepository,
 	userRepo repository.UserRepository,
+	sentimentRepo repository.JobSentimentRepository,
+	callRepo repository.CallRecordingRepository,
+	userSettingsUseCase *UserSettingsUseCase,
+	notionRoutingUseCase *NotionRoutingUseCase,
 	notionService service.NotionService,
+	lockService service.LockService,
+	knowledgeBaseUseCase *KnowledgeBaseUseCase,
+	languageUseCase *InterfaceLanguageUseCase,
+	lockWait time.Duration,
+	relatedNotes int,
 	logger *logger.Logger,
 ) *NotionProcessingUseCase {
 	return &NotionProcessingUseCase{
-		jobRepo:       jobRepo,
-		userRepo:      userRepo,
-		notionService: notionService,
-		logger:        logger,
+		jobRepo:              jobRepo,
+		userRepo:             userRepo,
+		sentimentRepo:        sentimentRepo,
+		callRepo:             callRepo,
+		userSettingsUseCase:  userSettingsUseCase,
+		notionRoutingUseCase: notionRoutingUseCase,
+		notionService:        notionService,
+		lockService:          lockService,
+		knowledgeBaseUseCase: knowledgeBaseUseCase,
+		languageUseCase:      languageUseCase,
+		lockWait:             lockWait,
+		relatedNotes:         relatedNotes,
+		logger:               logger,
 	}
 }
 
-// ProcessNotionIntegration обрабатывает интеграцию с Notion
-func (uc *NotionProcessingUseCase) ProcessNotionIntegration(ctx context.Context, job entity.QueueJob) error {
-	// Получение данных из задачи
-	payload := job.Payload.(map[string]interface{})
-	transcription, ok := payload["transcription"].(string)
-	if !ok {
-		return fmt.Errorf("transcription not found in job payload or has invalid type")
+// NotionSyncTargetName - имя места синхронизации Notion
+const NotionSyncTargetName = "notion"
+
+// notionSyncTarget выгружает результаты задачи страницей в базу данных Notion пользователя
+type notionSyncTarget struct {
+	uc *NotionProcessingUseCase
+}
+
+// SyncTarget возвращает Notion как место синхронизации результатов задач
+func (uc *NotionProcessingUseCase) SyncTarget() service.SyncTarget {
+	return &notionSyncTarget{uc: uc}
+}
+
+// Name возвращает имя места синхронизации
+func (t *notionSyncTarget) Name() string {
+	return NotionSyncTargetName
+}
+
+// Enabled сообщает, настроена ли у пользователя интеграция с Notion
+func (t *notionSyncTarget) Enabled(user *entity.User) bool {
+	return user.NotionToken != "" && user.NotionDatabaseID != ""
+}
+
+// Sync создает страницу задачи в Notion и сохраняет ссылку на нее в задаче
+func (t *notionSyncTarget) Sync(ctx context.Context, user *entity.User, job *entity.Job) (string, error) {
+	pageID, databaseID, err := t.uc.createJobPage(ctx, user, job, job.Transcription, job.Summary)
+	if err != nil {
+		return "", err
 	}
 
-	summary, ok := payload["summary"].(string)
-	if !ok {
-		return fmt.Errorf("summary not found in job payload or has invalid type")
+	// Страница уже создана: ошибка сохранения ссылки не должна приводить к повторному созданию
+	err = t.uc.jobRepo.SetNotionIDs(ctx, job.ID, pageID, databaseID)
+	if err != nil {
+		t.uc.logger.Error("Failed to update job Notion IDs",
+			"error", err,
+			"notion_page_id", pageID,
+		)
 	}
 
-	userID := job.UserID
+	return pageID, nil
+}
 
-	// Логирование начала обработки интеграции с Notion
-	uc.logger.Info("Processing Notion integration",
-		"job_id", job.JobID,
-		"user_id", userID,
-	)
+// createJobPage создает страницу задачи в Notion и возвращает ID страницы и базы данных, в которую она сохранена
+func (uc *NotionProcessingUseCase) createJobPage(ctx context.Context, user *entity.User, job *entity.Job, transcription, summary string) (string, string, error) {
+	// Страница создается из очереди, а не в ответ на сообщение: язык подписей берется из настроек пользователя
+	ctx = i18n.WithLanguage(ctx, uc.languageUseCase.ForUser(ctx, user))
+	databaseID := user.NotionDatabaseID
+	pageTitle := i18n.T(ctx, "notion.page.title", job.CreatedAt.Format("02.01.2006 15:04"))
+	if job.Type == entity.JobTypePodcastEpisode {
+		pageTitle = job.FileName
+		if job.NotionDatabaseID != "" {
+			databaseID = job.NotionDatabaseID
+		}
+	}
+	if job.Type.IsMediaLink() && job.FileName != "" {
+		pageTitle = job.FileName
+	}
+	// Страница задачи без своей базы создается в базе правила /route, к теме которого относится запись
+	if databaseID == user.NotionDatabaseID {
+		if routed := uc.notionRoutingUseCase.DatabaseFor(ctx, user, job); routed != "" {
+			databaseID = routed
+		}
+	}
+	call := uc.jobCall(ctx, job.ID)
+	if call != nil {
+		pageTitle = callTitle(ctx, call)
+	}
 
-	// Получение пользователя
-	user, err := uc.userRepo.GetByTelegramID(ctx, userID)
+	// Записи в Notion одного пользователя выполняются по очереди: параллельные задачи одного
+	// пользователя иначе одновременно упираются в конфликты и лимит запросов его интеграции.
+	// Задачи разных пользователей по-прежнему выгружаются параллельно
+	unlock, err := uc.lockService.Lock(ctx, fmt.Sprintf(notionUserLockKey, user.ID), uc.lockWait)
 	if err != nil {
-		uc.logger.Error("Failed to get user",
+		uc.logger.Error("Failed to lock user Notion writes",
 			"error", err,
-			"user_id", userID,
+			"user_id", user.ID,
 		)
-		return fmt.Errorf("failed to get user: %w", err)
+		return "", "", fmt.Errorf("failed to lock user Notion writes: %w", err)
 	}
+	defer unlock()
 
-	// Проверка наличия Notion интеграции у пользователя
-	if user.NotionToken == "" || user.NotionDatabaseID == "" {
-		uc.logger.Warn("User has no Notion integration",
-			"user_id", userID,
-		)
-		// Обновление статуса задачи
-		err = uc.jobRepo.UpdateStatus(ctx, job.JobID, entity.JobStatusCompleted, "")
+	settings := uc.userSettingsUseCase.GetOrDefault(ctx, user.ID)
+	content := uc.pageContent(ctx, job, call, settings, transcription, summary)
+
+	// Прерванная выгрузка продолжается на уже созданной странице, чтобы не оставлять в базе недописанные копии
+	pageID, cursor := uc.resumableUpload(ctx, job)
+	if pageID == "" {
+		pageID, err = uc.notionService.CreatePage(ctx, databaseID, pageTitle)
 		if err != nil {
-			uc.logger.Error("Failed to update job status",
+			uc.logger.Error("Failed to create Notion page",
 				"error", err,
 			)
-			return fmt.Errorf("failed to update job status: %w", err)
+			return "", "", fmt.Errorf("failed to create Notion page: %w", err)
 		}
-		return nil
+		cursor = 0
+		uc.saveUpload(ctx, job.ID, pageID, cursor)
 	}
 
-	// Создание страницы в Notion
-	pageTitle := fmt.Sprintf("Транскрипция от %s", time.Now().Format("02.01.2006 15:04"))
-	// Формируем содержимое страницы, включая транскрипцию и суммаризацию
-	content := fmt.Sprintf("## Суммаризация\n\n%s\n\n## Полная транскрипция\n\n%s", summary, transcription)
-	pageID, err := uc.notionService.CreatePage(
-		ctx,
-		user.NotionDatabaseID,
-		pageTitle,
-		content,
+	// Содержимое добавляется частями; после каждой части запоминается, сколько блоков уже на странице
+	for {
+		next, done, err := uc.notionService.AppendMarkdown(ctx, pageID, content, cursor)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to upload Notion page content: %w", err)
+		}
+		if done {
+			break
+		}
+		cursor = next
+		uc.saveUpload(ctx, job.ID, pageID, cursor)
+	}
+
+	// Выгрузка завершена: ход выгрузки больше не нужен
+	uc.saveUpload(ctx, job.ID, "", 0)
+
+	// На странице только с кратким содержанием ссылок на похожие заметки тоже нет
+	if !settings.NotionSummaryOnly {
+		uc.linkRelatedNotes(ctx, job, pageID)
+	}
+
+	return pageID, databaseID, nil
+}
+
+// pageContent формирует содержимое страницы задачи с разделами, включенными в настройках пользователя.
+// Разделы глав видео ("### ...") в суммаризации становятся заголовками третьего уровня,
+// а разбор настроения встречи, если он составлен, - выноской после суммаризации. Страница задачи
+// по записи звонка начинается с данных о звонке. В транскрипции, разделенной по говорящим,
+// каждая реплика становится отдельным абзацем, а после суммаризации идет раздел с долей каждого говорящего
+func (uc *NotionProcessingUseCase) pageContent(ctx context.Context, job *entity.Job, call *entity.CallRecording, settings *entity.UserSettings, transcription, summary string) string {
+	summarySection := fmt.Sprintf("## %s\n\n%s", i18n.T(ctx, "notion.page.summary"), summary)
+	if settings.NotionSummaryOnly {
+		return summarySection
+	}
+
+	sections := make([]string, 0, 7)
+	if call != nil {
+		sections = append(sections, formatCallSection(ctx, call))
+	}
+	sections = append(sections, summarySection)
+	if sentiment := uc.jobSentiment(ctx, job.ID); sentiment != nil {
+		sections = append(sections, formatSentimentSection(ctx, sentiment))
+	}
+	// Фразы с говорящими относятся к исходной транскрипции задачи, а не к переданному тексту
+	diarized := speakerCount(job.Segments) > 0 && transcription == job.Transcription
+	if diarized && speakerCount(job.Segments) > 1 {
+		sections = append(sections, formatSpeakersSection(ctx, job.Segments))
+	}
+	if settings.NotionAudioStats {
+		sections = append(sections, formatAudioStatsSection(ctx, job, transcription))
+	}
+	if settings.NotionUsageCost && job.SummaryModel != "" {
+		sections = append(sections, formatUsageCostSection(ctx, job))
+	}
+	if settings.NotionTranscript {
+		if diarized {
+			transcription = strings.ReplaceAll(transcription, "\n", "\n\n")
+		}
+		sections = append(sections, fmt.Sprintf("## %s\n\n%s", i18n.T(ctx, "notion.page.transcription"), transcription))
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+// formatAudioStatsSection формирует раздел страницы задачи со сведениями о записи: длительностью,
+// примененным профилем предобработки и темпом речи по числу слов транскрипции
+func formatAudioStatsSection(ctx context.Context, job *entity.Job, transcription string) string {
+	lines := []string{"## " + i18n.T(ctx, "notion.page.audio"), ""}
+	if job.Duration > 0 {
+		lines = append(lines, "- "+i18n.T(ctx, "notion.page.audio.duration", textutil.FormatTimestamp(int(job.Duration))))
+	}
+	if name, ok := audioProfileNames[job.AudioProfile]; ok {
+		lines = append(lines, "- "+i18n.T(ctx, "notion.page.audio.profile", i18n.T(ctx, name)))
+	}
+	words := len(strings.Fields(transcription))
+	lines = append(lines, "- "+i18n.T(ctx, "notion.page.audio.words", words))
+	if minutes := job.Duration / 60; minutes >= 1 {
+		lines = append(lines, "- "+i18n.T(ctx, "notion.page.audio.pace", float64(words)/minutes))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatUsageCostSection формирует раздел страницы задачи с моделью, токенами и стоимостью суммаризации
+func formatUsageCostSection(ctx context.Context, job *entity.Job) string {
+	lines := []string{
+		"## " + i18n.T(ctx, "notion.page.cost"),
+		"",
+		"- " + i18n.T(ctx, "notion.page.cost.model", job.SummaryModel),
+		"- " + i18n.T(ctx, "notion.page.cost.tokens", job.SummaryTokens),
+		"- " + i18n.T(ctx, "notion.page.cost.amount", job.SummaryCost),
+	}
+	return strings.Join(lines, "\n")
+}
+
+// linkRelatedNotes добавляет на страницу задачи упоминания похожих прошлых заметок, связывая отдельные
+// транскрипции в базу знаний. Без связанных заметок страница остается полноценной, поэтому ошибки только логируются
+func (uc *NotionProcessingUseCase) linkRelatedNotes(ctx context.Context, job *entity.Job, pageID string) {
+	if uc.relatedNotes <= 0 {
+		return
+	}
+
+	related, err := uc.knowledgeBaseUseCase.RelatedJobs(ctx, job, uc.relatedNotes)
+	if err != nil || len(related) == 0 {
+		return
+	}
+
+	pageIDs := make([]string, len(related))
+	for i, relatedJob := range related {
+		pageIDs[i] = relatedJob.NotionPageID
+	}
+
+	if err := uc.notionService.AppendRelatedPages(ctx, pageID, pageIDs); err != nil {
+		uc.logger.Warn("Failed to link related notes",
+			"error", err,
+			"job_id", job.ID,
+		)
+		return
+	}
+
+	// Логирование добавления связанных заметок
+	uc.logger.Info("Linked related notes",
+		"job_id", job.ID,
+		"related", len(pageIDs),
 	)
+}
+
+// jobSentiment возвращает разбор настроения встречи задачи или nil. Без разбора страница выгружается
+// как обычно, поэтому ошибка чтения только логируется
+func (uc *NotionProcessingUseCase) jobSentiment(ctx context.Context, jobID int64) *entity.JobSentiment {
+	sentiment, err := uc.sentimentRepo.GetByJobID(ctx, jobID)
 	if err != nil {
-		uc.logger.Error("Failed to create Notion page",
+		uc.logger.Warn("Failed to get job sentiment",
 			"error", err,
+			"job_id", jobID,
 		)
-		return fmt.Errorf("failed to create Notion page: %w", err)
+		return nil
 	}
 
-	// Обновление задачи в базе данных
-	err = uc.jobRepo.SetNotionIDs(ctx, job.JobID, pageID, user.NotionDatabaseID)
+	return sentiment
+}
+
+// jobCall возвращает звонок, по записи которого создана задача. Ошибка чтения не мешает выгрузке:
+// страница создается без данных о звонке
+func (uc *NotionProcessingUseCase) jobCall(ctx context.Context, jobID int64) *entity.CallRecording {
+	call, err := uc.callRepo.GetByJobID(ctx, jobID)
 	if err != nil {
-		uc.logger.Error("Failed to update job Notion IDs",
+		uc.logger.Warn("Failed to get job call",
+			"error", err,
+			"job_id", jobID,
+		)
+		return nil
+	}
+
+	return call
+}
+
+// resumableUpload возвращает страницу прерванной выгрузки задачи и число уже добавленных на нее блоков.
+// Если выгрузка не прерывалась или пользователь удалил недописанную страницу, возвращается пустой ID
+func (uc *NotionProcessingUseCase) resumableUpload(ctx context.Context, job *entity.Job) (string, int) {
+	if job.NotionUploadPageID == "" {
+		return "", 0
+	}
+
+	exists, err := uc.notionService.PageExists(ctx, job.NotionUploadPageID)
+	if err != nil || !exists {
+		uc.logger.Warn("Restarting interrupted Notion upload with a new page",
 			"error", err,
+			"job_id", job.ID,
+			"notion_page_id", job.NotionUploadPageID,
 		)
-		return fmt.Errorf("failed to update job Notion IDs: %w", err)
+		return "", 0
 	}
 
-	// Обновление статуса задачи
-	err = uc.jobRepo.UpdateStatus(ctx, job.JobID, entity.JobStatusCompleted, "")
+	// Логирование продолжения выгрузки
+	uc.logger.Info("Resuming interrupted Notion upload",
+		"job_id", job.ID,
+		"notion_page_id", job.NotionUploadPageID,
+		"cursor", job.NotionUploadCursor,
+	)
+
+	return job.NotionUploadPageID, job.NotionUploadCursor
+}
+
+// saveUpload сохраняет ход выгрузки задачи в Notion. Ошибка сохранения только логируется: выгрузка
+// продолжается, а после следующего сбоя часть содержимого может добавиться на страницу повторно
+func (uc *NotionProcessingUseCase) saveUpload(ctx context.Context, jobID int64, pageID string, cursor int) {
+	err := uc.jobRepo.SetNotionUpload(ctx, jobID, pageID, cursor)
 	if err != nil {
-		uc.logger.Error("Failed to update job status",
+		uc.logger.Warn("Failed to save Notion upload progress",
 			"error", err,
+			"job_id", jobID,
+			"cursor", cursor,
 		)
-		return fmt.Errorf("failed to update job status: %w", err)
 	}
+}
 
-	// Логирование успешной обработки интеграции с Notion
-	uc.logger.Info("Notion integration processed successfully",
-		"job_id", job.JobID,
-		"notion_page_id", pageID,
+// ArchiveJobPages перемещает в корзину Notion страницу задачи и страницу, выгрузка на которую была прервана
+func (uc *NotionProcessingUseCase) ArchiveJobPages(ctx context.Context, job *entity.Job) error {
+	pageIDs := []string{job.NotionPageID}
+	if job.NotionUploadPageID != job.NotionPageID {
+		pageIDs = append(pageIDs, job.NotionUploadPageID)
+	}
+	for _, pageID := range pageIDs {
+		if pageID == "" {
+			continue
+		}
+		if err := uc.notionService.ArchivePage(ctx, pageID); err != nil {
+			return err
+		}
+	}
+
+	uc.logger.Info("Archived Notion pages of job",
+		"job_id", job.ID,
 	)
 
 	return nil
@@ -134,19 +393,21 @@ func (uc *NotionProcessingUseCase) SetupNotionIntegration(ctx context.Context, u
 	)
 
 	// Получение пользователя из базы данных
-	user, err := uc.userRepo.GetByTelegramID(ctx, userID)
+	user, err := uc.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		uc.logger.Error("Failed to get user",
 			"error", err,
 		)
 		return fmt.Errorf("failed to get user: %w", err)
 	}
+	// Названия базы и страницы навигации - на языке пользователя
+	ctx = i18n.WithLanguage(ctx, uc.languageUseCase.ForUser(ctx, user))
 
 	// Создание базы данных в Notion
 	databaseID, err := uc.notionService.CreateDatabase(
 		ctx,
 		user.ID,
-		"Транскрипции аудио",
+		i18n.T(ctx, "notion.database.title"),
 	)
 	if err != nil {
 		uc.logger.Error("Failed to create Notion database",
@@ -155,9 +416,20 @@ func (uc *NotionProcessingUseCase) SetupNotionIntegration(ctx context.Context, u
 		return fmt.Errorf("failed to create Notion database: %w", err)
 	}
 
+	// Создание страницы навигации по представлениям базы.
+	// Без нее интеграция работает, поэтому ошибку только логируем
+	hubPageID, err := uc.notionService.CreateViewsPage(ctx, databaseID)
+	if err != nil {
+		uc.logger.Warn("Failed to create Notion views page",
+			"error", err,
+			"notion_database_id", databaseID,
+		)
+	}
+
 	// Обновление пользователя в базе данных
 	user.NotionToken = notionToken
 	user.NotionDatabaseID = databaseID
+	user.NotionHubPageID = hubPageID
 
 	err = uc.userRepo.Update(ctx, user)
 	if err != nil {
@@ -175,3 +447,8 @@ func (uc *NotionProcessingUseCase) SetupNotionIntegration(ctx context.Context, u
 
 	return nil
 }
+
+// notionURL возвращает ссылку на страницу или базу данных Notion по ее ID
+func notionURL(id string) string {
+	return "https://www.notion.so/" + strings.ReplaceAll(id, "-", "")
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// notionReconcileBatchSize - сколько задач загружается из базы за один запрос при сверке
+const notionReconcileBatchSize = 100
+
+// NotionReconcileUseCase представляет собой сценарий сверки задач в PostgreSQL со страницами Notion.
+// Завершенным задачам без страницы страница создается заново, а ссылки на страницы,
+// удаленные пользователем, очищаются
+type NotionReconcileUseCase struct {
+	jobRepo                 repository.JobRepository
+	userRepo                repository.UserRepository
+	notionService           service.NotionService
+	notionProcessingUseCase *NotionProcessingUseCase
+	interval                time.Duration
+	logger                  *logger.Logger
+
+	running sync.Mutex
+}
+
+// NewNotionReconcileUseCase создает новый сценарий сверки задач с Notion.
+// interval <= 0 отключает сверку по расписанию
+func NewNotionReconcileUseCase(
+	jobRepo repository.JobRepository,
+	userRepo repository.UserRepository,
+	notionService service.NotionService,
+	notionProcessingUseCase *NotionProcessingUseCase,
+	interval time.Duration,
+	logger *logger.Logger,
+) *NotionReconcileUseCase {
+	return &NotionReconcileUseCase{
+		jobRepo:                 jobRepo,
+		userRepo:                userRepo,
+		notionService:           notionService,
+		notionProcessingUseCase: notionProcessingUseCase,
+		interval:                interval,
+		logger:                  logger,
+	}
+}
+
+// Reconcile сверяет все завершенные задачи пользователей с настроенным Notion.
+// Ошибка отдельной задачи учитывается в итогах и не прерывает сверку
+func (uc *NotionReconcileUseCase) Reconcile(ctx context.Context) (*entity.NotionReconcileResult, error) {
+	// Сверка по команде и по расписанию не должны выполняться одновременно
+	if !uc.running.TryLock() {
+		return nil, fmt.Errorf("notion reconcile is already running")
+	}
+	defer uc.running.Unlock()
+
+	// Логирование начала сверки
+	uc.logger.Info("Reconciling jobs with Notion")
+
+	startedAt := time.Now()
+	result := &entity.NotionReconcileResult{}
+	users := make(map[int64]*entity.User)
+
+	var afterID int64
+	for {
+		jobs, err := uc.jobRepo.GetForNotionReconcile(ctx, afterID, notionReconcileBatchSize)
+		if err != nil {
+			uc.logger.Error("Failed to get jobs for Notion reconcile",
+				"error", err,
+			)
+			return nil, fmt.Errorf("failed to get jobs for Notion reconcile: %w", err)
+		}
+
+		for _, job := range jobs {
+			afterID = job.ID
+			result.Checked++
+
+			if err := uc.reconcileJob(ctx, users, job, result); err != nil {
+				result.Failed++
+				uc.logger.Error("Failed to reconcile job with Notion",
+					"error", err,
+					"job_id", job.ID,
+				)
+			}
+		}
+
+		if len(jobs) < notionReconcileBatchSize {
+			break
+		}
+	}
+
+	// Логирование успешной сверки
+	uc.logger.Info("Jobs reconciled with Notion",
+		"checked", result.Checked,
+		"recreated", result.Recreated,
+		"cleared", result.Cleared,
+		"failed", result.Failed,
+		"elapsed", time.Since(startedAt),
+	)
+
+	return result, nil
+}
+
+// reconcileJob восстанавливает связь одной задачи со страницей Notion
+func (uc *NotionReconcileUseCase) reconcileJob(ctx context.Context, users map[int64]*entity.User, job *entity.Job, result *entity.NotionReconcileResult) error {
+	if job.NotionPageID != "" {
+		exists, err := uc.notionService.PageExists(ctx, job.NotionPageID)
+		if err != nil {
+			return fmt.Errorf("failed to check Notion page: %w", err)
+		}
+		if exists {
+			return nil
+		}
+
+		// Пользователь удалил страницу сам, поэтому не создаем ее заново, а только убираем ссылку
+		if err := uc.jobRepo.ClearNotionPage(ctx, job.ID); err != nil {
+			return fmt.Errorf("failed to clear Notion page: %w", err)
+		}
+		result.Cleared++
+
+		uc.logger.Info("Stale Notion page cleared",
+			"job_id", job.ID,
+			"notion_page_id", job.NotionPageID,
+		)
+		return nil
+	}
+
+	// Без текста страницу создавать не из чего
+	if job.Transcription == "" && job.Summary == "" {
+		return nil
+	}
+
+	// Получение пользователя; пользователи кэшируются на время сверки
+	user, ok := users[job.UserID]
+	if !ok {
+		var err error
+		user, err = uc.userRepo.GetByID(ctx, job.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		users[job.UserID] = user
+	}
+
+	// Создание недостающей страницы
+	pageID, databaseID, err := uc.notionProcessingUseCase.createJobPage(ctx, user, job, job.Transcription, job.Summary)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.jobRepo.SetNotionIDs(ctx, job.ID, pageID, databaseID); err != nil {
+		return fmt.Errorf("failed to update job Notion IDs: %w", err)
+	}
+	result.Recreated++
+
+	uc.logger.Info("Missing Notion page created",
+		"job_id", job.ID,
+		"notion_page_id", pageID,
+	)
+
+	return nil
+}
+
+// RunSchedule сверяет задачи с Notion по расписанию до отмены контекста
+func (uc *NotionReconcileUseCase) RunSchedule(ctx context.Context) {
+	if uc.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(uc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := uc.Reconcile(ctx); err != nil {
+				uc.logger.Error("Scheduled Notion reconcile failed",
+					"error", err,
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Ограничения правил выгрузки в Notion по темам
+const (
+	maxNotionRoutes        = 10
+	maxNotionRouteCategory = 64
+)
+
+// notionIDPattern находит ID базы Notion в ссылке на нее или в самом ID, с дефисами или без
+var notionIDPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}`)
+
+// NotionRoutingUseCase представляет собой сценарий выгрузки в Notion по темам записей: пользователь
+// задает правила «тема → база» командой /route, а при создании страницы задачи классификатор относит
+// краткое содержание к одной из тем правил. Страницы задач без подходящей темы попадают в основную базу
+type NotionRoutingUseCase struct {
+	userRepo             repository.UserRepository
+	jobRepo              repository.JobRepository
+	routeRepo            repository.NotionRouteRepository
+	summarizationService service.SummarizationService
+	notionService        service.NotionService
+	logger               *logger.Logger
+}
+
+// NewNotionRoutingUseCase создает новый сценарий выгрузки в Notion по темам записей
+func NewNotionRoutingUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	routeRepo repository.NotionRouteRepository,
+	summarizationService service.SummarizationService,
+	notionService service.NotionService,
+	logger *logger.Logger,
+) *NotionRoutingUseCase {
+	return &NotionRoutingUseCase{
+		userRepo:             userRepo,
+		jobRepo:              jobRepo,
+		routeRepo:            routeRepo,
+		summarizationService: summarizationService,
+		notionService:        notionService,
+		logger:               logger,
+	}
+}
+
+// HandleRoute обрабатывает команду /route: показывает правила, добавляет и удаляет их
+// и показывает, в какую базу попадет страница задачи, ничего не выгружая
+func (uc *NotionRoutingUseCase) HandleRoute(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /route
+	uc.logger.Info("Handling /route command",
+		"telegram_id", telegramID,
+	)
+
+	usage := i18n.T(ctx, "route.usage")
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.NotionToken == "" || user.NotionDatabaseID == "" {
+		return i18n.T(ctx, "job_action.notion_not_connected"), nil
+	}
+
+	routes, err := uc.routeRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	action, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	rest = strings.TrimSpace(rest)
+	switch strings.ToLower(action) {
+	case "":
+		return formatNotionRoutes(ctx, routes) + "\n\n" + usage, nil
+	case "add":
+		return uc.addRoute(ctx, user, routes, rest, usage)
+	case "del":
+		number, err := strconv.Atoi(rest)
+		if err != nil || number < 1 || number > len(routes) {
+			return usage, nil
+		}
+		route := routes[number-1]
+		if _, err := uc.routeRepo.Delete(ctx, user.ID, route.ID); err != nil {
+			return "", err
+		}
+		return i18n.T(ctx, "route.deleted", route.Category), nil
+	case "test":
+		jobID, err := strconv.ParseInt(rest, 10, 64)
+		if err != nil {
+			return usage, nil
+		}
+		return uc.preview(ctx, user, routes, jobID)
+	}
+
+	return usage, nil
+}
+
+// addRoute добавляет правило «тема → база». Если ссылка на базу не указана, база создается
+// с теми же колонками, что и основная база пользователя
+func (uc *NotionRoutingUseCase) addRoute(ctx context.Context, user *entity.User, routes []*entity.NotionRoute, args string, usage string) (string, error) {
+	fields := strings.Fields(args)
+	databaseID := ""
+	if len(fields) > 1 {
+		databaseID = parseNotionID(fields[len(fields)-1])
+		if databaseID != "" {
+			fields = fields[:len(fields)-1]
+		}
+	}
+	category := strings.Join(fields, " ")
+	if category == "" {
+		return usage, nil
+	}
+	if utf8.RuneCountInString(category) > maxNotionRouteCategory {
+		return i18n.T(ctx, "route.category_too_long", maxNotionRouteCategory), nil
+	}
+	if len(routes) >= maxNotionRoutes {
+		return i18n.T(ctx, "route.too_many", maxNotionRoutes), nil
+	}
+	for _, route := range routes {
+		if strings.EqualFold(route.Category, category) {
+			return i18n.T(ctx, "route.exists", route.Category), nil
+		}
+	}
+
+	created := databaseID == ""
+	if created {
+		var err error
+		databaseID, err = uc.notionService.CreateDatabase(ctx, user.ID, "Транскрипции: "+category)
+		if err != nil {
+			uc.logger.Error("Failed to create Notion database for route",
+				"error", err,
+				"user_id", user.ID,
+			)
+			return "", fmt.Errorf("failed to create Notion database for route: %w", err)
+		}
+	}
+
+	route := &entity.NotionRoute{
+		UserID:     user.ID,
+		Category:   category,
+		DatabaseID: databaseID,
+	}
+	if err := uc.routeRepo.Create(ctx, route); err != nil {
+		uc.logger.Error("Failed to create notion route",
+			"error", err,
+		)
+		return "", err
+	}
+
+	// Логирование добавленного правила
+	uc.logger.Info("Notion route added",
+		"user_id", user.ID,
+		"route_id", route.ID,
+		"created_database", created,
+	)
+
+	text := i18n.T(ctx, "route.added", category, notionURL(databaseID))
+	if !created {
+		text += "\n\n" + i18n.T(ctx, "route.added_existing")
+	}
+	return text, nil
+}
+
+// preview показывает, к какой теме классификатор отнесет задачу и в какую базу попадет ее страница
+func (uc *NotionRoutingUseCase) preview(ctx context.Context, user *entity.User, routes []*entity.NotionRoute, jobID int64) (string, error) {
+	if len(routes) == 0 {
+		return i18n.T(ctx, "route.test_no_routes"), nil
+	}
+
+	// Чужие задачи считаем несуществующими
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "job.not_found"), nil
+	}
+	if job.Summary == "" && job.Transcription == "" {
+		return i18n.T(ctx, "route.test_no_summary"), nil
+	}
+
+	route, err := uc.match(ctx, job, routes)
+	if err != nil {
+		return "", err
+	}
+	if route == nil {
+		return i18n.T(ctx, "route.test_default", job.ID, notionURL(user.NotionDatabaseID)), nil
+	}
+	return i18n.T(ctx, "route.test_matched", job.ID, route.Category, notionURL(route.DatabaseID)), nil
+}
+
+// DatabaseFor возвращает базу, в которой нужно создать страницу задачи по правилам пользователя,
+// или пустую строку, если подходящего правила нет. Ошибка классификатора не мешает выгрузке:
+// страница тогда создается в основной базе
+func (uc *NotionRoutingUseCase) DatabaseFor(ctx context.Context, user *entity.User, job *entity.Job) string {
+	routes, err := uc.routeRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		uc.logger.Warn("Failed to get notion routes",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return ""
+	}
+	if len(routes) == 0 {
+		return ""
+	}
+
+	route, err := uc.match(ctx, job, routes)
+	if err != nil {
+		uc.logger.Warn("Failed to route job to Notion database",
+			"error", err,
+			"job_id", job.ID,
+		)
+		return ""
+	}
+	if route == nil {
+		return ""
+	}
+
+	// Логирование выбранного правила
+	uc.logger.Info("Job routed to Notion database",
+		"job_id", job.ID,
+		"route_id", route.ID,
+	)
+
+	return route.DatabaseID
+}
+
+// match определяет тему задачи по краткому содержанию (без него - по транскрипции) и возвращает
+// правило этой темы или nil, если ни одна тема не подходит
+func (uc *NotionRoutingUseCase) match(ctx context.Context, job *entity.Job, routes []*entity.NotionRoute) (*entity.NotionRoute, error) {
+	text := job.Summary
+	if text == "" {
+		text = job.Transcription
+	}
+
+	topics := make([]string, len(routes))
+	for i, route := range routes {
+		topics[i] = route.Category
+	}
+
+	topic, err := uc.summarizationService.ClassifyTopic(ctx, text, topics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to classify job topic: %w", err)
+	}
+	for _, route := range routes {
+		if route.Category == topic {
+			return route, nil
+		}
+	}
+	return nil, nil
+}
+
+// formatNotionRoutes формирует нумерованный список правил выгрузки в Notion по темам
+func formatNotionRoutes(ctx context.Context, routes []*entity.NotionRoute) string {
+	if len(routes) == 0 {
+		return i18n.T(ctx, "route.list_empty")
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString(i18n.T(ctx, "route.list") + "\n")
+	for i, route := range routes {
+		builder.WriteString("\n" + i18n.T(ctx, "route.list_item", i+1, route.Category, notionURL(route.DatabaseID)))
+	}
+	return builder.String()
+}
+
+// parseNotionID возвращает ID базы Notion из ссылки на нее или из самого ID. Параметры ссылки
+// отбрасываются: в них может быть ID представления базы
+func parseNotionID(value string) string {
+	value, _, _ = strings.Cut(value, "?")
+	return strings.ReplaceAll(notionIDPattern.FindString(value), "-", "")
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// attachmentTokenBytes - длина токена адреса фотографии в байтах
+const attachmentTokenBytes = 16
+
+// attachmentJobPattern находит в подписи к фотографии ссылку на задачу: «к задаче 42», «задача №42», «job 42»
+var attachmentJobPattern = regexp.MustCompile(`(?i)(?:задач[аеиу]?|job)\s*(?:№|#)?\s*(\d+)`)
+
+// attachmentNotionStatus - результат добавления фотографии на страницу задачи в Notion
+type attachmentNotionStatus int
+
+const (
+	attachmentNotionAdded  attachmentNotionStatus = iota // Фотография добавлена на страницу
+	attachmentNotionNoPage                               // У задачи еще нет страницы в Notion
+	attachmentNotionFailed                               // Notion вернул ошибку
+)
+
+// PhotoAttachmentUseCase представляет собой сценарий приложения фотографий к задачам:
+// текст на фотографии распознается и вместе с изображением добавляется на страницу задачи в Notion
+type PhotoAttachmentUseCase struct {
+	attachmentRepo repository.JobAttachmentRepository
+	ocrService     service.OCRService
+	notionService  service.NotionService
+	publicURL      string
+	logger         *logger.Logger
+}
+
+// NewPhotoAttachmentUseCase создает новый сценарий приложения фотографий к задачам
+func NewPhotoAttachmentUseCase(
+	attachmentRepo repository.JobAttachmentRepository,
+	ocrService service.OCRService,
+	notionService service.NotionService,
+	publicURL string,
+	logger *logger.Logger,
+) *PhotoAttachmentUseCase {
+	return &PhotoAttachmentUseCase{
+		attachmentRepo: attachmentRepo,
+		ocrService:     ocrService,
+		notionService:  notionService,
+		publicURL:      publicURL,
+		logger:         logger,
+	}
+}
+
+// IsPhotoAttachmentCaption сообщает, что подпись к фотографии прикладывает ее к задаче
+func IsPhotoAttachmentCaption(caption string) bool {
+	_, ok := attachmentJobID(caption)
+	return ok
+}
+
+// attachmentJobID возвращает ID задачи, указанной в подписи к фотографии
+func attachmentJobID(caption string) (int64, bool) {
+	match := attachmentJobPattern.FindStringSubmatch(caption)
+	if match == nil {
+		return 0, false
+	}
+
+	jobID, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return jobID, true
+}
+
+// AttachPhoto распознает текст на фотографии, сохраняет ее и добавляет на страницу задачи в Notion.
+// Принадлежность задачи пользователю проверяет вызывающий. Ошибка Notion не считается ошибкой
+// приложения: фотография и текст к этому моменту уже сохранены
+func (uc *PhotoAttachmentUseCase) AttachPhoto(ctx context.Context, job *entity.Job, caption string, image []byte, contentType string) (*entity.JobAttachment, attachmentNotionStatus, error) {
+	// Логирование начала приложения фотографии
+	uc.logger.Info("Attaching photo to job",
+		"job_id", job.ID,
+		"image_size", len(image),
+	)
+
+	// Распознавание текста
+	text, err := uc.ocrService.RecognizeText(ctx, image, contentType)
+	if err != nil {
+		uc.logger.Error("Failed to recognize text on photo",
+			"error", err,
+		)
+		return nil, attachmentNotionFailed, fmt.Errorf("failed to recognize text on photo: %w", err)
+	}
+
+	token, err := generateAttachmentToken()
+	if err != nil {
+		return nil, attachmentNotionFailed, fmt.Errorf("failed to generate attachment token: %w", err)
+	}
+
+	attachment := &entity.JobAttachment{
+		JobID:       job.ID,
+		Token:       token,
+		ContentType: contentType,
+		Image:       image,
+		OCRText:     text,
+	}
+	err = uc.attachmentRepo.Create(ctx, attachment)
+	if err != nil {
+		uc.logger.Error("Failed to save job attachment",
+			"error", err,
+		)
+		return nil, attachmentNotionFailed, fmt.Errorf("failed to save job attachment: %w", err)
+	}
+
+	// Страница Notion появляется только после обработки задачи
+	if job.NotionPageID == "" {
+		return attachment, attachmentNotionNoPage, nil
+	}
+
+	err = uc.notionService.AppendImage(ctx, job.NotionPageID, uc.AttachmentURL(token), caption, text)
+	if err != nil {
+		uc.logger.Error("Failed to append photo to Notion page",
+			"error", err,
+			"job_id", job.ID,
+		)
+		return attachment, attachmentNotionFailed, nil
+	}
+
+	// Логирование успешного приложения фотографии
+	uc.logger.Info("Photo attached to job",
+		"job_id", job.ID,
+		"attachment_id", attachment.ID,
+		"text_length", len(text),
+	)
+
+	return attachment, attachmentNotionAdded, nil
+}
+
+// GetAttachment возвращает фотографию по токену ее адреса
+func (uc *PhotoAttachmentUseCase) GetAttachment(ctx context.Context, token string) (*entity.JobAttachment, error) {
+	attachment, err := uc.attachmentRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// AttachmentURL формирует публичный адрес фотографии по её токену
+func (uc *PhotoAttachmentUseCase) AttachmentURL(token string) string {
+	return fmt.Sprintf("%s/attachments/%s", uc.publicURL, token)
+}
+
+// generateAttachmentToken генерирует случайный токен для адреса фотографии
+func generateAttachmentToken() (string, error) {
+	buf := make([]byte, attachmentTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
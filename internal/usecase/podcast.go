@@ -0,0 +1,322 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// maxEpisodesPerPoll - сколько новых выпусков одной ленты обрабатывается за проверку,
+// чтобы лента с архивом не заняла очередь целиком
+const maxEpisodesPerPoll = 3
+
+// PodcastUseCase представляет собой сценарий подписки на подкасты и обработки новых выпусков
+type PodcastUseCase struct {
+	userRepo               repository.UserRepository
+	podcastRepo            repository.PodcastRepository
+	podcastService         service.PodcastService
+	notionService          service.NotionService
+	audioProcessingUseCase *AudioProcessingUseCase
+	pollInterval           time.Duration
+	logger                 *logger.Logger
+}
+
+// NewPodcastUseCase создает новый сценарий подписки на подкасты
+func NewPodcastUseCase(
+	userRepo repository.UserRepository,
+	podcastRepo repository.PodcastRepository,
+	podcastService service.PodcastService,
+	notionService service.NotionService,
+	audioProcessingUseCase *AudioProcessingUseCase,
+	pollInterval time.Duration,
+	logger *logger.Logger,
+) *PodcastUseCase {
+	return &PodcastUseCase{
+		userRepo:               userRepo,
+		podcastRepo:            podcastRepo,
+		podcastService:         podcastService,
+		notionService:          notionService,
+		audioProcessingUseCase: audioProcessingUseCase,
+		pollInterval:           pollInterval,
+		logger:                 logger,
+	}
+}
+
+// AddFeed подписывает пользователя на ленту. Уже опубликованные выпуски, кроме последнего,
+// отмечаются как учтенные, чтобы не обрабатывать весь архив подкаста
+func (uc *PodcastUseCase) AddFeed(ctx context.Context, telegramID int64, feedURL string) (*entity.PodcastFeed, error) {
+	// Логирование начала подписки
+	uc.logger.Info("Adding podcast feed",
+		"telegram_id", telegramID,
+		"url", feedURL,
+	)
+
+	parsed, err := url.Parse(feedURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid feed URL")
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Загрузка ленты: заодно проверяем, что по ссылке действительно подкаст
+	title, episodes, err := uc.podcastService.FetchFeed(ctx, feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch podcast feed: %w", err)
+	}
+	if title == "" {
+		title = parsed.Host
+	}
+
+	feed := &entity.PodcastFeed{
+		UserID: user.ID,
+		URL:    feedURL,
+		Title:  title,
+	}
+
+	// Создание отдельной базы Notion для выпусков подкаста.
+	// Без нее выпуски сохраняются в основную базу, поэтому ошибку только логируем
+	if user.NotionToken != "" && user.NotionDatabaseID != "" {
+		databaseID, err := uc.notionService.CreateDatabase(ctx, user.ID, "Подкаст: "+title)
+		if err != nil {
+			uc.logger.Warn("Failed to create Notion database for podcast",
+				"error", err,
+				"user_id", user.ID,
+			)
+		}
+		feed.NotionDatabaseID = databaseID
+	}
+
+	// Сохранение подписки
+	err = uc.podcastRepo.CreateFeed(ctx, feed)
+	if err != nil {
+		uc.logger.Error("Failed to create podcast feed",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to create podcast feed: %w", err)
+	}
+
+	// Отметка архивных выпусков как учтенных. Архив может насчитывать сотни выпусков,
+	// поэтому они сохраняются одним пакетом
+	if len(episodes) > 1 {
+		archive := episodes[1:]
+		for _, episode := range archive {
+			episode.FeedID = feed.ID
+			episode.UserID = user.ID
+		}
+		if _, err := uc.podcastRepo.AddEpisodes(ctx, archive); err != nil {
+			uc.logger.Error("Failed to add podcast episodes",
+				"error", err,
+			)
+			return nil, fmt.Errorf("failed to add podcast episodes: %w", err)
+		}
+	}
+
+	// Логирование успешной подписки
+	uc.logger.Info("Podcast feed added successfully",
+		"feed_id", feed.ID,
+		"user_id", user.ID,
+		"episodes", len(episodes),
+	)
+
+	return feed, nil
+}
+
+// ListFeeds возвращает подписки пользователя
+func (uc *PodcastUseCase) ListFeeds(ctx context.Context, telegramID int64) ([]*entity.PodcastFeed, error) {
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	feeds, err := uc.podcastRepo.GetFeedsByUserID(ctx, user.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get podcast feeds",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get podcast feeds: %w", err)
+	}
+
+	return feeds, nil
+}
+
+// RemoveFeed отписывает пользователя от ленты и сообщает, была ли подписка найдена
+func (uc *PodcastUseCase) RemoveFeed(ctx context.Context, telegramID int64, feedID int64) (bool, error) {
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	removed, err := uc.podcastRepo.DeleteFeed(ctx, user.ID, feedID)
+	if err != nil {
+		uc.logger.Error("Failed to delete podcast feed",
+			"error", err,
+		)
+		return false, fmt.Errorf("failed to delete podcast feed: %w", err)
+	}
+
+	// Логирование успешной отписки
+	uc.logger.Info("Podcast feed removed",
+		"feed_id", feedID,
+		"user_id", user.ID,
+		"removed", removed,
+	)
+
+	return removed, nil
+}
+
+// RunPolling проверяет ленты на новые выпуски по расписанию до отмены контекста
+func (uc *PodcastUseCase) RunPolling(ctx context.Context) {
+	ticker := time.NewTicker(uc.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.pollFeeds(ctx)
+		}
+	}
+}
+
+// pollFeeds проверяет все ленты; ошибка одной ленты не мешает проверке остальных
+func (uc *PodcastUseCase) pollFeeds(ctx context.Context) {
+	feeds, err := uc.podcastRepo.GetAllFeeds(ctx)
+	if err != nil {
+		uc.logger.Error("Failed to get podcast feeds",
+			"error", err,
+		)
+		return
+	}
+
+	for _, feed := range feeds {
+		if err := uc.pollFeed(ctx, feed); err != nil {
+			uc.logger.Error("Failed to poll podcast feed",
+				"error", err,
+				"feed_id", feed.ID,
+			)
+		}
+	}
+}
+
+// pollFeed загружает ленту и ставит в обработку выпуски, которые пользователь еще не получал
+func (uc *PodcastUseCase) pollFeed(ctx context.Context, feed *entity.PodcastFeed) error {
+	_, episodes, err := uc.podcastService.FetchFeed(ctx, feed.URL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch podcast feed: %w", err)
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, feed.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Обрабатываем выпуски от старых к новым, чтобы дайджесты приходили по порядку
+	if len(episodes) > maxEpisodesPerPoll {
+		episodes = episodes[:maxEpisodesPerPoll]
+	}
+	for _, episode := range episodes {
+		episode.FeedID = feed.ID
+		episode.UserID = user.ID
+	}
+
+	// Новые выпуски получают ID, уже учтенные остаются без него
+	if _, err := uc.podcastRepo.AddEpisodes(ctx, episodes); err != nil {
+		return fmt.Errorf("failed to add podcast episodes: %w", err)
+	}
+
+	// Выпуски загружаются по одному, а задачи для них создаются одним пакетом
+	var pending []*entity.PodcastEpisode
+	var downloaded []podcastEpisodeAudio
+	for i := len(episodes) - 1; i >= 0; i-- {
+		episode := episodes[i]
+		if episode.ID == 0 {
+			continue
+		}
+
+		audio, err := uc.downloadEpisode(ctx, user, feed, episode)
+		if err != nil {
+			// Выпуск уже учтен, поэтому повторно он обработан не будет: только логируем
+			uc.logger.Error("Failed to process podcast episode",
+				"error", err,
+				"feed_id", feed.ID,
+				"guid", episode.GUID,
+			)
+			continue
+		}
+		pending = append(pending, episode)
+		downloaded = append(downloaded, audio)
+	}
+
+	if len(downloaded) > 0 {
+		uc.createEpisodeJobs(ctx, user, feed, pending, downloaded)
+	}
+
+	return uc.podcastRepo.SetFeedChecked(ctx, feed.ID, time.Now())
+}
+
+// createEpisodeJobs создает задачи обработки загруженных выпусков одним пакетом и связывает с ними выпуски.
+// Выпуски уже учтены, поэтому повторно они обработаны не будут: ошибки только логируются
+func (uc *PodcastUseCase) createEpisodeJobs(ctx context.Context, user *entity.User, feed *entity.PodcastFeed, episodes []*entity.PodcastEpisode, audio []podcastEpisodeAudio) {
+	jobIDs, err := uc.audioProcessingUseCase.ProcessPodcastEpisodes(ctx, user, audio, feed.NotionDatabaseID)
+	if err != nil {
+		uc.logger.Error("Failed to process podcast episodes",
+			"error", err,
+			"feed_id", feed.ID,
+		)
+		return
+	}
+
+	for i, episode := range episodes {
+		if jobIDs[i] == 0 {
+			continue
+		}
+		if err := uc.podcastRepo.SetEpisodeJob(ctx, episode.ID, jobIDs[i]); err != nil {
+			uc.logger.Error("Failed to set podcast episode job",
+				"error", err,
+				"episode_id", episode.ID,
+				"job_id", jobIDs[i],
+			)
+		}
+	}
+}
+
+// downloadEpisode загружает выпуск для создания задачи его обработки
+func (uc *PodcastUseCase) downloadEpisode(ctx context.Context, user *entity.User, feed *entity.PodcastFeed, episode *entity.PodcastEpisode) (podcastEpisodeAudio, error) {
+	// Логирование начала обработки выпуска
+	uc.logger.Info("New podcast episode found",
+		"feed_id", feed.ID,
+		"title", episode.Title,
+	)
+
+	audioPath, err := uc.podcastService.DownloadEpisode(ctx, user.ID, episode)
+	if err != nil {
+		return podcastEpisodeAudio{}, fmt.Errorf("failed to download podcast episode: %w", err)
+	}
+
+	return podcastEpisodeAudio{
+		audioPath: audioPath,
+		title:     fmt.Sprintf("%s — %s", feed.Title, episode.Title),
+	}, nil
+}
@@ -5,35 +5,55 @@ import (
 	"fmt"
 
 	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
 	"github.com/112Alex/project_obsidian/internal/domain/service"
 	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/metrics"
 )
 
 // QueueHandlersUseCase представляет собой сценарий регистрации обработчиков задач в очереди
 type QueueHandlersUseCase struct {
 	queueService                   service.QueueService
+	jobRepo                        repository.JobRepository
+	jobStageRepo                   repository.JobStageRepository
 	transcriptionProcessingUseCase *TranscriptionProcessingUseCase
 	summarizationProcessingUseCase *SummarizationProcessingUseCase
-	notionProcessingUseCase        *NotionProcessingUseCase
+	syncUseCase                    *SyncUseCase
 	telegramHandlersUseCase        *TelegramHandlersUseCase
+	knowledgeBaseUseCase           *KnowledgeBaseUseCase
+	readingLessonUseCase           *ReadingLessonUseCase
+	userWebhookUseCase             *UserWebhookUseCase
+	jobFollowUpUseCase             *JobFollowUpUseCase
 	logger                         *logger.Logger
 }
 
 // NewQueueHandlersUseCase создает новый сценарий регистрации обработчиков задач в очереди
 func NewQueueHandlersUseCase(
 	queueService service.QueueService,
+	jobRepo repository.JobRepository,
+	jobStageRepo repository.JobStageRepository,
 	transcriptionProcessingUseCase *TranscriptionProcessingUseCase,
 	summarizationProcessingUseCase *SummarizationProcessingUseCase,
-	notionProcessingUseCase *NotionProcessingUseCase,
+	syncUseCase *SyncUseCase,
 	telegramHandlersUseCase *TelegramHandlersUseCase,
+	knowledgeBaseUseCase *KnowledgeBaseUseCase,
+	readingLessonUseCase *ReadingLessonUseCase,
+	userWebhookUseCase *UserWebhookUseCase,
+	jobFollowUpUseCase *JobFollowUpUseCase,
 	logger *logger.Logger,
 ) *QueueHandlersUseCase {
 	return &QueueHandlersUseCase{
 		queueService:                   queueService,
+		jobRepo:                        jobRepo,
+		jobStageRepo:                   jobStageRepo,
 		transcriptionProcessingUseCase: transcriptionProcessingUseCase,
 		summarizationProcessingUseCase: summarizationProcessingUseCase,
-		notionProcessingUseCase:        notionProcessingUseCase,
+		syncUseCase:                    syncUseCase,
 		telegramHandlersUseCase:        telegramHandlersUseCase,
+		knowledgeBaseUseCase:           knowledgeBaseUseCase,
+		readingLessonUseCase:           readingLessonUseCase,
+		userWebhookUseCase:             userWebhookUseCase,
+		jobFollowUpUseCase:             jobFollowUpUseCase,
 		logger:                         logger,
 	}
 }
@@ -45,34 +65,61 @@ func (uc *QueueHandlersUseCase) RegisterHandlers(ctx context.Context) error {
 
 	// Регистрация обработчика для задач транскрибации
 	uc.queueService.RegisterHandler(entity.JobTypeTranscription, func(ctx context.Context, job entity.QueueJob) error {
-		return uc.transcriptionProcessingUseCase.ProcessTranscription(ctx, job)
+		return uc.trackStage(ctx, job, entity.JobStageTranscription, func(ctx context.Context) error {
+			return uc.transcriptionProcessingUseCase.ProcessTranscription(ctx, job)
+		})
 	})
 
 	// Регистрация обработчика для задач транскрибации с временными метками
 	uc.queueService.RegisterHandler(entity.JobTypeTranscriptionWithTimestamps, func(ctx context.Context, job entity.QueueJob) error {
-		return uc.transcriptionProcessingUseCase.ProcessTranscriptionWithTimestamps(ctx, job)
+		return uc.trackStage(ctx, job, entity.JobStageTranscription, func(ctx context.Context) error {
+			return uc.transcriptionProcessingUseCase.ProcessTranscriptionWithTimestamps(ctx, job)
+		})
 	})
 
 	// Регистрация обработчика для задач суммаризации
 	uc.queueService.RegisterHandler(entity.JobTypeSummarization, func(ctx context.Context, job entity.QueueJob) error {
-		return uc.summarizationProcessingUseCase.ProcessSummarization(ctx, job)
+		return uc.trackStage(ctx, job, entity.JobStageSummarization, func(ctx context.Context) error {
+			return uc.summarizationProcessingUseCase.ProcessSummarization(ctx, job)
+		})
 	})
 
 	// Регистрация обработчика для задач суммаризации с маркированным списком
 	uc.queueService.RegisterHandler(entity.JobTypeSummarizationWithBulletPoints, func(ctx context.Context, job entity.QueueJob) error {
-		return uc.summarizationProcessingUseCase.ProcessSummarizationWithBulletPoints(ctx, job)
+		return uc.trackStage(ctx, job, entity.JobStageSummarization, func(ctx context.Context) error {
+			return uc.summarizationProcessingUseCase.ProcessSummarizationWithBulletPoints(ctx, job)
+		})
 	})
 
-	// Регистрация обработчика для задач интеграции с Notion
+	// Регистрация обработчика для задач выгрузки результатов в Notion и другие места синхронизации
 	uc.queueService.RegisterHandler(entity.JobTypeNotion, func(ctx context.Context, job entity.QueueJob) error {
-		return uc.notionProcessingUseCase.ProcessNotionIntegration(ctx, job)
+		return uc.trackStage(ctx, job, entity.JobStageNotionSync, func(ctx context.Context) error {
+			return uc.syncUseCase.ProcessSync(ctx, job)
+		})
+	})
+
+	// Регистрация обработчика для задач проверки чтения вслух
+	uc.queueService.RegisterHandler(entity.JobTypeReadingLesson, func(ctx context.Context, job entity.QueueJob) error {
+		return uc.trackStage(ctx, job, entity.JobStageTranscription, func(ctx context.Context) error {
+			return uc.readingLessonUseCase.ProcessAttempt(ctx, job)
+		})
+	})
+
+	// Регистрация обработчика для задач индексации заметок
+	uc.queueService.RegisterHandler(entity.JobTypeEmbedding, func(ctx context.Context, job entity.QueueJob) error {
+		return uc.knowledgeBaseUseCase.ProcessEmbedding(ctx, job)
+	})
+
+	// Регистрация обработчика для действий с результатом задачи по кнопкам под уведомлением
+	uc.queueService.RegisterHandler(entity.JobTypeFollowUp, func(ctx context.Context, job entity.QueueJob) error {
+		return uc.jobFollowUpUseCase.ProcessFollowUp(ctx, job)
 	})
 
 	// Регистрация обработчика для задач уведомления о завершении
 	uc.queueService.RegisterHandler(entity.JobTypeNotification, func(ctx context.Context, job entity.QueueJob) error {
 		// Отправка уведомления о завершении задачи
 		jobIDStr := fmt.Sprintf("%d", job.JobID)
-		telegramID, _, err := uc.telegramHandlersUseCase.SendJobCompletionNotification(ctx, jobIDStr)
+		err := uc.telegramHandlersUseCase.SendJobCompletionNotification(ctx, jobIDStr)
 		if err != nil {
 			uc.logger.Error("Failed to send job completion notification",
 				"error", err,
@@ -80,16 +127,14 @@ func (uc *QueueHandlersUseCase) RegisterHandlers(ctx context.Context) error {
 			return err
 		}
 
-		// Здесь должна быть логика отправки сообщения пользователю через Telegram бота
-		// Но так как у нас нет прямого доступа к боту из этого слоя, мы можем использовать
-		// канал для отправки сообщений или другой механизм
-
 		// Логирование успешной отправки уведомления
 		uc.logger.Info("Successfully sent job completion notification",
 			"job_id", job.JobID,
-			"telegram_id", telegramID,
 		)
 
+		// Уведомление вебхука пользователя о завершении задачи
+		uc.userWebhookUseCase.NotifyJob(ctx, job.JobID, entity.WebhookEventJobCompleted, "")
+
 		return nil
 	})
 
@@ -99,22 +144,83 @@ func (uc *QueueHandlersUseCase) RegisterHandlers(ctx context.Context) error {
 	return nil
 }
 
-// StartWorker запускает обработчик задач из очереди
-func (uc *QueueHandlersUseCase) StartWorker(ctx context.Context) error {
-	// Логирование начала запуска обработчика задач
+// trackStage выполняет обработчик этапа, записывая его попытки, время и результат
+func (uc *QueueHandlersUseCase) trackStage(ctx context.Context, job entity.QueueJob, stage entity.JobStageName, handler func(ctx context.Context) error) error {
+	// Ошибка учета этапов не должна останавливать обработку задачи
+	if err := uc.jobStageRepo.Start(ctx, job.JobID, stage); err != nil {
+		uc.logger.Warn("Failed to record job stage start",
+			"error", err,
+			"job_id", job.JobID,
+			"stage", stage,
+		)
+	}
+
+	// Внешние вызовы этапа собираются для сохранения вместе с записью этапа
+	stageCtx, collector := metrics.WithCollector(ctx)
+	handlerErr := handler(stageCtx)
+
+	status := entity.JobStageStatusSucceeded
+	errorMessage := ""
+	if handlerErr != nil {
+		status = entity.JobStageStatusFailed
+		errorMessage = handlerErr.Error()
+	}
+
+	records := collector.Records()
+	calls := make([]entity.ExternalCall, len(records))
+	for i, record := range records {
+		calls[i] = entity.ExternalCall{
+			Provider:   record.Provider,
+			Operation:  record.Operation,
+			Status:     record.Status,
+			DurationMs: record.Duration.Milliseconds(),
+			Retries:    record.Retries,
+			Error:      record.Error,
+			StartedAt:  record.StartedAt,
+		}
+	}
+
+	if err := uc.jobStageRepo.Finish(ctx, job.JobID, stage, status, errorMessage, calls); err != nil {
+		uc.logger.Warn("Failed to record job stage finish",
+			"error", err,
+			"job_id", job.JobID,
+			"stage", stage,
+		)
+	}
+
+	// Задача с ошибкой запоминает этап, с которого ее перезапускает /retry, показывает ошибку в сообщении
+	// о ходе обработки и уведомляет вебхук пользователя
+	if handlerErr != nil {
+		if err := uc.jobRepo.MarkFailed(ctx, job.JobID, stage, errorMessage); err != nil {
+			uc.logger.Warn("Failed to mark job failed",
+				"error", err,
+				"job_id", job.JobID,
+				"stage", stage,
+			)
+		}
+		uc.telegramHandlersUseCase.SendProgressUpdate(ctx, job.JobID, entity.JobStatusFailed)
+		uc.userWebhookUseCase.NotifyJob(ctx, job.JobID, entity.WebhookEventJobFailed, errorMessage)
+	}
+
+	return handlerErr
+}
+
+// RunWorker обрабатывает задачи из очереди до отмены контекста
+func (uc *QueueHandlersUseCase) RunWorker(ctx context.Context) error {
+	// Логирование запуска обработчика задач
 	uc.logger.Info("Starting queue worker")
 
-	// Запуск обработчика задач
-	err := uc.queueService.StartWorker(ctx)
+	// Работа обработчика задач
+	err := uc.queueService.RunWorker(ctx)
 	if err != nil {
-		uc.logger.Error("Failed to start queue worker",
+		uc.logger.Error("Queue worker stopped with error",
 			"error", err,
 		)
 		return err
 	}
 
-	// Логирование успешного запуска обработчика задач
-	uc.logger.Info("Successfully started queue worker")
+	// Логирование остановки обработчика задач
+	uc.logger.Info("Queue worker stopped")
 
 	return nil
 }
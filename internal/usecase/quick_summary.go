@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// QuickSummaryCallbackPrefix - префикс данных кнопки под кратким содержанием команды /summarize.
+// Данные кнопки имеют вид summarize:<действие>
+const QuickSummaryCallbackPrefix = "summarize"
+
+// QuickSummaryActionNotion - действие кнопки «Сохранить в Notion»
+const QuickSummaryActionNotion = "notion"
+
+// quickSummaryHeader - идентификатор первой строки сообщения с кратким содержанием; по ней краткое содержание
+// извлекается из сообщения при сохранении в Notion
+const quickSummaryHeader = "quick_summary.header"
+
+// QuickSummaryUseCase представляет собой сценарий быстрой суммаризации произвольного текста командой /summarize:
+// краткое содержание присылается сразу, без задачи и очереди. Задача создается, только если пользователь
+// сохраняет результат в Notion
+type QuickSummaryUseCase struct {
+	userRepo                repository.UserRepository
+	jobRepo                 repository.JobRepository
+	summarizationService    service.SummarizationService
+	userSettingsUseCase     *UserSettingsUseCase
+	jobFollowUpUseCase      *JobFollowUpUseCase
+	messageRetentionUseCase *MessageRetentionUseCase
+	logger                  *logger.Logger
+}
+
+// NewQuickSummaryUseCase создает новый сценарий быстрой суммаризации текста
+func NewQuickSummaryUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	summarizationService service.SummarizationService,
+	userSettingsUseCase *UserSettingsUseCase,
+	jobFollowUpUseCase *JobFollowUpUseCase,
+	messageRetentionUseCase *MessageRetentionUseCase,
+	logger *logger.Logger,
+) *QuickSummaryUseCase {
+	return &QuickSummaryUseCase{
+		userRepo:                userRepo,
+		jobRepo:                 jobRepo,
+		summarizationService:    summarizationService,
+		userSettingsUseCase:     userSettingsUseCase,
+		jobFollowUpUseCase:      jobFollowUpUseCase,
+		messageRetentionUseCase: messageRetentionUseCase,
+		logger:                  logger,
+	}
+}
+
+// Summarize суммаризирует текст и присылает краткое содержание ответом на сообщение textMessageID с текстом.
+// Возвращает ответ команды только для ошибок ввода
+func (uc *QuickSummaryUseCase) Summarize(ctx context.Context, telegramID int64, textMessageID int, text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return i18n.T(ctx, "quick_summary.usage"), nil
+	}
+
+	// Логирование начала быстрой суммаризации
+	uc.logger.Info("Quick summarizing text",
+		"telegram_id", telegramID,
+		"text_length", len(text),
+	)
+
+	// Язык и стиль краткого содержания берутся из настроек, если пользователь уже пользовался ботом
+	summaryLanguage := ""
+	style := ""
+	if user, err := uc.userRepo.GetByTelegramID(ctx, telegramID); err == nil && user != nil {
+		summaryLanguage = user.SummaryLanguage
+		style = summaryStyleInstruction(uc.userSettingsUseCase.GetOrDefault(ctx, user.ID).SummaryStyle)
+	}
+	instructions := joinInstructions(summaryLanguageInstructions(summaryLanguage, text), style)
+
+	summary, err := uc.summarizationService.SummarizeWithInstructions(ctx, text, instructions)
+	if err != nil {
+		uc.logger.Error("Failed to summarize text",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to summarize text: %w", err)
+	}
+
+	buttons := [][]entity.MessageButton{{
+		{Text: i18n.T(ctx, "quick_summary.button.notion"), Data: QuickSummaryCallbackPrefix + ":" + QuickSummaryActionNotion},
+	}}
+
+	// Краткое содержание отправляется ответом на сообщение с текстом: при сохранении в Notion
+	// исходный текст берется из него
+	err = uc.messageRetentionUseCase.SendResult(ctx, telegramID, textMessageID, i18n.T(ctx, quickSummaryHeader)+"\n\n"+summary, buttons)
+	if err != nil {
+		return "", fmt.Errorf("failed to send quick summary: %w", err)
+	}
+
+	return "", nil
+}
+
+// SaveToNotion сохраняет краткое содержание из сообщения summaryMessage вместе с исходным текстом
+// в текстовую задачу и ставит ее выгрузку в Notion в очередь. Возвращает ответ на нажатие кнопки
+// и признак того, что выгрузка поставлена в очередь
+func (uc *QuickSummaryUseCase) SaveToNotion(ctx context.Context, telegramID int64, summaryMessageID int, summaryMessage, sourceText string) (string, bool, error) {
+	// Язык ответов мог смениться после отправки краткого содержания, поэтому заголовок ищется на всех языках
+	summary := summaryMessage
+	for _, language := range i18n.Languages {
+		summary = strings.TrimPrefix(summary, i18n.Translate(language, quickSummaryHeader))
+	}
+	summary = strings.TrimSpace(summary)
+	sourceText = strings.TrimSpace(sourceText)
+	if summary == "" || sourceText == "" {
+		return i18n.T(ctx, "quick_summary.source_unavailable"), false, nil
+	}
+
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil || user == nil || user.NotionToken == "" || user.NotionDatabaseID == "" {
+		return i18n.T(ctx, "job_action.notion_not_connected"), false, nil
+	}
+
+	// Задача создается уже завершенной: текст сохраняется вместо транскрипции, краткое содержание - готовое
+	job := entity.Job{
+		UserID:          user.ID,
+		Type:            entity.JobTypeText,
+		Transcription:   sourceText,
+		Summary:         summary,
+		SourceMessageID: summaryMessageID,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	if err := uc.jobRepo.Create(ctx, &job); err != nil {
+		uc.logger.Error("Failed to create job",
+			"error", err,
+		)
+		return "", false, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	if err := uc.jobRepo.UpdateStatus(ctx, job.ID, entity.JobStatusCompleted, ""); err != nil {
+		uc.logger.Error("Failed to update job status",
+			"error", err,
+		)
+		return "", false, fmt.Errorf("failed to update job status: %w", err)
+	}
+
+	// Выгрузка выполняется в очереди, ссылка на страницу придет ответом на сообщение с кратким содержанием
+	if err := uc.jobFollowUpUseCase.Enqueue(ctx, &job, followUpNotion, summaryMessageID); err != nil {
+		return "", false, err
+	}
+
+	// Логирование сохранения быстрой суммаризации
+	uc.logger.Info("Quick summary saved to Notion",
+		"telegram_id", telegramID,
+		"job_id", job.ID,
+	)
+
+	return i18n.T(ctx, "quick_summary.saving"), true, nil
+}
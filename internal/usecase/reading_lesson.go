@@ -0,0 +1,437 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// readingLessonFileName - имя, под которым попытки чтения отображаются в списке задач
+const readingLessonFileName = "Урок чтения"
+
+// readingLessonMaxWords - максимальная длина эталонного текста в словах. Выравнивание
+// квадратично по длине, а длинный текст все равно не поместится в одно голосовое сообщение
+const readingLessonMaxWords = 500
+
+// readingFeedbackMaxItems - сколько расхождений каждого вида показывается в отзыве
+const readingFeedbackMaxItems = 15
+
+// ReadingLessonUseCase представляет собой сценарий уроков чтения вслух: голосовое сообщение
+// транскрибируется и сравнивается с эталонным текстом
+type ReadingLessonUseCase struct {
+	userRepo             repository.UserRepository
+	jobRepo              repository.JobRepository
+	lessonRepo           repository.ReadingLessonRepository
+	queueService         service.QueueService
+	audioService         service.AudioService
+	transcriptionService service.TranscriptionService
+	languageUseCase      *InterfaceLanguageUseCase
+	messenger            service.MessengerService
+	logger               *logger.Logger
+}
+
+// NewReadingLessonUseCase создает новый сценарий уроков чтения вслух
+func NewReadingLessonUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	lessonRepo repository.ReadingLessonRepository,
+	queueService service.QueueService,
+	audioService service.AudioService,
+	transcriptionService service.TranscriptionService,
+	languageUseCase *InterfaceLanguageUseCase,
+	messenger service.MessengerService,
+	logger *logger.Logger,
+) *ReadingLessonUseCase {
+	return &ReadingLessonUseCase{
+		userRepo:             userRepo,
+		jobRepo:              jobRepo,
+		lessonRepo:           lessonRepo,
+		queueService:         queueService,
+		audioService:         audioService,
+		transcriptionService: transcriptionService,
+		languageUseCase:      languageUseCase,
+		messenger:            messenger,
+		logger:               logger,
+	}
+}
+
+// StartLesson начинает урок пользователя с указанным эталонным текстом, заменяя предыдущий
+func (uc *ReadingLessonUseCase) StartLesson(ctx context.Context, userID int64, text string) (*entity.ReadingLesson, error) {
+	text = strings.TrimSpace(text)
+	words := len(readingWords(text))
+	if words == 0 {
+		return nil, fmt.Errorf("reference text is empty")
+	}
+	if words > readingLessonMaxWords {
+		return nil, fmt.Errorf("reference text is too long: %d words, at most %d", words, readingLessonMaxWords)
+	}
+
+	lesson := &entity.ReadingLesson{
+		UserID:        userID,
+		ReferenceText: text,
+	}
+	if err := uc.lessonRepo.Set(ctx, lesson); err != nil {
+		uc.logger.Error("Failed to set reading lesson",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to set reading lesson: %w", err)
+	}
+
+	// Логирование начала урока
+	uc.logger.Info("Reading lesson started",
+		"user_id", userID,
+		"words", words,
+	)
+
+	return lesson, nil
+}
+
+// GetLesson возвращает активный урок пользователя или nil
+func (uc *ReadingLessonUseCase) GetLesson(ctx context.Context, userID int64) (*entity.ReadingLesson, error) {
+	lesson, err := uc.lessonRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		uc.logger.Error("Failed to get reading lesson",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get reading lesson: %w", err)
+	}
+
+	return lesson, nil
+}
+
+// EndLesson завершает урок пользователя и сообщает, был ли он активен
+func (uc *ReadingLessonUseCase) EndLesson(ctx context.Context, userID int64) (bool, error) {
+	ended, err := uc.lessonRepo.Delete(ctx, userID)
+	if err != nil {
+		uc.logger.Error("Failed to delete reading lesson",
+			"error", err,
+		)
+		return false, fmt.Errorf("failed to delete reading lesson: %w", err)
+	}
+
+	return ended, nil
+}
+
+// StartAttempt создает задачу проверки чтения и ставит ее в очередь.
+// Эталонный текст передается в задаче, чтобы смена урока не повлияла на уже отправленные попытки
+func (uc *ReadingLessonUseCase) StartAttempt(ctx context.Context, lesson *entity.ReadingLesson, sourceMessageID int, audioPath string) (int64, error) {
+	// Логирование начала попытки
+	uc.logger.Info("Starting reading attempt",
+		"user_id", lesson.UserID,
+		"audio_path", audioPath,
+	)
+
+	job := entity.Job{
+		UserID:          lesson.UserID,
+		Type:            entity.JobTypeReadingLesson,
+		Status:          entity.JobStatusCreated,
+		AudioFilePath:   audioPath,
+		FileName:        readingLessonFileName,
+		SourceMessageID: sourceMessageID,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	err := uc.jobRepo.Create(ctx, &job)
+	if err != nil {
+		uc.logger.Error("Failed to create job",
+			"error", err,
+		)
+		return 0, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	attemptJob := entity.QueueJob{
+		JobID:   job.ID,
+		UserID:  lesson.UserID,
+		JobType: entity.JobTypeReadingLesson,
+		Payload: map[string]interface{}{
+			"audio_path":     audioPath,
+			"reference_text": lesson.ReferenceText,
+		},
+	}
+
+	err = uc.queueService.PushJob(ctx, attemptJob)
+	if err != nil {
+		uc.logger.Error("Failed to push reading lesson job to queue",
+			"error", err,
+		)
+		return 0, fmt.Errorf("failed to push reading lesson job to queue: %w", err)
+	}
+
+	return job.ID, nil
+}
+
+// ProcessAttempt транскрибирует попытку чтения, сравнивает ее с эталонным текстом
+// и отправляет пользователю отзыв о пропусках и неверно прочитанных словах
+func (uc *ReadingLessonUseCase) ProcessAttempt(ctx context.Context, job entity.QueueJob) error {
+	// Получение данных из задачи
+	payload, ok := job.Payload.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid payload type in job")
+	}
+
+	audioPath, ok := payload["audio_path"].(string)
+	if !ok {
+		return fmt.Errorf("audio_path not found in job payload or has invalid type")
+	}
+
+	referenceText, ok := payload["reference_text"].(string)
+	if !ok {
+		return fmt.Errorf("reference_text not found in job payload or has invalid type")
+	}
+
+	// Логирование начала проверки
+	uc.logger.Info("Processing reading attempt",
+		"job_id", job.JobID,
+		"audio_path", audioPath,
+	)
+
+	storedJob, err := uc.jobRepo.GetByID(ctx, job.JobID)
+	if err != nil {
+		uc.logger.Error("Failed to get job",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, job.UserID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	ctx = i18n.WithLanguage(ctx, uc.languageUseCase.ForUser(ctx, user))
+
+	// Предобработка аудио с профилем, выбранным для задачи
+	processedAudioPath, _, err := uc.audioService.ProcessAudioWithProfile(ctx, audioPath, storedJob.AudioProfile)
+	if err != nil {
+		uc.logger.Error("Failed to process audio for transcription",
+			"error", err,
+		)
+		return fmt.Errorf("failed to process audio for transcription: %w", err)
+	}
+
+	// Подсказка с терминами здесь вредна: Whisper стал бы исправлять прочитанное под нее,
+	// поэтому из пользовательских параметров берется только температура
+	opts := entity.TranscriptionOptions{Temperature: user.TranscriptionOptions.Temperature}
+	transcription, err := uc.transcriptionService.TranscribeWithOptions(ctx, processedAudioPath, opts)
+	if err != nil {
+		uc.logger.Error("Failed to transcribe audio",
+			"error", err,
+		)
+		return fmt.Errorf("failed to transcribe audio: %w", err)
+	}
+
+	err = uc.jobRepo.SetTranscription(ctx, job.JobID, transcription)
+	if err != nil {
+		uc.logger.Error("Failed to update job transcription",
+			"error", err,
+		)
+		return fmt.Errorf("failed to update job transcription: %w", err)
+	}
+
+	// Сравнение с эталоном; отзыв сохраняется вместо краткого содержания
+	feedback := formatReadingFeedback(ctx, compareReading(referenceText, transcription), transcription)
+	err = uc.jobRepo.SetSummary(ctx, job.JobID, feedback)
+	if err != nil {
+		uc.logger.Error("Failed to update job summary",
+			"error", err,
+		)
+		return fmt.Errorf("failed to update job summary: %w", err)
+	}
+
+	err = uc.jobRepo.UpdateStatus(ctx, job.JobID, entity.JobStatusCompleted, "")
+	if err != nil {
+		uc.logger.Error("Failed to update job status",
+			"error", err,
+		)
+		return fmt.Errorf("failed to update job status: %w", err)
+	}
+
+	// Отзыв уже сохранен в задаче и доступен по /get, поэтому повторять задачу из-за ошибки отправки не нужно
+	if _, err := uc.messenger.SendReply(user.TelegramID, storedJob.SourceMessageID, feedback); err != nil {
+		uc.logger.Error("Failed to send reading feedback",
+			"error", err,
+			"job_id", job.JobID,
+		)
+	}
+
+	// Логирование успешной проверки
+	uc.logger.Info("Reading attempt processed successfully",
+		"job_id", job.JobID,
+		"transcription_length", len(transcription),
+	)
+
+	return nil
+}
+
+// readingOpKind - вид операции выравнивания прочитанного текста с эталоном
+type readingOpKind int
+
+const (
+	readingOpMatch      readingOpKind = iota // Слово прочитано верно
+	readingOpSubstitute                      // Вместо слова эталона прочитано другое
+	readingOpOmit                            // Слово эталона пропущено
+	readingOpInsert                          // Прочитано лишнее слово
+)
+
+// readingOp - шаг выравнивания: слово эталона и услышанное слово (пустое, если его нет)
+type readingOp struct {
+	kind     readingOpKind
+	expected string
+	heard    string
+}
+
+// readingReport - результат сравнения прочитанного текста с эталоном
+type readingReport struct {
+	ops           []readingOp
+	referenceSize int
+	matched       int
+}
+
+// readingWord - слово текста в исходном написании и в нормализованном виде для сравнения
+type readingWord struct {
+	text string
+	norm string
+}
+
+// compareReading выравнивает слова транскрипции со словами эталона по расстоянию Левенштейна
+// на уровне слов. Регистр, пунктуация и различие «е»/«ё» не учитываются
+func compareReading(reference, transcription string) readingReport {
+	ref := readingWords(reference)
+	hyp := readingWords(transcription)
+
+	// dist[i][j] - минимальное число правок, превращающих первые i слов эталона в первые j услышанных
+	dist := make([][]int, len(ref)+1)
+	for i := range dist {
+		dist[i] = make([]int, len(hyp)+1)
+		dist[i][0] = i
+	}
+	for j := range dist[0] {
+		dist[0][j] = j
+	}
+	for i := 1; i <= len(ref); i++ {
+		for j := 1; j <= len(hyp); j++ {
+			substitution := dist[i-1][j-1]
+			if ref[i-1].norm != hyp[j-1].norm {
+				substitution++
+			}
+			dist[i][j] = min(substitution, dist[i-1][j]+1, dist[i][j-1]+1)
+		}
+	}
+
+	// Восстановление выравнивания с конца; при равной цене совпадение и замена предпочтительнее
+	// пропуска и вставки, чтобы неверно прочитанное слово не распадалось на пропуск и лишнее слово
+	ops := make([]readingOp, 0, max(len(ref), len(hyp)))
+	report := readingReport{referenceSize: len(ref)}
+	i, j := len(ref), len(hyp)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && ref[i-1].norm == hyp[j-1].norm && dist[i][j] == dist[i-1][j-1]:
+			ops = append(ops, readingOp{kind: readingOpMatch, expected: ref[i-1].text, heard: hyp[j-1].text})
+			report.matched++
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && dist[i][j] == dist[i-1][j-1]+1:
+			ops = append(ops, readingOp{kind: readingOpSubstitute, expected: ref[i-1].text, heard: hyp[j-1].text})
+			i, j = i-1, j-1
+		case i > 0 && dist[i][j] == dist[i-1][j]+1:
+			ops = append(ops, readingOp{kind: readingOpOmit, expected: ref[i-1].text})
+			i--
+		default:
+			ops = append(ops, readingOp{kind: readingOpInsert, heard: hyp[j-1].text})
+			j--
+		}
+	}
+	for l, r := 0, len(ops)-1; l < r; l, r = l+1, r-1 {
+		ops[l], ops[r] = ops[r], ops[l]
+	}
+	report.ops = ops
+
+	return report
+}
+
+// readingWords разбивает текст на слова; знаки препинания вокруг слов отбрасываются
+func readingWords(text string) []readingWord {
+	fields := strings.Fields(text)
+	words := make([]readingWord, 0, len(fields))
+	for _, field := range fields {
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return unicode.IsPunct(r) || unicode.IsSymbol(r)
+		})
+		if word == "" {
+			continue
+		}
+		norm := strings.ReplaceAll(strings.ToLower(word), "ё", "е")
+		words = append(words, readingWord{text: word, norm: norm})
+	}
+	return words
+}
+
+// formatReadingFeedback формирует отзыв о чтении: точность, неверно прочитанные, пропущенные и лишние слова
+func formatReadingFeedback(ctx context.Context, report readingReport, transcription string) string {
+	var substituted, omitted, inserted []string
+	for _, op := range report.ops {
+		switch op.kind {
+		case readingOpSubstitute:
+			substituted = append(substituted, i18n.T(ctx, "reading.substituted", op.expected, op.heard))
+		case readingOpOmit:
+			omitted = append(omitted, "«"+op.expected+"»")
+		case readingOpInsert:
+			inserted = append(inserted, "«"+op.heard+"»")
+		}
+	}
+
+	accuracy := 0.0
+	if report.referenceSize > 0 {
+		accuracy = float64(report.matched) / float64(report.referenceSize) * 100
+	}
+
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, "reading.header") + "\n\n")
+	builder.WriteString(i18n.T(ctx, "reading.accuracy", accuracy, report.matched, report.referenceSize) + "\n")
+
+	if len(substituted)+len(omitted)+len(inserted) == 0 {
+		builder.WriteString("\n" + i18n.T(ctx, "reading.perfect") + "\n")
+	}
+	writeReadingIssues(ctx, &builder, i18n.T(ctx, "reading.substituted_title"), substituted, "\n")
+	writeReadingIssues(ctx, &builder, i18n.T(ctx, "reading.omitted_title"), omitted, ", ")
+	writeReadingIssues(ctx, &builder, i18n.T(ctx, "reading.inserted_title"), inserted, ", ")
+
+	heard := strings.TrimSpace(transcription)
+	if heard == "" {
+		heard = i18n.T(ctx, "reading.not_recognized")
+	} else if utf8.RuneCountInString(heard) > telegramMessageLimit/2 {
+		heard = string([]rune(heard)[:telegramMessageLimit/2]) + "…"
+	}
+	builder.WriteString("\n" + i18n.T(ctx, "reading.recognized", heard))
+
+	return builder.String()
+}
+
+// writeReadingIssues дописывает к отзыву раздел с расхождениями, показывая не больше readingFeedbackMaxItems
+func writeReadingIssues(ctx context.Context, builder *strings.Builder, title string, items []string, separator string) {
+	if len(items) == 0 {
+		return
+	}
+
+	fmt.Fprintf(builder, "\n%s (%d):\n", title, len(items))
+	shown := items
+	if len(shown) > readingFeedbackMaxItems {
+		shown = shown[:readingFeedbackMaxItems]
+	}
+	builder.WriteString(strings.Join(shown, separator))
+	if len(items) > len(shown) {
+		builder.WriteString(separator + i18n.T(ctx, "reading.more", len(items)-len(shown)))
+	}
+	builder.WriteString("\n")
+}
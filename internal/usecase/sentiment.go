@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+)
+
+// sentimentMoodEmojis - значки настроения встречи для выноски в Notion и уведомления о завершении задачи
+var sentimentMoodEmojis = map[entity.SentimentMood]string{
+	entity.SentimentMoodPositive: "🙂",
+	entity.SentimentMoodNeutral:  "😐",
+	entity.SentimentMoodNegative: "😠",
+	entity.SentimentMoodMixed:    "🎭",
+}
+
+// sentimentCalloutMarker - пометка цитаты Markdown, которую Notion показывает выноской со значком,
+// а Obsidian - выноской своего оформления
+const sentimentCalloutMarker = "[!callout]"
+
+// formatSentimentSection формирует раздел страницы задачи с разбором настроения встречи:
+// выноску с общим настроением, тоном обсуждения и напряженными моментами с цитатами
+func formatSentimentSection(ctx context.Context, sentiment *entity.JobSentiment) string {
+	mood := i18n.T(ctx, "notion.page.sentiment.mood", i18n.T(ctx, "sentiment."+string(sentiment.Mood)))
+	lines := []string{
+		sentimentCalloutMarker + " " + sentimentMoodEmojis[sentiment.Mood] + " " + mood,
+	}
+	if sentiment.Tone != "" {
+		lines = append(lines, i18n.T(ctx, "notion.page.sentiment.tone", sentiment.Tone))
+	}
+	if len(sentiment.TensionPoints) > 0 {
+		lines = append(lines, i18n.T(ctx, "notion.page.sentiment.tension"))
+		for _, point := range sentiment.TensionPoints {
+			line := "• " + point.Topic
+			if point.Quote != "" {
+				line += " — «" + point.Quote + "»"
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	// Все строки выноски - одна цитата: пустая строка закончила бы ее раньше
+	for i, line := range lines {
+		lines[i] = "> " + strings.ReplaceAll(line, "\n", " ")
+	}
+	return "## " + i18n.T(ctx, "notion.page.sentiment") + "\n\n" + strings.Join(lines, "\n")
+}
+
+// formatSentimentLine формирует строку уведомления о завершении задачи с общим настроением встречи
+func formatSentimentLine(ctx context.Context, sentiment *entity.JobSentiment) string {
+	line := i18n.T(ctx, "completion.sentiment", sentimentMoodEmojis[sentiment.Mood], i18n.T(ctx, "sentiment."+string(sentiment.Mood)))
+	if points := len(sentiment.TensionPoints); points > 0 {
+		line += i18n.T(ctx, "completion.tension", points)
+	}
+	return line + "\n\n"
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// shareTokenBytes - количество случайных байт в токене публичной ссылки
+const shareTokenBytes = 24
+
+// ShareLinkUseCase представляет собой сценарий работы с публичными ссылками на результаты задач
+type ShareLinkUseCase struct {
+	userRepo      repository.UserRepository
+	jobRepo       repository.JobRepository
+	shareLinkRepo repository.ShareLinkRepository
+	publicURL     string
+	linkTTL       time.Duration
+	logger        *logger.Logger
+}
+
+// NewShareLinkUseCase создает новый сценарий работы с публичными ссылками
+func NewShareLinkUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	shareLinkRepo repository.ShareLinkRepository,
+	publicURL string,
+	linkTTL time.Duration,
+	logger *logger.Logger,
+) *ShareLinkUseCase {
+	return &ShareLinkUseCase{
+		userRepo:      userRepo,
+		jobRepo:       jobRepo,
+		shareLinkRepo: shareLinkRepo,
+		publicURL:     strings.TrimRight(publicURL, "/"),
+		linkTTL:       linkTTL,
+		logger:        logger,
+	}
+}
+
+// CreateShareLink создает публичную ссылку на результаты задачи пользователя
+func (uc *ShareLinkUseCase) CreateShareLink(ctx context.Context, telegramID, jobID int64) (*entity.ShareLink, string, error) {
+	// Логирование начала создания ссылки
+	uc.logger.Info("Creating share link",
+		"telegram_id", telegramID,
+		"job_id", jobID,
+	)
+
+	// Проверка принадлежности задачи пользователю
+	user, job, err := uc.getOwnedJob(ctx, telegramID, jobID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Делиться можно только задачами с готовым результатом
+	if job.Transcription == "" && job.Summary == "" {
+		return nil, "", fmt.Errorf("job has no results to share")
+	}
+
+	// Генерация токена
+	token, err := generateShareToken()
+	if err != nil {
+		uc.logger.Error("Failed to generate share token",
+			"error", err,
+		)
+		return nil, "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+
+	// Сохранение ссылки в базе данных
+	link := &entity.ShareLink{
+		JobID:     job.ID,
+		UserID:    user.ID,
+		Token:     token,
+		ExpiresAt: time.Now().Add(uc.linkTTL),
+	}
+
+	err = uc.shareLinkRepo.Create(ctx, link)
+	if err != nil {
+		uc.logger.Error("Failed to create share link",
+			"error", err,
+		)
+		return nil, "", fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	// Логирование успешного создания ссылки
+	uc.logger.Info("Share link created successfully",
+		"job_id", job.ID,
+		"share_link_id", link.ID,
+		"expires_at", link.ExpiresAt,
+	)
+
+	return link, uc.ShareURL(link.Token), nil
+}
+
+// RevokeShareLinks отзывает все публичные ссылки на задачу пользователя
+func (uc *ShareLinkUseCase) RevokeShareLinks(ctx context.Context, telegramID, jobID int64) (int64, error) {
+	// Логирование начала отзыва ссылок
+	uc.logger.Info("Revoking share links",
+		"telegram_id", telegramID,
+		"job_id", jobID,
+	)
+
+	// Проверка принадлежности задачи пользователю
+	_, job, err := uc.getOwnedJob(ctx, telegramID, jobID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Отзыв ссылок
+	revoked, err := uc.shareLinkRepo.RevokeByJobID(ctx, job.ID)
+	if err != nil {
+		uc.logger.Error("Failed to revoke share links",
+			"error", err,
+		)
+		return 0, fmt.Errorf("failed to revoke share links: %w", err)
+	}
+
+	// Логирование успешного отзыва ссылок
+	uc.logger.Info("Share links revoked successfully",
+		"job_id", job.ID,
+		"revoked", revoked,
+	)
+
+	return revoked, nil
+}
+
+// GetSharedJob возвращает задачу по токену публичной ссылки, если ссылка активна
+func (uc *ShareLinkUseCase) GetSharedJob(ctx context.Context, token string) (*entity.Job, error) {
+	// Получение ссылки
+	link, err := uc.shareLinkRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+
+	// Проверка срока действия и отзыва ссылки
+	if !link.IsActive(time.Now()) {
+		return nil, fmt.Errorf("share link expired or revoked")
+	}
+
+	// Получение задачи
+	job, err := uc.jobRepo.GetByID(ctx, link.JobID)
+	if err != nil {
+		uc.logger.Error("Failed to get shared job",
+			"error", err,
+			"job_id", link.JobID,
+		)
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ShareURL формирует публичный адрес ссылки по её токену
+func (uc *ShareLinkUseCase) ShareURL(token string) string {
+	return fmt.Sprintf("%s/share/%s", uc.publicURL, token)
+}
+
+// getOwnedJob возвращает пользователя и задачу, проверяя, что задача принадлежит пользователю
+func (uc *ShareLinkUseCase) getOwnedJob(ctx context.Context, telegramID, jobID int64) (*entity.User, *entity.Job, error) {
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Получение задачи
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		uc.logger.Error("Failed to get job",
+			"error", err,
+		)
+		return nil, nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	// Чужие задачи считаем несуществующими
+	if job.UserID != user.ID {
+		return nil, nil, fmt.Errorf("job not found")
+	}
+
+	return user, job, nil
+}
+
+// generateShareToken генерирует случайный токен для публичной ссылки
+func generateShareToken() (string, error) {
+	buf := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
+)
+
+// transcribe распознает запись задачи пользователя userID. С разделением по говорящим фразы транскрипции
+// подписываются номером говорящего на языке пользователя («Спикер 1: …») и сохраняются у задачи для страницы
+// в Notion и краткого содержания.
+// Если провайдер не различает говорящих, их определяет сервис разделения; при его ошибке транскрипция
+// остается без говорящих, но задача обрабатывается дальше. Запись больше файла, который принимает провайдер,
+// распознается по фрагментам
+func (uc *TranscriptionProcessingUseCase) transcribe(ctx context.Context, jobID, userID int64, audioPath string, opts entity.TranscriptionOptions) (string, error) {
+	chunked := uc.exceedsFileLimit(audioPath)
+	if !uc.diarize && !chunked {
+		return uc.transcriptionService.TranscribeWithOptions(ctx, audioPath, opts)
+	}
+	ctx = i18n.WithLanguage(ctx, uc.languageUseCase.ForUserID(ctx, userID))
+
+	var segments []entity.SpeakerSegment
+	var err error
+	if chunked {
+		segments, err = uc.transcribeChunks(ctx, jobID, audioPath, opts)
+	} else {
+		segments, err = uc.transcriptionService.TranscribeWithSpeakers(ctx, audioPath, opts)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !uc.diarize {
+		return formatSpeakerTranscript(ctx, segments, opts.Granularity == entity.TranscriptionGranularitySegment), nil
+	}
+
+	if speakerCount(segments) == 0 && uc.diarizationService != nil {
+		turns, err := uc.diarizationService.Diarize(ctx, audioPath)
+		if err != nil {
+			uc.logger.Warn("Failed to diarize audio, keeping transcript without speakers",
+				"error", err,
+				"job_id", jobID,
+			)
+		} else {
+			assignSpeakers(segments, turns)
+		}
+	}
+
+	// Фразы без говорящих не хранятся: страница и краткое содержание строятся по тексту транскрипции.
+	// Пустой список заодно забывает говорящих, определенных при прошлом распознавании
+	stored := segments
+	if speakerCount(segments) == 0 {
+		stored = nil
+	}
+	if err := uc.jobRepo.SetSpeakerSegments(ctx, jobID, stored); err != nil {
+		uc.logger.Warn("Failed to save speaker segments",
+			"error", err,
+			"job_id", jobID,
+		)
+	}
+
+	// Логирование разделения по говорящим
+	uc.logger.Info("Transcript split by speakers",
+		"job_id", jobID,
+		"segments", len(segments),
+		"speakers", speakerCount(segments),
+	)
+
+	return formatSpeakerTranscript(ctx, segments, opts.Granularity == entity.TranscriptionGranularitySegment), nil
+}
+
+// assignSpeakers подписывает каждую фразу говорящим, который звучит дольше всего за время фразы.
+// Фраза, не пересекающаяся ни с одним промежутком, достается говорящему ближайшего промежутка
+func assignSpeakers(segments []entity.SpeakerSegment, turns []entity.SpeakerTurn) {
+	if len(turns) == 0 {
+		return
+	}
+
+	for i := range segments {
+		segment := &segments[i]
+		bestOverlap, bestDistance := 0.0, math.Inf(1)
+		for _, turn := range turns {
+			overlap := math.Min(segment.End, turn.End) - math.Max(segment.Start, turn.Start)
+			if overlap > bestOverlap {
+				bestOverlap = overlap
+				segment.Speaker = turn.Speaker
+				continue
+			}
+			if bestOverlap > 0 {
+				continue
+			}
+			distance := math.Abs((segment.Start + segment.End - turn.Start - turn.End) / 2)
+			if distance < bestDistance {
+				bestDistance = distance
+				segment.Speaker = turn.Speaker
+			}
+		}
+	}
+}
+
+// speakerCount возвращает число разных говорящих во фразах транскрипции
+func speakerCount(segments []entity.SpeakerSegment) int {
+	speakers := make(map[int]bool)
+	for _, segment := range segments {
+		if segment.Speaker > 0 {
+			speakers[segment.Speaker] = true
+		}
+	}
+	return len(speakers)
+}
+
+// speakerLabel возвращает подпись говорящего в транскрипции на языке из контекста. Это единственный
+// формат подписи: провайдеры распознавания возвращают только номера говорящих
+func speakerLabel(ctx context.Context, speaker int) string {
+	return i18n.T(ctx, "transcript.speaker", speaker)
+}
+
+// formatSpeakerTranscript собирает текст транскрипции из фраз. Подряд идущие фразы одного говорящего
+// объединяются в одну реплику, каждая реплика начинается с новой строки с подписью говорящего.
+// С метками времени каждая фраза начинается с новой строки с меткой [мм:сс], как в транскрипциях Whisper
+func formatSpeakerTranscript(ctx context.Context, segments []entity.SpeakerSegment, withTimestamps bool) string {
+	var builder strings.Builder
+	previous := -1
+	for _, segment := range segments {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+
+		switch {
+		case withTimestamps:
+			if builder.Len() > 0 {
+				builder.WriteString("\n")
+			}
+			builder.WriteString(textutil.SegmentTimestamp(segment.Start) + " ")
+			if segment.Speaker > 0 {
+				builder.WriteString(speakerLabel(ctx, segment.Speaker) + ": ")
+			}
+		case segment.Speaker != previous:
+			if builder.Len() > 0 {
+				builder.WriteString("\n")
+			}
+			if segment.Speaker > 0 {
+				builder.WriteString(speakerLabel(ctx, segment.Speaker) + ": ")
+			}
+		default:
+			builder.WriteString(" ")
+		}
+		builder.WriteString(text)
+		previous = segment.Speaker
+	}
+	return builder.String()
+}
+
+// speakerInstruction возвращает указание к суммаризации транскрипции, разделенной по говорящим:
+// краткое содержание должно сохранять, кто что сказал
+func speakerInstruction(segments []entity.SpeakerSegment) string {
+	if speakerCount(segments) < 2 {
+		return ""
+	}
+	return "Транскрипция разделена по говорящим: каждая реплика подписана номером говорящего. Указывай, кто из говорящих " +
+		"высказал мысль, предложил решение или взял на себя задачу."
+}
+
+// formatSpeakersSection формирует раздел страницы задачи с говорящими и их долей в разговоре
+func formatSpeakersSection(ctx context.Context, segments []entity.SpeakerSegment) string {
+	durations := make(map[int]float64)
+	order := make([]int, 0)
+	total := 0.0
+	for _, segment := range segments {
+		if segment.Speaker == 0 {
+			continue
+		}
+		if _, ok := durations[segment.Speaker]; !ok {
+			order = append(order, segment.Speaker)
+		}
+		duration := math.Max(segment.End-segment.Start, 0)
+		durations[segment.Speaker] += duration
+		total += duration
+	}
+
+	lines := []string{"## " + i18n.T(ctx, "notion.page.speakers"), ""}
+	for _, speaker := range order {
+		line := "- " + speakerLabel(ctx, speaker)
+		if total > 0 {
+			line += fmt.Sprintf(": %s (%d%%)", textutil.FormatTimestamp(int(durations[speaker])),
+				int(math.Round(durations[speaker]/total*100)))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
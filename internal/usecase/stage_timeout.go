@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// transcriptionTimeout возвращает ограничение времени распознавания: base плюс factor длительностей записи.
+// Для записи неизвестной длительности ограничение не задается (0), чтобы не обрывать длинные записи
+func transcriptionTimeout(base time.Duration, factor float64, durationSeconds float64) time.Duration {
+	if durationSeconds <= 0 || (base <= 0 && factor <= 0) {
+		return 0
+	}
+	return base + time.Duration(durationSeconds*factor*float64(time.Second))
+}
+
+// summarizationTimeout возвращает ограничение времени суммаризации: base плюс perThousandChars
+// на каждую тысячу символов транскрипции
+func summarizationTimeout(base time.Duration, perThousandChars time.Duration, text string) time.Duration {
+	if base <= 0 && perThousandChars <= 0 {
+		return 0
+	}
+	return base + perThousandChars*time.Duration(utf8.RuneCountInString(text))/1000
+}
+
+// withStageTimeout ограничивает время этапа обработки; timeout <= 0 означает этап без ограничения
+func withStageTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// stageTimeoutError дополняет ошибку этапа, прерванного по истечении ограничения времени, самим ограничением
+func stageTimeoutError(stageCtx context.Context, timeout time.Duration, err error) error {
+	if errors.Is(stageCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("stage timed out after %s: %w", timeout, err)
+	}
+	return err
+}
@@ -13,42 +13,65 @@ import (
 
 // SummarizationProcessingUseCase представляет собой сценарий обработки суммаризации
 type SummarizationProcessingUseCase struct {
-	jobRepo             repository.JobRepository
-	queueService        service.QueueService
+	userRepo             repository.UserRepository
+	jobRepo              repository.JobRepository
+	chapterRepo          repository.VideoChapterRepository
+	agendaRepo           repository.MeetingAgendaRepository
+	sentimentRepo        repository.JobSentimentRepository
+	queueService         service.QueueService
 	summarizationService service.SummarizationService
-	telegramHandlers    *TelegramHandlersUseCase
-	logger              *logger.Logger
+	userSettings         *UserSettingsUseCase
+	telegramHandlers     *TelegramHandlersUseCase
+	transcriptSearch     *TranscriptSearchUseCase
+	timeoutBase          time.Duration
+	timeoutPer1KChars    time.Duration
+	logger               *logger.Logger
 }
 
-// NewSummarizationProcessingUseCase создает новый сценарий обработки суммаризации
+// NewSummarizationProcessingUseCase создает новый сценарий обработки суммаризации.
+// timeoutBase и timeoutPer1KChars задают ограничение времени этапа: timeoutBase плюс timeoutPer1KChars
+// на каждую тысячу символов транскрипции
 func NewSummarizationProcessingUseCase(
+	userRepo repository.UserRepository,
 	jobRepo repository.JobRepository,
+	chapterRepo repository.VideoChapterRepository,
+	agendaRepo repository.MeetingAgendaRepository,
+	sentimentRepo repository.JobSentimentRepository,
 	queueService service.QueueService,
 	summarizationService service.SummarizationService,
+	userSettings *UserSettingsUseCase,
 	telegramHandlers *TelegramHandlersUseCase,
+	transcriptSearch *TranscriptSearchUseCase,
+	timeoutBase time.Duration,
+	timeoutPer1KChars time.Duration,
 	logger *logger.Logger,
 ) *SummarizationProcessingUseCase {
 	return &SummarizationProcessingUseCase{
-		jobRepo:             jobRepo,
-		queueService:        queueService,
+		userRepo:             userRepo,
+		jobRepo:              jobRepo,
+		chapterRepo:          chapterRepo,
+		agendaRepo:           agendaRepo,
+		sentimentRepo:        sentimentRepo,
+		queueService:         queueService,
 		summarizationService: summarizationService,
-		telegramHandlers:    telegramHandlers,
-		logger:              logger,
+		userSettings:         userSettings,
+		telegramHandlers:     telegramHandlers,
+		transcriptSearch:     transcriptSearch,
+		timeoutBase:          timeoutBase,
+		timeoutPer1KChars:    timeoutPer1KChars,
+		logger:               logger,
 	}
 }
 
 // ProcessSummarization обрабатывает суммаризацию текста
 func (uc *SummarizationProcessingUseCase) ProcessSummarization(ctx context.Context, job entity.QueueJob) error {
-	// Получение данных из задачи
-	payload, ok := job.Payload.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("invalid payload type in job")
-	}
-
-	transcription, ok := payload["transcription"].(string)
-	if !ok {
-		return fmt.Errorf("transcription not found in job payload or has invalid type")
+	// Получение задачи: транскрипция и указания пользователя из подписи к аудио хранятся в ней,
+	// а не в задаче очереди
+	storedJob, err := uc.getTranscribedJob(ctx, job.JobID)
+	if err != nil {
+		return err
 	}
+	transcription := storedJob.Transcription
 
 	// Логирование начала обработки суммаризации
 	uc.logger.Info("Processing summarization",
@@ -56,14 +79,39 @@ func (uc *SummarizationProcessingUseCase) ProcessSummarization(ctx context.Conte
 		"transcription_length", len(transcription),
 	)
 
-	// Суммаризация текста с использованием маркдаун форматирования
-	summary, err := uc.summarizationService.SummarizeText(ctx, transcription)
+	// Получение пользователя: краткое содержание пишется на выбранном им языке
+	user, err := uc.userRepo.GetByID(ctx, storedJob.UserID)
 	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Указания о языке, стиле и говорящих идут первыми, чтобы их не обрезало ограничение длины пользовательских
+	// указаний. Стиль, выбранный кнопками для этой задачи, приходит в данных задачи очереди, иначе берется из настроек
+	settings := uc.userSettings.GetOrDefault(ctx, storedJob.UserID)
+	summaryStyle := payloadSummaryStyle(job)
+	if summaryStyle == "" {
+		summaryStyle = settings.SummaryStyle
+	}
+	style := summaryStyleInstruction(summaryStyle)
+	instructions := joinInstructions(summaryLanguageInstructions(user.SummaryLanguage, transcription), style,
+		speakerInstruction(storedJob.Segments), storedJob.Instructions)
+
+	// Суммаризация текста по лестнице моделей с ограничением времени по длине транскрипции
+	timeout := summarizationTimeout(uc.timeoutBase, uc.timeoutPer1KChars, transcription)
+	stageCtx, cancel := withStageTimeout(ctx, timeout)
+	defer cancel()
+	result, err := uc.summarize(stageCtx, storedJob, user.SummaryLanguage, transcription, style, instructions)
+	if err != nil {
+		err = stageTimeoutError(stageCtx, timeout, err)
 		uc.logger.Error("Failed to summarize text",
 			"error", err,
 		)
 		return fmt.Errorf("failed to summarize text: %w", err)
 	}
+	summary := result.Summary
 
 	// Обновление задачи в базе данных
 	err = uc.jobRepo.SetSummary(ctx, job.JobID, summary)
@@ -74,21 +122,23 @@ func (uc *SummarizationProcessingUseCase) ProcessSummarization(ctx context.Conte
 		return fmt.Errorf("failed to update job summary: %w", err)
 	}
 
-	// Суммаризация транскрипции
-	summary, err := uc.summarizationService.Summarize(ctx, transcription)
+	// Модель, стоимость и токены нужны только для отчетности, поэтому ошибку сохранения только логируем
+	err = uc.jobRepo.SetSummaryUsage(ctx, job.JobID, result.Model, result.CostUSD, result.PromptTokens+result.CompletionTokens)
 	if err != nil {
-		uc.logger.Error("Failed to summarize text",
+		uc.logger.Error("Failed to update job summary usage",
 			"error", err,
+			"job_id", job.JobID,
 		)
-		return fmt.Errorf("failed to summarize text: %w", err)
 	}
 
-	// Отправка обновления прогресса после суммаризации
-	telegramID, message, err := uc.telegramHandlers.SendProgressUpdate(ctx, job.JobID, entity.JobStatusSummarized)
-	if err == nil {
-		uc.telegramHandlers.SendMessage(telegramID, message)
+	// Разбор настроения встречи, если пользователь включил его в /settings
+	if settings.SentimentAnalysis && hasSentimentAnalysis(storedJob.Type) {
+		uc.analyzeSentiment(stageCtx, job.JobID, transcription)
 	}
 
+	// Отправка обновления прогресса после суммаризации
+	uc.telegramHandlers.SendProgressUpdate(ctx, job.JobID, entity.JobStatusSummarized)
+
 	// Обновление статуса задачи
 	err = uc.jobRepo.UpdateStatus(ctx, job.JobID, entity.JobStatusSummarized, "")
 	if err != nil {
@@ -98,37 +148,106 @@ func (uc *SummarizationProcessingUseCase) ProcessSummarization(ctx context.Conte
 		return fmt.Errorf("failed to update job status: %w", err)
 	}
 
+	// Создание задачи для интеграции с Notion: результаты этап выгрузки читает из базы данных
+	notionJob := entity.QueueJob{
+		JobID:   job.JobID,
+		UserID:  job.UserID,
+		JobType: entity.JobTypeNotion,
+	}
+
+	// Добавление задачи в очередь
+	err = uc.queueService.PushJob(ctx, notionJob)
+	if err != nil {
+		uc.logger.Error("Failed to push Notion job to queue",
+			"error", err,
+		)
+		return fmt.Errorf("failed to push Notion job to queue: %w", err)
+	}
+
+	// Создание задачи для индексации заметки в базе знаний
+	embeddingJob := entity.QueueJob{
+		JobID:   job.JobID,
+		UserID:  job.UserID,
+		JobType: entity.JobTypeEmbedding,
+	}
+
+	// Добавление задачи в очередь
+	err = uc.queueService.PushJob(ctx, embeddingJob)
+	if err != nil {
+		uc.logger.Error("Failed to push embedding job to queue",
+			"error", err,
+		)
+		return fmt.Errorf("failed to push embedding job to queue: %w", err)
+	}
+
 	// Логирование успешной обработки суммаризации
 	uc.logger.Info("Summarization processed successfully",
 		"job_id", job.JobID,
 		"summary_length", len(summary),
+		"model", result.Model,
+		"cost_usd", result.CostUSD,
 	)
 
 	return nil
 }
 
-// ProcessSummarizationWithBulletPoints обрабатывает суммаризацию текста с маркированным списком
-func (uc *SummarizationProcessingUseCase) ProcessSummarizationWithBulletPoints(ctx context.Context, job entity.QueueJob) error {
-	// Получение данных из задачи
-	payload, ok := job.Payload.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("invalid payload type in job")
+// hasSentimentAnalysis проверяет, что для задачи этого типа составляется разбор настроения: он имеет смысл
+// для записей встреч и разговоров, но не для выпусков подкастов и видео
+func hasSentimentAnalysis(jobType entity.JobType) bool {
+	return jobType != entity.JobTypePodcastEpisode && !jobType.IsMediaLink()
+}
+
+// analyzeSentiment составляет и сохраняет разбор настроения встречи. Разбор дополняет краткое содержание,
+// поэтому его ошибки только логируются и не прерывают обработку задачи
+func (uc *SummarizationProcessingUseCase) analyzeSentiment(ctx context.Context, jobID int64, transcription string) {
+	sentiment, err := uc.summarizationService.AnalyzeSentiment(ctx, transcription)
+	if err != nil {
+		uc.logger.Warn("Failed to analyze meeting sentiment",
+			"error", err,
+			"job_id", jobID,
+		)
+		return
 	}
 
-	transcription, ok := payload["transcription"].(string)
-	if !ok {
-		return fmt.Errorf("transcription not found in job payload or has invalid type")
+	sentiment.JobID = jobID
+	if err := uc.sentimentRepo.Save(ctx, sentiment); err != nil {
+		uc.logger.Warn("Failed to save meeting sentiment",
+			"error", err,
+			"job_id", jobID,
+		)
+		return
 	}
 
+	// Логирование сохраненного разбора настроения
+	uc.logger.Info("Meeting sentiment analyzed",
+		"job_id", jobID,
+		"mood", sentiment.Mood,
+		"tension_points", len(sentiment.TensionPoints),
+	)
+}
+
+// ProcessSummarizationWithBulletPoints обрабатывает суммаризацию текста с маркированным списком
+func (uc *SummarizationProcessingUseCase) ProcessSummarizationWithBulletPoints(ctx context.Context, job entity.QueueJob) error {
+	// Получение транскрипции из задачи
+	storedJob, err := uc.getTranscribedJob(ctx, job.JobID)
+	if err != nil {
+		return err
+	}
+	transcription := storedJob.Transcription
+
 	// Логирование начала обработки суммаризации с маркированным списком
 	uc.logger.Info("Processing summarization with bullet points",
 		"job_id", job.JobID,
 		"transcription_length", len(transcription),
 	)
 
-	// Суммаризация текста с использованием маркированного списка
-	summary, err := uc.summarizationService.SummarizeText(ctx, transcription)
+	// Суммаризация текста с использованием маркированного списка с ограничением времени по длине транскрипции
+	timeout := summarizationTimeout(uc.timeoutBase, uc.timeoutPer1KChars, transcription)
+	stageCtx, cancel := withStageTimeout(ctx, timeout)
+	defer cancel()
+	summary, err := uc.summarizationService.SummarizeText(stageCtx, transcription)
 	if err != nil {
+		err = stageTimeoutError(stageCtx, timeout, err)
 		uc.logger.Error("Failed to summarize text with bullet points",
 			"error", err,
 		)
@@ -145,10 +264,7 @@ func (uc *SummarizationProcessingUseCase) ProcessSummarizationWithBulletPoints(c
 	}
 
 	// Отправка обновления прогресса перед интеграцией с Notion
-	telegramID, message, err = uc.telegramHandlers.SendProgressUpdate(ctx, job.JobID, entity.JobStatusIntegrating)  // Предполагая, что есть статус для интеграции
-	if err == nil {
-		uc.telegramHandlers.SendMessage(telegramID, message)
-	}
+	uc.telegramHandlers.SendProgressUpdate(ctx, job.JobID, entity.JobStatusSummarized)
 
 	// Обновление статуса задачи
 	err = uc.jobRepo.UpdateStatus(ctx, job.JobID, entity.JobStatusSummarized, "")
@@ -167,3 +283,70 @@ func (uc *SummarizationProcessingUseCase) ProcessSummarizationWithBulletPoints(c
 
 	return nil
 }
+
+// getTranscribedJob возвращает задачу с сохраненной транскрипцией. Транскрипция загружается
+// из базы данных, чтобы не передавать ее целиком через очередь
+func (uc *SummarizationProcessingUseCase) getTranscribedJob(ctx context.Context, jobID int64) (*entity.Job, error) {
+	storedJob, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		uc.logger.Error("Failed to get job",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	if storedJob.Transcription == "" {
+		return nil, fmt.Errorf("job %d has no transcription", jobID)
+	}
+
+	return storedJob, nil
+}
+
+// summarize составляет краткое содержание задачи. Для записи встречи с повесткой оно строится по пунктам
+// повестки, для видео с главами собирается по главам, иначе - по всей транскрипции
+func (uc *SummarizationProcessingUseCase) summarize(
+	ctx context.Context,
+	job *entity.Job,
+	summaryLanguage string,
+	transcription string,
+	style string,
+	instructions string,
+) (*entity.SummaryResult, error) {
+	agenda, err := uc.agendaRepo.GetByJobID(ctx, job.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get meeting agenda",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get meeting agenda: %w", err)
+	}
+	if agenda != nil {
+		return uc.summarizationService.SummarizeMeeting(ctx, transcription, agenda.Text, instructions)
+	}
+
+	if job.Type.IsMediaLink() {
+		chapters, err := uc.chapterRepo.GetByJobID(ctx, job.ID)
+		if err != nil {
+			uc.logger.Error("Failed to get video chapters",
+				"error", err,
+			)
+			return nil, fmt.Errorf("failed to get video chapters: %w", err)
+		}
+
+		if len(chapters) > 0 {
+			result, err := uc.transcriptSearch.SummarizeChapters(ctx, job, summaryLanguage, joinInstructions(style, job.Instructions), chapters)
+			if err != nil {
+				return nil, err
+			}
+			if result != nil {
+				return result, nil
+			}
+
+			// Без меток времени транскрипцию нельзя разделить на главы
+			uc.logger.Warn("Transcript has no timestamps, summarizing video without chapters",
+				"job_id", job.ID,
+			)
+		}
+	}
+
+	return uc.summarizationService.SummarizeJob(ctx, transcription, instructions)
+}
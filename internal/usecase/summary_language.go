@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SummaryLanguageAuto - язык краткого содержания определяется по языку транскрипции
+const SummaryLanguageAuto = "auto"
+
+// minLanguageEvidence - сколько характерных слов или букв нужно, чтобы уверенно определить язык текста
+const minLanguageEvidence = 5
+
+// languageDetectionSampleLength - сколько символов текста просматривается при определении языка
+const languageDetectionSampleLength = 20000
+
+// summaryLanguageNames - поддерживаемые языки краткого содержания: код ISO 639-1 и название в предложном падеже
+var summaryLanguageNames = map[string]string{
+	"ru": "русском",
+	"uk": "украинском",
+	"en": "английском",
+	"de": "немецком",
+	"fr": "французском",
+	"es": "испанском",
+	"it": "итальянском",
+	"pt": "португальском",
+}
+
+// summaryLanguageCodes - коды поддерживаемых языков в порядке показа пользователю
+var summaryLanguageCodes = []string{"ru", "uk", "en", "de", "fr", "es", "it", "pt"}
+
+// latinStopwords - частые служебные слова языков с латинским алфавитом
+var latinStopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "of", "to", "that", "this", "with", "we", "you", "it", "for", "have", "was", "not"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ich", "wir", "sie", "mit", "auf", "ein", "eine", "zu", "auch", "dass"},
+	"fr": {"le", "la", "les", "et", "est", "une", "des", "que", "pas", "nous", "vous", "pour", "dans", "avec", "qui", "ce"},
+	"es": {"el", "los", "las", "y", "es", "una", "que", "por", "para", "con", "no", "pero", "como", "del", "muy", "está"},
+	"it": {"il", "gli", "di", "che", "è", "una", "per", "non", "con", "sono", "della", "anche", "come", "ma", "questo", "noi"},
+	"pt": {"o", "os", "as", "e", "é", "um", "uma", "que", "não", "com", "para", "por", "mas", "você", "isso", "também"},
+}
+
+// latinStopwordIndex - обратный индекс latinStopwords: слово и языки, в которых оно встречается
+var latinStopwordIndex = buildStopwordIndex(latinStopwords)
+
+// buildStopwordIndex строит обратный индекс служебных слов
+func buildStopwordIndex(stopwords map[string][]string) map[string][]string {
+	index := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, word := range words {
+			index[word] = append(index[word], lang)
+		}
+	}
+	return index
+}
+
+// detectLanguage определяет преобладающий язык текста по алфавиту, характерным буквам и служебным словам.
+// Возвращает код ISO 639-1 из summaryLanguageNames или пустую строку, если язык определить не удалось
+func detectLanguage(text string) string {
+	if runes := []rune(text); len(runes) > languageDetectionSampleLength {
+		text = string(runes[:languageDetectionSampleLength])
+	}
+
+	var cyrillic, latin int
+	var ukrainianLetters, russianLetters int
+	latinScores := make(map[string]int)
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for _, word := range words {
+		for _, r := range word {
+			switch {
+			case unicode.Is(unicode.Cyrillic, r):
+				cyrillic++
+				switch r {
+				case 'і', 'ї', 'є', 'ґ':
+					ukrainianLetters++
+				case 'ы', 'э', 'ъ', 'ё':
+					russianLetters++
+				}
+			case unicode.Is(unicode.Latin, r):
+				latin++
+			}
+		}
+		for _, lang := range latinStopwordIndex[word] {
+			latinScores[lang]++
+		}
+	}
+
+	if cyrillic == 0 && latin == 0 {
+		return ""
+	}
+
+	if cyrillic >= latin {
+		if ukrainianLetters >= minLanguageEvidence && ukrainianLetters > russianLetters {
+			return "uk"
+		}
+		return "ru"
+	}
+
+	best, bestScore := "", 0
+	for _, lang := range summaryLanguageCodes {
+		if score := latinScores[lang]; score > bestScore {
+			best, bestScore = lang, score
+		}
+	}
+	if bestScore < minLanguageEvidence {
+		return ""
+	}
+	return best
+}
+
+// normalizeSummaryLanguage приводит выбранный пользователем язык к коду из summaryLanguageNames.
+// Пустая строка означает автоматический выбор; ok равен false для неподдерживаемого языка
+func normalizeSummaryLanguage(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == SummaryLanguageAuto {
+		return "", true
+	}
+	if _, ok := summaryLanguageNames[value]; !ok {
+		return "", false
+	}
+	return value, true
+}
+
+// summaryLanguageInstructions возвращает указание о языке краткого содержания: явно выбранный пользователем
+// язык или, при автоматическом выборе, преобладающий язык транскрипции. Если язык не определен, указание пустое
+func summaryLanguageInstructions(userLanguage string, transcription string) string {
+	lang := userLanguage
+	if lang == "" {
+		lang = detectLanguage(transcription)
+	}
+
+	name, ok := summaryLanguageNames[lang]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("Напиши резюме на %s языке.", name)
+}
+
+// joinInstructions объединяет непустые указания к суммаризации
+func joinInstructions(instructions ...string) string {
+	parts := make([]string, 0, len(instructions))
+	for _, instruction := range instructions {
+		if instruction = strings.TrimSpace(instruction); instruction != "" {
+			parts = append(parts, instruction)
+		}
+	}
+	return strings.Join(parts, " ")
+}
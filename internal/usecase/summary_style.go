@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+)
+
+// SummaryStyleCallbackPrefix - префикс данных кнопок выбора стиля краткого содержания под сообщением о приеме
+// записи. Данные кнопки имеют вид summary_style:<ID задачи>:<стиль>[:remember]
+const SummaryStyleCallbackPrefix = "summary_style"
+
+// summaryStyleRemember - суффикс данных кнопки, запоминающей выбранный стиль для следующих записей
+const summaryStyleRemember = "remember"
+
+// summaryStylePayloadKey - ключ стиля краткого содержания в данных задачи суммаризации в очереди
+const summaryStylePayloadKey = "summary_style"
+
+// summaryStyleChoices - стили в кнопках выбора под сообщением о приеме записи, по два в ряд
+var summaryStyleChoices = []entity.SummaryStyle{
+	entity.SummaryStyleBrief,
+	entity.SummaryStyleBullets,
+	entity.SummaryStyleMinutes,
+	entity.SummaryStyleDetailed,
+}
+
+// summaryStyleLabels - идентификаторы сообщений с подписями кнопок выбора стиля краткого содержания
+var summaryStyleLabels = map[entity.SummaryStyle]string{
+	entity.SummaryStyleBrief:    "summary_style.button.brief",
+	entity.SummaryStyleBullets:  "summary_style.button.bullets",
+	entity.SummaryStyleMinutes:  "summary_style.button.minutes",
+	entity.SummaryStyleDetailed: "summary_style.button.detailed",
+}
+
+// SummaryStylePrompt возвращает вопрос о стиле краткого содержания новой задачи и кнопки выбора.
+// Если пользователь запомнил выбор стиля, возвращает пустую строку: стиль берется из настроек
+func (uc *TelegramHandlersUseCase) SummaryStylePrompt(ctx context.Context, telegramID int64, jobID int64) (string, [][]entity.MessageButton) {
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Warn("Failed to get user for summary style prompt",
+			"error", err,
+		)
+		return "", nil
+	}
+
+	settings := uc.userSettingsUseCase.GetOrDefault(ctx, user.ID)
+	if settings.SummaryStyleRemembered {
+		return "", nil
+	}
+
+	buttons := make([][]entity.MessageButton, 0, len(summaryStyleChoices)/2)
+	for i := 0; i < len(summaryStyleChoices); i += 2 {
+		row := make([]entity.MessageButton, 0, 2)
+		for _, style := range summaryStyleChoices[i:min(i+2, len(summaryStyleChoices))] {
+			row = append(row, entity.MessageButton{
+				Text: i18n.T(ctx, summaryStyleLabels[style]),
+				Data: fmt.Sprintf("%s:%d:%s", SummaryStyleCallbackPrefix, jobID, style),
+			})
+		}
+		buttons = append(buttons, row)
+	}
+
+	text := i18n.T(ctx, "summary_style.prompt", jobID, summaryStyleName(ctx, settings.SummaryStyle))
+	return text, buttons
+}
+
+// HandleSummaryStyle выбирает стиль краткого содержания задачи по нажатию кнопки или запоминает его
+// для следующих записей. Возвращает новый текст сообщения с кнопками, кнопки и ответ на нажатие
+func (uc *TelegramHandlersUseCase) HandleSummaryStyle(ctx context.Context, telegramID int64, data string) (string, [][]entity.MessageButton, string, error) {
+	parts := strings.Split(data, ":")
+	if len(parts) < 2 {
+		return "", nil, i18n.T(ctx, "summary_style.invalid"), nil
+	}
+	jobID, err := strconv.ParseInt(parts[0], 10, 64)
+	style := entity.SummaryStyle(parts[1])
+	if _, ok := summaryStyleNames[style]; err != nil || !ok {
+		return "", nil, i18n.T(ctx, "summary_style.invalid"), nil
+	}
+	remember := len(parts) > 2 && parts[2] == summaryStyleRemember
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", nil, "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Проверка, что задача принадлежит пользователю
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return "", nil, i18n.T(ctx, "callback.job_not_found"), nil
+	}
+
+	if remember {
+		if err := uc.userSettingsUseCase.RememberSummaryStyle(ctx, user.ID, style); err != nil {
+			return "", nil, "", err
+		}
+		text := i18n.T(ctx, "summary_style.remembered", summaryStyleName(ctx, style))
+		return text, nil, i18n.T(ctx, "summary_style.remembered_answer"), nil
+	}
+
+	updated, err := uc.jobRepo.SetSummaryStyle(ctx, jobID, style)
+	if err != nil {
+		uc.logger.Error("Failed to set summary style",
+			"error", err,
+		)
+		return "", nil, "", fmt.Errorf("failed to set summary style: %w", err)
+	}
+	if !updated {
+		text := i18n.T(ctx, "summary_style.too_late", jobID)
+		return text, nil, i18n.T(ctx, "summary_style.too_late_answer"), nil
+	}
+
+	// Логирование выбора стиля
+	uc.logger.Info("Summary style selected",
+		"job_id", jobID,
+		"user_id", user.ID,
+		"summary_style", style,
+	)
+
+	text := i18n.T(ctx, "summary_style.selected", jobID, summaryStyleName(ctx, style))
+	buttons := [][]entity.MessageButton{{{
+		Text: i18n.T(ctx, "summary_style.button.remember"),
+		Data: fmt.Sprintf("%s:%d:%s:%s", SummaryStyleCallbackPrefix, jobID, style, summaryStyleRemember),
+	}}}
+	return text, buttons, i18n.T(ctx, "summary_style.selected_answer"), nil
+}
+
+// summaryStyleName возвращает название стиля краткого содержания на языке из контекста запроса;
+// неизвестный стиль считается обычным
+func summaryStyleName(ctx context.Context, style entity.SummaryStyle) string {
+	if name, ok := summaryStyleNames[style]; ok {
+		return i18n.T(ctx, name)
+	}
+	return i18n.T(ctx, summaryStyleNames[entity.SummaryStyleStandard])
+}
+
+// summarizationPayload возвращает данные задачи суммаризации в очереди со стилем, выбранным для задачи.
+// Если стиль не выбран, данных нет: этап суммаризации берет стиль из настроек пользователя
+func summarizationPayload(style entity.SummaryStyle) map[string]interface{} {
+	if style == "" {
+		return nil
+	}
+	return map[string]interface{}{
+		summaryStylePayloadKey: string(style),
+	}
+}
+
+// payloadSummaryStyle возвращает стиль краткого содержания из данных задачи суммаризации в очереди
+// или пустую строку, если стиль для задачи не выбран
+func payloadSummaryStyle(job entity.QueueJob) entity.SummaryStyle {
+	payload, _ := job.Payload.(map[string]interface{})
+	style, _ := payload[summaryStylePayloadKey].(string)
+	if _, ok := summaryStyleNames[entity.SummaryStyle(style)]; !ok {
+		return ""
+	}
+	return entity.SummaryStyle(style)
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// SyncUseCase представляет собой завершающий этап обработки: выгрузку результатов задачи
+// во все активные места синхронизации с учетом результата по каждому из них
+type SyncUseCase struct {
+	jobRepo        repository.JobRepository
+	userRepo       repository.UserRepository
+	syncResultRepo repository.SyncResultRepository
+	queueService   service.QueueService
+	userSettings   *UserSettingsUseCase
+	language       *InterfaceLanguageUseCase
+	targets        []service.SyncTarget
+	logger         *logger.Logger
+}
+
+// NewSyncUseCase создает новый сценарий выгрузки результатов задач
+func NewSyncUseCase(
+	jobRepo repository.JobRepository,
+	userRepo repository.UserRepository,
+	syncResultRepo repository.SyncResultRepository,
+	queueService service.QueueService,
+	userSettings *UserSettingsUseCase,
+	language *InterfaceLanguageUseCase,
+	logger *logger.Logger,
+) *SyncUseCase {
+	return &SyncUseCase{
+		jobRepo:        jobRepo,
+		userRepo:       userRepo,
+		syncResultRepo: syncResultRepo,
+		queueService:   queueService,
+		userSettings:   userSettings,
+		language:       language,
+		logger:         logger,
+	}
+}
+
+// RegisterTarget добавляет место синхронизации. Места выгружаются в порядке регистрации
+func (uc *SyncUseCase) RegisterTarget(target service.SyncTarget) {
+	uc.targets = append(uc.targets, target)
+}
+
+// ProcessSync выгружает результаты задачи во все места синхронизации, настроенные для пользователя.
+// Места, куда задача уже выгружена, при повторной попытке пропускаются. Этап завершается ошибкой,
+// только если не удалось выгрузить задачу ни в одно место
+func (uc *SyncUseCase) ProcessSync(ctx context.Context, job entity.QueueJob) error {
+	// Логирование начала выгрузки
+	uc.logger.Info("Processing job sync",
+		"job_id", job.JobID,
+		"user_id", job.UserID,
+	)
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByID(ctx, job.UserID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+			"user_id", job.UserID,
+		)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Получение задачи: транскрипция и краткое содержание читаются из базы данных,
+	// а не передаются через очередь
+	storedJob, err := uc.jobRepo.GetByID(ctx, job.JobID)
+	if err != nil {
+		uc.logger.Error("Failed to get job",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	if storedJob.Summary == "" {
+		return fmt.Errorf("job %d has no summary", job.JobID)
+	}
+
+	// Результаты предыдущих попыток
+	previous, err := uc.syncResultRepo.GetByJobID(ctx, job.JobID)
+	if err != nil {
+		uc.logger.Error("Failed to get sync results",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get sync results: %w", err)
+	}
+	alreadySynced := make(map[string]bool, len(previous))
+	for _, result := range previous {
+		alreadySynced[result.Target] = result.Status == entity.SyncResultStatusSucceeded
+	}
+
+	// Пользователь может отключить автоматическую выгрузку в Notion в настройках
+	notionAutoSync := uc.userSettings.GetOrDefault(ctx, user.ID).NotionAutoSync
+
+	// Выгрузка во все активные места
+	var synced, failed int
+	var lastErr error
+	for _, target := range uc.targets {
+		if !target.Enabled(user) {
+			continue
+		}
+		if target.Name() == NotionSyncTargetName && !notionAutoSync {
+			continue
+		}
+		if alreadySynced[target.Name()] {
+			synced++
+			continue
+		}
+
+		if err := uc.syncTarget(ctx, target, user, storedJob); err != nil {
+			failed++
+			lastErr = err
+			continue
+		}
+		synced++
+	}
+
+	if failed > 0 && synced == 0 {
+		return fmt.Errorf("failed to sync job to any target: %w", lastErr)
+	}
+
+	// Обновление статуса задачи
+	err = uc.jobRepo.UpdateStatus(ctx, job.JobID, entity.JobStatusCompleted, "")
+	if err != nil {
+		uc.logger.Error("Failed to update job status",
+			"error", err,
+		)
+		return fmt.Errorf("failed to update job status: %w", err)
+	}
+
+	// Логирование успешной выгрузки
+	uc.logger.Info("Job sync processed",
+		"job_id", job.JobID,
+		"synced", synced,
+		"failed", failed,
+	)
+
+	return uc.pushNotification(ctx, job)
+}
+
+// SyncTargetAgain выгружает задачу в место синхронизации targetName заново, даже если она уже выгружена туда.
+// Возвращает false, если место не настроено для пользователя
+func (uc *SyncUseCase) SyncTargetAgain(ctx context.Context, user *entity.User, job *entity.Job, targetName string) (bool, error) {
+	for _, target := range uc.targets {
+		if target.Name() != targetName {
+			continue
+		}
+		if !target.Enabled(user) {
+			return false, nil
+		}
+
+		// Логирование повторной выгрузки
+		uc.logger.Info("Syncing job again",
+			"job_id", job.ID,
+			"target", targetName,
+		)
+
+		return true, uc.syncTarget(ctx, target, user, job)
+	}
+
+	return false, nil
+}
+
+// syncTarget выгружает задачу в одно место синхронизации и сохраняет результат
+func (uc *SyncUseCase) syncTarget(ctx context.Context, target service.SyncTarget, user *entity.User, job *entity.Job) error {
+	result := &entity.SyncResult{
+		JobID:  job.ID,
+		Target: target.Name(),
+		Status: entity.SyncResultStatusSucceeded,
+	}
+
+	// Заголовки заметок и страниц - на языке пользователя
+	ctx = i18n.WithLanguage(ctx, uc.language.ForUser(ctx, user))
+	externalID, syncErr := target.Sync(ctx, user, job)
+	if syncErr != nil {
+		uc.logger.Error("Failed to sync job",
+			"error", syncErr,
+			"job_id", job.ID,
+			"target", target.Name(),
+		)
+		result.Status = entity.SyncResultStatusFailed
+		result.ErrorMessage = syncErr.Error()
+	}
+	result.ExternalID = externalID
+
+	// Без сохраненного результата повторная попытка выгрузит задачу еще раз, но это не повод терять сам результат
+	if err := uc.syncResultRepo.Save(ctx, result); err != nil {
+		uc.logger.Error("Failed to save sync result",
+			"error", err,
+			"job_id", job.ID,
+			"target", target.Name(),
+		)
+	}
+
+	return syncErr
+}
+
+// pushNotification ставит в очередь уведомление пользователя о завершении задачи
+func (uc *SyncUseCase) pushNotification(ctx context.Context, job entity.QueueJob) error {
+	notificationJob := entity.QueueJob{
+		JobID:   job.JobID,
+		UserID:  job.UserID,
+		JobType: entity.JobTypeNotification,
+	}
+
+	err := uc.queueService.PushJob(ctx, notificationJob)
+	if err != nil {
+		uc.logger.Error("Failed to push notification job to queue",
+			"error", err,
+		)
+		return fmt.Errorf("failed to push notification job to queue: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,383 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Параметры командных дайджестов
+const (
+	// teamInviteCodeBytes - количество случайных байт в коде приглашения в команду
+	teamInviteCodeBytes = 6
+	// defaultTeamDigestHour - час публикации дайджеста для новой команды
+	defaultTeamDigestHour = 9
+	// teamDigestCheckInterval - как часто проверяется, не пора ли публиковать дайджесты
+	teamDigestCheckInterval = 5 * time.Minute
+	// teamDigestSummaryLength - сколько символов краткого содержания попадает в дайджест
+	teamDigestSummaryLength = 500
+)
+
+// TeamDigestUseCase представляет собой сценарий работы с командами и публикации ежедневного
+// дайджеста кратких содержаний участников в канал команды
+type TeamDigestUseCase struct {
+	teamRepo         repository.TeamRepository
+	shareLinkUseCase *ShareLinkUseCase
+	languageUseCase  *InterfaceLanguageUseCase
+	messenger        service.MessengerService
+	location         *time.Location
+	logger           *logger.Logger
+}
+
+// NewTeamDigestUseCase создает новый сценарий командных дайджестов.
+// Час публикации дайджеста отсчитывается в часовом поясе timezone; неизвестный пояс заменяется на UTC
+func NewTeamDigestUseCase(
+	teamRepo repository.TeamRepository,
+	shareLinkUseCase *ShareLinkUseCase,
+	languageUseCase *InterfaceLanguageUseCase,
+	messenger service.MessengerService,
+	timezone string,
+	logger *logger.Logger,
+) *TeamDigestUseCase {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		logger.Warn("Unknown team digest timezone, using UTC",
+			"error", err,
+			"timezone", timezone,
+		)
+		location = time.UTC
+	}
+
+	return &TeamDigestUseCase{
+		teamRepo:         teamRepo,
+		shareLinkUseCase: shareLinkUseCase,
+		languageUseCase:  languageUseCase,
+		messenger:        messenger,
+		location:         location,
+		logger:           logger,
+	}
+}
+
+// Location возвращает часовой пояс, в котором задается час публикации дайджеста
+func (uc *TeamDigestUseCase) Location() *time.Location {
+	return uc.location
+}
+
+// GetTeam возвращает команду пользователя и ее участников; если пользователь не состоит в команде, команда равна nil
+func (uc *TeamDigestUseCase) GetTeam(ctx context.Context, user *entity.User) (*entity.Team, []*entity.TeamMember, error) {
+	team, err := uc.teamRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get team",
+			"error", err,
+		)
+		return nil, nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	if team == nil {
+		return nil, nil, nil
+	}
+
+	members, err := uc.teamRepo.GetMembers(ctx, team.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get team members",
+			"error", err,
+		)
+		return nil, nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+
+	return team, members, nil
+}
+
+// CreateTeam создает команду, владельцем и первым участником которой становится пользователь
+func (uc *TeamDigestUseCase) CreateTeam(ctx context.Context, user *entity.User, name string) (*entity.Team, error) {
+	// Логирование начала создания команды
+	uc.logger.Info("Creating team",
+		"user_id", user.ID,
+		"name", name,
+	)
+
+	code, err := generateTeamInviteCode()
+	if err != nil {
+		uc.logger.Error("Failed to generate team invite code",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to generate team invite code: %w", err)
+	}
+
+	team := &entity.Team{
+		Name:       name,
+		OwnerID:    user.ID,
+		InviteCode: code,
+		DigestHour: defaultTeamDigestHour,
+	}
+
+	err = uc.teamRepo.Create(ctx, team)
+	if err != nil {
+		uc.logger.Error("Failed to create team",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to create team: %w", err)
+	}
+
+	// Логирование успешного создания команды
+	uc.logger.Info("Team created successfully",
+		"team_id", team.ID,
+		"user_id", user.ID,
+	)
+
+	return team, nil
+}
+
+// JoinTeam добавляет пользователя в команду по коду приглашения
+func (uc *TeamDigestUseCase) JoinTeam(ctx context.Context, user *entity.User, code string) (*entity.Team, error) {
+	team, err := uc.teamRepo.GetByInviteCode(ctx, strings.ToLower(code))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	err = uc.teamRepo.AddMember(ctx, team.ID, user.ID)
+	if err != nil {
+		uc.logger.Error("Failed to add team member",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to add team member: %w", err)
+	}
+
+	// Логирование успешного вступления в команду
+	uc.logger.Info("User joined team",
+		"team_id", team.ID,
+		"user_id", user.ID,
+	)
+
+	return team, nil
+}
+
+// LeaveTeam исключает пользователя из команды. Команда, которую покидает владелец, распускается;
+// в этом случае возвращается true
+func (uc *TeamDigestUseCase) LeaveTeam(ctx context.Context, user *entity.User, team *entity.Team) (bool, error) {
+	if team.OwnerID == user.ID {
+		if err := uc.teamRepo.Delete(ctx, team.ID); err != nil {
+			uc.logger.Error("Failed to delete team",
+				"error", err,
+			)
+			return false, fmt.Errorf("failed to delete team: %w", err)
+		}
+
+		uc.logger.Info("Team disbanded",
+			"team_id", team.ID,
+			"user_id", user.ID,
+		)
+		return true, nil
+	}
+
+	if _, err := uc.teamRepo.RemoveMember(ctx, team.ID, user.ID); err != nil {
+		uc.logger.Error("Failed to remove team member",
+			"error", err,
+		)
+		return false, fmt.Errorf("failed to remove team member: %w", err)
+	}
+
+	uc.logger.Info("User left team",
+		"team_id", team.ID,
+		"user_id", user.ID,
+	)
+	return false, nil
+}
+
+// SetChannel задает канал для публикации дайджеста команды (0 - отключить публикацию).
+// В канал отправляется проверочное сообщение: если бот не может туда писать, канал не сохраняется
+func (uc *TeamDigestUseCase) SetChannel(ctx context.Context, team *entity.Team, channelID int64) error {
+	if channelID != 0 {
+		_, err := uc.messenger.SendMessage(channelID, i18n.T(ctx, "team.channel_test", team.Name, team.DigestHour))
+		if err != nil {
+			uc.logger.Warn("Failed to post to team channel",
+				"error", err,
+				"team_id", team.ID,
+				"channel_id", channelID,
+			)
+			return fmt.Errorf("failed to post to team channel: %w", err)
+		}
+	}
+
+	err := uc.teamRepo.SetChannel(ctx, team.ID, channelID)
+	if err != nil {
+		uc.logger.Error("Failed to set team channel",
+			"error", err,
+		)
+		return fmt.Errorf("failed to set team channel: %w", err)
+	}
+
+	team.ChannelID = channelID
+	return nil
+}
+
+// SetDigestHour задает час публикации дайджеста команды
+func (uc *TeamDigestUseCase) SetDigestHour(ctx context.Context, team *entity.Team, hour int) error {
+	err := uc.teamRepo.SetDigestHour(ctx, team.ID, hour)
+	if err != nil {
+		uc.logger.Error("Failed to set team digest hour",
+			"error", err,
+		)
+		return fmt.Errorf("failed to set team digest hour: %w", err)
+	}
+
+	team.DigestHour = hour
+	return nil
+}
+
+// RunSchedule публикует дайджесты команд по расписанию до отмены контекста
+func (uc *TeamDigestUseCase) RunSchedule(ctx context.Context) {
+	ticker := time.NewTicker(teamDigestCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.publishDueDigests(ctx, time.Now())
+		}
+	}
+}
+
+// publishDueDigests публикует дайджесты команд, у которых наступил час публикации;
+// ошибка одной команды не мешает публикации остальных
+func (uc *TeamDigestUseCase) publishDueDigests(ctx context.Context, now time.Time) {
+	teams, err := uc.teamRepo.GetWithChannel(ctx)
+	if err != nil {
+		uc.logger.Error("Failed to get teams",
+			"error", err,
+		)
+		return
+	}
+
+	local := now.In(uc.location)
+	for _, team := range teams {
+		if local.Hour() != team.DigestHour {
+			continue
+		}
+
+		// Дайджест за сегодня уже опубликован
+		slot := time.Date(local.Year(), local.Month(), local.Day(), team.DigestHour, 0, 0, 0, uc.location)
+		if team.LastDigestAt != nil && !team.LastDigestAt.Before(slot) {
+			continue
+		}
+
+		if err := uc.publishDigest(ctx, team, slot, now); err != nil {
+			uc.logger.Error("Failed to publish team digest",
+				"error", err,
+				"team_id", team.ID,
+			)
+		}
+	}
+}
+
+// publishDigest публикует в канал команды задачи участников, завершенные после предыдущего дайджеста
+func (uc *TeamDigestUseCase) publishDigest(ctx context.Context, team *entity.Team, slot, now time.Time) error {
+	since := slot.Add(-24 * time.Hour)
+	if team.LastDigestAt != nil {
+		since = *team.LastDigestAt
+	}
+
+	entries, err := uc.teamRepo.GetDigestEntries(ctx, team.ID, since, now)
+	if err != nil {
+		return fmt.Errorf("failed to get team digest entries: %w", err)
+	}
+
+	// Пустой дайджест не публикуем, но отмечаем, чтобы не проверять команду до следующего дня.
+	// Дайджест публикуется на языке ответов владельца команды
+	if len(entries) > 0 {
+		ctx := i18n.WithLanguage(ctx, uc.languageUseCase.ForUserID(ctx, team.OwnerID))
+		text := uc.buildDigest(ctx, team, entries, slot)
+		for _, part := range splitMessageText(text, telegramMessageLimit) {
+			if _, err := uc.messenger.SendMessage(team.ChannelID, part); err != nil {
+				return fmt.Errorf("failed to send team digest: %w", err)
+			}
+		}
+	}
+
+	if err := uc.teamRepo.SetDigestSent(ctx, team.ID, now); err != nil {
+		return fmt.Errorf("failed to set team digest sent: %w", err)
+	}
+
+	// Логирование успешной публикации дайджеста
+	uc.logger.Info("Team digest published",
+		"team_id", team.ID,
+		"channel_id", team.ChannelID,
+		"entries", len(entries),
+	)
+
+	return nil
+}
+
+// buildDigest формирует текст дайджеста: задачи сгруппированы по участникам, у каждой задачи
+// краткое содержание, публичная ссылка на результат и ссылка на страницу Notion, если она есть
+func (uc *TeamDigestUseCase) buildDigest(ctx context.Context, team *entity.Team, entries []*entity.TeamDigestEntry, slot time.Time) string {
+	messageBuilder := strings.Builder{}
+	messageBuilder.WriteString(i18n.T(ctx, "team.digest.header", team.Name, slot.Format("02.01.2006")) + "\n")
+
+	var currentUserID int64
+	for _, entry := range entries {
+		// Заголовок участника перед первой его задачей
+		if entry.UserID != currentUserID {
+			currentUserID = entry.UserID
+			messageBuilder.WriteString(fmt.Sprintf("\n👤 %s\n", teamMemberName(ctx, entry.Username, entry.FirstName)))
+		}
+
+		title := entry.FileName
+		if title == "" {
+			title = i18n.T(ctx, "team.digest.untitled", entry.JobID)
+		}
+		messageBuilder.WriteString(fmt.Sprintf("\n• %s (%s)\n", title, entry.CompletedAt.In(uc.location).Format("02.01 15:04")))
+
+		summary := strings.TrimSpace(entry.Summary)
+		if truncated := truncateRunes(summary, teamDigestSummaryLength, false); truncated != summary {
+			summary = strings.TrimSpace(truncated) + "…"
+		}
+		messageBuilder.WriteString(summary + "\n")
+
+		// Публичная ссылка доступна всем участникам канала, в отличие от страницы в Notion автора
+		_, shareURL, err := uc.shareLinkUseCase.CreateShareLink(ctx, entry.TelegramID, entry.JobID)
+		if err != nil {
+			uc.logger.Warn("Failed to create share link for team digest",
+				"error", err,
+				"job_id", entry.JobID,
+			)
+		} else {
+			messageBuilder.WriteString(fmt.Sprintf("🔗 %s\n", shareURL))
+		}
+		if entry.NotionPageID != "" {
+			messageBuilder.WriteString(fmt.Sprintf("📎 Notion: %s\n", notionURL(entry.NotionPageID)))
+		}
+	}
+
+	return messageBuilder.String()
+}
+
+// teamMemberName возвращает подпись участника команды в дайджесте
+func teamMemberName(ctx context.Context, username, firstName string) string {
+	if username != "" {
+		return "@" + username
+	}
+	if firstName != "" {
+		return firstName
+	}
+	return i18n.T(ctx, "team.digest.unnamed_member")
+}
+
+// generateTeamInviteCode генерирует случайный код приглашения в команду
+func generateTeamInviteCode() (string, error) {
+	buf := make([]byte, teamInviteCodeBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -2,40 +2,167 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"path/filepath"
+	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/112Alex/project_obsidian/internal/domain/entity"
 	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
 	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
 )
 
 // TelegramHandlersUseCase представляет собой сценарий обработки команд Telegram бота
 type TelegramHandlersUseCase struct {
-	userRepo                repository.UserRepository
-	jobRepo                 repository.JobRepository
-	audioProcessingUseCase  *AudioProcessingUseCase
-	notionProcessingUseCase *NotionProcessingUseCase
-	logger                  *logger.Logger
+	userRepo                 repository.UserRepository
+	jobRepo                  repository.JobRepository
+	jobStageRepo             repository.JobStageRepository
+	syncResultRepo           repository.SyncResultRepository
+	jobSentimentRepo         repository.JobSentimentRepository
+	audioProcessingUseCase   *AudioProcessingUseCase
+	notionProcessingUseCase  *NotionProcessingUseCase
+	shareLinkUseCase         *ShareLinkUseCase
+	textProcessingUseCase    *TextProcessingUseCase
+	knowledgeBaseUseCase     *KnowledgeBaseUseCase
+	messageRetentionUseCase  *MessageRetentionUseCase
+	podcastUseCase           *PodcastUseCase
+	notionReconcileUseCase   *NotionReconcileUseCase
+	transcriptSearchUseCase  *TranscriptSearchUseCase
+	teamDigestUseCase        *TeamDigestUseCase
+	readingLessonUseCase     *ReadingLessonUseCase
+	liveSessionUseCase       *LiveSessionUseCase
+	photoAttachmentUseCase   *PhotoAttachmentUseCase
+	userWebhookUseCase       *UserWebhookUseCase
+	failureAnalyticsUseCase  *FailureAnalyticsUseCase
+	adminSupportUseCase      *AdminSupportUseCase
+	diagnosticsUseCase       *DiagnosticsUseCase
+	userConfigUseCase        *UserConfigUseCase
+	videoUseCase             *VideoUseCase
+	meetingAgendaUseCase     *MeetingAgendaUseCase
+	userSettingsUseCase      *UserSettingsUseCase
+	jobFollowUpUseCase       *JobFollowUpUseCase
+	usageAnalyticsUseCase    *UsageAnalyticsUseCase
+	interfaceLanguageUseCase *InterfaceLanguageUseCase
+	modelComparisonUseCase   *ModelComparisonUseCase
+	maintenanceUseCase       *MaintenanceUseCase
+	urlIngestionService      service.URLIngestionService
+	messenger                service.MessengerService
+	textMinLength            int
+	admins                   *Admins
+	dailyJobQuota            int
+	logger                   *logger.Logger
+}
+
+// summarizeReplyCommand - ответ на сообщение, по которому текст отправляется на суммаризацию
+const summarizeReplyCommand = "суммаризируй"
+
+// maxWhisperPromptLength - Whisper учитывает только последние 224 токена подсказки
+const maxWhisperPromptLength = 800
+
+// findContextLength - сколько символов соседних фрагментов показывается вокруг найденного в /find
+const findContextLength = 150
+
+// audioProfileNames - идентификаторы сообщений с названиями профилей предобработки
+var audioProfileNames = map[entity.AudioProfile]string{
+	entity.AudioProfileAuto:    "audio_profile.auto",
+	entity.AudioProfileSpeech:  "audio_profile.speech",
+	entity.AudioProfilePhone:   "audio_profile.phone",
+	entity.AudioProfileLecture: "audio_profile.lecture",
+	entity.AudioProfileMusic:   "audio_profile.music",
+}
+
+// pipelineNames - идентификаторы сообщений с названиями наборов настроек обработки
+var pipelineNames = map[entity.Pipeline]string{
+	entity.PipelineFast:    "pipeline.fast",
+	entity.PipelineQuality: "pipeline.quality",
 }
 
 // NewTelegramHandlersUseCase создает новый сценарий обработки команд Telegram бота
 func NewTelegramHandlersUseCase(
 	userRepo repository.UserRepository,
 	jobRepo repository.JobRepository,
+	jobStageRepo repository.JobStageRepository,
+	syncResultRepo repository.SyncResultRepository,
+	jobSentimentRepo repository.JobSentimentRepository,
 	audioProcessingUseCase *AudioProcessingUseCase,
 	notionProcessingUseCase *NotionProcessingUseCase,
+	shareLinkUseCase *ShareLinkUseCase,
+	textProcessingUseCase *TextProcessingUseCase,
+	knowledgeBaseUseCase *KnowledgeBaseUseCase,
+	messageRetentionUseCase *MessageRetentionUseCase,
+	podcastUseCase *PodcastUseCase,
+	notionReconcileUseCase *NotionReconcileUseCase,
+	transcriptSearchUseCase *TranscriptSearchUseCase,
+	teamDigestUseCase *TeamDigestUseCase,
+	readingLessonUseCase *ReadingLessonUseCase,
+	liveSessionUseCase *LiveSessionUseCase,
+	photoAttachmentUseCase *PhotoAttachmentUseCase,
+	userWebhookUseCase *UserWebhookUseCase,
+	failureAnalyticsUseCase *FailureAnalyticsUseCase,
+	adminSupportUseCase *AdminSupportUseCase,
+	diagnosticsUseCase *DiagnosticsUseCase,
+	userConfigUseCase *UserConfigUseCase,
+	videoUseCase *VideoUseCase,
+	meetingAgendaUseCase *MeetingAgendaUseCase,
+	userSettingsUseCase *UserSettingsUseCase,
+	jobFollowUpUseCase *JobFollowUpUseCase,
+	usageAnalyticsUseCase *UsageAnalyticsUseCase,
+	interfaceLanguageUseCase *InterfaceLanguageUseCase,
+	modelComparisonUseCase *ModelComparisonUseCase,
+	maintenanceUseCase *MaintenanceUseCase,
+	urlIngestionService service.URLIngestionService,
+	messenger service.MessengerService,
+	textMinLength int,
+	admins *Admins,
+	dailyJobQuota int,
 	logger *logger.Logger,
 ) *TelegramHandlersUseCase {
 	return &TelegramHandlersUseCase{
-		userRepo:                userRepo,
-		jobRepo:                 jobRepo,
-		audioProcessingUseCase:  audioProcessingUseCase,
-		notionProcessingUseCase: notionProcessingUseCase,
-		logger:                  logger,
+		userRepo:                 userRepo,
+		jobRepo:                  jobRepo,
+		jobStageRepo:             jobStageRepo,
+		syncResultRepo:           syncResultRepo,
+		jobSentimentRepo:         jobSentimentRepo,
+		audioProcessingUseCase:   audioProcessingUseCase,
+		notionProcessingUseCase:  notionProcessingUseCase,
+		shareLinkUseCase:         shareLinkUseCase,
+		textProcessingUseCase:    textProcessingUseCase,
+		knowledgeBaseUseCase:     knowledgeBaseUseCase,
+		messageRetentionUseCase:  messageRetentionUseCase,
+		podcastUseCase:           podcastUseCase,
+		notionReconcileUseCase:   notionReconcileUseCase,
+		transcriptSearchUseCase:  transcriptSearchUseCase,
+		teamDigestUseCase:        teamDigestUseCase,
+		readingLessonUseCase:     readingLessonUseCase,
+		liveSessionUseCase:       liveSessionUseCase,
+		photoAttachmentUseCase:   photoAttachmentUseCase,
+		userWebhookUseCase:       userWebhookUseCase,
+		failureAnalyticsUseCase:  failureAnalyticsUseCase,
+		adminSupportUseCase:      adminSupportUseCase,
+		diagnosticsUseCase:       diagnosticsUseCase,
+		userConfigUseCase:        userConfigUseCase,
+		videoUseCase:             videoUseCase,
+		meetingAgendaUseCase:     meetingAgendaUseCase,
+		userSettingsUseCase:      userSettingsUseCase,
+		jobFollowUpUseCase:       jobFollowUpUseCase,
+		usageAnalyticsUseCase:    usageAnalyticsUseCase,
+		interfaceLanguageUseCase: interfaceLanguageUseCase,
+		modelComparisonUseCase:   modelComparisonUseCase,
+		maintenanceUseCase:       maintenanceUseCase,
+		urlIngestionService:      urlIngestionService,
+		messenger:                messenger,
+		textMinLength:            textMinLength,
+		admins:                   admins,
+		dailyJobQuota:            dailyJobQuota,
+		logger:                   logger,
 	}
 }
 
@@ -67,22 +194,26 @@ func (uc *TelegramHandlersUseCase) HandleStart(ctx context.Context, telegramID i
 			)
 			return "", fmt.Errorf("failed to create user: %w", err)
 		}
+	} else {
+		// Вернувшемуся пользователю показываем персональную сводку
+		dashboard, err := uc.buildStartDashboard(ctx, user, username, time.Now())
+		if err == nil {
+			uc.logger.Info("Successfully handled /start command for returning user",
+				"telegram_id", telegramID,
+				"user_id", user.ID,
+			)
+			return dashboard, nil
+		}
+
+		// При ошибке статистики показываем обычное приветствие
+		uc.logger.Warn("Failed to build start dashboard",
+			"error", err,
+			"user_id", user.ID,
+		)
 	}
 
 	// Формирование приветственного сообщения
-	welcomeMessage := fmt.Sprintf(
-		"Привет, %s! 👋\n\n"+
-			"Я бот для транскрибации аудио в текст и создания заметок в Notion. 🎙️📝\n\n"+
-			"Отправь мне голосовое сообщение или аудиофайл, и я:\n"+
-			"1️⃣ Преобразую его в текст\n"+
-			"2️⃣ Создам краткое содержание\n"+
-			"3️⃣ Сохраню в твою базу Notion (если настроено)\n\n"+
-			"Доступные команды:\n"+
-			"/help - показать справку\n"+
-			"/notion - настроить интеграцию с Notion\n"+
-			"/jobs - показать список задач",
-		username,
-	)
+	welcomeMessage := i18n.T(ctx, "start.welcome", username)
 
 	// Логирование успешной обработки команды /start
 	uc.logger.Info("Successfully handled /start command",
@@ -93,6 +224,48 @@ func (uc *TelegramHandlersUseCase) HandleStart(ctx context.Context, telegramID i
 	return welcomeMessage, nil
 }
 
+// buildStartDashboard формирует персональную сводку для вернувшегося пользователя
+func (uc *TelegramHandlersUseCase) buildStartDashboard(ctx context.Context, user *entity.User, username string, now time.Time) (string, error) {
+	// Статистика считается с начала текущей недели (с понедельника)
+	weekday := (int(now.Weekday()) + 6) % 7
+	weekStart := time.Date(now.Year(), now.Month(), now.Day()-weekday, 0, 0, 0, 0, now.Location())
+
+	stats, err := uc.jobRepo.GetUserStats(ctx, user.ID, weekStart)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	name := username
+	if name == "" {
+		name = user.FirstName
+	}
+
+	notionStatus := i18n.T(ctx, "start.notion.missing")
+	if user.NotionDatabaseID != "" {
+		notionStatus = i18n.T(ctx, "start.notion.connected")
+	}
+
+	return i18n.T(ctx, "start.dashboard",
+		timeOfDayGreeting(ctx, now), name,
+		stats.JobsSince, stats.SecondsTranscribed/60, stats.PendingJobs, stats.TotalJobs,
+		notionStatus,
+	), nil
+}
+
+// timeOfDayGreeting возвращает приветствие в зависимости от времени суток
+func timeOfDayGreeting(ctx context.Context, now time.Time) string {
+	switch hour := now.Hour(); {
+	case hour >= 5 && hour < 12:
+		return i18n.T(ctx, "greeting.morning")
+	case hour >= 12 && hour < 18:
+		return i18n.T(ctx, "greeting.day")
+	case hour >= 18 && hour < 23:
+		return i18n.T(ctx, "greeting.evening")
+	default:
+		return i18n.T(ctx, "greeting.night")
+	}
+}
+
 // HandleHelp обрабатывает команду /help
 func (uc *TelegramHandlersUseCase) HandleHelp(ctx context.Context, telegramID int64) (string, error) {
 	// Логирование начала обработки команды /help
@@ -101,26 +274,7 @@ func (uc *TelegramHandlersUseCase) HandleHelp(ctx context.Context, telegramID in
 	)
 
 	// Формирование сообщения справки
-	helpMessage := "🤖 *Справка по использованию бота* 🤖\n\n" +
-		"*Основные возможности:*\n" +
-		"• Транскрибация голосовых сообщений и аудиофайлов в текст\n" +
-		"• Создание краткого содержания транскрибации\n" +
-		"• Сохранение результатов в Notion\n\n" +
-		"*Команды:*\n" +
-		"/start - начать работу с ботом\n" +
-		"/help - показать эту справку\n" +
-		"/notion - настроить интеграцию с Notion\n" +
-		"/jobs - показать список ваших задач\n\n" +
-		"*Как использовать:*\n" +
-		"1. Отправьте боту голосовое сообщение или аудиофайл\n" +
-		"2. Дождитесь обработки (это может занять некоторое время)\n" +
-		"3. Получите транскрипцию и краткое содержание\n" +
-		"4. Если настроена интеграция с Notion, результаты будут автоматически сохранены\n\n" +
-		"*Поддерживаемые форматы аудио:*\n" +
-		"• Голосовые сообщения Telegram\n" +
-		"• Аудиофайлы (.mp3, .wav, .ogg, .m4a)\n\n" +
-		"*Настройка Notion:*\n" +
-		"Используйте команду /notion для настройки интеграции с Notion. Вам потребуется токен интеграции Notion."
+	helpMessage := i18n.T(ctx, "help")
 
 	// Логирование успешной обработки команды /help
 	uc.logger.Info("Successfully handled /help command",
@@ -146,15 +300,14 @@ func (uc *TelegramHandlersUseCase) HandleNotion(ctx context.Context, telegramID
 		return "", fmt.Errorf("failed to get user: %w", err)
 	}
 
+	// Если интеграция уже настроена, показываем ссылки на базу и ее представления
+	if args == "" && user.NotionDatabaseID != "" {
+		return formatNotionLinks(ctx, user) + "\n\n" + i18n.T(ctx, "notion.reconnect"), nil
+	}
+
 	// Если аргументы не предоставлены, отправляем инструкцию
 	if args == "" {
-		notionInstructions := "🔗 *Настройка интеграции с Notion* 🔗\n\n" +
-			"Для настройки интеграции с Notion, выполните следующие шаги:\n\n" +
-			"1. Перейдите на страницу [notion.so/my-integrations](https://www.notion.so/my-integrations)\n" +
-			"2. Создайте новую интеграцию\n" +
-			"3. Скопируйте токен интеграции\n" +
-			"4. Отправьте команду `/notion ваш_токен`\n\n" +
-			"После настройки интеграции, бот автоматически создаст базу данных в вашем Notion для хранения транскрипций."
+		notionInstructions := i18n.T(ctx, "notion.instructions")
 
 		// Логирование отправки инструкций по настройке Notion
 		uc.logger.Info("Sent Notion setup instructions",
@@ -174,10 +327,17 @@ func (uc *TelegramHandlersUseCase) HandleNotion(ctx context.Context, telegramID
 		return "", fmt.Errorf("failed to setup Notion integration: %w", err)
 	}
 
+	// Получение пользователя с созданными базой и страницей навигации
+	user, err = uc.userRepo.GetByID(ctx, user.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
 	// Формирование сообщения об успешной настройке
-	successMessage := "✅ *Интеграция с Notion успешно настроена!* ✅\n\n" +
-		"Теперь все транскрипции будут автоматически сохраняться в вашу базу данных Notion.\n\n" +
-		"Вы можете отправить мне голосовое сообщение или аудиофайл для обработки."
+	successMessage := i18n.T(ctx, "notion.connected", formatNotionLinks(ctx, user))
 
 	// Логирование успешной настройки интеграции с Notion
 	uc.logger.Info("Successfully set up Notion integration",
@@ -188,8 +348,19 @@ func (uc *TelegramHandlersUseCase) HandleNotion(ctx context.Context, telegramID
 	return successMessage, nil
 }
 
-// HandleJobs обрабатывает команду /jobs
-func (uc *TelegramHandlersUseCase) HandleJobs(ctx context.Context, telegramID int64) (string, error) {
+// formatNotionLinks формирует статус интеграции с Notion со ссылками на базу и ее представления
+func formatNotionLinks(ctx context.Context, user *entity.User) string {
+	messageBuilder := strings.Builder{}
+	messageBuilder.WriteString(i18n.T(ctx, "notion.links.title") + "\n\n")
+	messageBuilder.WriteString(i18n.T(ctx, "notion.links.database", notionURL(user.NotionDatabaseID)) + "\n")
+	if user.NotionHubPageID != "" {
+		messageBuilder.WriteString(i18n.T(ctx, "notion.links.hub", notionURL(user.NotionHubPageID)))
+	}
+	return strings.TrimSpace(messageBuilder.String())
+}
+
+// HandleJobs обрабатывает команду /jobs и возвращает первую страницу списка задач с кнопками навигации и отбора
+func (uc *TelegramHandlersUseCase) HandleJobs(ctx context.Context, telegramID int64) (string, [][]entity.MessageButton, error) {
 	// Логирование начала обработки команды /jobs
 	uc.logger.Info("Handling /jobs command",
 		"telegram_id", telegramID,
@@ -201,313 +372,2723 @@ func (uc *TelegramHandlersUseCase) HandleJobs(ctx context.Context, telegramID in
 		uc.logger.Error("Failed to get user",
 			"error", err,
 		)
-		return "", fmt.Errorf("failed to get user: %w", err)
+		return "", nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// Получение списка задач пользователя
-	jobs, err := uc.audioProcessingUseCase.GetUserJobs(ctx, user.ID)
+	text, buttons, err := uc.jobsPage(ctx, user.ID, entity.JobStatusFilterAll, 0)
 	if err != nil {
-		uc.logger.Error("Failed to get user jobs",
-			"error", err,
-		)
-		return "", fmt.Errorf("failed to get user jobs: %w", err)
-	}
-
-	// Если у пользователя нет задач
-	if len(jobs) == 0 {
-		return "У вас пока нет задач. Отправьте мне голосовое сообщение или аудиофайл для обработки.", nil
-	}
-
-	// Формирование сообщения со списком задач
-	messageBuilder := strings.Builder{}
-	messageBuilder.WriteString("📋 *Ваши задачи:* 📋\n\n")
-
-	for i, job := range jobs {
-		// Получение статуса задачи в текстовом виде
-		statusText := "Неизвестно"
-		statusEmoji := "❓"
-
-		switch job.Status {
-		case entity.JobStatusPending:
-			statusText = "В очереди"
-			statusEmoji = "⏳"
-		case entity.JobStatusProcessing:
-			statusText = "Обрабатывается"
-			statusEmoji = "⚙️"
-		case entity.JobStatusTranscribed:
-			statusText = "Транскрибировано"
-			statusEmoji = "📝"
-		case entity.JobStatusSummarized:
-			statusText = "Суммаризировано"
-			statusEmoji = "📊"
-		case entity.JobStatusCompleted:
-			statusText = "Завершено"
-			statusEmoji = "✅"
-		case entity.JobStatusFailed:
-			statusText = "Ошибка"
-			statusEmoji = "❌"
-		}
-
-		// Получение имени файла из пути
-		fileName := filepath.Base(job.AudioFilePath)
-
-		// Форматирование времени создания
-		createdAt := job.CreatedAt.Format("02.01.2006 15:04")
-
-		// Добавление информации о задаче
-		messageBuilder.WriteString(fmt.Sprintf(
-			"%d. %s *%s* (%s)\n   Создано: %s\n",
-			i+1,
-			statusEmoji,
-			fileName,
-			statusText,
-			createdAt,
-		))
-
-		// Если задача завершена и есть ID страницы Notion
-		if job.Status == entity.JobStatusCompleted && job.NotionPageID != "" {
-			messageBuilder.WriteString("   📎 Сохранено в Notion\n")
-		}
-
-		// Добавление разделителя между задачами
-		if i < len(jobs)-1 {
-			messageBuilder.WriteString("\n")
-		}
+		return "", nil, err
 	}
 
 	// Логирование успешной обработки команды /jobs
 	uc.logger.Info("Successfully handled /jobs command",
 		"telegram_id", telegramID,
 		"user_id", user.ID,
-		"jobs_count", len(jobs),
 	)
 
-	return messageBuilder.String(), nil
+	return text, buttons, nil
 }
 
-// HandleVoiceMessage обрабатывает голосовое сообщение
-func (uc *TelegramHandlersUseCase) HandleVoiceMessage(ctx context.Context, telegramID int64, username string, fileID string, filePath string, fileName string) (string, error) {
-	// Логирование начала обработки голосового сообщения
-	uc.logger.Info("Handling voice message",
-		"telegram_id", telegramID,
-		"file_id", fileID,
-	)
+// HandleJobsPage обрабатывает нажатие кнопки списка задач (<отбор>:<страница>) и возвращает
+// страницу списка, кнопки и ответ на нажатие
+func (uc *TelegramHandlersUseCase) HandleJobsPage(ctx context.Context, telegramID int64, data string) (string, [][]entity.MessageButton, string, error) {
+	filter, page, err := parseJobsPageData(data)
+	if err != nil {
+		return "", nil, "", err
+	}
 
-	// Получение или создание пользователя
+	// Получение пользователя
 	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
 	if err != nil {
-		// Если пользователь не найден, создаем нового
-		user = &entity.User{
-			TelegramID:       telegramID,
-			Username:         username,
-			CreatedAt:        time.Now(),
-			UpdatedAt:        time.Now(),
-			NotionToken:      "",
-			NotionDatabaseID: "",
-		}
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", nil, "", fmt.Errorf("failed to get user: %w", err)
+	}
 
-		err = uc.userRepo.Create(ctx, user)
-		if err != nil {
-			uc.logger.Error("Failed to create user",
-				"error", err,
-			)
-			return "", fmt.Errorf("failed to create user: %w", err)
-		}
+	text, buttons, err := uc.jobsPage(ctx, user.ID, filter, page)
+	if err != nil {
+		return "", nil, "", err
+	}
 
-		// ID пользователя уже установлен в методе Create
+	return text, buttons, i18n.T(ctx, "jobs.page", page+1), nil
+}
+
+// HandleShare обрабатывает команду /share
+func (uc *TelegramHandlersUseCase) HandleShare(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /share
+	uc.logger.Info("Handling /share command",
+		"telegram_id", telegramID,
+	)
+
+	// Разбор идентификатора задачи
+	jobID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil {
+		return i18n.T(ctx, "share.usage"), nil
 	}
 
-	// Обработка аудио файла
-	jobID, err := uc.audioProcessingUseCase.ProcessAudio(ctx, telegramID, filePath, fileName)
+	// Создание публичной ссылки
+	link, shareURL, err := uc.shareLinkUseCase.CreateShareLink(ctx, telegramID, jobID)
 	if err != nil {
-		uc.logger.Error("Failed to process audio file",
+		uc.logger.Error("Failed to create share link",
 			"error", err,
 		)
-		return "", fmt.Errorf("failed to process audio file: %w", err)
+		return "", fmt.Errorf("failed to create share link: %w", err)
 	}
 
-	// Формирование сообщения об успешном начале обработки
-	responseMessage := "🎙️ *Голосовое сообщение принято в обработку!* 🎙️\n\n" +
-		"Я начал обработку вашего голосового сообщения. Это может занять некоторое время.\n\n" +
-		"Вы получите уведомление, когда транскрипция и суммаризация будут готовы.\n\n" +
-		"Идентификатор задачи: `" + fmt.Sprintf("%d", jobID) + "`\n\n" +
-		"Вы можете проверить статус задачи с помощью команды /jobs"
+	// Формирование сообщения со ссылкой
+	responseMessage := i18n.T(ctx, "share.created", shareURL, link.ExpiresAt.Format("02.01.2006 15:04"), jobID)
 
-	// Логирование успешного начала обработки голосового сообщения
-	uc.logger.Info("Successfully started processing voice message",
+	// Логирование успешной обработки команды /share
+	uc.logger.Info("Successfully handled /share command",
 		"telegram_id", telegramID,
-		"user_id", user.ID,
 		"job_id", jobID,
 	)
 
 	return responseMessage, nil
 }
 
-// HandleAudioFile обрабатывает аудио файл
-func (uc *TelegramHandlersUseCase) HandleAudioFile(ctx context.Context, telegramID int64, username string, fileID string, filePath string, fileName string) (string, error) {
-	// Логирование начала обработки аудио файла
-	uc.logger.Info("Handling audio file",
+// HandleUnshare обрабатывает команду /unshare
+func (uc *TelegramHandlersUseCase) HandleUnshare(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /unshare
+	uc.logger.Info("Handling /unshare command",
 		"telegram_id", telegramID,
-		"file_id", fileID,
 	)
 
-	// Получение или создание пользователя
-	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	// Разбор идентификатора задачи
+	jobID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
 	if err != nil {
-		// Если пользователь не найден, создаем нового
-		user = &entity.User{
-			TelegramID:       telegramID,
-			Username:         username,
-			CreatedAt:        time.Now(),
-			UpdatedAt:        time.Now(),
-			NotionToken:      "",
-			NotionDatabaseID: "",
-		}
-
-		err = uc.userRepo.Create(ctx, user)
-		if err != nil {
-			uc.logger.Error("Failed to create user",
-				"error", err,
-			)
-			return "", fmt.Errorf("failed to create user: %w", err)
-		}
-
-		// ID пользователя устанавливается внутри метода Create
+		return i18n.T(ctx, "unshare.usage"), nil
 	}
 
-	// Обработка аудио файла
-	jobID, err := uc.audioProcessingUseCase.ProcessAudio(ctx, telegramID, filePath, fileName)
+	// Отзыв ссылок
+	revoked, err := uc.shareLinkUseCase.RevokeShareLinks(ctx, telegramID, jobID)
 	if err != nil {
-		uc.logger.Error("Failed to process audio file",
+		uc.logger.Error("Failed to revoke share links",
 			"error", err,
 		)
-		return "", fmt.Errorf("failed to process audio file: %w", err)
+		return "", fmt.Errorf("failed to revoke share links: %w", err)
 	}
 
-	// Формирование сообщения об успешном начале обработки
-	responseMessage := "🎵 *Аудиофайл принят в обработку!* 🎵\n\n" +
-		"Я начал обработку вашего аудиофайла. Это может занять некоторое время.\n\n" +
-		"Вы получите уведомление, когда транскрипция и суммаризация будут готовы.\n\n" +
-		"Идентификатор задачи: `" + fmt.Sprintf("%d", jobID) + "`\n\n" +
-		"Вы можете проверить статус задачи с помощью команды /jobs"
-
-	// Логирование успешного начала обработки аудио файла
-	uc.logger.Info("Successfully started processing audio file",
+	// Логирование успешной обработки команды /unshare
+	uc.logger.Info("Successfully handled /unshare command",
 		"telegram_id", telegramID,
-		"user_id", user.ID,
 		"job_id", jobID,
+		"revoked", revoked,
 	)
 
-	return responseMessage, nil
+	if revoked == 0 {
+		return i18n.T(ctx, "unshare.none"), nil
+	}
+
+	return i18n.T(ctx, "unshare.revoked", revoked), nil
 }
 
-// SendJobCompletionNotification отправляет уведомление о завершении задачи
-func (uc *TelegramHandlersUseCase) SendJobCompletionNotification(ctx context.Context, jobIDStr string) (int64, string, error) {
-	// Преобразование строки jobID в int64
-	jobID, err := strconv.ParseInt(jobIDStr, 10, 64)
+// HandleAsk обрабатывает команду /ask
+func (uc *TelegramHandlersUseCase) HandleAsk(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /ask
+	uc.logger.Info("Handling /ask command",
+		"telegram_id", telegramID,
+	)
+
+	question := strings.TrimSpace(args)
+	if question == "" {
+		return i18n.T(ctx, "ask.usage"), nil
+	}
+
+	// Поиск ответа по заметкам
+	answer, err := uc.knowledgeBaseUseCase.Ask(ctx, telegramID, question)
 	if err != nil {
-		uc.logger.Error("Failed to parse job ID",
+		uc.logger.Error("Failed to answer question",
 			"error", err,
 		)
-		return 0, "", fmt.Errorf("failed to parse job ID: %w", err)
+		return "", fmt.Errorf("failed to answer question: %w", err)
 	}
-	// Логирование начала отправки уведомления о завершении задачи
-	uc.logger.Info("Sending job completion notification",
-		"job_id", jobID,
+
+	if answer == "" {
+		return i18n.T(ctx, "ask.nothing"), nil
+	}
+
+	// Логирование успешной обработки команды /ask
+	uc.logger.Info("Successfully handled /ask command",
+		"telegram_id", telegramID,
 	)
 
-	// Получение задачи из базы данных
-	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	return answer, nil
+}
+
+// HandleGet обрабатывает команду /get и отправляет полный результат задачи
+func (uc *TelegramHandlersUseCase) HandleGet(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /get
+	uc.logger.Info("Handling /get command",
+		"telegram_id", telegramID,
+	)
+
+	// Разбор идентификатора задачи
+	jobID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
 	if err != nil {
-		uc.logger.Error("Failed to get job",
-			"error", err,
-		)
-		return 0, "", fmt.Errorf("failed to get job: %w", err)
+		return i18n.T(ctx, "job.usage", "get"), nil
 	}
 
-	// Получение пользователя из базы данных
-	user, err := uc.userRepo.GetByTelegramID(ctx, job.UserID)
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
 	if err != nil {
 		uc.logger.Error("Failed to get user",
 			"error", err,
 		)
-		return 0, "", fmt.Errorf("failed to get user: %w", err)
+		return "", fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// Формирование сообщения о завершении задачи
-	messageBuilder := strings.Builder{}
-	messageBuilder.WriteString("✅ *Задача успешно выполнена!* ✅\n\n")
-
-	// Добавление информации о транскрипции
-	if job.Transcription != "" {
-		// Ограничение длины транскрипции для сообщения
-		transcriptionPreview := job.Transcription
-		if len(transcriptionPreview) > 500 {
-			transcriptionPreview = transcriptionPreview[:500] + "..."
-		}
+	// Получение задачи; чужие задачи считаем несуществующими
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "job.not_found"), nil
+	}
 
-		messageBuilder.WriteString("📝 *Транскрипция:*\n")
-		messageBuilder.WriteString(transcriptionPreview)
-		messageBuilder.WriteString("\n\n")
+	if job.Transcription == "" && job.Summary == "" {
+		return i18n.T(ctx, "job.not_ready"), nil
 	}
 
-	// Добавление информации о суммаризации
+	// Формирование сообщения с результатом
+	messageBuilder := strings.Builder{}
+	messageBuilder.WriteString(i18n.T(ctx, "get.header", job.ID, job.CreatedAt.Format("02.01.2006 15:04")) + "\n\n")
 	if job.Summary != "" {
-		messageBuilder.WriteString("📊 *Краткое содержание:*\n")
+		messageBuilder.WriteString(i18n.T(ctx, "completion.summary"))
 		messageBuilder.WriteString(job.Summary)
 		messageBuilder.WriteString("\n\n")
 	}
+	if job.Transcription != "" {
+		messageBuilder.WriteString(i18n.T(ctx, "completion.transcription"))
+		messageBuilder.WriteString(job.Transcription)
+	}
 
-	// Добавление информации о сохранении в Notion
-	if job.NotionPageID != "" {
-		messageBuilder.WriteString("📎 *Сохранено в Notion*\n")
+	// Результат отправляется с учетом настройки автоудаления
+	err = uc.messageRetentionUseCase.SendResult(ctx, telegramID, 0, messageBuilder.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to send job result: %w", err)
 	}
 
-	// Логирование успешной отправки уведомления о завершении задачи
-	uc.logger.Info("Successfully prepared job completion notification",
+	// Логирование успешной обработки команды /get
+	uc.logger.Info("Successfully handled /get command",
+		"telegram_id", telegramID,
 		"job_id", jobID,
-		"user_id", job.UserID,
-		"telegram_id", user.TelegramID,
 	)
 
-	return user.TelegramID, messageBuilder.String(), nil
+	return "", nil
+}
+
+// HandleTranscript обрабатывает команду /transcript и присылает полную транскрипцию задачи;
+// если она не помещается в сообщение - файлом
+func (uc *TelegramHandlersUseCase) HandleTranscript(ctx context.Context, telegramID int64, messageID int, args string) (string, error) {
+	return uc.sendJobText(ctx, telegramID, messageID, "transcript", args, func(job *entity.Job) (string, string, string) {
+		return i18n.T(ctx, "transcript.header", job.ID) + "\n\n" + job.Transcription,
+			fmt.Sprintf("transcript_%d.txt", job.ID), job.Transcription
+	})
+}
+
+// HandleSummary обрабатывает команду /summary и присылает краткое содержание задачи;
+// если оно не помещается в сообщение - файлом
+func (uc *TelegramHandlersUseCase) HandleSummary(ctx context.Context, telegramID int64, messageID int, args string) (string, error) {
+	return uc.sendJobText(ctx, telegramID, messageID, "summary", args, func(job *entity.Job) (string, string, string) {
+		return i18n.T(ctx, "summary.header", job.ID) + "\n\n" + job.Summary,
+			fmt.Sprintf("summary_%d.md", job.ID), job.Summary
+	})
 }
 
-// SendProgressUpdate prepares a progress update message for the user
-func (uc *TelegramHandlersUseCase) SendProgressUpdate(ctx context.Context, jobID int64, status entity.JobStatus) (int64, string, error) {
+// sendJobText присылает часть результата задачи из аргументов команды /<command> <id> в ответ на сообщение
+// с командой. render возвращает текст сообщения, имя файла и содержимое файла; пустое содержимое означает,
+// что этой части результата еще нет. Ответ команды возвращается только для ошибок ввода
+func (uc *TelegramHandlersUseCase) sendJobText(ctx context.Context, telegramID int64, messageID int, command, args string, render func(job *entity.Job) (string, string, string)) (string, error) {
+	// Логирование начала обработки команды
+	uc.logger.Info("Handling /"+command+" command",
+		"telegram_id", telegramID,
+	)
+
+	// Разбор идентификатора задачи
+	jobID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil {
+		return i18n.T(ctx, "job.usage", command), nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Получение задачи; чужие задачи считаем несуществующими
 	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "job.not_found"), nil
+	}
+
+	text, fileName, content := render(job)
+	if content == "" {
+		return i18n.T(ctx, "job.not_ready"), nil
+	}
+
+	// Результат отправляется с учетом настройки автоудаления
+	if err := uc.messageRetentionUseCase.SendResultText(ctx, telegramID, messageID, text, fileName, content); err != nil {
+		return "", fmt.Errorf("failed to send job %s: %w", command, err)
+	}
+
+	return "", nil
+}
+
+// HandleFind обрабатывает команду /find и показывает фрагменты транскрипции задачи, содержащие фразу
+func (uc *TelegramHandlersUseCase) HandleFind(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /find
+	uc.logger.Info("Handling /find command",
+		"telegram_id", telegramID,
+	)
+
+	usage := i18n.T(ctx, "find.usage")
+
+	// Разбор идентификатора задачи и фразы
+	parts := strings.SplitN(strings.TrimSpace(args), " ", 2)
+	if len(parts) < 2 || strings.TrimSpace(parts[1]) == "" {
+		return usage, nil
+	}
+	jobID, err := strconv.ParseInt(parts[0], 10, 64)
 	if err != nil {
-		uc.logger.Error("Failed to get job", "error", err)
-		return 0, "", fmt.Errorf("failed to get job: %w", err)
+		return usage, nil
 	}
-	user, err := uc.userRepo.GetByID(ctx, job.UserID)
+	phrase := strings.TrimSpace(parts[1])
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
 	if err != nil {
-		uc.logger.Error("Failed to get user", "error", err)
-		return 0, "", fmt.Errorf("failed to get user: %w", err)
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
 	}
-	var message string
-	switch status {
-	case entity.JobStatusProcessing:
-		message = "⚙️ Обработка аудио начата."
-	case entity.JobStatusTranscribing:
-		message = "📝 Транскрипция в процессе."
-	case entity.JobStatusTranscribed:
-		message = "✅ Транскрипция завершена. Начинается суммаризация."
-	case entity.JobStatusSummarizing:
-		message = "📊 Суммаризация в процессе с DeepSeek."
-	case entity.JobStatusSummarized:
-		message = "✅ Суммаризация завершена. Начинается интеграция."
-	case entity.JobStatusIntegrating:
-		message = "🔗 Интеграция с Notion в процессе."
-	default:
-		message = fmt.Sprintf("Обновление статуса: %s", status)
+
+	// Получение задачи; чужие задачи считаем несуществующими
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "job.not_found"), nil
+	}
+
+	if job.Transcription == "" {
+		return i18n.T(ctx, "job.transcript_not_ready"), nil
+	}
+
+	excerpts, err := uc.transcriptSearchUseCase.Find(ctx, job, phrase)
+	if err != nil {
+		return "", fmt.Errorf("failed to search transcript: %w", err)
+	}
+
+	if len(excerpts) == 0 {
+		return i18n.T(ctx, "find.none", job.ID, phrase), nil
+	}
+
+	// Формирование сообщения с найденными фрагментами
+	messageBuilder := strings.Builder{}
+	messageBuilder.WriteString(i18n.T(ctx, "find.found", job.ID, phrase, len(excerpts)))
+	if len(excerpts) == maxFindResults {
+		messageBuilder.WriteString(i18n.T(ctx, "find.first"))
+	}
+	for _, excerpt := range excerpts {
+		messageBuilder.WriteString("\n\n")
+		if excerpt.StartSeconds != nil {
+			messageBuilder.WriteString(fmt.Sprintf("⏱ %s\n", textutil.FormatTimestamp(*excerpt.StartSeconds)))
+		}
+		if excerpt.Before != "" {
+			messageBuilder.WriteString("…" + truncateRunes(excerpt.Before, findContextLength, true) + "\n")
+		}
+		messageBuilder.WriteString("➡️ " + excerpt.Text)
+		if excerpt.After != "" {
+			messageBuilder.WriteString("\n" + truncateRunes(excerpt.After, findContextLength, false) + "…")
+		}
 	}
-	message = fmt.Sprintf("%s\nИдентификатор задачи: %d", message, jobID)
-	uc.logger.Info("Prepared progress update", "job_id", jobID, "status", status)
-	return user.TelegramID, message, nil
+
+	// Логирование успешной обработки команды /find
+	uc.logger.Info("Successfully handled /find command",
+		"telegram_id", telegramID,
+		"job_id", jobID,
+		"matches", len(excerpts),
+	)
+
+	return messageBuilder.String(), nil
+}
+
+// HandleSummarizeRange обрабатывает команду /summarize_range и суммаризирует промежуток записи,
+// например один пункт повестки длинной встречи
+func (uc *TelegramHandlersUseCase) HandleSummarizeRange(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /summarize_range
+	uc.logger.Info("Handling /summarize_range command",
+		"telegram_id", telegramID,
+	)
+
+	usage := i18n.T(ctx, "summarize_range.usage")
+
+	// Разбор идентификатора задачи и промежутка
+	parts := strings.Fields(args)
+	if len(parts) != 3 {
+		return usage, nil
+	}
+	jobID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return usage, nil
+	}
+	fromSeconds, okFrom := parseTimestamp(parts[1])
+	toSeconds, okTo := parseTimestamp(parts[2])
+	if !okFrom || !okTo {
+		return usage, nil
+	}
+	if toSeconds <= fromSeconds {
+		return i18n.T(ctx, "summarize_range.order"), nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Получение задачи; чужие задачи считаем несуществующими
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "job.not_found"), nil
+	}
+
+	if job.Transcription == "" {
+		return i18n.T(ctx, "job.transcript_not_ready"), nil
+	}
+
+	summary, lastStart, err := uc.transcriptSearchUseCase.SummarizeRange(ctx, job, user.SummaryLanguage, fromSeconds, toSeconds)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize transcript range: %w", err)
+	}
+
+	window := textutil.FormatTimestamp(fromSeconds) + "–" + textutil.FormatTimestamp(toSeconds)
+	if lastStart == nil {
+		return i18n.T(ctx, "summarize_range.no_timestamps"), nil
+	}
+	if summary == "" {
+		return i18n.T(ctx, "summarize_range.empty", job.ID, window, textutil.FormatTimestamp(*lastStart)), nil
+	}
+
+	// Логирование успешной обработки команды /summarize_range
+	uc.logger.Info("Successfully handled /summarize_range command",
+		"telegram_id", telegramID,
+		"job_id", jobID,
+	)
+
+	header := i18n.T(ctx, "summarize_range.header", job.ID, window) + "\n\n"
+	return header + truncateRunes(summary, telegramMessageLimit-utf8.RuneCountInString(header), false), nil
+}
+
+// truncateRunes укорачивает текст контекста до limit символов, оставляя конец (fromEnd) или начало
+func truncateRunes(text string, limit int, fromEnd bool) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	if fromEnd {
+		return string(runes[len(runes)-limit:])
+	}
+	return string(runes[:limit])
+}
+
+// HandleStatus обрабатывает команду /status и показывает ход обработки задачи по этапам
+func (uc *TelegramHandlersUseCase) HandleStatus(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /status
+	uc.logger.Info("Handling /status command",
+		"telegram_id", telegramID,
+	)
+
+	// Разбор идентификатора задачи
+	jobID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil {
+		return i18n.T(ctx, "job.usage", "status"), nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Получение задачи; чужие задачи считаем несуществующими
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "job.not_found"), nil
+	}
+
+	// Получение этапов задачи
+	stages, err := uc.jobStageRepo.GetByJobID(ctx, job.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get job stages",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get job stages: %w", err)
+	}
+
+	// Формирование сообщения с этапами
+	messageBuilder := strings.Builder{}
+	messageBuilder.WriteString(i18n.T(ctx, "status.header", job.ID, job.Status) + "\n\n")
+
+	if len(stages) == 0 {
+		messageBuilder.WriteString(i18n.T(ctx, "status.not_started"))
+	}
+
+	writeJobStages(ctx, &messageBuilder, stages)
+
+	// Модель, стоимость и токены суммаризации
+	if job.SummaryModel != "" {
+		messageBuilder.WriteString("\n" + i18n.T(ctx, "status.summary", job.SummaryModel, job.SummaryCost, job.SummaryTokens) + "\n")
+	}
+
+	// Результаты выгрузки по местам синхронизации
+	syncResults, err := uc.syncResultRepo.GetByJobID(ctx, job.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get sync results",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get sync results: %w", err)
+	}
+
+	if len(syncResults) > 0 {
+		messageBuilder.WriteString("\n" + i18n.T(ctx, "status.sync") + "\n")
+	}
+	for _, result := range syncResults {
+		statusEmoji := "✅"
+		if result.Status == entity.SyncResultStatusFailed {
+			statusEmoji = "❌"
+		}
+		messageBuilder.WriteString(fmt.Sprintf("%s %s\n", statusEmoji, syncTargetTitle(ctx, result.Target)))
+		if result.ErrorMessage != "" {
+			messageBuilder.WriteString("   " + i18n.T(ctx, "status.error", result.ErrorMessage) + "\n")
+		}
+	}
+
+	// Подсказка о перезапуске задачи с ошибкой
+	if job.Status == entity.JobStatusFailed && job.Type != entity.JobTypeReadingLesson {
+		messageBuilder.WriteString("\n" + i18n.T(ctx, "status.retry", jobStageTitle(ctx, retryStage(job)), job.ID) + "\n")
+	}
+
+	// Логирование успешной обработки команды /status
+	uc.logger.Info("Successfully handled /status command",
+		"telegram_id", telegramID,
+		"job_id", jobID,
+	)
+
+	return messageBuilder.String(), nil
+}
+
+// HandleRetry обрабатывает команду /retry: перезапускает задачу с ошибкой с упавшего этапа, используя
+// сохраненные запись, транскрипцию или краткое содержание
+func (uc *TelegramHandlersUseCase) HandleRetry(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /retry
+	uc.logger.Info("Handling /retry command",
+		"telegram_id", telegramID,
+	)
+
+	// Разбор идентификатора задачи
+	jobID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil {
+		return i18n.T(ctx, "retry.usage"), nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Получение задачи; чужие задачи считаем несуществующими
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "job.not_found"), nil
+	}
+	if job.Status != entity.JobStatusFailed {
+		return i18n.T(ctx, "retry.not_failed", job.ID, job.ID), nil
+	}
+	// Попытка чтения вслух сравнивается с уроком, который мог уже закончиться
+	if job.Type == entity.JobTypeReadingLesson {
+		return i18n.T(ctx, "retry.reading_lesson"), nil
+	}
+
+	stage := retryStage(job)
+	err = uc.audioProcessingUseCase.Retry(ctx, job)
+	if errors.Is(err, errRetrySourceMissing) {
+		return i18n.T(ctx, "retry.source_missing", job.ID), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to retry job: %w", err)
+	}
+
+	// Логирование успешного перезапуска задачи
+	uc.logger.Info("Successfully retried job",
+		"telegram_id", telegramID,
+		"job_id", job.ID,
+		"stage", stage,
+	)
+
+	return i18n.T(ctx, "retry.started", job.ID, jobStageTitle(ctx, stage)), nil
+}
+
+// writeJobStages добавляет в сообщение этапы задачи с длительностью, попытками, внешними вызовами и ошибками
+func writeJobStages(ctx context.Context, builder *strings.Builder, stages []*entity.JobStage) {
+	for _, stage := range stages {
+		statusEmoji := "⚙️"
+		switch stage.Status {
+		case entity.JobStageStatusSucceeded:
+			statusEmoji = "✅"
+		case entity.JobStageStatusFailed:
+			statusEmoji = "❌"
+		}
+
+		builder.WriteString(fmt.Sprintf("%s %s", statusEmoji, jobStageTitle(ctx, stage.Stage)))
+		if stage.Status != entity.JobStageStatusRunning {
+			builder.WriteString(fmt.Sprintf(" — %s", stage.Duration().Round(time.Second)))
+		}
+		if stage.Attempts > 1 {
+			builder.WriteString(i18n.T(ctx, "status.attempts", stage.Attempts))
+		}
+		builder.WriteString("\n")
+		if calls := formatExternalCalls(stage.ExternalCalls); calls != "" {
+			builder.WriteString("   " + i18n.T(ctx, "status.calls", calls) + "\n")
+		}
+		if stage.ErrorMessage != "" {
+			builder.WriteString("   " + i18n.T(ctx, "status.error", stage.ErrorMessage) + "\n")
+		}
+	}
+}
+
+// formatExternalCalls кратко описывает внешние вызовы этапа: количество и суммарное время по провайдерам
+func formatExternalCalls(calls []entity.ExternalCall) string {
+	providers := make([]string, 0)
+	counts := make(map[string]int)
+	durations := make(map[string]time.Duration)
+	for _, call := range calls {
+		if _, ok := counts[call.Provider]; !ok {
+			providers = append(providers, call.Provider)
+		}
+		counts[call.Provider]++
+		durations[call.Provider] += time.Duration(call.DurationMs) * time.Millisecond
+	}
+
+	parts := make([]string, len(providers))
+	for i, provider := range providers {
+		parts[i] = fmt.Sprintf("%s %d (%s)", provider, counts[provider], durations[provider].Round(100*time.Millisecond))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// jobStageTitle возвращает название этапа для пользователя
+func jobStageTitle(ctx context.Context, stage entity.JobStageName) string {
+	switch stage {
+	case entity.JobStageTranscription:
+		return i18n.T(ctx, "stage.transcription")
+	case entity.JobStageSummarization:
+		return i18n.T(ctx, "stage.summarization")
+	case entity.JobStageNotionSync:
+		return i18n.T(ctx, "stage.notion_sync")
+	default:
+		return string(stage)
+	}
+}
+
+// syncTargetTitle возвращает название места синхронизации для пользователя
+func syncTargetTitle(ctx context.Context, target string) string {
+	switch target {
+	case "notion":
+		return "Notion"
+	case "obsidian":
+		return i18n.T(ctx, "sync.obsidian")
+	case "webhook":
+		return i18n.T(ctx, "sync.webhook")
+	default:
+		return target
+	}
+}
+
+// HandleAutoDelete обрабатывает команду /autodelete
+func (uc *TelegramHandlersUseCase) HandleAutoDelete(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /autodelete
+	uc.logger.Info("Handling /autodelete command",
+		"telegram_id", telegramID,
+	)
+
+	usage := i18n.T(ctx, "autodelete.usage", MaxResultRetentionHours)
+
+	args = strings.ToLower(strings.TrimSpace(args))
+	if args == "" {
+		// Показ текущей настройки
+		user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+		if err != nil {
+			uc.logger.Error("Failed to get user",
+				"error", err,
+			)
+			return "", fmt.Errorf("failed to get user: %w", err)
+		}
+		if user.ResultRetentionHours == 0 {
+			return i18n.T(ctx, "autodelete.current_off") + "\n\n" + usage, nil
+		}
+		return i18n.T(ctx, "autodelete.current", user.ResultRetentionHours) + "\n\n" + usage, nil
+	}
+
+	hours := 0
+	if args != "off" {
+		parsed, err := strconv.Atoi(args)
+		if err != nil || parsed < 0 || parsed > MaxResultRetentionHours {
+			return usage, nil
+		}
+		hours = parsed
+	}
+
+	// Сохранение настройки
+	err := uc.messageRetentionUseCase.SetRetention(ctx, telegramID, hours)
+	if err != nil {
+		return "", fmt.Errorf("failed to set result retention: %w", err)
+	}
+
+	// Логирование успешной обработки команды /autodelete
+	uc.logger.Info("Successfully handled /autodelete command",
+		"telegram_id", telegramID,
+		"hours", hours,
+	)
+
+	if hours == 0 {
+		return i18n.T(ctx, "autodelete.off"), nil
+	}
+
+	return i18n.T(ctx, "autodelete.on", hours), nil
+}
+
+// HandleWhisper обрабатывает команду /whisper
+func (uc *TelegramHandlersUseCase) HandleWhisper(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /whisper
+	uc.logger.Info("Handling /whisper command",
+		"telegram_id", telegramID,
+	)
+
+	usage := i18n.T(ctx, "whisper.usage")
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	option, value, _ := strings.Cut(strings.TrimSpace(args), " ")
+	value = strings.TrimSpace(value)
+
+	opts := user.TranscriptionOptions
+	switch strings.ToLower(option) {
+	case "":
+		// Показ текущих настроек
+		return formatTranscriptionOptions(ctx, opts) + "\n\n" + usage, nil
+	case "temperature":
+		temperature, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 32)
+		if err != nil || temperature < 0 || temperature > 1 {
+			return i18n.T(ctx, "whisper.temperature"), nil
+		}
+		t := float32(temperature)
+		opts.Temperature = &t
+	case "prompt":
+		if value == "" {
+			return i18n.T(ctx, "whisper.prompt_missing") + "\n\n" + usage, nil
+		}
+		if utf8.RuneCountInString(value) > maxWhisperPromptLength {
+			return i18n.T(ctx, "whisper.prompt_too_long", maxWhisperPromptLength), nil
+		}
+		opts.Prompt = value
+	case "granularity":
+		granularity := entity.TranscriptionGranularity(strings.ToLower(value))
+		if granularity != entity.TranscriptionGranularityText && granularity != entity.TranscriptionGranularitySegment {
+			return i18n.T(ctx, "whisper.granularity"), nil
+		}
+		opts.Granularity = granularity
+	case "reset":
+		opts = entity.TranscriptionOptions{}
+	default:
+		return usage, nil
+	}
+
+	// Сохранение настроек
+	user.TranscriptionOptions = opts
+	err = uc.userRepo.Update(ctx, user)
+	if err != nil {
+		uc.logger.Error("Failed to update user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// Логирование успешной обработки команды /whisper
+	uc.logger.Info("Successfully handled /whisper command",
+		"telegram_id", telegramID,
+		"option", option,
+	)
+
+	return i18n.T(ctx, "whisper.saved") + "\n\n" + formatTranscriptionOptions(ctx, opts), nil
+}
+
+// formatTranscriptionOptions формирует описание пользовательских параметров Whisper
+func formatTranscriptionOptions(ctx context.Context, opts entity.TranscriptionOptions) string {
+	temperature := i18n.T(ctx, "whisper.default")
+	if opts.Temperature != nil {
+		temperature = strconv.FormatFloat(float64(*opts.Temperature), 'f', -1, 32)
+	}
+
+	prompt := i18n.T(ctx, "whisper.default")
+	if opts.Prompt != "" {
+		prompt = opts.Prompt
+	}
+
+	granularity := i18n.T(ctx, "whisper.default")
+	if opts.Granularity != "" {
+		granularity = string(opts.Granularity)
+	}
+
+	return i18n.T(ctx, "whisper.options", temperature, prompt, granularity)
+}
+
+// HandleLanguage обрабатывает команду /language
+func (uc *TelegramHandlersUseCase) HandleLanguage(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /language
+	uc.logger.Info("Handling /language command",
+		"telegram_id", telegramID,
+	)
+
+	usage := i18n.T(ctx, "language.usage", SummaryLanguageAuto, strings.Join(summaryLanguageCodes, "|"))
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	args = strings.TrimSpace(args)
+	if args == "" {
+		// Показ текущей настройки
+		return formatSummaryLanguage(ctx, user.SummaryLanguage) + "\n\n" + usage, nil
+	}
+
+	language, ok := normalizeSummaryLanguage(args)
+	if !ok {
+		return i18n.T(ctx, "language.unsupported") + "\n\n" + usage, nil
+	}
+
+	// Сохранение настройки
+	user.SummaryLanguage = language
+	err = uc.userRepo.Update(ctx, user)
+	if err != nil {
+		uc.logger.Error("Failed to update user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// Логирование успешной обработки команды /language
+	uc.logger.Info("Successfully handled /language command",
+		"telegram_id", telegramID,
+		"summary_language", language,
+	)
+
+	return i18n.T(ctx, "language.saved") + " " + formatSummaryLanguage(ctx, language), nil
+}
+
+// HandleSettings обрабатывает команду /settings и возвращает описание настроек пользователя с кнопками их выбора
+func (uc *TelegramHandlersUseCase) HandleSettings(ctx context.Context, telegramID int64) (string, [][]entity.MessageButton, error) {
+	// Логирование начала обработки команды /settings
+	uc.logger.Info("Handling /settings command",
+		"telegram_id", telegramID,
+	)
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	settings, err := uc.userSettingsUseCase.Get(ctx, user.ID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return formatUserSettings(ctx, settings, user.NotionToken != "" && user.NotionDatabaseID != ""), userSettingsButtons(ctx, settings), nil
+}
+
+// HandleSettingsAction обрабатывает нажатие кнопки команды /settings: сохраняет выбранное значение
+// и возвращает обновленное описание настроек, кнопки и ответ на нажатие
+func (uc *TelegramHandlersUseCase) HandleSettingsAction(ctx context.Context, telegramID int64, data string) (string, [][]entity.MessageButton, string, error) {
+	// Логирование нажатия кнопки
+	uc.logger.Info("Handling settings action",
+		"telegram_id", telegramID,
+		"data", data,
+	)
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", nil, "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	settings, err := uc.userSettingsUseCase.Apply(ctx, user.ID, data)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	// После отказа от статистики удаляются и уже записанные события пользователя
+	if settings.UsageAnalyticsOptOut && strings.HasPrefix(data, settingUsageAnalytics+":") {
+		if err := uc.usageAnalyticsUseCase.Forget(ctx, telegramID); err != nil {
+			return "", nil, "", err
+		}
+	}
+
+	// После смены языка интерфейса описание настроек показывается уже на новом языке
+	ctx = i18n.WithLanguage(ctx, uc.interfaceLanguageUseCase.ForUser(ctx, user))
+
+	return formatUserSettings(ctx, settings, user.NotionToken != "" && user.NotionDatabaseID != ""), userSettingsButtons(ctx, settings), i18n.T(ctx, "settings.saved"), nil
+}
+
+// formatSummaryLanguage формирует описание языка кратких содержаний пользователя
+func formatSummaryLanguage(ctx context.Context, language string) string {
+	if _, ok := summaryLanguageNames[language]; ok {
+		return i18n.T(ctx, "language.current", i18n.T(ctx, "language.name."+language))
+	}
+	return i18n.T(ctx, "language.current_auto")
+}
+
+// HandleConfigExport обрабатывает команду /config export и возвращает содержимое файла настроек
+func (uc *TelegramHandlersUseCase) HandleConfigExport(ctx context.Context, telegramID int64) ([]byte, error) {
+	// Логирование начала обработки команды /config export
+	uc.logger.Info("Handling /config export command",
+		"telegram_id", telegramID,
+	)
+
+	return uc.userConfigUseCase.Export(ctx, telegramID)
+}
+
+// HandleConfigImport обрабатывает команду /config import с содержимым файла настроек
+func (uc *TelegramHandlersUseCase) HandleConfigImport(ctx context.Context, telegramID int64, data []byte) (string, error) {
+	// Логирование начала обработки команды /config import
+	uc.logger.Info("Handling /config import command",
+		"telegram_id", telegramID,
+		"size", len(data),
+	)
+
+	config, problem := parseUserConfig(ctx, data)
+	if problem != "" {
+		return i18n.T(ctx, "config.invalid", problem) + "\n\n" + i18n.T(ctx, "config.usage"), nil
+	}
+
+	result, err := uc.userConfigUseCase.Import(ctx, telegramID, config)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, "config.imported") + "\n")
+	if len(config.Podcasts) > 0 {
+		builder.WriteString(i18n.T(ctx, "config.podcasts", result.PodcastsAdded, result.PodcastsSkipped) + "\n")
+	}
+	if len(result.PodcastsFailed) > 0 {
+		builder.WriteString(i18n.T(ctx, "config.podcasts_failed") + "\n")
+		for _, feedURL := range result.PodcastsFailed {
+			builder.WriteString("• " + feedURL + "\n")
+		}
+	}
+	if config.Notion != nil && !result.NotionConnected {
+		builder.WriteString(i18n.T(ctx, "config.notion_missing") + "\n")
+	}
+
+	return strings.TrimSpace(builder.String()), nil
+}
+
+// HandlePodcast обрабатывает команду /podcast
+func (uc *TelegramHandlersUseCase) HandlePodcast(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /podcast
+	uc.logger.Info("Handling /podcast command",
+		"telegram_id", telegramID,
+	)
+
+	usage := i18n.T(ctx, "podcast.usage")
+
+	action, value, _ := strings.Cut(strings.TrimSpace(args), " ")
+	value = strings.TrimSpace(value)
+
+	switch strings.ToLower(action) {
+	case "add":
+		if value == "" {
+			return usage, nil
+		}
+
+		// Проверка, что пользователь еще не подписан на ленту
+		feeds, err := uc.podcastUseCase.ListFeeds(ctx, telegramID)
+		if err != nil {
+			return "", fmt.Errorf("failed to list podcast feeds: %w", err)
+		}
+		for _, feed := range feeds {
+			if feed.URL == value {
+				return i18n.T(ctx, "podcast.exists", feed.Title, feed.ID), nil
+			}
+		}
+
+		feed, err := uc.podcastUseCase.AddFeed(ctx, telegramID, value)
+		if err != nil {
+			uc.logger.Warn("Failed to add podcast feed",
+				"error", err,
+				"telegram_id", telegramID,
+			)
+			return i18n.T(ctx, "podcast.add_failed"), nil
+		}
+
+		response := i18n.T(ctx, "podcast.added", feed.Title, feed.ID)
+		if feed.NotionDatabaseID != "" {
+			response += "\n\n" + i18n.T(ctx, "podcast.notion", notionURL(feed.NotionDatabaseID))
+		}
+		return response, nil
+	case "remove":
+		feedID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return usage, nil
+		}
+
+		removed, err := uc.podcastUseCase.RemoveFeed(ctx, telegramID, feedID)
+		if err != nil {
+			return "", fmt.Errorf("failed to remove podcast feed: %w", err)
+		}
+		if !removed {
+			return i18n.T(ctx, "podcast.not_found"), nil
+		}
+		return i18n.T(ctx, "podcast.removed"), nil
+	case "", "list":
+		feeds, err := uc.podcastUseCase.ListFeeds(ctx, telegramID)
+		if err != nil {
+			return "", fmt.Errorf("failed to list podcast feeds: %w", err)
+		}
+		if len(feeds) == 0 {
+			return i18n.T(ctx, "podcast.empty") + "\n\n" + usage, nil
+		}
+
+		messageBuilder := strings.Builder{}
+		messageBuilder.WriteString(i18n.T(ctx, "podcast.list") + "\n\n")
+		for _, feed := range feeds {
+			messageBuilder.WriteString(fmt.Sprintf("%d. %s\n%s\n", feed.ID, feed.Title, feed.URL))
+			if feed.LastCheckedAt != nil {
+				messageBuilder.WriteString(i18n.T(ctx, "podcast.checked", feed.LastCheckedAt.Format("02.01.2006 15:04")) + "\n")
+			}
+			messageBuilder.WriteString("\n")
+		}
+		messageBuilder.WriteString(usage)
+		return messageBuilder.String(), nil
+	default:
+		return usage, nil
+	}
+}
+
+// HandleTeam обрабатывает команду /team: создание команды, вступление по коду и настройку
+// канала для ежедневного дайджеста. chatID отличается от telegramID, если команда отправлена из группы
+func (uc *TelegramHandlersUseCase) HandleTeam(ctx context.Context, telegramID int64, chatID int64, args string) (string, error) {
+	// Логирование начала обработки команды /team
+	uc.logger.Info("Handling /team command",
+		"telegram_id", telegramID,
+		"chat_id", chatID,
+	)
+
+	usage := i18n.T(ctx, "team.usage")
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	team, members, err := uc.teamDigestUseCase.GetTeam(ctx, user)
+	if err != nil {
+		return "", fmt.Errorf("failed to get team: %w", err)
+	}
+
+	action, value, _ := strings.Cut(strings.TrimSpace(args), " ")
+	value = strings.TrimSpace(value)
+
+	switch strings.ToLower(action) {
+	case "create":
+		if value == "" {
+			return usage, nil
+		}
+		if team != nil {
+			return i18n.T(ctx, "team.create_member", team.Name), nil
+		}
+
+		team, err := uc.teamDigestUseCase.CreateTeam(ctx, user, truncateRunes(value, 255, false))
+		if err != nil {
+			return "", fmt.Errorf("failed to create team: %w", err)
+		}
+		return i18n.T(ctx, "team.created", team.Name, team.InviteCode, team.InviteCode, team.DigestHour, uc.teamDigestUseCase.Location()), nil
+	case "join":
+		if value == "" {
+			return usage, nil
+		}
+		if team != nil {
+			return i18n.T(ctx, "team.join_member", team.Name), nil
+		}
+
+		team, err := uc.teamDigestUseCase.JoinTeam(ctx, user, value)
+		if err != nil {
+			uc.logger.Warn("Failed to join team",
+				"error", err,
+				"telegram_id", telegramID,
+			)
+			return i18n.T(ctx, "team.join_not_found"), nil
+		}
+		return i18n.T(ctx, "team.joined", team.Name), nil
+	case "leave":
+		if team == nil {
+			return i18n.T(ctx, "team.none"), nil
+		}
+
+		disbanded, err := uc.teamDigestUseCase.LeaveTeam(ctx, user, team)
+		if err != nil {
+			return "", fmt.Errorf("failed to leave team: %w", err)
+		}
+		if disbanded {
+			return i18n.T(ctx, "team.disbanded", team.Name), nil
+		}
+		return i18n.T(ctx, "team.left", team.Name), nil
+	case "channel", "hour":
+		if team == nil {
+			return i18n.T(ctx, "team.none") + "\n\n" + usage, nil
+		}
+		if team.OwnerID != user.ID {
+			return i18n.T(ctx, "team.owner_only"), nil
+		}
+
+		if strings.ToLower(action) == "hour" {
+			hour, err := strconv.Atoi(value)
+			if err != nil || hour < 0 || hour > 23 {
+				return usage, nil
+			}
+			if err := uc.teamDigestUseCase.SetDigestHour(ctx, team, hour); err != nil {
+				return "", fmt.Errorf("failed to set team digest hour: %w", err)
+			}
+			return i18n.T(ctx, "team.hour", hour, uc.teamDigestUseCase.Location()), nil
+		}
+
+		// Канал: id из аргумента, иначе группа, из которой отправлена команда
+		var channelID int64
+		switch {
+		case strings.EqualFold(value, "off"):
+			channelID = 0
+		case value != "":
+			channelID, err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return usage, nil
+			}
+		case chatID != telegramID:
+			channelID = chatID
+		default:
+			return i18n.T(ctx, "team.channel_missing"), nil
+		}
+
+		if channelID == 0 {
+			if err := uc.teamDigestUseCase.SetChannel(ctx, team, 0); err != nil {
+				return "", fmt.Errorf("failed to set team channel: %w", err)
+			}
+			return i18n.T(ctx, "team.channel_off"), nil
+		}
+
+		if err := uc.teamDigestUseCase.SetChannel(ctx, team, channelID); err != nil {
+			return i18n.T(ctx, "team.channel_failed"), nil
+		}
+		return i18n.T(ctx, "team.channel_set", team.Name, team.DigestHour, uc.teamDigestUseCase.Location()), nil
+	case "":
+		if team == nil {
+			return i18n.T(ctx, "team.none") + "\n\n" + usage, nil
+		}
+
+		messageBuilder := strings.Builder{}
+		messageBuilder.WriteString(i18n.T(ctx, "team.title", team.Name) + "\n\n")
+		if team.OwnerID == user.ID {
+			messageBuilder.WriteString(i18n.T(ctx, "team.invite_code", team.InviteCode) + "\n")
+		}
+		if team.ChannelID != 0 {
+			messageBuilder.WriteString(i18n.T(ctx, "team.digest", team.DigestHour, uc.teamDigestUseCase.Location()) + "\n")
+		} else {
+			messageBuilder.WriteString(i18n.T(ctx, "team.digest_off") + "\n")
+		}
+		if team.LastDigestAt != nil {
+			messageBuilder.WriteString(i18n.T(ctx, "team.last_digest", team.LastDigestAt.In(uc.teamDigestUseCase.Location()).Format("02.01.2006 15:04")) + "\n")
+		}
+
+		messageBuilder.WriteString("\n" + i18n.T(ctx, "team.members", len(members)) + "\n")
+		for _, member := range members {
+			name := teamMemberName(ctx, member.Username, member.FirstName)
+			if member.UserID == team.OwnerID {
+				name += i18n.T(ctx, "team.owner")
+			}
+			messageBuilder.WriteString("• " + name + "\n")
+		}
+		messageBuilder.WriteString("\n" + usage)
+		return messageBuilder.String(), nil
+	default:
+		return usage, nil
+	}
+}
+
+// HandleLesson обрабатывает команду /lesson: начало и завершение урока чтения вслух.
+// Текст урока берется из аргументов команды или из сообщения, на которое она отправлена ответом
+func (uc *TelegramHandlersUseCase) HandleLesson(ctx context.Context, telegramID int64, args string, replyText string) (string, error) {
+	// Логирование начала обработки команды /lesson
+	uc.logger.Info("Handling /lesson command",
+		"telegram_id", telegramID,
+	)
+
+	usage := i18n.T(ctx, "lesson.usage")
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	text := strings.TrimSpace(args)
+	if strings.EqualFold(text, "off") {
+		ended, err := uc.readingLessonUseCase.EndLesson(ctx, user.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to end reading lesson: %w", err)
+		}
+		if !ended {
+			return i18n.T(ctx, "lesson.not_started"), nil
+		}
+		return i18n.T(ctx, "lesson.ended"), nil
+	}
+	if text == "" {
+		text = strings.TrimSpace(replyText)
+	}
+
+	if text == "" {
+		lesson, err := uc.readingLessonUseCase.GetLesson(ctx, user.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get reading lesson: %w", err)
+		}
+		if lesson == nil {
+			return usage, nil
+		}
+		return i18n.T(ctx, "lesson.current", lesson.ReferenceText), nil
+	}
+
+	if words := len(readingWords(text)); words > readingLessonMaxWords {
+		return i18n.T(ctx, "lesson.too_long", words, readingLessonMaxWords), nil
+	}
+
+	if _, err := uc.readingLessonUseCase.StartLesson(ctx, user.ID, text); err != nil {
+		return "", fmt.Errorf("failed to start reading lesson: %w", err)
+	}
+
+	return i18n.T(ctx, "lesson.started"), nil
+}
+
+// HandleAgenda обрабатывает команду /agenda: повестку встречи для следующей записи.
+// Повестка берется из аргументов команды, из сообщения, на которое она отправлена ответом,
+// или из текстового файла
+func (uc *TelegramHandlersUseCase) HandleAgenda(ctx context.Context, telegramID int64, args string, replyText string) (string, error) {
+	// Логирование начала обработки команды /agenda
+	uc.logger.Info("Handling /agenda command",
+		"telegram_id", telegramID,
+	)
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	text := strings.TrimSpace(args)
+	if strings.EqualFold(text, "off") {
+		cleared, err := uc.meetingAgendaUseCase.Clear(ctx, user.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to clear meeting agenda: %w", err)
+		}
+		if !cleared {
+			return i18n.T(ctx, "agenda.none"), nil
+		}
+		return i18n.T(ctx, "agenda.cleared"), nil
+	}
+	if text == "" {
+		text = strings.TrimSpace(replyText)
+	}
+
+	if text == "" {
+		agenda, err := uc.meetingAgendaUseCase.GetPending(ctx, user.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get meeting agenda: %w", err)
+		}
+		if agenda == nil {
+			return i18n.T(ctx, "agenda.usage"), nil
+		}
+		return i18n.T(ctx, "agenda.current", agenda.Text), nil
+	}
+
+	if !utf8.ValidString(text) {
+		return i18n.T(ctx, "agenda.not_utf8"), nil
+	}
+	if length := utf8.RuneCountInString(text); length > meetingAgendaMaxLength {
+		return i18n.T(ctx, "agenda.too_long", length, meetingAgendaMaxLength), nil
+	}
+	if items := len(parseAgendaItems(text)); items > meetingAgendaMaxItems {
+		return i18n.T(ctx, "agenda.too_many", items, meetingAgendaMaxItems), nil
+	}
+
+	agenda, err := uc.meetingAgendaUseCase.SetAgenda(ctx, user.ID, text)
+	if err != nil {
+		return "", fmt.Errorf("failed to set meeting agenda: %w", err)
+	}
+
+	return i18n.T(ctx, "agenda.saved", CountAgendaItems(agenda), agenda.Text), nil
+}
+
+// meetingAgendaNote возвращает строку ответа на аудио о том, что краткое содержание будет составлено
+// по ожидающей повестке пользователя, или пустую строку, если повестки нет
+func (uc *TelegramHandlersUseCase) meetingAgendaNote(ctx context.Context, userID int64) (string, error) {
+	agenda, err := uc.meetingAgendaUseCase.GetPending(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get meeting agenda: %w", err)
+	}
+	if agenda == nil {
+		return "", nil
+	}
+
+	return i18n.T(ctx, "accepted.agenda", CountAgendaItems(agenda)), nil
+}
+
+// HandleWebhook обрабатывает команду /webhook: настройку вебхука пользователя для сценариев n8n и Zapier
+func (uc *TelegramHandlersUseCase) HandleWebhook(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /webhook
+	uc.logger.Info("Handling /webhook command",
+		"telegram_id", telegramID,
+	)
+
+	usage := i18n.T(ctx, "webhook.usage")
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	webhook, err := uc.userWebhookUseCase.GetWebhook(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	action, value, _ := strings.Cut(strings.TrimSpace(args), " ")
+	value = strings.TrimSpace(value)
+
+	switch strings.ToLower(action) {
+	case "":
+		if webhook == nil {
+			return i18n.T(ctx, "webhook.none") + "\n\n" + usage, nil
+		}
+		return formatUserWebhook(ctx, webhook) + "\n\n" + usage, nil
+	case "set":
+		if !validateWebhookURL(value) {
+			return i18n.T(ctx, "webhook.url") + "\n\n" + usage, nil
+		}
+		webhook, err := uc.userWebhookUseCase.SetURL(ctx, user.ID, value)
+		if err != nil {
+			return "", err
+		}
+		return i18n.T(ctx, "webhook.saved", formatUserWebhook(ctx, webhook)), nil
+	case "off":
+		deleted, err := uc.userWebhookUseCase.DeleteWebhook(ctx, user.ID)
+		if err != nil {
+			return "", err
+		}
+		if !deleted {
+			return i18n.T(ctx, "webhook.none"), nil
+		}
+		return i18n.T(ctx, "webhook.deleted"), nil
+	}
+
+	// Остальные действия изменяют уже настроенный вебхук
+	if webhook == nil {
+		return i18n.T(ctx, "webhook.set_first"), nil
+	}
+
+	switch strings.ToLower(action) {
+	case "events":
+		events, ok := parseWebhookEvents(value)
+		if !ok || value == "" {
+			return usage, nil
+		}
+		if err := uc.userWebhookUseCase.SetEvents(ctx, webhook, events); err != nil {
+			return "", err
+		}
+		return i18n.T(ctx, "webhook.events_saved") + "\n\n" + formatUserWebhook(ctx, webhook), nil
+	case "header":
+		name, headerValue, _ := strings.Cut(value, " ")
+		headerValue = strings.TrimSpace(headerValue)
+		if !webhookHeaderNamePattern.MatchString(name) || headerValue == "" {
+			return usage, nil
+		}
+		if reservedWebhookHeaders[http.CanonicalHeaderKey(name)] {
+			return i18n.T(ctx, "webhook.header_reserved", http.CanonicalHeaderKey(name)), nil
+		}
+		if strings.EqualFold(headerValue, "off") {
+			headerValue = ""
+		}
+
+		saved, err := uc.userWebhookUseCase.SetHeader(ctx, webhook, name, headerValue)
+		if err != nil {
+			return "", err
+		}
+		if !saved {
+			return i18n.T(ctx, "webhook.header_limit", maxWebhookHeaders), nil
+		}
+		return i18n.T(ctx, "webhook.headers_saved") + "\n\n" + formatUserWebhook(ctx, webhook), nil
+	case "test":
+		// Причина ошибки только логируется: по ней можно было бы узнать, какие адреса и порты
+		// открыты в сети, где работает бот
+		if err := uc.userWebhookUseCase.SendTest(ctx, user, webhook); err != nil {
+			return i18n.T(ctx, "webhook.test_failed"), nil
+		}
+		return i18n.T(ctx, "webhook.test_delivered", webhook.URL), nil
+	default:
+		return usage, nil
+	}
+}
+
+// formatUserWebhook описывает настройки вебхука для пользователя. Значения заголовков
+// скрываются, поскольку обычно содержат ключи доступа
+func formatUserWebhook(ctx context.Context, webhook *entity.UserWebhook) string {
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, "webhook.url_line", webhook.URL) + "\n")
+
+	events := i18n.T(ctx, "webhook.events_all")
+	if len(webhook.Events) > 0 {
+		names := make([]string, len(webhook.Events))
+		for i, event := range webhook.Events {
+			names[i] = string(event)
+		}
+		events = strings.Join(names, ", ")
+	}
+	builder.WriteString(i18n.T(ctx, "webhook.events", events))
+
+	if len(webhook.Headers) > 0 {
+		names := make([]string, 0, len(webhook.Headers))
+		for name := range webhook.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		builder.WriteString("\n" + i18n.T(ctx, "webhook.headers"))
+		for _, name := range names {
+			builder.WriteString("\n• " + name + ": " + maskSecret(webhook.Headers[name]))
+		}
+	}
+
+	return builder.String()
+}
+
+// maskSecret скрывает значение, оставляя первые символы для узнавания
+func maskSecret(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 4 {
+		return "****"
+	}
+	return string(runes[:2]) + "****"
+}
+
+// HandleReconcile обрабатывает административную команду /reconcile: сверку задач с Notion.
+// Права администратора проверяются при регистрации команды (entity.CommandRequirementAdmin)
+func (uc *TelegramHandlersUseCase) HandleReconcile(ctx context.Context, telegramID int64) (string, error) {
+	// Логирование начала обработки команды /reconcile
+	uc.logger.Info("Handling /reconcile command",
+		"telegram_id", telegramID,
+	)
+
+	result, err := uc.notionReconcileUseCase.Reconcile(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to reconcile jobs with Notion: %w", err)
+	}
+
+	// Логирование успешной обработки команды /reconcile
+	uc.logger.Info("Successfully handled /reconcile command",
+		"telegram_id", telegramID,
+	)
+
+	return i18n.T(ctx, "reconcile.done", result.Checked, result.Recreated, result.Cleared, result.Failed), nil
+}
+
+// maxFailureReportDays - максимальный период отчетов /admin failures и /admin usage в днях
+const maxFailureReportDays = 90
+
+// failureCategoryTitles - идентификаторы сообщений с названиями категорий ошибок для отчета
+var failureCategoryTitles = map[entity.FailureCategory]string{
+	entity.FailureCategoryTimeout:      "failure_category.timeout",
+	entity.FailureCategoryRateLimit:    "failure_category.rate_limit",
+	entity.FailureCategoryAuth:         "failure_category.auth",
+	entity.FailureCategoryNetwork:      "failure_category.network",
+	entity.FailureCategoryInvalidInput: "failure_category.invalid_input",
+	entity.FailureCategoryBudget:       "failure_category.budget",
+	entity.FailureCategoryOther:        "failure_category.other",
+}
+
+// HandleAdmin обрабатывает административную команду /admin. Права администратора проверяются
+// при регистрации команды (entity.CommandRequirementAdmin)
+func (uc *TelegramHandlersUseCase) HandleAdmin(ctx context.Context, telegramID int64, args string) (string, error) {
+	// Логирование начала обработки команды /admin
+	uc.logger.Info("Handling /admin command",
+		"telegram_id", telegramID,
+	)
+
+	usage := i18n.T(ctx, "admin.usage", maxFailureReportDays)
+
+	action, value, _ := strings.Cut(strings.TrimSpace(args), " ")
+	switch strings.ToLower(action) {
+	case "failures":
+		days := 7
+		if value = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), "d"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 || parsed > maxFailureReportDays {
+				return usage, nil
+			}
+			days = parsed
+		}
+
+		rollups, err := uc.failureAnalyticsUseCase.Report(ctx, days)
+		if err != nil {
+			return "", fmt.Errorf("failed to get failure report: %w", err)
+		}
+		return formatFailureReport(ctx, rollups, days), nil
+	case "usage":
+		days := 7
+		if value = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), "d"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 || parsed > maxFailureReportDays {
+				return usage, nil
+			}
+			days = parsed
+		}
+
+		return uc.usageAnalyticsUseCase.Report(ctx, days)
+	case "user":
+		idValue, reason, _ := strings.Cut(strings.TrimSpace(value), " ")
+		targetID, err := strconv.ParseInt(idValue, 10, 64)
+		if err != nil {
+			return usage, nil
+		}
+
+		inspection, err := uc.adminSupportUseCase.InspectUser(ctx, telegramID, targetID, strings.TrimSpace(reason))
+		if err != nil {
+			return "", fmt.Errorf("failed to inspect user: %w", err)
+		}
+		if inspection == nil {
+			return i18n.T(ctx, "admin.user.not_found", targetID), nil
+		}
+		return truncateRunes(formatUserInspection(ctx, inspection), telegramMessageLimit, false), nil
+	case "audit":
+		entries, err := uc.adminSupportUseCase.RecentAudit(ctx, telegramID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get admin audit: %w", err)
+		}
+		return formatAdminAudit(ctx, entries), nil
+	case "doctor":
+		return uc.diagnosticsUseCase.Run(ctx), nil
+	case "models":
+		return uc.modelComparisonUseCase.Report(ctx)
+	default:
+		return usage, nil
+	}
+}
+
+// maxInspectErrorLength - ограничение длины ошибки задачи в отчете /admin user
+const maxInspectErrorLength = 200
+
+// formatUserInspection формирует отчет о пользователе для администратора. Секреты пользователя
+// (токен Notion, адрес и заголовки вебхука) в отчет не попадают
+func formatUserInspection(ctx context.Context, inspection *entity.UserInspection) string {
+	user := inspection.User
+
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, "admin.user.title", user.TelegramID))
+	if user.Username != "" {
+		builder.WriteString(" @" + user.Username)
+	}
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		builder.WriteString(" (" + name + ")")
+	}
+	builder.WriteString("\n" + i18n.T(ctx, "admin.user.registered", user.ID, user.CreatedAt.Format("02.01.2006 15:04")))
+	if user.IsAdmin {
+		builder.WriteString("\n" + i18n.T(ctx, "admin.user.admin"))
+	}
+	if user.BannedAt != nil {
+		builder.WriteString("\n" + i18n.T(ctx, "admin.user.banned", user.BannedAt.Format("02.01.2006 15:04")))
+		if user.BanReason != "" {
+			builder.WriteString(": " + user.BanReason)
+		}
+	}
+
+	// Настройки
+	builder.WriteString("\n\n" + i18n.T(ctx, "admin.user.settings"))
+	notion := i18n.T(ctx, "admin.user.notion_off")
+	if user.NotionToken != "" && user.NotionDatabaseID != "" {
+		notion = i18n.T(ctx, "admin.user.notion_on", user.NotionDatabaseID)
+	} else if user.NotionToken != "" {
+		notion = i18n.T(ctx, "admin.user.notion_token")
+	}
+	builder.WriteString("\n• Notion: " + notion)
+
+	retention := i18n.T(ctx, "admin.user.retention_off")
+	if user.ResultRetentionHours > 0 {
+		retention = i18n.T(ctx, "admin.user.retention_on", user.ResultRetentionHours)
+	}
+	builder.WriteString("\n• " + i18n.T(ctx, "admin.user.retention", retention))
+	builder.WriteString("\n• " + formatSummaryLanguage(ctx, user.SummaryLanguage))
+	builder.WriteString("\n• " + strings.NewReplacer(":\n", ": ", "\n", "; ").Replace(formatTranscriptionOptions(ctx, user.TranscriptionOptions)))
+
+	webhook := i18n.T(ctx, "admin.user.webhook_off")
+	if inspection.Webhook != nil {
+		host := i18n.T(ctx, "admin.user.webhook_host")
+		if parsed, err := url.Parse(inspection.Webhook.URL); err == nil {
+			host = parsed.Host
+		}
+		events := i18n.T(ctx, "admin.user.webhook_events")
+		if len(inspection.Webhook.Events) > 0 {
+			names := make([]string, len(inspection.Webhook.Events))
+			for i, event := range inspection.Webhook.Events {
+				names[i] = string(event)
+			}
+			events = strings.Join(names, ", ")
+		}
+		webhook = fmt.Sprintf("%s (%s)", host, events)
+	}
+	builder.WriteString("\n• " + i18n.T(ctx, "admin.user.webhook", webhook))
+
+	// Последние задачи
+	if len(inspection.RecentJobs) == 0 {
+		builder.WriteString("\n\n" + i18n.T(ctx, "admin.user.no_jobs"))
+		return builder.String()
+	}
+
+	builder.WriteString("\n\n" + i18n.T(ctx, "admin.user.jobs", len(inspection.RecentJobs)))
+	for _, job := range inspection.RecentJobs {
+		builder.WriteString(fmt.Sprintf("\n• %d — %s — %s — %s", job.ID, job.CreatedAt.Format("02.01 15:04"), job.Status, jobTitle(ctx, job)))
+		if job.ErrorMessage != "" {
+			builder.WriteString("\n   " + i18n.T(ctx, "status.error", truncateRunes(job.ErrorMessage, maxInspectErrorLength, false)))
+		}
+	}
+
+	// Подробности последней ошибки
+	if failure := inspection.LastFailure; failure != nil {
+		builder.WriteString("\n\n" + i18n.T(ctx, "admin.user.last_failure", failure.ID, failure.CreatedAt.Format("02.01.2006 15:04")) + "\n")
+		if failure.ErrorMessage != "" {
+			builder.WriteString(failure.ErrorMessage + "\n")
+		}
+		writeJobStages(ctx, &builder, inspection.FailureStages)
+	}
+
+	return builder.String()
+}
+
+// formatAdminAudit формирует журнал действий администраторов
+func formatAdminAudit(ctx context.Context, entries []*entity.AdminAuditEntry) string {
+	if len(entries) == 0 {
+		return i18n.T(ctx, "admin.audit.empty")
+	}
+
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, "admin.audit.title"))
+	for _, entry := range entries {
+		builder.WriteString(fmt.Sprintf("\n• %s — %d — %s", entry.CreatedAt.Format("02.01.2006 15:04"), entry.AdminTelegramID, entry.Action))
+		if entry.TargetTelegramID != 0 {
+			builder.WriteString(i18n.T(ctx, "admin.audit.target", entry.TargetTelegramID))
+		}
+		if entry.Details != "" {
+			builder.WriteString(": " + entry.Details)
+		}
+	}
+
+	return builder.String()
+}
+
+// formatFailureReport формирует отчет об ошибках из дневных сводок
+func formatFailureReport(ctx context.Context, rollups []*entity.FailureRollup, days int) string {
+	total := 0
+	byProvider := make(map[string]int)
+	byStage := make(map[string]int)
+	byCategory := make(map[string]int)
+	byDay := make(map[string]int)
+	dayOrder := make([]string, 0)
+	for _, rollup := range rollups {
+		total += rollup.Failures
+		byProvider[rollup.Provider] += rollup.Failures
+		byStage[jobStageTitle(ctx, rollup.Stage)] += rollup.Failures
+		byCategory[failureCategoryTitle(ctx, rollup.Category)] += rollup.Failures
+
+		day := rollup.Day.Format("02.01")
+		if _, ok := byDay[day]; !ok {
+			dayOrder = append(dayOrder, day)
+		}
+		byDay[day] += rollup.Failures
+	}
+
+	if total == 0 {
+		return i18n.T(ctx, "admin.failures.none", days)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, "admin.failures.title", days, total))
+	writeFailureCounts(&builder, i18n.T(ctx, "admin.failures.by_provider"), byProvider)
+	writeFailureCounts(&builder, i18n.T(ctx, "admin.failures.by_stage"), byStage)
+	writeFailureCounts(&builder, i18n.T(ctx, "admin.failures.by_category"), byCategory)
+
+	// Дни выводятся в хронологическом порядке
+	builder.WriteString("\n\n" + i18n.T(ctx, "admin.failures.by_day") + ":")
+	for _, day := range dayOrder {
+		builder.WriteString(fmt.Sprintf("\n• %s — %d", day, byDay[day]))
+	}
+
+	return builder.String()
+}
+
+// writeFailureCounts добавляет в отчет раздел с числом ошибок по убыванию
+func writeFailureCounts(builder *strings.Builder, title string, counts map[string]int) {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	builder.WriteString("\n\n" + title + ":")
+	for _, name := range names {
+		builder.WriteString(fmt.Sprintf("\n• %s — %d", name, counts[name]))
+	}
+}
+
+// failureCategoryTitle возвращает название категории ошибки для отчета
+func failureCategoryTitle(ctx context.Context, category entity.FailureCategory) string {
+	if title, ok := failureCategoryTitles[category]; ok {
+		return i18n.T(ctx, title)
+	}
+	return string(category)
+}
+
+// HandleTextMessage обрабатывает текстовое сообщение
+func (uc *TelegramHandlersUseCase) HandleTextMessage(ctx context.Context, telegramID int64, username string, messageID int, text string, replyText string) (string, error) {
+	// Логирование начала обработки текстового сообщения
+	uc.logger.Info("Handling text message",
+		"telegram_id", telegramID,
+		"text_length", len(text),
+	)
+
+	// Определение текста для суммаризации
+	sourceText := ""
+	if replyText != "" && IsSummarizeReply(text) {
+		sourceText = replyText
+	} else if utf8.RuneCountInString(strings.TrimSpace(text)) >= uc.textMinLength {
+		sourceText = text
+	}
+
+	// Короткий текст без команды не обрабатываем
+	if sourceText == "" {
+		return i18n.T(ctx, "text.hint"), nil
+	}
+
+	// Создание текстовой задачи
+	jobID, err := uc.textProcessingUseCase.ProcessText(ctx, telegramID, username, sourceText, messageID)
+	if err != nil {
+		uc.logger.Error("Failed to process text",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to process text: %w", err)
+	}
+
+	// Формирование сообщения об успешном начале обработки
+	responseMessage := i18n.T(ctx, "accepted.text") + i18n.T(ctx, "accepted.job_id", jobID)
+
+	// Учет созданной задачи в статистике использования
+	uc.usageAnalyticsUseCase.Track(ctx, telegramID, entity.UsageEventJobCreated, usageSourceText)
+
+	// Логирование успешного начала обработки текстового сообщения
+	uc.logger.Info("Successfully started processing text message",
+		"telegram_id", telegramID,
+		"job_id", jobID,
+	)
+
+	return responseMessage, nil
+}
+
+// IsSummarizeReply сообщает, что текст - ответ «суммаризируй», которым на суммаризацию отправляется
+// текст сообщения, на которое ответил пользователь
+func IsSummarizeReply(text string) bool {
+	return strings.EqualFold(strings.Trim(strings.TrimSpace(text), ".!"), summarizeReplyCommand)
+}
+
+// HandleDuplicateFile проверяет, не присылал ли пользователь этот файл раньше (например, дважды переслав
+// свое голосовое сообщение). Для повторного файла вместо новой обработки присылается прошлый результат
+// или сообщение, что файл еще обрабатывается, и возвращается true. Файлы, обработка которых завершилась
+// ошибкой, можно прислать заново
+func (uc *TelegramHandlersUseCase) HandleDuplicateFile(ctx context.Context, telegramID int64, messageID int, fileUniqueID string) (bool, error) {
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil || user == nil {
+		// Новый пользователь еще ничего не присылал
+		return false, nil
+	}
+
+	job, err := uc.jobRepo.GetLatestByFileUniqueID(ctx, user.ID, fileUniqueID)
+	if err != nil {
+		uc.logger.Error("Failed to find job by file unique id",
+			"error", err,
+		)
+		return false, fmt.Errorf("failed to find job by file unique id: %w", err)
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	// Логирование повторно присланного файла
+	uc.logger.Info("Duplicate file submission",
+		"telegram_id", telegramID,
+		"job_id", job.ID,
+		"status", job.Status,
+	)
+
+	if job.Status != entity.JobStatusCompleted {
+		text := i18n.T(ctx, "duplicate_file.processing", job.ID, job.ID)
+		if _, err := uc.messenger.SendReply(telegramID, messageID, text); err != nil {
+			return false, fmt.Errorf("failed to send duplicate file notice: %w", err)
+		}
+		return true, nil
+	}
+
+	// Прошлый результат: краткое содержание, а если его нет - транскрипция
+	messageBuilder := strings.Builder{}
+	messageBuilder.WriteString(i18n.T(ctx, "duplicate_file.done", job.ID, job.CreatedAt.Format("02.01.2006 15:04")) + "\n\n")
+	if job.Summary != "" {
+		messageBuilder.WriteString(i18n.T(ctx, "completion.summary"))
+		messageBuilder.WriteString(job.Summary)
+	} else {
+		messageBuilder.WriteString(i18n.T(ctx, "completion.transcription"))
+		messageBuilder.WriteString(job.Transcription)
+	}
+	messageBuilder.WriteString("\n\n" + i18n.T(ctx, "completion.full_result", job.ID))
+
+	err = uc.messageRetentionUseCase.SendResult(ctx, telegramID, messageID, messageBuilder.String(), jobResultButtons(ctx, job))
+	if err != nil {
+		return false, fmt.Errorf("failed to send previous job result: %w", err)
+	}
+
+	return true, nil
+}
+
+// HandleVoiceMessage обрабатывает голосовое сообщение и возвращает ответ и ID созданной задачи.
+// Короткое сообщение обрабатывается сразу, и ответ пуст: вместо него приходит результат
+func (uc *TelegramHandlersUseCase) HandleVoiceMessage(ctx context.Context, telegramID int64, username string, messageID int, fileID string, fileUniqueID string, filePath string, fileName string, caption string) (string, int64, error) {
+	// Логирование начала обработки голосового сообщения
+	uc.logger.Info("Handling voice message",
+		"telegram_id", telegramID,
+		"file_id", fileID,
+	)
+
+	// Получение или создание пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		// Если пользователь не найден, создаем нового
+		user = &entity.User{
+			TelegramID:       telegramID,
+			Username:         username,
+			CreatedAt:        time.Now(),
+			UpdatedAt:        time.Now(),
+			NotionToken:      "",
+			NotionDatabaseID: "",
+		}
+
+		err = uc.userRepo.Create(ctx, user)
+		if err != nil {
+			uc.logger.Error("Failed to create user",
+				"error", err,
+			)
+			return "", 0, fmt.Errorf("failed to create user: %w", err)
+		}
+
+		// ID пользователя уже установлен в методе Create
+	}
+
+	// Во время урока чтения голосовое сообщение сравнивается с текстом урока вместо суммаризации
+	lesson, err := uc.readingLessonUseCase.GetLesson(ctx, user.ID)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get reading lesson: %w", err)
+	}
+	if lesson != nil {
+		jobID, err := uc.readingLessonUseCase.StartAttempt(ctx, lesson, messageID, filePath)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to start reading attempt: %w", err)
+		}
+
+		responseMessage := i18n.T(ctx, "accepted.reading", jobID) + "\n\n" + i18n.T(ctx, "accepted.audio_profile")
+		return responseMessage, jobID, nil
+	}
+
+	// Во время live-сессии голосовое сообщение дописывается в транскрипцию сессии без отдельной задачи
+	session, err := uc.liveSessionUseCase.GetSession(ctx, user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+	if session != nil {
+		responseMessage, err := uc.liveSessionUseCase.AddVoice(ctx, user, filePath)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to add voice message to live session: %w", err)
+		}
+		return responseMessage, 0, nil
+	}
+
+	// Ожидающая повестка встречи привязывается к задаче при ее создании
+	agendaNote, err := uc.meetingAgendaNote(ctx, user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// Обработка голосового сообщения; короткое сообщение обрабатывается сразу
+	jobID, processed, err := uc.audioProcessingUseCase.ProcessVoice(ctx, telegramID, messageID, filePath, fileName, fileUniqueID, strings.TrimSpace(caption))
+	if err != nil {
+		uc.logger.Error("Failed to process audio file",
+			"error", err,
+		)
+		return "", 0, fmt.Errorf("failed to process audio file: %w", err)
+	}
+
+	// Учет созданной задачи в статистике использования
+	uc.usageAnalyticsUseCase.Track(ctx, telegramID, entity.UsageEventJobCreated, usageSourceVoice)
+
+	// Результат уже отправлен, поэтому сообщение о начале обработки не нужно
+	if processed {
+		uc.logger.Info("Voice message processed bypassing queue",
+			"telegram_id", telegramID,
+			"user_id", user.ID,
+			"job_id", jobID,
+		)
+		return "", jobID, nil
+	}
+
+	// Формирование сообщения об успешном начале обработки
+	responseMessage := i18n.T(ctx, "accepted.voice") + i18n.T(ctx, "accepted.job_id", jobID)
+	if strings.TrimSpace(caption) != "" {
+		responseMessage += i18n.T(ctx, "accepted.caption")
+	}
+	responseMessage += agendaNote
+	responseMessage += "\n\n" + i18n.T(ctx, "accepted.audio_profile")
+
+	// Логирование успешного начала обработки голосового сообщения
+	uc.logger.Info("Successfully started processing voice message",
+		"telegram_id", telegramID,
+		"user_id", user.ID,
+		"job_id", jobID,
+	)
+
+	return responseMessage, jobID, nil
+}
+
+// HandleAudioFile обрабатывает аудио файл и возвращает ответ и ID созданной задачи
+func (uc *TelegramHandlersUseCase) HandleAudioFile(ctx context.Context, telegramID int64, username string, messageID int, fileID string, fileUniqueID string, filePath string, fileName string, caption string) (string, int64, error) {
+	// Логирование начала обработки аудио файла
+	uc.logger.Info("Handling audio file",
+		"telegram_id", telegramID,
+		"file_id", fileID,
+	)
+
+	// Получение или создание пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		// Если пользователь не найден, создаем нового
+		user = &entity.User{
+			TelegramID:       telegramID,
+			Username:         username,
+			CreatedAt:        time.Now(),
+			UpdatedAt:        time.Now(),
+			NotionToken:      "",
+			NotionDatabaseID: "",
+		}
+
+		err = uc.userRepo.Create(ctx, user)
+		if err != nil {
+			uc.logger.Error("Failed to create user",
+				"error", err,
+			)
+			return "", 0, fmt.Errorf("failed to create user: %w", err)
+		}
+
+		// ID пользователя устанавливается внутри метода Create
+	}
+
+	// Ожидающая повестка встречи привязывается к задаче при ее создании
+	agendaNote, err := uc.meetingAgendaNote(ctx, user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// Обработка аудио файла
+	jobID, err := uc.audioProcessingUseCase.ProcessAudio(ctx, telegramID, messageID, filePath, fileName, fileUniqueID, strings.TrimSpace(caption))
+	if err != nil {
+		uc.logger.Error("Failed to process audio file",
+			"error", err,
+		)
+		return "", 0, fmt.Errorf("failed to process audio file: %w", err)
+	}
+
+	// Формирование сообщения об успешном начале обработки
+	responseMessage := i18n.T(ctx, "accepted.audio") + i18n.T(ctx, "accepted.job_id", jobID)
+	if strings.TrimSpace(caption) != "" {
+		responseMessage += i18n.T(ctx, "accepted.caption")
+	}
+	responseMessage += agendaNote
+	responseMessage += "\n\n" + i18n.T(ctx, "accepted.audio_profile")
+
+	// Учет созданной задачи в статистике использования
+	uc.usageAnalyticsUseCase.Track(ctx, telegramID, entity.UsageEventJobCreated, usageSourceAudio)
+
+	// Логирование успешного начала обработки аудио файла
+	uc.logger.Info("Successfully started processing audio file",
+		"telegram_id", telegramID,
+		"user_id", user.ID,
+		"job_id", jobID,
+	)
+
+	return responseMessage, jobID, nil
+}
+
+// HandleVideoMessage обрабатывает видеосообщение (кружок) или видеофайл: звуковая дорожка проходит
+// ту же обработку, что и аудио. Возвращает ответ и ID созданной задачи
+func (uc *TelegramHandlersUseCase) HandleVideoMessage(ctx context.Context, telegramID int64, username string, messageID int, fileID string, fileUniqueID string, filePath string, fileName string, caption string) (string, int64, error) {
+	// Логирование начала обработки видео
+	uc.logger.Info("Handling video message",
+		"telegram_id", telegramID,
+		"file_id", fileID,
+	)
+
+	// Получение или создание пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		// Если пользователь не найден, создаем нового
+		user = &entity.User{
+			TelegramID: telegramID,
+			Username:   username,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+
+		err = uc.userRepo.Create(ctx, user)
+		if err != nil {
+			uc.logger.Error("Failed to create user",
+				"error", err,
+			)
+			return "", 0, fmt.Errorf("failed to create user: %w", err)
+		}
+	}
+
+	// Ожидающая повестка встречи привязывается к задаче при ее создании
+	agendaNote, err := uc.meetingAgendaNote(ctx, user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// Извлечение звуковой дорожки и обработка аудио
+	jobID, err := uc.audioProcessingUseCase.ProcessVideoFile(ctx, telegramID, messageID, filePath, fileName, fileUniqueID, strings.TrimSpace(caption))
+	if err != nil {
+		uc.logger.Error("Failed to process video file",
+			"error", err,
+		)
+		return "", 0, fmt.Errorf("failed to process video file: %w", err)
+	}
+
+	// Формирование сообщения об успешном начале обработки
+	responseMessage := i18n.T(ctx, "accepted.video") + i18n.T(ctx, "accepted.job_id", jobID)
+	if strings.TrimSpace(caption) != "" {
+		responseMessage += i18n.T(ctx, "accepted.caption")
+	}
+	responseMessage += agendaNote
+	responseMessage += "\n\n" + i18n.T(ctx, "accepted.audio_profile")
+
+	// Учет созданной задачи в статистике использования
+	uc.usageAnalyticsUseCase.Track(ctx, telegramID, entity.UsageEventJobCreated, usageSourceVideo)
+
+	// Логирование успешного начала обработки видео
+	uc.logger.Info("Successfully started processing video message",
+		"telegram_id", telegramID,
+		"user_id", user.ID,
+		"job_id", jobID,
+	)
+
+	return responseMessage, jobID, nil
+}
+
+// mediaLinkReplies - ответы на ссылку на видео или выпуск подкаста
+type mediaLinkReplies struct {
+	jobType        entity.JobType
+	usageSource    string
+	fetchFailedKey string
+	tooLongKey     string // Ключ перевода с наибольшей длительностью
+	downloadingKey string // Ключ перевода с названием
+	acceptedKey    string // Ключ перевода ответа о приеме в обработку
+	chaptersKey    string // Ключ перевода с числом глав
+	logKind        string
+}
+
+// videoLinkReplies - ответы на ссылку на видео
+var videoLinkReplies = mediaLinkReplies{
+	jobType:        entity.JobTypeVideo,
+	usageSource:    usageSourceLink,
+	fetchFailedKey: "media_link.video.fetch_failed",
+	tooLongKey:     "media_link.video.too_long",
+	downloadingKey: "media_link.video.downloading",
+	acceptedKey:    "accepted.video_link",
+	chaptersKey:    "accepted.chapters",
+	logKind:        "video",
+}
+
+// podcastLinkReplies - ответы на ссылку на выпуск подкаста
+var podcastLinkReplies = mediaLinkReplies{
+	jobType:        entity.JobTypePodcastLink,
+	usageSource:    usageSourcePodcast,
+	fetchFailedKey: "media_link.podcast.fetch_failed",
+	tooLongKey:     "media_link.podcast.too_long",
+	downloadingKey: "media_link.podcast.downloading",
+	acceptedKey:    "accepted.podcast_link",
+	chaptersKey:    "accepted.podcast_chapters",
+	logKind:        "podcast episode",
+}
+
+// HandleVideoLink обрабатывает ссылку на видео: загружает звуковую дорожку и создает задачу ее обработки
+func (uc *TelegramHandlersUseCase) HandleVideoLink(ctx context.Context, telegramID int64, username string, messageID int, videoURL string) (string, error) {
+	return uc.handleMediaLink(ctx, telegramID, username, messageID, videoURL, videoLinkReplies)
+}
+
+// HandlePodcastLink обрабатывает ссылку на выпуск подкаста: загружает выпуск и создает задачу его обработки
+func (uc *TelegramHandlersUseCase) HandlePodcastLink(ctx context.Context, telegramID int64, username string, messageID int, episodeURL string) (string, error) {
+	return uc.handleMediaLink(ctx, telegramID, username, messageID, episodeURL, podcastLinkReplies)
+}
+
+// handleMediaLink загружает через yt-dlp звуковую дорожку видео или выпуска подкаста по ссылке и создает задачу
+// ее обработки. Сообщение о загрузке становится сообщением о ходе обработки задачи
+func (uc *TelegramHandlersUseCase) handleMediaLink(ctx context.Context, telegramID int64, username string, messageID int, mediaURL string, replies mediaLinkReplies) (string, error) {
+	// Логирование начала обработки ссылки
+	uc.logger.Info("Handling media link",
+		"telegram_id", telegramID,
+		"url", mediaURL,
+		"kind", replies.logKind,
+	)
+
+	// Получение или создание пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		// Если пользователь не найден, создаем нового
+		user = &entity.User{
+			TelegramID: telegramID,
+			Username:   username,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+
+		err = uc.userRepo.Create(ctx, user)
+		if err != nil {
+			uc.logger.Error("Failed to create user",
+				"error", err,
+			)
+			return "", fmt.Errorf("failed to create user: %w", err)
+		}
+	}
+
+	// Получение описания видео или выпуска
+	video, ok, err := uc.videoUseCase.FetchInfo(ctx, mediaURL)
+	if err != nil {
+		uc.logger.Warn("Failed to fetch media info",
+			"error", err,
+			"url", mediaURL,
+		)
+		return i18n.T(ctx, replies.fetchFailedKey), nil
+	}
+	if !ok {
+		return i18n.T(ctx, replies.tooLongKey, textutil.FormatTimestamp(int(uc.videoUseCase.MaxDuration().Seconds()))), nil
+	}
+
+	// Загрузка звуковой дорожки занимает время, поэтому сразу сообщаем о ней
+	progressMessageID, err := uc.messenger.SendReply(telegramID, messageID, i18n.T(ctx, replies.downloadingKey, video.Title))
+	if err != nil {
+		uc.logger.Warn("Failed to send media download message",
+			"error", err,
+			"telegram_id", telegramID,
+		)
+	}
+
+	// Загрузка звуковой дорожки и создание задачи
+	jobID, err := uc.videoUseCase.Process(ctx, user, messageID, video, replies.jobType)
+	if err != nil {
+		uc.logger.Error("Failed to process media link",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to process %s: %w", replies.logKind, err)
+	}
+
+	// Этапы обработки обновляют сообщение о загрузке, а не присылают новые
+	if progressMessageID != 0 {
+		uc.AttachProgressMessage(ctx, jobID, progressMessageID)
+	}
+
+	// Формирование сообщения об успешном начале обработки
+	responseMessage := i18n.T(ctx, replies.acceptedKey)
+	if len(video.Chapters) > 0 {
+		responseMessage += i18n.T(ctx, replies.chaptersKey, len(video.Chapters))
+	}
+	responseMessage += i18n.T(ctx, "accepted.job_id", jobID)
+
+	// Учет созданной задачи в статистике использования
+	uc.usageAnalyticsUseCase.Track(ctx, telegramID, entity.UsageEventJobCreated, replies.usageSource)
+
+	// Логирование успешного начала обработки
+	uc.logger.Info("Successfully started processing media link",
+		"telegram_id", telegramID,
+		"user_id", user.ID,
+		"job_id", jobID,
+		"kind", replies.logKind,
+		"chapters", len(video.Chapters),
+	)
+
+	return responseMessage, nil
+}
+
+// HandlePhoto обрабатывает фотографию с подписью «к задаче <id>»: распознает на ней текст
+// и добавляет фотографию с текстом на страницу задачи в Notion
+func (uc *TelegramHandlersUseCase) HandlePhoto(ctx context.Context, telegramID int64, caption string, image []byte, contentType string) (string, error) {
+	// Логирование начала обработки фотографии
+	uc.logger.Info("Handling photo",
+		"telegram_id", telegramID,
+		"image_size", len(image),
+	)
+
+	jobID, ok := attachmentJobID(caption)
+	if !ok {
+		return i18n.T(ctx, "photo.usage"), nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Чужие задачи считаем несуществующими
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "photo.job_not_found", jobID), nil
+	}
+
+	attachment, notionStatus, err := uc.photoAttachmentUseCase.AttachPhoto(ctx, job, strings.TrimSpace(caption), image, contentType)
+	if err != nil {
+		return "", fmt.Errorf("failed to attach photo: %w", err)
+	}
+
+	messageBuilder := strings.Builder{}
+	switch notionStatus {
+	case attachmentNotionAdded:
+		messageBuilder.WriteString(i18n.T(ctx, "photo.added", jobID) + "\n\n")
+	case attachmentNotionNoPage:
+		messageBuilder.WriteString(i18n.T(ctx, "photo.no_page", jobID) + "\n\n")
+	default:
+		messageBuilder.WriteString(i18n.T(ctx, "photo.notion_failed", jobID) + "\n\n")
+	}
+
+	if attachment.OCRText == "" {
+		messageBuilder.WriteString(i18n.T(ctx, "photo.no_text"))
+	} else {
+		messageBuilder.WriteString(i18n.T(ctx, "photo.text") + "\n" + truncateRunes(attachment.OCRText, telegramMessageLimit/2, false))
+	}
+
+	// Логирование успешной обработки фотографии
+	uc.logger.Info("Successfully handled photo",
+		"telegram_id", telegramID,
+		"job_id", jobID,
+		"attachment_id", attachment.ID,
+	)
+
+	return messageBuilder.String(), nil
+}
+
+// HandleAudioProfile выбирает профиль предобработки аудио для задачи по нажатию кнопки.
+// data имеет вид <ID задачи>:<профиль>; профиль можно сменить, пока обработка задачи не началась
+func (uc *TelegramHandlersUseCase) HandleAudioProfile(ctx context.Context, telegramID int64, data string) (string, error) {
+	jobIDStr, profileStr, _ := strings.Cut(data, ":")
+	jobID, err := strconv.ParseInt(jobIDStr, 10, 64)
+	profile := entity.AudioProfile(profileStr)
+	if err != nil || !profile.IsValid() {
+		return i18n.T(ctx, "audio_profile.invalid"), nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Проверка, что задача принадлежит пользователю
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "callback.job_not_found"), nil
+	}
+
+	updated, err := uc.jobRepo.SetAudioProfile(ctx, jobID, profile)
+	if err != nil {
+		uc.logger.Error("Failed to set audio profile",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to set audio profile: %w", err)
+	}
+	if !updated {
+		return i18n.T(ctx, "audio_profile.too_late"), nil
+	}
+
+	// Логирование выбора профиля
+	uc.logger.Info("Audio profile selected",
+		"job_id", jobID,
+		"user_id", user.ID,
+		"audio_profile", profile,
+	)
+
+	return i18n.T(ctx, "audio_profile.selected", i18n.T(ctx, audioProfileNames[profile]), jobID), nil
+}
+
+// HandlePipeline выбирает набор настроек обработки для задачи по нажатию кнопки: набор задает профиль
+// предобработки и стиль краткого содержания. data имеет вид <ID задачи>:<набор>; набор можно выбрать,
+// пока обработка задачи не началась
+func (uc *TelegramHandlersUseCase) HandlePipeline(ctx context.Context, telegramID int64, data string) (string, error) {
+	jobIDStr, pipelineStr, _ := strings.Cut(data, ":")
+	jobID, err := strconv.ParseInt(jobIDStr, 10, 64)
+	pipeline := entity.Pipeline(pipelineStr)
+	definition, ok := entity.PipelineDefinitions[pipeline]
+	if err != nil || !ok {
+		return i18n.T(ctx, "pipeline.invalid"), nil
+	}
+
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Проверка, что задача принадлежит пользователю
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.UserID != user.ID {
+		return i18n.T(ctx, "callback.job_not_found"), nil
+	}
+
+	// Профиль меняется только до начала обработки, поэтому он выбирается первым: если обработка уже идет,
+	// стиль краткого содержания тоже не меняется, и набор не применяется наполовину
+	updated, err := uc.jobRepo.SetAudioProfile(ctx, jobID, definition.AudioProfile)
+	if err != nil {
+		uc.logger.Error("Failed to set audio profile",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to set audio profile: %w", err)
+	}
+	if !updated {
+		return i18n.T(ctx, "pipeline.too_late"), nil
+	}
+
+	if _, err := uc.jobRepo.SetSummaryStyle(ctx, jobID, definition.SummaryStyle); err != nil {
+		uc.logger.Error("Failed to set summary style",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to set summary style: %w", err)
+	}
+
+	// Логирование выбора набора
+	uc.logger.Info("Pipeline selected",
+		"job_id", jobID,
+		"user_id", user.ID,
+		"pipeline", pipeline,
+		"audio_profile", definition.AudioProfile,
+		"summary_style", definition.SummaryStyle,
+	)
+
+	return i18n.T(ctx, "pipeline.selected", i18n.T(ctx, pipelineNames[pipeline]), jobID), nil
+}
+
+// SendJobCompletionNotification отправляет уведомление о завершении задачи
+// в ответ на исходное сообщение пользователя
+func (uc *TelegramHandlersUseCase) SendJobCompletionNotification(ctx context.Context, jobIDStr string) error {
+	// Преобразование строки jobID в int64
+	jobID, err := strconv.ParseInt(jobIDStr, 10, 64)
+	if err != nil {
+		uc.logger.Error("Failed to parse job ID",
+			"error", err,
+		)
+		return fmt.Errorf("failed to parse job ID: %w", err)
+	}
+	// Логирование начала отправки уведомления о завершении задачи
+	uc.logger.Info("Sending job completion notification",
+		"job_id", jobID,
+	)
+
+	// Получение задачи из базы данных
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		uc.logger.Error("Failed to get job",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	// Получение пользователя из базы данных
+	user, err := uc.userRepo.GetByID(ctx, job.UserID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Уведомление приходит не в ответ на сообщение, поэтому язык берется из настроек пользователя
+	ctx = i18n.WithLanguage(ctx, uc.interfaceLanguageUseCase.ForUser(ctx, user))
+
+	// Формирование сообщения о завершении задачи
+	messageBuilder := strings.Builder{}
+	isPodcast := job.Type == entity.JobTypePodcastEpisode
+	if isPodcast {
+		// Для выпусков подкастов отправляем дайджест: название и краткое содержание
+		messageBuilder.WriteString(i18n.T(ctx, "completion.podcast", job.FileName))
+	} else if job.Type == entity.JobTypeVideo {
+		messageBuilder.WriteString(i18n.T(ctx, "completion.video", job.FileName))
+	} else if job.Type == entity.JobTypePodcastLink {
+		messageBuilder.WriteString(i18n.T(ctx, "completion.podcast_link", job.FileName))
+	} else {
+		messageBuilder.WriteString(i18n.T(ctx, "completion.done"))
+	}
+
+	// Общее настроение встречи, если для задачи составлен его разбор; подробности - на странице в Notion
+	sentiment, err := uc.jobSentimentRepo.GetByJobID(ctx, job.ID)
+	if err != nil {
+		uc.logger.Warn("Failed to get job sentiment",
+			"error", err,
+			"job_id", job.ID,
+		)
+	} else if sentiment != nil {
+		messageBuilder.WriteString(formatSentimentLine(ctx, sentiment))
+	}
+
+	transcription := job.Transcription
+	if isPodcast {
+		transcription = ""
+	}
+	summary := job.Summary
+
+	// Добавление информации о сохранении в Notion
+	footerBuilder := strings.Builder{}
+	if job.NotionPageID != "" {
+		footerBuilder.WriteString(i18n.T(ctx, "completion.notion", notionURL(job.NotionPageID)))
+		if user.NotionHubPageID != "" {
+			footerBuilder.WriteString(i18n.T(ctx, "completion.notion_hub", notionURL(user.NotionHubPageID)))
+		}
+	}
+	// Предупреждение о повторно присланной записи; объединить задачи можно кнопкой под сообщением
+	if job.DuplicateOfJobID != 0 {
+		footerBuilder.WriteString(i18n.T(ctx, "completion.duplicate", job.DuplicateOfJobID, similarityPercent(job.DuplicateSimilarity)))
+	}
+	footerBuilder.WriteString(i18n.T(ctx, "completion.full_result", job.ID))
+	footer := footerBuilder.String()
+
+	// Если результат не помещается в одно сообщение, в сообщении остается начало текста,
+	// а полный текст прикладывается файлом: сначала транскрипция, затем, если нужно, краткое содержание
+	var attachments []completionAttachment
+	if completionMessageLength(ctx, messageBuilder.String(), transcription, summary, footer) > telegramMessageLimit && transcription != "" {
+		attachments = append(attachments, completionAttachment{
+			fileName: fmt.Sprintf("transcript_%d.txt", job.ID),
+			data:     []byte(job.Transcription),
+			caption:  i18n.T(ctx, "completion.transcript_file", job.ID),
+		})
+		transcription = truncateRunes(transcription, completionPreviewLength, false) + i18n.T(ctx, "completion.truncated")
+	}
+	if completionMessageLength(ctx, messageBuilder.String(), transcription, summary, footer) > telegramMessageLimit && summary != "" {
+		attachments = append(attachments, completionAttachment{
+			fileName: fmt.Sprintf("summary_%d.md", job.ID),
+			data:     []byte(job.Summary),
+			caption:  i18n.T(ctx, "completion.summary_file", job.ID),
+		})
+		summary = truncateRunes(summary, completionPreviewLength, false) + i18n.T(ctx, "completion.truncated")
+	}
+
+	// Добавление информации о транскрипции
+	if transcription != "" {
+		messageBuilder.WriteString(i18n.T(ctx, "completion.transcription"))
+		messageBuilder.WriteString(transcription)
+		messageBuilder.WriteString("\n\n")
+	}
+
+	// Добавление информации о суммаризации
+	if summary != "" {
+		messageBuilder.WriteString(i18n.T(ctx, "completion.summary"))
+		messageBuilder.WriteString(summary)
+		messageBuilder.WriteString("\n\n")
+	}
+
+	messageBuilder.WriteString(footer)
+
+	// Сообщение о ходе обработки отмечает задачу готовой; результат приходит отдельным сообщением
+	if job.ProgressMessageID != 0 {
+		uc.SendProgressUpdate(ctx, job.ID, entity.JobStatusCompleted)
+	}
+
+	// Сообщение с результатами отправляется с учетом настройки автоудаления
+	err = uc.messageRetentionUseCase.SendResult(ctx, user.TelegramID, job.SourceMessageID, messageBuilder.String(), jobResultButtons(ctx, job))
+	if err != nil {
+		uc.logger.Error("Failed to send job completion notification",
+			"error", err,
+		)
+		return fmt.Errorf("failed to send job completion notification: %w", err)
+	}
+
+	// Файлы с полным текстом отправляются после сообщения и удаляются вместе с ним. Сообщение уже доставлено,
+	// поэтому ошибка файла не повторяет уведомление: полный результат остается доступен по /get
+	for _, attachment := range attachments {
+		err = uc.messageRetentionUseCase.SendResultDocument(ctx, user.TelegramID, job.SourceMessageID, attachment.fileName, attachment.data, attachment.caption)
+		if err != nil {
+			uc.logger.Warn("Failed to send job result file",
+				"error", err,
+				"job_id", job.ID,
+				"file_name", attachment.fileName,
+			)
+		}
+	}
+
+	// Учет завершенной задачи в статистике использования
+	uc.usageAnalyticsUseCase.Track(ctx, user.TelegramID, entity.UsageEventJobCompleted, string(job.Type))
+
+	// Логирование успешной отправки уведомления о завершении задачи
+	uc.logger.Info("Successfully sent job completion notification",
+		"job_id", jobID,
+		"user_id", job.UserID,
+		"telegram_id", user.TelegramID,
+	)
+
+	return nil
+}
+
+// completionPreviewLength - сколько символов текста остается в уведомлении о завершении задачи,
+// если полный текст приложен файлом
+const completionPreviewLength = 500
+
+// completionAttachment представляет собой файл с полным текстом результата, приложенный к уведомлению о завершении задачи
+type completionAttachment struct {
+	fileName string
+	data     []byte
+	caption  string
+}
+
+// completionMessageLength возвращает длину уведомления о завершении задачи с указанными транскрипцией и кратким содержанием
+func completionMessageLength(ctx context.Context, header, transcription, summary, footer string) int {
+	length := utf8.RuneCountInString(header) + utf8.RuneCountInString(footer)
+	if transcription != "" {
+		length += utf8.RuneCountInString(i18n.T(ctx, "completion.transcription")+"\n\n") + utf8.RuneCountInString(transcription)
+	}
+	if summary != "" {
+		length += utf8.RuneCountInString(i18n.T(ctx, "completion.summary")+"\n\n") + utf8.RuneCountInString(summary)
+	}
+	return length
+}
+
+// jobProgressSteps - шаги сообщения о ходе обработки задачи, статусы, с которых начинается каждый шаг,
+// и идентификаторы названий шагов в каталоге i18n
+var jobProgressSteps = []struct {
+	status entity.JobStatus
+	title  string
+}{
+	{entity.JobStatusQueued, "progress.step.queued"},
+	{entity.JobStatusProcessing, "progress.step.processing"},
+	{entity.JobStatusTranscribed, "progress.step.transcribed"},
+	{entity.JobStatusSummarized, "progress.step.summarized"},
+	{entity.JobStatusCompleted, "progress.step.completed"},
+}
+
+// jobProgressBarWidth - число делений полосы хода обработки
+const jobProgressBarWidth = 10
+
+// jobProgressStep возвращает номер текущего шага задачи; задача с ошибкой остается на шаге, где она упала
+func jobProgressStep(job *entity.Job, status entity.JobStatus) int {
+	if status == entity.JobStatusFailed {
+		switch job.FailedStage {
+		case entity.JobStageSummarization:
+			return 2
+		case entity.JobStageNotionSync:
+			return 3
+		default:
+			return 1
+		}
+	}
+
+	for i, step := range jobProgressSteps {
+		if step.status == status {
+			return i
+		}
+	}
+	return 0
+}
+
+// formatJobProgress формирует текст сообщения о ходе обработки задачи: заголовок, полосу и список шагов
+func formatJobProgress(ctx context.Context, job *entity.Job, status entity.JobStatus) string {
+	current := jobProgressStep(job, status)
+	last := len(jobProgressSteps) - 1
+	filled := current * jobProgressBarWidth / last
+
+	var builder strings.Builder
+	switch status {
+	case entity.JobStatusCompleted:
+		builder.WriteString(i18n.T(ctx, "progress.completed", job.ID) + "\n")
+	case entity.JobStatusFailed:
+		builder.WriteString(i18n.T(ctx, "progress.failed", job.ID, i18n.T(ctx, jobProgressSteps[current].title)) + "\n")
+	default:
+		builder.WriteString(i18n.T(ctx, "progress.running", job.ID, strings.ToLower(i18n.T(ctx, jobProgressSteps[current].title))) + "\n")
+	}
+	builder.WriteString(fmt.Sprintf("%s%s %d%%\n\n",
+		strings.Repeat("▓", filled), strings.Repeat("░", jobProgressBarWidth-filled), current*100/last))
+
+	for i, step := range jobProgressSteps[:last] {
+		icon := "▫️"
+		switch {
+		case i < current || status == entity.JobStatusCompleted:
+			icon = "✅"
+		case i == current && status == entity.JobStatusFailed:
+			icon = "❌"
+		case i == current:
+			icon = "⏳"
+		}
+		builder.WriteString(fmt.Sprintf("%s %s\n", icon, i18n.T(ctx, step.title)))
+	}
+
+	if status == entity.JobStatusFailed && job.Type != entity.JobTypeReadingLesson {
+		builder.WriteString("\n" + i18n.T(ctx, "progress.retry", job.ID))
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// AttachProgressMessage делает ответ на присланную запись сообщением о ходе обработки задачи: дальше бот
+// обновляет его на каждом этапе, а не присылает новые. Если этапы уже начали свое сообщение, ответ не меняется
+func (uc *TelegramHandlersUseCase) AttachProgressMessage(ctx context.Context, jobID int64, messageID int) {
+	if _, err := uc.jobRepo.SetProgressMessage(ctx, jobID, 0, messageID); err != nil {
+		uc.logger.Warn("Failed to attach progress message",
+			"error", err,
+			"job_id", jobID,
+		)
+	}
+}
+
+// SendProgressUpdate обновляет сообщение о ходе обработки задачи; если сообщения еще нет или пользователь
+// его удалил, отправляет новое в ответ на исходное сообщение и запоминает его
+func (uc *TelegramHandlersUseCase) SendProgressUpdate(ctx context.Context, jobID int64, status entity.JobStatus) error {
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		uc.logger.Error("Failed to get job",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	user, err := uc.userRepo.GetByID(ctx, job.UserID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Сообщение обновляется из очереди, поэтому язык берется из настроек пользователя
+	ctx = i18n.WithLanguage(ctx, uc.interfaceLanguageUseCase.ForUser(ctx, user))
+	text := formatJobProgress(ctx, job, status)
+
+	if job.ProgressMessageID != 0 {
+		err := uc.messenger.EditMessage(user.TelegramID, job.ProgressMessageID, text)
+		if err == nil {
+			uc.logger.Debug("Updated progress message",
+				"job_id", jobID,
+				"status", status,
+			)
+			return nil
+		}
+		// Сообщение могло быть удалено пользователем, поэтому отправляем новое
+		uc.logger.Warn("Failed to edit progress message",
+			"error", err,
+			"job_id", jobID,
+		)
+	}
+
+	messageID, err := uc.messenger.SendReply(user.TelegramID, job.SourceMessageID, text)
+	if err != nil {
+		uc.logger.Error("Failed to send progress update",
+			"error", err,
+		)
+		return fmt.Errorf("failed to send progress update: %w", err)
+	}
+
+	if _, err := uc.jobRepo.SetProgressMessage(ctx, jobID, job.ProgressMessageID, messageID); err != nil {
+		uc.logger.Warn("Failed to save progress message",
+			"error", err,
+			"job_id", jobID,
+		)
+	}
+
+	uc.logger.Info("Sent progress update",
+		"job_id", jobID,
+		"status", status,
+	)
+	return nil
 }
 
 // SendMessage sends a message to the specified Telegram user
 func (uc *TelegramHandlersUseCase) SendMessage(to int64, text string) error {
-	return uc.bot.SendMessage(to, text)
+	_, err := uc.messenger.SendMessage(to, text)
+	return err
 }
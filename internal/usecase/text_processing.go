@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// TextProcessingUseCase представляет собой сценарий обработки присланного текста
+type TextProcessingUseCase struct {
+	userRepo     repository.UserRepository
+	jobRepo      repository.JobRepository
+	queueService service.QueueService
+	logger       *logger.Logger
+}
+
+// NewTextProcessingUseCase создает новый сценарий обработки присланного текста
+func NewTextProcessingUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	queueService service.QueueService,
+	logger *logger.Logger,
+) *TextProcessingUseCase {
+	return &TextProcessingUseCase{
+		userRepo:     userRepo,
+		jobRepo:      jobRepo,
+		queueService: queueService,
+		logger:       logger,
+	}
+}
+
+// ProcessText создает текстовую задачу и отправляет текст сразу на суммаризацию;
+// sourceMessageID - ID исходного сообщения в Telegram
+func (uc *TextProcessingUseCase) ProcessText(ctx context.Context, telegramID int64, username string, text string, sourceMessageID int) (int64, error) {
+	// Логирование начала обработки текста
+	uc.logger.Info("Processing text",
+		"telegram_id", telegramID,
+		"text_length", len(text),
+	)
+
+	// Получение или создание пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		user = &entity.User{
+			TelegramID: telegramID,
+			Username:   username,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+
+		err = uc.userRepo.Create(ctx, user)
+		if err != nil {
+			uc.logger.Error("Failed to create user",
+				"error", err,
+			)
+			return 0, fmt.Errorf("failed to create user: %w", err)
+		}
+	}
+
+	// Создание задачи: присланный текст сохраняется вместо транскрипции
+	job := entity.Job{
+		UserID:          user.ID,
+		Type:            entity.JobTypeText,
+		Status:          entity.JobStatusCreated,
+		Transcription:   text,
+		SourceMessageID: sourceMessageID,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	err = uc.jobRepo.Create(ctx, &job)
+	if err != nil {
+		uc.logger.Error("Failed to create job",
+			"error", err,
+		)
+		return 0, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	// Создание задачи для суммаризации: текст уже сохранен в задаче как транскрипция
+	summarizationJob := entity.QueueJob{
+		JobID:   job.ID,
+		UserID:  user.ID,
+		JobType: entity.JobTypeSummarization,
+	}
+
+	// Добавление задачи в очередь
+	err = uc.queueService.PushJob(ctx, summarizationJob)
+	if err != nil {
+		uc.logger.Error("Failed to push summarization job to queue",
+			"error", err,
+		)
+		return 0, fmt.Errorf("failed to push summarization job to queue: %w", err)
+	}
+
+	// Логирование успешной обработки текста
+	uc.logger.Info("Text processed successfully",
+		"job_id", job.ID,
+	)
+
+	return job.ID, nil
+}
@@ -0,0 +1,342 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+	"github.com/112Alex/project_obsidian/pkg/textutil"
+)
+
+// maxFindResults - максимальное количество фрагментов в ответе на /find
+const maxFindResults = 10
+
+// timestampedLinePattern находит строку транскрипции с меткой времени вида [mm:ss] или [hh:mm:ss]
+var timestampedLinePattern = regexp.MustCompile(`^\[(?:(\d+):)?(\d+):(\d{2})\]\s*(.*)$`)
+
+// rangeSummaryInstructions - указания к суммаризации фрагмента записи; параметры - начало и конец фрагмента
+const rangeSummaryInstructions = "Это фрагмент длинной записи с %s до %s. Изложи только то, что было сказано в этом фрагменте, " +
+	"не додумывая остальную запись."
+
+// sentencePattern выделяет предложения в строке транскрипции без меток времени
+var sentencePattern = regexp.MustCompile(`[^.!?…]+[.!?…]*`)
+
+// TranscriptSearchUseCase представляет собой сценарий поиска фраз внутри транскрипции задачи
+// и суммаризации ее отдельных промежутков времени
+type TranscriptSearchUseCase struct {
+	segmentRepo          repository.TranscriptSegmentRepository
+	summarizationService service.SummarizationService
+	logger               *logger.Logger
+}
+
+// NewTranscriptSearchUseCase создает новый сценарий поиска внутри транскрипции
+func NewTranscriptSearchUseCase(
+	segmentRepo repository.TranscriptSegmentRepository,
+	summarizationService service.SummarizationService,
+	logger *logger.Logger,
+) *TranscriptSearchUseCase {
+	return &TranscriptSearchUseCase{
+		segmentRepo:          segmentRepo,
+		summarizationService: summarizationService,
+		logger:               logger,
+	}
+}
+
+// IndexTranscript разбивает транскрипцию задачи на фрагменты и сохраняет их для поиска
+func (uc *TranscriptSearchUseCase) IndexTranscript(ctx context.Context, jobID int64, transcription string) error {
+	segments := splitTranscriptSegments(transcription)
+
+	err := uc.segmentRepo.ReplaceForJob(ctx, jobID, segments)
+	if err != nil {
+		return fmt.Errorf("failed to save transcript segments: %w", err)
+	}
+
+	uc.logger.Debug("Transcript indexed",
+		"job_id", jobID,
+		"segments", len(segments),
+	)
+
+	return nil
+}
+
+// Find ищет фразу в транскрипции задачи. Задачи, обработанные до появления поиска,
+// индексируются при первом обращении
+func (uc *TranscriptSearchUseCase) Find(ctx context.Context, job *entity.Job, phrase string) ([]*entity.TranscriptExcerpt, error) {
+	// Логирование начала поиска
+	uc.logger.Info("Searching transcript",
+		"job_id", job.ID,
+		"phrase_length", len(phrase),
+	)
+
+	if err := uc.ensureIndexed(ctx, job); err != nil {
+		return nil, err
+	}
+
+	excerpts, err := uc.segmentRepo.Search(ctx, job.ID, phrase, maxFindResults)
+	if err != nil {
+		uc.logger.Error("Failed to search transcript segments",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to search transcript segments: %w", err)
+	}
+
+	// Логирование успешного поиска
+	uc.logger.Info("Transcript searched successfully",
+		"job_id", job.ID,
+		"matches", len(excerpts),
+	)
+
+	return excerpts, nil
+}
+
+// SummarizeRange суммаризирует промежуток записи [fromSeconds, toSeconds) по фрагментам транскрипции
+// с метками времени. Возвращает также начало последнего фрагмента записи: nil означает, что в транскрипции
+// нет меток времени, а пустое краткое содержание - что в промежутке ничего не сказано.
+// summaryLanguage - выбранный пользователем язык краткого содержания (пустая строка - язык транскрипции)
+func (uc *TranscriptSearchUseCase) SummarizeRange(ctx context.Context, job *entity.Job, summaryLanguage string, fromSeconds, toSeconds int) (string, *int, error) {
+	// Логирование начала суммаризации промежутка
+	uc.logger.Info("Summarizing transcript range",
+		"job_id", job.ID,
+		"from_seconds", fromSeconds,
+		"to_seconds", toSeconds,
+	)
+
+	if err := uc.ensureIndexed(ctx, job); err != nil {
+		return "", nil, err
+	}
+
+	lastStart, err := uc.segmentRepo.LastStartSeconds(ctx, job.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get last transcript segment start",
+			"error", err,
+		)
+		return "", nil, fmt.Errorf("failed to get last transcript segment start: %w", err)
+	}
+	if lastStart == nil {
+		return "", nil, nil
+	}
+
+	segments, err := uc.segmentRepo.GetRange(ctx, job.ID, fromSeconds, toSeconds)
+	if err != nil {
+		uc.logger.Error("Failed to get transcript segments",
+			"error", err,
+		)
+		return "", lastStart, fmt.Errorf("failed to get transcript segments: %w", err)
+	}
+	if len(segments) == 0 {
+		return "", lastStart, nil
+	}
+
+	instructions := joinInstructions(
+		summaryLanguageInstructions(summaryLanguage, job.Transcription),
+		fmt.Sprintf(rangeSummaryInstructions, textutil.FormatTimestamp(fromSeconds), textutil.FormatTimestamp(toSeconds)),
+	)
+	result, err := uc.summarizationService.SummarizeJob(ctx, joinTimestampedSegments(segments), instructions)
+	if err != nil {
+		uc.logger.Error("Failed to summarize transcript range",
+			"error", err,
+		)
+		return "", lastStart, fmt.Errorf("failed to summarize transcript range: %w", err)
+	}
+
+	// Логирование успешной суммаризации промежутка
+	uc.logger.Info("Transcript range summarized successfully",
+		"job_id", job.ID,
+		"segments", len(segments),
+		"model", result.Model,
+		"cost_usd", result.CostUSD,
+	)
+
+	return result.Summary, lastStart, nil
+}
+
+// SummarizeChapters составляет краткое содержание записи по главам: каждая глава суммаризируется
+// по своим фрагментам транскрипции и становится разделом с заголовком "### Название (mm:ss)".
+// Возвращает nil, если в транскрипции нет меток времени и разделить ее на главы нельзя.
+// Главы, в которых ничего не сказано, пропускаются; стоимость и токены суммируются по всем главам
+func (uc *TranscriptSearchUseCase) SummarizeChapters(
+	ctx context.Context,
+	job *entity.Job,
+	summaryLanguage string,
+	instructions string,
+	chapters []*entity.VideoChapter,
+) (*entity.SummaryResult, error) {
+	// Логирование начала суммаризации по главам
+	uc.logger.Info("Summarizing transcript by chapters",
+		"job_id", job.ID,
+		"chapters", len(chapters),
+	)
+
+	if err := uc.ensureIndexed(ctx, job); err != nil {
+		return nil, err
+	}
+
+	lastStart, err := uc.segmentRepo.LastStartSeconds(ctx, job.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get last transcript segment start",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get last transcript segment start: %w", err)
+	}
+	if lastStart == nil {
+		return nil, nil
+	}
+
+	languageInstructions := summaryLanguageInstructions(summaryLanguage, job.Transcription)
+	result := &entity.SummaryResult{}
+	sections := make([]string, 0, len(chapters))
+	for _, chapter := range chapters {
+		segments, err := uc.segmentRepo.GetRange(ctx, job.ID, chapter.StartSeconds, chapter.EndSeconds)
+		if err != nil {
+			uc.logger.Error("Failed to get transcript segments",
+				"error", err,
+			)
+			return nil, fmt.Errorf("failed to get transcript segments: %w", err)
+		}
+		if len(segments) == 0 {
+			continue
+		}
+
+		chapterInstructions := joinInstructions(
+			languageInstructions,
+			fmt.Sprintf(rangeSummaryInstructions, textutil.FormatTimestamp(chapter.StartSeconds), textutil.FormatTimestamp(chapter.EndSeconds)),
+			instructions,
+		)
+		chapterResult, err := uc.summarizationService.SummarizeJob(ctx, joinTimestampedSegments(segments), chapterInstructions)
+		if err != nil {
+			uc.logger.Error("Failed to summarize chapter",
+				"error", err,
+				"chapter", chapter.Position,
+			)
+			return nil, fmt.Errorf("failed to summarize chapter %q: %w", chapter.Title, err)
+		}
+
+		title := chapter.Title
+		if title == "" {
+			title = fmt.Sprintf("Глава %d", chapter.Position+1)
+		}
+		sections = append(sections, fmt.Sprintf("### %s (%s)\n\n%s", title, textutil.FormatTimestamp(chapter.StartSeconds), strings.TrimSpace(chapterResult.Summary)))
+
+		result.Model = chapterResult.Model
+		result.CostUSD += chapterResult.CostUSD
+		result.PromptTokens += chapterResult.PromptTokens
+		result.CompletionTokens += chapterResult.CompletionTokens
+	}
+	if len(sections) == 0 {
+		return nil, nil
+	}
+	result.Summary = strings.Join(sections, "\n\n")
+
+	// Логирование успешной суммаризации по главам
+	uc.logger.Info("Transcript summarized by chapters successfully",
+		"job_id", job.ID,
+		"sections", len(sections),
+		"model", result.Model,
+		"cost_usd", result.CostUSD,
+	)
+
+	return result, nil
+}
+
+// joinTimestampedSegments собирает фрагменты транскрипции в текст для суммаризации.
+// Метки времени сохраняются в тексте, чтобы краткое содержание могло на них ссылаться
+func joinTimestampedSegments(segments []*entity.TranscriptSegment) string {
+	lines := make([]string, len(segments))
+	for i, segment := range segments {
+		lines[i] = fmt.Sprintf("[%s] %s", textutil.FormatTimestamp(*segment.StartSeconds), segment.Text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ensureIndexed индексирует транскрипцию задачи, обработанной до появления поиска
+func (uc *TranscriptSearchUseCase) ensureIndexed(ctx context.Context, job *entity.Job) error {
+	count, err := uc.segmentRepo.CountByJobID(ctx, job.ID)
+	if err != nil {
+		uc.logger.Error("Failed to count transcript segments",
+			"error", err,
+		)
+		return fmt.Errorf("failed to count transcript segments: %w", err)
+	}
+
+	if count == 0 && job.Transcription != "" {
+		if err := uc.IndexTranscript(ctx, job.ID, job.Transcription); err != nil {
+			uc.logger.Error("Failed to index transcript",
+				"error", err,
+			)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseTimestamp разбирает момент записи: h:mm:ss, mm:ss или целое число минут
+func parseTimestamp(value string) (int, bool) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return 0, false
+		}
+		// Минуты и секунды после двоеточия не превышают 59
+		if i > 0 && (len(part) != 2 || number > 59) {
+			return 0, false
+		}
+		numbers[i] = number
+	}
+
+	switch len(numbers) {
+	case 1:
+		return numbers[0] * 60, true
+	case 2:
+		return numbers[0]*60 + numbers[1], true
+	default:
+		return numbers[0]*3600 + numbers[1]*60 + numbers[2], true
+	}
+}
+
+// splitTranscriptSegments разбивает транскрипцию на фрагменты: строки с метками времени
+// становятся отдельными сегментами, остальной текст делится на предложения
+func splitTranscriptSegments(transcription string) []*entity.TranscriptSegment {
+	segments := make([]*entity.TranscriptSegment, 0)
+	add := func(text string, startSeconds *int) {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return
+		}
+		segments = append(segments, &entity.TranscriptSegment{
+			Position:     len(segments),
+			StartSeconds: startSeconds,
+			Text:         text,
+		})
+	}
+
+	for _, line := range strings.Split(transcription, "\n") {
+		line = strings.TrimSpace(line)
+		if match := timestampedLinePattern.FindStringSubmatch(line); match != nil {
+			hours, _ := strconv.Atoi(match[1])
+			minutes, _ := strconv.Atoi(match[2])
+			seconds, _ := strconv.Atoi(match[3])
+			start := hours*3600 + minutes*60 + seconds
+			add(match[4], &start)
+			continue
+		}
+
+		for _, sentence := range sentencePattern.FindAllString(line, -1) {
+			add(sentence, nil)
+		}
+	}
+
+	return segments
+}
@@ -3,7 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"path/filepath"
+	"time"
 
 	"github.com/112Alex/project_obsidian/internal/domain/entity"
 	"github.com/112Alex/project_obsidian/internal/domain/repository"
@@ -14,43 +14,116 @@ import (
 // TranscriptionProcessingUseCase представляет собой сценарий обработки транскрибации
 type TranscriptionProcessingUseCase struct {
 	jobRepo              repository.JobRepository
+	userRepo             repository.UserRepository
 	queueService         service.QueueService
 	audioService         service.AudioService
 	transcriptionService service.TranscriptionService
+	diarizationService   service.DiarizationService
+	userSettings         *UserSettingsUseCase
 	telegramHandlers     *TelegramHandlersUseCase
+	transcriptSearch     *TranscriptSearchUseCase
+	languageUseCase      *InterfaceLanguageUseCase
+	timeoutBase          time.Duration
+	timeoutFactor        float64
+	artifactRetention    time.Duration
+	duplicateThreshold   float64
+	duplicateWindow      time.Duration
+	diarize              bool
+	chunkBytes           int64
+	chunkOverlap         time.Duration
+	chunkConcurrency     int
 	logger               *logger.Logger
 }
 
-// NewTranscriptionProcessingUseCase создает новый сценарий обработки транскрибации
+// NewTranscriptionProcessingUseCase создает новый сценарий обработки транскрибации.
+// timeoutBase и timeoutFactor задают ограничение времени этапа: timeoutBase плюс timeoutFactor длительностей записи.
+// artifactRetention - сколько хранится подготовленный WAV задачи после последнего использования.
+// duplicateThreshold и duplicateWindow задают поиск повторно присланных записей: задача, транскрипция которой
+// совпадает с транскрипцией другой задачи пользователя за duplicateWindow не меньше чем на duplicateThreshold,
+// помечается как повтор (0 отключает поиск). diarize включает разделение транскрипций по говорящим;
+// diarizationService определяет говорящих, если провайдер распознавания их не различает, и может быть nil.
+// Запись больше chunkBytes распознается по фрагментам, перекрывающимся на chunkOverlap, не больше
+// chunkConcurrency фрагментов одновременно (0 - провайдер принимает запись целиком)
 func NewTranscriptionProcessingUseCase(
 	jobRepo repository.JobRepository,
+	userRepo repository.UserRepository,
 	queueService service.QueueService,
 	audioService service.AudioService,
 	transcriptionService service.TranscriptionService,
+	diarizationService service.DiarizationService,
+	userSettings *UserSettingsUseCase,
 	telegramHandlers *TelegramHandlersUseCase,
+	transcriptSearch *TranscriptSearchUseCase,
+	languageUseCase *InterfaceLanguageUseCase,
+	timeoutBase time.Duration,
+	timeoutFactor float64,
+	artifactRetention time.Duration,
+	duplicateThreshold float64,
+	duplicateWindow time.Duration,
+	diarize bool,
+	chunkBytes int64,
+	chunkOverlap time.Duration,
+	chunkConcurrency int,
 	logger *logger.Logger,
 ) *TranscriptionProcessingUseCase {
 	return &TranscriptionProcessingUseCase{
 		jobRepo:              jobRepo,
+		userRepo:             userRepo,
 		queueService:         queueService,
 		audioService:         audioService,
 		transcriptionService: transcriptionService,
+		diarizationService:   diarizationService,
+		userSettings:         userSettings,
 		telegramHandlers:     telegramHandlers,
+		transcriptSearch:     transcriptSearch,
+		languageUseCase:      languageUseCase,
+		timeoutBase:          timeoutBase,
+		timeoutFactor:        timeoutFactor,
+		artifactRetention:    artifactRetention,
+		duplicateThreshold:   duplicateThreshold,
+		duplicateWindow:      duplicateWindow,
+		diarize:              diarize,
+		chunkBytes:           chunkBytes,
+		chunkOverlap:         chunkOverlap,
+		chunkConcurrency:     max(chunkConcurrency, 1),
 		logger:               logger,
 	}
 }
 
+// transcriptionPayload возвращает данные задачи транскрибации в том виде, в котором их читает ProcessTranscription:
+// задача, запущенная в обход очереди, не проходит через JSON и должна сразу содержать map
+func transcriptionPayload(audioPath string) map[string]interface{} {
+	return map[string]interface{}{
+		"audio_path": audioPath,
+	}
+}
+
+// transcriptionAudioPath извлекает путь к записи из данных задачи транскрибации. Задачи, поставленные
+// в очередь до перехода на объект с полем audio_path, содержат путь строкой и читаются как раньше
+func transcriptionAudioPath(payload any) (string, error) {
+	switch p := payload.(type) {
+	case string:
+		if p == "" {
+			return "", fmt.Errorf("audio_path not found in job payload or has invalid type")
+		}
+		return p, nil
+	case map[string]interface{}:
+		audioPath, ok := p["audio_path"].(string)
+		if !ok {
+			return "", fmt.Errorf("audio_path not found in job payload or has invalid type")
+		}
+		return audioPath, nil
+	default:
+		return "", fmt.Errorf("invalid payload type in job")
+	}
+}
+
 // ProcessTranscription обрабатывает транскрибацию аудио файла
 func (uc *TranscriptionProcessingUseCase) ProcessTranscription(ctx context.Context, job entity.QueueJob) error {
 	// Получение данных из задачи
-	payload, ok := job.Payload.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("invalid payload type in job")
-	}
-
-	audioPath, ok := payload["audio_path"].(string)
-	if !ok {
-		return fmt.Errorf("audio_path not found in job payload or has invalid type")
+	audioPath, err := transcriptionAudioPath(job.Payload)
+	if err != nil {
+		return err
 	}
 
 	// Логирование начала обработки транскрибации
@@ -59,24 +132,41 @@ func (uc *TranscriptionProcessingUseCase) ProcessTranscription(ctx context.Conte
 		"audio_path", audioPath,
 	)
 
-	// Обработка аудио файла для транскрибации
-	processedAudioPath, err := uc.audioService.ProcessAudio(ctx, audioPath, filepath.Base(audioPath))
+	// Получение задачи: от нее зависят профиль предобработки, ограничение времени и детализация распознавания
+	storedJob := uc.getStoredJob(ctx, job.JobID)
+
+	// Предобработка и распознавание ограничены по времени пропорционально длительности записи
+	timeout := transcriptionTimeout(uc.timeoutBase, uc.timeoutFactor, uc.audioDuration(ctx, storedJob, audioPath))
+	stageCtx, cancel := withStageTimeout(ctx, timeout)
+	defer cancel()
+
+	// Обработка аудио файла для транскрибации с выбранным профилем или подготовленный ранее WAV
+	processedAudioPath, profile, release, err := uc.preprocessAudio(stageCtx, job.JobID, storedJob, audioPath)
 	if err != nil {
+		err = stageTimeoutError(stageCtx, timeout, err)
 		uc.logger.Error("Failed to process audio for transcription",
 			"error", err,
 		)
 		return fmt.Errorf("failed to process audio for transcription: %w", err)
 	}
+	defer release()
+	uc.logger.Info("Audio preprocessed",
+		"job_id", job.JobID,
+		"audio_profile", profile,
+	)
 
 	// Отправка обновления прогресса после обработки аудио
-	telegramID, message, err := uc.telegramHandlers.SendProgressUpdate(ctx, job.JobID, entity.JobStatusProcessing)
-	if err == nil {
-		uc.telegramHandlers.SendMessage(telegramID, message)
-	}
+	uc.telegramHandlers.SendProgressUpdate(ctx, job.JobID, entity.JobStatusProcessing)
 
-	// Транскрибация аудио файла
-	transcription, err := uc.transcriptionService.Transcribe(ctx, processedAudioPath)
+	// Транскрибация аудио файла с пользовательскими параметрами декодирования.
+	// Видео и выпуски подкастов по ссылке распознаются с метками времени: по ним транскрипция делится на главы
+	opts := uc.userTranscriptionOptions(ctx, job.UserID)
+	if storedJob != nil && storedJob.Type.IsMediaLink() {
+		opts.Granularity = entity.TranscriptionGranularitySegment
+	}
+	transcription, err := uc.transcribe(stageCtx, job.JobID, job.UserID, processedAudioPath, opts)
 	if err != nil {
+		err = stageTimeoutError(stageCtx, timeout, err)
 		uc.logger.Error("Failed to transcribe audio",
 			"error", err,
 		)
@@ -84,10 +174,7 @@ func (uc *TranscriptionProcessingUseCase) ProcessTranscription(ctx context.Conte
 	}
 
 	// Отправка обновления прогресса после транскрипции
-	telegramID, message, err = uc.telegramHandlers.SendProgressUpdate(ctx, job.JobID, entity.JobStatusTranscribed)
-	if err == nil {
-		uc.telegramHandlers.SendMessage(telegramID, message)
-	}
+	uc.telegramHandlers.SendProgressUpdate(ctx, job.JobID, entity.JobStatusTranscribed)
 
 	// Обновление задачи в базе данных
 	err = uc.jobRepo.SetTranscription(ctx, job.JobID, transcription)
@@ -98,18 +185,16 @@ func (uc *TranscriptionProcessingUseCase) ProcessTranscription(ctx context.Conte
 		return fmt.Errorf("failed to update job transcription: %w", err)
 	}
 
-	// Создание задачи для суммаризации
-	// Получаем user_id из payload
-	payloadMap, _ := job.Payload.(map[string]interface{})
-	userID, _ := payloadMap["user_id"].(int64)
+	uc.indexTranscript(ctx, job.JobID, transcription)
+	uc.detectDuplicate(ctx, job.JobID, job.UserID, transcription)
 
+	// Создание задачи для суммаризации: транскрипция уже сохранена в базе данных, поэтому через очередь
+	// передаются только идентификатор задачи и стиль краткого содержания, если его выбрали во время распознавания
 	summarizationJob := entity.QueueJob{
 		JobID:   job.JobID,
+		UserID:  job.UserID,
 		JobType: entity.JobTypeSummarization,
-		Payload: map[string]interface{}{
-			"transcription": transcription,
-			"user_id":       userID,
-		},
+		Payload: summarizationPayload(uc.chosenSummaryStyle(ctx, job.JobID)),
 	}
 
 	// Добавление задачи в очередь
@@ -142,14 +227,9 @@ func (uc *TranscriptionProcessingUseCase) ProcessTranscription(ctx context.Conte
 // ProcessTranscriptionWithTimestamps обрабатывает транскрибацию аудио файла с временными метками
 func (uc *TranscriptionProcessingUseCase) ProcessTranscriptionWithTimestamps(ctx context.Context, job entity.QueueJob) error {
 	// Получение данных из задачи
-	payload, ok := job.Payload.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("invalid payload type in job")
-	}
-
-	audioPath, ok := payload["audio_path"].(string)
-	if !ok {
-		return fmt.Errorf("audio_path not found in job payload or has invalid type")
+	audioPath, err := transcriptionAudioPath(job.Payload)
+	if err != nil {
+		return err
 	}
 
 	// Логирование начала обработки транскрибации с временными метками
@@ -158,19 +238,35 @@ func (uc *TranscriptionProcessingUseCase) ProcessTranscriptionWithTimestamps(ctx
 		"audio_path", audioPath,
 	)
 
-	// Обработка аудио файла для транскрибации
-	processedAudioPath, err := uc.audioService.ProcessAudio(ctx, audioPath, filepath.Base(audioPath))
+	// Получение задачи: от нее зависят профиль предобработки, ограничение времени и детализация распознавания
+	storedJob := uc.getStoredJob(ctx, job.JobID)
+
+	// Предобработка и распознавание ограничены по времени пропорционально длительности записи
+	timeout := transcriptionTimeout(uc.timeoutBase, uc.timeoutFactor, uc.audioDuration(ctx, storedJob, audioPath))
+	stageCtx, cancel := withStageTimeout(ctx, timeout)
+	defer cancel()
+
+	// Обработка аудио файла для транскрибации с выбранным профилем или подготовленный ранее WAV
+	processedAudioPath, profile, release, err := uc.preprocessAudio(stageCtx, job.JobID, storedJob, audioPath)
 	if err != nil {
+		err = stageTimeoutError(stageCtx, timeout, err)
 		uc.logger.Error("Failed to process audio for transcription with timestamps",
 			"error", err,
 		)
 		return fmt.Errorf("failed to process audio for transcription with timestamps: %w", err)
 	}
+	defer release()
+	uc.logger.Info("Audio preprocessed",
+		"job_id", job.JobID,
+		"audio_profile", profile,
+	)
 
 	// Транскрибация аудио файла с временными метками
-	// Используем обычный метод Transcribe, так как метод с временными метками не реализован
-	transcription, err := uc.transcriptionService.Transcribe(ctx, processedAudioPath)
+	opts := uc.userTranscriptionOptions(ctx, job.UserID)
+	opts.Granularity = entity.TranscriptionGranularitySegment
+	transcription, err := uc.transcribe(stageCtx, job.JobID, job.UserID, processedAudioPath, opts)
 	if err != nil {
+		err = stageTimeoutError(stageCtx, timeout, err)
 		uc.logger.Error("Failed to transcribe audio with timestamps",
 			"error", err,
 		)
@@ -186,6 +282,9 @@ func (uc *TranscriptionProcessingUseCase) ProcessTranscriptionWithTimestamps(ctx
 		return fmt.Errorf("failed to update job transcription with timestamps: %w", err)
 	}
 
+	uc.indexTranscript(ctx, job.JobID, transcription)
+	uc.detectDuplicate(ctx, job.JobID, job.UserID, transcription)
+
 	// Обновление статуса задачи
 	err = uc.jobRepo.UpdateStatus(ctx, job.JobID, entity.JobStatusTranscribed, "")
 	if err != nil {
@@ -203,3 +302,89 @@ func (uc *TranscriptionProcessingUseCase) ProcessTranscriptionWithTimestamps(ctx
 
 	return nil
 }
+
+// userTranscriptionOptions возвращает параметры декодирования, заданные пользователем, с языком записей
+// из его настроек. Если пользователя не удалось получить, используются значения по умолчанию
+func (uc *TranscriptionProcessingUseCase) userTranscriptionOptions(ctx context.Context, userID int64) entity.TranscriptionOptions {
+	opts := entity.TranscriptionOptions{}
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		uc.logger.Warn("Failed to get user transcription options, using defaults",
+			"error", err,
+			"user_id", userID,
+		)
+	} else {
+		opts = user.TranscriptionOptions
+	}
+
+	opts.Language = uc.userSettings.GetOrDefault(ctx, userID).TranscriptionLanguage
+	return opts
+}
+
+// getStoredJob возвращает сохраненную задачу. Если задачу не удалось получить, возвращает nil:
+// распознавание продолжается с настройками по умолчанию
+func (uc *TranscriptionProcessingUseCase) getStoredJob(ctx context.Context, jobID int64) *entity.Job {
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		uc.logger.Warn("Failed to get job, using default transcription settings",
+			"error", err,
+			"job_id", jobID,
+		)
+		return nil
+	}
+
+	return job
+}
+
+// chosenSummaryStyle возвращает стиль краткого содержания, выбранный для задачи кнопками. Задача читается заново:
+// стиль можно выбрать, пока запись распознается. Если задачу не удалось получить, стиль берется из настроек
+func (uc *TranscriptionProcessingUseCase) chosenSummaryStyle(ctx context.Context, jobID int64) entity.SummaryStyle {
+	job := uc.getStoredJob(ctx, jobID)
+	if job == nil {
+		return ""
+	}
+	return job.SummaryStyle
+}
+
+// jobAudioProfile возвращает профиль предобработки, выбранный для задачи.
+// Если задачу не удалось получить, профиль выбирается автоматически
+func jobAudioProfile(job *entity.Job) entity.AudioProfile {
+	if job == nil {
+		return entity.AudioProfileAuto
+	}
+	return job.AudioProfile
+}
+
+// audioDuration возвращает длительность записи задачи в секундах: сохраненную при создании задачи
+// или, если ее нет, измеренную по файлу. Если длительность узнать не удалось, возвращает 0
+func (uc *TranscriptionProcessingUseCase) audioDuration(ctx context.Context, job *entity.Job, audioPath string) float64 {
+	if job != nil && job.Duration > 0 {
+		return job.Duration
+	}
+	jobID := int64(0)
+	if job != nil {
+		jobID = job.ID
+	}
+
+	duration, err := uc.audioService.GetAudioDuration(ctx, audioPath)
+	if err != nil {
+		uc.logger.Warn("Failed to get audio duration, transcription is not time-limited",
+			"error", err,
+			"job_id", jobID,
+		)
+		return 0
+	}
+
+	return duration
+}
+
+// indexTranscript сохраняет фрагменты транскрипции для поиска командой /find.
+// Без индекса задача обрабатывается дальше, а индекс будет построен при первом поиске
+func (uc *TranscriptionProcessingUseCase) indexTranscript(ctx context.Context, jobID int64, transcription string) {
+	if err := uc.transcriptSearch.IndexTranscript(ctx, jobID, transcription); err != nil {
+		uc.logger.Warn("Failed to index transcript",
+			"error", err,
+			"job_id", jobID,
+		)
+	}
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// usageUserKeyLength - длина обезличенного ключа пользователя (начало hex-записи хэша)
+const usageUserKeyLength = 32
+
+// maxUsageEventDetailLength - ограничение длины уточнения события: в него попадают только имена команд и источники
+const maxUsageEventDetailLength = 64
+
+// Источники задач в событиях создания задачи
+const (
+	usageSourceVoice     = "voice"      // Голосовое сообщение
+	usageSourceAudio     = "audio"      // Аудиофайл
+	usageSourceVideo     = "video"      // Видеосообщение или видеофайл
+	usageSourceText      = "text"       // Присланный текст
+	usageSourceLink      = "link"       // Ссылка на видео
+	usageSourcePodcast   = "podcast"    // Ссылка на выпуск подкаста
+	usageSourceAudioLink = "audio_link" // Прямая ссылка на аудиофайл
+	usageSourceTrim      = "trim"       // Фрагмент уже присланной записи (/trim)
+	usageSourceCall      = "call"       // Запись звонка из вебхука VoIP провайдера
+)
+
+// usageReportCommandsLimit - сколько самых частых команд показывается в отчете /admin usage
+const usageReportCommandsLimit = 15
+
+// UsageAnalyticsUseCase представляет собой сценарий обезличенной аналитики использования бота: вызовы
+// команд, источники задач и воронка от /start до первой завершенной задачи. Вместо пользователя
+// в событиях хранится хэш его Telegram ID с солью; пользователи, отказавшиеся от аналитики в /settings,
+// не учитываются
+type UsageAnalyticsUseCase struct {
+	userRepo            repository.UserRepository
+	eventRepo           repository.UsageEventRepository
+	userSettingsUseCase *UserSettingsUseCase
+	enabled             bool
+	salt                string
+	logger              *logger.Logger
+}
+
+// NewUsageAnalyticsUseCase создает новый сценарий аналитики использования.
+// enabled = false отключает запись событий; отчет при этом показывает уже записанные
+func NewUsageAnalyticsUseCase(
+	userRepo repository.UserRepository,
+	eventRepo repository.UsageEventRepository,
+	userSettingsUseCase *UserSettingsUseCase,
+	enabled bool,
+	salt string,
+	logger *logger.Logger,
+) *UsageAnalyticsUseCase {
+	return &UsageAnalyticsUseCase{
+		userRepo:            userRepo,
+		eventRepo:           eventRepo,
+		userSettingsUseCase: userSettingsUseCase,
+		enabled:             enabled,
+		salt:                salt,
+		logger:              logger,
+	}
+}
+
+// userKey возвращает обезличенный ключ пользователя
+func (uc *UsageAnalyticsUseCase) userKey(telegramID int64) string {
+	sum := sha256.Sum256([]byte(uc.salt + ":" + strconv.FormatInt(telegramID, 10)))
+	return hex.EncodeToString(sum[:])[:usageUserKeyLength]
+}
+
+// Track записывает событие пользователя. Незарегистрированные и отказавшиеся от аналитики пользователи
+// не учитываются; ошибки записи только логируются и не мешают обработке команды
+func (uc *UsageAnalyticsUseCase) Track(ctx context.Context, telegramID int64, event entity.UsageEventName, detail string) {
+	if !uc.enabled {
+		return
+	}
+
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil || user == nil {
+		return
+	}
+	if uc.userSettingsUseCase.GetOrDefault(ctx, user.ID).UsageAnalyticsOptOut {
+		return
+	}
+
+	usageEvent := &entity.UsageEvent{
+		UserKey: uc.userKey(telegramID),
+		Event:   event,
+		Detail:  truncateRunes(strings.ToLower(detail), maxUsageEventDetailLength, false),
+	}
+	if err := uc.eventRepo.Create(ctx, usageEvent); err != nil {
+		uc.logger.Warn("Failed to track usage event",
+			"error", err,
+			"event", event,
+		)
+	}
+}
+
+// TrackCommand записывает вызов команды; для /start также записывается начало воронки новых пользователей
+func (uc *UsageAnalyticsUseCase) TrackCommand(ctx context.Context, telegramID int64, command string) {
+	uc.Track(ctx, telegramID, entity.UsageEventCommand, command)
+	if command == "start" {
+		uc.Track(ctx, telegramID, entity.UsageEventStart, "")
+	}
+}
+
+// Forget удаляет записанные события пользователя после отказа от аналитики
+func (uc *UsageAnalyticsUseCase) Forget(ctx context.Context, telegramID int64) error {
+	if err := uc.eventRepo.DeleteByUserKey(ctx, uc.userKey(telegramID)); err != nil {
+		uc.logger.Error("Failed to delete usage events",
+			"error", err,
+		)
+		return fmt.Errorf("failed to delete usage events: %w", err)
+	}
+
+	return nil
+}
+
+// Report формирует отчет об использовании бота за последние days дней
+func (uc *UsageAnalyticsUseCase) Report(ctx context.Context, days int) (string, error) {
+	since := time.Now().AddDate(0, 0, -days)
+
+	counts, err := uc.eventRepo.CountSince(ctx, since)
+	if err != nil {
+		uc.logger.Error("Failed to count usage events",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to count usage events: %w", err)
+	}
+
+	funnel, err := uc.eventRepo.GetFunnelSince(ctx, since)
+	if err != nil {
+		uc.logger.Error("Failed to get usage funnel",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get usage funnel: %w", err)
+	}
+
+	report := formatUsageReport(ctx, counts, funnel, days)
+	if !uc.enabled {
+		report += "\n\n" + i18n.T(ctx, "usage.disabled")
+	}
+	return report, nil
+}
+
+// formatUsageReport формирует отчет /admin usage: воронку, источники задач и самые частые команды
+func formatUsageReport(ctx context.Context, counts []*entity.UsageEventCount, funnel *entity.UsageFunnel, days int) string {
+	if len(counts) == 0 {
+		return i18n.T(ctx, "usage.empty", days)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, "usage.header", days) + "\n\n")
+
+	builder.WriteString(i18n.T(ctx, "usage.funnel") + "\n")
+	builder.WriteString(fmt.Sprintf("• /start — %d\n", funnel.Started))
+	builder.WriteString(i18n.T(ctx, "usage.funnel.created", funnel.CreatedJob, usagePercent(funnel.CreatedJob, funnel.Started)) + "\n")
+	builder.WriteString(i18n.T(ctx, "usage.funnel.completed", funnel.CompletedJob, usagePercent(funnel.CompletedJob, funnel.Started)))
+
+	var created, completed, commands []*entity.UsageEventCount
+	for _, count := range counts {
+		switch count.Event {
+		case entity.UsageEventJobCreated:
+			created = append(created, count)
+		case entity.UsageEventJobCompleted:
+			completed = append(completed, count)
+		case entity.UsageEventCommand:
+			commands = append(commands, count)
+		}
+	}
+
+	writeUsageCounts(ctx, &builder, i18n.T(ctx, "usage.created"), "", created, 0)
+	writeUsageCounts(ctx, &builder, i18n.T(ctx, "usage.completed"), "", completed, 0)
+	writeUsageCounts(ctx, &builder, i18n.T(ctx, "usage.commands"), "/", commands, usageReportCommandsLimit)
+
+	return builder.String()
+}
+
+// writeUsageCounts добавляет в отчет раздел с числом событий и пользователей по убыванию; limit = 0 - без ограничения
+func writeUsageCounts(ctx context.Context, builder *strings.Builder, title, prefix string, counts []*entity.UsageEventCount, limit int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		return counts[i].Events > counts[j].Events
+	})
+
+	builder.WriteString("\n\n" + title + ":")
+	for i, count := range counts {
+		if limit > 0 && i == limit {
+			builder.WriteString("\n" + i18n.T(ctx, "usage.more", len(counts)-i))
+			break
+		}
+		builder.WriteString("\n" + i18n.T(ctx, "usage.count", prefix, count.Detail, count.Events, count.Users))
+	}
+}
+
+// usagePercent возвращает в скобках долю от пользователей, выполнивших /start
+func usagePercent(value, total int) string {
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d%%)", value*100/total)
+}
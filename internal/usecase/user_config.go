@@ -0,0 +1,336 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// MaxUserConfigSize - максимальный размер файла настроек пользователя в байтах
+const MaxUserConfigSize = 64 << 10
+
+// UserConfigFileName - имя файла, в котором пользователь получает свои настройки
+const UserConfigFileName = "obsidian-bot-config.json"
+
+// UserConfigUseCase представляет собой сценарий выгрузки и загрузки настроек пользователя:
+// параметров распознавания и суммаризации, автоудаления, Notion, вебхука, подписок на подкасты
+// и настроек обработки записей из /settings
+type UserConfigUseCase struct {
+	userRepo            repository.UserRepository
+	webhookRepo         repository.UserWebhookRepository
+	podcastRepo         repository.PodcastRepository
+	podcastUseCase      *PodcastUseCase
+	userSettingsUseCase *UserSettingsUseCase
+	logger              *logger.Logger
+}
+
+// NewUserConfigUseCase создает новый сценарий выгрузки и загрузки настроек пользователя
+func NewUserConfigUseCase(
+	userRepo repository.UserRepository,
+	webhookRepo repository.UserWebhookRepository,
+	podcastRepo repository.PodcastRepository,
+	podcastUseCase *PodcastUseCase,
+	userSettingsUseCase *UserSettingsUseCase,
+	logger *logger.Logger,
+) *UserConfigUseCase {
+	return &UserConfigUseCase{
+		userRepo:            userRepo,
+		webhookRepo:         webhookRepo,
+		podcastRepo:         podcastRepo,
+		podcastUseCase:      podcastUseCase,
+		userSettingsUseCase: userSettingsUseCase,
+		logger:              logger,
+	}
+}
+
+// Export возвращает настройки пользователя в формате JSON
+func (uc *UserConfigUseCase) Export(ctx context.Context, telegramID int64) ([]byte, error) {
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	config := entity.UserConfig{
+		Version:              entity.UserConfigVersion,
+		ExportedAt:           time.Now().UTC(),
+		ResultRetentionHours: user.ResultRetentionHours,
+		SummaryLanguage:      user.SummaryLanguage,
+		Transcription:        user.TranscriptionOptions,
+	}
+
+	if user.NotionDatabaseID != "" || user.NotionHubPageID != "" {
+		config.Notion = &entity.UserConfigNotion{
+			DatabaseID: user.NotionDatabaseID,
+			HubPageID:  user.NotionHubPageID,
+		}
+	}
+
+	// Получение настроек обработки записей
+	settings, err := uc.userSettingsUseCase.Get(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	config.Settings = &entity.UserConfigSettings{
+		TranscriptionLanguage:  settings.TranscriptionLanguage,
+		SummaryStyle:           settings.SummaryStyle,
+		SummaryStyleRemembered: settings.SummaryStyleRemembered,
+		NotionAutoSync:         settings.NotionAutoSync,
+		FailedJobsCleanup:      settings.FailedJobsCleanup,
+		UsageAnalyticsOptOut:   settings.UsageAnalyticsOptOut,
+		InterfaceLanguage:      settings.InterfaceLanguage,
+		SentimentAnalysis:      settings.SentimentAnalysis,
+		NotionTranscript:       settings.NotionTranscript,
+		NotionSummaryOnly:      settings.NotionSummaryOnly,
+		NotionAudioStats:       settings.NotionAudioStats,
+		NotionUsageCost:        settings.NotionUsageCost,
+	}
+
+	// Получение вебхука
+	webhook, err := uc.webhookRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get user webhook",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get user webhook: %w", err)
+	}
+	if webhook != nil {
+		config.Webhook = &entity.UserConfigWebhook{
+			URL:     webhook.URL,
+			Events:  webhook.Events,
+			Headers: webhook.Headers,
+		}
+	}
+
+	// Получение подписок на подкасты
+	feeds, err := uc.podcastRepo.GetFeedsByUserID(ctx, user.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get podcast feeds",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get podcast feeds: %w", err)
+	}
+	for _, feed := range feeds {
+		config.Podcasts = append(config.Podcasts, feed.URL)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal user config: %w", err)
+	}
+
+	return data, nil
+}
+
+// Import применяет к пользователю настройки, проверенные parseUserConfig
+func (uc *UserConfigUseCase) Import(ctx context.Context, telegramID int64, config *entity.UserConfig) (*entity.UserConfigImportResult, error) {
+	// Получение пользователя
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		uc.logger.Error("Failed to get user",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Сохранение настроек пользователя
+	user.ResultRetentionHours = config.ResultRetentionHours
+	user.SummaryLanguage = config.SummaryLanguage
+	user.TranscriptionOptions = config.Transcription
+	// Без токена база из файла недоступна, а /notion все равно создаст новую, поэтому ссылки
+	// на базу применяются только к уже подключенной интеграции
+	if config.Notion != nil && user.NotionToken != "" {
+		user.NotionDatabaseID = config.Notion.DatabaseID
+		user.NotionHubPageID = config.Notion.HubPageID
+	}
+	err = uc.userRepo.Update(ctx, user)
+	if err != nil {
+		uc.logger.Error("Failed to update user",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// Сохранение настроек обработки записей. Время последнего разбора задач с ошибками остается прежним
+	if config.Settings != nil {
+		settings, err := uc.userSettingsUseCase.Get(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		settings.TranscriptionLanguage = config.Settings.TranscriptionLanguage
+		settings.SummaryStyle = config.Settings.SummaryStyle
+		settings.SummaryStyleRemembered = config.Settings.SummaryStyleRemembered
+		settings.NotionAutoSync = config.Settings.NotionAutoSync
+		settings.FailedJobsCleanup = config.Settings.FailedJobsCleanup
+		settings.UsageAnalyticsOptOut = config.Settings.UsageAnalyticsOptOut
+		settings.InterfaceLanguage = config.Settings.InterfaceLanguage
+		settings.SentimentAnalysis = config.Settings.SentimentAnalysis
+		settings.NotionTranscript = config.Settings.NotionTranscript
+		settings.NotionSummaryOnly = config.Settings.NotionSummaryOnly
+		settings.NotionAudioStats = config.Settings.NotionAudioStats
+		settings.NotionUsageCost = config.Settings.NotionUsageCost
+		if err := uc.userSettingsUseCase.Save(ctx, settings); err != nil {
+			return nil, err
+		}
+	}
+
+	// Сохранение вебхука
+	if config.Webhook != nil {
+		webhook := &entity.UserWebhook{
+			UserID:  user.ID,
+			URL:     config.Webhook.URL,
+			Events:  config.Webhook.Events,
+			Headers: config.Webhook.Headers,
+		}
+		err = uc.webhookRepo.Save(ctx, webhook)
+		if err != nil {
+			uc.logger.Error("Failed to save user webhook",
+				"error", err,
+			)
+			return nil, fmt.Errorf("failed to save user webhook: %w", err)
+		}
+	}
+
+	result := &entity.UserConfigImportResult{
+		PodcastsFailed:  make([]string, 0),
+		NotionConnected: user.NotionToken != "",
+	}
+
+	// Подписка на подкасты, которых у пользователя еще нет
+	feeds, err := uc.podcastRepo.GetFeedsByUserID(ctx, user.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get podcast feeds",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get podcast feeds: %w", err)
+	}
+	subscribed := make(map[string]bool, len(feeds))
+	for _, feed := range feeds {
+		subscribed[feed.URL] = true
+	}
+
+	for _, feedURL := range config.Podcasts {
+		if subscribed[feedURL] {
+			result.PodcastsSkipped++
+			continue
+		}
+		// Лента могла перестать работать, поэтому ошибка одной ленты не мешает остальным
+		if _, err := uc.podcastUseCase.AddFeed(ctx, telegramID, feedURL); err != nil {
+			uc.logger.Warn("Failed to import podcast feed",
+				"error", err,
+				"url", feedURL,
+			)
+			result.PodcastsFailed = append(result.PodcastsFailed, feedURL)
+			continue
+		}
+		subscribed[feedURL] = true
+		result.PodcastsAdded++
+	}
+
+	// Логирование успешной загрузки настроек
+	uc.logger.Info("User config imported",
+		"telegram_id", telegramID,
+		"podcasts_added", result.PodcastsAdded,
+		"podcasts_failed", len(result.PodcastsFailed),
+	)
+
+	return result, nil
+}
+
+// parseUserConfig разбирает файл настроек и проверяет значения по тем же правилам, что и команды настройки.
+// Файл проверяется целиком до применения; при ошибке возвращается ее описание для пользователя
+func parseUserConfig(ctx context.Context, data []byte) (*entity.UserConfig, string) {
+	if len(data) > MaxUserConfigSize {
+		return nil, i18n.T(ctx, "config.problem.size")
+	}
+
+	var config entity.UserConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, i18n.T(ctx, "config.problem.json")
+	}
+
+	if config.Version != entity.UserConfigVersion {
+		return nil, i18n.T(ctx, "config.problem.version", config.Version)
+	}
+
+	if config.ResultRetentionHours < 0 || config.ResultRetentionHours > MaxResultRetentionHours {
+		return nil, i18n.T(ctx, "config.problem.retention", MaxResultRetentionHours)
+	}
+
+	language, ok := normalizeSummaryLanguage(config.SummaryLanguage)
+	if !ok {
+		return nil, i18n.T(ctx, "config.problem.language", config.SummaryLanguage)
+	}
+	config.SummaryLanguage = language
+
+	opts := config.Transcription
+	if opts.Temperature != nil && (*opts.Temperature < 0 || *opts.Temperature > 1) {
+		return nil, i18n.T(ctx, "config.problem.temperature")
+	}
+	if utf8.RuneCountInString(opts.Prompt) > maxWhisperPromptLength {
+		return nil, i18n.T(ctx, "config.problem.prompt", maxWhisperPromptLength)
+	}
+	if opts.Granularity != "" && opts.Granularity != entity.TranscriptionGranularityText && opts.Granularity != entity.TranscriptionGranularitySegment {
+		return nil, i18n.T(ctx, "config.problem.granularity", opts.Granularity)
+	}
+
+	if webhook := config.Webhook; webhook != nil {
+		if !validateWebhookURL(webhook.URL) {
+			return nil, i18n.T(ctx, "config.problem.webhook_url")
+		}
+		for _, event := range webhook.Events {
+			if event != entity.WebhookEventJobCompleted && event != entity.WebhookEventJobFailed {
+				return nil, i18n.T(ctx, "config.problem.webhook_event", event)
+			}
+		}
+		if len(webhook.Headers) > maxWebhookHeaders {
+			return nil, i18n.T(ctx, "config.problem.webhook_count", maxWebhookHeaders)
+		}
+		headers := make(map[string]string, len(webhook.Headers))
+		for name, value := range webhook.Headers {
+			name = http.CanonicalHeaderKey(name)
+			if !webhookHeaderNamePattern.MatchString(name) || reservedWebhookHeaders[name] || value == "" {
+				return nil, i18n.T(ctx, "config.problem.webhook_header", name)
+			}
+			headers[name] = value
+		}
+		webhook.Headers = headers
+	}
+
+	if settings := config.Settings; settings != nil {
+		if _, ok := summaryLanguageNames[settings.TranscriptionLanguage]; settings.TranscriptionLanguage != "" && !ok {
+			return nil, i18n.T(ctx, "config.problem.language", settings.TranscriptionLanguage)
+		}
+		if settings.SummaryStyle == "" {
+			settings.SummaryStyle = entity.SummaryStyleStandard
+		}
+		if _, ok := summaryStyleNames[settings.SummaryStyle]; !ok {
+			return nil, i18n.T(ctx, "config.problem.summary_style", settings.SummaryStyle)
+		}
+		if settings.InterfaceLanguage != "" && !i18n.Supported(settings.InterfaceLanguage) {
+			return nil, i18n.T(ctx, "config.problem.language", settings.InterfaceLanguage)
+		}
+	}
+
+	for i, feedURL := range config.Podcasts {
+		feedURL = strings.TrimSpace(feedURL)
+		config.Podcasts[i] = feedURL
+		if !validateWebhookURL(feedURL) {
+			return nil, i18n.T(ctx, "config.problem.podcast", feedURL)
+		}
+	}
+
+	return &config, ""
+}
@@ -0,0 +1,408 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// SettingsCallbackPrefix - префикс данных кнопок команды /settings.
+// Данные кнопки имеют вид settings:<настройка>:<значение>
+const SettingsCallbackPrefix = "settings"
+
+// Настройки, которые меняют кнопки команды /settings
+const (
+	settingTranscriptionLanguage = "lang"    // Язык записей
+	settingSummaryStyle          = "style"   // Стиль краткого содержания
+	settingNotionAutoSync        = "notion"  // Автоматическая выгрузка в Notion
+	settingFailedJobsCleanup     = "cleanup" // Ежедневный разбор задач с ошибками
+	settingUsageAnalytics        = "stats"   // Обезличенная аналитика использования
+	settingInterfaceLanguage     = "ui"      // Язык ответов бота
+	settingSentimentAnalysis     = "mood"    // Разбор настроения встреч
+	settingSummaryStylePrompt    = "ask"     // Вопрос о стиле краткого содержания для каждой записи
+	settingNotionTranscript      = "ntext"   // Полная транскрипция на странице Notion
+	settingNotionSummaryOnly     = "nbrief"  // Только краткое содержание на странице Notion
+	settingNotionAudioStats      = "naudio"  // Сведения о записи на странице Notion
+	settingNotionUsageCost       = "ncost"   // Стоимость суммаризации на странице Notion
+)
+
+// transcriptionLanguageAuto - язык записи определяется Whisper автоматически
+const transcriptionLanguageAuto = "auto"
+
+// interfaceLanguageAuto - язык ответов бота определяется по профилю Telegram
+const interfaceLanguageAuto = "auto"
+
+// summaryStyleNames - идентификаторы сообщений с названиями стилей краткого содержания для кнопок и ответов пользователю
+var summaryStyleNames = map[entity.SummaryStyle]string{
+	entity.SummaryStyleStandard: "summary_style.standard",
+	entity.SummaryStyleBrief:    "summary_style.brief",
+	entity.SummaryStyleDetailed: "summary_style.detailed",
+	entity.SummaryStyleBullets:  "summary_style.bullets",
+	entity.SummaryStyleMinutes:  "summary_style.minutes",
+}
+
+// summaryStyleInstructions - указания модели для стилей краткого содержания; для обычного стиля указаний нет
+var summaryStyleInstructions = map[entity.SummaryStyle]string{
+	entity.SummaryStyleBrief:    "Сделай краткое содержание коротким: 3-5 предложений о самом главном, без разделов и списков.",
+	entity.SummaryStyleDetailed: "Сделай подробный конспект: разбей его на разделы по темам и перечисли в каждом все существенные факты, решения и цифры.",
+	entity.SummaryStyleBullets:  "Изложи краткое содержание только тезисами: маркированный список коротких пунктов без вступления и заключения.",
+	entity.SummaryStyleMinutes:  "Оформи краткое содержание как протокол встречи: участники, обсужденные вопросы, принятые решения и задачи с ответственными и сроками, если они названы.",
+}
+
+// defaultUserSettings возвращает настройки пользователя, который их еще не менял
+func defaultUserSettings(userID int64) *entity.UserSettings {
+	return &entity.UserSettings{
+		UserID:           userID,
+		SummaryStyle:     entity.SummaryStyleStandard,
+		NotionAutoSync:   true,
+		NotionTranscript: true,
+	}
+}
+
+// UserSettingsUseCase представляет собой сценарий настроек обработки записей: языка записей,
+// стиля краткого содержания, автоматической выгрузки в Notion, ежедневного разбора задач с ошибками,
+// участия в обезличенной аналитике использования, языка ответов бота, разбора настроения встреч
+// и содержимого страниц задач в Notion
+type UserSettingsUseCase struct {
+	settingsRepo repository.UserSettingsRepository
+	logger       *logger.Logger
+}
+
+// NewUserSettingsUseCase создает новый сценарий настроек обработки записей
+func NewUserSettingsUseCase(
+	settingsRepo repository.UserSettingsRepository,
+	logger *logger.Logger,
+) *UserSettingsUseCase {
+	return &UserSettingsUseCase{
+		settingsRepo: settingsRepo,
+		logger:       logger,
+	}
+}
+
+// Get возвращает настройки пользователя; если пользователь их не менял, возвращаются значения по умолчанию
+func (uc *UserSettingsUseCase) Get(ctx context.Context, userID int64) (*entity.UserSettings, error) {
+	settings, err := uc.settingsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		uc.logger.Error("Failed to get user settings",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get user settings: %w", err)
+	}
+	if settings == nil {
+		return defaultUserSettings(userID), nil
+	}
+
+	return settings, nil
+}
+
+// GetOrDefault возвращает настройки пользователя для этапов обработки задачи. Если настройки не удалось
+// получить, обработка продолжается со значениями по умолчанию
+func (uc *UserSettingsUseCase) GetOrDefault(ctx context.Context, userID int64) *entity.UserSettings {
+	settings, err := uc.Get(ctx, userID)
+	if err != nil {
+		uc.logger.Warn("Using default user settings",
+			"error", err,
+			"user_id", userID,
+		)
+		return defaultUserSettings(userID)
+	}
+
+	return settings
+}
+
+// Apply меняет одну настройку пользователя по данным кнопки (<настройка>:<значение>) и возвращает
+// сохраненные настройки. Для неизвестной настройки или значения возвращается ошибка
+func (uc *UserSettingsUseCase) Apply(ctx context.Context, userID int64, data string) (*entity.UserSettings, error) {
+	settings, err := uc.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	setting, value, _ := strings.Cut(data, ":")
+	switch setting {
+	case settingTranscriptionLanguage:
+		if value == transcriptionLanguageAuto {
+			settings.TranscriptionLanguage = ""
+		} else if _, ok := summaryLanguageNames[value]; ok {
+			settings.TranscriptionLanguage = value
+		} else {
+			return nil, fmt.Errorf("unsupported transcription language %q", value)
+		}
+	case settingSummaryStyle:
+		style := entity.SummaryStyle(value)
+		if _, ok := summaryStyleNames[style]; !ok {
+			return nil, fmt.Errorf("unsupported summary style %q", value)
+		}
+		settings.SummaryStyle = style
+	case settingNotionAutoSync:
+		settings.NotionAutoSync = value == "on"
+	case settingFailedJobsCleanup:
+		settings.FailedJobsCleanup = value == "on"
+	case settingUsageAnalytics:
+		settings.UsageAnalyticsOptOut = value == "off"
+	case settingSentimentAnalysis:
+		settings.SentimentAnalysis = value == "on"
+	case settingSummaryStylePrompt:
+		settings.SummaryStyleRemembered = value == "off"
+	case settingNotionTranscript:
+		settings.NotionTranscript = value == "on"
+	case settingNotionSummaryOnly:
+		settings.NotionSummaryOnly = value == "on"
+	case settingNotionAudioStats:
+		settings.NotionAudioStats = value == "on"
+	case settingNotionUsageCost:
+		settings.NotionUsageCost = value == "on"
+	case settingInterfaceLanguage:
+		if value == interfaceLanguageAuto {
+			settings.InterfaceLanguage = ""
+		} else if i18n.Supported(value) {
+			settings.InterfaceLanguage = value
+		} else {
+			return nil, fmt.Errorf("unsupported interface language %q", value)
+		}
+	default:
+		return nil, fmt.Errorf("unknown setting %q", setting)
+	}
+
+	if err := uc.settingsRepo.Upsert(ctx, settings); err != nil {
+		uc.logger.Error("Failed to save user settings",
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to save user settings: %w", err)
+	}
+
+	// Логирование изменения настройки
+	uc.logger.Info("User setting changed",
+		"user_id", userID,
+		"setting", setting,
+		"value", value,
+	)
+
+	return settings, nil
+}
+
+// Save сохраняет настройки пользователя целиком, например восстановленные из файла настроек
+func (uc *UserSettingsUseCase) Save(ctx context.Context, settings *entity.UserSettings) error {
+	if err := uc.settingsRepo.Upsert(ctx, settings); err != nil {
+		uc.logger.Error("Failed to save user settings",
+			"error", err,
+		)
+		return fmt.Errorf("failed to save user settings: %w", err)
+	}
+
+	return nil
+}
+
+// RememberSummaryStyle сохраняет стиль краткого содержания, выбранный под сообщением о приеме записи,
+// и отключает вопрос о стиле для следующих записей
+func (uc *UserSettingsUseCase) RememberSummaryStyle(ctx context.Context, userID int64, style entity.SummaryStyle) error {
+	settings, err := uc.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	settings.SummaryStyle = style
+	settings.SummaryStyleRemembered = true
+	if err := uc.settingsRepo.Upsert(ctx, settings); err != nil {
+		uc.logger.Error("Failed to save user settings",
+			"error", err,
+		)
+		return fmt.Errorf("failed to save user settings: %w", err)
+	}
+
+	// Логирование запомненного стиля
+	uc.logger.Info("Summary style remembered",
+		"user_id", userID,
+		"summary_style", style,
+	)
+
+	return nil
+}
+
+// summaryStyleInstruction возвращает указание модели для стиля краткого содержания
+func summaryStyleInstruction(style entity.SummaryStyle) string {
+	return summaryStyleInstructions[style]
+}
+
+// formatUserSettings формирует описание настроек пользователя для сообщения команды /settings
+func formatUserSettings(ctx context.Context, settings *entity.UserSettings, notionConnected bool) string {
+	var builder strings.Builder
+	builder.WriteString(i18n.T(ctx, "settings.header") + "\n\n")
+
+	if _, ok := summaryLanguageNames[settings.TranscriptionLanguage]; ok {
+		builder.WriteString(i18n.T(ctx, "settings.transcription_language", i18n.T(ctx, "language.name."+settings.TranscriptionLanguage)) + "\n")
+	} else {
+		builder.WriteString(i18n.T(ctx, "settings.transcription_language.auto") + "\n")
+	}
+
+	builder.WriteString(i18n.T(ctx, "settings.summary_style", summaryStyleName(ctx, settings.SummaryStyle)))
+	if settings.SummaryStyleRemembered {
+		builder.WriteString(i18n.T(ctx, "settings.summary_style.remembered") + "\n")
+	} else {
+		builder.WriteString(i18n.T(ctx, "settings.summary_style.prompted") + "\n")
+	}
+
+	switch {
+	case !settings.NotionAutoSync:
+		builder.WriteString(i18n.T(ctx, "settings.notion_sync.off"))
+	case notionConnected:
+		builder.WriteString(i18n.T(ctx, "settings.notion_sync.on"))
+	default:
+		builder.WriteString(i18n.T(ctx, "settings.notion_sync.not_connected"))
+	}
+
+	builder.WriteString("\n" + i18n.T(ctx, "settings.notion_content", notionContentDescription(ctx, settings)))
+
+	if settings.FailedJobsCleanup {
+		builder.WriteString("\n" + i18n.T(ctx, "settings.failed_jobs.on"))
+	} else {
+		builder.WriteString("\n" + i18n.T(ctx, "settings.failed_jobs.off"))
+	}
+
+	if settings.UsageAnalyticsOptOut {
+		builder.WriteString("\n" + i18n.T(ctx, "settings.analytics.off"))
+	} else {
+		builder.WriteString("\n" + i18n.T(ctx, "settings.analytics.on"))
+	}
+
+	if settings.SentimentAnalysis {
+		builder.WriteString("\n" + i18n.T(ctx, "settings.sentiment.on"))
+	} else {
+		builder.WriteString("\n" + i18n.T(ctx, "settings.sentiment.off"))
+	}
+
+	if name, ok := i18n.Names[settings.InterfaceLanguage]; ok {
+		builder.WriteString("\n" + i18n.T(ctx, "settings.interface_language", name))
+	} else {
+		builder.WriteString("\n" + i18n.T(ctx, "settings.interface_language.auto"))
+	}
+
+	builder.WriteString("\n\n" + i18n.T(ctx, "settings.choose"))
+	return builder.String()
+}
+
+// notionContentDescription перечисляет разделы страницы задачи в Notion, включенные в настройках
+func notionContentDescription(ctx context.Context, settings *entity.UserSettings) string {
+	if settings.NotionSummaryOnly {
+		return i18n.T(ctx, "settings.notion_content.summary_only")
+	}
+
+	parts := []string{i18n.T(ctx, "settings.notion_content.summary")}
+	if settings.NotionTranscript {
+		parts = append(parts, i18n.T(ctx, "settings.notion_content.transcript"))
+	}
+	if settings.NotionAudioStats {
+		parts = append(parts, i18n.T(ctx, "settings.notion_content.audio_stats"))
+	}
+	if settings.NotionUsageCost {
+		parts = append(parts, i18n.T(ctx, "settings.notion_content.usage_cost"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// userSettingsButtons возвращает кнопки команды /settings; выбранные значения отмечены галочкой
+func userSettingsButtons(ctx context.Context, settings *entity.UserSettings) [][]entity.MessageButton {
+	button := func(label string, selected bool, setting, value string) entity.MessageButton {
+		if selected {
+			label = "✅ " + label
+		}
+		return entity.MessageButton{Text: label, Data: SettingsCallbackPrefix + ":" + setting + ":" + value}
+	}
+
+	// Язык записей: автоопределение и коды языков по три в ряд
+	languages := append([]string{transcriptionLanguageAuto}, summaryLanguageCodes...)
+	var buttons [][]entity.MessageButton
+	var row []entity.MessageButton
+	for _, code := range languages {
+		selected := settings.TranscriptionLanguage == code || (code == transcriptionLanguageAuto && settings.TranscriptionLanguage == "")
+		label := strings.ToUpper(code)
+		if code == transcriptionLanguageAuto {
+			label = i18n.T(ctx, "settings.button.auto")
+		}
+		row = append(row, button(label, selected, settingTranscriptionLanguage, code))
+		if len(row) == 3 {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+
+	// Стиль краткого содержания
+	styles := make([]entity.MessageButton, 0, len(entity.SummaryStyles))
+	for _, style := range entity.SummaryStyles {
+		styles = append(styles, button(i18n.T(ctx, summaryStyleNames[style]), settings.SummaryStyle == style, settingSummaryStyle, string(style)))
+	}
+	buttons = append(buttons, styles[:3], styles[3:])
+
+	// Вопрос о стиле для каждой записи
+	if settings.SummaryStyleRemembered {
+		buttons = append(buttons, []entity.MessageButton{button(i18n.T(ctx, "settings.button.style_prompt.on"), false, settingSummaryStylePrompt, "on")})
+	} else {
+		buttons = append(buttons, []entity.MessageButton{button(i18n.T(ctx, "settings.button.style_prompt.off"), false, settingSummaryStylePrompt, "off")})
+	}
+
+	// Выгрузка в Notion
+	if settings.NotionAutoSync {
+		buttons = append(buttons, []entity.MessageButton{button(i18n.T(ctx, "settings.button.notion_sync.off"), false, settingNotionAutoSync, "off")})
+	} else {
+		buttons = append(buttons, []entity.MessageButton{button(i18n.T(ctx, "settings.button.notion_sync.on"), false, settingNotionAutoSync, "on")})
+	}
+
+	// Содержимое страницы задачи в Notion: кнопки переключают разделы, включенные отмечены галочкой
+	toggle := func(label string, enabled bool, setting string) entity.MessageButton {
+		value := "on"
+		if enabled {
+			value = "off"
+		}
+		return button(label, enabled, setting, value)
+	}
+	buttons = append(buttons,
+		[]entity.MessageButton{
+			toggle(i18n.T(ctx, "settings.button.notion_transcript"), settings.NotionTranscript, settingNotionTranscript),
+			toggle(i18n.T(ctx, "settings.button.notion_summary_only"), settings.NotionSummaryOnly, settingNotionSummaryOnly),
+		},
+		[]entity.MessageButton{
+			toggle(i18n.T(ctx, "settings.button.notion_audio_stats"), settings.NotionAudioStats, settingNotionAudioStats),
+			toggle(i18n.T(ctx, "settings.button.notion_usage_cost"), settings.NotionUsageCost, settingNotionUsageCost),
+		},
+	)
+
+	// Ежедневный разбор задач с ошибками
+	if settings.FailedJobsCleanup {
+		buttons = append(buttons, []entity.MessageButton{button(i18n.T(ctx, "settings.button.failed_jobs.off"), false, settingFailedJobsCleanup, "off")})
+	} else {
+		buttons = append(buttons, []entity.MessageButton{button(i18n.T(ctx, "settings.button.failed_jobs.on"), false, settingFailedJobsCleanup, "on")})
+	}
+
+	// Обезличенная аналитика использования
+	if settings.UsageAnalyticsOptOut {
+		buttons = append(buttons, []entity.MessageButton{button(i18n.T(ctx, "settings.button.analytics.on"), false, settingUsageAnalytics, "on")})
+	} else {
+		buttons = append(buttons, []entity.MessageButton{button(i18n.T(ctx, "settings.button.analytics.off"), false, settingUsageAnalytics, "off")})
+	}
+
+	// Разбор настроения встреч
+	if settings.SentimentAnalysis {
+		buttons = append(buttons, []entity.MessageButton{button(i18n.T(ctx, "settings.button.sentiment.off"), false, settingSentimentAnalysis, "off")})
+	} else {
+		buttons = append(buttons, []entity.MessageButton{button(i18n.T(ctx, "settings.button.sentiment.on"), false, settingSentimentAnalysis, "on")})
+	}
+
+	// Язык ответов бота: по профилю Telegram и языки с каталогом сообщений
+	interfaceLanguages := []entity.MessageButton{
+		button(i18n.T(ctx, "settings.interface_language.any"), settings.InterfaceLanguage == "", settingInterfaceLanguage, interfaceLanguageAuto),
+	}
+	for _, language := range i18n.Languages {
+		interfaceLanguages = append(interfaceLanguages, button(i18n.Names[language], settings.InterfaceLanguage == language, settingInterfaceLanguage, language))
+	}
+	buttons = append(buttons, interfaceLanguages)
+
+	return buttons
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/112Alex/project_obsidian/pkg/i18n"
+)
+
+// HandleStats обрабатывает команду /stats и возвращает итоги использования бота пользователем за все время:
+// число задач по результату, минуты распознанных записей и токены, потраченные на краткие содержания
+func (uc *TelegramHandlersUseCase) HandleStats(ctx context.Context, telegramID int64) (string, error) {
+	// Логирование начала обработки команды /stats
+	uc.logger.Info("Handling /stats command",
+		"telegram_id", telegramID,
+	)
+
+	// Пользователь, который еще ничего не отправлял, может быть не сохранен
+	user, err := uc.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil || user == nil {
+		return i18n.T(ctx, "stats.empty"), nil
+	}
+
+	usage, err := uc.jobRepo.GetUserUsage(ctx, user.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get user usage",
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to get user usage: %w", err)
+	}
+	if usage.TotalJobs == 0 {
+		return i18n.T(ctx, "stats.empty"), nil
+	}
+
+	messageBuilder := strings.Builder{}
+	messageBuilder.WriteString(i18n.T(ctx, "stats.header") + "\n\n")
+	messageBuilder.WriteString(i18n.T(ctx, "stats.total", usage.TotalJobs) + "\n")
+	messageBuilder.WriteString(i18n.T(ctx, "stats.completed", usage.CompletedJobs) + "\n")
+	messageBuilder.WriteString(i18n.T(ctx, "stats.failed", usage.FailedJobs) + "\n")
+	if active := usage.TotalJobs - usage.CompletedJobs - usage.FailedJobs; active > 0 {
+		messageBuilder.WriteString(i18n.T(ctx, "stats.active", active) + "\n")
+	}
+	messageBuilder.WriteString("\n" + i18n.T(ctx, "stats.minutes", usage.SecondsTranscribed/60) + "\n")
+	messageBuilder.WriteString(i18n.T(ctx, "stats.tokens", usage.SummaryTokens, usage.SummaryCost))
+
+	// Логирование успешной обработки команды /stats
+	uc.logger.Info("Successfully handled /stats command",
+		"telegram_id", telegramID,
+		"total_jobs", usage.TotalJobs,
+	)
+
+	return messageBuilder.String(), nil
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/repository"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// maxWebhookHeaders - максимальное число дополнительных заголовков вебхука
+const maxWebhookHeaders = 10
+
+// webhookHeaderNamePattern - допустимое имя заголовка HTTP
+var webhookHeaderNamePattern = regexp.MustCompile(`^[A-Za-z0-9!#$%&'*+.^_|~-]{1,64}$`)
+
+// reservedWebhookHeaders - заголовки, которые формирует сам клиент и которые нельзя переопределить
+var reservedWebhookHeaders = map[string]bool{
+	"Content-Type":      true,
+	"Content-Length":    true,
+	"Host":              true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+}
+
+// UserWebhookUseCase представляет собой сценарий работы с вебхуками пользователей:
+// события задач пользователя отправляются на его адрес (например, в сценарий n8n или Zapier)
+type UserWebhookUseCase struct {
+	userRepo       repository.UserRepository
+	jobRepo        repository.JobRepository
+	webhookRepo    repository.UserWebhookRepository
+	webhookService service.WebhookService
+	logger         *logger.Logger
+}
+
+// NewUserWebhookUseCase создает новый сценарий работы с вебхуками пользователей
+func NewUserWebhookUseCase(
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	webhookRepo repository.UserWebhookRepository,
+	webhookService service.WebhookService,
+	logger *logger.Logger,
+) *UserWebhookUseCase {
+	return &UserWebhookUseCase{
+		userRepo:       userRepo,
+		jobRepo:        jobRepo,
+		webhookRepo:    webhookRepo,
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// validateWebhookURL проверяет, что адрес вебхука - абсолютный адрес HTTP(S)
+func validateWebhookURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// parseWebhookEvents разбирает фильтр событий: all, completed, failed или их перечисление через запятую.
+// Пустой результат означает все события
+func parseWebhookEvents(value string) ([]entity.WebhookEvent, bool) {
+	seen := make(map[entity.WebhookEvent]bool)
+	events := make([]entity.WebhookEvent, 0, 2)
+	for _, name := range strings.Split(strings.ToLower(value), ",") {
+		var event entity.WebhookEvent
+		switch strings.TrimSpace(name) {
+		case "all":
+			return nil, true
+		case "completed", string(entity.WebhookEventJobCompleted):
+			event = entity.WebhookEventJobCompleted
+		case "failed", string(entity.WebhookEventJobFailed):
+			event = entity.WebhookEventJobFailed
+		default:
+			return nil, false
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+
+	// Фильтр из всех событий равносилен отсутствию фильтра
+	if len(events) == 2 {
+		return nil, true
+	}
+	return events, true
+}
+
+// GetWebhook возвращает вебхук пользователя или nil
+func (uc *UserWebhookUseCase) GetWebhook(ctx context.Context, userID int64) (*entity.UserWebhook, error) {
+	webhook, err := uc.webhookRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// SetURL задает адрес вебхука пользователя, сохраняя фильтр событий и заголовки
+func (uc *UserWebhookUseCase) SetURL(ctx context.Context, userID int64, webhookURL string) (*entity.UserWebhook, error) {
+	webhook, err := uc.GetWebhook(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		webhook = &entity.UserWebhook{UserID: userID}
+	}
+	webhook.URL = webhookURL
+
+	if err := uc.save(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// SetEvents задает фильтр событий вебхука; пустой фильтр - все события
+func (uc *UserWebhookUseCase) SetEvents(ctx context.Context, webhook *entity.UserWebhook, events []entity.WebhookEvent) error {
+	webhook.Events = events
+	return uc.save(ctx, webhook)
+}
+
+// SetHeader задает дополнительный заголовок вебхука; пустое значение удаляет заголовок.
+// Возвращает false, если превышено число заголовков
+func (uc *UserWebhookUseCase) SetHeader(ctx context.Context, webhook *entity.UserWebhook, name, value string) (bool, error) {
+	name = http.CanonicalHeaderKey(name)
+	if webhook.Headers == nil {
+		webhook.Headers = make(map[string]string)
+	}
+
+	if value == "" {
+		delete(webhook.Headers, name)
+	} else {
+		if _, exists := webhook.Headers[name]; !exists && len(webhook.Headers) >= maxWebhookHeaders {
+			return false, nil
+		}
+		webhook.Headers[name] = value
+	}
+
+	if err := uc.save(ctx, webhook); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DeleteWebhook удаляет вебхук пользователя и сообщает, был ли он настроен
+func (uc *UserWebhookUseCase) DeleteWebhook(ctx context.Context, userID int64) (bool, error) {
+	deleted, err := uc.webhookRepo.Delete(ctx, userID)
+	if err != nil {
+		uc.logger.Error("Failed to delete user webhook",
+			"error", err,
+		)
+		return false, fmt.Errorf("failed to delete user webhook: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// SendTest отправляет на вебхук тестовое событие с примером задачи, чтобы по нему можно было
+// настроить сценарий n8n или Zapier. Ошибка доставки возвращается вызывающему для показа пользователю
+func (uc *UserWebhookUseCase) SendTest(ctx context.Context, user *entity.User, webhook *entity.UserWebhook) error {
+	sample := &entity.Job{
+		Type:          entity.JobTypeTranscription,
+		FileName:      "test.ogg",
+		CreatedAt:     time.Now(),
+		Summary:       "Тестовая доставка вебхука: здесь будет краткое содержание задачи.",
+		Transcription: "Тестовая доставка вебхука: здесь будет полная транскрипция задачи.",
+	}
+
+	return uc.webhookService.Deliver(ctx, webhook, entity.WebhookEventTest, user, sample, "")
+}
+
+// NotifyJob отправляет событие задачи на вебхук ее владельца, если он настроен и принимает событие.
+// Ошибки доставки только логируются: вебхук не должен влиять на обработку задачи
+func (uc *UserWebhookUseCase) NotifyJob(ctx context.Context, jobID int64, event entity.WebhookEvent, errorMessage string) {
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		uc.logger.Warn("Failed to get job for webhook event",
+			"error", err,
+			"job_id", jobID,
+		)
+		return
+	}
+
+	webhook, err := uc.webhookRepo.GetByUserID(ctx, job.UserID)
+	if err != nil {
+		uc.logger.Warn("Failed to get user webhook",
+			"error", err,
+			"job_id", jobID,
+		)
+		return
+	}
+	if webhook == nil || !webhook.Accepts(event) {
+		return
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, job.UserID)
+	if err != nil {
+		uc.logger.Warn("Failed to get user for webhook event",
+			"error", err,
+			"job_id", jobID,
+		)
+		return
+	}
+
+	if err := uc.webhookService.Deliver(ctx, webhook, event, user, job, errorMessage); err != nil {
+		return
+	}
+
+	// Логирование успешной доставки
+	uc.logger.Info("Webhook event delivered",
+		"job_id", jobID,
+		"event", event,
+	)
+}
+
+// save сохраняет вебхук пользователя
+func (uc *UserWebhookUseCase) save(ctx context.Context, webhook *entity.UserWebhook) error {
+	err := uc.webhookRepo.Save(ctx, webhook)
+	if err != nil {
+		uc.logger.Error("Failed to save user webhook",
+			"error", err,
+		)
+		return fmt.Errorf("failed to save user webhook: %w", err)
+	}
+
+	return nil
+}
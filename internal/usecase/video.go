@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/112Alex/project_obsidian/internal/domain/entity"
+	"github.com/112Alex/project_obsidian/internal/domain/service"
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// videoHosts - сайты, ссылки на которые обрабатываются как видео
+var videoHosts = map[string]bool{
+	"youtube.com":       true,
+	"m.youtube.com":     true,
+	"music.youtube.com": true,
+	"youtu.be":          true,
+	"vimeo.com":         true,
+	"player.vimeo.com":  true,
+}
+
+// podcastHosts - сайты, ссылки на выпуски подкастов с которых обрабатываются как выпуски. Ссылка на сайт
+// подходит и для его поддоменов: у некоторых площадок у каждого подкаста свой поддомен
+var podcastHosts = map[string]bool{
+	"podcasts.apple.com": true,
+	"soundcloud.com":     true,
+	"castbox.fm":         true,
+	"podbean.com":        true,
+	"mixcloud.com":       true,
+	"music.yandex.ru":    true,
+	"music.yandex.com":   true,
+}
+
+// ParseVideoLink проверяет, что сообщение состоит из одной ссылки на видео, и возвращает ее
+func ParseVideoLink(text string) (string, bool) {
+	return parseHostLink(text, func(host string) bool {
+		return videoHosts[host]
+	})
+}
+
+// ParsePodcastLink проверяет, что сообщение состоит из одной ссылки на выпуск подкаста, и возвращает ее
+func ParsePodcastLink(text string) (string, bool) {
+	return parseHostLink(text, func(host string) bool {
+		for ; host != ""; _, host, _ = strings.Cut(host, ".") {
+			if podcastHosts[host] {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// parseHostLink проверяет, что сообщение состоит из одной ссылки http(s) на сайт, подходящий под match, и возвращает ее
+func parseHostLink(text string, match func(host string) bool) (string, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.ContainsAny(text, " \n\t") {
+		return "", false
+	}
+
+	parsed, err := url.Parse(text)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	if !match(host) {
+		return "", false
+	}
+
+	return text, true
+}
+
+// VideoUseCase представляет собой сценарий обработки видео и выпусков подкастов, присланных ссылкой: звуковая
+// дорожка загружается через yt-dlp и обрабатывается как аудиозапись, а краткое содержание составляется по главам
+type VideoUseCase struct {
+	videoService           service.VideoService
+	audioProcessingUseCase *AudioProcessingUseCase
+	enabled                bool
+	maxDuration            time.Duration
+	logger                 *logger.Logger
+}
+
+// NewVideoUseCase создает новый сценарий обработки видео и выпусков подкастов по ссылке.
+// enabled = false отключает обработку ссылок, maxDuration <= 0 снимает ограничение длительности
+func NewVideoUseCase(
+	videoService service.VideoService,
+	audioProcessingUseCase *AudioProcessingUseCase,
+	enabled bool,
+	maxDuration time.Duration,
+	logger *logger.Logger,
+) *VideoUseCase {
+	return &VideoUseCase{
+		videoService:           videoService,
+		audioProcessingUseCase: audioProcessingUseCase,
+		enabled:                enabled,
+		maxDuration:            maxDuration,
+		logger:                 logger,
+	}
+}
+
+// Enabled сообщает, обрабатываются ли ссылки на видео и выпуски подкастов
+func (uc *VideoUseCase) Enabled() bool {
+	return uc.enabled
+}
+
+// MaxDuration возвращает наибольшую длительность обрабатываемого видео; 0 - без ограничения
+func (uc *VideoUseCase) MaxDuration() time.Duration {
+	return uc.maxDuration
+}
+
+// FetchInfo возвращает описание видео. Для видео длиннее допустимого возвращает описание и false
+func (uc *VideoUseCase) FetchInfo(ctx context.Context, videoURL string) (*entity.VideoInfo, bool, error) {
+	video, err := uc.videoService.FetchInfo(ctx, videoURL)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if uc.maxDuration > 0 && time.Duration(video.Duration*float64(time.Second)) > uc.maxDuration {
+		uc.logger.Info("Video is too long",
+			"url", videoURL,
+			"duration", video.Duration,
+		)
+		return video, false, nil
+	}
+
+	return video, true, nil
+}
+
+// Process загружает звуковую дорожку видео или выпуска подкаста и создает задачу ее обработки с типом
+// jobType (JobTypeVideo или JobTypePodcastLink)
+func (uc *VideoUseCase) Process(ctx context.Context, user *entity.User, sourceMessageID int, video *entity.VideoInfo, jobType entity.JobType) (int64, error) {
+	audioPath, err := uc.videoService.DownloadAudio(ctx, user.ID, video)
+	if err != nil {
+		return 0, err
+	}
+
+	jobID, err := uc.audioProcessingUseCase.ProcessVideo(ctx, user, sourceMessageID, audioPath, video, jobType)
+	if err != nil {
+		return 0, fmt.Errorf("failed to process video: %w", err)
+	}
+
+	return jobID, nil
+}
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_share_links_job_id;
+DROP TABLE IF EXISTS share_links;
+
+COMMIT;
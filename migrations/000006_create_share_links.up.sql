@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Создание таблицы публичных ссылок на результаты задач
+CREATE TABLE IF NOT EXISTS share_links (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Создание индекса для быстрого поиска ссылок задачи
+CREATE INDEX IF NOT EXISTS idx_share_links_job_id ON share_links(job_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Добавляем тип задачи, чтобы отличать аудио от присланного текста
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS type VARCHAR(64) NOT NULL DEFAULT 'transcription';
+
+COMMIT;
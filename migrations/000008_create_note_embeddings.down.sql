@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_note_embeddings_embedding;
+DROP INDEX IF EXISTS idx_note_embeddings_job_id;
+DROP INDEX IF EXISTS idx_note_embeddings_user_id;
+DROP TABLE IF EXISTS note_embeddings;
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+-- Подключение расширения pgvector
+CREATE EXTENSION IF NOT EXISTS vector;
+
+-- Создание таблицы эмбеддингов заметок для семантического поиска
+CREATE TABLE IF NOT EXISTS note_embeddings (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL,
+    chunk_index INTEGER NOT NULL DEFAULT 0,
+    content TEXT NOT NULL,
+    embedding vector(1536) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Создание индексов для поиска по пользователю и по близости векторов
+CREATE INDEX IF NOT EXISTS idx_note_embeddings_user_id ON note_embeddings(user_id);
+CREATE INDEX IF NOT EXISTS idx_note_embeddings_job_id ON note_embeddings(job_id);
+CREATE INDEX IF NOT EXISTS idx_note_embeddings_embedding ON note_embeddings USING hnsw (embedding vector_cosine_ops);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Через сколько часов удалять из чата сообщения бота с результатами (0 - не удалять)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS result_retention_hours INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- ID исходного сообщения в Telegram, в ответ на которое отправляются уведомления о задаче
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS source_message_id INTEGER;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE job_stages DROP COLUMN IF EXISTS external_calls;
+
+COMMIT;
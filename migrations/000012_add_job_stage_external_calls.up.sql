@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Внешние вызовы последней попытки этапа: провайдер, операция, статус, задержка и число повторов
+ALTER TABLE job_stages ADD COLUMN IF NOT EXISTS external_calls JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+COMMIT;
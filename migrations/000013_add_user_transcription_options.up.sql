@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Пользовательские параметры декодирования Whisper (температура, подсказка, детализация ответа)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS transcription_options JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Страница Notion со ссылками на представления базы транскрипций
+ALTER TABLE users ADD COLUMN IF NOT EXISTS notion_hub_page_id VARCHAR(255);
+
+COMMIT;
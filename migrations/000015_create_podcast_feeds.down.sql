@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS podcast_episodes;
+DROP TABLE IF EXISTS podcast_feeds;
+
+COMMIT;
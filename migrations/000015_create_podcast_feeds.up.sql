@@ -0,0 +1,30 @@
+BEGIN;
+
+-- Создание таблицы подписок на RSS-ленты подкастов
+CREATE TABLE IF NOT EXISTS podcast_feeds (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    notion_database_id VARCHAR(255),
+    last_checked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, url)
+);
+
+-- Создание таблицы учтенных выпусков; история сохраняется после отписки,
+-- чтобы при повторной подписке выпуски не обрабатывались заново
+CREATE TABLE IF NOT EXISTS podcast_episodes (
+    id SERIAL PRIMARY KEY,
+    feed_id INTEGER REFERENCES podcast_feeds(id) ON DELETE SET NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    guid TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    audio_url TEXT NOT NULL,
+    published_at TIMESTAMP WITH TIME ZONE,
+    job_id INTEGER REFERENCES jobs(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, guid)
+);
+
+COMMIT;
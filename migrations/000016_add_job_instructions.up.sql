@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Дополнительные указания пользователя к конспекту из подписи к аудио
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS instructions TEXT;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Время, когда сверка с Notion обнаружила, что пользователь удалил страницу задачи.
+-- Такие задачи не выгружаются в Notion повторно
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS notion_unlinked_at TIMESTAMP WITH TIME ZONE;
+
+COMMIT;
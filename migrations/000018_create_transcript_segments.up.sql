@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Создание таблицы фрагментов транскрипций для поиска внутри задачи (команда /find).
+-- Фрагмент - сегмент Whisper с меткой времени или предложение транскрипции без меток
+CREATE TABLE IF NOT EXISTS transcript_segments (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    start_seconds INTEGER,
+    text TEXT NOT NULL,
+    UNIQUE (job_id, position)
+);
+
+COMMIT;
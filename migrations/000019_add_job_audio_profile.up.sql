@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Профиль предобработки аудио: auto, speech, phone, lecture или music
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS audio_profile VARCHAR(16) NOT NULL DEFAULT 'auto';
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Создание таблицы результатов выгрузки задач в места синхронизации (Notion, хранилище Obsidian, вебхук).
+-- По ней повторная выгрузка пропускает места, куда задача уже выгружена
+CREATE TABLE IF NOT EXISTS job_sync_results (
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    target VARCHAR(32) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    external_id TEXT,
+    error_message TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (job_id, target)
+);
+
+COMMIT;
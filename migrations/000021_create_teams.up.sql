@@ -0,0 +1,25 @@
+BEGIN;
+
+-- Создание таблицы команд. Краткие содержания участников команды раз в сутки
+-- публикуются одним дайджестом в канал или группу команды
+CREATE TABLE IF NOT EXISTS teams (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invite_code VARCHAR(32) UNIQUE NOT NULL,
+    channel_id BIGINT,
+    digest_hour SMALLINT NOT NULL DEFAULT 9,
+    last_digest_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Создание таблицы участников команд; пользователь состоит не более чем в одной команде
+CREATE TABLE IF NOT EXISTS team_members (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    joined_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_team_id ON team_members(team_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS summary_cost;
+ALTER TABLE jobs DROP COLUMN IF EXISTS summary_model;
+
+COMMIT;
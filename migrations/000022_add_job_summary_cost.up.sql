@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Модель, которой в итоге выполнена суммаризация, и стоимость суммаризации в долларах США
+-- с учетом неудачных попыток на более дешевых моделях
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS summary_model VARCHAR(64);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS summary_cost NUMERIC(12, 6);
+
+COMMIT;
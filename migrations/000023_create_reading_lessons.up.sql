@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Создание таблицы уроков чтения вслух. Пока урок активен, голосовые сообщения пользователя
+-- сравниваются с эталонным текстом вместо суммаризации
+CREATE TABLE IF NOT EXISTS reading_lessons (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    reference_text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+COMMIT;
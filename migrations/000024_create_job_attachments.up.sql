@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Создание таблицы фотографий, приложенных к задачам (например, снимков доски со встречи).
+-- Изображение хранится в базе и отдается по токену, чтобы страница Notion могла его показать
+CREATE TABLE IF NOT EXISTS job_attachments (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    image BYTEA NOT NULL,
+    ocr_text TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_attachments_job_id ON job_attachments(job_id);
+
+COMMIT;
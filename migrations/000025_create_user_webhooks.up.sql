@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Создание таблицы вебхуков пользователей. На вебхук отправляются события задач пользователя
+-- (завершение, ошибка) с учетом фильтра событий и дополнительными заголовками
+CREATE TABLE IF NOT EXISTS user_webhooks (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    events TEXT[] NOT NULL DEFAULT '{}',
+    headers JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+COMMIT;
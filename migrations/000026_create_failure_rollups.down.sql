@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_job_stages_failed_finished_at;
+DROP TABLE IF EXISTS failure_rollups;
+
+COMMIT;
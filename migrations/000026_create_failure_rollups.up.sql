@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Создание таблицы дневных сводок ошибок обработки задач по этапу, внешнему сервису и категории.
+-- Таблица заполняется по расписанию из job_stages и пересчитывается за день целиком
+CREATE TABLE IF NOT EXISTS failure_rollups (
+    day DATE NOT NULL,
+    stage VARCHAR(64) NOT NULL,
+    provider VARCHAR(64) NOT NULL,
+    category VARCHAR(64) NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (day, stage, provider, category)
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_stages_failed_finished_at ON job_stages(finished_at) WHERE status = 'failed';
+
+COMMIT;
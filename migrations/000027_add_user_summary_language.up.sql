@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Язык кратких содержаний, выбранный пользователем (NULL - язык транскрипции)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS summary_language VARCHAR(16);
+
+COMMIT;
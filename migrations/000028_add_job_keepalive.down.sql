@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS keepalive_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS keepalive_message_id;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Сообщение «всё ещё обрабатываю» длинной задачи: ID сообщения для редактирования и время последнего обновления
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS keepalive_message_id INTEGER;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS keepalive_at TIMESTAMP WITH TIME ZONE;
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Создание журнала действий администраторов с данными пользователей (просмотр через /admin user).
+-- Записи только добавляются: журнал нужен, чтобы любой доступ поддержки к данным пользователя был виден
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    id SERIAL PRIMARY KEY,
+    admin_telegram_id BIGINT NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    target_telegram_id BIGINT,
+    details TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_created_at ON admin_audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_target ON admin_audit_log(target_telegram_id);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Создание таблицы глав видео, присланных ссылкой. Транскрипция делится по главам,
+-- и краткое содержание составляется отдельно для каждой главы
+CREATE TABLE IF NOT EXISTS video_chapters (
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    start_seconds INTEGER NOT NULL,
+    end_seconds INTEGER NOT NULL,
+    PRIMARY KEY (job_id, position)
+);
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Создание таблицы повесток встреч. Повестка без задачи ожидает следующей записи пользователя,
+-- после чего привязывается к ее задаче, и краткое содержание составляется по пунктам повестки
+CREATE TABLE IF NOT EXISTS meeting_agendas (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job_id INTEGER UNIQUE REFERENCES jobs(id) ON DELETE CASCADE,
+    agenda TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- У пользователя не больше одной ожидающей повестки
+CREATE UNIQUE INDEX IF NOT EXISTS idx_meeting_agendas_pending ON meeting_agendas(user_id) WHERE job_id IS NULL;
+
+COMMIT;
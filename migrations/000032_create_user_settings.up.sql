@@ -0,0 +1,13 @@
+BEGIN;
+
+-- Создание таблицы настроек обработки записей, которые пользователь выбирает командой /settings.
+-- Строка появляется при первом изменении настроек, до этого действуют значения по умолчанию
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    transcription_language VARCHAR(8) NOT NULL DEFAULT '',
+    summary_style VARCHAR(16) NOT NULL DEFAULT 'standard',
+    notion_auto_sync BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS cleanup_retried_at;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS failed_jobs_cleanup_at;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS failed_jobs_cleanup;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Ежедневный разбор задач с ошибками: включается пользователем в /settings, время последнего разбора
+-- хранится в настройках, а время перезапуска - в задаче, чтобы каждая задача перезапускалась не больше одного раза
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS failed_jobs_cleanup BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS failed_jobs_cleanup_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS cleanup_retried_at TIMESTAMP WITH TIME ZONE;
+
+COMMIT;
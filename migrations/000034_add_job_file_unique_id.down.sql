@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_jobs_user_file_unique_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS file_unique_id;
+
+COMMIT;
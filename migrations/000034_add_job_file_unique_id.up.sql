@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Постоянный идентификатор исходного файла в Telegram (file_unique_id): повторно присланный файл,
+-- например дважды пересланное голосовое сообщение, не обрабатывается заново
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS file_unique_id VARCHAR(255);
+CREATE INDEX IF NOT EXISTS idx_jobs_user_file_unique_id ON jobs(user_id, file_unique_id) WHERE file_unique_id IS NOT NULL;
+
+COMMIT;
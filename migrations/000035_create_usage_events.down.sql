@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE user_settings DROP COLUMN IF EXISTS usage_analytics_opt_out;
+DROP TABLE IF EXISTS usage_events;
+
+COMMIT;
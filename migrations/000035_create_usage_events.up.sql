@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Обезличенные события использования бота: вместо пользователя хранится хэш его Telegram ID с солью.
+-- Пользователь может отказаться от записи событий в /settings
+CREATE TABLE IF NOT EXISTS usage_events (
+    id SERIAL PRIMARY KEY,
+    user_key VARCHAR(64) NOT NULL,
+    event VARCHAR(64) NOT NULL,
+    detail VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_events_created_at ON usage_events(created_at);
+CREATE INDEX IF NOT EXISTS idx_usage_events_user_key ON usage_events(user_key);
+
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS usage_analytics_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Этап, на котором задача завершилась ошибкой: с него задачу перезапускает команда /retry
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS failed_stage VARCHAR(32);
+
+COMMIT;
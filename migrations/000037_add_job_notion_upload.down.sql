@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS notion_upload_cursor;
+ALTER TABLE jobs DROP COLUMN IF EXISTS notion_upload_page_id;
+
+COMMIT;
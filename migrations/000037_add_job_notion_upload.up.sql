@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Ход выгрузки содержимого задачи в Notion: страница и число уже добавленных на нее блоков.
+-- Прерванная выгрузка продолжается на той же странице с последней добавленной части
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS notion_upload_page_id VARCHAR(255);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS notion_upload_cursor INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Сообщение о ходе обработки задачи: бот обновляет его на каждом этапе вместо отправки новых сообщений
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS progress_message_id INTEGER;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE user_settings DROP COLUMN IF EXISTS interface_language;
+ALTER TABLE users DROP COLUMN IF EXISTS language_code;
+
+COMMIT;
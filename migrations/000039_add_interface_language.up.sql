@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Язык интерфейса бота: код языка из профиля Telegram, полученный с последним сообщением,
+-- и язык, выбранный пользователем в /settings (NULL - определяется по профилю Telegram)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language_code VARCHAR(16);
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS interface_language VARCHAR(16);
+
+COMMIT;
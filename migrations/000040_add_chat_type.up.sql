@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Тип чата пользователя: личный чат или группа с общими настройками. ID групповых чатов
+-- в Telegram отрицательные, поэтому группы, в которые бот уже был добавлен, определяются по ним
+ALTER TABLE users ADD COLUMN IF NOT EXISTS chat_type VARCHAR(16) NOT NULL DEFAULT 'private';
+UPDATE users SET chat_type = 'group' WHERE telegram_id < 0;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Число токенов запросов и ответов всех попыток суммаризации для статистики /stats
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS summary_tokens INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Сжатые тексты распаковывает только приложение, поэтому колонки удаляются, лишь если сжатых текстов нет
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM jobs WHERE transcription_gz IS NOT NULL OR summary_gz IS NOT NULL) THEN
+        RAISE EXCEPTION 'jobs contain compressed texts, decompress them before rolling back';
+    END IF;
+END $$;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS summary_gz;
+ALTER TABLE jobs DROP COLUMN IF EXISTS transcription_gz;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Сжатые gzip транскрипция и резюме задачи. У сжатого текста текстовая колонка пустая
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS transcription_gz BYTEA;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS summary_gz BYTEA;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE user_settings DROP COLUMN IF EXISTS sentiment_analysis;
+DROP TABLE IF EXISTS job_sentiments;
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Создание таблицы разборов настроения встреч: общее настроение, тон обсуждения
+-- и напряженные моменты с цитатами из транскрипции
+CREATE TABLE IF NOT EXISTS job_sentiments (
+    job_id INTEGER PRIMARY KEY REFERENCES jobs(id) ON DELETE CASCADE,
+    mood VARCHAR(16) NOT NULL,
+    tone TEXT NOT NULL DEFAULT '',
+    tension_points JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Разбор настроения пользователь включает в /settings
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS sentiment_analysis BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
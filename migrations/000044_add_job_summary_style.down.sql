@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE user_settings DROP COLUMN IF EXISTS summary_style_remembered;
+ALTER TABLE jobs DROP COLUMN IF EXISTS summary_style;
+
+COMMIT;
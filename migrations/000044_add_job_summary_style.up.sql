@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Стиль краткого содержания, выбранный кнопками под сообщением о приеме записи
+-- (NULL - стиль из настроек пользователя)
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS summary_style VARCHAR(16);
+
+-- Запомненный выбор стиля: бот больше не спрашивает стиль для каждой записи
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS summary_style_remembered BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
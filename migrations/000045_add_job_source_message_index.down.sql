@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_jobs_user_source_message_id;
+
+COMMIT;
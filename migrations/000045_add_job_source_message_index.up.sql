@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Индекс для поиска задачи по сообщению с записью, на которое пользователь ответил текстовой просьбой
+CREATE INDEX IF NOT EXISTS idx_jobs_user_source_message_id ON jobs(user_id, source_message_id) WHERE source_message_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Создание таблицы сравнений моделей суммаризации командой /compare: пользователь видит краткие содержания
+-- двух моделей без названий и голосует за лучшее, а итоги голосований помогают выбрать модель по умолчанию
+CREATE TABLE IF NOT EXISTS model_comparisons (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    model_a VARCHAR(255) NOT NULL,
+    model_b VARCHAR(255) NOT NULL,
+    vote VARCHAR(8),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    voted_at TIMESTAMP WITH TIME ZONE
+);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Отметка администратора бота (по списку TELEGRAM_ADMIN_IDS) и блокировка пользователя администратором.
+-- Сообщения заблокированных пользователей бот не обрабатывает
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS banned_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS ban_reason TEXT;
+
+COMMIT;
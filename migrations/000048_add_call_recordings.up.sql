@@ -0,0 +1,24 @@
+BEGIN;
+
+-- Создание таблицы звонков, записи которых присылают вебхуки VoIP провайдеров (Twilio, Zadarma).
+-- Данные о звонке и ссылка на запись приходят разными уведомлениями и собираются по ID звонка у провайдера;
+-- recording_received_at отмечает полученную запись, чтобы повторное уведомление не создало вторую задачу
+CREATE TABLE IF NOT EXISTS call_recordings (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(32) NOT NULL,
+    call_id VARCHAR(255) NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job_id INTEGER REFERENCES jobs(id) ON DELETE SET NULL,
+    direction VARCHAR(16),
+    caller VARCHAR(64),
+    callee VARCHAR(64),
+    started_at TIMESTAMP WITH TIME ZONE,
+    duration INTEGER NOT NULL DEFAULT 0,
+    recording_received_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (provider, call_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_call_recordings_job_id ON call_recordings(job_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE user_settings DROP COLUMN IF EXISTS notion_usage_cost;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS notion_audio_stats;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS notion_summary_only;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS notion_transcript;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Настройки содержимого страниц задач в Notion: полная транскрипция, только краткое содержание,
+-- сведения о записи и стоимость суммаризации
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS notion_transcript BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS notion_summary_only BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS notion_audio_stats BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS notion_usage_cost BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Создание таблицы правил выгрузки в Notion по темам записей (/route): страница задачи, которую
+-- классификатор отнес к теме правила, создается в базе правила вместо основной базы пользователя
+CREATE TABLE IF NOT EXISTS notion_routes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category VARCHAR(64) NOT NULL,
+    database_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, category)
+);
+
+COMMIT;
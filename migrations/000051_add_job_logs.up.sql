@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Создание индекса логов задач: записи лога приложения с атрибутом job_id для /logs и /admin_logs.
+-- Записи старше LOG_JOB_RETENTION удаляются по расписанию
+CREATE TABLE IF NOT EXISTS job_logs (
+    id BIGSERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    level VARCHAR(8) NOT NULL,
+    message TEXT NOT NULL,
+    attrs JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_logs_job_id ON job_logs(job_id, id);
+CREATE INDEX IF NOT EXISTS idx_job_logs_created_at ON job_logs(created_at);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Создание таблицы live-сессий (/live): транскрипция и текущее краткое содержание коротких голосовых
+-- сообщений до завершения сессии. У пользователя может быть только одна активная сессия
+CREATE TABLE IF NOT EXISTS live_sessions (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    message_id INTEGER NOT NULL DEFAULT 0,
+    transcript TEXT NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    segments INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_jobs_processed_audio_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS processed_audio_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS processed_audio_profile;
+ALTER TABLE jobs DROP COLUMN IF EXISTS processed_audio_path;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Подготовленный к распознаванию WAV задачи (после конвертации и фильтров профиля): повторная
+-- транскрибация берет его вместо полной цепочки FFmpeg. Файл удаляется по истечении срока хранения
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS processed_audio_path VARCHAR(255);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS processed_audio_profile VARCHAR(16);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS processed_audio_at TIMESTAMP WITH TIME ZONE;
+
+-- Создание индекса для поиска подготовленных WAV с истекшим сроком хранения
+CREATE INDEX IF NOT EXISTS idx_jobs_processed_audio_at ON jobs(processed_audio_at) WHERE processed_audio_at IS NOT NULL;
+
+COMMIT;
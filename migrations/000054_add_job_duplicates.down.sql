@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS duplicate_similarity;
+ALTER TABLE jobs DROP COLUMN IF EXISTS duplicate_of_job_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS transcript_minhash;
+
+COMMIT;
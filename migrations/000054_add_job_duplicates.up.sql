@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Сигнатура MinHash транскрипции задачи: по ней находится повторно присланная запись, например голосовое
+-- сообщение и та же запись, выгруженная файлом .m4a. duplicate_of_job_id - задача, повтором которой
+-- считается эта, duplicate_similarity - оценка доли совпадения транскрипций
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS transcript_minhash TEXT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS duplicate_of_job_id INTEGER REFERENCES jobs(id) ON DELETE SET NULL;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS duplicate_similarity REAL;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Фразы транскрипции с метками времени и номерами говорящих, если транскрипция разделена по говорящим:
+-- по ним страница в Notion и краткое содержание учитывают, кто что сказал
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS speaker_segments JSONB;
+
+COMMIT;
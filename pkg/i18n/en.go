@@ -0,0 +1,1115 @@
+package i18n
+
+// english - каталог сообщений на английском языке; сообщения, которых в нем нет, показываются на русском
+var english = map[string]string{
+	// Ошибки обработки обновлений
+	"error.message":         "Something went wrong while processing your message",
+	"error.document":        "Something went wrong while processing your document",
+	"error.unknown_command": "Unknown command",
+	"error.command":         "Something went wrong while processing the command",
+	"error.banned":          "⛔ Your access to the bot has been blocked by an administrator",
+	"error.private":         "🔒 This is a private bot: only users approved by its owner can use it. If you need access, please contact the bot owner",
+	"error.rate_limited":    "⏳ You have sent too many recordings recently. You can send the next one in %d min.",
+	"error.voice.get":       "Could not get the voice message",
+	"error.voice.download":  "Could not download the voice message",
+	"error.voice.save":      "Could not save the voice message",
+	"error.voice":           "Something went wrong while processing your voice message",
+	"error.audio.too_large": "The audio file is too large: the bot can download files up to %d MB",
+	"error.audio.get":       "Could not get the audio file",
+	"error.audio.download":  "Could not download the audio file",
+	"error.audio.save":      "Could not save the audio file",
+	"error.audio":           "Something went wrong while processing your audio file",
+	"error.video.too_large": "The video is too large: the bot can download files up to %d MB",
+	"error.video.get":       "Could not get the video",
+	"error.video.download":  "Could not download the video",
+	"error.video.save":      "Could not save the video",
+	"error.video":           "Something went wrong while processing your video",
+	"error.photo.too_large": "The photo is too large",
+	"error.photo.get":       "Could not get the photo",
+	"error.photo.download":  "Could not download the photo",
+	"error.photo.read":      "Could not read the photo",
+	"error.photo":           "Something went wrong while processing your photo",
+	"callback.unknown":      "Unknown button",
+	"callback.error":        "Something went wrong while handling the button",
+
+	// Команда /start
+	"start.welcome": "Hi, %s! 👋\n\n" +
+		"I transcribe audio into text and create notes in Notion. 🎙️📝\n\n" +
+		"Send me a voice message, an audio file or a long text and I will:\n" +
+		"1️⃣ Turn it into text\n" +
+		"2️⃣ Write a summary\n" +
+		"3️⃣ Save it to your Notion database (if connected)\n\n" +
+		"Commands:\n" +
+		"/help - show help\n" +
+		"/notion - connect Notion\n" +
+		"/jobs - list your jobs\n" +
+		"/share - share a job result by link\n" +
+		"/ask - ask a question about your notes\n" +
+		"/autodelete - delete results from the chat automatically",
+	"start.dashboard": "%s, %s! 👋\n\n" +
+		"📊 *Your week so far:*\n" +
+		"• Jobs this week: %d\n" +
+		"• Minutes transcribed: %.0f\n" +
+		"• Jobs in progress: %d\n" +
+		"• Jobs in total: %d\n" +
+		"• Notion: %s\n\n" +
+		"Send a voice message, an audio file or a text to continue. All commands — /help",
+	"start.notion.connected": "✅ connected",
+	"start.notion.missing":   "❌ not connected — /notion",
+	"greeting.morning":       "Good morning",
+	"greeting.day":           "Good afternoon",
+	"greeting.evening":       "Good evening",
+	"greeting.night":         "Good night",
+
+	// Команда /help
+	"help": "🤖 *How to use the bot* 🤖\n\n" +
+		"*What it does:*\n" +
+		"• Transcribes voice messages and audio files into text\n" +
+		"• Summarizes the transcription\n" +
+		"• Saves the results to Notion\n\n" +
+		"*Commands:*\n" +
+		"/start - get started\n" +
+		"/help - show this help\n" +
+		"/notion - connect Notion\n" +
+		"/jobs - list your jobs\n" +
+		"/share <id> - create a public link to a job result\n" +
+		"/unshare <id> - revoke the links to a job result\n" +
+		"/delete <id> [notion] - delete a job and its recording, with notion - its Notion page too\n" +
+		"/ask <question> - find the answer in your past notes\n" +
+		"/get <id> - get the transcription and summary of a job\n" +
+		"/transcript <id> - get the full transcription of a job (long ones come as a file)\n" +
+		"/summary <id> - get the summary of a job (long ones come as a file)\n" +
+		"/pdf <id> - get the result of a job as a PDF document\n" +
+		"/compare <id> - compare summaries of a job by two models and pick the better one\n" +
+		"/summarize - in reply to a text message: summarize it right away\n" +
+		"/status <id> - show the processing stages of a job\n" +
+		"/logs <id> - send the processing log of a job as a file for support\n" +
+		"/stats - usage stats: jobs, minutes transcribed and tokens used\n" +
+		"/retry <id> - restart a failed job from the failed stage\n" +
+		"/find <id> <phrase> - find a phrase in a job transcription\n" +
+		"/summarize_range <id> <from> <to> - summarize part of a recording, e.g. 10:00 20:00\n" +
+		"/trim <from> <to> - in reply to a recording: process only this part of it as a new job\n" +
+		"/autodelete <hours|off> - delete result messages from the chat after the given time\n" +
+		"/language <auto|language code> - summary language (the recording language by default)\n" +
+		"/settings - recording and interface language, summary style and automatic Notion upload\n" +
+		"/config export - export your settings to a file, /config import - import them from a file\n" +
+		"/team - a team with a daily digest of its members' summaries in a shared channel\n" +
+		"/live - live session: consecutive short voice messages in one transcript, /live stop - save it\n" +
+		"/lesson <text> - reading aloud lesson: the bot compares your voice messages with the text\n" +
+		"/agenda <items> - meeting agenda: the next recording is summarized item by item\n" +
+		"/webhook - send your job events to a webhook (n8n, Zapier)\n" +
+		"/route - save recordings on different topics to different Notion databases\n" +
+		"/delete_account - delete your account and all your data\n\n" +
+		"*How it works:*\n" +
+		"1. Send the bot a voice message, an audio file, a video note or a video\n" +
+		"2. Wait while it is processed (this may take a while)\n" +
+		"3. Get the transcription and the summary\n" +
+		"4. If Notion is connected, the results are saved there automatically\n\n" +
+		"*Video links:*\n" +
+		"Send a YouTube or Vimeo link — the bot transcribes the audio track and, if the video has chapters, summarizes it chapter by chapter. Podcast episode links from Apple Podcasts, SoundCloud, Castbox, Podbean, Mixcloud and Yandex Music are handled the same way\n\n" +
+		"*Photos for a job:*\n" +
+		"Send a photo of a whiteboard or a slide captioned «к задаче <id>» — the bot recognizes the text and adds the photo with the text to the job page in Notion\n\n" +
+		"*Text summaries:*\n" +
+		"Send a long text, or reply «суммаризируй» to a forwarded message — the bot summarizes it right away\n\n" +
+		"*Supported audio formats:*\n" +
+		"• Telegram voice messages\n" +
+		"• Audio files (.mp3, .wav, .ogg, .m4a)\n" +
+		"• Direct links to audio files\n\n" +
+		"*Notion setup:*\n" +
+		"Use /notion to connect Notion. You will need a Notion integration token.",
+
+	// Прием записей и текстов в обработку
+	"text.hint": "Send me a voice message, an audio file or a long text to summarize.\n\n" +
+		"To summarize a forwarded message, reply to it with «суммаризируй».",
+	"accepted.text": "📄 *Text accepted!* 📄\n\n" +
+		"You will be notified when the summary is ready.\n\n",
+	"accepted.voice": "🎙️ *Voice message accepted!* 🎙️\n\n" +
+		"I have started processing your voice message. This may take a while.\n\n" +
+		"You will be notified when the transcription and the summary are ready.\n\n",
+	"accepted.audio": "🎵 *Audio file accepted!* 🎵\n\n" +
+		"I have started processing your audio file. This may take a while.\n\n" +
+		"You will be notified when the transcription and the summary are ready.\n\n",
+	"accepted.video": "🎬 *Video accepted!* 🎬\n\n" +
+		"I have extracted the audio track and started processing it. This may take a while.\n\n" +
+		"You will be notified when the transcription and the summary are ready.\n\n",
+	"accepted.video_link": "🎬 *Video accepted!* 🎬\n\n" +
+		"You will be notified when the transcription and the summary are ready.\n\n",
+	"accepted.chapters": "The summary will follow the video chapters: %d.\n\n",
+	"accepted.podcast_link": "🎙 *Podcast episode accepted!* 🎙\n\n" +
+		"You will be notified when the transcription and the summary are ready.\n\n",
+	"accepted.podcast_chapters": "The summary will follow the episode chapters: %d.\n\n",
+	"accepted.job_id": "Job ID: `%d`\n\n" +
+		"You can check the job status with /jobs",
+	"accepted.caption": "\n\n📝 The caption will be taken into account in the summary.",
+	"accepted.agenda":  "\n\n📋 The summary will follow the meeting agenda items (%d).",
+	"accepted.audio_profile": "🎚 The audio processing profile is chosen automatically. " +
+		"If this is a phone call, a lecture or speech over music, pick a profile below before processing starts.",
+
+	// Сообщение о ходе обработки задачи
+	"progress.step.queued":      "Queued",
+	"progress.step.processing":  "Transcription",
+	"progress.step.transcribed": "Summarization",
+	"progress.step.summarized":  "Uploading results",
+	"progress.step.completed":   "Done",
+	"progress.completed":        "✅ Job %d is done",
+	"progress.failed":           "❌ Job %d failed at «%s»",
+	"progress.running":          "⏳ Job %d: %s",
+	"progress.retry":            "Restart from the failed step: /retry %d",
+
+	// Уведомление о завершении задачи
+	"completion.podcast":         "🎙 New podcast episode: %s\n\n",
+	"completion.video":           "✅ Video processed: %s\n\n",
+	"completion.podcast_link":    "✅ Podcast episode processed: %s\n\n",
+	"completion.done":            "✅ Job completed! ✅\n\n",
+	"completion.notion":          "📎 Saved to Notion: %s\n",
+	"completion.notion_hub":      "➡️ Continue in Notion: %s\n",
+	"completion.duplicate":       "♻️ Looks like the same recording as job %d (%d%% match). Merge them with the button below.\n",
+	"completion.full_result":     "Full result: /get %d",
+	"completion.transcription":   "📝 Transcription:\n",
+	"completion.summary":         "📊 Summary:\n",
+	"completion.truncated":       "... (full text in the file)",
+	"completion.transcript_file": "📝 Full transcription of job %d",
+	"completion.summary_file":    "📊 Summary of job %d",
+
+	// Настроение встречи в уведомлении о завершении задачи
+	"completion.sentiment": "%s Meeting mood: %s",
+	"completion.tension":   ", tension points: %d",
+	"sentiment.positive":   "positive",
+	"sentiment.neutral":    "neutral",
+	"sentiment.negative":   "tense",
+	"sentiment.mixed":      "mixed",
+
+	// Язык интерфейса в /settings
+	"settings.interface_language":      "🌍 Interface language: %s",
+	"settings.interface_language.auto": "🌍 Interface language: same as Telegram",
+	"settings.interface_language.any":  "🌍 Auto",
+
+	// Описания команд в меню Telegram
+	"command.start":           "Get started and see your weekly summary",
+	"command.help":            "Command help",
+	"command.notion":          "Connect Notion",
+	"command.jobs":            "List your jobs",
+	"command.settings":        "Processing and language settings",
+	"command.get":             "Get a job result",
+	"command.transcript":      "Full transcription of a job",
+	"command.summary":         "Summary of a job",
+	"command.pdf":             "Job result as PDF",
+	"command.compare":         "Compare summarization models",
+	"command.summarize":       "Summarize a text",
+	"command.summarize_range": "Summarize part of a recording",
+	"command.trim":            "Process part of a recording",
+	"command.status":          "Processing stages of a job",
+	"command.logs":            "Processing log of a job",
+	"command.stats":           "Usage statistics",
+	"command.retry":           "Restart a failed job",
+	"command.find":            "Find a phrase in a transcription",
+	"command.ask":             "Ask about your past notes",
+	"command.share":           "Share a job result by link",
+	"command.unshare":         "Revoke the links to a job result",
+	"command.delete":          "Delete a job",
+	"command.autodelete":      "Delete results from the chat automatically",
+	"command.language":        "Summary language",
+	"command.agenda":          "Agenda for the next meeting",
+	"command.live":            "Live session from voice messages",
+	"command.lesson":          "Reading aloud lesson",
+	"command.team":            "Team and daily digest",
+	"command.webhook":         "Job events webhook",
+	"command.route":           "Notion databases by topic",
+	"command.config":          "Export or import your settings",
+	"command.delete_account":  "Delete your account and all data",
+
+	// Команда /notion
+	"notion.instructions": "🔗 *Notion integration setup* 🔗\n\n" +
+		"To set up the Notion integration, follow these steps:\n\n" +
+		"1. Open [notion.so/my-integrations](https://www.notion.so/my-integrations)\n" +
+		"2. Create a new integration\n" +
+		"3. Copy the integration token\n" +
+		"4. Send the command `/notion your_token`\n\n" +
+		"Once the integration is set up, the bot will create a database in your Notion to store transcriptions.",
+	"notion.connected": "✅ *Notion integration is set up!* ✅\n\n" +
+		"From now on all transcriptions will be saved to your Notion database automatically.\n\n" +
+		"%s\n\n" +
+		"You can send me a voice message or an audio file to process.",
+	"notion.reconnect":      "To connect a different integration, send the command `/notion your_token`.",
+	"notion.links.title":    "🔗 *Notion is connected*",
+	"notion.links.database": "[Transcriptions database](%s)",
+	"notion.links.hub":      "[Continue in Notion: by tags and by weeks](%s)",
+
+	// Команда /jobs
+	"jobs.page": "Page %d",
+
+	// Команды /share и /unshare
+	"share.usage": "Usage: `/share <job id>`\n\nYou can find the job id with the /jobs command",
+	"share.created": "🔗 *A link to the job result has been created!* 🔗\n\n" +
+		"%s\n\n" +
+		"The link is valid until %s.\n" +
+		"To revoke it earlier, send the command `/unshare %d`",
+	"unshare.usage":   "Usage: `/unshare <job id>`",
+	"unshare.none":    "This job has no active links.",
+	"unshare.revoked": "🚫 Links revoked: %d. The job result is no longer available through them.",
+
+	// Команда /ask
+	"ask.usage":   "Usage: /ask <question>\n\nFor example: /ask what did we discuss about the budget last week?",
+	"ask.nothing": "🔍 Nothing relevant in your notes yet. Send a voice message or some text to grow your knowledge base.",
+
+	// Общие ответы команд с идентификатором задачи
+	"job.usage":                "Usage: `/%s <job id>`\n\nYou can find the job id with the /jobs command",
+	"job.not_found":            "Job not found.",
+	"job.not_ready":            "The job result is not ready yet.",
+	"job.transcript_not_ready": "The job transcription is not ready yet.",
+
+	// Команды /get, /transcript и /summary
+	"get.header":        "Job %d from %s",
+	"transcript.header": "📝 Transcription of job %d:",
+	"summary.header":    "📊 Summary of job %d:",
+
+	// Команда /find
+	"find.usage": "Usage: /find <job id> <phrase>\n\nYou can find the job id with the /jobs command",
+	"find.none":  "🔎 The phrase «%[2]s» was not found in job %[1]d.",
+	"find.found": "🔎 Job %d, «%s»: fragments found — %d",
+	"find.first": " (showing the first ones)",
+
+	// Команда /summarize_range
+	"summarize_range.usage": "Usage: /summarize_range <job id> <from> <to>\n\n" +
+		"Moments of the recording are given as mm:ss, h:mm:ss or a number of minutes, for example: /summarize_range 42 10:00 20:00\n" +
+		"The range is selected by the transcription timestamps, so the recording must be transcribed with segments (/whisper granularity segment).",
+	"summarize_range.order": "The end of the range must be later than its start.",
+	"summarize_range.no_timestamps": "The job transcription has no timestamps, so a range cannot be selected. " +
+		"Turn on timestamped segments with /whisper granularity segment and send the recording again.",
+	"summarize_range.empty":  "Nothing was said in job %d during %s. The last fragment of the recording starts at %s.",
+	"summarize_range.header": "📝 Job %d, %s:",
+
+	// Команда /status и этапы обработки задачи
+	"status.header":       "📋 Job %d (%s)",
+	"status.not_started":  "Processing of the job has not started yet.",
+	"status.summary":      "Summarization: %s, $%.4f, tokens: %d",
+	"status.sync":         "Result delivery:",
+	"status.error":        "Error: %s",
+	"status.retry":        "🔄 Restart from the «%s» stage: /retry %d",
+	"status.attempts":     ", attempts: %d",
+	"status.calls":        "External calls: %s",
+	"stage.transcription": "Transcription",
+	"stage.summarization": "Summarization",
+	"stage.notion_sync":   "Result delivery",
+	"sync.obsidian":       "Obsidian vault",
+	"sync.webhook":        "Webhook",
+
+	// Команда /retry
+	"retry.usage": "Usage: /retry <job id>\n\nA failed job is restarted from the stage where it failed. " +
+		"You can find failed jobs in /jobs.",
+	"retry.not_failed":     "Job %d did not fail, so there is no need to restart it. Progress: /status %d",
+	"retry.reading_lesson": "A reading attempt cannot be restarted: read the lesson text again.",
+	"retry.source_missing": "Job %d cannot be restarted: the original recording has already been deleted. Please send it again.",
+	"retry.started":        "🔄 Job %d has been restarted from the «%s» stage. The result will arrive in a separate message.",
+
+	// Команда /autodelete
+	"autodelete.usage": "Usage: `/autodelete <hours>` (from 1 to %d) or `/autodelete off`\n\n" +
+		"Messages with transcriptions will be deleted from the chat after the given time. " +
+		"Results stay available with the `/get <job id>` command.",
+	"autodelete.current_off": "Auto-delete is off.",
+	"autodelete.current":     "Messages with results are deleted after %d h.",
+	"autodelete.off":         "🗑 Auto-delete is off.",
+	"autodelete.on":          "🗑 New messages with results will be deleted from the chat after %d h. You can get them again with the `/get <job id>` command.",
+
+	// Команда /whisper
+	"whisper.usage": "Usage:\n" +
+		"/whisper temperature <0..1> - decoding temperature (0 - less made-up text on quiet recordings)\n" +
+		"/whisper prompt <text> - hint: names, terms, punctuation style\n" +
+		"/whisper granularity <text|segment> - plain text or timestamped segments\n" +
+		"/whisper reset - restore the default settings",
+	"whisper.temperature":     "The temperature must be a number from 0 to 1.",
+	"whisper.prompt_missing":  "Please provide the hint text.",
+	"whisper.prompt_too_long": "The hint is too long: %d characters at most.",
+	"whisper.granularity":     "Granularity can be text or segment.",
+	"whisper.saved":           "🎙 Recognition settings saved.",
+	"whisper.default":         "default",
+	"whisper.options":         "Recognition settings:\nTemperature: %s\nHint: %s\nGranularity: %s",
+
+	// Команда /language
+	"language.usage": "Usage: /language <%s|%s>\n" +
+		"auto - summary in the language of the recording (an English meeting gets an English summary)",
+	"language.unsupported":  "This language is not supported.",
+	"language.saved":        "🌐 Setting saved.",
+	"language.current":      "Summaries are written in %s.",
+	"language.current_auto": "Summaries are written in the language of the recording.",
+	"language.name.ru":      "Russian",
+	"language.name.uk":      "Ukrainian",
+	"language.name.en":      "English",
+	"language.name.de":      "German",
+	"language.name.fr":      "French",
+	"language.name.es":      "Spanish",
+	"language.name.it":      "Italian",
+	"language.name.pt":      "Portuguese",
+
+	// Команда /settings
+	"settings.saved": "Setting saved",
+
+	// Команда /config
+	"config.usage": "Usage:\n" +
+		"/config export - get a file with your settings: recognition, language, auto-delete, Notion, webhook and podcasts\n" +
+		"/config import - send the settings file with this caption or reply with this command to a message with the file\n\n" +
+		"The Notion token is not included in the file: on a new bot connect Notion with /notion first, then upload the file.",
+	"config.invalid":                "Settings were not loaded: %s.",
+	"config.imported":               "⚙️ Settings loaded.",
+	"config.podcasts":               "Podcasts: %d added, %d already present.",
+	"config.podcasts_failed":        "Could not subscribe to these feeds:",
+	"config.notion_missing":         "Notion is not connected on this bot, so the database from the file was not applied: connect Notion with /notion.",
+	"config.exported":               "⚙️ Your settings. Upload the file with /config import.",
+	"config.too_large":              "Could not load the settings file: it must be no larger than 64 KB.",
+	"config.problem.size":           "the file is too large",
+	"config.problem.json":           "the file is not a JSON settings file",
+	"config.problem.version":        "unsupported file version %d",
+	"config.problem.retention":      "auto-delete must be from 0 to %d h",
+	"config.problem.language":       "unsupported language %q",
+	"config.problem.temperature":    "recognition temperature must be from 0 to 1",
+	"config.problem.prompt":         "recognition hint is longer than %d characters",
+	"config.problem.granularity":    "unknown recognition granularity %q",
+	"config.problem.webhook_url":    "the webhook address must start with http:// or https://",
+	"config.problem.webhook_event":  "unknown webhook event %q",
+	"config.problem.webhook_count":  "the webhook has more than %d headers",
+	"config.problem.webhook_header": "invalid webhook header %q",
+	"config.problem.summary_style":  "unknown summary style %q",
+	"config.problem.podcast":        "invalid podcast address %q",
+
+	// Команда /podcast
+	"podcast.usage": "Usage:\n" +
+		"/podcast add <RSS link> - subscribe to a podcast\n" +
+		"/podcast list - show subscriptions\n" +
+		"/podcast remove <id> - unsubscribe\n\n" +
+		"New episodes are transcribed and summarized automatically, and the digest arrives in this chat.",
+	"podcast.exists":     "You are already subscribed to «%s» (id %d).",
+	"podcast.add_failed": "Could not subscribe: check that the link points to a podcast RSS feed.",
+	"podcast.added": "🎙 Subscribed to «%s» (id %d).\n\n" +
+		"The latest episode will be processed at the next feed check, and after that digests will arrive as new episodes come out.",
+	"podcast.notion":    "Episodes are saved to a separate Notion database: %s",
+	"podcast.not_found": "Subscription not found. Your subscriptions: /podcast list",
+	"podcast.removed":   "Subscription removed.",
+	"podcast.empty":     "You have no podcast subscriptions.",
+	"podcast.list":      "🎙 Your podcasts:",
+	"podcast.checked":   "Checked: %s",
+
+	// Команда /team
+	"team.usage": "Usage:\n" +
+		"/team create <name> - create a team\n" +
+		"/team join <code> - join a team with an invite code\n" +
+		"/team leave - leave the team (the owner disbands the team)\n" +
+		"/team channel [chat id] - publish the digest to this group or to the channel with the given id\n" +
+		"/team channel off - stop publishing the digest\n" +
+		"/team hour <0-23> - hour of the digest publication\n\n" +
+		"Once a day the bot publishes the summaries of all members to the team channel, signed by the author and with links to the results. " +
+		"The bot must be added to the channel as an administrator.",
+	"team.create_member": "You are already a member of the team «%s». To create a new one, leave it first: /team leave",
+	"team.created": "👥 Team «%s» created.\n\n" +
+		"Invite code: %s\nMembers join with the command /team join %s\n\n" +
+		"Add the bot to the team channel or group and run /team channel there — " +
+		"the digest will be published daily at %02d:00 (%s).",
+	"team.join_member":     "You are already a member of the team «%s». Leave it first: /team leave",
+	"team.join_not_found":  "No team with this code was found. Check the code with the team owner.",
+	"team.joined":          "👥 You have joined the team «%s». Your summaries will be included in the team daily digest.",
+	"team.none":            "You are not a member of a team.",
+	"team.disbanded":       "Team «%s» has been disbanded.",
+	"team.left":            "You have left the team «%s».",
+	"team.owner_only":      "Only the team owner can configure the digest.",
+	"team.hour":            "The digest will be published daily at %02d:00 (%s).",
+	"team.channel_missing": "Run /team channel in the team group or specify the channel id: /team channel -100…",
+	"team.channel_off":     "Digest publication is turned off.",
+	"team.channel_failed":  "Could not send a message to the channel: check the id and that the bot is a channel administrator.",
+	"team.channel_set":     "📰 The digest of the team «%s» will be published to this channel daily at %02d:00 (%s).",
+	"team.title":           "👥 Team «%s»",
+	"team.invite_code":     "Invite code: %s",
+	"team.digest":          "Digest: daily at %02d:00 (%s)",
+	"team.digest_off":      "Digest: no channel set",
+	"team.last_digest":     "Last digest: %s",
+	"team.members":         "Members (%d):",
+	"team.owner":           " (owner)",
+
+	// Команда /lesson
+	"lesson.usage": "Usage:\n" +
+		"/lesson <text> - start a reading lesson with this text (or reply with /lesson to a message with the text)\n" +
+		"/lesson off - end the lesson\n\n" +
+		"During the lesson, record a voice message reading the text aloud: the bot will compare the recording with the text and point out " +
+		"skipped, extra and misread words. Voice messages are not summarized during a lesson.",
+	"lesson.not_started": "No reading lesson has been started.",
+	"lesson.ended":       "The reading lesson is over. Voice messages are processed as usual again.",
+	"lesson.current":     "📖 Lesson text:\n\n%s\n\nRead it aloud in a voice message. End the lesson: /lesson off",
+	"lesson.too_long":    "The text is too long: %d words. A lesson needs an excerpt of at most %d words.",
+	"lesson.started": "📖 The reading lesson has started. Read the text aloud and send a voice message — the bot will send feedback on your reading.\n\n" +
+		"You can make as many attempts as you like. End the lesson: /lesson off",
+
+	// Команда /agenda
+	"agenda.usage": "Usage:\n" +
+		"/agenda <items> - set the agenda of the next meeting recording, one item per line\n" +
+		"/agenda - reply with this command to a message or a text file with the agenda, or send the file with the caption /agenda\n" +
+		"/agenda off - remove the agenda\n\n" +
+		"The next voice message or audio file will be summarized by the agenda items: " +
+		"for each item - what was discussed and what was decided; unresolved items are listed separately. " +
+		"The agenda waits for a recording for 12 hours.",
+	"agenda.none":      "No agenda is set.",
+	"agenda.cleared":   "The agenda has been removed. The next recording will be summarized as usual.",
+	"agenda.current":   "📋 Agenda of the next recording:\n\n%s\n\nRemove the agenda: /agenda off",
+	"agenda.not_utf8":  "The agenda was not saved: the file must be UTF-8 text.",
+	"agenda.too_long":  "The agenda is too long: %d characters, at most %d are allowed.",
+	"agenda.too_many":  "The agenda has too many items: %d, at most %d are allowed.",
+	"agenda.too_large": "Could not load the agenda file: it must be no larger than 16 KB.",
+	"agenda.saved": "📋 Agenda saved (items: %d):\n\n%s\n\n" +
+		"Now send the meeting recording as a voice message or an audio file - the summary will follow the agenda items. " +
+		"Remove the agenda: /agenda off",
+
+	// Команда /webhook
+	"webhook.usage": "Usage:\n" +
+		"/webhook set <url> - send job events to this address as a POST request with JSON\n" +
+		"/webhook events <all|completed|failed> - which events to send (comma-separated)\n" +
+		"/webhook header <name> <value> - add a request header, for example for authorization\n" +
+		"/webhook header <name> off - remove a header\n" +
+		"/webhook test - send a test event\n" +
+		"/webhook off - turn the webhook off\n\n" +
+		"Events: job.completed - the job has been processed, job.failed - a processing stage has failed.",
+	"webhook.none":            "No webhook is set up.",
+	"webhook.url":             "Specify a webhook address starting with http:// or https://",
+	"webhook.saved":           "🔗 Webhook saved.\n\n%s\n\nCheck delivery: /webhook test",
+	"webhook.deleted":         "Webhook turned off.",
+	"webhook.set_first":       "Set the webhook address first: /webhook set <url>",
+	"webhook.events_saved":    "Event filter saved.",
+	"webhook.header_reserved": "The %s header is set automatically and cannot be changed.",
+	"webhook.header_limit":    "At most %d headers can be set. Remove one you do not need: /webhook header <name> off",
+	"webhook.headers_saved":   "Headers saved.",
+	"webhook.test_failed": "❌ The test event was not delivered: the webhook is unreachable or returned an error. " +
+		"Local and internal network addresses are not supported.",
+	"webhook.test_delivered": "✅ The test event was delivered to %s",
+	"webhook.url_line":       "🔗 Webhook: %s",
+	"webhook.events_all":     "all",
+	"webhook.events":         "Events: %s",
+	"webhook.headers":        "Headers:",
+
+	// Команда /reconcile
+	"reconcile.done": "🔄 *Notion reconciliation finished*\n\n" +
+		"Jobs checked: %d\n" +
+		"Missing pages created: %d\n" +
+		"Links to deleted pages cleared: %d\n" +
+		"Errors: %d",
+
+	// Команда /admin
+	"admin.usage": "Usage:\n" +
+		"/admin failures [7d] - job processing errors by source, stage and category over a period (up to %d days)\n" +
+		"/admin usage [7d] - anonymized usage statistics: new user funnel, job sources and commands\n" +
+		"/admin user <telegram id> [reason] - recent jobs, errors and settings of a user (the lookup is recorded in the audit log)\n" +
+		"/admin audit - log of user data lookups\n" +
+		"/admin models - voting results of summarization model comparisons (/compare)\n" +
+		"/admin doctor - self-diagnostics: databases, FFmpeg, provider keys, queue worker and disk space",
+	"admin.user.not_found":           "User %d not found. The lookup has been recorded in the audit log.",
+	"admin.user.title":               "👤 User %d",
+	"admin.user.registered":          "Database ID: %d, registered %s",
+	"admin.user.admin":               "Bot administrator",
+	"admin.user.banned":              "⛔ Banned %s",
+	"admin.user.settings":            "Settings:",
+	"admin.user.notion_off":          "not connected",
+	"admin.user.notion_on":           "connected, database %s",
+	"admin.user.notion_token":        "token set, no database selected",
+	"admin.user.retention":           "Auto-delete: %s",
+	"admin.user.retention_off":       "off",
+	"admin.user.retention_on":        "after %d h",
+	"admin.user.webhook":             "Webhook: %s",
+	"admin.user.webhook_off":         "not set up",
+	"admin.user.webhook_host":        "address could not be parsed",
+	"admin.user.webhook_events":      "all events",
+	"admin.user.no_jobs":             "No jobs.",
+	"admin.user.jobs":                "Recent jobs (%d):",
+	"admin.user.last_failure":        "Last error: job %d from %s",
+	"admin.audit.empty":              "The administrator audit log is empty.",
+	"admin.audit.title":              "🛡 Administrator audit log:",
+	"admin.audit.target":             " — user %d",
+	"admin.failures.none":            "✅ No job processing errors in %d days.",
+	"admin.failures.title":           "📉 Job processing errors in %d days: %d",
+	"admin.failures.by_provider":     "By source",
+	"admin.failures.by_stage":        "By stage",
+	"admin.failures.by_category":     "By category",
+	"admin.failures.by_day":          "By day",
+	"failure_category.timeout":       "timeout",
+	"failure_category.rate_limit":    "rate limit",
+	"failure_category.auth":          "authorization",
+	"failure_category.network":       "network",
+	"failure_category.invalid_input": "invalid input",
+	"failure_category.budget":        "cost ceiling",
+	"failure_category.other":         "other",
+
+	// Повторно присланный файл
+	"duplicate_file.processing": "⏳ This file is already being processed: job %d. The result will arrive as soon as it is ready; status - /status %d",
+	"duplicate_file.done":       "♻️ This file has already been processed: job %d from %s",
+
+	// Попытка чтения во время урока
+	"accepted.reading": "📖 *Reading attempt accepted!* 📖\n\n" +
+		"I will compare the recording with the lesson text and send feedback: which words were skipped or read differently.\n\n" +
+		"Job ID: `%d`\n\n" +
+		"End the lesson: /lesson off",
+
+	// Видео и выпуски подкастов по ссылке
+	"media_link.video.fetch_failed":   "Could not get the video from the link. Check that the video is available without signing in.",
+	"media_link.video.too_long":       "The video is too long: videos up to %s can be processed.",
+	"media_link.video.downloading":    "⏬ Downloading the video «%s»...",
+	"media_link.podcast.fetch_failed": "Could not get the podcast episode from the link. Check that it is available without signing in.",
+	"media_link.podcast.too_long":     "The episode is too long: episodes up to %s can be processed.",
+	"media_link.podcast.downloading":  "⏬ Downloading the episode «%s»...",
+
+	// Фото к задаче
+	"photo.usage": "To attach a photo to a job, send it with the caption «к задаче <id>». " +
+		"You can find the job id with the /jobs command",
+	"photo.job_not_found": "Job %d not found. You can find the job id with the /jobs command",
+	"photo.added":         "🖼 The photo has been added to the Notion page of job %d.",
+	"photo.no_page": "🖼 The photo has been saved to job %d, but the job has no Notion page yet. " +
+		"Send the photo again once the job has finished processing.",
+	"photo.notion_failed": "🖼 The photo has been saved to job %d, but it could not be added to Notion. " +
+		"Try sending the photo again later.",
+	"photo.no_text": "No text was found on the photo.",
+	"photo.text":    "Recognized text:",
+
+	// Профили предобработки и наборы настроек обработки
+	"callback.job_not_found":  "Job not found",
+	"audio_profile.auto":      "automatic",
+	"audio_profile.speech":    "speech",
+	"audio_profile.phone":     "phone call",
+	"audio_profile.lecture":   "lecture",
+	"audio_profile.music":     "speech with music",
+	"audio_profile.invalid":   "Invalid processing profile",
+	"audio_profile.too_late":  "Processing has already started, you can choose the profile for the next recording",
+	"audio_profile.selected":  "The «%s» profile will be applied to job %d",
+	"pipeline.fast":           "Fast",
+	"pipeline.quality":        "Quality",
+	"pipeline.invalid":        "Invalid processing preset",
+	"pipeline.too_late":       "Processing has already started, you can choose the preset for the next recording",
+	"pipeline.selected":       "The «%s» preset will be applied to job %d",
+	"button.pipeline.fast":    "⚡ Fast",
+	"button.pipeline.quality": "💎 Quality",
+	"button.profile.auto":     "🤖 Auto",
+	"button.profile.speech":   "🗣 Speech",
+	"button.profile.phone":    "📞 Call",
+	"button.profile.lecture":  "🎓 Lecture",
+	"button.profile.music":    "🎵 With music",
+
+	// Нажатия inline-кнопок
+	"callback.message_unavailable": "Message unavailable",
+	"callback.unknown_action":      "Unknown action",
+	"callback.unknown_setting":     "Unknown setting",
+
+	// Самодиагностика: /admin doctor
+	"health.report.empty":       "No self-diagnostic checks are configured.",
+	"health.report.ok":          "🩺 Self-diagnostics: all %d checks passed",
+	"health.report.failed":      "🩺 Self-diagnostics: %d of %d checks failed",
+	"health.report.duration":    " in %d ms",
+	"health.report.latency":     "%d ms",
+	"health.local_whisper":      "Local Whisper",
+	"health.diarization":        "Speaker diarization",
+	"health.queue_worker":       "Queue worker",
+	"health.disk":               "Disk",
+	"health.ffmpeg":             "version %s, %d of %d slots busy",
+	"health.local_whisper.busy": "transcribing %d of %d recordings",
+	"health.notion.no_key":      "key is not set, check skipped",
+	"health.queue.busy":         "processing job %d for %s, %d in queue",
+	"health.queue.paused":       "paused for maintenance, %d in queue",
+	"health.queue.idle":         "idle, %d in queue",
+	"health.disk.free":          "%.1f of %.1f GB free in %s",
+
+	// Команда /settings
+	"settings.header":                      "⚙️ Recording processing settings",
+	"settings.transcription_language":      "🎙 Recording language: transcribed as %s",
+	"settings.transcription_language.auto": "🎙 Recording language: detected automatically",
+	"settings.summary_style":               "📝 Summary: %s",
+	"settings.summary_style.remembered":    ", the style is not asked for each recording",
+	"settings.summary_style.prompted":      ", the style can be chosen with the buttons under the message about an accepted recording",
+	"settings.notion_sync.off":             "📤 Notion export: off",
+	"settings.notion_sync.on":              "📤 Notion export: automatic",
+	"settings.notion_sync.not_connected":   "📤 Notion export: on, but Notion is not connected (/notion)",
+	"settings.notion_content":              "📄 Job page in Notion: %s",
+	"settings.notion_content.summary_only": "summary only",
+	"settings.notion_content.summary":      "summary",
+	"settings.notion_content.transcript":   "full transcript",
+	"settings.notion_content.audio_stats":  "recording details",
+	"settings.notion_content.usage_cost":   "processing cost",
+	"settings.failed_jobs.on":              "🧹 Failed jobs: retried once a day, with a report about those that cannot be fixed",
+	"settings.failed_jobs.off":             "🧹 Failed jobs: not retried",
+	"settings.analytics.off":               "📊 Anonymous usage statistics: not collected",
+	"settings.analytics.on":                "📊 Anonymous usage statistics: commands and jobs are counted without linking them to your account",
+	"settings.sentiment.on":                "🎭 Meeting mood: the summary includes the overall mood and tense moments with quotes",
+	"settings.sentiment.off":               "🎭 Meeting mood: not analyzed",
+	"settings.choose":                      "Choose the values with the buttons below.",
+	"settings.button.auto":                 "🌐 Auto",
+	"settings.button.style_prompt.on":      "❓ Ask the style for each recording",
+	"settings.button.style_prompt.off":     "📌 Don't ask the style",
+	"settings.button.notion_sync.off":      "📤 Don't export to Notion",
+	"settings.button.notion_sync.on":       "📤 Export to Notion",
+	"settings.button.notion_transcript":    "📄 Transcript",
+	"settings.button.notion_summary_only":  "🧾 Summary only",
+	"settings.button.notion_audio_stats":   "🔊 Recording details",
+	"settings.button.notion_usage_cost":    "💰 Cost",
+	"settings.button.failed_jobs.off":      "🧹 Don't retry failed jobs",
+	"settings.button.failed_jobs.on":       "🧹 Retry failed jobs",
+	"settings.button.analytics.on":         "📊 Take part in statistics",
+	"settings.button.analytics.off":        "📊 Don't take part in statistics",
+	"settings.button.sentiment.off":        "🎭 Don't analyze meeting mood",
+	"settings.button.sentiment.on":         "🎭 Analyze meeting mood",
+
+	// Стили краткого содержания
+	"summary_style.standard":        "standard",
+	"summary_style.brief":           "short",
+	"summary_style.detailed":        "detailed",
+	"summary_style.bullets":         "bullet points",
+	"summary_style.minutes":         "meeting minutes",
+	"summary_style.button.brief":    "⚡ TL;DR",
+	"summary_style.button.bullets":  "📋 Bullet points",
+	"summary_style.button.minutes":  "🗂 Meeting minutes",
+	"summary_style.button.detailed": "📖 Detailed",
+	"summary_style.button.remember": "📌 Remember the choice",
+	"summary_style.prompt":          "📝 Which summary should I write for job %d? If you don't choose, it will be «%s», as in /settings.",
+	"summary_style.invalid":         "Invalid style",
+	"summary_style.remembered": "📌 The «%s» style is remembered: it will be applied to all recordings without asking. " +
+		"You can change the style or be asked for each recording again in /settings.",
+	"summary_style.remembered_answer": "Choice remembered",
+	"summary_style.too_late":          "The summary of job %d is already being written, you can choose the style for the next recording.",
+	"summary_style.too_late_answer":   "Summarization has already started",
+	"summary_style.selected":          "📝 The summary of job %d will be «%s».",
+	"summary_style.selected_answer":   "Style selected",
+
+	// Кнопки под уведомлением о завершении задачи
+	"callback.invalid_button":           "Invalid button",
+	"job_action.button.transcript":      "📝 Full text",
+	"job_action.button.summary":         "📊 Summary only",
+	"job_action.button.translate":       "🌐 Translate",
+	"job_action.button.tags":            "🏷 Tags",
+	"job_action.button.listen":          "🔊 Listen",
+	"job_action.button.resummarize":     "🔄 Resummarize",
+	"job_action.button.notion":          "📤 Notion again",
+	"job_action.button.merge":           "🔗 Merge with job %d",
+	"job_action.button.distinct":        "↔️ These are different recordings",
+	"job_action.button.open_notion":     "📎 Open in Notion",
+	"job_action.button.delete":          "🗑 Delete",
+	"job_action.summary":                "I'll send the summary in a separate message",
+	"job_action.translate":              "Translating the summary, I'll send the translation in a separate message",
+	"job_action.tags":                   "Picking tags, I'll send them in a separate message",
+	"job_action.listen":                 "Voicing the summary, I'll send a voice message",
+	"job_action.notion":                 "Exporting the result to Notion again, I'll send a link to the page",
+	"job_action.no_transcript":          "The job has no transcript",
+	"job_action.transcript":             "I'll send the full transcript in a separate message",
+	"job_action.no_summary":             "The job has no summary",
+	"job_action.notion_not_connected":   "Notion is not connected. Connect it with the /notion command",
+	"job_action.processing":             "The job is still being processed",
+	"job_action.resummarize":            "Writing the summary again, I'll send it in a separate message. The Notion page will not change",
+	"job_action.delete_failed":          "Could not delete the message: Telegram allows deleting bot messages only within 48 hours",
+	"job_action.deleted":                "Message deleted. The result is available via /get %d",
+	"job_action.not_duplicate":          "The job is not marked as a duplicate",
+	"job_action.distinct":               "OK, jobs %d and %d will stay separate",
+	"job_action.merge_original_deleted": "Job %d has already been deleted, there is nothing to merge with",
+	"job_action.merge_notion_failed":    "Could not move the duplicate's page to the Notion trash, the jobs were not merged. Try again later",
+	"job_action.merged":                 "Duplicate deleted, the recording remains in job %d",
+
+	// Команда /live
+	"live.usage": "Usage:\n" +
+		"/live - start a live session: send short voice messages one after another, and the bot will keep " +
+		"the transcript and summary in a single message\n" +
+		"/live stop - end the session and save it as one job with a Notion page\n" +
+		"/live cancel - end the session without saving\n\n" +
+		"This mode is experimental.",
+	"live.not_started":      "No live session has been started.",
+	"live.cancelled":        "The live session has ended without saving.",
+	"live.already_started":  "A live session is already running: send voice messages. End it: /live stop",
+	"live.already_stopped":  "The live session has already ended. Start a new one: /live",
+	"live.too_long":         "The session transcript has reached its length limit. End the session with /live stop and start a new one.",
+	"live.no_speech":        "Could not recognize speech in the message.",
+	"live.stop_not_started": "No live session has been started. Start one: /live",
+	"live.stopped_empty":    "The live session has ended: it had no messages, there is nothing to save.",
+	"live.stopped": "⏹ The live session has ended: %d messages saved as job %d. When the final summary " +
+		"is ready, you will get a notification and the session page will appear in Notion.",
+	"live.header":     "🔴 Live session · messages: %d",
+	"live.footer":     "End and save: /live stop",
+	"live.empty":      "Send short voice messages one after another: the transcript and summary will appear here.",
+	"live.summary":    "📝 Summary:",
+	"live.transcript": "🗒 Transcript:",
+
+	// Команды /admin_ban, /admin_broadcast и /admin_stats
+	"admin_ban.usage": "Usage:\n" +
+		"/admin_ban <telegram id> [reason] - ban a user: the bot will stop processing their messages\n" +
+		"/admin_ban off <telegram id> - lift the ban",
+	"admin_ban.not_found":   "User %d not found.",
+	"admin_ban.unbanned":    "✅ The ban on user %d has been lifted.",
+	"admin_ban.admin":       "A bot administrator cannot be banned.",
+	"admin_ban.never_wrote": "User %d not found: they have not written to the bot yet.",
+	"admin_ban.banned":      "⛔ User %d has been banned. Lift the ban: /admin_ban off %d",
+	"admin_broadcast.usage": "Usage: /admin_broadcast <message text>\n\n" +
+		"All users except banned ones will get the message; groups don't get broadcasts.",
+	"admin_broadcast.too_long":      "The message is too long: no more than %d characters.",
+	"admin_broadcast.running":       "The previous broadcast has not finished yet.",
+	"admin_broadcast.started":       "📣 Broadcast started. I'll send the results when it finishes.",
+	"admin_broadcast.finished":      "📣 Broadcast finished in %s: delivered %d, not delivered %d.",
+	"admin_stats.header":            "📊 Bot statistics",
+	"admin_stats.users":             "Users:",
+	"admin_stats.users.total":       "• Total: %d (new in the last day: %d)",
+	"admin_stats.users.groups":      "• Groups: %d",
+	"admin_stats.users.banned":      "• Banned: %d",
+	"admin_stats.users.admins":      "• Administrators: %d of %d in the configuration",
+	"admin_stats.jobs":              "Jobs:",
+	"admin_stats.jobs.total":        "• Total: %d, being processed now: %d",
+	"admin_stats.jobs.since":        "• In the last day: %d (completed %d, failed %d) from %d users and groups",
+	"admin_stats.jobs.transcribed":  "• Recordings transcribed in the last day: %.0f min",
+	"admin_stats.jobs.summary_cost": "• Summarization cost in the last day: $%.2f",
+
+	// Команда /route
+	"route.usage": "Usage:\n" +
+		"/route - show the rules for exporting to Notion by recording topic\n" +
+		"/route add <topic> [database link] - save pages of recordings on this topic to a separate database; " +
+		"without a link the bot will create a new database\n" +
+		"/route del <number> - delete a rule\n" +
+		"/route test <job id> - show which database the job page would go to, without exporting anything",
+	"route.deleted":           "🗑 The rule for the «%s» topic has been deleted. The Notion database itself is not deleted.",
+	"route.category_too_long": "The topic is too long: no more than %d characters.",
+	"route.too_many":          "There can be no more than %d rules. Delete one you don't need with /route del <number>.",
+	"route.exists":            "A rule for the «%s» topic already exists. To change the database, delete it and add it again.",
+	"route.added":             "✅ Recordings on the «%s» topic will be saved to [this database](%s).",
+	"route.added_existing":    "The database must have the same columns as the main one: Name, Date and Status, and the bot integration must have access to it.",
+	"route.test_no_routes":    "There are no rules yet: pages of all jobs are saved to the main database. Add a rule with /route add <topic>",
+	"route.test_no_summary":   "The job has no summary yet.",
+	"route.test_default":      "🧭 Job %d doesn't match any topic: the page will go to the [main database](%s).",
+	"route.test_matched":      "🧭 Job %d belongs to the «%s» topic: the page will go to [this database](%s).",
+	"route.list_empty":        "🧭 There are no topic rules: pages of all jobs are saved to the main Notion database.",
+	"route.list":              "🧭 Rules for exporting to Notion by topic (other recordings go to the main database):",
+	"route.list_item":         "%d. %s → [database](%s)",
+
+	// Ответы на сообщение с записью
+	"audio_reply.instructions":  "✏️ Job %d is still being processed: the summary will take your request into account.",
+	"audio_reply.processing":    "⏳ The recording is still being processed: job %d. Reply to it again when the result arrives; status - /status %d",
+	"audio_reply.no_transcript": "Job %d has no transcript.",
+	"audio_reply.no_summary":    "Job %d has no summary.",
+	"audio_reply.accepted":      "👌 Job %d: working on your request, I'll send the result as a reply to your message.",
+
+	// Список задач /jobs
+	"jobs.filter.all":        "All",
+	"jobs.filter.active":     "In progress",
+	"jobs.filter.completed":  "Done",
+	"jobs.filter.failed":     "Failed",
+	"jobs.empty":             "You have no jobs yet. Send me a voice message or an audio file to process.",
+	"jobs.header":            "📋 *Your jobs* (%s, page %d) 📋",
+	"jobs.none":              "No jobs found.",
+	"jobs.item":              "`%d` %s *%s* (%s)\n   Created: %s\n",
+	"jobs.in_notion":         "📎 Saved to Notion",
+	"jobs.footer":            "Job result: /get <id>",
+	"jobs.text_title":        "Text",
+	"jobs.button.back":       "◀️ Back",
+	"jobs.button.next":       "Next ▶️",
+	"job_status.pending":     "Queued",
+	"job_status.processing":  "Processing",
+	"job_status.transcribed": "Transcribed",
+	"job_status.summarized":  "Summarized",
+	"job_status.completed":   "Completed",
+	"job_status.failed":      "Failed",
+	"job_status.unknown":     "Unknown",
+
+	// Команда /compare
+	"compare.vote.a":        "🅰️ A is better",
+	"compare.vote.b":        "🅱️ B is better",
+	"compare.vote.tie":      "🤝 The same",
+	"compare.disabled":      "Model comparison is not configured.",
+	"compare.variant.a":     "🅰️ Variant A for job %d:",
+	"compare.variant.b":     "🅱️ Variant B for job %d:",
+	"compare.question":      "⚖️ Which summary is better? I'll show the models after the vote.",
+	"compare.not_found":     "Comparison not found",
+	"compare.already_voted": "You have already voted",
+	"compare.voted":         "⚖️ Your choice: %s. Thank you!\n\n🅰️ Variant A - %s\n🅱️ Variant B - %s",
+	"compare.voted_answer":  "Vote counted",
+	"compare.report.empty":  "⚖️ There are no votes in model comparisons (/compare) yet.",
+	"compare.report.header": "⚖️ Summarization model comparisons",
+	"compare.report.model":  "%s: wins %d, ties %d, losses %d (%d%% wins out of %d comparisons)",
+
+	// Команда /delete_account
+	"delete_account.private_only": "You can delete your account only in a private chat with the bot.",
+	"delete_account.warning": "⚠️ *Account deletion* ⚠️\n\n" +
+		"Your settings, all jobs with transcripts and summaries, uploaded recordings, public links, " +
+		"podcast subscriptions, webhook, teams you own and usage statistics will be permanently deleted. " +
+		"Jobs that are still being processed will be stopped.\n\n" +
+		"Pages already saved to Notion will remain in your workspace.",
+	"delete_account.no_data":          "The bot has no saved data about you.",
+	"delete_account.button.delete":    "🗑 Delete everything",
+	"delete_account.button.archive":   "🗂 Send the list of Notion databases and delete",
+	"delete_account.button.cancel":    "Cancel",
+	"delete_account.cancelled":        "Account deletion cancelled.",
+	"delete_account.already_deleted":  "The account has already been deleted.",
+	"delete_account.deleted":          "✅ Your account and all your data have been deleted. To use the bot again, send /start.",
+	"delete_account.archive.header":   "Notion databases as of %s",
+	"delete_account.archive.database": "Transcripts database: %s",
+	"delete_account.archive.hub":      "«By tags and by weeks» page: %s",
+	"delete_account.archive.podcast":  "Database of the «%s» podcast: %s",
+	"delete_account.archive.job":      "Database of job %d: %s",
+	"delete_account.archive.caption":  "🗂 The list of your Notion databases. The bot does not delete the databases and pages in Notion.",
+
+	// Команды /logs и /admin_logs
+	"logs.usage": "Usage: /logs <job id>\n\n" +
+		"The bot will send a file with the job's processing history that you can attach to a support request. " +
+		"You can find the job id with the /jobs command",
+	"admin_logs.usage":     "Usage: /admin_logs <job id>",
+	"logs.disabled":        "Job log collection is disabled.",
+	"logs.empty":           "There are no logs for job %d: they are kept for %s.",
+	"logs.caption":         "🧾 Processing log of job %d",
+	"logs.file.job":        "Job %d",
+	"logs.file.status":     "Status: %s",
+	"logs.file.created":    "Created: %s",
+	"logs.file.error":      "Error: %s",
+	"logs.file.stages":     "Stages:",
+	"logs.file.journal":    "Log:",
+	"logs.file.no_lines":   "no entries",
+	"logs.retention.days":  "%d days",
+	"logs.retention.hours": "%d h",
+
+	// Бот в группе
+	"group.admin_only": "Only group administrators can change the group settings.",
+	"group.welcome": "👋 Hi everyone! I transcribe voice messages, audio and video sent to this group " +
+		"and reply with a transcript and a summary.\n\n" +
+		"The group has its own settings and a shared job list: /jobs, /settings. Group recordings can be saved to a shared Notion database — " +
+		"an administrator connects Notion in a private chat with the bot and runs /notion link here.\n\n" +
+		"For the bot to see all recordings, not just commands, make it a group administrator or disable its privacy mode in @BotFather.",
+	"group.notion.usage": "Usage in a group:\n" +
+		"/notion - show the connected Notion database\n" +
+		"/notion link - save group recordings to your Notion database (connect it in a private chat with the bot first)\n" +
+		"/notion unlink - stop saving group recordings to Notion",
+	"group.notion.not_connected": "Notion is not connected for the group.",
+	"group.notion.token": "⚠️ Don't send a Notion token to the group — delete this message and revoke the token if the group is not only yours. " +
+		"Connect Notion in a private chat with the bot, then run /notion link here.",
+	"group.notion.unlinked":      "Group recordings are no longer saved to Notion.",
+	"group.notion.connect_first": "First connect Notion in a private chat with the bot using /notion, then repeat /notion link here.",
+	"group.notion.linked":        "✅ *Group recordings will be saved to a shared Notion database* ✅",
+
+	// Режим обслуживания: /admin_maintenance
+	"maintenance.notice":         "🛠 The bot is under maintenance, new recordings are not accepted for now.",
+	"maintenance.reason":         "Reason: %s",
+	"maintenance.notice_queued":  "Recordings sent before the maintenance will be processed after it.",
+	"maintenance.resume.soon":    "Work will resume shortly.",
+	"maintenance.resume.minutes": "Work will resume in about %d min.",
+	"maintenance.resume.hours":   "Work will resume in about %d h %d min.",
+	"maintenance.usage": "Usage: /admin_maintenance on [duration] [reason] — enable maintenance " +
+		"(a duration like 30m or 2h), /admin_maintenance off — disable it, no arguments — show the state.",
+	"maintenance.status.off": "Maintenance mode is off: the bot accepts recordings, the queue is being processed.",
+	"maintenance.status.on":  "🛠 Maintenance mode was enabled at %s by administrator %d: new recordings are not accepted, the queue is paused.",
+	"maintenance.enabled": "🛠 Maintenance mode is on: new recordings are not accepted, workers will finish current jobs " +
+		"and pause the queue. Disable it: /admin_maintenance off",
+	"maintenance.already_off": "Maintenance mode is already off.",
+	"maintenance.disabled":    "✅ Maintenance mode is off: the bot accepts recordings again, the queue has resumed.",
+
+	// Предусловия команд
+	"guard.notion":       "🔗 This command needs the Notion integration. Connect it — it takes a couple of minutes.",
+	"guard.notion_setup": "Connect Notion",
+	"guard.admin":        "The command is available only to administrators.",
+	"guard.quota": "⏳ The daily limit is reached: %d of %d jobs have already been created today. " +
+		"You can send new recordings tomorrow.",
+
+	// Ежедневный разбор задач с ошибками
+	"failed_jobs.advice.too_long":       "the recording is too long — split it into parts and send it again",
+	"failed_jobs.advice.rate_limit":     "the transcription service was overloaded — send the recording again later",
+	"failed_jobs.advice.network":        "the transcription service was unavailable — send the recording again later",
+	"failed_jobs.advice.auth":           "an error on the bot's side — contact the administrator",
+	"failed_jobs.advice.invalid_input":  "the file could not be read — send the recording in a different format",
+	"failed_jobs.advice.source_missing": "the original file has already been deleted — send the recording again",
+	"failed_jobs.advice.transcript":     "the transcript has been saved — get it with /get %d",
+	"failed_jobs.advice.default":        "send the recording again; if the error repeats, contact the administrator",
+	"failed_jobs.header":                "🧹 Daily review of failed jobs",
+	"failed_jobs.retried":               "🔄 Jobs retried: %d — the results will arrive in separate messages.",
+	"failed_jobs.failed":                "❌ Could not be processed:",
+	"failed_jobs.more":                  "…and %d more",
+	"failed_jobs.untitled":              "untitled",
+	"failed_jobs.footer":                "You can turn off the review in /settings.",
+
+	// Отзыв об уроке чтения
+	"reading.header":            "📖 Reading check",
+	"reading.accuracy":          "Accuracy: %.0f%% (%d of %d words read correctly)",
+	"reading.perfect":           "Excellent! The text was read without mistakes.",
+	"reading.substituted":       "«%s» → heard «%s»",
+	"reading.substituted_title": "Read differently (check the pronunciation)",
+	"reading.omitted_title":     "Skipped",
+	"reading.inserted_title":    "Extra words",
+	"reading.not_recognized":    "(no speech recognized)",
+	"reading.recognized":        "Recognized: %s",
+	"reading.more":              "and %d more",
+
+	// Подключение Notion через OAuth
+	"notion_oauth.button":    "🔗 Sign in to Notion",
+	"notion_oauth.reconnect": "To connect another workspace, sign in to Notion with the button below.",
+	"notion_oauth.instructions": "🔗 *Notion integration setup* 🔗\n\n" +
+		"Press the button below, sign in to Notion and choose the pages the bot may access. " +
+		"The bot will then create a database for your transcripts in Notion.\n\n" +
+		"The link is valid for %d minutes.",
+	"notion_oauth.failed":    "❌ Could not connect Notion. Send /notion to try again.",
+	"notion_oauth.connected": "✅ Notion integration is set up!",
+	"notion_oauth.workspace": "Workspace: %s",
+	"notion_oauth.database":  "Transcripts database: %s",
+	"notion_oauth.next": "From now on all transcripts will be saved to Notion automatically. " +
+		"You can send me a voice message or an audio file to process.",
+
+	// Действия с результатом задачи
+	"follow_up.transcript":             "📝 Transcript of job %d:\n\n%s",
+	"follow_up.summary":                "📊 Summary of job %d:\n\n%s",
+	"follow_up.transcript_translation": "🌐 Transcript of job %d in %s:\n\n%s",
+	"follow_up.summary_translation":    "🌐 Summary of job %d in %s:\n\n%s",
+	"follow_up.instruction":            "💬 Job %d: «%s»\n\n%s",
+	"follow_up.notion_synced":          "📤 Job %d was exported to Notion again: %s",
+	"follow_up.no_tags":                "🏷 Could not suggest tags for job %d",
+	"follow_up.tags":                   "🏷 Tags of job %d: #%s",
+	"follow_up.nothing_to_listen":      "🔊 Job %d has no summary to read aloud",
+	"follow_up.listen":                 "🔊 Summary of job %d",
+	"follow_up.listen_truncated":       "The rest is in the text of the summary",
+
+	// Записи звонков
+	"call.failed":             "📞 Could not process the call recording «%s».",
+	"call.received":           "📞 Call recording «%s» received. Job %d, status - /status %d",
+	"call.title":              "Call",
+	"call.title.numbers":      "Call %s → %s",
+	"call.direction.incoming": "incoming",
+	"call.direction.outgoing": "outgoing",
+
+	// Отчет /admin usage
+	"usage.disabled":         "⚠️ Event recording is turned off (ANALYTICS_USAGE_EVENTS=false).",
+	"usage.empty":            "📊 No usage events in the last %d days.",
+	"usage.header":           "📊 Bot usage in the last %d days",
+	"usage.funnel":           "New user funnel:",
+	"usage.funnel.created":   "• created a job — %d%s",
+	"usage.funnel.completed": "• received a result — %d%s",
+	"usage.created":          "Created jobs by source",
+	"usage.completed":        "Completed jobs by type",
+	"usage.commands":         "Commands",
+	"usage.more":             "…and %d more",
+	"usage.count":            "• %s%s — %d (users: %d)",
+
+	// Выгрузка задачи в PDF
+	"pdf.disabled":       "PDF export is not configured.",
+	"pdf.caption":        "📄 Job %d as PDF",
+	"pdf.title":          "Job %d",
+	"pdf.field.job":      "Job",
+	"pdf.field.created":  "Created",
+	"pdf.field.duration": "Duration",
+	"pdf.field.model":    "Model",
+	"pdf.summary":        "Summary",
+	"pdf.transcription":  "Transcription",
+	"pdf.generated":      "Document created %s",
+
+	// Удаление задачи командой /delete
+	"delete.usage": "Usage: `/delete <job id>` - delete the job, its result and the recording file\n" +
+		"`/delete <job id> notion` - also move the job page to the Notion trash\n\n" +
+		"You can find the job id with the /jobs command",
+	"delete.in_progress": "The job is still being processed. Delete it after it finishes.",
+	"delete.notion_failed": "Could not move the job page to the Notion trash, the job was not deleted. Try again later " +
+		"or delete the job without Notion: `/delete %d`",
+	"delete.done_notion_archived": "🗑 Job %d deleted, the Notion page was moved to the trash.",
+	"delete.done_notion_kept":     "🗑 Job %d deleted. The Notion page remains, you can delete it in Notion.",
+	"delete.done":                 "🗑 Job %d deleted.",
+
+	// Статистика пользователя /stats
+	"stats.empty":     "You have not sent any recordings yet. Send a voice message, audio or video to get started.",
+	"stats.header":    "📊 *Your statistics* 📊",
+	"stats.total":     "Total jobs: %d",
+	"stats.completed": "✅ Completed: %d",
+	"stats.failed":    "❌ Failed: %d",
+	"stats.active":    "⏳ In progress: %d",
+	"stats.minutes":   "🎙 Recordings transcribed: %.0f min",
+	"stats.tokens":    "🧠 Tokens spent on summaries: %d ($%.4f)",
+
+	// Вырезание фрагмента записи командой /trim
+	"trim.usage": "Usage: reply to a voice message, audio or video with /trim <from> <to>\n\n" +
+		"Points in the recording are given as mm:ss, h:mm:ss or a number of minutes, for example: /trim 10:00 20:00\n" +
+		"The bot will cut this range out of the already uploaded recording and process it as a new job — no need to send the recording again.",
+	"trim.invalid_range":     "The end of the range must be later than its start.",
+	"trim.unknown_recording": "This recording was not processed by the bot. Send it, then reply to it with /trim.",
+	"trim.source_missing":    "The file of this recording has already been deleted, so a fragment cannot be cut from it. Send the recording again.",
+	"trim.out_of_range":      "The recording is shorter: it lasts %s.",
+	"trim.accepted":          "✂️ *Fragment %s–%s of job %d accepted for processing!* ✂️",
+
+	// Быстрая суммаризация текста командой /summarize
+	"quick_summary.header": "📊 Summary:",
+	"quick_summary.usage": "Usage: reply with /summarize to a message with text or write the text after the command: /summarize <text>\n\n" +
+		"The summary arrives right away and is not saved as a job; you can save it to Notion with the button under the reply.",
+	"quick_summary.button.notion":      "📤 Save to Notion",
+	"quick_summary.source_unavailable": "The original text is unavailable: send it and repeat /summarize",
+	"quick_summary.saving":             "Saving to Notion…",
+
+	// Сообщения о ходе длинных задач
+	"keepalive.elapsed":     "⏳ Still processing job %d: %d min elapsed",
+	"keepalive.remaining":   ", about %d min left",
+	"keepalive.almost_done": ", almost done",
+	"keepalive.footer":      "No need to send the file again — the result will arrive in this chat.",
+
+	// Загрузка записи по прямой ссылке
+	"audio_link.downloading": "⏬ Downloading the recording from the link...",
+	"audio_link.too_large":   "The file at the link is too large: recordings up to %d MB can be downloaded.",
+	"audio_link.not_audio":   "The link does not point to an audio file. Send a direct link to the recording file, for example .mp3 or .m4a.",
+	"audio_link.failed":      "Could not download the file from the link. Check that it is accessible without signing in.",
+
+	// Командные дайджесты
+	"team.channel_test":          "📰 The daily digest of team «%s» will be published here at %02d:00.",
+	"team.digest.header":         "📰 Digest of team «%s» for %s",
+	"team.digest.untitled":       "Note #%d",
+	"team.digest.unnamed_member": "Unnamed member",
+
+	// Источники ответа /ask
+	"ask.sources":        "Sources:",
+	"ask.source":         "job %d",
+	"ask.source_details": "job %d, %s, %s",
+
+	// Исправление подписи к записи
+	"caption.removed": "✏️ Caption removed: the summary will be written without extra instructions.",
+	"caption.updated": "✏️ Caption updated: the summary will follow the new instructions.",
+
+	"notion_oauth.page.connected.title": "Notion connected",
+	"notion_oauth.page.connected.text":  "The integration is set up and the links to the transcription database have been sent to Telegram. You can close this page.",
+	"notion_oauth.page.cancelled.title": "Connection cancelled",
+	"notion_oauth.page.cancelled.text":  "Access to Notion was not granted. To try again, send the /notion command to the bot.",
+	"notion_oauth.page.invalid.title":   "Invalid link",
+	"notion_oauth.page.invalid.text":    "Open the Notion sign-in link from the bot's message.",
+	"notion_oauth.page.expired.title":   "Link expired",
+	"notion_oauth.page.expired.text":    "The Notion sign-in link has already been used or has expired. Send the /notion command to the bot to get a new one.",
+	"notion_oauth.page.failed.title":    "Could not connect Notion",
+	"notion_oauth.page.failed.text":     "Please try again: send the /notion command to the bot.",
+
+	// Оповещение администраторов о перезапуске подсистемы
+	"supervisor.restart": "⚠️ Subsystem %s stopped with an error and will be restarted in %s: %v",
+
+	// Публичная страница с результатами задачи
+	"share.page.untitled":      "Transcription",
+	"share.page.created":       "Created: %s",
+	"share.page.summary":       "Summary",
+	"share.page.transcription": "Full transcription",
+
+	// Страницы Notion, которые создает бот
+	"notion.database.title":  "Audio transcriptions",
+	"notion.related_notes":   "Related notes",
+	"notion.hub.title":       "Transcriptions: navigation",
+	"notion.hub.tags.title":  "By tags",
+	"notion.hub.tags.text":   "Open the database and group the entries by the Tags property.",
+	"notion.hub.weeks.title": "By weeks",
+	"notion.hub.weeks.text":  "Open the database and group the entries by the Week property.",
+	"notion.hub.all.title":   "All transcriptions",
+	"notion.hub.all.text":    "The full list of entries with date, status and duration.",
+
+	// Разделы страницы задачи в Notion
+	"notion.page.title":             "Transcription from %s",
+	"notion.page.summary":           "Summary",
+	"notion.page.transcription":     "Full transcription",
+	"notion.page.audio":             "Recording",
+	"notion.page.audio.duration":    "Duration: %s",
+	"notion.page.audio.profile":     "Preprocessing: %s",
+	"notion.page.audio.words":       "Words in transcription: %d",
+	"notion.page.audio.pace":        "Speech rate: %.0f words per minute",
+	"notion.page.cost":              "Processing cost",
+	"notion.page.cost.model":        "Summarization model: %s",
+	"notion.page.cost.tokens":       "Tokens: %d",
+	"notion.page.cost.amount":       "Summarization cost: $%.4f",
+	"notion.page.sentiment":         "Meeting mood",
+	"notion.page.sentiment.mood":    "Overall mood: %s",
+	"notion.page.sentiment.tone":    "Tone: %s",
+	"notion.page.sentiment.tension": "Tense moments:",
+	"notion.page.call.direction":    "Direction: %s",
+	"notion.page.call.caller":       "Caller: %s",
+	"notion.page.call.callee":       "Callee: %s",
+	"notion.page.call.started":      "Started: %s",
+	"notion.page.call.duration":     "Duration: %s",
+	"notion.page.speakers":          "Speakers",
+
+	// Заметки в хранилище Obsidian
+	"obsidian.note.untitled":      "Transcription",
+	"obsidian.note.summary":       "Summary",
+	"obsidian.note.transcription": "Full transcription",
+
+	// Подписи говорящих в транскрипциях
+	"transcript.speaker": "Speaker %d",
+}
@@ -0,0 +1,92 @@
+// Package i18n переводит ответы бота на язык пользователя. Тексты хранятся в каталогах по языкам
+// и выбираются по идентификатору сообщения; если перевода нет, показывается русский текст
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Языки интерфейса бота
+const (
+	Russian = "ru" // Русский
+	English = "en" // Английский
+
+	// Default - язык интерфейса, если язык пользователя неизвестен
+	Default = Russian
+)
+
+// Languages - языки интерфейса в порядке показа пользователю
+var Languages = []string{Russian, English}
+
+// Names - названия языков интерфейса на самих этих языках
+var Names = map[string]string{
+	Russian: "Русский",
+	English: "English",
+}
+
+// catalogs - каталоги сообщений по языкам
+var catalogs = map[string]map[string]string{
+	Russian: russian,
+	English: english,
+}
+
+// Supported сообщает, есть ли каталог сообщений для языка
+func Supported(language string) bool {
+	_, ok := catalogs[language]
+	return ok
+}
+
+// Normalize возвращает язык интерфейса по коду языка Telegram (например, en-US): поддерживаемый язык
+// выбирается как есть, неизвестный язык пользователя - Default, любой другой - английский
+func Normalize(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return Default
+	}
+
+	language, _, _ := strings.Cut(strings.ReplaceAll(code, "_", "-"), "-")
+	if Supported(language) {
+		return language
+	}
+	return English
+}
+
+// Translate возвращает сообщение id на языке language, подставляя args по правилам fmt.Sprintf.
+// Если перевода нет, возвращается русский текст, а если нет и его - сам идентификатор
+func Translate(language, id string, args ...interface{}) string {
+	text, ok := catalogs[language][id]
+	if !ok {
+		text, ok = catalogs[Default][id]
+	}
+	if !ok {
+		return id
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// languageKey - ключ языка интерфейса в контексте запроса
+type languageKey struct{}
+
+// WithLanguage возвращает контекст, в котором ответы бота переводятся на язык language
+func WithLanguage(ctx context.Context, language string) context.Context {
+	return context.WithValue(ctx, languageKey{}, language)
+}
+
+// FromContext возвращает язык интерфейса из контекста запроса или Default, если язык не задан
+func FromContext(ctx context.Context) string {
+	if language, ok := ctx.Value(languageKey{}).(string); ok && language != "" {
+		return language
+	}
+	return Default
+}
+
+// T возвращает сообщение id на языке из контекста запроса
+func T(ctx context.Context, id string, args ...interface{}) string {
+	return Translate(FromContext(ctx), id, args...)
+}
@@ -0,0 +1,1115 @@
+package i18n
+
+// russian - каталог сообщений на русском языке. Это основной каталог: в нем должны быть все сообщения
+var russian = map[string]string{
+	// Ошибки обработки обновлений
+	"error.message":         "Произошла ошибка при обработке сообщения",
+	"error.document":        "Произошла ошибка при обработке документа",
+	"error.unknown_command": "Неизвестная команда",
+	"error.command":         "Произошла ошибка при обработке команды",
+	"error.banned":          "⛔ Доступ к боту заблокирован администратором",
+	"error.private":         "🔒 Это приватный бот: доступ к нему есть только у пользователей из списка владельца. Если вам нужен доступ, напишите владельцу бота",
+	"error.rate_limited":    "⏳ Вы прислали слишком много записей за последнее время. Следующую можно будет отправить через %d мин.",
+	"error.voice.get":       "Не удалось получить голосовое сообщение",
+	"error.voice.download":  "Не удалось загрузить голосовое сообщение",
+	"error.voice.save":      "Не удалось сохранить голосовое сообщение",
+	"error.voice":           "Произошла ошибка при обработке голосового сообщения",
+	"error.audio.too_large": "Аудио файл слишком большой: бот может загружать файлы до %d МБ",
+	"error.audio.get":       "Не удалось получить аудио файл",
+	"error.audio.download":  "Не удалось загрузить аудио файл",
+	"error.audio.save":      "Не удалось сохранить аудио файл",
+	"error.audio":           "Произошла ошибка при обработке аудио файла",
+	"error.video.too_large": "Видео слишком большое: бот может загружать файлы до %d МБ",
+	"error.video.get":       "Не удалось получить видео",
+	"error.video.download":  "Не удалось загрузить видео",
+	"error.video.save":      "Не удалось сохранить видео",
+	"error.video":           "Произошла ошибка при обработке видео",
+	"error.photo.too_large": "Фотография слишком большая",
+	"error.photo.get":       "Не удалось получить фотографию",
+	"error.photo.download":  "Не удалось загрузить фотографию",
+	"error.photo.read":      "Не удалось прочитать фотографию",
+	"error.photo":           "Произошла ошибка при обработке фотографии",
+	"callback.unknown":      "Неизвестная кнопка",
+	"callback.error":        "Произошла ошибка при обработке нажатия",
+
+	// Команда /start
+	"start.welcome": "Привет, %s! 👋\n\n" +
+		"Я бот для транскрибации аудио в текст и создания заметок в Notion. 🎙️📝\n\n" +
+		"Отправь мне голосовое сообщение, аудиофайл или длинный текст, и я:\n" +
+		"1️⃣ Преобразую его в текст\n" +
+		"2️⃣ Создам краткое содержание\n" +
+		"3️⃣ Сохраню в твою базу Notion (если настроено)\n\n" +
+		"Доступные команды:\n" +
+		"/help - показать справку\n" +
+		"/notion - настроить интеграцию с Notion\n" +
+		"/jobs - показать список задач\n" +
+		"/share - поделиться результатом задачи по ссылке\n" +
+		"/ask - задать вопрос по своим заметкам\n" +
+		"/autodelete - автоудаление результатов из чата",
+	"start.dashboard": "%s, %s! 👋\n\n" +
+		"📊 *Ваша сводка за неделю:*\n" +
+		"• Задач на этой неделе: %d\n" +
+		"• Минут транскрибировано: %.0f\n" +
+		"• Задач в обработке: %d\n" +
+		"• Всего задач: %d\n" +
+		"• Интеграция с Notion: %s\n\n" +
+		"Отправьте голосовое сообщение, аудиофайл или текст, чтобы продолжить. Список команд — /help",
+	"start.notion.connected": "✅ подключена",
+	"start.notion.missing":   "❌ не настроена — /notion",
+	"greeting.morning":       "Доброе утро",
+	"greeting.day":           "Добрый день",
+	"greeting.evening":       "Добрый вечер",
+	"greeting.night":         "Доброй ночи",
+
+	// Команда /help
+	"help": "🤖 *Справка по использованию бота* 🤖\n\n" +
+		"*Основные возможности:*\n" +
+		"• Транскрибация голосовых сообщений и аудиофайлов в текст\n" +
+		"• Создание краткого содержания транскрибации\n" +
+		"• Сохранение результатов в Notion\n\n" +
+		"*Команды:*\n" +
+		"/start - начать работу с ботом\n" +
+		"/help - показать эту справку\n" +
+		"/notion - настроить интеграцию с Notion\n" +
+		"/jobs - показать список ваших задач\n" +
+		"/share <id> - создать публичную ссылку на результат задачи\n" +
+		"/unshare <id> - отозвать ссылки на результат задачи\n" +
+		"/delete <id> [notion] - удалить задачу и файл записи, с notion - и страницу в Notion\n" +
+		"/ask <вопрос> - найти ответ в своих прошлых заметках\n" +
+		"/get <id> - получить транскрипцию и краткое содержание задачи\n" +
+		"/transcript <id> - получить полную транскрипцию задачи (длинная приходит файлом)\n" +
+		"/summary <id> - получить краткое содержание задачи (длинное приходит файлом)\n" +
+		"/pdf <id> - получить результат задачи документом PDF\n" +
+		"/compare <id> - сравнить краткие содержания задачи двумя моделями и выбрать лучшее\n" +
+		"/summarize - ответом на сообщение с текстом: сразу получить его краткое содержание\n" +
+		"/status <id> - показать ход обработки задачи по этапам\n" +
+		"/logs <id> - прислать файлом лог обработки задачи для поддержки\n" +
+		"/stats - статистика: задачи, минуты распознанных записей и потраченные токены\n" +
+		"/retry <id> - перезапустить задачу с ошибкой с упавшего этапа\n" +
+		"/find <id> <фраза> - найти фразу в транскрипции задачи\n" +
+		"/summarize_range <id> <от> <до> - краткое содержание промежутка записи, например 10:00 20:00\n" +
+		"/trim <от> <до> - ответом на запись: обработать только этот ее промежуток как новую задачу\n" +
+		"/autodelete <часы|off> - удалять сообщения с результатами из чата через указанное время\n" +
+		"/language <auto|код языка> - язык кратких содержаний (по умолчанию - язык записи)\n" +
+		"/settings - язык записей и интерфейса, стиль краткого содержания и автоматическая выгрузка в Notion\n" +
+		"/config export - выгрузить настройки в файл, /config import - загрузить их из файла\n" +
+		"/team - команда с ежедневным дайджестом кратких содержаний участников в общем канале\n" +
+		"/live - live-сессия: короткие голосовые сообщения подряд в одну транскрипцию, /live stop - сохранить\n" +
+		"/lesson <текст> - урок чтения вслух: бот сравнит ваши голосовые сообщения с текстом\n" +
+		"/agenda <пункты> - повестка встречи: следующая запись будет суммаризирована по ее пунктам\n" +
+		"/webhook - отправлять события ваших задач на вебхук (n8n, Zapier)\n" +
+		"/route - сохранять записи на разные темы в разные базы Notion\n" +
+		"/delete_account - удалить аккаунт и все ваши данные\n\n" +
+		"*Как использовать:*\n" +
+		"1. Отправьте боту голосовое сообщение, аудиофайл, кружок или видео\n" +
+		"2. Дождитесь обработки (это может занять некоторое время)\n" +
+		"3. Получите транскрипцию и краткое содержание\n" +
+		"4. Если настроена интеграция с Notion, результаты будут автоматически сохранены\n\n" +
+		"*Видео по ссылке:*\n" +
+		"Пришлите ссылку на видео YouTube или Vimeo — бот расшифрует звуковую дорожку, а если у видео есть главы, составит краткое содержание по главам. Также обрабатываются ссылки на выпуски подкастов Apple Podcasts, SoundCloud, Castbox, Podbean, Mixcloud и Яндекс Музыки\n\n" +
+		"*Фото к задаче:*\n" +
+		"Пришлите фото доски или слайда с подписью «к задаче <id>» — бот распознает текст и добавит фото с текстом на страницу задачи в Notion\n\n" +
+		"*Суммаризация текста:*\n" +
+		"Пришлите длинный текст или ответьте словом «суммаризируй» на пересланное сообщение — бот сразу создаст краткое содержание\n\n" +
+		"*Поддерживаемые форматы аудио:*\n" +
+		"• Голосовые сообщения Telegram\n" +
+		"• Аудиофайлы (.mp3, .wav, .ogg, .m4a)\n" +
+		"• Прямые ссылки на аудиофайлы\n\n" +
+		"*Настройка Notion:*\n" +
+		"Используйте команду /notion для настройки интеграции с Notion. Вам потребуется токен интеграции Notion.",
+
+	// Прием записей и текстов в обработку
+	"text.hint": "Отправьте мне голосовое сообщение, аудиофайл или длинный текст для суммаризации.\n\n" +
+		"Чтобы суммаризировать пересланное сообщение, ответьте на него словом «суммаризируй».",
+	"accepted.text": "📄 *Текст принят в обработку!* 📄\n\n" +
+		"Вы получите уведомление, когда краткое содержание будет готово.\n\n",
+	"accepted.voice": "🎙️ *Голосовое сообщение принято в обработку!* 🎙️\n\n" +
+		"Я начал обработку вашего голосового сообщения. Это может занять некоторое время.\n\n" +
+		"Вы получите уведомление, когда транскрипция и суммаризация будут готовы.\n\n",
+	"accepted.audio": "🎵 *Аудиофайл принят в обработку!* 🎵\n\n" +
+		"Я начал обработку вашего аудиофайла. Это может занять некоторое время.\n\n" +
+		"Вы получите уведомление, когда транскрипция и суммаризация будут готовы.\n\n",
+	"accepted.video": "🎬 *Видео принято в обработку!* 🎬\n\n" +
+		"Я извлек звуковую дорожку и начал ее обработку. Это может занять некоторое время.\n\n" +
+		"Вы получите уведомление, когда транскрипция и суммаризация будут готовы.\n\n",
+	"accepted.video_link": "🎬 *Видео принято в обработку!* 🎬\n\n" +
+		"Вы получите уведомление, когда транскрипция и суммаризация будут готовы.\n\n",
+	"accepted.chapters": "Краткое содержание будет составлено по главам видео: %d.\n\n",
+	"accepted.podcast_link": "🎙 *Выпуск подкаста принят в обработку!* 🎙\n\n" +
+		"Вы получите уведомление, когда транскрипция и суммаризация будут готовы.\n\n",
+	"accepted.podcast_chapters": "Краткое содержание будет составлено по главам выпуска: %d.\n\n",
+	"accepted.job_id": "Идентификатор задачи: `%d`\n\n" +
+		"Вы можете проверить статус задачи с помощью команды /jobs",
+	"accepted.caption": "\n\n📝 Подпись к сообщению будет учтена при составлении конспекта.",
+	"accepted.agenda":  "\n\n📋 Краткое содержание будет составлено по пунктам повестки встречи (%d).",
+	"accepted.audio_profile": "🎚 Профиль обработки звука подбирается автоматически. " +
+		"Если это запись звонка, лекции или речь на фоне музыки, выберите профиль кнопкой ниже до начала обработки.",
+
+	// Сообщение о ходе обработки задачи
+	"progress.step.queued":      "В очереди",
+	"progress.step.processing":  "Транскрибация",
+	"progress.step.transcribed": "Суммаризация",
+	"progress.step.summarized":  "Выгрузка результатов",
+	"progress.step.completed":   "Готово",
+	"progress.completed":        "✅ Задача %d обработана",
+	"progress.failed":           "❌ Задача %d: ошибка на шаге «%s»",
+	"progress.running":          "⏳ Задача %d: %s",
+	"progress.retry":            "Перезапустить с шага, где произошла ошибка: /retry %d",
+
+	// Уведомление о завершении задачи
+	"completion.podcast":         "🎙 Новый выпуск подкаста: %s\n\n",
+	"completion.video":           "✅ Видео обработано: %s\n\n",
+	"completion.podcast_link":    "✅ Выпуск подкаста обработан: %s\n\n",
+	"completion.done":            "✅ Задача успешно выполнена! ✅\n\n",
+	"completion.notion":          "📎 Сохранено в Notion: %s\n",
+	"completion.notion_hub":      "➡️ Продолжить в Notion: %s\n",
+	"completion.duplicate":       "♻️ Похоже, это та же запись, что и задача %d (совпадение %d%%). Объединить их можно кнопкой ниже.\n",
+	"completion.full_result":     "Полный результат: /get %d",
+	"completion.transcription":   "📝 Транскрипция:\n",
+	"completion.summary":         "📊 Краткое содержание:\n",
+	"completion.truncated":       "... (полностью - в файле)",
+	"completion.transcript_file": "📝 Полная транскрипция задачи %d",
+	"completion.summary_file":    "📊 Краткое содержание задачи %d",
+
+	// Настроение встречи в уведомлении о завершении задачи
+	"completion.sentiment": "%s Настроение встречи: %s",
+	"completion.tension":   ", напряженных моментов: %d",
+	"sentiment.positive":   "доброжелательное",
+	"sentiment.neutral":    "нейтральное",
+	"sentiment.negative":   "напряженное",
+	"sentiment.mixed":      "смешанное",
+
+	// Язык интерфейса в /settings
+	"settings.interface_language":      "🌍 Язык интерфейса: %s",
+	"settings.interface_language.auto": "🌍 Язык интерфейса: как в Telegram",
+	"settings.interface_language.any":  "🌍 Авто",
+
+	// Описания команд в меню Telegram
+	"command.start":           "Начать работу и сводка за неделю",
+	"command.help":            "Справка по командам",
+	"command.notion":          "Подключить Notion",
+	"command.jobs":            "Список задач",
+	"command.settings":        "Настройки обработки и язык",
+	"command.get":             "Результат задачи",
+	"command.transcript":      "Полная транскрипция задачи",
+	"command.summary":         "Краткое содержание задачи",
+	"command.pdf":             "Результат задачи в PDF",
+	"command.compare":         "Сравнить модели суммаризации",
+	"command.summarize":       "Суммаризировать текст",
+	"command.summarize_range": "Краткое содержание промежутка записи",
+	"command.trim":            "Обработать промежуток записи",
+	"command.status":          "Ход обработки задачи",
+	"command.logs":            "Лог обработки задачи",
+	"command.stats":           "Статистика использования",
+	"command.retry":           "Перезапустить задачу с ошибкой",
+	"command.find":            "Найти фразу в транскрипции",
+	"command.ask":             "Вопрос по прошлым заметкам",
+	"command.share":           "Поделиться результатом по ссылке",
+	"command.unshare":         "Отозвать ссылки на результат",
+	"command.delete":          "Удалить задачу",
+	"command.autodelete":      "Автоудаление результатов из чата",
+	"command.language":        "Язык кратких содержаний",
+	"command.agenda":          "Повестка следующей встречи",
+	"command.live":            "Live-сессия из голосовых сообщений",
+	"command.lesson":          "Урок чтения вслух",
+	"command.team":            "Команда и ежедневный дайджест",
+	"command.webhook":         "Вебхук событий задач",
+	"command.route":           "Базы Notion по темам записей",
+	"command.config":          "Выгрузить или загрузить настройки",
+	"command.delete_account":  "Удалить аккаунт и все данные",
+
+	// Команда /notion
+	"notion.instructions": "🔗 *Настройка интеграции с Notion* 🔗\n\n" +
+		"Для настройки интеграции с Notion, выполните следующие шаги:\n\n" +
+		"1. Перейдите на страницу [notion.so/my-integrations](https://www.notion.so/my-integrations)\n" +
+		"2. Создайте новую интеграцию\n" +
+		"3. Скопируйте токен интеграции\n" +
+		"4. Отправьте команду `/notion ваш_токен`\n\n" +
+		"После настройки интеграции, бот автоматически создаст базу данных в вашем Notion для хранения транскрипций.",
+	"notion.connected": "✅ *Интеграция с Notion успешно настроена!* ✅\n\n" +
+		"Теперь все транскрипции будут автоматически сохраняться в вашу базу данных Notion.\n\n" +
+		"%s\n\n" +
+		"Вы можете отправить мне голосовое сообщение или аудиофайл для обработки.",
+	"notion.reconnect":      "Чтобы подключить другую интеграцию, отправьте команду `/notion ваш_токен`.",
+	"notion.links.title":    "🔗 *Notion подключен*",
+	"notion.links.database": "[База транскрипций](%s)",
+	"notion.links.hub":      "[Продолжить в Notion: по тегам и по неделям](%s)",
+
+	// Команда /jobs
+	"jobs.page": "Страница %d",
+
+	// Команды /share и /unshare
+	"share.usage": "Использование: `/share <id задачи>`\n\nИдентификатор задачи можно узнать с помощью команды /jobs",
+	"share.created": "🔗 *Ссылка на результат задачи создана!* 🔗\n\n" +
+		"%s\n\n" +
+		"Ссылка действительна до %s.\n" +
+		"Чтобы отозвать её раньше, отправьте команду `/unshare %d`",
+	"unshare.usage":   "Использование: `/unshare <id задачи>`",
+	"unshare.none":    "У этой задачи нет активных ссылок.",
+	"unshare.revoked": "🚫 Отозвано ссылок: %d. Результат задачи больше недоступен по ним.",
+
+	// Команда /ask
+	"ask.usage":   "Использование: /ask <вопрос>\n\nНапример: /ask что обсуждали про бюджет на прошлой неделе?",
+	"ask.nothing": "🔍 В ваших заметках пока нет ничего подходящего. Отправьте голосовое сообщение или текст, чтобы пополнить базу знаний.",
+
+	// Общие ответы команд с идентификатором задачи
+	"job.usage":                "Использование: `/%s <id задачи>`\n\nИдентификатор задачи можно узнать с помощью команды /jobs",
+	"job.not_found":            "Задача не найдена.",
+	"job.not_ready":            "Результат задачи пока не готов.",
+	"job.transcript_not_ready": "Транскрипция задачи пока не готова.",
+
+	// Команды /get, /transcript и /summary
+	"get.header":        "Задача %d от %s",
+	"transcript.header": "📝 Транскрипция задачи %d:",
+	"summary.header":    "📊 Краткое содержание задачи %d:",
+
+	// Команда /find
+	"find.usage": "Использование: /find <id задачи> <фраза>\n\nИдентификатор задачи можно узнать с помощью команды /jobs",
+	"find.none":  "🔎 В задаче %d фраза «%s» не найдена.",
+	"find.found": "🔎 Задача %d, «%s»: найдено фрагментов — %d",
+	"find.first": " (показаны первые)",
+
+	// Команда /summarize_range
+	"summarize_range.usage": "Использование: /summarize_range <id задачи> <от> <до>\n\n" +
+		"Моменты записи указываются как мм:сс, ч:мм:сс или числом минут, например: /summarize_range 42 10:00 20:00\n" +
+		"Промежуток выбирается по меткам времени транскрипции, поэтому запись должна быть распознана с сегментами (/whisper granularity segment).",
+	"summarize_range.order": "Конец промежутка должен быть позже его начала.",
+	"summarize_range.no_timestamps": "В транскрипции задачи нет меток времени, поэтому выбрать промежуток нельзя. " +
+		"Включите сегменты с метками времени командой /whisper granularity segment и отправьте запись заново.",
+	"summarize_range.empty":  "В задаче %d за %s ничего не сказано. Последний фрагмент записи начинается в %s.",
+	"summarize_range.header": "📝 Задача %d, %s:",
+
+	// Команда /status и этапы обработки задачи
+	"status.header":       "📋 Задача %d (%s)",
+	"status.not_started":  "Обработка задачи еще не началась.",
+	"status.summary":      "Суммаризация: %s, $%.4f, токенов: %d",
+	"status.sync":         "Выгрузка результатов:",
+	"status.error":        "Ошибка: %s",
+	"status.retry":        "🔄 Перезапустить с этапа «%s»: /retry %d",
+	"status.attempts":     ", попыток: %d",
+	"status.calls":        "Внешние вызовы: %s",
+	"stage.transcription": "Транскрибация",
+	"stage.summarization": "Суммаризация",
+	"stage.notion_sync":   "Выгрузка результатов",
+	"sync.obsidian":       "Хранилище Obsidian",
+	"sync.webhook":        "Вебхук",
+
+	// Команда /retry
+	"retry.usage": "Использование: /retry <id задачи>\n\nЗадача с ошибкой перезапускается с этапа, на котором она упала. " +
+		"Задачи с ошибками можно найти в /jobs.",
+	"retry.not_failed":     "Задача %d не завершилась ошибкой, перезапускать ее не нужно. Ход обработки: /status %d",
+	"retry.reading_lesson": "Попытку чтения нельзя перезапустить: прочитайте текст урока заново.",
+	"retry.source_missing": "Задачу %d нельзя перезапустить: исходная запись уже удалена. Пришлите ее заново.",
+	"retry.started":        "🔄 Задача %d перезапущена с этапа «%s». Результат придет отдельным сообщением.",
+
+	// Команда /autodelete
+	"autodelete.usage": "Использование: `/autodelete <часы>` (от 1 до %d) или `/autodelete off`\n\n" +
+		"Сообщения с транскрипциями будут удаляться из чата через указанное время. " +
+		"Результаты останутся доступны по команде `/get <id задачи>`.",
+	"autodelete.current_off": "Автоудаление выключено.",
+	"autodelete.current":     "Сообщения с результатами удаляются через %d ч.",
+	"autodelete.off":         "🗑 Автоудаление выключено.",
+	"autodelete.on":          "🗑 Новые сообщения с результатами будут удаляться из чата через %d ч. Получить их снова можно командой `/get <id задачи>`.",
+
+	// Команда /whisper
+	"whisper.usage": "Использование:\n" +
+		"/whisper temperature <0..1> - температура декодирования (0 - меньше выдуманного текста на тихих записях)\n" +
+		"/whisper prompt <текст> - подсказка: имена, термины, стиль пунктуации\n" +
+		"/whisper granularity <text|segment> - сплошной текст или сегменты с метками времени\n" +
+		"/whisper reset - вернуть настройки по умолчанию",
+	"whisper.temperature":     "Температура должна быть числом от 0 до 1.",
+	"whisper.prompt_missing":  "Укажите текст подсказки.",
+	"whisper.prompt_too_long": "Подсказка слишком длинная: не больше %d символов.",
+	"whisper.granularity":     "Детализация может быть text или segment.",
+	"whisper.saved":           "🎙 Настройки распознавания сохранены.",
+	"whisper.default":         "по умолчанию",
+	"whisper.options":         "Настройки распознавания:\nТемпература: %s\nПодсказка: %s\nДетализация: %s",
+
+	// Команда /language
+	"language.usage": "Использование: /language <%s|%s>\n" +
+		"auto - краткое содержание на языке записи (английская встреча - английское резюме)",
+	"language.unsupported":  "Такой язык не поддерживается.",
+	"language.saved":        "🌐 Настройка сохранена.",
+	"language.current":      "Краткие содержания пишутся на %s языке.",
+	"language.current_auto": "Краткие содержания пишутся на языке записи.",
+	"language.name.ru":      "русском",
+	"language.name.uk":      "украинском",
+	"language.name.en":      "английском",
+	"language.name.de":      "немецком",
+	"language.name.fr":      "французском",
+	"language.name.es":      "испанском",
+	"language.name.it":      "итальянском",
+	"language.name.pt":      "португальском",
+
+	// Команда /settings
+	"settings.saved": "Настройка сохранена",
+
+	// Команда /config
+	"config.usage": "Использование:\n" +
+		"/config export - получить файл с настройками: распознавание, язык, автоудаление, Notion, вебхук и подкасты\n" +
+		"/config import - отправьте файл настроек с этой подписью или ответьте этой командой на сообщение с файлом\n\n" +
+		"Токен Notion в файл не попадает: на новом боте сначала подключите Notion командой /notion, затем загрузите файл.",
+	"config.invalid":                "Настройки не загружены: %s.",
+	"config.imported":               "⚙️ Настройки загружены.",
+	"config.podcasts":               "Подкасты: добавлено %d, уже были %d.",
+	"config.podcasts_failed":        "Не удалось подписаться на ленты:",
+	"config.notion_missing":         "Notion на этом боте не подключен, поэтому база из файла не применена: подключите Notion командой /notion.",
+	"config.exported":               "⚙️ Ваши настройки. Загрузите файл командой /config import.",
+	"config.too_large":              "Не удалось загрузить файл настроек: он должен быть не больше 64 КБ.",
+	"config.problem.size":           "файл слишком большой",
+	"config.problem.json":           "файл не является JSON-файлом настроек",
+	"config.problem.version":        "неподдерживаемая версия файла %d",
+	"config.problem.retention":      "автоудаление должно быть от 0 до %d ч",
+	"config.problem.language":       "неподдерживаемый язык %q",
+	"config.problem.temperature":    "температура распознавания должна быть от 0 до 1",
+	"config.problem.prompt":         "подсказка распознавания длиннее %d символов",
+	"config.problem.granularity":    "неизвестная детализация распознавания %q",
+	"config.problem.webhook_url":    "адрес вебхука должен начинаться с http:// или https://",
+	"config.problem.webhook_event":  "неизвестное событие вебхука %q",
+	"config.problem.webhook_count":  "у вебхука больше %d заголовков",
+	"config.problem.webhook_header": "недопустимый заголовок вебхука %q",
+	"config.problem.summary_style":  "неизвестный стиль краткого содержания %q",
+	"config.problem.podcast":        "недопустимый адрес подкаста %q",
+
+	// Команда /podcast
+	"podcast.usage": "Использование:\n" +
+		"/podcast add <ссылка на RSS> - подписаться на подкаст\n" +
+		"/podcast list - показать подписки\n" +
+		"/podcast remove <id> - отписаться\n\n" +
+		"Новые выпуски транскрибируются и суммаризируются автоматически, дайджест приходит в этот чат.",
+	"podcast.exists":     "Вы уже подписаны на «%s» (id %d).",
+	"podcast.add_failed": "Не удалось подписаться: проверьте, что ссылка ведет на RSS-ленту подкаста.",
+	"podcast.added": "🎙 Подписка на «%s» оформлена (id %d).\n\n" +
+		"Последний выпуск будет обработан при ближайшей проверке ленты, дальше дайджесты будут приходить по мере выхода новых выпусков.",
+	"podcast.notion":    "Выпуски сохраняются в отдельную базу Notion: %s",
+	"podcast.not_found": "Подписка не найдена. Список подписок: /podcast list",
+	"podcast.removed":   "Подписка удалена.",
+	"podcast.empty":     "У вас нет подписок на подкасты.",
+	"podcast.list":      "🎙 Ваши подкасты:",
+	"podcast.checked":   "Проверено: %s",
+
+	// Команда /team
+	"team.usage": "Использование:\n" +
+		"/team create <название> - создать команду\n" +
+		"/team join <код> - вступить в команду по коду приглашения\n" +
+		"/team leave - выйти из команды (владелец распускает команду)\n" +
+		"/team channel [id чата] - публиковать дайджест в эту группу или в канал с указанным id\n" +
+		"/team channel off - не публиковать дайджест\n" +
+		"/team hour <0-23> - час публикации дайджеста\n\n" +
+		"Раз в сутки бот публикует в канал команды краткие содержания всех участников с подписью автора и ссылками на результаты. " +
+		"Бот должен быть добавлен в канал администратором.",
+	"team.create_member": "Вы уже состоите в команде «%s». Чтобы создать новую, выйдите из нее: /team leave",
+	"team.created": "👥 Команда «%s» создана.\n\n" +
+		"Код приглашения: %s\nУчастники вступают командой /team join %s\n\n" +
+		"Добавьте бота в канал или группу команды и выполните там /team channel — " +
+		"дайджест будет публиковаться ежедневно в %02d:00 (%s).",
+	"team.join_member":     "Вы уже состоите в команде «%s». Сначала выйдите из нее: /team leave",
+	"team.join_not_found":  "Команда с таким кодом не найдена. Проверьте код у владельца команды.",
+	"team.joined":          "👥 Вы вступили в команду «%s». Ваши краткие содержания будут попадать в ежедневный дайджест команды.",
+	"team.none":            "Вы не состоите в команде.",
+	"team.disbanded":       "Команда «%s» распущена.",
+	"team.left":            "Вы вышли из команды «%s».",
+	"team.owner_only":      "Настраивать дайджест может только владелец команды.",
+	"team.hour":            "Дайджест будет публиковаться ежедневно в %02d:00 (%s).",
+	"team.channel_missing": "Выполните /team channel в группе команды или укажите id канала: /team channel -100…",
+	"team.channel_off":     "Публикация дайджеста отключена.",
+	"team.channel_failed":  "Не удалось отправить сообщение в канал: проверьте id и что бот добавлен в канал администратором.",
+	"team.channel_set":     "📰 Дайджест команды «%s» будет публиковаться в этот канал ежедневно в %02d:00 (%s).",
+	"team.title":           "👥 Команда «%s»",
+	"team.invite_code":     "Код приглашения: %s",
+	"team.digest":          "Дайджест: ежедневно в %02d:00 (%s)",
+	"team.digest_off":      "Дайджест: канал не задан",
+	"team.last_digest":     "Последний дайджест: %s",
+	"team.members":         "Участники (%d):",
+	"team.owner":           " (владелец)",
+
+	// Команда /lesson
+	"lesson.usage": "Использование:\n" +
+		"/lesson <текст> - начать урок чтения с этим текстом (или ответьте командой /lesson на сообщение с текстом)\n" +
+		"/lesson off - завершить урок\n\n" +
+		"Во время урока запишите голосовое сообщение, читая текст вслух: бот сравнит запись с текстом и укажет " +
+		"пропущенные, лишние и прочитанные иначе слова. Голосовые сообщения во время урока не суммаризируются.",
+	"lesson.not_started": "Урок чтения не начат.",
+	"lesson.ended":       "Урок чтения завершен. Голосовые сообщения снова обрабатываются как обычно.",
+	"lesson.current":     "📖 Текст урока:\n\n%s\n\nПрочитайте его вслух голосовым сообщением. Завершить урок: /lesson off",
+	"lesson.too_long":    "Текст слишком длинный: %d слов. Для урока подойдет отрывок не длиннее %d слов.",
+	"lesson.started": "📖 Урок чтения начат. Прочитайте текст вслух и отправьте голосовое сообщение — бот пришлет отзыв о прочитанном.\n\n" +
+		"Попыток может быть сколько угодно. Завершить урок: /lesson off",
+
+	// Команда /agenda
+	"agenda.usage": "Использование:\n" +
+		"/agenda <пункты> - задать повестку следующей записи встречи, по пункту в строке\n" +
+		"/agenda - ответьте этой командой на сообщение или текстовый файл с повесткой, либо отправьте файл с подписью /agenda\n" +
+		"/agenda off - убрать повестку\n\n" +
+		"Следующее голосовое сообщение или аудиофайл будет суммаризирован по пунктам повестки: " +
+		"для каждого пункта - что обсудили и что решили, нерешенные пункты отмечаются отдельно. " +
+		"Повестка ждет записи 12 часов.",
+	"agenda.none":      "Повестка не задана.",
+	"agenda.cleared":   "Повестка убрана. Следующая запись будет суммаризирована как обычно.",
+	"agenda.current":   "📋 Повестка следующей записи:\n\n%s\n\nУбрать повестку: /agenda off",
+	"agenda.not_utf8":  "Повестка не сохранена: файл должен быть текстовым в кодировке UTF-8.",
+	"agenda.too_long":  "Повестка слишком длинная: %d символов, допустимо не больше %d.",
+	"agenda.too_many":  "В повестке слишком много пунктов: %d, допустимо не больше %d.",
+	"agenda.too_large": "Не удалось загрузить файл повестки: он должен быть не больше 16 КБ.",
+	"agenda.saved": "📋 Повестка сохранена (пунктов: %d):\n\n%s\n\n" +
+		"Теперь отправьте запись встречи голосовым сообщением или аудиофайлом - краткое содержание будет составлено по пунктам повестки. " +
+		"Убрать повестку: /agenda off",
+
+	// Команда /webhook
+	"webhook.usage": "Использование:\n" +
+		"/webhook set <url> - отправлять события задач POST-запросом с JSON на этот адрес\n" +
+		"/webhook events <all|completed|failed> - какие события отправлять (можно через запятую)\n" +
+		"/webhook header <имя> <значение> - добавить заголовок запроса, например для авторизации\n" +
+		"/webhook header <имя> off - удалить заголовок\n" +
+		"/webhook test - отправить тестовое событие\n" +
+		"/webhook off - отключить вебхук\n\n" +
+		"События: job.completed - задача обработана, job.failed - этап обработки завершился ошибкой.",
+	"webhook.none":            "Вебхук не настроен.",
+	"webhook.url":             "Укажите адрес вебхука, начинающийся с http:// или https://",
+	"webhook.saved":           "🔗 Вебхук сохранен.\n\n%s\n\nПроверить доставку: /webhook test",
+	"webhook.deleted":         "Вебхук отключен.",
+	"webhook.set_first":       "Сначала задайте адрес вебхука: /webhook set <url>",
+	"webhook.events_saved":    "Фильтр событий сохранен.",
+	"webhook.header_reserved": "Заголовок %s формируется автоматически и не может быть изменен.",
+	"webhook.header_limit":    "Можно задать не больше %d заголовков. Удалите ненужный: /webhook header <имя> off",
+	"webhook.headers_saved":   "Заголовки сохранены.",
+	"webhook.test_failed": "❌ Тестовое событие не доставлено: вебхук недоступен или ответил ошибкой. " +
+		"Адреса локальной и внутренней сети не поддерживаются.",
+	"webhook.test_delivered": "✅ Тестовое событие доставлено на %s",
+	"webhook.url_line":       "🔗 Вебхук: %s",
+	"webhook.events_all":     "все",
+	"webhook.events":         "События: %s",
+	"webhook.headers":        "Заголовки:",
+
+	// Команда /reconcile
+	"reconcile.done": "🔄 *Сверка с Notion завершена*\n\n" +
+		"Проверено задач: %d\n" +
+		"Создано недостающих страниц: %d\n" +
+		"Очищено ссылок на удаленные страницы: %d\n" +
+		"Ошибок: %d",
+
+	// Команда /admin
+	"admin.usage": "Использование:\n" +
+		"/admin failures [7d] - ошибки обработки задач по источникам, этапам и категориям за период (до %d дней)\n" +
+		"/admin usage [7d] - обезличенная статистика использования: воронка новых пользователей, источники задач и команды\n" +
+		"/admin user <telegram id> [причина] - последние задачи, ошибки и настройки пользователя (просмотр записывается в журнал)\n" +
+		"/admin audit - журнал просмотров данных пользователей\n" +
+		"/admin models - итоги голосований в сравнениях моделей суммаризации (/compare)\n" +
+		"/admin doctor - самодиагностика: базы данных, FFmpeg, ключи провайдеров, воркер очереди и место на диске",
+	"admin.user.not_found":           "Пользователь %d не найден. Просмотр записан в журнал.",
+	"admin.user.title":               "👤 Пользователь %d",
+	"admin.user.registered":          "ID в базе: %d, зарегистрирован %s",
+	"admin.user.admin":               "Администратор бота",
+	"admin.user.banned":              "⛔ Заблокирован %s",
+	"admin.user.settings":            "Настройки:",
+	"admin.user.notion_off":          "не подключен",
+	"admin.user.notion_on":           "подключен, база %s",
+	"admin.user.notion_token":        "токен задан, база не выбрана",
+	"admin.user.retention":           "Автоудаление: %s",
+	"admin.user.retention_off":       "выключено",
+	"admin.user.retention_on":        "через %d ч",
+	"admin.user.webhook":             "Вебхук: %s",
+	"admin.user.webhook_off":         "не настроен",
+	"admin.user.webhook_host":        "адрес не разобран",
+	"admin.user.webhook_events":      "все события",
+	"admin.user.no_jobs":             "Задач нет.",
+	"admin.user.jobs":                "Последние задачи (%d):",
+	"admin.user.last_failure":        "Последняя ошибка: задача %d от %s",
+	"admin.audit.empty":              "Журнал действий администраторов пуст.",
+	"admin.audit.title":              "🛡 Журнал действий администраторов:",
+	"admin.audit.target":             " — пользователь %d",
+	"admin.failures.none":            "✅ За %d дн. ошибок обработки задач нет.",
+	"admin.failures.title":           "📉 Ошибки обработки задач за %d дн.: %d",
+	"admin.failures.by_provider":     "По источникам",
+	"admin.failures.by_stage":        "По этапам",
+	"admin.failures.by_category":     "По категориям",
+	"admin.failures.by_day":          "По дням",
+	"failure_category.timeout":       "таймаут",
+	"failure_category.rate_limit":    "лимит запросов",
+	"failure_category.auth":          "авторизация",
+	"failure_category.network":       "сеть",
+	"failure_category.invalid_input": "некорректные данные",
+	"failure_category.budget":        "потолок стоимости",
+	"failure_category.other":         "прочее",
+
+	// Повторно присланный файл
+	"duplicate_file.processing": "⏳ Этот файл уже обрабатывается: задача %d. Результат придет, как только будет готов; статус - /status %d",
+	"duplicate_file.done":       "♻️ Этот файл уже обработан: задача %d от %s",
+
+	// Попытка чтения во время урока
+	"accepted.reading": "📖 *Попытка чтения принята!* 📖\n\n" +
+		"Я сравню запись с текстом урока и пришлю отзыв: какие слова пропущены или прочитаны иначе.\n\n" +
+		"Идентификатор задачи: `%d`\n\n" +
+		"Завершить урок: /lesson off",
+
+	// Видео и выпуски подкастов по ссылке
+	"media_link.video.fetch_failed":   "Не удалось получить видео по ссылке. Проверьте, что видео доступно без входа в аккаунт.",
+	"media_link.video.too_long":       "Видео слишком длинное: можно обработать видео длительностью до %s.",
+	"media_link.video.downloading":    "⏬ Загружаю видео «%s»...",
+	"media_link.podcast.fetch_failed": "Не удалось получить выпуск подкаста по ссылке. Проверьте, что он доступен без входа в аккаунт.",
+	"media_link.podcast.too_long":     "Выпуск слишком длинный: можно обработать выпуск длительностью до %s.",
+	"media_link.podcast.downloading":  "⏬ Загружаю выпуск «%s»...",
+
+	// Фото к задаче
+	"photo.usage": "Чтобы приложить фото к задаче, отправьте его с подписью «к задаче <id>». " +
+		"Идентификатор задачи можно узнать командой /jobs",
+	"photo.job_not_found": "Задача %d не найдена. Идентификатор задачи можно узнать командой /jobs",
+	"photo.added":         "🖼 Фото добавлено на страницу задачи %d в Notion.",
+	"photo.no_page": "🖼 Фото сохранено в задаче %d, но страницы в Notion у нее пока нет. " +
+		"Отправьте фото еще раз, когда обработка задачи завершится.",
+	"photo.notion_failed": "🖼 Фото сохранено в задаче %d, но добавить его в Notion не удалось. " +
+		"Попробуйте отправить фото еще раз позже.",
+	"photo.no_text": "Текст на фото не найден.",
+	"photo.text":    "Распознанный текст:",
+
+	// Профили предобработки и наборы настроек обработки
+	"callback.job_not_found":  "Задача не найдена",
+	"audio_profile.auto":      "автоматический",
+	"audio_profile.speech":    "речь",
+	"audio_profile.phone":     "телефонный звонок",
+	"audio_profile.lecture":   "лекция",
+	"audio_profile.music":     "речь с музыкой",
+	"audio_profile.invalid":   "Некорректный профиль обработки",
+	"audio_profile.too_late":  "Обработка уже началась, профиль можно выбрать для следующей записи",
+	"audio_profile.selected":  "Профиль «%s» будет применен к задаче %d",
+	"pipeline.fast":           "Быстро",
+	"pipeline.quality":        "Качественно",
+	"pipeline.invalid":        "Некорректный набор настроек",
+	"pipeline.too_late":       "Обработка уже началась, набор можно выбрать для следующей записи",
+	"pipeline.selected":       "Набор «%s» будет применен к задаче %d",
+	"button.pipeline.fast":    "⚡ Быстро",
+	"button.pipeline.quality": "💎 Качественно",
+	"button.profile.auto":     "🤖 Авто",
+	"button.profile.speech":   "🗣 Речь",
+	"button.profile.phone":    "📞 Звонок",
+	"button.profile.lecture":  "🎓 Лекция",
+	"button.profile.music":    "🎵 С музыкой",
+
+	// Нажатия inline-кнопок
+	"callback.message_unavailable": "Сообщение недоступно",
+	"callback.unknown_action":      "Неизвестное действие",
+	"callback.unknown_setting":     "Неизвестная настройка",
+
+	// Самодиагностика: /admin doctor
+	"health.report.empty":       "Проверки самодиагностики не настроены.",
+	"health.report.ok":          "🩺 Самодиагностика: все %d проверок пройдены",
+	"health.report.failed":      "🩺 Самодиагностика: ошибок %d из %d проверок",
+	"health.report.duration":    " за %d мс",
+	"health.report.latency":     "%d мс",
+	"health.local_whisper":      "Локальный Whisper",
+	"health.diarization":        "Разделение по говорящим",
+	"health.queue_worker":       "Воркер очереди",
+	"health.disk":               "Диск",
+	"health.ffmpeg":             "версия %s, занято %d из %d мест",
+	"health.local_whisper.busy": "распознается %d из %d записей",
+	"health.notion.no_key":      "ключ не задан, проверка пропущена",
+	"health.queue.busy":         "обрабатывает задачу %d уже %s, в очереди %d",
+	"health.queue.paused":       "приостановлен на время обслуживания, в очереди %d",
+	"health.queue.idle":         "свободен, в очереди %d",
+	"health.disk.free":          "свободно %.1f из %.1f ГБ в %s",
+
+	// Команда /settings
+	"settings.header":                      "⚙️ Настройки обработки записей",
+	"settings.transcription_language":      "🎙 Язык записей: распознаются на %s языке",
+	"settings.transcription_language.auto": "🎙 Язык записей: определяется автоматически",
+	"settings.summary_style":               "📝 Краткое содержание: %s",
+	"settings.summary_style.remembered":    ", стиль не спрашивается для каждой записи",
+	"settings.summary_style.prompted":      ", стиль можно выбрать кнопками под сообщением о приеме записи",
+	"settings.notion_sync.off":             "📤 Выгрузка в Notion: выключена",
+	"settings.notion_sync.on":              "📤 Выгрузка в Notion: автоматически",
+	"settings.notion_sync.not_connected":   "📤 Выгрузка в Notion: включена, но Notion не подключен (/notion)",
+	"settings.notion_content":              "📄 Страница задачи в Notion: %s",
+	"settings.notion_content.summary_only": "только краткое содержание",
+	"settings.notion_content.summary":      "краткое содержание",
+	"settings.notion_content.transcript":   "полная транскрипция",
+	"settings.notion_content.audio_stats":  "сведения о записи",
+	"settings.notion_content.usage_cost":   "стоимость обработки",
+	"settings.failed_jobs.on":              "🧹 Задачи с ошибками: раз в день перезапускаются, о неисправимых приходит сводка",
+	"settings.failed_jobs.off":             "🧹 Задачи с ошибками: не перезапускаются",
+	"settings.analytics.off":               "📊 Обезличенная статистика использования: не собирается",
+	"settings.analytics.on":                "📊 Обезличенная статистика использования: учитываются команды и задачи без привязки к вашему аккаунту",
+	"settings.sentiment.on":                "🎭 Настроение встреч: в краткое содержание добавляется общее настроение и напряженные моменты с цитатами",
+	"settings.sentiment.off":               "🎭 Настроение встреч: не разбирается",
+	"settings.choose":                      "Выберите значения кнопками ниже.",
+	"settings.button.auto":                 "🌐 Авто",
+	"settings.button.style_prompt.on":      "❓ Спрашивать стиль для каждой записи",
+	"settings.button.style_prompt.off":     "📌 Не спрашивать стиль",
+	"settings.button.notion_sync.off":      "📤 Не выгружать в Notion",
+	"settings.button.notion_sync.on":       "📤 Выгружать в Notion",
+	"settings.button.notion_transcript":    "📄 Транскрипция",
+	"settings.button.notion_summary_only":  "🧾 Только саммари",
+	"settings.button.notion_audio_stats":   "🔊 О записи",
+	"settings.button.notion_usage_cost":    "💰 Стоимость",
+	"settings.button.failed_jobs.off":      "🧹 Не перезапускать задачи с ошибками",
+	"settings.button.failed_jobs.on":       "🧹 Перезапускать задачи с ошибками",
+	"settings.button.analytics.on":         "📊 Участвовать в статистике",
+	"settings.button.analytics.off":        "📊 Не участвовать в статистике",
+	"settings.button.sentiment.off":        "🎭 Не разбирать настроение встреч",
+	"settings.button.sentiment.on":         "🎭 Разбирать настроение встреч",
+
+	// Стили краткого содержания
+	"summary_style.standard":        "обычное",
+	"summary_style.brief":           "короткое",
+	"summary_style.detailed":        "подробное",
+	"summary_style.bullets":         "тезисы",
+	"summary_style.minutes":         "протокол встречи",
+	"summary_style.button.brief":    "⚡ TL;DR",
+	"summary_style.button.bullets":  "📋 Тезисы",
+	"summary_style.button.minutes":  "🗂 Протокол встречи",
+	"summary_style.button.detailed": "📖 Подробно",
+	"summary_style.button.remember": "📌 Запомнить выбор",
+	"summary_style.prompt":          "📝 Какое краткое содержание составить для задачи %d? Если не выбрать, оно будет «%s», как в /settings.",
+	"summary_style.invalid":         "Некорректный стиль",
+	"summary_style.remembered": "📌 Стиль «%s» запомнен: он будет применяться ко всем записям без вопроса. " +
+		"Изменить стиль или снова спрашивать его для каждой записи можно в /settings.",
+	"summary_style.remembered_answer": "Выбор запомнен",
+	"summary_style.too_late":          "Краткое содержание задачи %d уже составляется, стиль можно выбрать для следующей записи.",
+	"summary_style.too_late_answer":   "Суммаризация уже началась",
+	"summary_style.selected":          "📝 Краткое содержание задачи %d будет «%s».",
+	"summary_style.selected_answer":   "Стиль выбран",
+
+	// Кнопки под уведомлением о завершении задачи
+	"callback.invalid_button":           "Некорректная кнопка",
+	"job_action.button.transcript":      "📝 Полный текст",
+	"job_action.button.summary":         "📊 Только саммари",
+	"job_action.button.translate":       "🌐 Перевести",
+	"job_action.button.tags":            "🏷 Теги",
+	"job_action.button.listen":          "🔊 Прослушать",
+	"job_action.button.resummarize":     "🔄 Пересуммаризировать",
+	"job_action.button.notion":          "📤 В Notion повторно",
+	"job_action.button.merge":           "🔗 Объединить с задачей %d",
+	"job_action.button.distinct":        "↔️ Это разные записи",
+	"job_action.button.open_notion":     "📎 Открыть в Notion",
+	"job_action.button.delete":          "🗑 Удалить",
+	"job_action.summary":                "Пришлю краткое содержание отдельным сообщением",
+	"job_action.translate":              "Перевожу краткое содержание, пришлю перевод отдельным сообщением",
+	"job_action.tags":                   "Подбираю теги, пришлю их отдельным сообщением",
+	"job_action.listen":                 "Озвучиваю краткое содержание, пришлю голосовое сообщение",
+	"job_action.notion":                 "Выгружаю результат в Notion заново, пришлю ссылку на страницу",
+	"job_action.no_transcript":          "Транскрипции у задачи нет",
+	"job_action.transcript":             "Пришлю полную транскрипцию отдельным сообщением",
+	"job_action.no_summary":             "Краткого содержания у задачи нет",
+	"job_action.notion_not_connected":   "Notion не подключен. Подключите его командой /notion",
+	"job_action.processing":             "Задача еще обрабатывается",
+	"job_action.resummarize":            "Составляю краткое содержание заново, пришлю его отдельным сообщением. Страница в Notion не изменится",
+	"job_action.delete_failed":          "Не удалось удалить сообщение: Telegram позволяет удалять сообщения бота только в течение 48 часов",
+	"job_action.deleted":                "Сообщение удалено. Результат доступен по /get %d",
+	"job_action.not_duplicate":          "Задача не помечена как повтор",
+	"job_action.distinct":               "Хорошо, задачи %d и %d останутся отдельными",
+	"job_action.merge_original_deleted": "Задача %d уже удалена, объединять не с чем",
+	"job_action.merge_notion_failed":    "Не удалось переместить страницу повтора в корзину Notion, задачи не объединены. Попробуйте позже",
+	"job_action.merged":                 "Повтор удален, запись осталась в задаче %d",
+
+	// Команда /live
+	"live.usage": "Использование:\n" +
+		"/live - начать live-сессию: присылайте короткие голосовые сообщения подряд, а бот будет вести " +
+		"транскрипцию и краткое содержание в одном сообщении\n" +
+		"/live stop - завершить сессию и сохранить ее одной задачей со страницей в Notion\n" +
+		"/live cancel - завершить сессию без сохранения\n\n" +
+		"Режим экспериментальный.",
+	"live.not_started":      "Live-сессия не начата.",
+	"live.cancelled":        "Live-сессия завершена без сохранения.",
+	"live.already_started":  "Live-сессия уже идет: присылайте голосовые сообщения. Завершить: /live stop",
+	"live.already_stopped":  "Live-сессия уже завершена. Начать новую: /live",
+	"live.too_long":         "Транскрипция сессии достигла предела длины. Завершите сессию командой /live stop и начните новую.",
+	"live.no_speech":        "В сообщении не удалось разобрать речь.",
+	"live.stop_not_started": "Live-сессия не начата. Начать: /live",
+	"live.stopped_empty":    "Live-сессия завершена: в ней не было сообщений, сохранять нечего.",
+	"live.stopped": "⏹ Live-сессия завершена: %d сообщ. сохранены задачей %d. Когда итоговое краткое содержание " +
+		"будет готово, придет уведомление, а в Notion появится страница сессии.",
+	"live.header":     "🔴 Live-сессия · сообщений: %d",
+	"live.footer":     "Завершить и сохранить: /live stop",
+	"live.empty":      "Присылайте короткие голосовые сообщения подряд: транскрипция и краткое содержание появятся здесь.",
+	"live.summary":    "📝 Кратко:",
+	"live.transcript": "🗒 Транскрипция:",
+
+	// Команды /admin_ban, /admin_broadcast и /admin_stats
+	"admin_ban.usage": "Использование:\n" +
+		"/admin_ban <telegram id> [причина] - заблокировать пользователя: бот перестанет обрабатывать его сообщения\n" +
+		"/admin_ban off <telegram id> - снять блокировку",
+	"admin_ban.not_found":   "Пользователь %d не найден.",
+	"admin_ban.unbanned":    "✅ Блокировка пользователя %d снята.",
+	"admin_ban.admin":       "Администратора бота заблокировать нельзя.",
+	"admin_ban.never_wrote": "Пользователь %d не найден: он еще не писал боту.",
+	"admin_ban.banned":      "⛔ Пользователь %d заблокирован. Снять блокировку: /admin_ban off %d",
+	"admin_broadcast.usage": "Использование: /admin_broadcast <текст сообщения>\n\n" +
+		"Сообщение получат все пользователи, кроме заблокированных; группы рассылку не получают.",
+	"admin_broadcast.too_long":      "Сообщение слишком длинное: не больше %d символов.",
+	"admin_broadcast.running":       "Предыдущая рассылка еще не закончилась.",
+	"admin_broadcast.started":       "📣 Рассылка начата. Итоги пришлю, когда она закончится.",
+	"admin_broadcast.finished":      "📣 Рассылка завершена за %s: доставлено %d, не доставлено %d.",
+	"admin_stats.header":            "📊 Статистика бота",
+	"admin_stats.users":             "Пользователи:",
+	"admin_stats.users.total":       "• Всего: %d (новых за сутки: %d)",
+	"admin_stats.users.groups":      "• Группы: %d",
+	"admin_stats.users.banned":      "• Заблокированы: %d",
+	"admin_stats.users.admins":      "• Администраторы: %d из %d в конфигурации",
+	"admin_stats.jobs":              "Задачи:",
+	"admin_stats.jobs.total":        "• Всего: %d, в обработке сейчас: %d",
+	"admin_stats.jobs.since":        "• За сутки: %d (готово %d, с ошибкой %d) от %d пользователей и групп",
+	"admin_stats.jobs.transcribed":  "• Распознано записей за сутки: %.0f мин",
+	"admin_stats.jobs.summary_cost": "• Стоимость суммаризации за сутки: $%.2f",
+
+	// Команда /route
+	"route.usage": "Использование:\n" +
+		"/route - показать правила выгрузки в Notion по темам записей\n" +
+		"/route add <тема> [ссылка на базу] - страницы записей на эту тему сохранять в отдельную базу; " +
+		"без ссылки бот создаст новую базу\n" +
+		"/route del <номер> - удалить правило\n" +
+		"/route test <id задачи> - показать, в какую базу попадет страница задачи, ничего не выгружая",
+	"route.deleted":           "🗑 Правило для темы «%s» удалено. Сама база в Notion не удаляется.",
+	"route.category_too_long": "Тема слишком длинная: не больше %d символов.",
+	"route.too_many":          "Правил может быть не больше %d. Удалите ненужное командой /route del <номер>.",
+	"route.exists":            "Правило для темы «%s» уже есть. Чтобы сменить базу, удалите его и добавьте заново.",
+	"route.added":             "✅ Записи на тему «%s» будут сохраняться в [базу](%s).",
+	"route.added_existing":    "У базы должны быть те же колонки, что у основной: Name, Date и Status, а интеграция бота должна иметь к ней доступ.",
+	"route.test_no_routes":    "Правил пока нет: страницы всех задач сохраняются в основную базу. Добавьте правило командой /route add <тема>",
+	"route.test_no_summary":   "Краткого содержания у задачи пока нет.",
+	"route.test_default":      "🧭 Задача %d не подходит ни к одной теме: страница попадет в [основную базу](%s).",
+	"route.test_matched":      "🧭 Задача %d относится к теме «%s»: страница попадет в [базу](%s).",
+	"route.list_empty":        "🧭 Правил выгрузки по темам нет: страницы всех задач сохраняются в основную базу Notion.",
+	"route.list":              "🧭 Правила выгрузки в Notion по темам (остальные записи - в основную базу):",
+	"route.list_item":         "%d. %s → [база](%s)",
+
+	// Ответы на сообщение с записью
+	"audio_reply.instructions":  "✏️ Задача %d еще обрабатывается: краткое содержание будет составлено с учетом вашей просьбы.",
+	"audio_reply.processing":    "⏳ Запись еще обрабатывается: задача %d. Ответьте на нее снова, когда придет результат; статус - /status %d",
+	"audio_reply.no_transcript": "Транскрипции у задачи %d нет.",
+	"audio_reply.no_summary":    "Краткого содержания у задачи %d нет.",
+	"audio_reply.accepted":      "👌 Задача %d: выполняю просьбу, результат пришлю ответом на ваше сообщение.",
+
+	// Список задач /jobs
+	"jobs.filter.all":        "Все",
+	"jobs.filter.active":     "В работе",
+	"jobs.filter.completed":  "Готовые",
+	"jobs.filter.failed":     "С ошибкой",
+	"jobs.empty":             "У вас пока нет задач. Отправьте мне голосовое сообщение или аудиофайл для обработки.",
+	"jobs.header":            "📋 *Ваши задачи* (%s, страница %d) 📋",
+	"jobs.none":              "Задач не найдено.",
+	"jobs.item":              "`%d` %s *%s* (%s)\n   Создано: %s\n",
+	"jobs.in_notion":         "📎 Сохранено в Notion",
+	"jobs.footer":            "Результат задачи: /get <id>",
+	"jobs.text_title":        "Текст",
+	"jobs.button.back":       "◀️ Назад",
+	"jobs.button.next":       "Вперед ▶️",
+	"job_status.pending":     "В очереди",
+	"job_status.processing":  "Обрабатывается",
+	"job_status.transcribed": "Транскрибировано",
+	"job_status.summarized":  "Суммаризировано",
+	"job_status.completed":   "Завершено",
+	"job_status.failed":      "Ошибка",
+	"job_status.unknown":     "Неизвестно",
+
+	// Команда /compare
+	"compare.vote.a":        "🅰️ Лучше A",
+	"compare.vote.b":        "🅱️ Лучше B",
+	"compare.vote.tie":      "🤝 Одинаково",
+	"compare.disabled":      "Сравнение моделей не настроено.",
+	"compare.variant.a":     "🅰️ Вариант A для задачи %d:",
+	"compare.variant.b":     "🅱️ Вариант B для задачи %d:",
+	"compare.question":      "⚖️ Какое краткое содержание лучше? Модели покажу после голосования.",
+	"compare.not_found":     "Сравнение не найдено",
+	"compare.already_voted": "Вы уже проголосовали",
+	"compare.voted":         "⚖️ Ваш выбор: %s. Спасибо!\n\n🅰️ Вариант A - %s\n🅱️ Вариант B - %s",
+	"compare.voted_answer":  "Голос учтен",
+	"compare.report.empty":  "⚖️ Голосов в сравнениях моделей (/compare) пока нет.",
+	"compare.report.header": "⚖️ Сравнения моделей суммаризации",
+	"compare.report.model":  "%s: побед %d, ничьих %d, поражений %d (%d%% побед из %d сравнений)",
+
+	// Команда /delete_account
+	"delete_account.private_only": "Удалить аккаунт можно только в личном чате с ботом.",
+	"delete_account.warning": "⚠️ *Удаление аккаунта* ⚠️\n\n" +
+		"Будут безвозвратно удалены ваши настройки, все задачи с транскрипциями и краткими содержаниями, " +
+		"загруженные записи, публичные ссылки, подписки на подкасты, вебхук, команды, которыми вы владеете, " +
+		"и статистика использования. Задачи, которые еще обрабатываются, будут остановлены.\n\n" +
+		"Страницы, уже сохраненные в Notion, останутся в вашем рабочем пространстве.",
+	"delete_account.no_data":          "У бота нет сохраненных данных о вас.",
+	"delete_account.button.delete":    "🗑 Удалить все",
+	"delete_account.button.archive":   "🗂 Прислать список баз Notion и удалить",
+	"delete_account.button.cancel":    "Отмена",
+	"delete_account.cancelled":        "Удаление аккаунта отменено.",
+	"delete_account.already_deleted":  "Аккаунт уже удален.",
+	"delete_account.deleted":          "✅ Аккаунт и все ваши данные удалены. Чтобы снова пользоваться ботом, отправьте /start.",
+	"delete_account.archive.header":   "Базы Notion на %s",
+	"delete_account.archive.database": "База транскрипций: %s",
+	"delete_account.archive.hub":      "Страница «по тегам и по неделям»: %s",
+	"delete_account.archive.podcast":  "База подкаста «%s»: %s",
+	"delete_account.archive.job":      "База задачи %d: %s",
+	"delete_account.archive.caption":  "🗂 Список ваших баз Notion. Сами базы и страницы в Notion бот не удаляет.",
+
+	// Команды /logs и /admin_logs
+	"logs.usage": "Использование: /logs <id задачи>\n\n" +
+		"Бот пришлет файл с ходом обработки задачи, который можно приложить к обращению в поддержку. " +
+		"Идентификатор задачи можно узнать с помощью команды /jobs",
+	"admin_logs.usage":     "Использование: /admin_logs <id задачи>",
+	"logs.disabled":        "Сбор логов задач отключен.",
+	"logs.empty":           "Логов задачи %d нет: они хранятся %s.",
+	"logs.caption":         "🧾 Лог обработки задачи %d",
+	"logs.file.job":        "Задача %d",
+	"logs.file.status":     "Статус: %s",
+	"logs.file.created":    "Создана: %s",
+	"logs.file.error":      "Ошибка: %s",
+	"logs.file.stages":     "Этапы:",
+	"logs.file.journal":    "Журнал:",
+	"logs.file.no_lines":   "записей нет",
+	"logs.retention.days":  "%d дн.",
+	"logs.retention.hours": "%d ч",
+
+	// Бот в группе
+	"group.admin_only": "Настройки группы могут менять только ее администраторы.",
+	"group.welcome": "👋 Всем привет! Я расшифровываю голосовые сообщения, аудио и видео, которые присылают в эту группу, " +
+		"и отвечаю транскрипцией и кратким содержанием.\n\n" +
+		"У группы свои настройки и общий список задач: /jobs, /settings. Записи группы можно сохранять в общую базу Notion — " +
+		"администратор подключает Notion в личном чате с ботом и выполняет здесь /notion link.\n\n" +
+		"Чтобы бот видел все записи, а не только команды, сделайте его администратором группы или отключите ему режим приватности в @BotFather.",
+	"group.notion.usage": "Использование в группе:\n" +
+		"/notion - показать подключенную базу Notion\n" +
+		"/notion link - сохранять записи группы в вашу базу Notion (сначала подключите ее в личном чате с ботом)\n" +
+		"/notion unlink - не сохранять записи группы в Notion",
+	"group.notion.not_connected": "Notion для группы не подключен.",
+	"group.notion.token": "⚠️ Не отправляйте токен Notion в группу — удалите это сообщение и отзовите токен, если группа не только ваша. " +
+		"Подключите Notion в личном чате с ботом, а затем выполните здесь /notion link.",
+	"group.notion.unlinked":      "Записи группы больше не сохраняются в Notion.",
+	"group.notion.connect_first": "Сначала подключите Notion в личном чате с ботом командой /notion, а затем повторите здесь /notion link.",
+	"group.notion.linked":        "✅ *Записи группы будут сохраняться в общую базу Notion* ✅",
+
+	// Режим обслуживания: /admin_maintenance
+	"maintenance.notice":         "🛠 Бот на техническом обслуживании, новые записи пока не принимаются.",
+	"maintenance.reason":         "Причина: %s",
+	"maintenance.notice_queued":  "Записи, присланные до обслуживания, будут обработаны после него.",
+	"maintenance.resume.soon":    "Работа возобновится в ближайшее время.",
+	"maintenance.resume.minutes": "Работа возобновится примерно через %d мин.",
+	"maintenance.resume.hours":   "Работа возобновится примерно через %d ч %d мин.",
+	"maintenance.usage": "Использование: /admin_maintenance on [длительность] [причина] — включить обслуживание " +
+		"(длительность вида 30m или 2h), /admin_maintenance off — выключить, без аргументов — состояние.",
+	"maintenance.status.off": "Режим обслуживания выключен: бот принимает записи, очередь обрабатывается.",
+	"maintenance.status.on":  "🛠 Режим обслуживания включен %s администратором %d: новые записи не принимаются, очередь приостановлена.",
+	"maintenance.enabled": "🛠 Режим обслуживания включен: новые записи не принимаются, воркеры доработают текущие задачи " +
+		"и приостановят очередь. Выключить: /admin_maintenance off",
+	"maintenance.already_off": "Режим обслуживания уже выключен.",
+	"maintenance.disabled":    "✅ Режим обслуживания выключен: бот снова принимает записи, очередь возобновлена.",
+
+	// Предусловия команд
+	"guard.notion":       "🔗 Для этой команды нужна интеграция с Notion. Подключите ее — это займет пару минут.",
+	"guard.notion_setup": "Подключить Notion",
+	"guard.admin":        "Команда доступна только администраторам.",
+	"guard.quota": "⏳ Дневной лимит исчерпан: сегодня уже создано задач — %d из %d. " +
+		"Новые записи можно будет отправить завтра.",
+
+	// Ежедневный разбор задач с ошибками
+	"failed_jobs.advice.too_long":       "запись слишком длинная — разбейте ее на части и пришлите заново",
+	"failed_jobs.advice.rate_limit":     "сервис распознавания был перегружен — пришлите запись заново позже",
+	"failed_jobs.advice.network":        "сервис распознавания был недоступен — пришлите запись заново позже",
+	"failed_jobs.advice.auth":           "ошибка на стороне бота — напишите администратору",
+	"failed_jobs.advice.invalid_input":  "файл не удалось прочитать — пришлите запись в другом формате",
+	"failed_jobs.advice.source_missing": "исходный файл уже удален — пришлите запись заново",
+	"failed_jobs.advice.transcript":     "транскрипция сохранилась — получите ее командой /get %d",
+	"failed_jobs.advice.default":        "пришлите запись заново; если ошибка повторится — напишите администратору",
+	"failed_jobs.header":                "🧹 Ежедневный разбор задач с ошибками",
+	"failed_jobs.retried":               "🔄 Перезапущено задач: %d — результаты придут отдельными сообщениями.",
+	"failed_jobs.failed":                "❌ Не удалось обработать:",
+	"failed_jobs.more":                  "…и еще %d",
+	"failed_jobs.untitled":              "без названия",
+	"failed_jobs.footer":                "Отключить разбор можно в /settings.",
+
+	// Отзыв об уроке чтения
+	"reading.header":            "📖 Проверка чтения",
+	"reading.accuracy":          "Точность: %.0f%% (верно прочитано %d из %d слов)",
+	"reading.perfect":           "Отлично! Текст прочитан без ошибок.",
+	"reading.substituted":       "«%s» → услышано «%s»",
+	"reading.substituted_title": "Прочитано иначе (проверьте произношение)",
+	"reading.omitted_title":     "Пропущено",
+	"reading.inserted_title":    "Лишние слова",
+	"reading.not_recognized":    "(речь не распознана)",
+	"reading.recognized":        "Распознано: %s",
+	"reading.more":              "и еще %d",
+
+	// Подключение Notion через OAuth
+	"notion_oauth.button":    "🔗 Войти в Notion",
+	"notion_oauth.reconnect": "Чтобы подключить другое рабочее пространство, войдите в Notion по кнопке ниже.",
+	"notion_oauth.instructions": "🔗 *Настройка интеграции с Notion* 🔗\n\n" +
+		"Нажмите кнопку ниже, войдите в Notion и выберите страницы, к которым бот получит доступ. " +
+		"После этого бот создаст в вашем Notion базу данных для хранения транскрипций.\n\n" +
+		"Ссылка действует %d минут.",
+	"notion_oauth.failed":    "❌ Не удалось подключить Notion. Отправьте /notion, чтобы попробовать еще раз.",
+	"notion_oauth.connected": "✅ Интеграция с Notion успешно настроена!",
+	"notion_oauth.workspace": "Рабочее пространство: %s",
+	"notion_oauth.database":  "База транскрипций: %s",
+	"notion_oauth.next": "Теперь все транскрипции будут автоматически сохраняться в Notion. " +
+		"Вы можете отправить мне голосовое сообщение или аудиофайл для обработки.",
+
+	// Действия с результатом задачи
+	"follow_up.transcript":             "📝 Транскрипция задачи %d:\n\n%s",
+	"follow_up.summary":                "📊 Краткое содержание задачи %d:\n\n%s",
+	"follow_up.transcript_translation": "🌐 Транскрипция задачи %d на %s языке:\n\n%s",
+	"follow_up.summary_translation":    "🌐 Краткое содержание задачи %d на %s языке:\n\n%s",
+	"follow_up.instruction":            "💬 Задача %d: «%s»\n\n%s",
+	"follow_up.notion_synced":          "📤 Задача %d заново выгружена в Notion: %s",
+	"follow_up.no_tags":                "🏷 Не удалось подобрать теги для задачи %d",
+	"follow_up.tags":                   "🏷 Теги задачи %d: #%s",
+	"follow_up.nothing_to_listen":      "🔊 У задачи %d нет краткого содержания, которое можно озвучить",
+	"follow_up.listen":                 "🔊 Краткое содержание задачи %d",
+	"follow_up.listen_truncated":       "Остальное - в тексте краткого содержания",
+
+	// Записи звонков
+	"call.failed":             "📞 Не удалось обработать запись звонка «%s».",
+	"call.received":           "📞 Получена запись звонка «%s». Задача %d, статус - /status %d",
+	"call.title":              "Звонок",
+	"call.title.numbers":      "Звонок %s → %s",
+	"call.direction.incoming": "входящий",
+	"call.direction.outgoing": "исходящий",
+
+	// Отчет /admin usage
+	"usage.disabled":         "⚠️ Запись событий выключена (ANALYTICS_USAGE_EVENTS=false).",
+	"usage.empty":            "📊 За %d дн. событий использования нет.",
+	"usage.header":           "📊 Использование бота за %d дн.",
+	"usage.funnel":           "Воронка новых пользователей:",
+	"usage.funnel.created":   "• создали задачу — %d%s",
+	"usage.funnel.completed": "• получили результат — %d%s",
+	"usage.created":          "Созданные задачи по источникам",
+	"usage.completed":        "Завершенные задачи по типам",
+	"usage.commands":         "Команды",
+	"usage.more":             "…и еще %d",
+	"usage.count":            "• %s%s — %d (пользователей: %d)",
+
+	// Выгрузка задачи в PDF
+	"pdf.disabled":       "Выгрузка в PDF не настроена.",
+	"pdf.caption":        "📄 Задача %d в PDF",
+	"pdf.title":          "Задача %d",
+	"pdf.field.job":      "Задача",
+	"pdf.field.created":  "Создана",
+	"pdf.field.duration": "Длительность",
+	"pdf.field.model":    "Модель",
+	"pdf.summary":        "Краткое содержание",
+	"pdf.transcription":  "Транскрипция",
+	"pdf.generated":      "Документ создан %s",
+
+	// Удаление задачи командой /delete
+	"delete.usage": "Использование: `/delete <id задачи>` - удалить задачу, ее результат и файл записи\n" +
+		"`/delete <id задачи> notion` - заодно переместить страницу задачи в корзину Notion\n\n" +
+		"Идентификатор задачи можно узнать с помощью команды /jobs",
+	"delete.in_progress": "Задача еще обрабатывается. Удалите ее после завершения.",
+	"delete.notion_failed": "Не удалось переместить страницу задачи в корзину Notion, задача не удалена. Попробуйте позже " +
+		"или удалите задачу без Notion: `/delete %d`",
+	"delete.done_notion_archived": "🗑 Задача %d удалена, страница в Notion перемещена в корзину.",
+	"delete.done_notion_kept":     "🗑 Задача %d удалена. Страница в Notion осталась, ее можно удалить в Notion.",
+	"delete.done":                 "🗑 Задача %d удалена.",
+
+	// Статистика пользователя /stats
+	"stats.empty":     "Вы еще не отправляли записи. Отправьте голосовое сообщение, аудио или видео, чтобы начать.",
+	"stats.header":    "📊 *Ваша статистика* 📊",
+	"stats.total":     "Задач всего: %d",
+	"stats.completed": "✅ Завершено: %d",
+	"stats.failed":    "❌ С ошибкой: %d",
+	"stats.active":    "⏳ В обработке: %d",
+	"stats.minutes":   "🎙 Распознано записей: %.0f мин",
+	"stats.tokens":    "🧠 Токенов на краткие содержания: %d ($%.4f)",
+
+	// Вырезание фрагмента записи командой /trim
+	"trim.usage": "Использование: ответьте на голосовое сообщение, аудио или видео командой /trim <от> <до>\n\n" +
+		"Моменты записи указываются как мм:сс, ч:мм:сс или числом минут, например: /trim 10:00 20:00\n" +
+		"Бот вырежет этот промежуток из уже загруженной записи и обработает его как новую задачу — присылать запись заново не нужно.",
+	"trim.invalid_range":     "Конец промежутка должен быть позже его начала.",
+	"trim.unknown_recording": "Эта запись не обрабатывалась ботом. Отправьте ее, а затем ответьте на нее командой /trim.",
+	"trim.source_missing":    "Файл этой записи уже удален, поэтому вырезать из него фрагмент нельзя. Отправьте запись заново.",
+	"trim.out_of_range":      "Запись короче: она длится %s.",
+	"trim.accepted":          "✂️ *Фрагмент %s–%s задачи %d принят в обработку!* ✂️",
+
+	// Быстрая суммаризация текста командой /summarize
+	"quick_summary.header": "📊 Краткое содержание:",
+	"quick_summary.usage": "Использование: ответьте командой /summarize на сообщение с текстом или напишите текст после команды: /summarize <текст>\n\n" +
+		"Краткое содержание приходит сразу и не сохраняется в задачах; сохранить его в Notion можно кнопкой под ответом.",
+	"quick_summary.button.notion":      "📤 Сохранить в Notion",
+	"quick_summary.source_unavailable": "Исходный текст недоступен: пришлите его и повторите /summarize",
+	"quick_summary.saving":             "Сохраняю в Notion…",
+
+	// Сообщения о ходе длинных задач
+	"keepalive.elapsed":     "⏳ Всё ещё обрабатываю задачу %d: прошло %d мин",
+	"keepalive.remaining":   ", осталось ~%d мин",
+	"keepalive.almost_done": ", осталось совсем немного",
+	"keepalive.footer":      "Отправлять файл заново не нужно — результат придет в этот чат.",
+
+	// Загрузка записи по прямой ссылке
+	"audio_link.downloading": "⏬ Загружаю запись по ссылке...",
+	"audio_link.too_large":   "Файл по ссылке слишком большой: можно загрузить запись размером до %d МБ.",
+	"audio_link.not_audio":   "По ссылке находится не аудиофайл. Пришлите прямую ссылку на файл записи, например .mp3 или .m4a.",
+	"audio_link.failed":      "Не удалось загрузить файл по ссылке. Проверьте, что он доступен без входа в аккаунт.",
+
+	// Командные дайджесты
+	"team.channel_test":          "📰 Сюда будет публиковаться ежедневный дайджест команды «%s» в %02d:00.",
+	"team.digest.header":         "📰 Дайджест команды «%s» за %s",
+	"team.digest.untitled":       "Заметка #%d",
+	"team.digest.unnamed_member": "Участник без имени",
+
+	// Источники ответа /ask
+	"ask.sources":        "Источники:",
+	"ask.source":         "задача %d",
+	"ask.source_details": "задача %d, %s, %s",
+
+	// Исправление подписи к записи
+	"caption.removed": "✏️ Подпись удалена: краткое содержание будет составлено без дополнительных указаний.",
+	"caption.updated": "✏️ Подпись исправлена: краткое содержание будет составлено по новым указаниям.",
+
+	"notion_oauth.page.connected.title": "Notion подключен",
+	"notion_oauth.page.connected.text":  "Интеграция настроена, ссылки на базу транскрипций отправлены в Telegram. Эту страницу можно закрыть.",
+	"notion_oauth.page.cancelled.title": "Подключение отменено",
+	"notion_oauth.page.cancelled.text":  "Доступ к Notion не выдан. Чтобы попробовать еще раз, отправьте боту команду /notion.",
+	"notion_oauth.page.invalid.title":   "Неверная ссылка",
+	"notion_oauth.page.invalid.text":    "Откройте ссылку на вход в Notion из сообщения бота.",
+	"notion_oauth.page.expired.title":   "Ссылка устарела",
+	"notion_oauth.page.expired.text":    "Ссылка на вход в Notion уже использована или истекла. Отправьте боту команду /notion, чтобы получить новую.",
+	"notion_oauth.page.failed.title":    "Не удалось подключить Notion",
+	"notion_oauth.page.failed.text":     "Попробуйте еще раз: отправьте боту команду /notion.",
+
+	// Оповещение администраторов о перезапуске подсистемы
+	"supervisor.restart": "⚠️ Подсистема %s остановилась с ошибкой и будет перезапущена через %s: %v",
+
+	// Публичная страница с результатами задачи
+	"share.page.untitled":      "Транскрипция",
+	"share.page.created":       "Создано: %s",
+	"share.page.summary":       "Краткое содержание",
+	"share.page.transcription": "Полная транскрипция",
+
+	// Страницы Notion, которые создает бот
+	"notion.database.title":  "Транскрипции аудио",
+	"notion.related_notes":   "Связанные заметки",
+	"notion.hub.title":       "Транскрипции: навигация",
+	"notion.hub.tags.title":  "По тегам",
+	"notion.hub.tags.text":   "Откройте базу и сгруппируйте записи по свойству Tags.",
+	"notion.hub.weeks.title": "По неделям",
+	"notion.hub.weeks.text":  "Откройте базу и сгруппируйте записи по свойству Week.",
+	"notion.hub.all.title":   "Все транскрипции",
+	"notion.hub.all.text":    "Полный список записей с датой, статусом и длительностью.",
+
+	// Разделы страницы задачи в Notion
+	"notion.page.title":             "Транскрипция от %s",
+	"notion.page.summary":           "Суммаризация",
+	"notion.page.transcription":     "Полная транскрипция",
+	"notion.page.audio":             "Запись",
+	"notion.page.audio.duration":    "Длительность: %s",
+	"notion.page.audio.profile":     "Предобработка: %s",
+	"notion.page.audio.words":       "Слов в транскрипции: %d",
+	"notion.page.audio.pace":        "Темп речи: %.0f слов в минуту",
+	"notion.page.cost":              "Стоимость обработки",
+	"notion.page.cost.model":        "Модель суммаризации: %s",
+	"notion.page.cost.tokens":       "Токены: %d",
+	"notion.page.cost.amount":       "Стоимость суммаризации: $%.4f",
+	"notion.page.sentiment":         "Настроение встречи",
+	"notion.page.sentiment.mood":    "Общее настроение: %s",
+	"notion.page.sentiment.tone":    "Тон: %s",
+	"notion.page.sentiment.tension": "Напряженные моменты:",
+	"notion.page.call.direction":    "Направление: %s",
+	"notion.page.call.caller":       "Кто звонил: %s",
+	"notion.page.call.callee":       "Кому: %s",
+	"notion.page.call.started":      "Начало: %s",
+	"notion.page.call.duration":     "Длительность: %s",
+	"notion.page.speakers":          "Говорящие",
+
+	// Заметки в хранилище Obsidian
+	"obsidian.note.untitled":      "Транскрипция",
+	"obsidian.note.summary":       "Суммаризация",
+	"obsidian.note.transcription": "Полная транскрипция",
+
+	// Подписи говорящих в транскрипциях
+	"transcript.speaker": "Спикер %d",
+}
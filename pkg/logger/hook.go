@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Entry представляет собой запись лога, переданную перехватчику
+type Entry struct {
+	Time    time.Time
+	Level   string
+	Message string
+	Attrs   map[string]any
+}
+
+// Hook получает записи лога, прошедшие уровень логирования. Вызывается синхронно при каждой записи,
+// поэтому не должен блокироваться и писать в лог сам
+type Hook func(entry Entry)
+
+// hookHandler передает записи лога перехватчику вместе с атрибутами, добавленными через With,
+// не меняя основной вывод
+type hookHandler struct {
+	slog.Handler
+	hook  *atomic.Pointer[Hook]
+	attrs []slog.Attr
+}
+
+// Handle передает запись перехватчику и основному обработчику
+func (h *hookHandler) Handle(ctx context.Context, record slog.Record) error {
+	if hook := h.hook.Load(); hook != nil {
+		attrs := make(map[string]any, len(h.attrs)+record.NumAttrs())
+		for _, attr := range h.attrs {
+			attrs[attr.Key] = attr.Value.Resolve().Any()
+		}
+		record.Attrs(func(attr slog.Attr) bool {
+			attrs[attr.Key] = attr.Value.Resolve().Any()
+			return true
+		})
+		(*hook)(Entry{
+			Time:    record.Time,
+			Level:   record.Level.String(),
+			Message: record.Message,
+			Attrs:   attrs,
+		})
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs возвращает обработчик с добавленными атрибутами
+func (h *hookHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	combined := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	combined = append(combined, h.attrs...)
+	combined = append(combined, attrs...)
+	return &hookHandler{Handler: h.Handler.WithAttrs(attrs), hook: h.hook, attrs: combined}
+}
+
+// WithGroup возвращает обработчик с группой атрибутов. Перехватчик получает атрибуты без групп
+func (h *hookHandler) WithGroup(name string) slog.Handler {
+	return &hookHandler{Handler: h.Handler.WithGroup(name), hook: h.hook, attrs: h.attrs}
+}
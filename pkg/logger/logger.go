@@ -4,11 +4,14 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"sync/atomic"
 )
 
 // Logger представляет собой обертку над slog для логирования
 type Logger struct {
 	logger *slog.Logger
+	// hook - перехватчик записей, общий для логгера и всех логгеров, созданных из него через With
+	hook *atomic.Pointer[Hook]
 }
 
 // NewLogger создает новый экземпляр логгера с указанным уровнем логирования
@@ -33,10 +36,11 @@ func NewLogger(level string) *Logger {
 		Level: logLevel,
 	})
 
-	// Создание логгера
-	logger := slog.New(handler)
+	// Создание логгера; записи дополнительно передаются перехватчику, если он установлен
+	hook := &atomic.Pointer[Hook]{}
+	logger := slog.New(&hookHandler{Handler: handler, hook: hook})
 
-	return &Logger{logger: logger}
+	return &Logger{logger: logger, hook: hook}
 }
 
 // Debug логирует сообщение с уровнем Debug
@@ -67,5 +71,10 @@ func (l *Logger) Fatal(msg string, args ...any) {
 
 // With возвращает новый логгер с добавленными атрибутами
 func (l *Logger) With(args ...any) *Logger {
-	return &Logger{logger: l.logger.With(args...)}
+	return &Logger{logger: l.logger.With(args...), hook: l.hook}
+}
+
+// SetHook устанавливает перехватчик записей лога для логгера и всех логгеров, созданных из него
+func (l *Logger) SetHook(hook Hook) {
+	l.hook.Store(&hook)
 }
\ No newline at end of file
@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Внешние провайдеры
+const (
+	ProviderWhisper  = "whisper"
+	ProviderOpenAI   = "openai"
+	ProviderDeepSeek = "deepseek"
+	ProviderNotion   = "notion"
+	ProviderTelegram = "telegram"
+	ProviderPodcast  = "podcast"
+	ProviderWebhook  = "webhook"
+	ProviderVideo    = "video"
+	ProviderURL      = "url"
+	ProviderPDF      = "pdf"
+	ProviderZadarma  = "zadarma"
+	// ProviderWhisperLocal - локальный сервер распознавания whisper.cpp или faster-whisper
+	ProviderWhisperLocal = "whisper_local"
+	// ProviderDeepgram - распознавание через Deepgram API
+	ProviderDeepgram = "deepgram"
+	// ProviderPyannote - сервис разделения записей по говорящим на pyannote.audio
+	ProviderPyannote = "pyannote"
+)
+
+// CallRecord представляет собой запись о внешнем вызове
+type CallRecord struct {
+	Provider  string        `json:"provider"`
+	Operation string        `json:"operation"`
+	Status    string        `json:"status"`
+	Duration  time.Duration `json:"duration"`
+	Retries   int           `json:"retries"`
+	Error     string        `json:"error,omitempty"`
+	StartedAt time.Time     `json:"started_at"`
+}
+
+// Collector накапливает записи о внешних вызовах в рамках одной операции (например, этапа задачи)
+type Collector struct {
+	mu      sync.Mutex
+	records []CallRecord
+	retries map[[2]string]int
+}
+
+// collectorKey - ключ сборщика в контексте
+type collectorKey struct{}
+
+// WithCollector возвращает контекст, в котором внешние вызовы записываются в новый сборщик
+func WithCollector(ctx context.Context) (context.Context, *Collector) {
+	collector := &Collector{retries: make(map[[2]string]int)}
+	return context.WithValue(ctx, collectorKey{}, collector), collector
+}
+
+// Records возвращает накопленные записи о внешних вызовах
+func (c *Collector) Records() []CallRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	records := make([]CallRecord, len(c.records))
+	copy(records, c.records)
+	return records
+}
+
+// add добавляет запись, присваивая ей накопленное число повторов
+func (c *Collector) add(record CallRecord) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := [2]string{record.Provider, record.Operation}
+	record.Retries = c.retries[key]
+	delete(c.retries, key)
+	c.records = append(c.records, record)
+}
+
+// retry учитывает повтор следующего вызова операции
+func (c *Collector) retry(provider, operation string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.retries[[2]string{provider, operation}]++
+}
+
+// Track выполняет внешний вызов, записывая его задержку и статус в метрики, лог и сборщик из контекста
+func Track(ctx context.Context, log *logger.Logger, provider, operation string, call func(ctx context.Context) error) error {
+	startedAt := time.Now()
+	err := call(ctx)
+	duration := time.Since(startedAt)
+
+	status := StatusOK
+	errorMessage := ""
+	if err != nil {
+		status = StatusError
+		errorMessage = err.Error()
+	}
+
+	Default.ObserveCall(provider, operation, status, duration)
+
+	if collector, ok := ctx.Value(collectorKey{}).(*Collector); ok {
+		collector.add(CallRecord{
+			Provider:  provider,
+			Operation: operation,
+			Status:    status,
+			Duration:  duration,
+			Error:     errorMessage,
+			StartedAt: startedAt,
+		})
+	}
+
+	if log != nil {
+		log.Debug("External call finished",
+			"provider", provider,
+			"operation", operation,
+			"status", status,
+			"duration_ms", duration.Milliseconds(),
+		)
+	}
+
+	return err
+}
+
+// Retry учитывает повторную попытку внешнего вызова; вызывается перед повторным Track
+func Retry(ctx context.Context, provider, operation string) {
+	Default.ObserveRetry(provider, operation)
+
+	if collector, ok := ctx.Value(collectorKey{}).(*Collector); ok {
+		collector.retry(provider, operation)
+	}
+}
@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Статусы внешних вызовов
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// Результаты обращений к кэшу
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+// latencyBuckets - границы гистограммы задержек внешних вызовов в секундах
+var latencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// seriesKey идентифицирует серию метрик внешнего вызова
+type seriesKey struct {
+	provider  string
+	operation string
+	status    string
+}
+
+// latencySeries содержит гистограмму задержек одной серии
+type latencySeries struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// Registry хранит метрики внешних вызовов и отдает их в формате Prometheus
+type Registry struct {
+	mu      sync.Mutex
+	calls   map[seriesKey]*latencySeries
+	retries map[seriesKey]uint64
+	caches  map[cacheKey]uint64
+	// restarts - перезапуски упавших подсистем приложения по названию подсистемы
+	restarts map[string]uint64
+}
+
+// cacheKey идентифицирует серию обращений к кэшу
+type cacheKey struct {
+	cache  string
+	result string
+}
+
+// Default - реестр метрик приложения
+var Default = NewRegistry()
+
+// NewRegistry создает новый реестр метрик
+func NewRegistry() *Registry {
+	return &Registry{
+		calls:   make(map[seriesKey]*latencySeries),
+		retries: make(map[seriesKey]uint64),
+		caches:  make(map[cacheKey]uint64),
+
+		restarts: make(map[string]uint64),
+	}
+}
+
+// ObserveCall учитывает завершенный внешний вызов
+func (r *Registry) ObserveCall(provider, operation, status string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := seriesKey{provider: provider, operation: operation, status: status}
+	series, ok := r.calls[key]
+	if !ok {
+		series = &latencySeries{buckets: make([]uint64, len(latencyBuckets))}
+		r.calls[key] = series
+	}
+
+	seconds := duration.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			series.buckets[i]++
+		}
+	}
+	series.count++
+	series.sum += seconds
+}
+
+// ObserveRetry учитывает повторную попытку внешнего вызова
+func (r *Registry) ObserveRetry(provider, operation string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.retries[seriesKey{provider: provider, operation: operation}]++
+}
+
+// ObserveCache учитывает обращение к кэшу с результатом CacheHit или CacheMiss
+func (r *Registry) ObserveCache(cache, result string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.caches[cacheKey{cache: cache, result: result}]++
+}
+
+// ObserveRestart учитывает перезапуск упавшей подсистемы приложения
+func (r *Registry) ObserveRestart(subsystem string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.restarts[subsystem]++
+}
+
+// WritePrometheus записывает метрики в текстовом формате Prometheus
+func (r *Registry) WritePrometheus(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP external_call_duration_seconds Latency of calls to external providers.")
+	fmt.Fprintln(w, "# TYPE external_call_duration_seconds histogram")
+	for _, key := range sortedKeys(r.calls) {
+		series := r.calls[key]
+		labels := fmt.Sprintf(`provider=%q,operation=%q,status=%q`, key.provider, key.operation, key.status)
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(w, "external_call_duration_seconds_bucket{%s,le=\"%g\"} %d\n", labels, bound, series.buckets[i])
+		}
+		fmt.Fprintf(w, "external_call_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, series.count)
+		fmt.Fprintf(w, "external_call_duration_seconds_sum{%s} %g\n", labels, series.sum)
+		fmt.Fprintf(w, "external_call_duration_seconds_count{%s} %d\n", labels, series.count)
+	}
+
+	fmt.Fprintln(w, "# HELP external_call_retries_total Retries of calls to external providers.")
+	fmt.Fprintln(w, "# TYPE external_call_retries_total counter")
+	for _, key := range sortedKeys(r.retries) {
+		fmt.Fprintf(w, "external_call_retries_total{provider=%q,operation=%q} %d\n", key.provider, key.operation, r.retries[key])
+	}
+
+	fmt.Fprintln(w, "# HELP cache_requests_total Cache lookups by result.")
+	fmt.Fprintln(w, "# TYPE cache_requests_total counter")
+	cacheKeys := make([]cacheKey, 0, len(r.caches))
+	for key := range r.caches {
+		cacheKeys = append(cacheKeys, key)
+	}
+	sort.Slice(cacheKeys, func(i, j int) bool {
+		if cacheKeys[i].cache != cacheKeys[j].cache {
+			return cacheKeys[i].cache < cacheKeys[j].cache
+		}
+		return cacheKeys[i].result < cacheKeys[j].result
+	})
+	for _, key := range cacheKeys {
+		fmt.Fprintf(w, "cache_requests_total{cache=%q,result=%q} %d\n", key.cache, key.result, r.caches[key])
+	}
+
+	fmt.Fprintln(w, "# HELP subsystem_restarts_total Restarts of crashed application subsystems.")
+	fmt.Fprintln(w, "# TYPE subsystem_restarts_total counter")
+	subsystems := make([]string, 0, len(r.restarts))
+	for subsystem := range r.restarts {
+		subsystems = append(subsystems, subsystem)
+	}
+	sort.Strings(subsystems)
+	for _, subsystem := range subsystems {
+		fmt.Fprintf(w, "subsystem_restarts_total{subsystem=%q} %d\n", subsystem, r.restarts[subsystem])
+	}
+}
+
+// Handler возвращает HTTP обработчик, отдающий метрики реестра
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WritePrometheus(w)
+	})
+}
+
+// sortedKeys возвращает ключи серий в стабильном порядке
+func sortedKeys[V any](m map[seriesKey]V) []seriesKey {
+	keys := make([]seriesKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].provider != keys[j].provider {
+			return keys[i].provider < keys[j].provider
+		}
+		if keys[i].operation != keys[j].operation {
+			return keys[i].operation < keys[j].operation
+		}
+		return keys[i].status < keys[j].status
+	})
+	return keys
+}
@@ -0,0 +1,67 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Run выполняет fn для номеров от 0 до n-1, не больше limit вызовов одновременно. Первая ошибка останавливает
+// остальные вызовы: новые не запускаются, а контекст выполняющихся отменяется. Возвращаются номер вызова
+// и ошибка, из-за которой вызовы остановлены, а не ошибки отмены, которые она вызвала. Если отменен
+// контекст ctx, возвращается его ошибка
+func Run(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) (int, error) {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, n)
+	sem := make(chan struct{}, max(limit, 1))
+
+	var wg sync.WaitGroup
+dispatch:
+	for i := 0; i < n; i++ {
+		// Ожидание свободного слота в пуле
+		select {
+		case sem <- struct{}{}:
+		case <-runCtx.Done():
+			break dispatch
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			errs[i] = fn(runCtx, i)
+			if errs[i] != nil {
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return firstError(errs)
+}
+
+// firstError возвращает первую ошибку, не вызванную отменой контекста, или первую ошибку отмены
+func firstError(errs []error) (int, error) {
+	first := -1
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, context.Canceled) {
+			return i, err
+		}
+		if first < 0 {
+			first = i
+		}
+	}
+	if first < 0 {
+		return 0, nil
+	}
+	return first, errs[first]
+}
@@ -0,0 +1,56 @@
+package safehttp
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// Ограничения соединений с адресами, которые присылают пользователи
+const (
+	dialTimeout  = 30 * time.Second
+	maxRedirects = 5
+)
+
+// NewClient создает HTTP клиент для адресов, которые присылают пользователи: лент подкастов, файлов по ссылкам,
+// вебхуков. Клиент не соединяется с адресами локальной и внутренней сети, в том числе после перенаправлений,
+// и проходит не больше maxRedirects перенаправлений. timeout ограничивает весь запрос (0 - без ограничения)
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: dialTimeout,
+		Control: denyInternalAddress,
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: dialTimeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}
+}
+
+// denyInternalAddress запрещает соединения с адресами локальной и внутренней сети. Адрес проверяется
+// после разрешения имени, поэтому имя, указывающее на внутренний адрес, тоже не пройдет
+func denyInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("connections to %s are not allowed", host)
+	}
+
+	return nil
+}
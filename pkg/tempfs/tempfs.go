@@ -0,0 +1,107 @@
+package tempfs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/112Alex/project_obsidian/pkg/logger"
+)
+
+// Space представляет собой каталог для временных файлов с ограничением на суммарный объем.
+// Каталог можно смонтировать как tmpfs: тогда загрузки и промежуточные файлы FFmpeg
+// обрабатываются в памяти, а ограничение не дает им занять всю память контейнера
+type Space struct {
+	dir      string
+	maxBytes int64
+	logger   *logger.Logger
+
+	mu       sync.Mutex
+	reserved int64
+	released chan struct{}
+}
+
+// New создает каталог для временных файлов. maxBytes <= 0 отключает ограничение объема
+func New(dir string, maxBytes int64, logger *logger.Logger) (*Space, error) {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	logger.Info("Temp directory initialized",
+		"dir", dir,
+		"max_bytes", maxBytes,
+	)
+
+	return &Space{
+		dir:      dir,
+		maxBytes: maxBytes,
+		logger:   logger,
+		released: make(chan struct{}),
+	}, nil
+}
+
+// Dir возвращает путь к каталогу временных файлов
+func (s *Space) Dir() string {
+	return s.dir
+}
+
+// CreateTemp создает временный файл в каталоге по шаблону имени os.CreateTemp
+func (s *Space) CreateTemp(pattern string) (*os.File, error) {
+	return os.CreateTemp(s.dir, pattern)
+}
+
+// Reserve резервирует место под временные файлы и ждет, пока его освободят другие задачи.
+// Возвращает функцию освобождения резерва, которую нужно вызвать после удаления файлов
+func (s *Space) Reserve(ctx context.Context, bytes int64) (func(), error) {
+	if s.maxBytes <= 0 || bytes <= 0 {
+		return func() {}, nil
+	}
+
+	// Файл больше всего бюджета ждал бы вечно, поэтому сразу возвращаем ошибку
+	if bytes > s.maxBytes {
+		return nil, fmt.Errorf("temp file of %d bytes exceeds temp directory limit of %d bytes", bytes, s.maxBytes)
+	}
+
+	for {
+		s.mu.Lock()
+		if s.reserved+bytes <= s.maxBytes {
+			s.reserved += bytes
+			s.mu.Unlock()
+			return s.releaseFunc(bytes), nil
+		}
+		released := s.released
+		s.mu.Unlock()
+
+		s.logger.Debug("Waiting for temp directory space",
+			"requested_bytes", bytes,
+			"max_bytes", s.maxBytes,
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to reserve temp space: %w", ctx.Err())
+		case <-released:
+		}
+	}
+}
+
+// releaseFunc возвращает функцию однократного освобождения резерва
+func (s *Space) releaseFunc(bytes int64) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			s.reserved -= bytes
+			// Будим всех ожидающих: каждый заново проверит, хватает ли ему места
+			close(s.released)
+			s.released = make(chan struct{})
+		})
+	}
+}
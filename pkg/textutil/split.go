@@ -0,0 +1,43 @@
+package textutil
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// SplitWords разбивает текст на фрагменты не длиннее size символов по границам слов. Длина считается в символах,
+// а не в байтах, поэтому русский текст режется так же, как английский. Текст, который помещается в один фрагмент,
+// возвращается как есть; текст без слов фрагментов не дает
+func SplitWords(text string, size int) []string {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(text) <= size {
+		return []string{text}
+	}
+
+	chunks := make([]string, 0)
+	var builder strings.Builder
+	length := 0
+
+	for _, word := range strings.Fields(text) {
+		wordLength := utf8.RuneCountInString(word)
+		if length > 0 && length+1+wordLength > size {
+			chunks = append(chunks, builder.String())
+			builder.Reset()
+			length = 0
+		}
+		if length > 0 {
+			builder.WriteByte(' ')
+			length++
+		}
+		builder.WriteString(word)
+		length += wordLength
+	}
+
+	if length > 0 {
+		chunks = append(chunks, builder.String())
+	}
+
+	return chunks
+}
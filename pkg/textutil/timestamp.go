@@ -0,0 +1,39 @@
+package textutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatTimestamp форматирует смещение от начала записи в секундах как mm:ss или h:mm:ss
+func FormatTimestamp(totalSeconds int) string {
+	hours := totalSeconds / 3600
+	minutes := totalSeconds % 3600 / 60
+	seconds := totalSeconds % 60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+}
+
+// Segment представляет собой фразу транскрипции с началом в секундах от начала записи
+type Segment struct {
+	Start float64
+	Text  string
+}
+
+// SegmentTimestamp возвращает метку времени [мм:сс], которой начинаются фразы транскрипции с детализацией по сегментам
+func SegmentTimestamp(start float64) string {
+	seconds := int(start)
+	return fmt.Sprintf("[%02d:%02d]", seconds/60, seconds%60)
+}
+
+// FormatSegments собирает текст транскрипции из фраз, начиная каждую с новой строки с метки времени [мм:сс].
+// Так выглядят транскрипции с детализацией по сегментам у всех провайдеров распознавания
+func FormatSegments(segments []Segment) string {
+	var builder strings.Builder
+	for _, segment := range segments {
+		fmt.Fprintf(&builder, "%s %s\n", SegmentTimestamp(segment.Start), strings.TrimSpace(segment.Text))
+	}
+	return strings.TrimSpace(builder.String())
+}